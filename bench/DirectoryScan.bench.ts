@@ -0,0 +1,48 @@
+/**
+ * Benchmark of scanning deep command directories
+ *
+ * Run with `bun run bench`. Builds a real directory tree in the system temp
+ * directory, since the cost being measured is readdir() on nested
+ * namespaces, and removes it afterwards.
+ */
+import { mkdir, mkdtemp, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import BunFileService from "../src/services/BunFileService.ts";
+import { measure } from "./harness.ts";
+
+const DEPTH = 6;
+const FAN_OUT = 3;
+const FILES_PER_DIRECTORY = 5;
+const ITERATIONS = 20;
+
+async function createTree(directory: string, depth: number): Promise<number> {
+	await mkdir(directory, { recursive: true });
+	let files = 0;
+	for (let i = 0; i < FILES_PER_DIRECTORY; i++) {
+		await writeFile(join(directory, `command-${i}.md`), `# Command ${i}\n`);
+		files++;
+	}
+	if (depth < DEPTH) {
+		for (let i = 0; i < FAN_OUT; i++) {
+			files += await createTree(join(directory, `namespace-${i}`), depth + 1);
+		}
+	}
+	return files;
+}
+
+const root = await mkdtemp(join(tmpdir(), "claude-cmd-bench-"));
+try {
+	const files = await createTree(root, 0);
+	const fileService = new BunFileService();
+
+	console.log(`Directory scan, ${files} files ${DEPTH} levels deep`);
+	await measure("scan: scanNamespaceHierarchy", ITERATIONS, () =>
+		fileService.scanNamespaceHierarchy(root),
+	);
+	await measure("scan: listFilesRecursive", ITERATIONS, () =>
+		fileService.listFilesRecursive(root),
+	);
+} finally {
+	await rm(root, { recursive: true, force: true });
+}
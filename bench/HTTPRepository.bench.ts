@@ -0,0 +1,137 @@
+/**
+ * Time and memory of fetching very large manifests over HTTP
+ *
+ * Run with `bun run bench`. HTTPRepository streams manifest bodies through
+ * ManifestDecoder, so a manifest is never held whole as text or as a parse
+ * tree. The client here serves manifests from memory in 64 KiB chunks and
+ * samples the heap while the body is read. The benchmark fails when the heap
+ * retained per command grows past MAX_RETAINED_BYTES_PER_COMMAND, or when
+ * the heap in use while decoding exceeds what the decoded commands retain
+ * by more than MAX_TRANSIENT_FRACTION of the body size.
+ */
+import type IHTTPClient from "../src/interfaces/IHTTPClient.ts";
+import type {
+	HTTPOptions,
+	HTTPResponse,
+	HTTPStreamResponse,
+} from "../src/interfaces/IHTTPClient.ts";
+import { CacheConfig } from "../src/interfaces/IRepository.ts";
+import HTTPRepository from "../src/services/HTTPRepository.ts";
+import InMemoryFileService from "../tests/mocks/InMemoryFileService.ts";
+import { measure } from "./harness.ts";
+
+const SIZES = [10_000, 50_000];
+const ITERATIONS = 5;
+const CHUNK_SIZE = 64 * 1024;
+const SAMPLE_EVERY_CHUNKS = 16;
+const MAX_RETAINED_BYTES_PER_COMMAND = 4096;
+const MAX_TRANSIENT_FRACTION = 0.25;
+const CACHE_DIR = "/bench/cache";
+
+function manifestBytes(count: number): Uint8Array {
+	return Buffer.from(
+		JSON.stringify({
+			version: "1.0.0",
+			schemaVersion: 2,
+			updated: "2025-01-01T00:00:00Z",
+			commands: Array.from({ length: count }, (_, i) => ({
+				name: `team-${i % 100}:command-${i}`,
+				description: `Benchmark command ${i} with a description of typical length`,
+				file: `team-${i % 100}/command-${i}.md`,
+				"allowed-tools": ["Read", "Edit", "Bash(git:*)"],
+				tags: [`tag-${i % 50}`, "benchmark"],
+			})),
+		}),
+	);
+}
+
+function heapUsed(): number {
+	Bun.gc(true);
+	return process.memoryUsage().heapUsed;
+}
+
+/**
+ * Serves one manifest in chunks, optionally recording the peak heap
+ */
+class ChunkedHTTPClient implements IHTTPClient {
+	sampling = false;
+	peakHeap = 0;
+
+	constructor(private readonly manifest: Uint8Array) {}
+
+	async get(url: string): Promise<HTTPResponse> {
+		throw new Error(`Unexpected buffered request for ${url}`);
+	}
+
+	async stream(
+		url: string,
+		_options?: HTTPOptions,
+	): Promise<HTTPStreamResponse> {
+		return {
+			status: 200,
+			statusText: "OK",
+			headers: { "content-type": "application/json" },
+			url,
+			body: this.chunks(),
+		};
+	}
+
+	private async *chunks(): AsyncGenerator<Uint8Array> {
+		for (
+			let offset = 0, chunk = 0;
+			offset < this.manifest.length;
+			offset += CHUNK_SIZE, chunk++
+		) {
+			if (this.sampling && chunk % SAMPLE_EVERY_CHUNKS === 0) {
+				this.peakHeap = Math.max(this.peakHeap, heapUsed());
+			}
+			yield this.manifest.subarray(offset, offset + CHUNK_SIZE);
+		}
+	}
+}
+
+function mib(bytes: number): string {
+	return `${(bytes / 1024 / 1024).toFixed(1)} MiB`;
+}
+
+for (const size of SIZES) {
+	const body = manifestBytes(size);
+	const client = new ChunkedHTTPClient(body);
+	const fileService = new InMemoryFileService();
+	const repository = new HTTPRepository(
+		client,
+		fileService,
+		new CacheConfig({ cacheDir: CACHE_DIR }),
+	);
+	await measure(`http: getManifest, ${size} commands`, ITERATIONS, () =>
+		repository.getManifest("en", { forceRefresh: true }),
+	);
+
+	// Drop the cached copy so only the decoded manifest is counted
+	await fileService.deleteFile(`${CACHE_DIR}/manifest-en.json`);
+	const before = heapUsed();
+	client.sampling = true;
+	client.peakHeap = before;
+	const manifest = await repository.getManifest("en", { forceRefresh: true });
+	client.sampling = false;
+	await fileService.deleteFile(`${CACHE_DIR}/manifest-en.json`);
+	const retained = heapUsed() - before;
+	const transient = Math.max(0, client.peakHeap - before - retained);
+	const perCommand = retained / manifest.commands.length;
+
+	console.log(
+		`${"".padEnd(48)} ${mib(retained)} retained, ${perCommand.toFixed(0)} B/command, ${mib(transient)} transient`,
+	);
+	if (perCommand > MAX_RETAINED_BYTES_PER_COMMAND) {
+		console.error(
+			`Retained ${perCommand.toFixed(0)} bytes per command, more than the budget of ${MAX_RETAINED_BYTES_PER_COMMAND}`,
+		);
+		process.exitCode = 1;
+	}
+	if (transient > body.length * MAX_TRANSIENT_FRACTION) {
+		console.error(
+			`Used ${mib(transient)} while decoding a ${mib(body.length)} body, more than the budget of ${MAX_TRANSIENT_FRACTION * 100}%`,
+		);
+		process.exitCode = 1;
+	}
+}
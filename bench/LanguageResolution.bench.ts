@@ -0,0 +1,51 @@
+/**
+ * Benchmark of resolving the effective language
+ *
+ * Run with `bun run bench`. Measures the detector alone and the full
+ * resolution through both config files, which every command does on start.
+ */
+import {
+	type DetectionContext,
+	LanguageDetector,
+} from "../src/services/LanguageDetector.ts";
+import { createServices } from "../src/services/serviceFactory.ts";
+import InMemoryFileService from "../tests/mocks/InMemoryFileService.ts";
+import { measure } from "./harness.ts";
+
+const ITERATIONS = 10_000;
+
+const detector = new LanguageDetector();
+const context = (sources: Partial<DetectionContext>): DetectionContext => ({
+	cliFlag: "",
+	envVar: "",
+	posixLocale: "",
+	...sources,
+});
+const contexts = [
+	context({}),
+	context({ envVar: "fr" }),
+	context({ userConfig: "es" }),
+	context({ posixLocale: "pt_BR.UTF-8" }),
+];
+
+console.log("Language resolution");
+await measure("language: LanguageDetector.resolve", ITERATIONS, (i) =>
+	detector.resolve(contexts[i % contexts.length] as DetectionContext),
+);
+
+const fileService = new InMemoryFileService();
+const services = createServices({
+	fileService,
+	userConfigPath: "/home/bench/.config/claude-cmd/config.claude-cmd.json",
+	projectConfigPath: "/project/.claude/config.claude-cmd.json",
+	projectRoot: "/project",
+	cacheDir: "/home/bench/.cache/claude-cmd",
+	claudeDir: "/home/bench/.claude",
+});
+await fileService.writeFile(
+	"/home/bench/.config/claude-cmd/config.claude-cmd.json",
+	JSON.stringify({ preferredLanguage: "de" }),
+);
+await measure("language: getEffectiveLanguage (config files)", 1000, () =>
+	services.configManager.getEffectiveLanguage(),
+);
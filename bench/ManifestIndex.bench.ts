@@ -0,0 +1,42 @@
+/**
+ * Benchmark of manifest lookups, scanning versus ManifestIndex
+ *
+ * Run with `bun run bench`. Manifests have 10,000
+ * namespaced, tagged commands, the size of a large organization catalog.
+ */
+import type { Command } from "../src/types/Command.ts";
+import { ManifestIndex } from "../src/utils/manifestIndex.ts";
+import { hasTag } from "../src/utils/tags.ts";
+import { measure } from "./harness.ts";
+
+const COMMANDS = 10_000;
+const LOOKUPS = 10_000;
+
+const commands: Command[] = Array.from({ length: COMMANDS }, (_, i) => ({
+	name: `team-${i % 100}:command-${i}`,
+	description: `Benchmark command ${i}`,
+	file: `team-${i % 100}/command-${i}.md`,
+	"allowed-tools": [],
+	tags: [`tag-${i % 50}`],
+}));
+const names = Array.from(
+	{ length: LOOKUPS },
+	(_, i) => commands[(i * 7919) % COMMANDS]?.name ?? "",
+);
+
+console.log(`Manifest lookups, ${COMMANDS} commands`);
+await measure("index: build index", 20, () => new ManifestIndex(commands));
+
+const index = new ManifestIndex(commands);
+await measure("index: name (scan)", LOOKUPS, (i) =>
+	commands.find((command) => command.name === names[i]),
+);
+await measure("index: name (index)", LOOKUPS, (i) =>
+	index.get(names[i] ?? ""),
+);
+await measure("index: tag (scan)", 100, (i) =>
+	commands.filter((command) => hasTag(command, `tag-${i % 50}`)),
+);
+await measure("index: tag (index)", 100, (i) =>
+	index.withTag(`tag-${i % 50}`),
+);
@@ -0,0 +1,86 @@
+/**
+ * Benchmark of StatusService polling, with and without status snapshots
+ *
+ * Run with `bun run bench`. Services are built on in-memory files holding a
+ * cached manifest and installed commands, so the numbers measure the status
+ * collection itself rather than the disk.
+ */
+import { StatusService } from "../src/services/StatusService.ts";
+import { createServices } from "../src/services/serviceFactory.ts";
+import InMemoryFileService from "../tests/mocks/InMemoryFileService.ts";
+import { measure } from "./harness.ts";
+
+const COMMANDS = 200;
+const ITERATIONS = 200;
+const POLLERS = 10;
+
+async function createStatusService(snapshotTtlMs: number) {
+	const services = createServices({
+		fileService: new InMemoryFileService(),
+		userConfigPath: "/home/bench/.config/claude-cmd/config.claude-cmd.json",
+		projectConfigPath: "/project/.claude/config.claude-cmd.json",
+		projectRoot: "/project",
+		cacheDir: "/home/bench/.cache/claude-cmd",
+		claudeDir: "/home/bench/.claude",
+	});
+	const language = await services.configManager.getEffectiveLanguage();
+
+	const commands = Array.from({ length: COMMANDS }, (_, i) => ({
+		name: `bench-${i}`,
+		description: `Benchmark command ${i}`,
+		file: `bench-${i}.md`,
+		"allowed-tools": [],
+	}));
+	await services.cacheManager.set(language, {
+		version: "1.0.0",
+		updated: "2025-01-01T00:00:00Z",
+		commands,
+	});
+	for (const command of commands) {
+		await services.fileService.writeFile(
+			`/home/bench/.claude/commands/${command.file}`,
+			`---\ndescription: ${command.description}\n---\n\n# ${command.name}\n`,
+		);
+	}
+
+	return new StatusService(
+		services.fileService,
+		services.cacheManager,
+		services.directoryDetector,
+		services.localCommandRepository,
+		services.languageDetector,
+		services.configManager,
+		services.clock,
+		undefined,
+		{ snapshotTtlMs },
+	);
+}
+
+const uncached = await createStatusService(0);
+const snapshotted = await createStatusService(60000);
+
+console.log(
+	`StatusService, ${COMMANDS} installed commands, ${ITERATIONS} iterations`,
+);
+await measure("status: getSystemStatus (no snapshot)", ITERATIONS, () =>
+	uncached.getSystemStatus(),
+);
+await measure("status: getSystemStatus (snapshot)", ITERATIONS, () =>
+	snapshotted.getSystemStatus(),
+);
+await measure(
+	`status: ${POLLERS} concurrent pollers (no snapshot)`,
+	ITERATIONS,
+	() =>
+		Promise.all(
+			Array.from({ length: POLLERS }, () => uncached.getSystemStatus()),
+		),
+);
+await measure(
+	`status: ${POLLERS} concurrent pollers (snapshot)`,
+	ITERATIONS,
+	() =>
+		Promise.all(
+			Array.from({ length: POLLERS }, () => snapshotted.getSystemStatus()),
+		),
+);
@@ -0,0 +1,65 @@
+/**
+ * Shared measuring for the benchmarks in this directory
+ *
+ * Every measurement is printed and recorded, so bench/index.ts can save the
+ * results as a baseline or compare a run against one.
+ */
+
+/**
+ * Average time of one operation of a benchmark
+ */
+export interface BenchResult {
+	readonly name: string;
+	readonly nsPerOp: number;
+}
+
+const results: BenchResult[] = [];
+
+/**
+ * Time an operation, after one warm-up run
+ *
+ * @param name - Benchmark name, unique across the suite (baselines key on it)
+ * @param iterations - Number of timed runs
+ * @param run - Operation to time; awaited when it returns a promise
+ */
+export async function measure(
+	name: string,
+	iterations: number,
+	run: (iteration: number) => unknown,
+): Promise<BenchResult> {
+	await run(0);
+	const started = Bun.nanoseconds();
+	for (let i = 0; i < iterations; i++) {
+		const result = run(i);
+		if (result instanceof Promise) {
+			await result;
+		}
+	}
+	const measured = {
+		name,
+		nsPerOp: (Bun.nanoseconds() - started) / iterations,
+	};
+	results.push(measured);
+	console.log(`${name.padEnd(48)} ${formatTime(measured.nsPerOp)}/op`);
+	return measured;
+}
+
+/**
+ * Get every result measured so far
+ */
+export function benchResults(): readonly BenchResult[] {
+	return results;
+}
+
+/**
+ * Format a duration in nanoseconds with a readable unit
+ */
+export function formatTime(ns: number): string {
+	if (ns >= 1e6) {
+		return `${(ns / 1e6).toFixed(3)} ms`;
+	}
+	if (ns >= 1e3) {
+		return `${(ns / 1e3).toFixed(3)} µs`;
+	}
+	return `${ns.toFixed(0)} ns`;
+}
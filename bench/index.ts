@@ -0,0 +1,75 @@
+/**
+ * Run every benchmark, optionally against a baseline
+ *
+ *   bun run bench             run the suite
+ *   bun run bench:baseline    run it and save the results to bench/baseline.json
+ *   bun run bench:compare     run it and fail if a benchmark got slower than
+ *                             its baseline by more than BENCH_TOLERANCE
+ *                             (a fraction, default 0.25)
+ *
+ * Timings depend on the machine, so baselines are not committed: save one on
+ * the base branch, then compare on the branch under test.
+ */
+import { existsSync } from "node:fs";
+import { readFile, writeFile } from "node:fs/promises";
+import { join } from "node:path";
+import { type BenchResult, benchResults, formatTime } from "./harness.ts";
+
+const BASELINE_PATH = join(import.meta.dir, "baseline.json");
+const DEFAULT_TOLERANCE = 0.25;
+
+await import("./DirectoryScan.bench.ts");
+await import("./HTTPRepository.bench.ts");
+await import("./ManifestIndex.bench.ts");
+await import("./LanguageResolution.bench.ts");
+await import("./StatusService.bench.ts");
+
+const mode = process.argv[2];
+if (mode === "--save") {
+	await writeFile(BASELINE_PATH, JSON.stringify(benchResults(), null, 2));
+	console.log(`\nSaved baseline to ${BASELINE_PATH}`);
+} else if (mode === "--compare") {
+	if (!existsSync(BASELINE_PATH)) {
+		console.error(
+			`\nNo baseline at ${BASELINE_PATH}; run 'bun run bench:baseline' first`,
+		);
+		process.exit(1);
+	}
+	const baseline: BenchResult[] = JSON.parse(
+		await readFile(BASELINE_PATH, "utf8"),
+	);
+	const tolerance = Number(process.env.BENCH_TOLERANCE ?? DEFAULT_TOLERANCE);
+	if (!compare(baseline, benchResults(), tolerance)) {
+		process.exitCode = 1;
+	}
+}
+
+/**
+ * Print each benchmark's change against the baseline
+ *
+ * @returns False if any benchmark slowed down by more than the tolerance
+ */
+function compare(
+	baseline: readonly BenchResult[],
+	current: readonly BenchResult[],
+	tolerance: number,
+): boolean {
+	const before = new Map(baseline.map((result) => [result.name, result]));
+	let passed = true;
+
+	console.log(`\nCompared with baseline (tolerance ${tolerance * 100}%)`);
+	for (const result of current) {
+		const previous = before.get(result.name);
+		if (!previous) {
+			console.log(`${result.name.padEnd(48)} new`);
+			continue;
+		}
+		const change = result.nsPerOp / previous.nsPerOp - 1;
+		const regressed = change > tolerance;
+		passed &&= !regressed;
+		console.log(
+			`${result.name.padEnd(48)} ${formatTime(previous.nsPerOp)} -> ${formatTime(result.nsPerOp)} (${change >= 0 ? "+" : ""}${(change * 100).toFixed(1)}%)${regressed ? "  REGRESSION" : ""}`,
+		);
+	}
+	return passed;
+}
@@ -1,11 +1,80 @@
-import type { LanguageDetector } from "../services/LanguageDetector.js";
+import { type Command, InvalidArgumentError } from "commander";
+import { ClaudeCmdClient } from "../client.js";
+import type IProgressReporter from "../interfaces/IProgressReporter.js";
 import { getServices } from "../services/serviceFactory.js";
+import { type AliasTarget, parseAliasTarget } from "../utils/aliases.js";
+import { isCrash } from "../utils/crashReport.js";
+import { normalizeLanguageCode } from "../utils/naming.js";
+import { suggestNames } from "../utils/suggestions.js";
+import {
+	isTemplate,
+	parseTemplate,
+	type Template,
+	TemplateError,
+} from "../utils/template.js";
+import { exitWithCrashReport } from "./crashReport.js";
+import { jsonEventsEnabled } from "./events.js";
+import { exitCodeForError } from "./exitCodes.js";
+import { createProgressReporter, EventProgressReporter } from "./progress.js";
+
+let porcelain = false;
+
+/**
+ * Enable or disable porcelain output for the current invocation
+ * Set once from the global --porcelain flag before a command runs
+ */
+export function setPorcelain(enabled: boolean): void {
+	porcelain = enabled;
+}
+
+/**
+ * Check whether output should be machine-readable
+ *
+ * Porcelain output has no headers, colors or hints: one record per line with
+ * tab-separated fields, stable across releases.
+ */
+export function isPorcelain(): boolean {
+	return porcelain;
+}
 
 /**
  * Handle CLI command errors with user-friendly messages
  * Centralizes error handling patterns across all CLI commands
+ *
+ * Exits with the code of the error category (see exitCodes.ts). In porcelain
+ * mode the error is written as "error<TAB>code<TAB>name<TAB>message".
+ * Crashes (see isCrash()) write a crash report before exiting, so callers
+ * must await this to stop before the process exits.
+ *
+ * @param error - The error to report
+ * @param defaultMessage - Message for errors without a better one
+ * @param hints - Lines printed after the message (not in porcelain mode)
  */
-export function handleError(error: unknown, defaultMessage: string): void {
+export async function handleError(
+	error: unknown,
+	defaultMessage: string,
+	hints: readonly string[] = [],
+): Promise<never> {
+	const exitCode = exitCodeForError(error);
+
+	if (jsonEventsEnabled()) {
+		getServices().eventBus.emit({
+			type: "error",
+			name: error instanceof Error ? error.name : "Error",
+			message: error instanceof Error ? error.message : defaultMessage,
+			exitCode,
+		});
+	}
+
+	if (porcelain) {
+		const name = error instanceof Error ? error.name : "Error";
+		const message = error instanceof Error ? error.message : defaultMessage;
+		console.error(
+			["error", exitCode, name, message.replace(/\s+/g, " ")].join("\t"),
+		);
+		return exitAfterError(error, exitCode);
+	}
+
 	let errorMessage = defaultMessage;
 
 	if (error instanceof Error) {
@@ -30,7 +99,106 @@ export function handleError(error: unknown, defaultMessage: string): void {
 	}
 
 	console.error(errorMessage);
-	process.exit(1);
+	for (const hint of hints) {
+		console.error(hint);
+	}
+	return exitAfterError(error, exitCode);
+}
+
+/**
+ * Exit after an error was reported, writing a crash report first for crashes
+ */
+async function exitAfterError(
+	error: unknown,
+	exitCode: number,
+): Promise<never> {
+	if (isCrash(error)) {
+		return exitWithCrashReport(error, exitCode, { announce: !porcelain });
+	}
+	process.exit(exitCode);
+}
+
+/**
+ * Resolve a command name typed on the command line through the `aliases`
+ * config key (project aliases over personal ones)
+ *
+ * @param commandName - Name or alias
+ * @returns The aliased command, or the name itself if it is no alias
+ */
+export async function resolveAlias(commandName: string): Promise<AliasTarget> {
+	const { aliases } = await getServices().configManager.getEffectiveConfig();
+	const target = aliases?.[commandName];
+	return (
+		(typeof target === "string" ? parseAliasTarget(target) : undefined) ?? {
+			name: commandName,
+		}
+	);
+}
+
+/**
+ * Where to look for the names a mistyped command name may have meant
+ */
+export interface SuggestionSources {
+	/** Commands in the repository manifest */
+	readonly repository?: boolean;
+	/** Installed commands */
+	readonly installed?: boolean;
+	/** Manifest language (default: auto-detect) */
+	readonly language?: string;
+}
+
+/**
+ * Suggest commands for a name that was not found
+ *
+ * Sources that cannot be read (e.g., while offline) are skipped, and
+ * nothing is suggested in porcelain mode.
+ *
+ * @param commandName - Name that was not found
+ * @param sources - Command sets to suggest from
+ * @returns Hint lines for handleError: a "Did you mean" line, or none when
+ *   no name is close
+ */
+export async function didYouMean(
+	commandName: string,
+	sources: SuggestionSources,
+): Promise<string[]> {
+	if (porcelain) {
+		return [];
+	}
+	const { commandQueryService, installationService } = getServices();
+	const names: string[] = [];
+	if (sources.repository) {
+		const commands = await commandQueryService
+			.listCommands({ language: sources.language })
+			.catch(() => []);
+		names.push(...commands.map((command) => command.name));
+	}
+	if (sources.installed) {
+		const commands = await installationService
+			.listInstalledCommands()
+			.catch(() => []);
+		names.push(...commands.map((command) => command.name));
+	}
+
+	const suggestions = suggestNames(commandName, names);
+	return suggestions.length > 0
+		? [`Did you mean: ${suggestions.join(", ")}?`]
+		: [];
+}
+
+/**
+ * Explain why a command file failed to parse, including the parser's cause
+ *
+ * @param error - Error thrown by CommandParser.parseCommandFile
+ * @returns Message for a warning line
+ */
+export function describeParseError(error: unknown): string {
+	const reason = error instanceof Error ? error.message : String(error);
+	const cause =
+		error instanceof Error && error.cause instanceof Error
+			? ` (${error.cause.message})`
+			: "";
+	return `${reason}${cause}`;
 }
 
 /**
@@ -39,14 +207,124 @@ export function handleError(error: unknown, defaultMessage: string): void {
  */
 export async function detectLanguage(
 	optionLanguage: string | undefined,
-	_languageDetector: LanguageDetector,
 ): Promise<string> {
-	if (optionLanguage) {
-		return optionLanguage;
+	// Resolved like the library client, so both read the same language
+	return optionLanguage || getClient().getLanguage();
+}
+
+/**
+ * Create the progress reporter for a command invocation
+ * Honors the global --quiet and --porcelain flags and only draws on
+ * interactive terminals; with --json-events progress is published as events
+ */
+export function getProgressReporter(command: Command): IProgressReporter {
+	if (jsonEventsEnabled()) {
+		return new EventProgressReporter(getServices().eventBus);
 	}
+	const { quiet } = command.optsWithGlobals();
+	return createProgressReporter({ quiet: Boolean(quiet) || porcelain });
+}
 
-	// Use ConfigManager for unified language detection
-	const { configManager } = getServices();
+/**
+ * Commands that render output with a --format template
+ */
+const templateCommands = new WeakSet<Command>();
 
-	return await configManager.getEffectiveLanguage();
+/**
+ * Mark a command as rendering --format templates (see getOutputTemplate())
+ *
+ * @param command - Command whose action reads the template
+ * @returns The same command, for chaining
+ */
+export function rendersTemplates(command: Command): Command {
+	templateCommands.add(command);
+	return command;
+}
+
+/**
+ * Reject a --format template given to a command that would ignore it
+ *
+ * @param command - Command about to run
+ * @throws TemplateError if --format is a template and the command was not
+ *   marked with rendersTemplates()
+ */
+export function checkOutputTemplate(command: Command): void {
+	const { format } = command.optsWithGlobals();
+	if (
+		typeof format === "string" &&
+		isTemplate(format) &&
+		!templateCommands.has(command)
+	) {
+		throw new TemplateError(
+			`'${command.name()}' does not support --format templates`,
+			format,
+		);
+	}
+}
+
+/**
+ * Get the output template given with the global --format flag
+ *
+ * @returns The parsed template, or undefined when --format names a format
+ *   (default, compact, json) instead
+ * @throws TemplateError if the template is malformed
+ */
+export function getOutputTemplate(command: Command): Template | undefined {
+	const { format } = command.optsWithGlobals();
+	return typeof format === "string" && isTemplate(format)
+		? parseTemplate(format)
+		: undefined;
+}
+
+/**
+ * Get a library client over the CLI's service instances
+ *
+ * Commands use it for the orchestration they share with embedders
+ * (language, configuration and install defaults).
+ */
+export function getClient(): ClaudeCmdClient {
+	return new ClaudeCmdClient(getServices());
+}
+
+/**
+ * Parse a positive integer option value (e.g., --limit, --concurrency)
+ */
+export function parsePositiveInteger(value: string): number {
+	const parsed = Number(value);
+	if (!Number.isInteger(parsed) || parsed < 1) {
+		throw new InvalidArgumentError("Must be a positive integer.");
+	}
+	return parsed;
+}
+
+/**
+ * Parse an option value that must be a whole number, 0 included
+ */
+export function parseNonNegativeInteger(value: string): number {
+	const parsed = Number(value);
+	if (!Number.isInteger(parsed) || parsed < 0) {
+		throw new InvalidArgumentError("Must be a non-negative integer.");
+	}
+	return parsed;
+}
+
+/**
+ * Parse a language code option value (e.g., --language)
+ */
+export function parseLanguageCode(value: string): string {
+	const normalized = normalizeLanguageCode(value);
+	if (!normalized) {
+		throw new InvalidArgumentError("Must be a 2-3 letter language code.");
+	}
+	return normalized;
+}
+
+/**
+ * Parse a 'personal' or 'project' location option value
+ */
+export function parseInstallLocation(value: string): "personal" | "project" {
+	if (value !== "personal" && value !== "project") {
+		throw new InvalidArgumentError("Must be 'personal' or 'project'.");
+	}
+	return value;
 }
@@ -0,0 +1,81 @@
+import type { Command } from "commander";
+
+/**
+ * Groups shown in root help, in display order
+ */
+export const COMMAND_GROUPS = [
+	"Discover",
+	"Install",
+	"Maintain",
+	"Configure",
+	"Advanced",
+] as const;
+
+/**
+ * Help group a subcommand belongs to
+ */
+export type CommandGroup = (typeof COMMAND_GROUPS)[number];
+
+/**
+ * Group used for subcommands registered without one
+ */
+const DEFAULT_GROUP: CommandGroup = "Advanced";
+
+/**
+ * Annotate a subcommand with the help group it belongs to
+ *
+ * Command modules call this where they create their command so that new
+ * subcommands place themselves in root help without touching main.ts.
+ *
+ * @param command - Subcommand to annotate
+ * @param group - Help group for the subcommand
+ * @returns The same command, for chaining
+ */
+export function inGroup(command: Command, group: CommandGroup): Command {
+	return command.helpGroup(toHeading(group));
+}
+
+/**
+ * Get the help group a subcommand was annotated with
+ *
+ * @param command - Subcommand to inspect
+ * @returns The annotated group, or undefined if none was set
+ */
+export function getCommandGroup(command: Command): CommandGroup | undefined {
+	const heading = command.helpGroup();
+	return COMMAND_GROUPS.find((group) => toHeading(group) === heading);
+}
+
+/**
+ * Add subcommands to a program ordered by help group
+ *
+ * Commander lists groups in the order it first sees them, so commands are
+ * sorted by group before being added. Registration order is kept within a
+ * group, and unannotated commands land in the "Advanced" group.
+ *
+ * @param program - Root program
+ * @param commands - Subcommands to register
+ */
+export function registerCommands(program: Command, commands: Command[]): void {
+	const ordered = commands
+		.map((command, index) => {
+			const group = getCommandGroup(command);
+			if (!group) {
+				inGroup(command, DEFAULT_GROUP);
+			}
+			return {
+				command,
+				index,
+				rank: COMMAND_GROUPS.indexOf(group ?? DEFAULT_GROUP),
+			};
+		})
+		.sort((a, b) => a.rank - b.rank || a.index - b.index);
+
+	for (const { command } of ordered) {
+		program.addCommand(command);
+	}
+}
+
+function toHeading(group: CommandGroup): string {
+	return `${group}:`;
+}
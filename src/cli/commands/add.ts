@@ -1,37 +1,537 @@
-import { Command } from "commander";
+import { createHash } from "node:crypto";
+import { Command, InvalidArgumentError } from "commander";
+import type IProgressReporter from "../../interfaces/IProgressReporter.js";
+import { DEFAULT_MAX_COMMAND_BYTES } from "../../services/HTTPRepository.js";
+import { RemoteCommandError } from "../../services/RemoteCommandSource.js";
 import { getServices } from "../../services/serviceFactory.js";
-import { handleError } from "../cliUtils.js";
+import { CommandNotFoundError } from "../../types/Command.js";
+import {
+	CommandExistsError,
+	type InstallOptions,
+} from "../../types/Installation.js";
+import type { Provenance } from "../../utils/frontmatter.js";
+import {
+	isValidLanguageCode,
+	validateCommandName,
+} from "../../utils/naming.js";
+import {
+	languageVariantName,
+	parseVersionedCommand,
+} from "../../utils/namespace.js";
+import {
+	didYouMean,
+	getClient,
+	getProgressReporter,
+	handleError,
+	isPorcelain,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import {
+	backupCommandFile,
+	promptConflictResolution,
+} from "../conflictResolution.js";
+import { ExitCode } from "../exitCodes.js";
+import { runHooks } from "../hooks.js";
+import { isQuiet, success } from "../output.js";
+import { readStdin } from "../stdin.js";
+import { confirmToolUse } from "../toolConsent.js";
+import { importedCommandName } from "./import.js";
+
+/**
+ * Version recorded for commands installed by URL, which have none
+ */
+const URL_INSTALL_VERSION = "unversioned";
 
 export const addCommand = new Command("add")
 	.description(
 		"Download and install a Claude Code slash command from the repository.",
 	)
-	.argument("<command-name>", "Name of the command to install")
-	.option("-f, --force", "Overwrite existing command if it exists")
+	.argument(
+		"[command-name]",
+		"Name of the command to install; use <name>@<version> to pin a version. With --url, the name to install under (default: from the file name)",
+	)
+	.argument(
+		"[source]",
+		"'-' to read the command file from stdin instead of the repository",
+	)
+	.option(
+		"--url <url>",
+		"Install a single command file from an https or file:// URL or a gist (gist:<id>[#file]) instead of the repository",
+	)
+	.option(
+		"-f, --force",
+		"Overwrite existing command if it exists (otherwise asks on terminals)",
+	)
 	.option("-l, --language <lang>", "Language for the command (default: en)")
 	.option(
 		"-t, --target <target>",
-		"Install target: 'personal' or 'project' (default: personal)",
+		"Install target: 'personal' or 'project' (default: defaultTarget setting, else personal)",
 	)
-	.action(async (commandName, options) => {
-		try {
-			console.log(`Installing command: ${commandName}`);
+	.option(
+		"--also <lang>",
+		"Also install the command in another language as <command-name>-<lang> (repeatable)",
+		collectLanguages,
+		[] as string[],
+	)
+	.option(
+		"-y, --yes",
+		"Install required commands and approve requested tools without asking",
+	)
+	.option("--no-deps", "Do not check for or install required commands")
+	.action(
+		async (
+			spec: string | undefined,
+			source: string | undefined,
+			options,
+			command: Command,
+		) => {
+			if (source !== undefined) {
+				if (source !== "-" || !spec || options.url) {
+					console.error(
+						"error: use 'add <command-name> -' to read a command file from stdin",
+					);
+					process.exitCode = ExitCode.Failure;
+					return;
+				}
+				await addFromStdin(spec, options);
+				return;
+			}
+			if (options.url) {
+				await addFromURL(options.url, spec, options);
+				return;
+			}
+			if (!spec) {
+				console.error(
+					"error: missing required argument 'command-name' (or --url)",
+				);
+				process.exitCode = ExitCode.Failure;
+				return;
+			}
+
+			let commandName = spec;
+			try {
+				// Get singleton service instances from factory
+				const { commandQueryService, operationHistory, usageStatsService } =
+					getServices();
+
+				// Everything installed here can be reverted with `claude-cmd undo`
+				await operationHistory.batch(`add ${spec}`, async () => {
+					const parsed = parseVersionedCommand(spec);
+					commandName = parsed.name;
+					console.log(`Installing command: ${spec}`);
+
+					// Prepare installation options (target and provenance from config)
+					const installOptions = await getClient().resolveInstallOptions({
+						force: options.force,
+						language: command.optsWithGlobals().language || "en",
+						target: options.target,
+						version: parsed.version,
+					});
+					// Bare names find commands of prefixed repositories (team:deploy)
+					commandName = await commandQueryService.resolveCommandName(
+						commandName,
+						{ language: installOptions.language },
+					);
+					const hookContext = {
+						command: commandName,
+						language: installOptions.language,
+						target: installOptions.target,
+					};
+					await runHooks("pre-add", hookContext);
+
+					// Install the command; the download shows a spinner on terminals
+					const progress = getProgressReporter(command);
+					if (options.deps) {
+						await installDependencies(
+							commandName,
+							installOptions,
+							progress,
+							options.yes ?? false,
+						);
+					}
+					const installed = await installResolvingConflicts(
+						commandName,
+						installOptions,
+						progress,
+						`Downloading ${commandName}`,
+						options.yes ?? false,
+					);
+					if (installed) {
+						success(`Successfully installed command: ${commandName}`);
+						if (parsed.version && !isQuiet()) {
+							console.log(`Pinned ${commandName} to version ${parsed.version}`);
+						}
+						await usageStatsService.record({
+							command: commandName,
+							action: "install",
+							language: installOptions.language,
+							target: installOptions.target,
+						});
+					}
+
+					for (const language of options.also as string[]) {
+						if (language === installOptions.language) {
+							continue;
+						}
+						const variantName = languageVariantName(commandName, language);
+						const variantInstalled = await installResolvingConflicts(
+							commandName,
+							{
+								...installOptions,
+								language,
+								installAs: variantName,
+								version: undefined,
+							},
+							progress,
+							`Downloading ${commandName} (${language})`,
+							options.yes ?? false,
+						);
+						if (!variantInstalled) {
+							continue;
+						}
+						success(`Installed ${language} variant as: ${variantName}`);
+						await usageStatsService.record({
+							command: variantName,
+							action: "install",
+							language,
+							target: installOptions.target,
+						});
+					}
+
+					await runHooks("post-add", hookContext);
+				});
+			} catch (error) {
+				await handleError(
+					error,
+					`Failed to install command '${commandName}'`,
+					error instanceof CommandNotFoundError
+						? await didYouMean(error.commandName, {
+								repository: true,
+								language: command.optsWithGlobals().language,
+							})
+						: [],
+				);
+			}
+		},
+	);
+
+/**
+ * Options shared by the installs that bypass the manifest
+ */
+interface ExternalAddOptions {
+	force?: boolean;
+	target?: string;
+	yes?: boolean;
+}
+
+/**
+ * Install a command file shared by URL or gist, bypassing the manifest
+ *
+ * The file is installed like an imported file (see installExternalFile()),
+ * with its URL, checksum and install time recorded in its frontmatter.
+ *
+ * @param url - URL or gist reference (see RemoteCommandSource)
+ * @param name - Name to install under; derived from the file name if unset
+ */
+async function addFromURL(
+	url: string,
+	name: string | undefined,
+	options: ExternalAddOptions,
+): Promise<void> {
+	let commandName = name ?? url;
+	try {
+		const { clock, remoteCommandSource } = getServices();
+
+		console.log(`Downloading ${url}`);
+		const file = await remoteCommandSource.fetch(url);
+		const derived = name ?? importedCommandName(file.fileName, {});
+		if (!derived) {
+			throw new RemoteCommandError(
+				`Cannot name a command after ${file.fileName || url}; pass <command-name>`,
+				url,
+			);
+		}
+		commandName = derived;
 
-			// Get singleton service instances from factory
-			const { installationService } = getServices();
+		const installed = await installExternalFile(
+			commandName,
+			file.content,
+			`add --url ${url}`,
+			options,
+			{
+				source: file.url,
+				version: URL_INSTALL_VERSION,
+				sha256: createHash("sha256")
+					.update(file.content, "utf8")
+					.digest("hex"),
+				installedAt: new Date(clock.now()).toISOString(),
+			},
+		);
+		if (installed) {
+			success(
+				`Successfully installed command: ${commandName} (from ${file.url})`,
+			);
+		}
+	} catch (error) {
+		await handleError(error, `Failed to install command '${commandName}'`);
+	}
+}
+
+/**
+ * Install a command file piped to stdin, bypassing the manifest
+ *
+ * Lets generators install commands without temporary files:
+ * `generate | claude-cmd add my-command -`.
+ *
+ * @param name - Name to install under
+ */
+async function addFromStdin(
+	name: string,
+	options: ExternalAddOptions,
+): Promise<void> {
+	try {
+		const content = await readStdin(DEFAULT_MAX_COMMAND_BYTES);
+		const installed = await installExternalFile(
+			name,
+			content,
+			`add ${name} -`,
+			options,
+		);
+		if (installed) {
+			success(`Successfully installed command: ${name} (from stdin)`);
+		}
+	} catch (error) {
+		await handleError(error, `Failed to install command '${name}'`);
+	}
+}
 
-			// Prepare installation options
-			const installOptions = {
-				force: options.force,
-				language: options.language || "en",
-				target: options.target || "personal",
-			};
+/**
+ * Validate and install command file content from outside the repository
+ *
+ * The content must parse as a command file. It is installed like an imported
+ * file: no lockfile entry, so it is never upgraded. Runs the add hooks and
+ * records the install in the usage stats.
+ *
+ * @param commandName - Name to install under
+ * @param content - Command file content
+ * @param label - Operation history label (shown by `claude-cmd undo`)
+ * @param options - Target, overwrite flag and tool consent
+ * @param provenance - Where the file came from, if it has a source to record
+ * @returns Whether the command was installed (false if tools were declined)
+ */
+async function installExternalFile(
+	commandName: string,
+	content: string,
+	label: string,
+	options: ExternalAddOptions,
+	provenance?: Provenance,
+): Promise<boolean> {
+	const {
+		commandParser,
+		configManager,
+		installationService,
+		operationHistory,
+		usageStatsService,
+		userConfigService,
+		userInteractionService,
+	} = getServices();
+
+	validateCommandName(commandName);
+	// Refuse anything that is not a command file before asking about tools
+	await commandParser.parseCommandFile(content, commandName);
+	const allowed = await confirmToolUse(
+		commandName,
+		content,
+		options.yes ?? false,
+		{
+			commandParser,
+			userInteractionService,
+			configService: userConfigService,
+		},
+	);
+	if (!allowed) {
+		return false;
+	}
+
+	const target =
+		options.target === "project" || options.target === "personal"
+			? options.target
+			: ((await configManager.getEffectiveConfig()).defaultTarget ??
+				"personal");
+	const hookContext = { command: commandName, target };
+	await runHooks("pre-add", hookContext);
+	await operationHistory.batch(label, () =>
+		installationService.importCommand(commandName, content, {
+			target,
+			force: options.force,
+			provenance,
+		}),
+	);
+	await usageStatsService.record({
+		command: commandName,
+		action: "install",
+		target,
+	});
+	await runHooks("post-add", hookContext);
+	return true;
+}
 
-			// Install the command
-			await installationService.installCommand(commandName, installOptions);
+/**
+ * Offer to install the commands a command requires before installing it
+ *
+ * Requirement cycles and unknown required commands abort the installation
+ * before anything is written.
+ */
+async function installDependencies(
+	commandName: string,
+	options: InstallOptions,
+	progress: IProgressReporter,
+	yes: boolean,
+): Promise<void> {
+	const { installationService, userInteractionService, usageStatsService } =
+		getServices();
 
-			console.log(`✓ Successfully installed command: ${commandName}`);
-		} catch (error) {
-			handleError(error, `Failed to install command '${commandName}'`);
+	progress.start(`Resolving requirements of ${commandName}`);
+	let missing: string[];
+	try {
+		missing = await installationService.getMissingDependencies(
+			commandName,
+			options.language,
+		);
+	} finally {
+		progress.finish();
+	}
+	if (missing.length === 0) {
+		return;
+	}
+
+	console.log(`${commandName} requires: ${missing.join(", ")}`);
+	userInteractionService.setYesMode(yes);
+	const proceed = await userInteractionService.confirmAction({
+		message: "Install required commands first?",
+		defaultResponse: true,
+		skipWithYes: true,
+	});
+	if (!proceed) {
+		console.log("Continuing without required commands.");
+		return;
+	}
+
+	for (const name of missing) {
+		const installed = await installResolvingConflicts(
+			name,
+			{ ...options, installAs: undefined, version: undefined },
+			progress,
+			`Downloading ${name}`,
+			yes,
+		);
+		if (!installed) {
+			continue;
 		}
+		success(`Installed required command: ${name}`);
+		await usageStatsService.record({
+			command: name,
+			action: "install",
+			language: options.language,
+			target: options.target,
+		});
+	}
+}
+
+/**
+ * Install a command, asking how to resolve a clash with an existing file
+ *
+ * On terminals the user can overwrite, back up and overwrite, inspect a diff
+ * or skip; elsewhere (and in --porcelain mode) the CommandExistsError is
+ * rethrown unchanged. Commands requesting powerful tools are reviewed first
+ * (see confirmToolUse).
+ *
+ * @returns false if the command was skipped
+ */
+async function installResolvingConflicts(
+	commandName: string,
+	options: InstallOptions,
+	progress: IProgressReporter,
+	label: string,
+	yes: boolean,
+): Promise<boolean> {
+	const {
+		commandParser,
+		installationService,
+		userInteractionService,
+		userConfigService,
+		fileService,
+		repository,
+		operationHistory,
+	} = getServices();
+
+	// Commands with shell or broad editing access need consent first
+	const content = await repository.getCommand(
+		commandName,
+		options.language ?? "en",
+	);
+	const allowed = await confirmToolUse(commandName, content, yes, {
+		commandParser,
+		userInteractionService,
+		configService: userConfigService,
 	});
+	if (!allowed) {
+		return false;
+	}
+
+	const install = async (force?: boolean) => {
+		progress.start(label);
+		try {
+			await installationService.installCommand(commandName, {
+				...options,
+				force: force ?? options.force,
+			});
+		} finally {
+			progress.finish();
+		}
+	};
+
+	try {
+		await install();
+		return true;
+	} catch (error) {
+		if (!(error instanceof CommandExistsError) || isPorcelain()) {
+			throw error;
+		}
+
+		const resolution = await promptConflictResolution(
+			error,
+			() => repository.getCommand(commandName, options.language ?? "en"),
+			{ userInteractionService, fileService },
+		);
+		if (resolution === undefined) {
+			throw error;
+		}
+		if (resolution === "skip") {
+			console.log(`Skipped ${options.installAs ?? commandName}`);
+			return false;
+		}
+		if (resolution === "backup") {
+			await operationHistory.capture(error.existingPath);
+			const backupPath = await backupCommandFile(
+				fileService,
+				error.existingPath,
+			);
+			console.log(`Backed up existing command to ${backupPath}`);
+		}
+		await install(true);
+		return true;
+	}
+}
+
+/**
+ * Accumulate repeated --also values, ignoring duplicates
+ */
+function collectLanguages(value: string, previous: string[]): string[] {
+	if (!isValidLanguageCode(value)) {
+		throw new InvalidArgumentError(`Invalid language code '${value}'.`);
+	}
+	return previous.includes(value) ? previous : [...previous, value];
+}
+
+inGroup(addCommand, "Install");
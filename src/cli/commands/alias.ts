@@ -0,0 +1,149 @@
+import { Command, InvalidArgumentError } from "commander";
+import type { IConfigService } from "../../interfaces/IConfigService.js";
+import { getServices } from "../../services/serviceFactory.js";
+import {
+	type AliasTarget,
+	formatAliasTarget,
+	parseAliasTarget,
+} from "../../utils/aliases.js";
+import { isValidCommandName } from "../../utils/naming.js";
+import { compareStrings } from "../../utils/ordering.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { warning } from "../output.js";
+
+/**
+ * An alias definition given as name=target
+ */
+interface AliasDefinition {
+	readonly alias: string;
+	readonly target: AliasTarget;
+}
+
+/**
+ * Parse an alias definition (e.g., review=project:backend:code-review)
+ */
+export function parseAliasDefinition(value: string): AliasDefinition {
+	const separator = value.indexOf("=");
+	const alias = value.slice(0, separator).trim();
+	if (separator < 1 || !isValidCommandName(alias)) {
+		throw new InvalidArgumentError("Must be <alias>=<command-name>.");
+	}
+	const target = parseAliasTarget(value.slice(separator + 1));
+	if (!target) {
+		throw new InvalidArgumentError(
+			"Target must be a command name, optionally prefixed with personal: or project:.",
+		);
+	}
+	return { alias, target };
+}
+
+/**
+ * Get the config file aliases are written to
+ */
+function serviceFor(project: boolean | undefined): IConfigService {
+	const { projectConfigService, userConfigService } = getServices();
+	return project ? projectConfigService : userConfigService;
+}
+
+export const aliasCommand = new Command("alias").description(
+	"Give installed commands short names that info, remove, edit and which accept.",
+);
+
+aliasCommand
+	.command("set")
+	.description("Define or change an alias (global config unless --project)")
+	.argument(
+		"<definition>",
+		"<alias>=<command-name>; prefix the command with personal: or project: to pick a location",
+		parseAliasDefinition,
+	)
+	.option("--project", "Write the project config")
+	.action(async (definition: AliasDefinition, options) => {
+		try {
+			const { installationService } = getServices();
+			const service = serviceFor(options.project);
+			const current = (await service.getConfig()) ?? {};
+			const target = formatAliasTarget(definition.target);
+			await service.setConfig({
+				...current,
+				aliases: { ...current.aliases, [definition.alias]: target },
+			});
+			console.log(`${definition.alias} → ${target}`);
+
+			if (
+				!(await installationService.findInstalledCommand(
+					definition.target.name,
+					definition.target.location,
+				))
+			) {
+				warning(
+					`${definition.target.name} is not installed${definition.target.location ? ` in ${definition.target.location}` : ""}`,
+				);
+			}
+			if (await installationService.isInstalled(definition.alias)) {
+				warning(`the alias hides the installed command ${definition.alias}`);
+			}
+		} catch (error) {
+			await handleError(error, `Failed to set alias '${definition.alias}'`);
+		}
+	});
+
+aliasCommand
+	.command("list")
+	.description("Show aliases, project aliases taking precedence")
+	.action(async () => {
+		try {
+			const { configManager } = getServices();
+			const { aliases = {} } = await configManager.getEffectiveConfig();
+			const entries = Object.entries(aliases).sort(([a], [b]) =>
+				compareStrings(a, b),
+			);
+
+			if (isPorcelain()) {
+				for (const [alias, target] of entries) {
+					console.log(`${alias}\t${target}`);
+				}
+				return;
+			}
+			if (entries.length === 0) {
+				console.log(
+					"No aliases defined. Add one with 'claude-cmd alias set <alias>=<command-name>'.",
+				);
+				return;
+			}
+			for (const [alias, target] of entries) {
+				console.log(`${alias} → ${target}`);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to list aliases");
+		}
+	});
+
+aliasCommand
+	.command("remove")
+	.description("Delete an alias (global config unless --project)")
+	.argument("<alias>", "Alias to delete")
+	.option("--project", "Write the project config")
+	.action(async (alias: string, options) => {
+		try {
+			const service = serviceFor(options.project);
+			const current = (await service.getConfig()) ?? {};
+			const scope = options.project ? "project" : "global";
+			if (!current.aliases || !Object.hasOwn(current.aliases, alias)) {
+				console.log(`${alias} is not an alias in ${scope} config`);
+				return;
+			}
+
+			const { [alias]: _removed, ...aliases } = current.aliases;
+			const { aliases: _previous, ...rest } = current;
+			await service.setConfig(
+				Object.keys(aliases).length > 0 ? { ...rest, aliases } : rest,
+			);
+			console.log(`Removed alias ${alias} from ${scope} config`);
+		} catch (error) {
+			await handleError(error, `Failed to remove alias '${alias}'`);
+		}
+	});
+
+inGroup(aliasCommand, "Configure");
@@ -0,0 +1,76 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import {
+	detectLanguage,
+	getProgressReporter,
+	handleError,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Bundle export subcommand - packages commands of a language for offline use
+ */
+const bundleExportCommand = new Command("export")
+	.description(
+		"Package the manifest and all command files of a language into a .tar.gz bundle.",
+	)
+	.argument("<file>", "Bundle file to create (e.g., commands-en.tar.gz)")
+	.option(
+		"-l, --language <lang>",
+		"Language for commands (default: auto-detect)",
+	)
+	.action(async (file: string, options, command: Command) => {
+		try {
+			const { bundleService } = getServices();
+			const language = await detectLanguage(options.language);
+
+			console.log(`Exporting ${language} commands...`);
+			const result = await bundleService.exportBundle(
+				language,
+				file,
+				getProgressReporter(command),
+			);
+
+			success(
+				`Exported ${result.commandCount} commands (${result.language}) to ${result.path}`,
+			);
+		} catch (error) {
+			await handleError(error, "Failed to export bundle");
+		}
+	});
+
+/**
+ * Bundle import subcommand - makes a bundle available without network access
+ */
+const bundleImportCommand = new Command("import")
+	.description(
+		"Import a bundle created by 'bundle export' so commands can be listed and installed offline.",
+	)
+	.argument("<file>", "Bundle file to import")
+	.action(async (file: string, _options, command: Command) => {
+		try {
+			const { bundleService } = getServices();
+
+			const result = await bundleService.importBundle(
+				file,
+				getProgressReporter(command),
+			);
+
+			success(
+				`Imported ${result.commandCount} commands (${result.language}) into ${result.path}`,
+			);
+		} catch (error) {
+			await handleError(error, "Failed to import bundle");
+		}
+	});
+
+/**
+ * Main bundle command with subcommands for offline bundles
+ */
+export const bundleCommand = new Command("bundle")
+	.description("Export and import offline command bundles")
+	.addCommand(bundleExportCommand)
+	.addCommand(bundleImportCommand);
+
+inGroup(bundleCommand, "Advanced");
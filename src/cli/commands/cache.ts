@@ -1,7 +1,23 @@
 import { Command } from "commander";
+import type { CacheInspection } from "../../interfaces/ICacheManager.js";
+import type IProgressReporter from "../../interfaces/IProgressReporter.js";
+import { DEFAULT_PREFETCH_CONCURRENCY } from "../../services/CommandCacheService.js";
 import { getServices } from "../../services/serviceFactory.js";
 import type { CommandServiceOptions } from "../../types/Command.js";
-import { handleError } from "../cliUtils.js";
+import {
+	findDeprecatedInstalled,
+	formatDeprecation,
+} from "../../utils/commandDeprecation.js";
+import { formatDuration, formatFileSize } from "../../utils/format.js";
+import {
+	getProgressReporter,
+	handleError,
+	parsePositiveInteger,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+import { runHooks } from "../hooks.js";
+import { getTheme, success, warning } from "../output.js";
 
 /**
  * Cache update subcommand - refreshes cached command manifest from repository
@@ -17,7 +33,18 @@ const cacheUpdateCommand = new Command("update")
 		"Display detailed information about changes detected in the update",
 		false,
 	)
-	.action(async (options) => {
+	.option(
+		"--prefetch",
+		"Also download every command file for offline use",
+		false,
+	)
+	.option(
+		"--concurrency <n>",
+		"Parallel downloads when prefetching",
+		parsePositiveInteger,
+		DEFAULT_PREFETCH_CONCURRENCY,
+	)
+	.action(async (options, command: Command) => {
 		try {
 			console.log("Updating command manifest...");
 
@@ -31,12 +58,20 @@ const cacheUpdateCommand = new Command("update")
 				? { language: options.lang }
 				: {};
 
+			await runHooks("pre-update", { language: options.lang });
+
 			// Use updateCacheWithChanges to get change information
-			const result =
-				await commandCacheService.updateCacheWithChanges(serviceOptions);
+			const progress = getProgressReporter(command);
+			progress.start("Downloading manifest");
+			const result = await commandCacheService
+				.updateCacheWithChanges(serviceOptions)
+				.finally(() => progress.finish());
 
 			// Format and display the results
-			const summary = changeDisplayFormatter.formatUpdateSummary(result);
+			const summary = changeDisplayFormatter.formatUpdateSummary(
+				result,
+				getTheme(),
+			);
 			console.log(summary);
 
 			// If detailed changes are requested and there were changes, show them
@@ -51,8 +86,154 @@ const cacheUpdateCommand = new Command("update")
 				);
 				console.log(detailedOutput);
 			}
+
+			// Advisory only: a failed scan of installed commands is not an error
+			await warnDeprecatedInstalled(serviceOptions).catch(() => {});
+
+			if (options.prefetch) {
+				await prefetchCommands(serviceOptions, options.concurrency, progress);
+			}
+
+			await runHooks("post-update", { language: result.language });
 		} catch (error) {
-			handleError(error, "Failed to update command manifest");
+			await handleError(error, "Failed to update command manifest");
+		}
+	});
+
+/**
+ * Warn about installed commands the refreshed manifest marks as deprecated
+ */
+async function warnDeprecatedInstalled(
+	serviceOptions: CommandServiceOptions,
+): Promise<void> {
+	const { commandQueryService, installationService } = getServices();
+
+	const [repositoryCommands, installed] = await Promise.all([
+		commandQueryService.listCommands(serviceOptions),
+		installationService.listInstalledCommands(),
+	]);
+	const deprecated = findDeprecatedInstalled(
+		installed.map((command) => command.name),
+		repositoryCommands,
+	);
+	if (deprecated.length === 0) {
+		return;
+	}
+
+	warning(
+		[
+			`${deprecated.length} installed command(s) are deprecated:`,
+			...deprecated.map(
+				(command) => `  ${command.name}: ${formatDeprecation(command)}`,
+			),
+		].join("\n"),
+	);
+}
+
+/**
+ * Download all command files into the content cache, reporting progress
+ */
+async function prefetchCommands(
+	serviceOptions: CommandServiceOptions,
+	concurrency: number,
+	progress: IProgressReporter,
+): Promise<void> {
+	const { commandCacheService } = getServices();
+
+	let started = false;
+	const result = await commandCacheService
+		.prefetchCommands({
+			...serviceOptions,
+			concurrency,
+			onProgress: ({ total, bytes }) => {
+				if (!started) {
+					progress.start("Prefetching command files", total);
+					started = true;
+				}
+				progress.advance(1, bytes);
+			},
+		})
+		.finally(() => progress.finish());
+
+	console.log(
+		`Prefetched ${result.fetched}/${result.total} command files (${result.language})`,
+	);
+	if (result.failed.length > 0) {
+		console.log(`\nFailed to fetch ${result.failed.length} command files:`);
+		for (const failure of result.failed) {
+			console.log(`  ${failure.commandName}: ${failure.error}`);
+		}
+		process.exitCode = ExitCode.Network;
+	}
+}
+
+/**
+ * Cache info subcommand - shows where manifests are cached and their state
+ */
+const cacheInfoCommand = new Command("info")
+	.description(
+		"Show cache location and the size, age and health of cached manifests.",
+	)
+	.option("-l, --lang <language>", "Show a specific language only")
+	.option(
+		"--usage",
+		"Show disk space used per language, cached command files included",
+	)
+	.action(async (options) => {
+		try {
+			const { cacheManager, clock, statusFormatter, statusService } =
+				getServices();
+			console.log(`Cache directory: ${cacheManager.getCacheDir()}`);
+
+			if (options.usage) {
+				const usage = (await statusService.getDiskUsage()).cache.filter(
+					(lang) => !options.lang || lang.language === options.lang,
+				);
+				console.log("");
+				console.log(statusFormatter.formatCacheUsage(usage));
+				if (usage.length > 1) {
+					const total = usage.reduce((sum, lang) => sum + lang.sizeBytes, 0);
+					console.log(`Total: ${formatFileSize(total)}`);
+				}
+				return;
+			}
+
+			const languages = options.lang
+				? [options.lang]
+				: await cacheManager.listLanguages();
+
+			const inspections: CacheInspection[] = [];
+			for (const language of languages) {
+				const inspection = await cacheManager.inspect(language);
+				if (inspection) {
+					inspections.push(inspection);
+				}
+			}
+
+			if (inspections.length === 0) {
+				console.log("No cached manifests found");
+				return;
+			}
+
+			console.log("");
+			for (const inspection of inspections) {
+				const details = [
+					inspection.status,
+					formatFileSize(inspection.sizeBytes),
+				];
+				if (inspection.commandCount !== undefined) {
+					details.push(`${inspection.commandCount} commands`);
+				}
+				if (inspection.timestamp !== undefined) {
+					details.push(
+						`updated ${formatDuration(clock.now() - inspection.timestamp)} ago`,
+					);
+				}
+				console.log(`${inspection.language}: ${details.join(", ")}`);
+				console.log(`    ${inspection.path}`);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to read cache information");
 		}
 	});
 
@@ -67,40 +248,23 @@ const cacheClearCommand = new Command("clear")
 	)
 	.action(async (options) => {
 		try {
-			const { cacheManager, languageDetector } = getServices();
+			const { cacheManager } = getServices();
 
 			if (options.lang) {
 				// Clear specific language cache
-				await cacheManager.clear(options.lang);
+				await cacheManager.remove(options.lang);
 				console.log(`Cache cleared for language: ${options.lang}`);
 			} else {
 				// Clear all language caches
-				const supportedLanguages = [
-					"en",
-					"es",
-					"fr",
-					"de",
-					"ja",
-					"zh",
-					"pt",
-					"it",
-					"ru",
-					"ko",
-				];
-
 				let clearedCount = 0;
-				for (const language of supportedLanguages) {
+				for (const language of await cacheManager.listLanguages()) {
 					try {
-						const cachePath = cacheManager.getCachePath(language);
-						const { fileService } = getServices();
-						const exists = await fileService.exists(cachePath);
-						if (exists) {
-							await cacheManager.clear(language);
+						if (await cacheManager.remove(language)) {
 							clearedCount++;
 						}
 					} catch (error) {
 						// Continue with other languages if one fails
-						console.warn(`Warning: Failed to clear cache for ${language}`);
+						warning(`Failed to clear cache for ${language}`);
 					}
 				}
 
@@ -111,7 +275,78 @@ const cacheClearCommand = new Command("clear")
 				}
 			}
 		} catch (error) {
-			handleError(error, "Failed to clear cache");
+			await handleError(error, "Failed to clear cache");
+		}
+	});
+
+/**
+ * Cache verify subcommand - detects and optionally repairs corrupted manifests
+ */
+const cacheVerifyCommand = new Command("verify")
+	.description(
+		"Check cached manifests for corruption. Use --repair to remove and re-download corrupted ones.",
+	)
+	.option("-l, --lang <language>", "Verify a specific language only")
+	.option(
+		"--repair",
+		"Remove corrupted manifests and fetch them again from the repository",
+		false,
+	)
+	.action(async (options) => {
+		try {
+			const { cacheManager, commandCacheService } = getServices();
+			const languages = options.lang
+				? [options.lang]
+				: await cacheManager.listLanguages();
+
+			let corruptedCount = 0;
+			let repairedCount = 0;
+			for (const language of languages) {
+				const inspection = await cacheManager.inspect(language);
+				if (!inspection) {
+					console.log(`${language}: not cached`);
+					continue;
+				}
+				if (inspection.status !== "corrupted") {
+					console.log(`${language}: ok (${inspection.status})`);
+					continue;
+				}
+
+				corruptedCount++;
+				if (!options.repair) {
+					console.log(`${language}: corrupted`);
+					continue;
+				}
+
+				await cacheManager.remove(language);
+				try {
+					await commandCacheService.updateCache({ language });
+					repairedCount++;
+					console.log(`${language}: corrupted, repaired`);
+				} catch (error) {
+					console.log(
+						`${language}: corrupted, removed (re-download failed: ${error instanceof Error ? error.message : error})`,
+					);
+				}
+			}
+
+			if (corruptedCount === 0) {
+				success("No corrupted manifests found");
+			} else if (!options.repair) {
+				console.log(
+					`\n${corruptedCount} corrupted manifest(s) found. Run 'claude-cmd cache verify --repair' to fix them.`,
+				);
+				process.exitCode = ExitCode.CacheCorrupted;
+			} else if (repairedCount < corruptedCount) {
+				console.log(
+					`\nRepaired ${repairedCount} of ${corruptedCount} corrupted manifest(s). Run 'claude-cmd cache update' once the repository is reachable.`,
+				);
+				process.exitCode = ExitCode.CacheCorrupted;
+			} else {
+				success(`Repaired ${repairedCount} corrupted manifest(s)`);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to verify cache");
 		}
 	});
 
@@ -121,4 +356,8 @@ const cacheClearCommand = new Command("clear")
 export const cacheCommand = new Command("cache")
 	.description("Manage local cache for command manifests")
 	.addCommand(cacheUpdateCommand)
-	.addCommand(cacheClearCommand);
+	.addCommand(cacheInfoCommand)
+	.addCommand(cacheClearCommand)
+	.addCommand(cacheVerifyCommand);
+
+inGroup(cacheCommand, "Maintain");
@@ -1,4 +1,5 @@
 import { Command } from "commander";
+import { inGroup } from "../commandGroups.js";
 
 export const completionCommand = new Command("completion").description(
 	"Generate the autocompletion script for claude-cmd for the specified shell.",
@@ -35,3 +36,5 @@ completionCommand
 		console.log("Generating powershell completion script...");
 		// TODO: Implement actual powershell completion generation
 	});
+
+inGroup(completionCommand, "Configure");
@@ -0,0 +1,285 @@
+import * as path from "node:path";
+import { Command, Option } from "commander";
+import type {
+	Config,
+	IConfigService,
+} from "../../interfaces/IConfigService.js";
+import { getServices } from "../../services/serviceFactory.js";
+import {
+	diagnoseConfig,
+	formatDiagnostic,
+} from "../../utils/configDiagnostics.js";
+import {
+	CONFIG_KEYS,
+	getConfigKey,
+	parseConfigValue,
+} from "../../utils/configKeys.js";
+import { compareStrings } from "../../utils/ordering.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { openInEditor } from "../editor.js";
+import { ExitCode } from "../exitCodes.js";
+import { success, warning } from "../output.js";
+
+type ConfigScope = "project" | "global";
+
+/**
+ * Add the mutually exclusive --project/--global options
+ */
+function withScope(command: Command, description: string): Command {
+	return command
+		.addOption(
+			new Option("--project", `${description} the project config`).conflicts(
+				"global",
+			),
+		)
+		.addOption(new Option("--global", `${description} the global config`));
+}
+
+/**
+ * Get the config file a scope refers to
+ */
+function serviceFor(scope: ConfigScope): IConfigService {
+	const { projectConfigService, userConfigService } = getServices();
+	return scope === "project" ? projectConfigService : userConfigService;
+}
+
+/**
+ * Read the scope selected by --project/--global, if any
+ */
+function scopeOf(options: {
+	project?: boolean;
+	global?: boolean;
+}): ConfigScope | undefined {
+	if (options.project) {
+		return "project";
+	}
+	return options.global ? "global" : undefined;
+}
+
+/**
+ * Configuration values with the scope each one comes from
+ *
+ * @param scope - Only read this scope; otherwise merge project over global
+ */
+async function readEntries(
+	scope: ConfigScope | undefined,
+): Promise<Array<{ key: string; value: unknown; scope: ConfigScope }>> {
+	const scopes: ConfigScope[] = scope ? [scope] : ["global", "project"];
+	const entries = new Map<string, { value: unknown; scope: ConfigScope }>();
+	for (const current of scopes) {
+		const config: Config = (await serviceFor(current).getConfig()) ?? {};
+		for (const [key, value] of Object.entries(config)) {
+			entries.set(key, { value, scope: current });
+		}
+	}
+	return [...entries]
+		.map(([key, entry]) => ({ key, ...entry }))
+		.sort((a, b) => compareStrings(a.key, b.key));
+}
+
+/**
+ * Check a config file and print its problems
+ *
+ * @param configPath - Config file to check; a missing file is skipped
+ * @returns Whether the file has errors (warnings do not count)
+ */
+async function reportDiagnostics(configPath: string): Promise<boolean> {
+	const { fileService } = getServices();
+	if (!(await fileService.exists(configPath))) {
+		if (!isPorcelain()) {
+			console.log(`- ${configPath} does not exist`);
+		}
+		return false;
+	}
+
+	const diagnostics = diagnoseConfig(await fileService.readFile(configPath));
+	for (const diagnostic of diagnostics) {
+		console.log(
+			isPorcelain()
+				? [
+						configPath,
+						diagnostic.line,
+						diagnostic.column,
+						diagnostic.severity,
+						diagnostic.message,
+					].join("\t")
+				: formatDiagnostic(configPath, diagnostic),
+		);
+	}
+	if (diagnostics.length === 0 && !isPorcelain()) {
+		success(`${configPath} is valid`);
+	}
+	return diagnostics.some((diagnostic) => diagnostic.severity === "error");
+}
+
+/**
+ * Format a configuration value for display
+ */
+function formatValue(value: unknown): string {
+	return typeof value === "string" ? value : JSON.stringify(value);
+}
+
+export const configCommand = new Command("config").description(
+	"Read and change claude-cmd settings in the global or project config.",
+);
+
+withScope(
+	configCommand
+		.command("list")
+		.description("Show configured settings and where each one comes from"),
+	"Only show",
+).action(async (options) => {
+	try {
+		const scope = scopeOf(options);
+		const entries = await readEntries(scope);
+
+		if (isPorcelain()) {
+			for (const entry of entries) {
+				console.log(
+					`${entry.key}\t${formatValue(entry.value)}\t${entry.scope}`,
+				);
+			}
+			return;
+		}
+
+		if (entries.length === 0) {
+			console.log("No settings configured.");
+			console.log(`Available keys: ${Object.keys(CONFIG_KEYS).join(", ")}`);
+			return;
+		}
+		for (const entry of entries) {
+			const source = scope ? "" : ` (${entry.scope})`;
+			console.log(`${entry.key} = ${formatValue(entry.value)}${source}`);
+		}
+	} catch (error) {
+		await handleError(error, "Failed to list configuration");
+	}
+});
+
+withScope(
+	configCommand
+		.command("get")
+		.description("Print the value of a setting")
+		.argument("<key>", "Setting name (e.g., preferredLanguage)"),
+	"Read from",
+).action(async (key: string, options) => {
+	try {
+		getConfigKey(key);
+		const entry = (await readEntries(scopeOf(options))).find(
+			(candidate) => candidate.key === key,
+		);
+		if (!entry) {
+			process.exitCode = ExitCode.NotFound;
+			return;
+		}
+		console.log(formatValue(entry.value));
+	} catch (error) {
+		await handleError(error, `Failed to read '${key}'`);
+	}
+});
+
+withScope(
+	configCommand
+		.command("set")
+		.description("Change a setting (global config unless --project)")
+		.argument("<key>", "Setting name (e.g., preferredLanguage)")
+		.argument("<value>", "New value"),
+	"Write",
+).action(async (key: string, value: string, options) => {
+	try {
+		const scope = scopeOf(options) ?? "global";
+		const service = serviceFor(scope);
+		const parsed = parseConfigValue(key, value);
+
+		const current = (await service.getConfig()) ?? {};
+		await service.setConfig({ ...current, [key]: parsed });
+		console.log(`Set ${key} = ${formatValue(parsed)} in ${scope} config`);
+	} catch (error) {
+		await handleError(error, `Failed to set '${key}'`);
+	}
+});
+
+withScope(
+	configCommand
+		.command("unset")
+		.description("Remove a setting (global config unless --project)")
+		.argument("<key>", "Setting name (e.g., preferredLanguage)"),
+	"Write",
+).action(async (key: string, options) => {
+	try {
+		const scope = scopeOf(options) ?? "global";
+		const service = serviceFor(scope);
+
+		const current = (await service.getConfig()) ?? {};
+		if (!Object.hasOwn(current, key)) {
+			console.log(`${key} is not set in ${scope} config`);
+			return;
+		}
+		const { [key]: _removed, ...rest } = current;
+		await service.setConfig(rest);
+		console.log(`Removed ${key} from ${scope} config`);
+	} catch (error) {
+		await handleError(error, `Failed to unset '${key}'`);
+	}
+});
+
+withScope(
+	configCommand
+		.command("edit")
+		.description(
+			"Open a config file in $VISUAL or $EDITOR (global config unless --project)",
+		),
+	"Edit",
+).action(async (options) => {
+	try {
+		const { fileService } = getServices();
+		const service = serviceFor(scopeOf(options) ?? "global");
+		const configPath = service.getConfigPath();
+
+		if (!(await fileService.exists(configPath))) {
+			await fileService.mkdir(path.dirname(configPath));
+			await fileService.writeFile(configPath, "{}\n");
+		}
+
+		const exitCode = await openInEditor(configPath);
+		if (exitCode !== 0) {
+			warning(`editor exited with code ${exitCode}`);
+		}
+
+		// Invalid files are ignored at runtime, so say so while it is fresh
+		if (await reportDiagnostics(configPath)) {
+			warning(`${configPath} is invalid and will be ignored until fixed`);
+		}
+	} catch (error) {
+		await handleError(error, "Failed to edit configuration");
+	}
+});
+
+withScope(
+	configCommand
+		.command("validate")
+		.description(
+			"Check config files for syntax errors, unknown keys and invalid values",
+		),
+	"Only check",
+).action(async (options) => {
+	try {
+		const scope = scopeOf(options);
+		const scopes: ConfigScope[] = scope ? [scope] : ["global", "project"];
+
+		let failed = false;
+		for (const current of scopes) {
+			if (await reportDiagnostics(serviceFor(current).getConfigPath())) {
+				failed = true;
+			}
+		}
+		if (failed) {
+			process.exitCode = ExitCode.Validation;
+		}
+	} catch (error) {
+		await handleError(error, "Failed to validate configuration");
+	}
+});
+
+inGroup(configCommand, "Configure");
@@ -0,0 +1,152 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import type { InstallationInfo } from "../../types/Installation.js";
+import { countDiffLines, formatLineDiff } from "../../utils/diff.js";
+import { stripProvenance } from "../../utils/frontmatter.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+
+/**
+ * A command installed both in the project and personally
+ *
+ * Claude Code uses the project copy; the personal one is shadowed.
+ */
+export interface ScopeConflict {
+	/** Command name */
+	readonly name: string;
+	/** Project copy, the one Claude Code uses */
+	readonly project: InstallationInfo;
+	/** Personal copy, shadowed by the project one */
+	readonly personal: InstallationInfo;
+}
+
+/**
+ * How the project copy of a conflicting command differs from the personal one
+ */
+export interface ScopeConflictReport extends ScopeConflict {
+	/** Lines the project copy adds */
+	readonly added: number;
+	/** Lines the project copy removes */
+	readonly removed: number;
+	/** Line diff from the personal to the project copy, if requested */
+	readonly diff: string;
+}
+
+/**
+ * Find the commands installed in both scopes
+ *
+ * @param installed - Every installed copy (see getAllInstallationInfo())
+ * @returns Conflicts in the order of `installed`
+ */
+export function findScopeConflicts(
+	installed: readonly InstallationInfo[],
+): ScopeConflict[] {
+	const conflicts: ScopeConflict[] = [];
+	for (const project of installed) {
+		if (project.location !== "project") {
+			continue;
+		}
+		const personal = installed.find(
+			(info) => info.location === "personal" && info.name === project.name,
+		);
+		if (personal) {
+			conflicts.push({ name: project.name, project, personal });
+		}
+	}
+	return conflicts;
+}
+
+/**
+ * Summarize how the copies of a conflicting command differ
+ *
+ * @param report - Conflict and its line counts
+ * @returns "identical", or the lines the project copy adds and removes
+ */
+export function formatConflictSummary(report: ScopeConflictReport): string {
+	if (report.added === 0 && report.removed === 0) {
+		return "identical";
+	}
+	return `differs: +${report.added} -${report.removed} lines in the project copy`;
+}
+
+/**
+ * Format conflicts for terminal output
+ *
+ * @param reports - Conflicts to show
+ * @param showDiff - Include the line diff of each differing command
+ */
+export function formatConflicts(
+	reports: readonly ScopeConflictReport[],
+	showDiff = false,
+): string {
+	if (reports.length === 0) {
+		return "No command is installed both in the project and personally.";
+	}
+
+	const noun = reports.length === 1 ? "command is" : "commands are";
+	let output = `${reports.length} ${noun} installed both in the project and personally.\nClaude Code uses the project copy; the personal copy is ignored.\n`;
+	for (const report of reports) {
+		output += `\n${report.name} (${formatConflictSummary(report)})\n`;
+		output += `  uses:     ${path.resolve(report.project.filePath)}\n`;
+		output += `  shadowed: ${path.resolve(report.personal.filePath)}\n`;
+		if (showDiff && report.diff !== "") {
+			output += `${report.diff
+				.split("\n")
+				.map((line) => `    ${line}`)
+				.join("\n")}\n`;
+		}
+	}
+	return output.trim();
+}
+
+export const conflictsCommand = new Command("conflicts")
+	.description(
+		"List commands installed both in the project and personally.\nThe project copy wins, so the personal one is silently unused.",
+	)
+	.option("-d, --diff", "Show what differs between the two copies")
+	.action(async (options) => {
+		try {
+			const { fileService, installationService } = getServices();
+			const conflicts = findScopeConflicts(
+				await installationService.getAllInstallationInfo(),
+			);
+
+			const reports: ScopeConflictReport[] = [];
+			for (const conflict of conflicts) {
+				// Provenance fields differ between installs of the same content
+				const personal = stripProvenance(
+					await fileService.readFile(conflict.personal.filePath),
+				);
+				const project = stripProvenance(
+					await fileService.readFile(conflict.project.filePath),
+				);
+				reports.push({
+					...conflict,
+					...countDiffLines(personal, project),
+					diff: options.diff ? formatLineDiff(personal, project) : "",
+				});
+			}
+
+			if (isPorcelain()) {
+				for (const report of reports) {
+					console.log(
+						[
+							report.name,
+							path.resolve(report.project.filePath),
+							path.resolve(report.personal.filePath),
+							report.added,
+							report.removed,
+						].join("\t"),
+					);
+				}
+				return;
+			}
+
+			console.log(formatConflicts(reports, options.diff));
+		} catch (error) {
+			await handleError(error, "Failed to check for conflicting commands");
+		}
+	});
+
+inGroup(conflictsCommand, "Maintain");
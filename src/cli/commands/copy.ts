@@ -0,0 +1,38 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { handleError, parseInstallLocation } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { isQuiet, success } from "../output.js";
+
+export const copyCommand = new Command("copy")
+	.description(
+		"Copy an installed command between the personal and project directories.",
+	)
+	.argument("<command-name>", "Name of the installed command")
+	.requiredOption(
+		"--to <location>",
+		"Location to copy to: 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option("-f, --force", "Overwrite an existing copy at the destination")
+	.action(async (commandName, options) => {
+		try {
+			const { installationService, operationHistory } = getServices();
+
+			await operationHistory.batch(`copy ${commandName}`, async () => {
+				const result = await installationService.copyCommand(commandName, {
+					to: options.to,
+					force: options.force,
+				});
+
+				success(`Copied ${commandName} to ${result.location}`);
+				if (!isQuiet()) {
+					console.log(`  ${result.fromPath} -> ${result.toPath}`);
+				}
+			});
+		} catch (error) {
+			await handleError(error, `Failed to copy command '${commandName}'`);
+		}
+	});
+
+inGroup(copyCommand, "Install");
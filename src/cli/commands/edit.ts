@@ -0,0 +1,58 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { CommandNotInstalledError } from "../../types/Installation.js";
+import {
+	describeParseError,
+	handleError,
+	parseInstallLocation,
+	resolveAlias,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { openInEditor } from "../editor.js";
+import { success, warning } from "../output.js";
+
+export const editCommand = new Command("edit")
+	.description(
+		"Open an installed command in $VISUAL or $EDITOR and validate it after saving.",
+	)
+	.argument("<command-name>", "Name or alias of the installed command")
+	.option(
+		"--from <location>",
+		"Location to edit when installed in both: 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.action(async (commandName, options) => {
+		try {
+			const { installationService, commandParser, fileService } =
+				getServices();
+
+			const target = await resolveAlias(commandName);
+			const installed = await installationService.findInstalledCommand(
+				target.name,
+				options.from ?? target.location,
+			);
+			if (!installed) {
+				throw new CommandNotInstalledError(target.name);
+			}
+
+			const exitCode = await openInEditor(installed.filePath);
+			if (exitCode !== 0) {
+				warning(`editor exited with code ${exitCode}`);
+			}
+
+			// Re-validate so a broken frontmatter is noticed before Claude loads it
+			const content = await fileService.readFile(installed.filePath);
+			try {
+				await commandParser.parseCommandFile(content, target.name);
+				success(`${installed.filePath} is valid`);
+			} catch (error) {
+				warning(
+					`${installed.filePath} is invalid: ${describeParseError(error)}`,
+				);
+			}
+		} catch (error) {
+			await handleError(error, `Failed to edit command '${commandName}'`);
+		}
+	});
+
+inGroup(editCommand, "Install");
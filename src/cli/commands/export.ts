@@ -0,0 +1,49 @@
+import { Command, InvalidArgumentError } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { handleError } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Parse the --scope option value
+ */
+function parseScope(value: string): "personal" | "project" | "all" {
+	if (value !== "personal" && value !== "project" && value !== "all") {
+		throw new InvalidArgumentError("Must be 'personal', 'project' or 'all'.");
+	}
+	return value;
+}
+
+export const exportCommand = new Command("export")
+	.description(
+		"Copy installed commands, namespaces included, into a .tar.gz archive or a directory for sharing or backup. Bring them back with 'import'.",
+	)
+	.argument(
+		"<dest>",
+		"Archive to create (ending in .tar.gz or .tgz) or directory to copy into",
+	)
+	.option(
+		"-s, --scope <scope>",
+		"Commands to export: 'personal', 'project' or 'all' (all keeps them apart in personal/ and project/)",
+		parseScope,
+		"all",
+	)
+	.option("-f, --force", "Overwrite existing files at the destination")
+	.action(async (dest: string, options) => {
+		try {
+			const { installationService } = getServices();
+
+			const result = await installationService.exportCommands(dest, {
+				scope: options.scope,
+				force: options.force,
+			});
+
+			success(
+				`Exported ${result.commandCount} command(s) (${options.scope}) to ${result.path}`,
+			);
+		} catch (error) {
+			await handleError(error, "Failed to export commands");
+		}
+	});
+
+inGroup(exportCommand, "Maintain");
@@ -0,0 +1,213 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { isInNamespace } from "../../utils/namespace.js";
+import {
+	handleError,
+	isPorcelain,
+	parseInstallLocation,
+	parseNonNegativeInteger,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+
+/**
+ * One line of a command file shown in grep output
+ */
+export interface GrepLine {
+	/** Line number, from 1 */
+	readonly line: number;
+	/** Line text without its line ending */
+	readonly text: string;
+	/** Whether the line matches (false for context lines) */
+	readonly match: boolean;
+}
+
+/**
+ * Options of a content search
+ */
+export interface GrepOptions {
+	/** Match regardless of case */
+	readonly ignoreCase?: boolean;
+	/** Treat the pattern as a literal string rather than a regular expression */
+	readonly fixedStrings?: boolean;
+}
+
+/**
+ * Compile a grep pattern
+ *
+ * @param pattern - Regular expression, or literal text with fixedStrings
+ * @param options - How to interpret the pattern
+ * @throws SyntaxError if the pattern is not a valid regular expression
+ */
+export function compileGrepPattern(
+	pattern: string,
+	options: GrepOptions = {},
+): RegExp {
+	const source = options.fixedStrings
+		? pattern.replace(/[.*+?^${}()|[\]\\]/g, "\\$&")
+		: pattern;
+	return new RegExp(source, options.ignoreCase ? "i" : "");
+}
+
+/**
+ * Find the lines of a file matching a pattern
+ *
+ * Matches are returned with up to `context` lines before and after them.
+ * Overlapping or adjacent stretches are merged, so each group is a run of
+ * consecutive lines.
+ *
+ * @param content - File content
+ * @param pattern - Pattern lines are tested against (see compileGrepPattern())
+ * @param context - Lines of context around each match
+ * @returns Groups of consecutive lines, empty when nothing matches
+ */
+export function grepContent(
+	content: string,
+	pattern: RegExp,
+	context = 0,
+): GrepLine[][] {
+	const lines = content.split(/\r?\n/);
+	if (lines.at(-1) === "") {
+		lines.pop();
+	}
+
+	const groups: GrepLine[][] = [];
+	let current: GrepLine[] = [];
+	let lastShown = -1;
+	for (const [index, text] of lines.entries()) {
+		if (!pattern.test(text)) {
+			continue;
+		}
+		const first = Math.max(index - context, lastShown + 1);
+		if (current.length > 0 && first > lastShown + 1) {
+			groups.push(current);
+			current = [];
+		}
+		const last = Math.min(index + context, lines.length - 1);
+		for (let i = first; i <= last; i++) {
+			current.push({
+				line: i + 1,
+				text: lines[i] ?? "",
+				match: i === index || pattern.test(lines[i] ?? ""),
+			});
+		}
+		lastShown = Math.max(lastShown, last);
+	}
+	if (current.length > 0) {
+		groups.push(current);
+	}
+	return groups;
+}
+
+/**
+ * Format the matches of one file like grep(1)
+ *
+ * Matching lines read "file:line:text", context lines "file-line-text";
+ * groups are separated by "--".
+ *
+ * @param file - File name to prefix lines with
+ * @param groups - Matches of the file (see grepContent())
+ */
+export function formatGrepMatches(
+	file: string,
+	groups: readonly (readonly GrepLine[])[],
+): string {
+	return groups
+		.map((group) =>
+			group
+				.map(({ line, text, match }) => {
+					const separator = match ? ":" : "-";
+					return `${file}${separator}${line}${separator}${text}`;
+				})
+				.join("\n"),
+		)
+		.join("\n--\n");
+}
+
+export const grepCommand = new Command("grep")
+	.description(
+		"Search the content of installed commands, personal and project, and print matching lines.\nExits with 1 if nothing matches.",
+	)
+	.argument("<pattern>", "Regular expression to search for")
+	.option("-i, --ignore-case", "Match regardless of case")
+	.option("-F, --fixed-strings", "Treat the pattern as literal text")
+	.option(
+		"-C, --context <n>",
+		"Show n lines of context around each match",
+		parseNonNegativeInteger,
+		0,
+	)
+	.option("-l, --files-with-matches", "Only print the files that match")
+	.option(
+		"--from <location>",
+		"Only search 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option(
+		"--namespace <namespace>",
+		"Only search commands in this namespace (e.g., 'frontend')",
+	)
+	.action(async (pattern: string, options) => {
+		try {
+			let regex: RegExp;
+			try {
+				regex = compileGrepPattern(pattern, {
+					ignoreCase: options.ignoreCase,
+					fixedStrings: options.fixedStrings,
+				});
+			} catch (error) {
+				console.error(
+					`Invalid pattern '${pattern}': ${error instanceof Error ? error.message : String(error)}. Use -F to search for literal text.`,
+				);
+				process.exitCode = ExitCode.Validation;
+				return;
+			}
+
+			const { fileService, installationService } = getServices();
+			// Sorted by name, then location
+			const installed = (
+				await installationService.getAllInstallationInfo()
+			).filter(
+				(info) =>
+					(!options.from || info.location === options.from) &&
+					(!options.namespace || isInNamespace(info.name, options.namespace)),
+			);
+
+			let found = false;
+			for (const info of installed) {
+				const file = path.resolve(info.filePath);
+				const groups = grepContent(
+					await fileService.readFile(info.filePath),
+					regex,
+					options.filesWithMatches || isPorcelain() ? 0 : options.context,
+				);
+				if (groups.length === 0) {
+					continue;
+				}
+				found = true;
+
+				if (options.filesWithMatches) {
+					console.log(file);
+				} else if (isPorcelain()) {
+					for (const { line, text } of groups.flat()) {
+						console.log([info.name, info.location, line, text].join("\t"));
+					}
+				} else {
+					console.log(formatGrepMatches(file, groups));
+				}
+			}
+
+			if (!found) {
+				// Like grep(1): nothing on stdout, a plain failure status
+				process.exitCode = ExitCode.Failure;
+			}
+		} catch (error) {
+			await handleError(
+				error,
+				`Failed to search installed commands for '${pattern}'`,
+			);
+		}
+	});
+
+inGroup(grepCommand, "Discover");
@@ -0,0 +1,272 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { CommandExistsError } from "../../types/Installation.js";
+import { normalizeNameSegment } from "../../utils/naming.js";
+import { pathSegments } from "../../utils/paths.js";
+import { extractTarGz } from "../../utils/tar.js";
+import { handleError, isPorcelain, parseInstallLocation } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode, exitCodeForError } from "../exitCodes.js";
+import { success, warning } from "../output.js";
+
+/**
+ * A Markdown file found for importing
+ */
+interface SourceFile {
+	/** Path of the file as found */
+	readonly filePath: string;
+	/** Path below the directory or glob base it was found in */
+	readonly relativePath: string;
+	/** Content of a file read from an archive */
+	readonly content?: string;
+	/** Location an archive of all commands kept the file under */
+	readonly location?: "personal" | "project";
+}
+
+/**
+ * Characters that make an argument a glob pattern rather than a path
+ */
+const GLOB_CHARACTERS = /[*?[{]/;
+
+/**
+ * Archives written by 'export'
+ */
+const ARCHIVE_PATTERN = /\.(tar\.gz|tgz)$/i;
+
+/**
+ * Read the Markdown files of an archive written by 'export'
+ *
+ * An archive of all commands keeps them apart in personal/ and project/;
+ * those files go back to the location they came from.
+ *
+ * @param archivePath - Path of the .tar.gz archive
+ * @returns Files with their paths below the archive root or location
+ */
+async function readArchive(archivePath: string): Promise<SourceFile[]> {
+	const { fileService } = getServices();
+	const entries = extractTarGz(
+		await fileService.readBinaryFile(archivePath),
+	).filter((entry) => entry.path.endsWith(".md"));
+	const byLocation = entries.every((entry) =>
+		/^(personal|project)\//.test(entry.path),
+	);
+
+	return entries.map((entry) => {
+		const [first = "", ...rest] = entry.path.split("/");
+		return {
+			filePath: `${archivePath}/${entry.path}`,
+			relativePath: byLocation ? rest.join("/") : entry.path,
+			content: entry.content,
+			...(byLocation ? { location: first as "personal" | "project" } : {}),
+		};
+	});
+}
+
+/**
+ * Find the Markdown files an argument names
+ *
+ * - "file.md": that file
+ * - "backup.tar.gz" or "backup.tgz": the .md files of an 'export' archive
+ * - a glob pattern (quoted so the shell leaves it alone): the matching .md
+ *   files below the pattern's fixed leading directories
+ * - anything else: every .md file below that directory
+ *
+ * @param argument - Path or glob pattern given on the command line
+ * @returns Files with their paths below the directory or glob base
+ */
+async function findSourceFiles(argument: string): Promise<SourceFile[]> {
+	const { fileService } = getServices();
+
+	if (ARCHIVE_PATTERN.test(argument)) {
+		return readArchive(argument);
+	}
+	if (GLOB_CHARACTERS.test(argument)) {
+		const segments = argument.split(/[\\/]/);
+		const firstGlob = segments.findIndex((s) => GLOB_CHARACTERS.test(s));
+		const base = segments.slice(0, firstGlob).join("/") || ".";
+		const glob = new Bun.Glob(segments.slice(firstGlob).join("/"));
+		return (await fileService.listFilesRecursive(base))
+			.map((relativePath) => pathSegments(relativePath).join("/"))
+			.filter((relativePath) => relativePath.endsWith(".md"))
+			.filter((relativePath) => glob.match(relativePath))
+			.map((relativePath) => ({
+				filePath: path.join(base, relativePath),
+				relativePath,
+			}));
+	}
+
+	if (argument.endsWith(".md")) {
+		return [{ filePath: argument, relativePath: path.basename(argument) }];
+	}
+
+	return (await fileService.listFilesRecursive(argument))
+		.filter((relativePath) => relativePath.endsWith(".md"))
+		.map((relativePath) => ({
+			filePath: path.join(argument, relativePath),
+			relativePath,
+		}));
+}
+
+/**
+ * Derive the command name of an imported file
+ *
+ * @param relativePath - Path below the directory the file was found in
+ * @param options - Namespace to put the command in and whether to drop the
+ *   file's subdirectories instead of mapping them to namespaces
+ * @returns Normalized command name, or undefined if a part has no usable
+ *   characters
+ */
+export function importedCommandName(
+	relativePath: string,
+	options: { namespace?: string; flatten?: boolean },
+): string | undefined {
+	const segments = pathSegments(relativePath.replace(/\.md$/i, ""));
+	const kept = options.flatten ? segments.slice(-1) : segments;
+	const parts = [
+		...(options.namespace ? options.namespace.split(/[:/]/) : []),
+		...kept,
+	].map(normalizeNameSegment);
+	if (parts.length === 0 || parts.some((part) => part === undefined)) {
+		return undefined;
+	}
+	return parts.join(":");
+}
+
+export const importCommand = new Command("import")
+	.description(
+		"Import hand-written command files or an 'export' archive: validate them, normalize their names and copy them into a commands directory. Subdirectories become namespaces.",
+	)
+	.argument(
+		"<paths...>",
+		"Markdown files, directories to scan, .tar.gz archives, or quoted glob patterns (e.g., 'old/**/*.md')",
+	)
+	.option(
+		"-t, --target <target>",
+		"Import into 'personal' or 'project' (default: the location an archive of all commands kept them in, else defaultTarget setting, else personal)",
+		parseInstallLocation,
+	)
+	.option(
+		"--namespace <namespace>",
+		"Put every imported command in this namespace",
+	)
+	.option(
+		"--flatten",
+		"Ignore subdirectories instead of mapping them to namespaces",
+	)
+	.option(
+		"-f, --force",
+		"Overwrite commands already installed under the same name",
+	)
+	.option("--dry-run", "Show what would be imported without writing anything")
+	.action(async (paths: string[], options) => {
+		try {
+			const {
+				commandParser,
+				configManager,
+				fileService,
+				installationService,
+				operationHistory,
+			} = getServices();
+			const defaultTarget: "personal" | "project" =
+				options.target ??
+				(await configManager.getEffectiveConfig()).defaultTarget ??
+				"personal";
+
+			const sources: SourceFile[] = [];
+			for (const argument of paths) {
+				const found = await findSourceFiles(argument);
+				if (found.length === 0) {
+					warning(`no Markdown files found in ${argument}`);
+				}
+				sources.push(...found);
+			}
+
+			let imported = 0;
+			let skipped = 0;
+			const skip = (source: SourceFile, reason: string, code: ExitCode) => {
+				warning(`Skipped ${source.filePath}: ${reason}`);
+				process.exitCode = code;
+				skipped++;
+			};
+			const seen = new Map<string, string>();
+			const targets = new Set<"personal" | "project">();
+
+			await operationHistory.batch(`import ${paths.join(" ")}`, async () => {
+				for (const source of sources) {
+					const target = options.target ?? source.location ?? defaultTarget;
+					const name = importedCommandName(source.relativePath, options);
+					if (!name) {
+						skip(source, "no usable name", ExitCode.Validation);
+						continue;
+					}
+					const earlier = seen.get(`${target}:${name}`);
+					if (earlier) {
+						skip(
+							source,
+							`'${name}' is already taken by ${earlier}`,
+							ExitCode.Validation,
+						);
+						continue;
+					}
+					seen.set(`${target}:${name}`, source.filePath);
+
+					const content =
+						source.content ?? (await fileService.readFile(source.filePath));
+					try {
+						await commandParser.parseCommandFile(content, name);
+					} catch (error) {
+						skip(
+							source,
+							`not a valid command file (${error instanceof Error ? error.message : error})`,
+							ExitCode.Validation,
+						);
+						continue;
+					}
+
+					if (options.dryRun) {
+						console.log(`Would import ${source.filePath} as ${name}`);
+						targets.add(target);
+						imported++;
+						continue;
+					}
+					try {
+						const filePath = await installationService.importCommand(
+							name,
+							content,
+							{ target, force: options.force },
+						);
+						if (isPorcelain()) {
+							console.log([name, target, filePath].join("\t"));
+						} else {
+							success(`Imported ${source.filePath} as ${name}`);
+						}
+						targets.add(target);
+						imported++;
+					} catch (error) {
+						if (!(error instanceof CommandExistsError)) {
+							throw error;
+						}
+						skip(
+							source,
+							`'${name}' is already installed (use --force to overwrite)`,
+							exitCodeForError(error),
+						);
+					}
+				}
+			});
+
+			if (!isPorcelain()) {
+				const verb = options.dryRun ? "Would import" : "Imported";
+				const into =
+					targets.size > 0 ? [...targets].join(" and ") : defaultTarget;
+				console.log(
+					`\n${verb} ${imported} command(s) into ${into}${skipped > 0 ? `, skipped ${skipped}` : ""}`,
+				);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to import commands");
+		}
+	});
+
+inGroup(importCommand, "Install");
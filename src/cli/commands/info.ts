@@ -1,10 +1,39 @@
 import { Command } from "commander";
 import { getServices } from "../../services/serviceFactory.js";
-import type {
-	Command as CommandType,
-	EnhancedCommandInfo,
+import {
+	CommandNotFoundError,
+	type CommandServiceOptions,
+	type Command as CommandType,
+	type EnhancedCommandInfo,
 } from "../../types/Command.js";
-import { detectLanguage, handleError } from "../cliUtils.js";
+import {
+	formatDeprecation,
+	isDeprecated,
+} from "../../utils/commandDeprecation.js";
+import {
+	type CommandExample,
+	formatExample,
+	parseManifestExamples,
+} from "../../utils/examples.js";
+import {
+	DEFAULT_PREVIEW_LIMITS,
+	type PreviewLimits,
+	truncatePreview,
+} from "../../utils/format.js";
+import {
+	PLAIN_THEME,
+	styleCommandName,
+	type Theme,
+} from "../../utils/style.js";
+import {
+	detectLanguage,
+	didYouMean,
+	handleError,
+	parsePositiveInteger,
+	resolveAlias,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { getTheme } from "../output.js";
 
 /**
  * Format command information for terminal output
@@ -17,6 +46,9 @@ function _formatCommandInfo(
 ): string {
 	let output = `Command: ${command.name}\n`;
 	output += `Description: ${command.description}\n`;
+	if (command["argument-hint"]) {
+		output += `Usage: /${command.name} ${command["argument-hint"]}\n`;
+	}
 	output += `File: ${command.file}\n`;
 	output += `Language: ${language}\n`;
 
@@ -35,6 +67,33 @@ function _formatCommandInfo(
 	return output.trim();
 }
 
+/**
+ * Format usage examples as the slash commands that run them
+ *
+ * Descriptions are aligned in a column after the commands.
+ *
+ * @param commandName - Name the command is run by
+ * @param examples - Examples to format
+ * @param theme - Style of the descriptions
+ * @returns Indented lines, or "" without examples
+ */
+export function formatExamples(
+	commandName: string,
+	examples: readonly CommandExample[],
+	theme: Theme = PLAIN_THEME,
+): string {
+	const lines = examples.map((example) => formatExample(commandName, example));
+	const width = Math.max(0, ...lines.map((line) => line.length));
+	return examples
+		.map((example, index) => {
+			const line = lines[index] ?? "";
+			return example.description
+				? `  ${line.padEnd(width)}  ${theme.dim(example.description)}`
+				: `  ${line}`;
+		})
+		.join("\n");
+}
+
 /**
  * Format enhanced command information for terminal output
  * Includes source attribution and installation status
@@ -43,9 +102,14 @@ function formatEnhancedCommandInfo(
 	command: EnhancedCommandInfo,
 	language: string,
 	content?: string,
+	theme: Theme = PLAIN_THEME,
+	examples: readonly CommandExample[] = [],
 ): string {
-	let output = `Command: ${command.name}\n`;
+	let output = `Command: ${styleCommandName(command.name, theme)}\n`;
 	output += `Description: ${command.description}\n`;
+	if (command["argument-hint"]) {
+		output += `Usage: /${command.name} ${command["argument-hint"]}\n`;
+	}
 	output += `File: ${command.file}\n`;
 	output += `Language: ${language}\n`;
 
@@ -63,19 +127,36 @@ function formatEnhancedCommandInfo(
 	if (command.installationStatus) {
 		const status = command.installationStatus;
 		if (status.isInstalled) {
-			output += `Installation Status: Installed (${status.installLocation})`;
+			output += `Installation Status: ${theme.success("Installed")} (${status.installLocation})`;
 			if (status.hasLocalChanges) {
-				output += " [Local changes detected]";
+				output += ` ${theme.warning("[Local changes detected]")}`;
 			}
 			output += "\n";
 			if (status.installPath) {
-				output += `Installation Path: ${status.installPath}\n`;
+				output += `Installation Path: ${theme.dim(status.installPath)}\n`;
+			}
+			if (status.projectRoot) {
+				output += `Project Root: ${status.projectRoot}\n`;
 			}
 		} else {
 			output += `Installation Status: Not installed\n`;
 		}
 	}
 
+	if (isDeprecated(command)) {
+		output += `Status: ${theme.warning(formatDeprecation(command))}\n`;
+	}
+
+	if (command.category) {
+		output += `Category: ${command.category}\n`;
+	}
+	if (command.tags && command.tags.length > 0) {
+		output += `Tags: ${command.tags.join(", ")}\n`;
+	}
+	if (command.requires && command.requires.length > 0) {
+		output += `Requires: ${command.requires.join(", ")}\n`;
+	}
+
 	if (command["allowed-tools"] && command["allowed-tools"].length > 0) {
 		const tools = Array.isArray(command["allowed-tools"])
 			? command["allowed-tools"].join(", ")
@@ -83,36 +164,129 @@ function formatEnhancedCommandInfo(
 		output += `Allowed Tools: ${tools}\n`;
 	}
 
+	if (examples.length > 0) {
+		output += `Examples:\n${formatExamples(command.name, examples, theme)}\n`;
+	}
+
 	if (content) {
-		output += `\n--- Command Content ---\n`;
+		output += `\n${theme.heading("--- Command Content ---")}\n`;
 		output += content;
 	}
 
 	return output.trim();
 }
 
+/**
+ * Resolve preview limits from the --preview-lines flag and configuration
+ *
+ * Content is shown in full unless a limit is set; the other one then takes
+ * its default.
+ *
+ * @returns The limits, or undefined when neither is set
+ */
+async function getPreviewLimits(
+	previewLines?: number,
+): Promise<PreviewLimits | undefined> {
+	const { configManager } = getServices();
+	const config = await configManager.getEffectiveConfig();
+	if (
+		previewLines === undefined &&
+		config.previewLines === undefined &&
+		config.previewCharacters === undefined
+	) {
+		return undefined;
+	}
+	return {
+		maxLines:
+			previewLines ?? config.previewLines ?? DEFAULT_PREVIEW_LIMITS.maxLines,
+		maxCharacters:
+			config.previewCharacters ?? DEFAULT_PREVIEW_LIMITS.maxCharacters,
+	};
+}
+
+/**
+ * Truncate content to a preview, noting how much was left out
+ */
+function previewContent(content: string, limits: PreviewLimits): string {
+	const { preview, omittedLines } = truncatePreview(content, limits);
+	if (omittedLines === 0) {
+		return preview;
+	}
+	return `${preview}\n... (${omittedLines} more lines; use --full to show everything)`;
+}
+
+/**
+ * Get the content of a command from the source it was found in
+ */
+async function getCommandContent(
+	command: EnhancedCommandInfo,
+	language: string,
+	options: CommandServiceOptions,
+): Promise<string> {
+	const { commandContentService, localCommandRepository } = getServices();
+	if (command.source === "repository") {
+		return commandContentService.getCommandContent(command.name, options);
+	}
+	try {
+		return await localCommandRepository.getCommand(
+			command.name,
+			language,
+			options,
+		);
+	} catch (_error) {
+		// Fallback to repository if local content isn't available
+		return commandContentService.getCommandContent(command.name, options);
+	}
+}
+
+/**
+ * Read the usable examples of a command file; none if it does not parse
+ */
+async function readExamples(
+	content: string,
+	commandName: string,
+): Promise<readonly CommandExample[]> {
+	try {
+		const parsed = await getServices().commandParser.parseCommandFile(
+			content,
+			commandName,
+		);
+		return parsed.examples ?? [];
+	} catch {
+		return [];
+	}
+}
+
 export const infoCommand = new Command("info")
 	.description(
 		"Display detailed information about a Claude Code slash command from the repository.",
 	)
-	.argument("<command-name>", "Name of the command to show info for")
+	.argument(
+		"<command-name>",
+		"Name of the command to show info for, or an alias of an installed one",
+	)
+	.option("-d, --detailed", "Show the command content")
 	.option(
-		"-d, --detailed",
-		"Show detailed command content with full file preview",
+		"--preview-lines <n>",
+		"Only show this many lines of content (implies --detailed; config: previewLines)",
+		parsePositiveInteger,
+	)
+	.option(
+		"--full",
+		"Show the full command content even if preview limits are configured (implies --detailed)",
 	)
 	.option(
 		"-l, --language <lang>",
 		"Language for commands (default: auto-detect)",
 	)
 	.option("-f, --force", "Force refresh cache even if current")
-	.action(async (commandName, options) => {
+	.action(async (typedName, options) => {
 		try {
+			// Aliases of installed commands are shown as the command they stand for
+			const { name: commandName } = await resolveAlias(typedName);
+
 			// Get singleton service instances from factory
-			const {
-				commandEnrichmentService,
-				commandContentService,
-				languageDetector,
-			} = getServices();
+			const { commandEnrichmentService } = getServices();
 
 			// Prepare options for CommandService
 			const serviceOptions = {
@@ -128,34 +302,29 @@ export const infoCommand = new Command("info")
 				);
 
 			// Determine language used via shared utility
-			const language = await detectLanguage(options.language, languageDetector);
-
-			// Get command content if detailed flag is set
-			let content: string | undefined;
-			if (options.detailed) {
-				// Try to get content from the primary source (repository or local)
-				if (enhancedCommand.source === "repository") {
-					content = await commandContentService.getCommandContent(
-						commandName,
-						serviceOptions,
-					);
-				} else {
-					// For local commands, get content from the LocalCommandRepository
-					const { localCommandRepository } = getServices();
-					try {
-						content = await localCommandRepository.getCommand(
-							commandName,
-							language,
-							serviceOptions,
-						);
-					} catch (_error) {
-						// Fallback to repository if local content isn't available
-						content = await commandContentService.getCommandContent(
-							commandName,
-							serviceOptions,
-						);
-					}
-				}
+			const language = await detectLanguage(options.language);
+
+			// The file is only fetched to be shown; examples come with the
+			// command, or from the file when the manifest lists none
+			const showContent = Boolean(
+				options.detailed || options.full || options.previewLines,
+			);
+			let content = showContent
+				? await getCommandContent(enhancedCommand, language, serviceOptions)
+				: undefined;
+			let examples: readonly CommandExample[] = parseManifestExamples(
+				enhancedCommand.examples,
+				enhancedCommand.name,
+			).examples;
+			if (examples.length === 0 && content !== undefined) {
+				examples = await readExamples(content, enhancedCommand.name);
+			}
+
+			const limits = options.full
+				? undefined
+				: await getPreviewLimits(options.previewLines);
+			if (content !== undefined && limits) {
+				content = previewContent(content, limits);
 			}
 
 			// Format and display output using enhanced formatting
@@ -163,9 +332,23 @@ export const infoCommand = new Command("info")
 				enhancedCommand,
 				language,
 				content,
+				getTheme(),
+				examples,
 			);
 			console.log(output);
 		} catch (error) {
-			handleError(error, "Failed to get command info");
+			await handleError(
+				error,
+				"Failed to get command info",
+				error instanceof CommandNotFoundError
+					? await didYouMean(typedName, {
+							repository: true,
+							installed: true,
+							language: options.language,
+						})
+					: [],
+			);
 		}
 	});
+
+inGroup(infoCommand, "Discover");
@@ -0,0 +1,437 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import {
+	type Config,
+	InvalidConfigError,
+} from "../../interfaces/IConfigService.js";
+import type IFileService from "../../interfaces/IFileService.js";
+import { getServices } from "../../services/serviceFactory.js";
+import { checkConfigValue, SCP_LIKE_GIT_URL } from "../../utils/configKeys.js";
+import { normalizeLanguageCode } from "../../utils/naming.js";
+import {
+	getClient,
+	getProgressReporter,
+	handleError,
+	isPorcelain,
+	parseInstallLocation,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { exitCodeForError } from "../exitCodes.js";
+import { isQuiet, notice, success, warning } from "../output.js";
+
+type InstallTarget = "personal" | "project";
+
+/**
+ * Commands that set up claude-cmd themselves, so the welcome hint is noise
+ */
+const SETUP_COMMANDS = new Set(["init", "config", "completion", "language"]);
+
+/**
+ * Point first-time users at `claude-cmd init`
+ *
+ * Shown as a notice(), when neither a global config nor a cache exists,
+ * which is the state of a fresh installation. Running any command that
+ * caches, or init itself, makes it go away.
+ *
+ * @param fileService - File service used to look for earlier use
+ * @param commandName - Name of the top-level command about to run
+ * @param paths - Global config file and cache directory
+ */
+export async function showFirstUseMessage(
+	fileService: IFileService,
+	commandName: string,
+	paths: { userConfigPath: string; cacheDir: string },
+): Promise<void> {
+	if (
+		isPorcelain() ||
+		SETUP_COMMANDS.has(commandName) ||
+		(await fileService.exists(paths.userConfigPath)) ||
+		(await fileService.exists(paths.cacheDir))
+	) {
+		return;
+	}
+	notice(
+		"Welcome to claude-cmd! Run 'claude-cmd init' to choose your language, repository and install location.\n",
+	);
+}
+
+/**
+ * Check whether a repository URL points at a git repository
+ */
+function isGitURL(url: string): boolean {
+	return SCP_LIKE_GIT_URL.test(url) || url.endsWith(".git");
+}
+
+/**
+ * Ask for a language code until a valid one is given
+ */
+async function askLanguage(defaultValue: string): Promise<string> {
+	const { userInteractionService } = getServices();
+	for (;;) {
+		const answer = await userInteractionService.askText({
+			message: "Language for commands (e.g., en, fr, de)",
+			defaultValue,
+		});
+		const language = normalizeLanguageCode(answer);
+		if (language) {
+			return language;
+		}
+		console.log(`'${answer}' is not a language code; try again.`);
+	}
+}
+
+/**
+ * Ask for a repository URL until a valid one (or none) is given
+ *
+ * @returns The URL, or "" for the default repository
+ */
+async function askRepository(defaultValue: string): Promise<string> {
+	const { userInteractionService } = getServices();
+	for (;;) {
+		const answer = await userInteractionService.askText({
+			message: "Repository URL (empty for the default repository)",
+			defaultValue,
+		});
+		const problem = answer && checkConfigValue("repositoryURL", answer);
+		if (!problem) {
+			return answer;
+		}
+		console.log(`'${answer}' is not a repository URL; try again.`);
+	}
+}
+
+/**
+ * Claude Code's per-user settings file, kept out of version control
+ */
+const LOCAL_SETTINGS = ".claude/settings.local.json";
+
+/**
+ * Take the repository URL from --repository or ask for it
+ *
+ * @throws InvalidConfigError if --repository is not a repository URL
+ */
+async function chooseRepository(
+	option: string | undefined,
+	defaultValue: string,
+): Promise<string> {
+	const repositoryURL = option ?? (await askRepository(defaultValue));
+	const problem =
+		repositoryURL && checkConfigValue("repositoryURL", repositoryURL);
+	if (problem) {
+		throw new InvalidConfigError(
+			`Invalid repository URL '${repositoryURL}' (${problem})`,
+		);
+	}
+	return repositoryURL;
+}
+
+/**
+ * Replace the repository settings of a config, git URLs selecting git
+ *
+ * @param config - Config to update
+ * @param repositoryURL - New URL, or "" for the default repository
+ */
+function withRepository(config: Config, repositoryURL: string): Config {
+	const { repositoryURL: _url, repositoryType: _type, ...rest } = config;
+	if (!repositoryURL) {
+		return rest;
+	}
+	return isGitURL(repositoryURL)
+		? { ...rest, repositoryURL, repositoryType: "git" }
+		: { ...rest, repositoryURL };
+}
+
+/**
+ * Add a line to the project's .gitignore unless it is already there
+ *
+ * @param gitignore - Path of the .gitignore file
+ * @returns Whether the file was changed
+ */
+async function ensureGitignoreEntry(
+	fileService: IFileService,
+	gitignore: string,
+	entry: string,
+): Promise<boolean> {
+	const content = (await fileService.exists(gitignore))
+		? await fileService.readFile(gitignore)
+		: "";
+	if (content.split(/\r?\n/).some((line) => line.trim() === entry)) {
+		return false;
+	}
+	const separator = content && !content.endsWith("\n") ? "\n" : "";
+	await fileService.writeFile(gitignore, `${content}${separator}${entry}\n`);
+	return true;
+}
+
+export const initCommand = new Command("init")
+	.description(
+		"Set up claude-cmd: choose a language, repository and default install location, then download the command list. With --project, set up the current project instead.",
+	)
+	.option("-y, --yes", "Accept the suggested answers without asking")
+	.option("--repository <url>", "Repository URL to use (skips the question)")
+	.option(
+		"-t, --target <target>",
+		"Default install target: 'personal' or 'project' (skips the question)",
+		parseInstallLocation,
+	)
+	.option(
+		"--project",
+		"Scaffold .claude/ in the project (the nearest directory with .claude, else the current one) and write the project config",
+	)
+	.option(
+		"--command <name>",
+		"Command the project uses, installed into the project (repeatable, with --project)",
+		(value: string, previous: string[]) => [...previous, value],
+		[] as string[],
+	)
+	.option(
+		"--gitignore",
+		`Add ${LOCAL_SETTINGS} to .gitignore (with --project; asks otherwise)`,
+	)
+	.option("--no-gitignore", "Leave .gitignore alone (with --project)")
+	.option("--no-install", "Do not install the project's commands (--project)")
+	.option("--no-cache", "Do not download the command list afterwards")
+	.action(async (options, command: Command) => {
+		try {
+			getServices().userInteractionService.setYesMode(Boolean(options.yes));
+			if (options.project) {
+				await initProject(options, command);
+			} else {
+				await initGlobal(options, command);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to set up claude-cmd");
+		}
+	});
+
+/**
+ * Write the global config from the answers
+ */
+async function initGlobal(
+	options: {
+		yes?: boolean;
+		repository?: string;
+		target?: InstallTarget;
+		cache: boolean;
+	},
+	command: Command,
+): Promise<void> {
+	const { configManager, userConfigService, userInteractionService } =
+		getServices();
+
+	const configPath = userConfigService.getConfigPath();
+	const current = (await userConfigService.getConfig()) ?? {};
+	console.log(
+		Object.keys(current).length > 0
+			? `Updating ${configPath}`
+			: `Creating ${configPath}`,
+	);
+
+	// The global --language flag answers the language question
+	const language =
+		command.optsWithGlobals().language ??
+		(await askLanguage(
+			current.preferredLanguage ?? (await configManager.getEffectiveLanguage()),
+		));
+
+	const repositoryURL = await chooseRepository(
+		options.repository,
+		current.repositoryURL ?? "",
+	);
+
+	const suggestedTarget: InstallTarget = current.defaultTarget ?? "personal";
+	const target: InstallTarget =
+		options.target ??
+		(options.yes
+			? suggestedTarget
+			: await userInteractionService.chooseOption<InstallTarget>({
+					message: `Install commands for you alone or for this project by default? (currently ${suggestedTarget})`,
+					choices: [
+						{ value: "personal", key: "u", label: "personal (~/.claude)" },
+						{ value: "project", key: "p", label: "project (./.claude)" },
+					],
+				})) ??
+		suggestedTarget;
+
+	await userConfigService.setConfig(
+		withRepository(
+			{ ...current, preferredLanguage: language, defaultTarget: target },
+			repositoryURL,
+		),
+	);
+
+	if (!isQuiet()) {
+		console.log();
+		success(`Saved ${configPath}`);
+		console.log(`  Language:       ${language}`);
+		console.log(`  Repository:     ${repositoryURL || "default"}`);
+		console.log(`  Install target: ${target}`);
+	}
+
+	if (options.cache) {
+		await warmCache(language, command);
+	}
+
+	if (!isPorcelain() && !isQuiet()) {
+		console.log(
+			"\nNext: 'claude-cmd list' to browse commands, 'claude-cmd add <name>' to install one.",
+		);
+	}
+}
+
+/**
+ * Scaffold .claude/ in the project root and write the project config
+ *
+ * The project root is the nearest parent directory that already has .claude
+ * (or the current directory), so running it from a subdirectory updates the
+ * existing project instead of starting a nested one.
+ *
+ * Running it again keeps existing answers as suggestions and installs
+ * listed commands that are missing, so it also brings a fresh clone up to
+ * date.
+ */
+async function initProject(
+	options: {
+		repository?: string;
+		command: string[];
+		gitignore?: boolean;
+		install: boolean;
+		cache: boolean;
+	},
+	command: Command,
+): Promise<void> {
+	const {
+		configManager,
+		fileService,
+		projectConfigService,
+		projectRoot,
+		userInteractionService,
+	} = getServices();
+
+	const configPath = projectConfigService.getConfigPath();
+	const commandsDir = path.join(path.dirname(configPath), "commands");
+	if (!(await fileService.exists(commandsDir))) {
+		await fileService.mkdir(commandsDir);
+		success(`Created ${commandsDir}`);
+	}
+
+	const current = (await projectConfigService.getConfig()) ?? {};
+	const language =
+		command.optsWithGlobals().language ??
+		(await askLanguage(
+			current.preferredLanguage ?? (await configManager.getEffectiveLanguage()),
+		));
+	const repositoryURL = await chooseRepository(
+		options.repository,
+		current.repositoryURL ?? "",
+	);
+
+	let commands = options.command;
+	if (commands.length === 0) {
+		const answer = await userInteractionService.askText({
+			message: "Commands this project uses (comma-separated, optional)",
+			defaultValue: (current.commands ?? []).join(", "),
+		});
+		commands = answer
+			.split(",")
+			.map((name) => name.trim())
+			.filter(Boolean);
+	}
+	const commandsProblem = checkConfigValue("commands", commands);
+	if (commandsProblem) {
+		throw new InvalidConfigError(`Invalid commands list (${commandsProblem})`);
+	}
+
+	await projectConfigService.setConfig(
+		withRepository(
+			{ ...current, preferredLanguage: language, commands },
+			repositoryURL,
+		),
+	);
+	if (!isQuiet()) {
+		success(`Saved ${configPath}`);
+		console.log(`  Language:   ${language}`);
+		console.log(`  Repository: ${repositoryURL || "inherited"}`);
+		console.log(`  Commands:   ${commands.join(", ") || "none"}`);
+	}
+
+	const addGitignoreEntry =
+		options.gitignore ??
+		(await userInteractionService.confirmAction({
+			message: `Add ${LOCAL_SETTINGS} to .gitignore for personal overrides?`,
+			defaultResponse: true,
+			skipWithYes: true,
+		}));
+	if (
+		addGitignoreEntry &&
+		(await ensureGitignoreEntry(
+			fileService,
+			path.join(projectRoot, ".gitignore"),
+			LOCAL_SETTINGS,
+		))
+	) {
+		success(`Added ${LOCAL_SETTINGS} to .gitignore`);
+	}
+
+	if (options.cache) {
+		await warmCache(language, command);
+	}
+	if (options.install && commands.length > 0) {
+		await installProjectCommands(commands, language);
+	}
+}
+
+/**
+ * Install the project's commands that are not installed yet
+ *
+ * Each failure is reported and the rest are still installed; the exit code
+ * reflects the last failure.
+ */
+async function installProjectCommands(
+	commands: readonly string[],
+	language: string,
+): Promise<void> {
+	const { installationService } = getServices();
+	const installOptions = await getClient().resolveInstallOptions({
+		target: "project",
+		language,
+	});
+	for (const name of commands) {
+		if (await installationService.findInstalledCommand(name, "project")) {
+			continue;
+		}
+		try {
+			await installationService.installCommand(name, installOptions);
+			success(`Installed ${name}`);
+		} catch (error) {
+			warning(
+				`could not install ${name}: ${error instanceof Error ? error.message : error}`,
+			);
+			process.exitCode = exitCodeForError(error);
+		}
+	}
+}
+
+/**
+ * Download the command list so the first list or search is instant
+ *
+ * A failure only warns: the configuration is saved and the cache can be
+ * filled later.
+ */
+async function warmCache(language: string, command: Command): Promise<void> {
+	const { commandCacheService } = getServices();
+	const progress = getProgressReporter(command);
+	progress.start("Downloading command list");
+	try {
+		const result = await commandCacheService.updateCache({ language });
+		progress.finish();
+		success(`Cached ${result.commandCount} commands (${language})`);
+	} catch (error) {
+		progress.finish();
+		warning(
+			`could not download the command list (${error instanceof Error ? error.message : error}). Run 'claude-cmd cache update' later.`,
+		);
+	}
+}
+
+inGroup(initCommand, "Configure");
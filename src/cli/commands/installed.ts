@@ -4,16 +4,35 @@ import type {
 	InstallationInfo,
 	InstallationSummary,
 } from "../../types/Installation.js";
-import { detectLanguage, handleError } from "../cliUtils.js";
+import type { UntrackedReason } from "../../types/Status.js";
+import { stringComparator } from "../../utils/ordering.js";
+import { detectLanguage, handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
 
 /**
  * Format installed commands with enhanced display including location indicators
  * Provides detailed formatting with location information and grouping
+ *
+ * Commands listed in `untracked` are marked as local only or removed upstream;
+ * pinned commands show the version they are pinned to.
  */
 export function formatInstalledCommandsEnhanced(
 	installationInfos: readonly InstallationInfo[],
 	language: string,
+	untracked: ReadonlyMap<string, UntrackedReason> = new Map(),
 ): string {
+	const label = (info: InstallationInfo): string => {
+		const name =
+			info.pinned && info.version
+				? `${info.name} (pinned to ${info.version})`
+				: info.name;
+		const reason = untracked.get(info.name);
+		if (!reason) {
+			return name;
+		}
+		return `${name} (${reason === "removed-upstream" ? "removed upstream" : "local only"})`;
+	};
+
 	if (installationInfos.length === 0) {
 		return "No commands are currently installed.";
 	}
@@ -39,15 +58,18 @@ export function formatInstalledCommandsEnhanced(
 	if (personalCommands.length > 0) {
 		output += "Personal Commands:\n";
 		for (const info of personalCommands) {
-			output += `${info.name}\n`;
+			output += `${label(info)}\n`;
 		}
 		output += "\n";
 	}
 
-	if (projectCommands.length > 0) {
-		output += "Project Commands:\n";
-		for (const info of projectCommands) {
-			output += `${info.name}\n`;
+	// One group per project root; nested projects add their enclosing ones
+	const projects = Map.groupBy(projectCommands, (info) => info.projectRoot);
+	for (const [root, commands] of projects) {
+		// Named after its root, which may be a parent of the working directory
+		output += root ? `Project Commands (${root}):\n` : "Project Commands:\n";
+		for (const info of commands) {
+			output += `${label(info)}\n`;
 		}
 		output += "\n";
 	}
@@ -112,14 +134,17 @@ export function formatInstalledCommandsTree(
 	}
 
 	let output = `${installationInfos.length} installed Claude Code Commands (${language}) - Tree View:\n\n`;
+	const compare = stringComparator(language);
 
 	// Display flat commands with tree characters for consistency
-	for (const command of flatCommands) {
+	for (const command of [...flatCommands].sort(compare)) {
 		output += `├ ${command}\n`;
 	}
 
-	// Display namespaced commands with tree structure
-	for (const [namespace, commands] of tree.entries()) {
+	// Display namespaced commands with tree structure, namespaces in canonical order
+	const namespaces = [...tree.keys()].sort(compare);
+	for (const namespace of namespaces) {
+		const commands = (tree.get(namespace) ?? []).sort(compare);
 		output += `├ ${namespace}:\n`;
 		for (let i = 0; i < commands.length; i++) {
 			const isLast = i === commands.length - 1;
@@ -131,6 +156,20 @@ export function formatInstalledCommandsTree(
 	return output.trim();
 }
 
+/**
+ * Format installed commands as porcelain records: name<TAB>location<TAB>path
+ *
+ * @param installationInfos - Installed commands to format
+ * @returns One line per command, empty when there are none
+ */
+export function formatInstalledCommandsPorcelain(
+	installationInfos: readonly InstallationInfo[],
+): string {
+	return installationInfos
+		.map((info) => [info.name, info.location, info.filePath].join("\t"))
+		.join("\n");
+}
+
 export const installedCommand = new Command("installed")
 	.description(
 		"List displays all installed Claude Code slash commands.\nShows commands that are available in your local Claude Code directories.",
@@ -145,10 +184,10 @@ export const installedCommand = new Command("installed")
 	.action(async (options) => {
 		try {
 			// Get singleton service instances from factory
-			const { languageDetector, installationService } = getServices();
+			const { installationService, statusService } = getServices();
 
 			// Determine language used
-			const language = await detectLanguage(options.language, languageDetector);
+			const language = await detectLanguage(options.language);
 
 			// Check which display mode to use
 			if (options.summary) {
@@ -159,7 +198,14 @@ export const installedCommand = new Command("installed")
 			} else {
 				// For tree and enhanced modes, fetch installation info once
 				const installationInfos =
-					await installationService.getAllInstallationInfo();
+					await installationService.getAllInstallationInfo(language);
+
+				if (isPorcelain()) {
+					if (installationInfos.length > 0) {
+						console.log(formatInstalledCommandsPorcelain(installationInfos));
+					}
+					return;
+				}
 
 				if (options.tree) {
 					// Tree mode: show hierarchical display for namespaced commands
@@ -169,15 +215,22 @@ export const installedCommand = new Command("installed")
 					);
 					console.log(output);
 				} else {
-					// Default enhanced mode: show location information by default
+					// Default enhanced mode: show location information by default,
+					// flagging commands the repository does not list
+					const untracked = await statusService
+						.findUntrackedCommands()
+						.catch(() => null);
 					const output = formatInstalledCommandsEnhanced(
 						installationInfos,
 						language,
+						new Map(untracked?.map((cmd) => [cmd.name, cmd.reason] as const)),
 					);
 					console.log(output);
 				}
 			}
 		} catch (error) {
-			handleError(error, "Failed to list installed commands");
+			await handleError(error, "Failed to list installed commands");
 		}
 	});
+
+inGroup(installedCommand, "Install");
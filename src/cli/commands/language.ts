@@ -1,5 +1,8 @@
 import { Command } from "commander";
+import type { LanguageSource } from "../../interfaces/IConfigService.js";
 import { getServices } from "../../services/serviceFactory.js";
+import { inGroup } from "../commandGroups.js";
+import { exitCodeForError } from "../exitCodes.js";
 
 export const languageCommand = new Command("language").description(
 	"Manage language settings for claude-cmd.",
@@ -46,7 +49,7 @@ languageCommand
 				"Error listing languages:",
 				error instanceof Error ? error.message : error,
 			);
-			process.exit(1);
+			process.exit(exitCodeForError(error));
 		}
 	});
 
@@ -69,6 +72,62 @@ languageCommand
 				"Error setting language:",
 				error instanceof Error ? error.message : error,
 			);
-			process.exit(1);
+			process.exit(exitCodeForError(error));
 		}
 	});
+
+/**
+ * Description of each language source, in precedence order
+ */
+const LANGUAGE_SOURCE_LABELS: Record<LanguageSource, string> = {
+	flag: "--language flag",
+	env: "CLAUDE_CMD_LANG",
+	project: "Project config",
+	user: "Global config",
+	locale: "System locale",
+	default: "Default",
+};
+
+languageCommand
+	.command("status")
+	.description("Show which source determines the current language and why")
+	.action(async () => {
+		try {
+			const { configManager } = getServices();
+			const resolution = await configManager.getLanguageResolution();
+
+			console.log(
+				`Current language: ${resolution.language} (from ${LANGUAGE_SOURCE_LABELS[resolution.source]})`,
+			);
+			console.log("\nSources, highest precedence first:");
+
+			const rows: Array<[LanguageSource, string]> = [
+				...(Object.entries(resolution.sources) as Array<
+					[LanguageSource, string]
+				>),
+				["default", "en"],
+			];
+			const width = Math.max(
+				...rows.map(([source]) => LANGUAGE_SOURCE_LABELS[source].length),
+			);
+			for (const [source, value] of rows) {
+				const marker = source === resolution.source ? "→" : " ";
+				const label = LANGUAGE_SOURCE_LABELS[source].padEnd(width);
+				console.log(`  ${marker} ${label}  ${value || "(not set)"}`);
+			}
+
+			if (resolution.source === "locale") {
+				console.log(
+					"\nSet a language with 'claude-cmd language set <code>' to stop following the system locale.",
+				);
+			}
+		} catch (error) {
+			console.error(
+				"Error showing language status:",
+				error instanceof Error ? error.message : error,
+			);
+			process.exit(exitCodeForError(error));
+		}
+	});
+
+inGroup(languageCommand, "Configure");
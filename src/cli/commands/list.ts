@@ -1,29 +1,394 @@
-import { Command } from "commander";
+import { Command, InvalidArgumentError, Option } from "commander";
 import { getServices } from "../../services/serviceFactory.js";
 import type { Command as CommandType } from "../../types/Command.js";
-import { detectLanguage, handleError } from "../cliUtils.js";
+import type { InstallationInfo } from "../../types/Installation.js";
+import { isDeprecated } from "../../utils/commandDeprecation.js";
+import { truncateText } from "../../utils/format.js";
+import { compareStrings } from "../../utils/ordering.js";
+import {
+	PLAIN_THEME,
+	styleCommandName,
+	type Theme,
+} from "../../utils/style.js";
+import {
+	checkTemplateFields,
+	renderTemplate,
+	type TemplateData,
+} from "../../utils/template.js";
+import {
+	detectLanguage,
+	getClient,
+	getOutputTemplate,
+	handleError,
+	isPorcelain,
+	parsePositiveInteger,
+	rendersTemplates,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { getTheme } from "../output.js";
+import { formatCatalogHeader } from "./repo.js";
+
+/**
+ * Orders the list command can sort by
+ */
+export type ListSort = "name" | "updated" | "category";
+
+const LIST_SORTS: readonly ListSort[] = ["name", "updated", "category"];
+
+/**
+ * Whether a repository command is installed locally
+ *
+ * "outdated" means the repository has changed the command since it was
+ * installed.
+ */
+export type InstallState = "installed" | "outdated" | "available";
+
+/**
+ * Markers shown before each command in the terminal listing
+ */
+const INSTALL_MARKERS: Readonly<Record<InstallState, string>> = {
+	installed: "✓",
+	outdated: "↑",
+	available: " ",
+};
+
+/**
+ * Style of the markers that are not blank
+ */
+const INSTALL_MARKER_STYLES: Readonly<
+	Partial<Record<InstallState, "success" | "warning">>
+> = {
+	installed: "success",
+	outdated: "warning",
+};
+
+/**
+ * Page size used when --page is given without --limit
+ */
+const DEFAULT_PAGE_SIZE = 20;
+
+/**
+ * Widest name column chosen automatically; longer names are cut
+ */
+const MAX_AUTO_NAME_WIDTH = 32;
+
+/**
+ * Column layout of the terminal listing
+ */
+export interface ListLayout {
+	/** Width of the name column (default: longest name, up to 32) */
+	readonly nameWidth?: number;
+	/** Total line width descriptions are cut to (default: no limit) */
+	readonly width?: number;
+}
+
+/**
+ * One page of a listing
+ */
+export interface ListPage<T> {
+	/** Items on the page */
+	readonly items: readonly T[];
+	/** Page number, from 1 */
+	readonly page: number;
+	/** Number of pages (at least 1) */
+	readonly pageCount: number;
+	/** Position of the first item on the page in the whole listing, from 0 */
+	readonly offset: number;
+}
+
+/**
+ * Parse a --sort option value
+ */
+export function parseListSort(value: string): ListSort {
+	if (!LIST_SORTS.includes(value as ListSort)) {
+		throw new InvalidArgumentError(`Must be one of: ${LIST_SORTS.join(", ")}.`);
+	}
+	return value as ListSort;
+}
+
+/**
+ * Order commands for the listing
+ *
+ * "updated" puts the most recently changed commands first and undated ones
+ * last; "category" groups commands by category, uncategorized ones last.
+ * Ties keep name order.
+ *
+ * @param commands - Commands in name order
+ * @param sort - Requested order
+ * @returns A sorted copy
+ */
+export function orderCommands(
+	commands: readonly CommandType[],
+	sort: ListSort,
+): CommandType[] {
+	const sorted = [...commands];
+	if (sort === "updated") {
+		// ISO 8601 timestamps sort chronologically as strings
+		sorted.sort((a, b) =>
+			compareMissingLast(a.updated, b.updated, (x, y) => compareStrings(y, x)),
+		);
+	} else if (sort === "category") {
+		sorted.sort((a, b) =>
+			compareMissingLast(a.category, b.category, compareStrings),
+		);
+	}
+	return sorted;
+}
+
+/**
+ * Compare optional values, placing missing ones last
+ */
+function compareMissingLast(
+	a: string | undefined,
+	b: string | undefined,
+	compare: (a: string, b: string) => number,
+): number {
+	if (a === undefined || b === undefined) {
+		return (a === undefined ? 1 : 0) - (b === undefined ? 1 : 0);
+	}
+	return compare(a, b);
+}
+
+/**
+ * Cross-reference repository commands with the installed ones
+ *
+ * A command installed in several places is outdated when any of its copies
+ * is. Copies whose installed hash is unknown (local or hand-copied files)
+ * count as installed.
+ *
+ * @param commands - Repository commands
+ * @param installed - Installed commands (see getAllInstallationInfo())
+ * @returns The state of every command, by name
+ */
+export function getInstallStates(
+	commands: readonly CommandType[],
+	installed: readonly InstallationInfo[],
+): Map<string, InstallState> {
+	const states = new Map<string, InstallState>();
+	for (const command of commands) {
+		const copies = installed.filter((info) => info.name === command.name);
+		if (copies.length === 0) {
+			states.set(command.name, "available");
+			continue;
+		}
+		const outdated = copies.some(
+			(info) =>
+				info.sha256 !== undefined &&
+				command.sha256 !== undefined &&
+				info.sha256 !== command.sha256,
+		);
+		states.set(command.name, outdated ? "outdated" : "installed");
+	}
+	return states;
+}
+
+/**
+ * Cut a listing into pages
+ *
+ * @param items - Whole listing
+ * @param page - Page to return, from 1 (clamped to the last page)
+ * @param limit - Items per page; undefined returns everything
+ * @returns The requested page
+ */
+export function paginate<T>(
+	items: readonly T[],
+	page: number,
+	limit?: number,
+): ListPage<T> {
+	if (limit === undefined) {
+		return { items, page: 1, pageCount: 1, offset: 0 };
+	}
+	const pageCount = Math.max(Math.ceil(items.length / limit), 1);
+	const current = Math.min(page, pageCount);
+	const offset = (current - 1) * limit;
+	return {
+		items: items.slice(offset, offset + limit),
+		page: current,
+		pageCount,
+		offset,
+	};
+}
+
+/**
+ * Format one command as a row of the name and description columns
+ *
+ * @param command - Command to format
+ * @param nameWidth - Width of the name column; longer names are cut
+ * @param width - Line width the description is cut to, if any
+ * @param state - Install state to mark the row with, if known
+ * @param theme - Styles for the marker, namespace and deprecation flag
+ * @returns The row
+ */
+export function formatCommandRow(
+	command: CommandType,
+	nameWidth: number,
+	width?: number,
+	state?: InstallState,
+	theme: Theme = PLAIN_THEME,
+): string {
+	const flag = isDeprecated(command) ? " [deprecated]" : "";
+	const marker = state ? `${INSTALL_MARKERS[state]} ` : "";
+	const name = truncateText(command.name, nameWidth).padEnd(nameWidth);
+	const description = `${command.description.replace(/\s+/g, " ")}${flag}`;
+	// Widths are measured on plain text; styles are applied afterwards
+	const fitted =
+		width === undefined
+			? description
+			: truncateText(
+					description,
+					Math.max(width - marker.length - nameWidth - 2, 1),
+				);
+	const markerStyle = state ? INSTALL_MARKER_STYLES[state] : undefined;
+	const styledMarker =
+		state && markerStyle
+			? `${theme[markerStyle](INSTALL_MARKERS[state])} `
+			: marker;
+	const styledDescription =
+		flag && fitted.endsWith(flag)
+			? `${fitted.slice(0, -flag.length)}${theme.warning(flag)}`
+			: fitted;
+	return `${styledMarker}${styleCommandName(name, theme)}  ${styledDescription}`.trimEnd();
+}
+
+/**
+ * Filter of the listing by install state
+ */
+export type InstallFilter = "installed" | "not-installed";
+
+/**
+ * Keep the commands matching an install filter
+ *
+ * Outdated commands are installed.
+ *
+ * @param commands - Commands to filter
+ * @param states - Install state of every command (see getInstallStates())
+ * @param filter - Filter to apply; undefined keeps everything
+ */
+export function filterByInstallState(
+	commands: readonly CommandType[],
+	states: ReadonlyMap<string, InstallState>,
+	filter?: InstallFilter,
+): CommandType[] {
+	if (filter === undefined) {
+		return [...commands];
+	}
+	return commands.filter(
+		(command) =>
+			(states.get(command.name) === "available") ===
+			(filter === "not-installed"),
+	);
+}
 
 /**
  * Format commands for terminal output
  * Handles presentation layer concerns for the list command
  */
 function formatCommandList(
-	commands: readonly CommandType[],
+	listing: ListPage<CommandType>,
+	total: number,
 	language: string,
+	layout: ListLayout,
+	states: ReadonlyMap<string, InstallState>,
+	tag?: string,
+	filter?: InstallFilter,
+	theme: Theme = PLAIN_THEME,
 ): string {
-	if (commands.length === 0) {
-		return "No commands available in the repository.";
+	const tagged = tag ? ` tagged '${tag}'` : "";
+	if (total === 0) {
+		if (filter === "installed") {
+			return `No installed commands${tagged} in the repository.`;
+		}
+		if (filter === "not-installed") {
+			return `Every command${tagged} in the repository is installed.`;
+		}
+		return tag
+			? `No commands${tagged} in the repository.`
+			: "No commands available in the repository.";
 	}
 
-	let output = `${commands.length} available Claude Code Commands (${language}):\n\n`;
+	const kind =
+		filter === "installed"
+			? "installed"
+			: filter === "not-installed"
+				? "not installed"
+				: "available";
+	let output = `${total} ${kind} Claude Code Commands${tagged} (${language}):\n\n`;
 
-	for (const command of commands) {
-		output += `${command.name}\t\t${command.description}\n`;
+	const nameWidth =
+		layout.nameWidth ??
+		Math.min(
+			Math.max(...listing.items.map((command) => command.name.length)),
+			MAX_AUTO_NAME_WIDTH,
+		);
+	for (const command of listing.items) {
+		output += `${formatCommandRow(
+			command,
+			nameWidth,
+			layout.width,
+			states.get(command.name) ?? "available",
+			theme,
+		)}\n`;
+	}
+	output += `\n${theme.success(INSTALL_MARKERS.installed)} installed  ${theme.warning(INSTALL_MARKERS.outdated)} update available\n`;
+
+	if (listing.pageCount > 1) {
+		const first = listing.offset + 1;
+		const last = listing.offset + listing.items.length;
+		output += `\nPage ${listing.page} of ${listing.pageCount} (commands ${first}-${last} of ${total})`;
+		if (listing.page < listing.pageCount) {
+			output += `; use --page ${listing.page + 1} for more`;
+		}
 	}
 
 	return output.trim();
 }
 
+/**
+ * Format commands as porcelain records: name<TAB>description, followed by
+ * <TAB>state when install states are given
+ *
+ * @param commands - Commands to format
+ * @param states - Install state of every command (see getInstallStates())
+ * @returns One line per command, empty when there are none
+ */
+export function formatCommandsPorcelain(
+	commands: readonly CommandType[],
+	states?: ReadonlyMap<string, InstallState>,
+): string {
+	return commands
+		.map((command) => {
+			const fields = [command.name, command.description.replace(/\s+/g, " ")];
+			if (states) {
+				fields.push(states.get(command.name) ?? "available");
+			}
+			return fields.join("\t");
+		})
+		.join("\n");
+}
+
+/**
+ * Fields of a command for --format templates
+ *
+ * @param command - Command to describe
+ * @param state - Its install state
+ * @returns .Name, .Description, .State, .Category, .Tags, .Updated and
+ *   .Deprecated
+ */
+export function commandTemplateData(
+	command: CommandType,
+	state: InstallState,
+): TemplateData {
+	return {
+		Name: command.name,
+		Description: command.description.replace(/\s+/g, " "),
+		State: state,
+		Category: command.category,
+		Tags: command.tags ?? [],
+		Updated: command.updated,
+		Deprecated: isDeprecated(command),
+	};
+}
+
 export const listCommand = new Command("list")
 	.description(
 		"List displays all available Claude Code slash commands from the repository.\nCommands include descriptions to help you find what you need.",
@@ -32,28 +397,139 @@ export const listCommand = new Command("list")
 		"-l, --language <lang>",
 		"Language for commands (default: auto-detect)",
 	)
+	.option("-t, --tag <tag>", "Only list commands with this tag or category")
 	.option("-f, --force", "Force refresh cache even if current")
-	.action(async (options) => {
+	.option(
+		"-s, --sort <order>",
+		"Sort by 'name', 'updated' (newest first) or 'category'",
+		parseListSort,
+		"name",
+	)
+	.option(
+		"-n, --limit <n>",
+		"Show at most n commands per page",
+		parsePositiveInteger,
+	)
+	.option(
+		"-p, --page <n>",
+		`Page to show (page size: --limit, default ${DEFAULT_PAGE_SIZE})`,
+		parsePositiveInteger,
+	)
+	.option(
+		"--name-width <n>",
+		"Width of the name column (default: longest name)",
+		parsePositiveInteger,
+	)
+	.option(
+		"--width <n>",
+		"Line width to fit descriptions to (default: terminal width)",
+		parsePositiveInteger,
+	)
+	.option("--no-truncate", "Show descriptions in full")
+	.addOption(
+		new Option(
+			"--installed-only",
+			"Only list commands that are installed",
+		).conflicts("notInstalled"),
+	)
+	.addOption(
+		new Option("--not-installed", "Only list commands that are not installed"),
+	)
+	.action(async (options, command: Command) => {
 		try {
+			// Rejected before anything is fetched, even for empty listings
+			const template = getOutputTemplate(command);
+			if (template) {
+				checkTemplateFields(
+					template,
+					commandTemplateData(
+						{ name: "", description: "", file: "", "allowed-tools": [] },
+						"available",
+					),
+				);
+			}
+
 			// Get singleton service instances from factory
-			const { commandQueryService, languageDetector } = getServices();
+			const { installationService, repository } = getServices();
 
 			// Prepare options for CommandService
 			const serviceOptions = {
 				language: options.language,
 				forceRefresh: options.force,
+				tag: options.tag,
 			};
 
-			// Get commands from service
-			const commands = await commandQueryService.listCommands(serviceOptions);
+			// Get commands from service, marked with what is installed
+			const available = await getClient().listCommands(serviceOptions);
+			const states = getInstallStates(
+				available,
+				await installationService.getAllInstallationInfo(),
+			);
+			const filter: InstallFilter | undefined = options.installedOnly
+				? "installed"
+				: options.notInstalled
+					? "not-installed"
+					: undefined;
+			const commands = orderCommands(
+				filterByInstallState(available, states, filter),
+				options.sort,
+			);
+			const listing = paginate(
+				commands,
+				options.page ?? 1,
+				options.limit ??
+					(options.page !== undefined ? DEFAULT_PAGE_SIZE : undefined),
+			);
+
+			if (template) {
+				for (const item of listing.items) {
+					console.log(
+						renderTemplate(
+							template,
+							commandTemplateData(item, states.get(item.name) ?? "available"),
+						),
+					);
+				}
+				return;
+			}
+
+			if (isPorcelain()) {
+				if (listing.items.length > 0) {
+					console.log(formatCommandsPorcelain(listing.items, states));
+				}
+				return;
+			}
 
 			// Determine language used
-			const language = await detectLanguage(options.language, languageDetector);
+			const language = await detectLanguage(options.language);
+
+			// Show which catalog is being browsed when it describes itself
+			const about = await repository.getAbout(language).catch(() => null);
+
+			// Descriptions are fitted to the terminal unless piped or disabled
+			const layout: ListLayout = {
+				nameWidth: options.nameWidth,
+				width: options.truncate
+					? (options.width ?? process.stdout.columns)
+					: undefined,
+			};
 
 			// Format and display output
-			const output = formatCommandList(commands, language);
-			console.log(output);
+			const output = formatCommandList(
+				listing,
+				commands.length,
+				language,
+				layout,
+				states,
+				options.tag,
+				filter,
+				getTheme(),
+			);
+			console.log(`${formatCatalogHeader(about)}${output}`);
 		} catch (error) {
-			handleError(error, "Failed to list available commands");
+			await handleError(error, "Failed to list available commands");
 		}
 	});
+
+rendersTemplates(listCommand);
+inGroup(listCommand, "Discover");
@@ -0,0 +1,98 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import {
+	type LintIssue,
+	ManifestLinter,
+	type ManifestLintResult,
+} from "../../services/ManifestLinter.js";
+import { getServices } from "../../services/serviceFactory.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+
+/**
+ * Format lint findings for the terminal, one per line, with a summary
+ *
+ * @param result - Lint result
+ * @param manifestPath - Manifest that was linted, as given
+ */
+export function formatLintResult(
+	result: ManifestLintResult,
+	manifestPath: string,
+): string {
+	const errors = result.issues.filter((issue) => issue.severity === "error");
+	const warnings = result.issues.length - errors.length;
+	if (result.issues.length === 0) {
+		return `✓ ${manifestPath}: ${result.commandCount} commands, no problems`;
+	}
+
+	const lines = result.issues.map(
+		(issue) =>
+			`${issue.severity.padEnd(7)}  ${issue.command ? `${issue.command}: ` : ""}${issue.message} (${issue.rule})`,
+	);
+	lines.push(
+		"",
+		`${manifestPath}: ${errors.length} error(s), ${warnings} warning(s) in ${result.commandCount} commands`,
+	);
+	return lines.join("\n");
+}
+
+/**
+ * Format lint findings as porcelain records:
+ * severity<TAB>rule<TAB>command<TAB>message (command empty when not about one)
+ */
+export function formatLintPorcelain(issues: readonly LintIssue[]): string {
+	return issues
+		.map((issue) =>
+			[issue.severity, issue.rule, issue.command ?? "", issue.message].join(
+				"\t",
+			),
+		)
+		.join("\n");
+}
+
+const manifestLintCommand = new Command("lint")
+	.description(
+		"Check a repository manifest and its command files before publishing. Exits with 1 on errors.",
+	)
+	.argument("<manifest>", "Manifest file to check (e.g., index.json)")
+	.option(
+		"--files-dir <dir>",
+		"Directory command files are relative to (default: the manifest's directory)",
+	)
+	.option("--strict", "Fail on warnings too")
+	.action(async (manifestPath: string, options) => {
+		try {
+			const { fileService } = getServices();
+			const result = await new ManifestLinter(fileService).lint(
+				path.resolve(manifestPath),
+				options.filesDir ? path.resolve(options.filesDir) : undefined,
+			);
+
+			if (isPorcelain()) {
+				if (result.issues.length > 0) {
+					console.log(formatLintPorcelain(result.issues));
+				}
+			} else {
+				console.log(formatLintResult(result, manifestPath));
+			}
+
+			const failing = result.issues.filter(
+				(issue) => options.strict || issue.severity === "error",
+			);
+			if (failing.length > 0) {
+				process.exitCode = ExitCode.Failure;
+			}
+		} catch (error) {
+			await handleError(error, `Failed to lint ${manifestPath}`);
+		}
+	});
+
+/**
+ * Tools for people who publish command repositories
+ */
+export const manifestCommand = new Command("manifest")
+	.description("Tools for maintaining a command repository's manifest")
+	.addCommand(manifestLintCommand);
+
+inGroup(manifestCommand, "Advanced");
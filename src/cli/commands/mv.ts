@@ -0,0 +1,57 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { handleError, parseInstallLocation } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { isQuiet, success } from "../output.js";
+
+export const mvCommand = new Command("mv")
+	.description(
+		"Rename an installed command, or move it between namespaces or between personal and project directories.",
+	)
+	.argument("<command-name>", "Name of the installed command")
+	.argument("<new-name>", "New name (e.g., 'frontend:component')")
+	.option(
+		"--from <location>",
+		"Location to move from when installed in both: 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option(
+		"-t, --target <location>",
+		"Location to move to: 'personal' or 'project' (default: current location)",
+		parseInstallLocation,
+	)
+	.option("-f, --force", "Overwrite a command already installed as <new-name>")
+	.option(
+		"--rewrite-name",
+		"Also update the name field in the command's frontmatter",
+	)
+	.action(async (commandName, newName, options) => {
+		try {
+			const { configManager, installationService, operationHistory } =
+				getServices();
+			const config = await configManager.getEffectiveConfig();
+
+			await operationHistory.batch(`mv ${commandName} ${newName}`, async () => {
+				const result = await installationService.moveCommand(
+					commandName,
+					newName,
+					{
+						from: options.from,
+						target: options.target,
+						force: options.force,
+						rewriteName: options.rewriteName,
+						keepEmptyDirectories: config.cleanupEmptyDirectories === false,
+					},
+				);
+
+				success(`Moved ${commandName} to ${newName} (${result.location})`);
+				if (!isQuiet()) {
+					console.log(`  ${result.fromPath} -> ${result.toPath}`);
+				}
+			});
+		} catch (error) {
+			await handleError(error, `Failed to move command '${commandName}'`);
+		}
+	});
+
+inGroup(mvCommand, "Install");
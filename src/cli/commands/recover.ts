@@ -0,0 +1,89 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import type { PendingTransaction } from "../../services/TransactionJournal.js";
+import { formatDuration } from "../../utils/format.js";
+import { handleError } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Describe a pending transaction on one line
+ *
+ * @param transaction - Transaction to describe
+ * @param now - Current time for the relative start time
+ */
+export function formatPendingTransaction(
+	transaction: PendingTransaction,
+	now: number,
+): string {
+	const files = `${transaction.paths.length} file${transaction.paths.length === 1 ? "" : "s"}`;
+	return `${transaction.name} [${transaction.id}], ${files}, started ${formatDuration(now - transaction.startedAt)} ago`;
+}
+
+export const recoverCommand = new Command("recover")
+	.description(
+		"Recover from operations that were interrupted midway.\nEach interrupted operation can be rolled back to the state before it started\nor completed, so the commands directory is never left half-updated.",
+	)
+	.option("--rollback", "Roll back all interrupted operations")
+	.option("--complete", "Complete all interrupted operations")
+	.action(async (options) => {
+		try {
+			if (options.rollback && options.complete) {
+				throw new Error("Use either --rollback or --complete, not both");
+			}
+
+			const { transactionJournal, userInteractionService, clock } =
+				getServices();
+			const pending = await transactionJournal.listPending();
+
+			if (pending.length === 0) {
+				console.log("No interrupted operations to recover.");
+				return;
+			}
+
+			for (const transaction of pending) {
+				const description = formatPendingTransaction(transaction, clock.now());
+
+				let action: "rollback" | "complete" | null = null;
+				if (options.rollback) {
+					action = "rollback";
+				} else if (options.complete) {
+					action = "complete";
+				} else if (
+					await userInteractionService.confirmAction({
+						message: `Roll back ${description}?`,
+						defaultResponse: false,
+					})
+				) {
+					action = "rollback";
+				} else if (
+					await userInteractionService.confirmAction({
+						message: `Complete ${transaction.name} instead?`,
+						defaultResponse: false,
+					})
+				) {
+					action = "complete";
+				}
+
+				if (action === "rollback") {
+					await transactionJournal.rollback(transaction.id);
+					success(`Rolled back ${transaction.name}`);
+				} else if (action === "complete") {
+					await transactionJournal.complete(transaction.id);
+					success(`Completed ${transaction.name}`);
+				} else {
+					console.log(`Left pending: ${description}`);
+				}
+			}
+
+			if (!options.rollback && !options.complete) {
+				console.log(
+					"\nRun 'claude-cmd recover --rollback' or 'claude-cmd recover --complete' to resolve remaining operations.",
+				);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to recover interrupted operations");
+		}
+	});
+
+inGroup(recoverCommand, "Maintain");
@@ -1,32 +1,294 @@
 import { Command } from "commander";
 import { getServices } from "../../services/serviceFactory.js";
-import { handleError } from "../cliUtils.js";
+import type { InstallationInfo } from "../../types/Installation.js";
+import { type AliasTarget, formatAliasTarget } from "../../utils/aliases.js";
+import {
+	isCommandPattern,
+	isInNamespace,
+	matchesCommandPattern,
+} from "../../utils/namespace.js";
+import {
+	didYouMean,
+	handleError,
+	parseInstallLocation,
+	resolveAlias,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+import { runHooks } from "../hooks.js";
+import { warning } from "../output.js";
+
+/**
+ * Which installed commands a batch removal applies to
+ */
+export interface RemoveSelector {
+	/** Name pattern, optionally prefixed with a location ("project:api:*") */
+	readonly pattern?: string;
+	/** Namespace whose commands are removed, nested ones included */
+	readonly namespace?: string;
+	/** Only remove copies in this location */
+	readonly from?: "personal" | "project";
+}
+
+/**
+ * Select the installed commands a batch removal applies to
+ *
+ * A location prefix on the pattern works like --from; both must agree when
+ * given together.
+ *
+ * @param installed - Every installed copy (see getAllInstallationInfo())
+ * @param selector - Pattern, namespace and location to match
+ * @returns The matching copies, in the order of `installed`
+ */
+export function selectCommandsToRemove(
+	installed: readonly InstallationInfo[],
+	selector: RemoveSelector,
+): InstallationInfo[] {
+	const prefixed = selector.pattern?.match(/^(personal|project):(.+)$/);
+	const pattern = prefixed?.[2] ?? selector.pattern;
+	const locations = [selector.from, prefixed?.[1]].filter(Boolean);
+
+	return installed.filter(
+		(info) =>
+			locations.every((location) => info.location === location) &&
+			(!selector.namespace || isInNamespace(info.name, selector.namespace)) &&
+			(!pattern || matchesCommandPattern(info.name, pattern)),
+	);
+}
+
+/**
+ * Format the confirmation listing of a batch removal
+ *
+ * @param commands - Copies about to be removed
+ */
+export function formatRemovalListing(
+	commands: readonly InstallationInfo[],
+): string {
+	const nameWidth = Math.max(...commands.map((info) => info.name.length));
+	const noun = commands.length === 1 ? "command" : "commands";
+	const lines = commands.map(
+		(info) =>
+			`  ${info.name.padEnd(nameWidth)}  ${info.location.padEnd(8)}  ${info.filePath}`,
+	);
+	return `The following ${commands.length} ${noun} will be removed:\n${lines.join("\n")}`;
+}
+
+/**
+ * Options shared by single and batch removals
+ */
+interface RemoveCommandOptions {
+	readonly yes?: boolean;
+	readonly keepEmptyDirs?: boolean;
+	readonly purge?: boolean;
+	readonly namespace?: string;
+	readonly from?: "personal" | "project";
+}
+
+/**
+ * Remove every installed command matching a pattern or namespace after
+ * listing them and asking once
+ */
+async function removeMatching(
+	pattern: string | undefined,
+	options: RemoveCommandOptions,
+): Promise<void> {
+	const {
+		installationService,
+		configManager,
+		usageStatsService,
+		operationHistory,
+		userInteractionService,
+	} = getServices();
+
+	const selector = pattern ?? `namespace ${options.namespace}`;
+	const matching = selectCommandsToRemove(
+		await installationService.getAllInstallationInfo(),
+		{ pattern, namespace: options.namespace, from: options.from },
+	);
+	if (matching.length === 0) {
+		console.log(
+			pattern
+				? `No installed commands match '${pattern}'.`
+				: `No installed commands in namespace '${options.namespace}'.`,
+		);
+		process.exitCode = ExitCode.NotFound;
+		return;
+	}
+
+	console.log(formatRemovalListing(matching));
+	userInteractionService.setYesMode(options.yes ?? false);
+	const confirmed = await userInteractionService.confirmAction({
+		message:
+			matching.length === 1
+				? "Remove this command?"
+				: `Remove these ${matching.length} commands?`,
+		defaultResponse: false,
+		skipWithYes: true,
+	});
+	if (!confirmed) {
+		console.log("Nothing was removed.");
+		return;
+	}
+
+	const config = await configManager.getEffectiveConfig();
+	const keepEmptyDirectories =
+		options.keepEmptyDirs || config.cleanupEmptyDirectories === false;
+
+	// One batch, so `claude-cmd undo` brings the whole group back
+	await operationHistory.batch(`remove ${selector}`, async () => {
+		for (const info of matching) {
+			await runHooks("pre-remove", { command: info.name });
+			await installationService.removeCommand(info.name, {
+				yes: true,
+				purge: options.purge,
+				keepEmptyDirectories,
+				location: info.location,
+			});
+			await usageStatsService.record({ command: info.name, action: "remove" });
+			await runHooks("post-remove", { command: info.name });
+		}
+	});
+
+	const noun = matching.length === 1 ? "command" : "commands";
+	console.log(
+		options.purge
+			? `Removed ${matching.length} ${noun}.`
+			: `Moved ${matching.length} ${noun} to the trash. Run 'claude-cmd undo' to bring them back.`,
+	);
+}
 
 export const removeCommand = new Command("remove")
 	.description(
-		"Remove an installed Claude Code command from your local system.",
+		"Remove an installed Claude Code command from your local system.\nA pattern such as 'frontend:*' or 'project:frontend:*', or --namespace, removes a whole group after listing it.",
+	)
+	.argument(
+		"[command-name]",
+		"Name or alias of the command to remove, or a pattern ('*' and '?')",
 	)
-	.argument("<command-name>", "Name of the command to remove")
 	.option("-y, --yes", "Skip confirmation prompt")
-	.action(async (commandName, options) => {
+	.option(
+		"--keep-empty-dirs",
+		"Keep namespace directories left empty by the removal",
+	)
+	.option("--purge", "Delete permanently instead of moving to the trash")
+	.option(
+		"--namespace <namespace>",
+		"Remove every command in this namespace, nested ones included",
+	)
+	.option(
+		"--from <location>",
+		"With a pattern or --namespace, only remove commands in 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.action(async (typedName: string | undefined, options) => {
 		try {
-			// Get singleton service instances from factory
-			const { installationService } = getServices();
-
-			// Check if command is installed before attempting removal
-			if (!(await installationService.isInstalled(commandName))) {
-				console.log(`Command '${commandName}' is not installed.`);
+			if (options.namespace || (typedName && isCommandPattern(typedName))) {
+				await removeMatching(typedName, options);
+				return;
+			}
+			if (!typedName) {
+				console.error(
+					"error: missing required argument 'command-name' (or --namespace)",
+				);
+				process.exitCode = ExitCode.Failure;
 				return;
 			}
 
-			// Prepare removal options
-			const removeOptions = {
-				yes: options.yes,
-			};
+			// Aliases may name the location of the copy they stand for
+			const target = await resolveAlias(typedName);
+			const { name: commandName, location } = target;
 
-			// Remove the command (includes interactive confirmation)
-			await installationService.removeCommand(commandName, removeOptions);
+			// Get singleton service instances from factory
+			const {
+				installationService,
+				configManager,
+				usageStatsService,
+				operationHistory,
+			} = getServices();
+			const findPath = async () =>
+				location
+					? ((await installationService.findInstalledCommand(
+							commandName,
+							location,
+						))?.filePath ?? null)
+					: installationService.getInstallationPath(commandName);
+
+			// Removals can be reverted with `claude-cmd undo`
+			await operationHistory.batch(`remove ${commandName}`, async () => {
+				// Check if command is installed before attempting removal
+				const installedPath = await findPath();
+				if (!installedPath) {
+					console.log(`Command '${commandName}' is not installed.`);
+					for (const hint of await didYouMean(typedName, {
+						installed: true,
+					})) {
+						console.log(hint);
+					}
+					process.exitCode = ExitCode.NotFound;
+					return;
+				}
+
+				// Prepare removal options; empty namespace cleanup can be disabled in config
+				const config = await configManager.getEffectiveConfig();
+				const removeOptions = {
+					yes: options.yes,
+					purge: options.purge,
+					keepEmptyDirectories:
+						options.keepEmptyDirs || config.cleanupEmptyDirectories === false,
+					location,
+				};
+
+				await runHooks("pre-remove", { command: commandName });
+
+				// Remove the command (includes interactive confirmation)
+				await installationService.removeCommand(commandName, removeOptions);
+
+				// Declining the confirmation leaves the file in place
+				if ((await findPath()) !== installedPath) {
+					await usageStatsService.record({
+						command: commandName,
+						action: "remove",
+					});
+					if (!options.purge) {
+						console.log(
+							`Moved '${commandName}' to the trash. Run 'claude-cmd restore ${commandName}' to bring it back.`,
+						);
+					}
+					await runHooks("post-remove", { command: commandName });
+					if (config.aliases && Object.hasOwn(config.aliases, typedName)) {
+						await warnAboutStaleAlias(typedName, target);
+					}
+				}
+			});
 		} catch (error) {
-			handleError(error, `Failed to remove command '${commandName}'`);
+			await handleError(error, `Failed to remove command '${typedName}'`);
 		}
 	});
+
+/**
+ * Point at an alias whose command is no longer installed
+ *
+ * The alias is kept: the command may be restored or installed again.
+ */
+async function warnAboutStaleAlias(
+	alias: string,
+	target: AliasTarget,
+): Promise<void> {
+	const { installationService, projectConfigService } = getServices();
+	if (
+		await installationService.findInstalledCommand(
+			target.name,
+			target.location,
+		)
+	) {
+		return;
+	}
+	const projectAliases = (await projectConfigService.getConfig())?.aliases;
+	const flag =
+		projectAliases && Object.hasOwn(projectAliases, alias) ? " --project" : "";
+	warning(
+		`alias '${alias}' still points at ${formatAliasTarget(target)}, which is no longer installed. Run 'claude-cmd alias remove ${alias}${flag}' to delete it.`,
+	);
+}
+
+inGroup(removeCommand, "Install");
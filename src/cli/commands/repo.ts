@@ -0,0 +1,437 @@
+import { Command } from "commander";
+import type {
+	Config,
+	IConfigService,
+} from "../../interfaces/IConfigService.js";
+import FileSystemRepository from "../../services/FileSystemRepository.js";
+import HTTPRepository from "../../services/HTTPRepository.js";
+import type {
+	ConfiguredRepositoryEntry,
+	RepositoryHealth,
+} from "../../services/RepositoryRegistry.js";
+import { getServices } from "../../services/serviceFactory.js";
+import type { Command as CommandType } from "../../types/Command.js";
+import type { RepositoryAbout } from "../../types/Repository.js";
+import {
+	isSameRepository,
+	resolveRepositorySource,
+} from "../../utils/repositorySource.js";
+import { detectLanguage, handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+import { success } from "../output.js";
+
+/**
+ * Describe where commands are read from, as configured
+ */
+function describeSource(config: Config): { type: string; url: string } {
+	if (config.repositoryType === "git" && config.repositoryURL) {
+		const ref = config.repositoryRef ? ` (ref: ${config.repositoryRef})` : "";
+		return { type: "git", url: `${config.repositoryURL}${ref}` };
+	}
+	if (
+		config.repositoryURL &&
+		FileSystemRepository.isFileURL(config.repositoryURL)
+	) {
+		return { type: "file", url: config.repositoryURL };
+	}
+	if (
+		config.repositoryURL &&
+		HTTPRepository.isHTTPURL(config.repositoryURL)
+	) {
+		return { type: "http", url: config.repositoryURL };
+	}
+	return { type: "http", url: HTTPRepository.BASE_URL };
+}
+
+/**
+ * Format a maintainer contact as "Name <email> (url)"
+ */
+function formatMaintainer(
+	maintainer: NonNullable<RepositoryAbout["maintainer"]>,
+): string {
+	return [
+		maintainer.name,
+		maintainer.email ? `<${maintainer.email}>` : undefined,
+		maintainer.url ? `(${maintainer.url})` : undefined,
+	]
+		.filter(Boolean)
+		.join(" ");
+}
+
+/**
+ * Format the catalog header shown above discovery output
+ *
+ * @param about - Repository metadata, or null when none is published
+ * @returns Header text ending in a blank line, or "" without metadata
+ */
+export function formatCatalogHeader(about: RepositoryAbout | null): string {
+	if (!about?.title && !about?.description) {
+		return "";
+	}
+
+	let header = about.title ?? "";
+	if (about.homepage) {
+		header += header ? ` (${about.homepage})` : about.homepage;
+	}
+	if (about.description) {
+		header += header ? `\n${about.description}` : about.description;
+	}
+	return `${header}\n\n`;
+}
+
+/**
+ * Describe the state of a repository: its health when it was checked
+ */
+function describeRepository(
+	repository: ConfiguredRepositoryEntry | RepositoryHealth,
+): string {
+	if (!("status" in repository)) {
+		return repository.enabled ? "enabled" : "disabled";
+	}
+	switch (repository.status) {
+		case "ok":
+			return `ok, ${repository.commandCount ?? 0} commands`;
+		case "failed":
+			return `failed: ${repository.error ?? "unknown error"}`;
+		default:
+			return repository.status;
+	}
+}
+
+/**
+ * Format the configured repositories for the terminal
+ *
+ * @param repositories - Entries of RepositoryRegistry.list(), or the results
+ *   of RepositoryRegistry.check()
+ * @param served - Names of the commands each repository serves in the merged
+ *   listing, by URL; shown under each repository when given
+ * @returns One line per repository (plus its commands)
+ */
+export function formatRepositoryList(
+	repositories: readonly (ConfiguredRepositoryEntry | RepositoryHealth)[],
+	served?: ReadonlyMap<string, readonly string[]>,
+): string {
+	const width = Math.max(...repositories.map(({ url }) => url.length));
+	const lines: string[] = [];
+	for (const repository of repositories) {
+		const role = repository.primary ? "primary" : "additional";
+		lines.push(
+			`${repository.url.padEnd(width)}  ${role.padEnd(10)}  ${describeRepository(repository)}`,
+		);
+		for (const name of served?.get(repository.url) ?? []) {
+			lines.push(`  ${name}`);
+		}
+	}
+	return lines.join("\n");
+}
+
+/**
+ * Group listed commands by the repository they come from
+ *
+ * Commands without Command.repository (a single configured repository) are
+ * counted as the primary repository's.
+ *
+ * @param commands - Commands of the merged listing
+ * @param primary - URL of the primary repository
+ * @returns Command names by repository URL, in listing order
+ */
+export function commandsByRepository(
+	commands: readonly CommandType[],
+	primary: string,
+): Map<string, string[]> {
+	const grouped = new Map<string, string[]>();
+	for (const command of commands) {
+		const url = command.repository ?? primary;
+		const names = grouped.get(url) ?? [];
+		names.push(command.name);
+		grouped.set(url, names);
+	}
+	return grouped;
+}
+
+/**
+ * Get the config file --project selects, global otherwise
+ */
+function configFor(options: { project?: boolean }): {
+	scope: "project" | "global";
+	service: IConfigService;
+} {
+	const { projectConfigService, userConfigService } = getServices();
+	return options.project
+		? { scope: "project", service: projectConfigService }
+		: { scope: "global", service: userConfigService };
+}
+
+/**
+ * Find the additionalRepositories entry a source names in a config
+ *
+ * @param source - owner/name[@ref] or URL, as accepted by `repo add`
+ * @returns The entry as written in the config, or undefined
+ */
+function findAdditional(config: Config, source: string): string | undefined {
+	const url = resolveRepositorySource(source);
+	return config.additionalRepositories?.find((entry) =>
+		isSameRepository(entry, url),
+	);
+}
+
+const repoInfoCommand = new Command("info")
+	.description(
+		"Show which command repository is in use and the metadata it publishes.",
+	)
+	.option("-l, --language <lang>", "Language for localized repository details")
+	.action(async (options) => {
+		try {
+			const { configManager, repository } = getServices();
+			const language = await detectLanguage(options.language);
+			const source = describeSource(await configManager.getEffectiveConfig());
+			const about = await repository.getAbout(language);
+
+			console.log(`Source:       ${source.url}`);
+			console.log(`Type:         ${source.type}`);
+			console.log(`Language:     ${language}`);
+
+			if (!about) {
+				console.log("\nThis repository does not publish an about.json.");
+				return;
+			}
+
+			console.log("");
+			if (about.title) {
+				console.log(`Title:        ${about.title}`);
+			}
+			if (about.description) {
+				console.log(`Description:  ${about.description}`);
+			}
+			if (about.homepage) {
+				console.log(`Homepage:     ${about.homepage}`);
+			}
+			if (about.maintainer) {
+				console.log(`Maintainer:   ${formatMaintainer(about.maintainer)}`);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to get repository information");
+		}
+	});
+
+const repoListCommand = new Command("list")
+	.description(
+		"List the configured repositories and check that each is reachable and serves a valid manifest.",
+	)
+	.option("-l, --language <lang>", "Language whose manifests are checked")
+	.option("--offline", "Only list the configuration; fetch nothing")
+	.option(
+		"--commands",
+		"Also list the commands each repository contributes to the listing",
+	)
+	.action(async (options) => {
+		try {
+			const { commandQueryService, repositoryRegistry } = getServices();
+			const language = await detectLanguage(options.language);
+			const repositories = options.offline
+				? await repositoryRegistry.list()
+				: await repositoryRegistry.check(language);
+
+			let served: Map<string, string[]> | undefined;
+			if (options.commands) {
+				const primary = repositories[0]?.url ?? HTTPRepository.BASE_URL;
+				served = commandsByRepository(
+					await commandQueryService.listCommands({ language }),
+					primary,
+				);
+			}
+
+			if (
+				repositories.some(
+					(repository) =>
+						"status" in repository && repository.status === "failed",
+				)
+			) {
+				process.exitCode = ExitCode.Network;
+			}
+
+			if (isPorcelain()) {
+				for (const repository of repositories) {
+					console.log(
+						[
+							repository.url,
+							repository.primary ? "primary" : "additional",
+							"status" in repository
+								? repository.status
+								: describeRepository(repository),
+							"commandCount" in repository
+								? (repository.commandCount ?? "")
+								: "",
+						].join("\t"),
+					);
+				}
+				return;
+			}
+
+			console.log(formatRepositoryList(repositories, served));
+		} catch (error) {
+			await handleError(error, "Failed to list repositories");
+		}
+	});
+
+const repoAddCommand = new Command("add")
+	.description(
+		"Add a repository whose commands are listed after the configured repository's (additionalRepositories).",
+	)
+	.argument(
+		"<source>",
+		"GitHub repository as owner/name[@ref] (default ref: main), or an http(s) or file:// URL",
+	)
+	.option("--project", "Add it to the project config instead of the global one")
+	.action(async (source: string, options) => {
+		try {
+			const { configManager } = getServices();
+			const url = resolveRepositorySource(source);
+
+			const effective = await configManager.getEffectiveConfig();
+			const duplicate = [
+				effective.repositoryURL || HTTPRepository.BASE_URL,
+				...(effective.additionalRepositories ?? []),
+			].find((configured) => isSameRepository(configured, url));
+			if (duplicate) {
+				console.log(`${duplicate} is already configured`);
+				return;
+			}
+
+			const { scope, service } = configFor(options);
+			const current = (await service.getConfig()) ?? {};
+			await service.setConfig({
+				...current,
+				additionalRepositories: [
+					...(current.additionalRepositories ?? []),
+					url,
+				],
+			});
+			success(`Added ${url} to ${scope} config`);
+		} catch (error) {
+			await handleError(error, `Failed to add repository '${source}'`);
+		}
+	});
+
+const repoRemoveCommand = new Command("remove")
+	.description("Stop reading commands from an additional repository.")
+	.argument(
+		"<source>",
+		"Repository as given to repo add (owner/name[@ref] or URL)",
+	)
+	.option(
+		"--project",
+		"Remove it from the project config instead of the global one",
+	)
+	.action(async (source: string, options) => {
+		try {
+			const { scope, service } = configFor(options);
+			const current = (await service.getConfig()) ?? {};
+			const entry = findAdditional(current, source);
+			if (!entry) {
+				console.error(
+					`${source} is not an additional repository in ${scope} config`,
+				);
+				process.exitCode = ExitCode.NotFound;
+				return;
+			}
+
+			const { additionalRepositories, disabledRepositories, ...rest } =
+				current;
+			const remaining = (additionalRepositories ?? []).filter(
+				(url) => url !== entry,
+			);
+			const disabled = (disabledRepositories ?? []).filter(
+				(url) => !isSameRepository(url, entry),
+			);
+			await service.setConfig({
+				...rest,
+				...(remaining.length > 0
+					? { additionalRepositories: remaining }
+					: {}),
+				...(disabled.length > 0 ? { disabledRepositories: disabled } : {}),
+			});
+			success(`Removed ${entry} from ${scope} config`);
+		} catch (error) {
+			await handleError(error, `Failed to remove repository '${source}'`);
+		}
+	});
+
+/**
+ * Create `repo enable` or `repo disable`
+ */
+function toggleCommand(enable: boolean): Command {
+	const verb = enable ? "enable" : "disable";
+	return new Command(verb)
+		.description(
+			enable
+				? "List the commands of a disabled additional repository again."
+				: "Leave the commands of an additional repository out without removing it.",
+		)
+		.argument(
+			"<source>",
+			"Repository as given to repo add (owner/name[@ref] or URL)",
+		)
+		.option(
+			"--project",
+			`${enable ? "Enable" : "Disable"} it in the project config, also when it was added globally`,
+		)
+		.action(async (source: string, options) => {
+			try {
+				const { configManager } = getServices();
+				const { scope, service } = configFor(options);
+				const current = (await service.getConfig()) ?? {};
+				// Project lists replace global ones, so a project toggles the
+				// repositories and disabled list in effect
+				const effective = options.project
+					? await configManager.getEffectiveConfig()
+					: current;
+				const entry = findAdditional(effective, source);
+				if (!entry) {
+					const where = options.project ? "project or global" : scope;
+					console.error(
+						`${source} is not an additional repository in ${where} config`,
+					);
+					process.exitCode = ExitCode.NotFound;
+					return;
+				}
+
+				const { disabledRepositories: _previous, ...rest } = current;
+				const disabledRepositories = effective.disabledRepositories ?? [];
+				const others = disabledRepositories.filter(
+					(url) => !isSameRepository(url, entry),
+				);
+				const wasDisabled = others.length !== disabledRepositories.length;
+				if (wasDisabled !== enable) {
+					console.log(`${entry} is already ${verb}d`);
+					return;
+				}
+				const disabled = enable ? others : [...others, entry];
+				// An empty project list still overrides a global one
+				const keepList = disabled.length > 0 || options.project;
+				await service.setConfig({
+					...rest,
+					...(keepList ? { disabledRepositories: disabled } : {}),
+				});
+				success(
+					`${enable ? "Enabled" : "Disabled"} ${entry} in ${scope} config`,
+				);
+			} catch (error) {
+				await handleError(error, `Failed to ${verb} repository '${source}'`);
+			}
+		});
+}
+
+export const repoCommand = new Command("repo")
+	.description(
+		"Inspect and manage the command repositories claude-cmd reads from",
+	)
+	.addCommand(repoListCommand)
+	.addCommand(repoInfoCommand)
+	.addCommand(repoAddCommand)
+	.addCommand(repoRemoveCommand)
+	.addCommand(toggleCommand(true))
+	.addCommand(toggleCommand(false));
+
+inGroup(repoCommand, "Configure");
@@ -0,0 +1,60 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import type { TrashEntry } from "../../types/Installation.js";
+import { formatDuration } from "../../utils/format.js";
+import { handleError, parseInstallLocation } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Describe a trashed command on one line
+ *
+ * @param entry - Trash entry to describe
+ * @param now - Current time for the relative removal time
+ */
+export function formatTrashEntry(entry: TrashEntry, now: number): string {
+	return `${entry.name} (${entry.location}), removed ${formatDuration(now - entry.trashedAt)} ago`;
+}
+
+export const restoreCommand = new Command("restore")
+	.description(
+		"Restore a removed command from the trash.\nRemoved commands are kept for 30 days.",
+	)
+	.argument("[command-name]", "Name of the removed command")
+	.option(
+		"--from <location>",
+		"Restore the command removed from 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option("-f, --force", "Overwrite a command installed under the same name")
+	.option("--list", "List the commands in the trash")
+	.action(async (commandName: string | undefined, options) => {
+		try {
+			const { installationService, operationHistory, trashService, clock } =
+				getServices();
+
+			if (options.list || !commandName) {
+				const entries = await trashService.list();
+				if (entries.length === 0) {
+					console.log("The trash is empty.");
+					return;
+				}
+				for (const entry of entries) {
+					console.log(formatTrashEntry(entry, clock.now()));
+				}
+				return;
+			}
+
+			await operationHistory.batch(`restore ${commandName}`, async () => {
+				const entry = await installationService.restoreCommand(commandName, {
+					from: options.from,
+					force: options.force,
+				});
+				success(`Restored ${commandName} to ${entry.originalPath}`);
+			});
+		} catch (error) {
+			await handleError(error, `Failed to restore command '${commandName}'`);
+		}
+	});
+
+inGroup(restoreCommand, "Install");
@@ -1,7 +1,14 @@
 import { Command } from "commander";
-import { getServices } from "../../services/serviceFactory.js";
 import type { Command as CommandType } from "../../types/Command.js";
-import { detectLanguage, handleError } from "../cliUtils.js";
+import { isDeprecated } from "../../utils/commandDeprecation.js";
+import {
+	detectLanguage,
+	getClient,
+	handleError,
+	isPorcelain,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { formatCommandsPorcelain } from "./list.js";
 
 /**
  * Format search results for terminal output with enhanced UX
@@ -30,7 +37,8 @@ function formatSearchResults(
 	// Format each command with consistent spacing
 	for (const command of commands) {
 		// Use consistent tab spacing for alignment (matching list command)
-		output += `${command.name}\t\t${command.description}\n`;
+		const flag = isDeprecated(command) ? " [deprecated]" : "";
+		output += `${command.name}\t\t${command.description}${flag}\n`;
 	}
 
 	return output.trim();
@@ -44,7 +52,10 @@ function formatSearchResults(
  * in a user-friendly manner with helpful context and suggestions.
  *
  * Features:
- * - Case-insensitive search in names and descriptions
+ * - Case-insensitive search in names, descriptions and tags
+ * - Optional --tag filter for a tag or category
+ * - Optional --content search of command file bodies through an index kept
+ *   in the cache directory (built on first use, refreshed by cache update)
  * - Language-specific search with auto-detection
  * - Cache management with force refresh option
  * - Clear result formatting with count and context
@@ -60,11 +71,14 @@ function formatSearchResults(
  *
  * # Force refresh cache and search
  * claude-cmd search api --force
+ *
+ * # Also search inside command files
+ * claude-cmd search "git rebase" --content
  * ```
  */
 export const searchCommand = new Command("search")
 	.description(
-		"Find Claude Code commands by name or description.\nPerforms case-insensitive search to help you discover relevant commands.",
+		"Find Claude Code commands by name, description or tag.\nPerforms case-insensitive search to help you discover relevant commands.",
 	)
 	.argument(
 		"<query>",
@@ -74,32 +88,45 @@ export const searchCommand = new Command("search")
 		"-l, --language <lang>",
 		"Language for commands (default: auto-detect from system)",
 	)
+	.option("-t, --tag <tag>", "Only search commands with this tag or category")
+	.option(
+		"-c, --content",
+		"Also match words in command files (downloads and indexes them on first use)",
+	)
 	.option("-f, --force", "Force refresh cache to get latest commands")
 	.action(async (query, options) => {
 		try {
-			// Get singleton service instances from factory
-			const { commandQueryService, languageDetector } = getServices();
-
 			// Prepare options for CommandService with proper typing
 			const serviceOptions = {
 				language: options.language,
 				forceRefresh: options.force,
+				tag: options.tag,
+				content: options.content,
 			};
 
 			// Execute search through service layer
-			const commands = await commandQueryService.searchCommands(
+			const commands = await getClient().searchCommands(
 				query,
 				serviceOptions,
 			);
 
+			if (isPorcelain()) {
+				if (commands.length > 0) {
+					console.log(formatCommandsPorcelain(commands));
+				}
+				return;
+			}
+
 			// Determine effective language used for search
-			const language = await detectLanguage(options.language, languageDetector);
+			const language = await detectLanguage(options.language);
 
 			// Format results and display to user
 			const output = formatSearchResults(commands, query, language);
 			console.log(output);
 		} catch (error) {
 			// Handle errors with user-friendly messages and proper exit codes
-			handleError(error, "Failed to search commands");
+			await handleError(error, "Failed to search commands");
 		}
 	});
+
+inGroup(searchCommand, "Discover");
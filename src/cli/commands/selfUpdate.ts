@@ -0,0 +1,62 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Whether claude-cmd runs as a compiled standalone binary
+ *
+ * `bun build --compile` serves the bundled sources from a virtual file
+ * system ("/$bunfs/" on POSIX, a "~BUN" drive on Windows). Anything else is
+ * an install managed by a package manager, which should update it instead.
+ */
+function isStandaloneBinary(): boolean {
+	return Bun.main.startsWith("/$bunfs/") || Bun.main.includes("~BUN");
+}
+
+export const selfUpdateCommand = new Command("self-update")
+	.description(
+		"Update claude-cmd to the latest GitHub release, verifying the download against the release checksums.",
+	)
+	.option("--check", "Only report whether a newer release is available")
+	.option("--force", "Reinstall the latest release even if it is not newer")
+	.action(async (options, command: Command) => {
+		try {
+			const { selfUpdateService, updateNotifier } = getServices();
+			const currentVersion = command.parent?.version() ?? "0.0.0";
+
+			const release = await selfUpdateService.check(currentVersion);
+			await updateNotifier.record(release.version);
+			if (isPorcelain()) {
+				console.log(
+					[currentVersion, release.version, String(release.newer)].join("\t"),
+				);
+			} else if (release.newer) {
+				console.log(
+					`claude-cmd ${release.version} is available (current: ${currentVersion}).`,
+				);
+				console.log(`Release notes: ${release.url}`);
+			} else {
+				console.log(`claude-cmd ${currentVersion} is up to date.`);
+			}
+			if (options.check || !(release.newer || options.force)) {
+				return;
+			}
+
+			if (!isStandaloneBinary()) {
+				throw new Error(
+					"self-update only replaces standalone binaries; update claude-cmd with the package manager it was installed with",
+				);
+			}
+
+			await selfUpdateService.install(release, process.execPath);
+			if (!isPorcelain()) {
+				success(`Updated claude-cmd to ${release.version}`);
+			}
+		} catch (error) {
+			await handleError(error, "Failed to update claude-cmd");
+		}
+	});
+
+inGroup(selfUpdateCommand, "Maintain");
@@ -0,0 +1,59 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { CommandNotInstalledError } from "../../types/Installation.js";
+import { handleError, parseInstallLocation } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { highlightMarkdown } from "../highlight.js";
+import { colorEnabled } from "../output.js";
+import { printWithPager } from "../pager.js";
+
+export const showCommand = new Command("show")
+	.alias("cat")
+	.description(
+		"Print the full content of a repository or installed command, paged on terminals.",
+	)
+	.argument("<command-name>", "Name of the command to show")
+	.option(
+		"-l, --language <lang>",
+		"Language for commands (default: auto-detect)",
+	)
+	.option("-i, --installed", "Show the installed copy, not the repository one")
+	.option(
+		"--from <location>",
+		"With --installed, the location to read: 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option("--no-pager", "Print directly instead of using $PAGER")
+	.option("--no-highlight", "Disable syntax highlighting")
+	.action(async (commandName, options) => {
+		try {
+			const { commandContentService, installationService, fileService } =
+				getServices();
+
+			let content: string;
+			if (options.installed || options.from) {
+				const installed = await installationService.findInstalledCommand(
+					commandName,
+					options.from,
+				);
+				if (!installed) {
+					throw new CommandNotInstalledError(commandName);
+				}
+				content = await fileService.readFile(installed.filePath);
+			} else {
+				content = await commandContentService.getCommandContent(commandName, {
+					language: options.language,
+				});
+			}
+
+			const output =
+				options.highlight && colorEnabled(process.stdout)
+					? highlightMarkdown(content)
+					: content;
+			await printWithPager(output.trimEnd(), options.pager);
+		} catch (error) {
+			await handleError(error, `Failed to show command '${commandName}'`);
+		}
+	});
+
+inGroup(showCommand, "Discover");
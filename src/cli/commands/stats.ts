@@ -0,0 +1,121 @@
+import { Command } from "commander";
+import type {
+	CommandUsage,
+	UsageEvent,
+} from "../../interfaces/IUsageStatsService.js";
+import { getServices } from "../../services/serviceFactory.js";
+import { formatDuration } from "../../utils/format.js";
+import { handleError, parsePositiveInteger } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { success } from "../output.js";
+
+/**
+ * Format the most-installed table
+ *
+ * @param usage - Per-command statistics, already ordered
+ * @param now - Current time for relative timestamps
+ */
+export function formatCommandUsage(
+	usage: readonly CommandUsage[],
+	now: number,
+): string {
+	if (usage.length === 0) {
+		return "No install activity recorded yet.";
+	}
+
+	const width = Math.max(...usage.map((entry) => entry.command.length));
+	const lines = ["Most installed commands:"];
+	for (const entry of usage) {
+		const installs = `${entry.installs} install${entry.installs === 1 ? "" : "s"}`;
+		const removals =
+			entry.removals > 0
+				? `, ${entry.removals} removal${entry.removals === 1 ? "" : "s"}`
+				: "";
+		lines.push(
+			`  ${entry.command.padEnd(width)}  ${installs}${removals}, last used ${formatDuration(now - entry.lastUsedAt)} ago`,
+		);
+	}
+	return lines.join("\n");
+}
+
+/**
+ * Format install history, one event per line
+ *
+ * @param history - Events, most recent first
+ */
+export function formatUsageHistory(history: readonly UsageEvent[]): string {
+	if (history.length === 0) {
+		return "No install history recorded yet.";
+	}
+
+	const lines = ["Install history:"];
+	for (const event of history) {
+		const details = [event.language, event.target].filter(Boolean).join(", ");
+		lines.push(
+			`  ${new Date(event.at).toISOString()}  ${event.action.padEnd(7)}  ${event.command}${details ? ` (${details})` : ""}`,
+		);
+	}
+	return lines.join("\n");
+}
+
+export const statsCommand = new Command("stats")
+	.description(
+		"Show which commands are installed most and the local install history.\nStatistics are stored only on this machine.",
+	)
+	.option(
+		"-n, --limit <n>",
+		"Number of entries to show",
+		parsePositiveInteger,
+		10,
+	)
+	.option("--history", "Show install history instead of totals")
+	.option(
+		"--output <format>",
+		"Output format: default (human-readable), json (structured data)",
+		"default",
+	)
+	.option("--clear", "Delete all recorded statistics")
+	.action(async (options) => {
+		try {
+			if (!["default", "json"].includes(options.output)) {
+				throw new Error(
+					`Invalid format: ${options.output}. Must be one of: default, json`,
+				);
+			}
+			const json = options.output === "json";
+
+			const { usageStatsService, clock } = getServices();
+
+			if (options.clear) {
+				const cleared = await usageStatsService.clear();
+				if (cleared) {
+					success("Usage statistics cleared");
+				} else {
+					console.log("No usage statistics to clear");
+				}
+				return;
+			}
+
+			if (options.history) {
+				const history = await usageStatsService.getHistory(options.limit);
+				console.log(
+					json ? JSON.stringify(history, null, 2) : formatUsageHistory(history),
+				);
+				return;
+			}
+
+			const usage = (await usageStatsService.getCommandUsage()).slice(
+				0,
+				options.limit,
+			);
+			console.log(
+				json
+					? JSON.stringify(usage, null, 2)
+					: formatCommandUsage(usage, clock.now()),
+			);
+		} catch (error) {
+			await handleError(error, "Failed to read usage statistics");
+		}
+	});
+
+inGroup(statsCommand, "Maintain");
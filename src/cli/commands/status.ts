@@ -1,7 +1,10 @@
 import { Command } from "commander";
 import { getServices } from "../../services/serviceFactory.js";
 import type { StatusOutputFormat } from "../../types/Status.js";
-import { handleError } from "../cliUtils.js";
+import { TemplateError } from "../../utils/template.js";
+import { getOutputTemplate, handleError, rendersTemplates } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { getTheme } from "../output.js";
 
 export const statusCommand = new Command("status")
 	.description(
@@ -12,7 +15,19 @@ export const statusCommand = new Command("status")
 		"Output format: default (human-readable), compact (one-line summary), json (structured data)",
 		"default",
 	)
-	.action(async (options) => {
+	.option(
+		"--all-languages",
+		"Show cache counts and ages for every cached language",
+	)
+	.option(
+		"--usage",
+		"Show disk space used by caches and installed commands instead",
+	)
+	.option(
+		"--check-repository",
+		"Also check that the repository is reachable (needs the network)",
+	)
+	.action(async (options, command: Command) => {
 		try {
 			// Validate format option
 			const format = options.output as StatusOutputFormat;
@@ -21,17 +36,54 @@ export const statusCommand = new Command("status")
 					`Invalid format: ${format}. Must be one of: default, compact, json`,
 				);
 			}
+			const template = getOutputTemplate(command);
+			if (template && (options.usage || options.allLanguages)) {
+				throw new TemplateError(
+					"--format templates are not supported with --usage or --all-languages",
+					template.source,
+				);
+			}
 
 			// Get singleton service instances from factory
-			const { statusService, statusFormatter } = getServices();
+			const { statusService, statusFormatter, configManager } = getServices();
+			const locale = await configManager.getEffectiveLanguage();
+
+			if (options.usage) {
+				const usage = await statusService.getDiskUsage();
+				console.log(
+					statusFormatter.formatDiskUsage(usage, format, { locale }),
+				);
+				return;
+			}
+
+			if (options.allLanguages) {
+				const languages = await statusService.getAllLanguagesStatus();
+				console.log(
+					statusFormatter.formatAllLanguages(languages, format, { locale }),
+				);
+				return;
+			}
 
 			// Collect system status information
-			const status = await statusService.getSystemStatus();
+			const status = await statusService.getSystemStatus({
+				checkRepository: options.checkRepository,
+			});
+
+			if (template) {
+				console.log(statusFormatter.formatTemplate(status, template, locale));
+				return;
+			}
 
 			// Format and display output
-			const output = statusFormatter.format(status, format);
+			const output = statusFormatter.format(status, format, {
+				theme: getTheme(),
+				locale,
+			});
 			console.log(output);
 		} catch (error) {
-			handleError(error, "Failed to collect system status");
+			await handleError(error, "Failed to collect system status");
 		}
 	});
+
+rendersTemplates(statusCommand);
+inGroup(statusCommand, "Maintain");
@@ -0,0 +1,77 @@
+import { Command } from "commander";
+import {
+	type HistoryBatch,
+	UndoError,
+} from "../../services/OperationHistory.js";
+import { getServices } from "../../services/serviceFactory.js";
+import { formatDuration } from "../../utils/format.js";
+import { handleError } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { isQuiet, success } from "../output.js";
+
+/**
+ * Describe a recorded operation on one line
+ *
+ * @param batch - Operation to describe
+ * @param now - Current time for the relative time
+ */
+export function formatHistoryBatch(batch: HistoryBatch, now: number): string {
+	const files = `${batch.paths.length} file${batch.paths.length === 1 ? "" : "s"}`;
+	return `${batch.name}, ${files}, ${formatDuration(now - batch.at)} ago`;
+}
+
+export const undoCommand = new Command("undo")
+	.description(
+		"Revert the last add, remove, mv or copy.\nFiles it changed are restored to their previous content.",
+	)
+	.option("--list", "List the operations that can be undone, newest first")
+	.option("-f, --force", "Restore files even if they were edited since")
+	.option("-y, --yes", "Skip confirmation prompt")
+	.action(async (options) => {
+		try {
+			const { operationHistory, userInteractionService, clock } =
+				getServices();
+			const history = await operationHistory.list();
+			const latest = history[0];
+
+			if (!latest) {
+				console.log("Nothing to undo.");
+				return;
+			}
+
+			if (options.list) {
+				for (const batch of history) {
+					console.log(formatHistoryBatch(batch, clock.now()));
+				}
+				return;
+			}
+
+			userInteractionService.setYesMode(options.yes ?? false);
+			const confirmed = await userInteractionService.confirmAction({
+				message: `Undo ${formatHistoryBatch(latest, clock.now())}?`,
+				defaultResponse: false,
+				skipWithYes: true,
+			});
+			if (!confirmed) {
+				console.log("Nothing was changed.");
+				return;
+			}
+
+			const undone = await operationHistory.undo({ force: options.force });
+			success(`Undid ${undone.name}`);
+			if (!isQuiet()) {
+				for (const filePath of undone.paths) {
+					console.log(`  ${filePath}`);
+				}
+			}
+		} catch (error) {
+			if (error instanceof UndoError) {
+				for (const filePath of error.modifiedPaths) {
+					console.error(`  changed: ${filePath}`);
+				}
+			}
+			await handleError(error, "Failed to undo the last operation");
+		}
+	});
+
+inGroup(undoCommand, "Maintain");
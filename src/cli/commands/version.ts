@@ -0,0 +1,53 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import { getBuildInfo } from "../../utils/buildInfo.js";
+import { handleError, isPorcelain } from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+
+/**
+ * Longest a background release lookup may keep the process alive
+ */
+const UPDATE_CHECK_TIMEOUT_MS = 1500;
+
+export const versionCommand = new Command("version")
+	.description(
+		"Show the claude-cmd version with build metadata for bug reports, and whether a newer release is available.",
+	)
+	.option("--no-update-check", "Do not look up the latest release")
+	.action(async (options, command: Command) => {
+		try {
+			const info = getBuildInfo(command.parent?.version() ?? "0.0.0");
+
+			if (isPorcelain()) {
+				for (const [key, value] of Object.entries(info)) {
+					console.log(`${key}\t${value}`);
+				}
+			} else {
+				console.log(`claude-cmd ${info.version}`);
+				console.log(`  commit:   ${info.commit}`);
+				console.log(`  built:    ${info.date}`);
+				console.log(`  runtime:  ${info.runtime}`);
+				console.log(`  platform: ${info.platform}`);
+			}
+
+			// Only the recorded release is reported; a stale record is refreshed
+			// in the background for the next run
+			if (options.updateCheck) {
+				const newer = await getServices().updateNotifier.getNewerVersion(
+					info.version,
+					UPDATE_CHECK_TIMEOUT_MS,
+				);
+				if (newer && isPorcelain()) {
+					console.log(`latest\t${newer}`);
+				} else if (newer) {
+					console.log(
+						`\nclaude-cmd ${newer} is available. Run 'claude-cmd self-update' to install it.`,
+					);
+				}
+			}
+		} catch (error) {
+			await handleError(error, "Failed to show version");
+		}
+	});
+
+inGroup(versionCommand, "Advanced");
@@ -0,0 +1,217 @@
+import { type FSWatcher, watch } from "node:fs";
+import * as path from "node:path";
+import { Command } from "commander";
+import type { ScanOptions } from "../../interfaces/IFileService.js";
+import { getServices } from "../../services/serviceFactory.js";
+import { compareStrings } from "../../utils/ordering.js";
+import { matchesDirectoryPattern, pathSegments } from "../../utils/paths.js";
+import {
+	describeParseError,
+	handleError,
+	parseInstallLocation,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+import { getTheme, success } from "../output.js";
+
+/**
+ * Time to wait for an editor to finish saving before validating a file
+ */
+const SETTLE_MS = 100;
+
+/**
+ * A commands directory being watched
+ */
+interface WatchedDirectory {
+	readonly location: "personal" | "project";
+	readonly dir: string;
+}
+
+/**
+ * Validate one command file
+ *
+ * @param dir - Commands directory the file belongs to
+ * @param relativePath - File path inside dir (e.g., "frontend/review.md")
+ * @returns Why the file is invalid, or undefined if it parses
+ */
+export async function checkCommandFile(
+	dir: string,
+	relativePath: string,
+): Promise<string | undefined> {
+	const { commandParser, fileService } = getServices();
+	try {
+		const content = await fileService.readFile(path.join(dir, relativePath));
+		await commandParser.parseCommandFile(
+			content,
+			relativePath.replace(/\\/g, "/"),
+		);
+		return undefined;
+	} catch (error) {
+		return describeParseError(error);
+	}
+}
+
+/**
+ * Check whether a command scan skips a file
+ *
+ * @param relativePath - File path inside a commands directory
+ * @param options - Depth limit and ignored directories of the scan
+ */
+function isPruned(
+	relativePath: string,
+	{ maxDepth, ignore }: Required<ScanOptions>,
+): boolean {
+	const directories = pathSegments(relativePath).slice(0, -1);
+	return (
+		directories.length > maxDepth ||
+		directories.some((name) => matchesDirectoryPattern(name, ignore))
+	);
+}
+
+/**
+ * Validate every command file of the watched directories
+ *
+ * @returns Number of invalid files
+ */
+async function checkAll(
+	dirs: readonly WatchedDirectory[],
+	scanOptions: Required<ScanOptions>,
+): Promise<number> {
+	const { fileService } = getServices();
+	let invalid = 0;
+	for (const { location, dir } of dirs) {
+		const files = (await fileService.listFilesRecursive(dir, scanOptions))
+			.filter((file) => file.endsWith(".md"))
+			.sort(compareStrings);
+		for (const file of files) {
+			const problem = await checkCommandFile(dir, file);
+			if (problem) {
+				invalid++;
+				console.warn(
+					`${getTheme(process.stderr).error("✗")} ${location}: ${file}: ${problem}`,
+				);
+			}
+		}
+		console.log(`Checked ${files.length} ${location} command file(s)`);
+	}
+	return invalid;
+}
+
+/**
+ * Watch directories and validate command files as they are saved
+ *
+ * Editors often write a file in several steps, so each file is validated
+ * once its events have settled.
+ *
+ * @returns Watchers, to close on exit
+ */
+function watchDirectories(
+	dirs: readonly WatchedDirectory[],
+	scanOptions: Required<ScanOptions>,
+): FSWatcher[] {
+	const { fileService } = getServices();
+	const pending = new Map<string, ReturnType<typeof setTimeout>>();
+
+	const revalidate = async (location: string, dir: string, file: string) => {
+		const label = `${location}: ${file}`;
+		if (!(await fileService.exists(path.join(dir, file)))) {
+			console.log(`- ${label} removed`);
+			return;
+		}
+		const problem = await checkCommandFile(dir, file);
+		if (problem) {
+			console.warn(
+				`${getTheme(process.stderr).error("✗")} ${label}: ${problem}`,
+			);
+		} else {
+			success(label);
+		}
+	};
+
+	return dirs.map(({ location, dir }) =>
+		watch(dir, { recursive: true }, (_event, filename) => {
+			if (!filename?.endsWith(".md") || isPruned(filename, scanOptions)) {
+				return;
+			}
+			const key = path.join(dir, filename);
+			clearTimeout(pending.get(key));
+			pending.set(
+				key,
+				setTimeout(() => {
+					pending.delete(key);
+					void revalidate(location, dir, filename);
+				}, SETTLE_MS),
+			);
+		}),
+	);
+}
+
+export const watchCommand = new Command("watch")
+	.description(
+		"Watch personal and project command directories and report invalid frontmatter as soon as a file is saved.",
+	)
+	.option(
+		"--from <location>",
+		"Only watch 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.option(
+		"--once",
+		"Check every command file once and exit (1 if any is invalid)",
+	)
+	.action(async (options) => {
+		try {
+			const { directoryDetector, fileService } = getServices();
+
+			const candidates: WatchedDirectory[] = [
+				{
+					location: "personal",
+					dir: await directoryDetector.getPersonalDirectory(),
+				},
+				...(await directoryDetector.getProjectDirectories(true)).map(
+					(dir): WatchedDirectory => ({ location: "project", dir }),
+				),
+			];
+			const dirs: WatchedDirectory[] = [];
+			for (const candidate of candidates) {
+				if (options.from && options.from !== candidate.location) {
+					continue;
+				}
+				if (await fileService.exists(candidate.dir)) {
+					dirs.push(candidate);
+				}
+			}
+			if (dirs.length === 0) {
+				console.log("No command directories to watch.");
+				return;
+			}
+
+			const scanOptions = await directoryDetector.getScanOptions();
+			const invalid = await checkAll(dirs, scanOptions);
+			if (options.once) {
+				if (invalid > 0) {
+					process.exitCode = ExitCode.Failure;
+				}
+				return;
+			}
+
+			const watchers = watchDirectories(dirs, scanOptions);
+			for (const { location, dir } of dirs) {
+				console.log(`Watching ${location} commands in ${dir}`);
+			}
+			console.log("Press Ctrl+C to stop.");
+
+			await new Promise<void>((resolve) => {
+				process.once("SIGINT", () => {
+					for (const watcher of watchers) {
+						watcher.close();
+					}
+					resolve();
+				});
+			});
+		} catch (error) {
+			await handleError(error, "Failed to watch command directories");
+		}
+	});
+
+inGroup(watchCommand, "Maintain");
@@ -0,0 +1,89 @@
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import type { ManifestComparisonResult } from "../../types/index.js";
+import {
+	detectLanguage,
+	handleError,
+	isPorcelain,
+	parsePositiveInteger,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+
+/**
+ * Format a comparison as porcelain records: change<TAB>name<TAB>description
+ *
+ * The change is "added", "modified" or "removed"; removed commands carry
+ * their last known description.
+ *
+ * @param comparison - Comparison of the older and the newer manifest
+ * @returns One line per change, empty when nothing changed
+ */
+export function formatChangesPorcelain(
+	comparison: ManifestComparisonResult,
+): string {
+	return comparison.changes
+		.map((change) => {
+			const command = change.newCommand ?? change.oldCommand;
+			const description = command?.description.replace(/\s+/g, " ") ?? "";
+			return [change.type, change.name, description].join("\t");
+		})
+		.join("\n");
+}
+
+export const whatsnewCommand = new Command("whatsnew")
+	.description(
+		"Show commands added, changed and removed by the last manifest update.",
+	)
+	.option(
+		"-l, --language <lang>",
+		"Language for commands (default: auto-detect)",
+	)
+	.option(
+		"-b, --back <n>",
+		"Compare against the manifest n updates ago instead of the previous one",
+		parsePositiveInteger,
+		1,
+	)
+	.action(async (options) => {
+		try {
+			const {
+				cacheManager,
+				changeDisplayFormatter,
+				manifestComparison,
+			} = getServices();
+			const language = await detectLanguage(options.language);
+
+			const history = await cacheManager.getHistory(language);
+			const [current] = history;
+			const previous = history[Math.min(options.back, history.length - 1)];
+			if (!current || !previous || previous === current) {
+				if (!isPorcelain()) {
+					console.log(
+						`No earlier manifest cached for ${language}. Changes are recorded from the next 'claude-cmd cache update' on.`,
+					);
+				}
+				return;
+			}
+
+			const comparison = await manifestComparison.compareManifests(
+				previous.manifest,
+				current.manifest,
+			);
+
+			if (isPorcelain()) {
+				if (comparison.changes.length > 0) {
+					console.log(formatChangesPorcelain(comparison));
+				}
+				return;
+			}
+
+			console.log(
+				`Changes in ${language} commands between ${new Date(previous.timestamp).toLocaleString()} and ${new Date(current.timestamp).toLocaleString()}:\n`,
+			);
+			console.log(changeDisplayFormatter.formatComparisonDetails(comparison));
+		} catch (error) {
+			await handleError(error, "Failed to show manifest changes");
+		}
+	});
+
+inGroup(whatsnewCommand, "Discover");
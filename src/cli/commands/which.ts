@@ -0,0 +1,65 @@
+import * as path from "node:path";
+import { Command } from "commander";
+import { getServices } from "../../services/serviceFactory.js";
+import {
+	handleError,
+	parseInstallLocation,
+	resolveAlias,
+} from "../cliUtils.js";
+import { inGroup } from "../commandGroups.js";
+import { ExitCode } from "../exitCodes.js";
+
+/**
+ * Locations in the order Claude Code resolves a command name
+ */
+const PRECEDENCE = ["project", "personal"] as const;
+
+export const whichCommand = new Command("which")
+	.description(
+		"Print the path of an installed command, the project's copy first. Exits with 1 if it is not installed.",
+	)
+	.argument("<command-name>", "Name or alias of the installed command")
+	.option("-a, --all", "Print every installed copy, in precedence order")
+	.option(
+		"--from <location>",
+		"Only look in 'personal' or 'project'",
+		parseInstallLocation,
+	)
+	.action(async (commandName: string, options) => {
+		try {
+			const { installationService } = getServices();
+			const target = await resolveAlias(commandName);
+			const from = options.from ?? target.location;
+
+			const found: string[] = [];
+			for (const location of PRECEDENCE) {
+				if (from && from !== location) {
+					continue;
+				}
+				const installed = await installationService.findInstalledCommand(
+					target.name,
+					location,
+				);
+				if (installed) {
+					found.push(path.resolve(installed.filePath));
+					if (!options.all) {
+						break;
+					}
+				}
+			}
+
+			if (found.length === 0) {
+				// Like which(1): nothing on stdout, a plain failure status
+				console.error(`${commandName} is not installed`);
+				process.exitCode = ExitCode.Failure;
+				return;
+			}
+			for (const filePath of found) {
+				console.log(filePath);
+			}
+		} catch (error) {
+			await handleError(error, `Failed to locate command '${commandName}'`);
+		}
+	});
+
+inGroup(whichCommand, "Install");
@@ -0,0 +1,81 @@
+import type IFileService from "../interfaces/IFileService.js";
+import type IUserInteractionService from "../interfaces/IUserInteractionService.js";
+import type { Choice } from "../interfaces/IUserInteractionService.js";
+import type { CommandExistsError } from "../types/Installation.js";
+import { formatLineDiff } from "../utils/diff.js";
+import { stripProvenance } from "../utils/frontmatter.js";
+
+/**
+ * How to proceed when installing over an existing command file
+ */
+export type ConflictResolution = "overwrite" | "backup" | "skip";
+
+const CONFLICT_CHOICES: readonly Choice<ConflictResolution | "diff">[] = [
+	{ value: "overwrite", key: "o", label: "overwrite" },
+	{ value: "backup", key: "b", label: "back up and overwrite" },
+	{ value: "diff", key: "d", label: "show diff" },
+	{ value: "skip", key: "s", label: "skip" },
+];
+
+/**
+ * Services the conflict prompt reads from
+ */
+export interface ConflictPromptDependencies {
+	readonly userInteractionService: IUserInteractionService;
+	readonly fileService: IFileService;
+}
+
+/**
+ * Ask how to resolve an install conflict, showing diffs on request
+ *
+ * @param conflict - Error raised for the existing command file
+ * @param loadIncoming - Loads the content that would be installed
+ * @param deps - Prompt and file access
+ * @returns The chosen resolution, or undefined when no terminal is available
+ *   to ask (callers then report the conflict as an error)
+ */
+export async function promptConflictResolution(
+	conflict: CommandExistsError,
+	loadIncoming: () => Promise<string>,
+	deps: ConflictPromptDependencies,
+): Promise<ConflictResolution | undefined> {
+	const message = `'${conflict.commandName}' already exists at ${conflict.existingPath}.`;
+
+	while (true) {
+		const choice = await deps.userInteractionService.chooseOption({
+			message,
+			choices: CONFLICT_CHOICES,
+		});
+		if (choice !== "diff") {
+			return choice;
+		}
+
+		// Provenance fields are ours, not a change worth showing
+		const diff = formatLineDiff(
+			stripProvenance(await deps.fileService.readFile(conflict.existingPath)),
+			await loadIncoming(),
+		);
+		console.log(diff === "" ? "(no differences)" : diff);
+	}
+}
+
+/**
+ * Move an existing command file aside before it is overwritten
+ *
+ * Uses "<file>.bak", or "<file>.bak.N" if earlier backups exist.
+ *
+ * @param fileService - File access
+ * @param filePath - Command file to back up
+ * @returns Path of the backup
+ */
+export async function backupCommandFile(
+	fileService: IFileService,
+	filePath: string,
+): Promise<string> {
+	let backupPath = `${filePath}.bak`;
+	for (let n = 1; await fileService.exists(backupPath); n++) {
+		backupPath = `${filePath}.bak.${n}`;
+	}
+	await fileService.rename(filePath, backupPath);
+	return backupPath;
+}
@@ -0,0 +1,127 @@
+import * as os from "node:os";
+import * as path from "node:path";
+import { getServices } from "../services/serviceFactory.js";
+import { getBuildInfo } from "../utils/buildInfo.js";
+import {
+	describeCrashError,
+	redactArgs,
+	redactConfig,
+	redactText,
+} from "../utils/crashReport.js";
+import { cliLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+
+/**
+ * Number of crash reports kept in the cache directory
+ */
+export const MAX_CRASH_REPORTS = 10;
+
+let version = "0.0.0";
+
+/**
+ * Set the claude-cmd version written to crash reports
+ * Set once from package.json when the CLI starts
+ */
+export function setCrashReportVersion(current: string): void {
+	version = current;
+}
+
+/**
+ * Write an anonymized crash report to the cache directory
+ *
+ * The report holds the command line, build metadata, the effective config
+ * (see redactConfig()), the cache status and the error with its stack.
+ * Reports are opt-in: none is written unless the crashReports config key is
+ * true, or when the config cannot be read. Writing never throws: the crash
+ * being reported matters more than a failure to report it.
+ *
+ * @param error - Error that crashed claude-cmd
+ * @returns Path of the report, or undefined if none was written
+ */
+export async function writeCrashReport(
+	error: unknown,
+): Promise<string | undefined> {
+	try {
+		const { cacheDir, clock, configManager, fileService, statusService } =
+			getServices();
+		const homeDir = os.homedir();
+		const describeFailure = (failure: unknown) =>
+			`unavailable: ${redactText(
+				failure instanceof Error ? failure.message : String(failure),
+				homeDir,
+			)}`;
+
+		const effective = await configManager.getEffectiveConfig();
+		if (effective.crashReports !== true) {
+			return undefined;
+		}
+		const config = redactConfig(effective, homeDir);
+
+		let cache: unknown;
+		try {
+			cache = (await statusService.getSystemStatus()).cache.map((info) => ({
+				language: info.language,
+				exists: info.exists,
+				isExpired: info.isExpired,
+				ageMs: info.ageMs,
+				commandCount: info.commandCount,
+			}));
+		} catch (failure) {
+			cache = describeFailure(failure);
+		}
+
+		const now = clock.now();
+		const report = {
+			time: new Date(now).toISOString(),
+			build: getBuildInfo(version),
+			commandLine: redactArgs(process.argv.slice(2), homeDir),
+			error: describeCrashError(error, homeDir),
+			config,
+			cache,
+		};
+
+		const dir = path.join(cacheDir, "crash-reports");
+		const reportPath = path.join(
+			dir,
+			`crash-${new Date(now).toISOString().replace(/[:.]/g, "-")}.json`,
+		);
+		await fileService.writeFile(reportPath, JSON.stringify(report, null, 2));
+
+		// Timestamped names sort chronologically
+		const reports = (await fileService.listFiles(dir))
+			.map((file) => path.basename(file))
+			.filter((file) => /^crash-.*\.json$/.test(file))
+			.sort(compareStrings);
+		for (const old of reports.slice(0, -MAX_CRASH_REPORTS)) {
+			await fileService.deleteFile(path.join(dir, old));
+		}
+
+		return reportPath;
+	} catch (failure) {
+		cliLogger.debug("failed to write crash report: {error}", {
+			error: failure instanceof Error ? failure.message : String(failure),
+		});
+		return undefined;
+	}
+}
+
+/**
+ * Write a crash report, tell the user where it is and exit
+ *
+ * @param error - Error that crashed claude-cmd
+ * @param exitCode - Code to exit with
+ * @param options.announce - Print the report path on stderr (default: true)
+ */
+export async function exitWithCrashReport(
+	error: unknown,
+	exitCode: number,
+	{ announce = true }: { announce?: boolean } = {},
+): Promise<never> {
+	const reportPath = await writeCrashReport(error);
+	if (reportPath && announce) {
+		console.error(
+			`claude-cmd crashed. A crash report was written to ${reportPath}; please attach it when reporting the bug.`,
+		);
+	}
+	process.exit(exitCode);
+}
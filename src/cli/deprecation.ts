@@ -0,0 +1,206 @@
+import type { Command, Option } from "commander";
+import { compareVersions } from "../utils/versions.js";
+import { handleError } from "./cliUtils.js";
+import { warning } from "./output.js";
+
+/**
+ * Environment variable that turns deprecation warnings into errors
+ */
+export const STRICT_DEPRECATIONS_ENV = "CLAUDE_CMD_STRICT_DEPRECATIONS";
+
+/**
+ * How a deprecated flag or subcommand is being retired
+ */
+export interface DeprecationInfo {
+	/** Version that deprecated it (e.g., "0.2.0") */
+	readonly since: string;
+	/** Version that will remove it (e.g., "1.0.0") */
+	readonly removeIn: string;
+	/** What to use instead (e.g., "claude-cmd config set language") */
+	readonly replacement?: string;
+}
+
+/**
+ * A registered deprecation
+ */
+export interface Deprecation extends DeprecationInfo {
+	/** Usage as typed by users (e.g., "claude-cmd list --format") */
+	readonly usage: string;
+}
+
+/**
+ * Error raised for deprecated usage in strict mode
+ */
+export class DeprecationError extends Error {
+	constructor(
+		message: string,
+		public readonly deprecation: Deprecation,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+const registry: Deprecation[] = [];
+const warned = new Set<string>();
+
+/**
+ * Mark a subcommand as deprecated
+ *
+ * Running it (or any of its subcommands) prints a warning once per
+ * invocation, or fails when strict deprecations are enabled.
+ *
+ * @param command - Subcommand to deprecate
+ * @param info - Versions and replacement hint
+ * @returns The same command, for chaining
+ */
+export function deprecateCommand(
+	command: Command,
+	info: DeprecationInfo,
+): Command {
+	const deprecation = register({ ...info, usage: commandPath(command) });
+	command.description(withNotice(command.description(), deprecation));
+	command.hook("preAction", () => enforce(deprecation));
+	return command;
+}
+
+/**
+ * Mark an option of a command as deprecated
+ *
+ * Only explicit use on the command line is reported; defaults and values
+ * from the environment are not.
+ *
+ * @param command - Command that defines the option
+ * @param flag - Long or short flag (e.g., "--format")
+ * @param info - Versions and replacement hint
+ * @returns The same command, for chaining
+ * @throws Error if the command has no such option
+ */
+export function deprecateOption(
+	command: Command,
+	flag: string,
+	info: DeprecationInfo,
+): Command {
+	const option = command.options.find(
+		(candidate: Option) => candidate.long === flag || candidate.short === flag,
+	);
+	if (!option) {
+		throw new Error(`Unknown option ${flag} for ${commandPath(command)}`);
+	}
+
+	const deprecation = register({
+		...info,
+		usage: `${commandPath(command)} ${option.long ?? flag}`,
+	});
+	option.description = withNotice(option.description, deprecation);
+	command.hook("preAction", async (thisCommand) => {
+		if (thisCommand.getOptionValueSource(option.attributeName()) === "cli") {
+			await enforce(deprecation);
+		}
+	});
+	return command;
+}
+
+/**
+ * Warn about (or, in strict mode, reject) use of a deprecated feature
+ *
+ * Each deprecation is reported at most once per process.
+ *
+ * @param deprecation - Deprecation being used
+ * @param env - Environment to read strict mode from
+ * @throws DeprecationError in strict mode
+ */
+export function reportDeprecation(
+	deprecation: Deprecation,
+	env: NodeJS.ProcessEnv = process.env,
+): void {
+	const message = formatDeprecation(deprecation);
+
+	if (isStrict(env)) {
+		throw new DeprecationError(message, deprecation);
+	}
+
+	if (warned.has(deprecation.usage)) {
+		return;
+	}
+	warned.add(deprecation.usage);
+	warning(message);
+}
+
+/**
+ * Build the user-facing message for a deprecation
+ */
+export function formatDeprecation(deprecation: Deprecation): string {
+	const replacement = deprecation.replacement
+		? ` Use '${deprecation.replacement}' instead.`
+		: "";
+	return `'${deprecation.usage}' is deprecated since v${deprecation.since} and will be removed in v${deprecation.removeIn}.${replacement}`;
+}
+
+/**
+ * List all registered deprecations, e.g. to audit removal versions
+ */
+export function getDeprecations(): readonly Deprecation[] {
+	return registry;
+}
+
+/**
+ * List deprecations that should already have been removed
+ *
+ * @param version - Current claude-cmd version (e.g., "1.0.0")
+ * @returns Deprecations whose removeIn is at or below the version
+ */
+export function getOverdueDeprecations(version: string): Deprecation[] {
+	return registry.filter(
+		(deprecation) => compareVersions(deprecation.removeIn, version) <= 0,
+	);
+}
+
+/**
+ * Forget which deprecations were already reported (for tests)
+ */
+export function resetDeprecationWarnings(): void {
+	warned.clear();
+}
+
+/**
+ * Report a deprecation from a commander hook, exiting cleanly in strict mode
+ */
+async function enforce(deprecation: Deprecation): Promise<void> {
+	try {
+		reportDeprecation(deprecation);
+	} catch (error) {
+		await handleError(error, "Error: deprecated usage");
+	}
+}
+
+function register(deprecation: Deprecation): Deprecation {
+	registry.push(deprecation);
+	return deprecation;
+}
+
+function isStrict(env: NodeJS.ProcessEnv): boolean {
+	const value = env[STRICT_DEPRECATIONS_ENV]?.toLowerCase();
+	return value === "1" || value === "true";
+}
+
+function withNotice(description: string, deprecation: Deprecation): string {
+	const hint = deprecation.replacement
+		? `, use '${deprecation.replacement}'`
+		: "";
+	return `${description} (deprecated${hint})`;
+}
+
+function commandPath(command: Command): string {
+	const names: string[] = [];
+	let current: Command | null = command;
+	while (current) {
+		names.unshift(current.name());
+		current = current.parent;
+	}
+	// Subcommands are often deprecated before being attached to the program
+	if (names[0] !== "claude-cmd") {
+		names.unshift("claude-cmd");
+	}
+	return names.join(" ");
+}
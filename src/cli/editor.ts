@@ -0,0 +1,36 @@
+/**
+ * Resolve the user's editor command from the environment
+ *
+ * $VISUAL wins over $EDITOR, as in git and most Unix tools. The value is split
+ * on whitespace so settings such as "code --wait" work.
+ *
+ * @param env - Environment to read (defaults to process.env)
+ * @param platform - Platform used to pick the fallback editor
+ * @returns Editor executable followed by its arguments
+ */
+export function resolveEditor(
+	env: NodeJS.ProcessEnv = process.env,
+	platform: NodeJS.Platform = process.platform,
+): string[] {
+	const configured = (env.VISUAL || env.EDITOR || "").trim();
+	if (configured) {
+		return configured.split(/\s+/);
+	}
+	return [platform === "win32" ? "notepad" : "vi"];
+}
+
+/**
+ * Open a file in the user's editor and wait for it to close
+ *
+ * @param filePath - File to edit
+ * @returns Exit code of the editor process
+ */
+export async function openInEditor(filePath: string): Promise<number> {
+	const [editor, ...args] = resolveEditor();
+	const proc = Bun.spawn([editor ?? "vi", ...args, filePath], {
+		stdin: "inherit",
+		stdout: "inherit",
+		stderr: "inherit",
+	});
+	return await proc.exited;
+}
@@ -0,0 +1,40 @@
+import type { EventBus } from "../services/EventBus.js";
+
+let jsonEvents = false;
+
+/**
+ * Stream --json-events are written to
+ */
+export interface EventStream {
+	write(chunk: string): unknown;
+}
+
+/**
+ * Print every event of a bus as a line of JSON (--json-events)
+ *
+ * Events go to stderr so results on stdout stay parseable on their own.
+ *
+ * @param bus - Bus to subscribe to
+ * @param stream - Stream to write to (default: stderr)
+ * @returns Function that stops printing
+ */
+export function enableJsonEvents(
+	bus: EventBus,
+	stream: EventStream = process.stderr,
+): () => void {
+	jsonEvents = true;
+	const unsubscribe = bus.subscribe((event) => {
+		stream.write(`${JSON.stringify(event)}\n`);
+	});
+	return () => {
+		jsonEvents = false;
+		unsubscribe();
+	};
+}
+
+/**
+ * Whether events are printed as JSON lines
+ */
+export function jsonEventsEnabled(): boolean {
+	return jsonEvents;
+}
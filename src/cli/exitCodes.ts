@@ -0,0 +1,108 @@
+import { InvalidConfigError } from "../interfaces/IConfigService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { GitError } from "../interfaces/IGitClient.js";
+import { HTTPError } from "../interfaces/IHTTPClient.js";
+import { NamespaceError } from "../interfaces/INamespaceService.js";
+import { CacheError } from "../services/CacheManager.js";
+import { ManifestSchemaError } from "../services/ManifestMigrations.js";
+import {
+	InvalidLanguageCodeError,
+	InvalidLocaleError,
+} from "../services/LanguageDetector.js";
+import { RemoteCommandError } from "../services/RemoteCommandSource.js";
+import {
+	AmbiguousCommandError,
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+	ManifestSignatureError,
+} from "../types/Command.js";
+import { CommandNotInstalledError } from "../types/Installation.js";
+import { InvalidNameError } from "../utils/naming.js";
+import { UnsafeCommandNameError } from "../utils/namespace.js";
+import { TemplateError } from "../utils/template.js";
+import { StdinError } from "./stdin.js";
+
+/**
+ * Process exit codes, stable across releases so scripts can branch on them
+ *
+ * | Code | Meaning                                               |
+ * | ---- | ----------------------------------------------------- |
+ * | 0    | Success                                               |
+ * | 1    | Any other failure                                     |
+ * | 2    | Command, file or installation not found               |
+ * | 3    | Network or repository unreachable                     |
+ * | 4    | Local cache unreadable or corrupted                   |
+ * | 5    | Invalid input (names, language codes, option values)  |
+ */
+export const ExitCode = {
+	Success: 0,
+	Failure: 1,
+	NotFound: 2,
+	Network: 3,
+	CacheCorrupted: 4,
+	Validation: 5,
+} as const;
+
+export type ExitCode = (typeof ExitCode)[keyof typeof ExitCode];
+
+/**
+ * Map an error to the exit code describing its category
+ *
+ * Wrapper errors (e.g., CommandServiceError) are classified by their cause.
+ *
+ * @param error - Error thrown by a command handler
+ * @returns Exit code for the error category
+ */
+export function exitCodeForError(error: unknown): ExitCode {
+	if (
+		error instanceof CommandNotFoundError ||
+		error instanceof CommandNotInstalledError ||
+		error instanceof FileNotFoundError
+	) {
+		return ExitCode.NotFound;
+	}
+
+	// A manifest too new for this release or failing signature verification
+	// is reachable, just unusable
+	if (
+		error instanceof ManifestSchemaError ||
+		error instanceof ManifestSignatureError
+	) {
+		return ExitCode.Failure;
+	}
+
+	if (
+		error instanceof HTTPError ||
+		error instanceof ManifestError ||
+		error instanceof CommandContentError ||
+		error instanceof GitError
+	) {
+		return ExitCode.Network;
+	}
+
+	if (error instanceof CacheError) {
+		return ExitCode.CacheCorrupted;
+	}
+
+	if (
+		error instanceof InvalidNameError ||
+		error instanceof UnsafeCommandNameError ||
+		error instanceof InvalidLanguageCodeError ||
+		error instanceof InvalidLocaleError ||
+		error instanceof NamespaceError ||
+		error instanceof InvalidConfigError ||
+		error instanceof RemoteCommandError ||
+		error instanceof AmbiguousCommandError ||
+		error instanceof StdinError ||
+		error instanceof TemplateError
+	) {
+		return ExitCode.Validation;
+	}
+
+	if (error instanceof Error && error.cause instanceof Error) {
+		return exitCodeForError(error.cause);
+	}
+
+	return ExitCode.Failure;
+}
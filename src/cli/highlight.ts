@@ -0,0 +1,61 @@
+/**
+ * Lightweight ANSI highlighting for command files on terminals
+ *
+ * Only the structure that matters when reviewing a command is colored:
+ * frontmatter, headings, fenced code and inline code.
+ */
+
+const RESET = "\x1b[0m";
+const DIM = "\x1b[2m";
+const BOLD_CYAN = "\x1b[1;36m";
+const YELLOW = "\x1b[33m";
+const GREEN = "\x1b[32m";
+
+/**
+ * Add ANSI colors to a markdown command file
+ *
+ * @param content - Command file content
+ * @returns Content with escape sequences for terminal display
+ */
+export function highlightMarkdown(content: string): string {
+	const lines = content.split("\n");
+	let inFrontmatter = lines[0]?.trim() === "---";
+	let inFence = false;
+
+	return lines
+		.map((line, index) => {
+			if (inFrontmatter) {
+				if (index > 0 && line.trim() === "---") {
+					inFrontmatter = false;
+				}
+				const key = line.match(/^([\w-]+):(.*)$/);
+				return key
+					? `${YELLOW}${key[1]}${RESET}:${key[2]}`
+					: `${DIM}${line}${RESET}`;
+			}
+			if (/^\s*(```|~~~)/.test(line)) {
+				inFence = !inFence;
+				return `${DIM}${line}${RESET}`;
+			}
+			if (inFence) {
+				return `${GREEN}${line}${RESET}`;
+			}
+			if (/^#{1,6}\s/.test(line)) {
+				return `${BOLD_CYAN}${line}${RESET}`;
+			}
+			return line.replace(/`[^`]+`/g, (code) => `${GREEN}${code}${RESET}`);
+		})
+		.join("\n");
+}
+
+/**
+ * Check whether colored output is appropriate for a stream
+ *
+ * Honors the NO_COLOR convention (https://no-color.org).
+ */
+export function shouldColorize(
+	stream: { readonly isTTY?: boolean },
+	env: NodeJS.ProcessEnv = process.env,
+): boolean {
+	return Boolean(stream.isTTY) && !env.NO_COLOR;
+}
@@ -0,0 +1,133 @@
+import * as path from "node:path";
+import type { Choice } from "../interfaces/IUserInteractionService.js";
+import {
+	type HookContext,
+	HookError,
+	HookRunner,
+} from "../services/HookRunner.js";
+import { getServices } from "../services/serviceFactory.js";
+import {
+	HOOK_EVENTS,
+	type HookCommands,
+	type HookEvent,
+	hookCommandsFor,
+	hooksFingerprint,
+	isHookTrust,
+} from "../utils/hooks.js";
+import { isPorcelain } from "./cliUtils.js";
+import { warning } from "./output.js";
+
+const TRUST_CHOICES: readonly Choice<"allow" | "deny">[] = [
+	{ value: "allow", key: "y", label: "run them" },
+	{ value: "deny", key: "n", label: "skip them" },
+];
+
+/**
+ * Whether the project's hooks may run, once decided in this process
+ */
+let trusted: boolean | undefined;
+
+/**
+ * Run the project hooks configured for an event
+ *
+ * Hooks come from the project config and only run once the user has allowed
+ * them for this project (see trustProjectHooks()); a project cannot allow its
+ * own hooks.
+ *
+ * A failing pre- hook aborts the operation; a failing post- hook only warns,
+ * since the operation already happened.
+ *
+ * @param event - Event about to happen or just finished
+ * @param context - Operation details passed to the hooks
+ * @throws HookError when a pre- hook fails
+ */
+export async function runHooks(
+	event: HookEvent,
+	context: HookContext = {},
+): Promise<void> {
+	const { configManager, projectConfigService, projectRoot } = getServices();
+
+	const configured = (await projectConfigService.getConfig())?.hooks;
+	const hooks = hookCommandsFor(configured, event);
+	if (!configured || hooks.length === 0) {
+		return;
+	}
+
+	trusted ??= await trustProjectHooks(projectRoot, configured);
+	if (!trusted) {
+		return;
+	}
+
+	const { hookTimeoutSeconds } = await configManager.getEffectiveConfig();
+	const runner = new HookRunner(
+		projectRoot,
+		hookTimeoutSeconds !== undefined ? hookTimeoutSeconds * 1000 : undefined,
+	);
+	try {
+		await runner.run(event, hooks, context);
+	} catch (error) {
+		if (!(error instanceof HookError) || event.startsWith("pre-")) {
+			throw error;
+		}
+		warning(error.message);
+	}
+}
+
+/**
+ * Ask whether a project's hooks may run
+ *
+ * The answer is remembered in the user config by project root, together
+ * with a fingerprint of the hooks, so moving the project or changing any of
+ * its hooks asks again. Without a terminal to ask, hooks that were not
+ * allowed before are skipped with a note; --yes does not allow them.
+ *
+ * @param projectRoot - Root of the project the hooks are configured in
+ * @param hooks - The project's `hooks` config value
+ * @returns True if the hooks may run
+ */
+async function trustProjectHooks(
+	projectRoot: string,
+	hooks: Partial<Record<HookEvent, HookCommands>>,
+): Promise<boolean> {
+	const { userConfigService, userInteractionService } = getServices();
+	const root = path.resolve(projectRoot);
+	const fingerprint = hooksFingerprint(hooks);
+	const config = (await userConfigService.getConfig()) ?? {};
+	const remembered = config.projectHookTrust?.[root];
+	if (isHookTrust(remembered) && remembered.fingerprint === fingerprint) {
+		return remembered.allow;
+	}
+
+	if (isPorcelain()) {
+		return false;
+	}
+	console.log(
+		remembered
+			? `The hooks of ${root} changed:`
+			: `${root} configures hooks that run shell commands:`,
+	);
+	for (const event of HOOK_EVENTS) {
+		for (const command of hookCommandsFor(hooks, event)) {
+			console.log(`  ${event}: ${command}`);
+		}
+	}
+	const choice = await userInteractionService.chooseOption({
+		message: "Run them? Your answer is remembered until the hooks change.",
+		choices: TRUST_CHOICES,
+	});
+	if (choice === undefined) {
+		warning(
+			"Skipping project hooks. Run claude-cmd on a terminal to review and allow them.",
+		);
+		return false;
+	}
+
+	await userConfigService.setConfig({
+		...config,
+		projectHookTrust: {
+			...config.projectHookTrust,
+			[root]: { allow: choice === "allow", fingerprint },
+		},
+	});
+	return choice === "allow";
+}
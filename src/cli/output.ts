@@ -0,0 +1,108 @@
+import {
+	COLOR_THEME,
+	type ColorMode,
+	PLAIN_THEME,
+	type Theme,
+} from "../utils/style.js";
+import { shouldColorize } from "./highlight.js";
+
+let quiet = false;
+let colorMode: ColorMode = "auto";
+
+/**
+ * Configure user-facing output for the current invocation
+ * Set once from the global --quiet, --no-color and --porcelain flags and the
+ * outputColor setting before a command runs
+ *
+ * @param options.quiet - Suppress progress, confirmations and hints
+ * @param options.color - When to color output (default: auto)
+ */
+export function configureOutput(options: {
+	quiet?: boolean;
+	color?: ColorMode;
+}): void {
+	quiet = options.quiet ?? false;
+	colorMode = options.color ?? "auto";
+}
+
+/**
+ * Check whether chatty output (progress, confirmations, hints) is suppressed
+ */
+export function isQuiet(): boolean {
+	return quiet;
+}
+
+/**
+ * Check whether output to a stream may carry ANSI colors
+ *
+ * In auto mode colors need a terminal and are off with NO_COLOR; "always"
+ * and "never" ignore both.
+ *
+ * @param stream - Stream the output goes to (default: stdout)
+ */
+export function colorEnabled(
+	stream: { readonly isTTY?: boolean } = process.stdout,
+	env: NodeJS.ProcessEnv = process.env,
+): boolean {
+	if (colorMode !== "auto") {
+		return colorMode === "always";
+	}
+	return shouldColorize(stream, env);
+}
+
+/**
+ * Get the theme for output to a stream
+ *
+ * @param stream - Stream the output goes to (default: stdout)
+ * @returns The color theme when colors are enabled, else the plain one
+ */
+export function getTheme(
+	stream: { readonly isTTY?: boolean } = process.stdout,
+): Theme {
+	return colorEnabled(stream) ? COLOR_THEME : PLAIN_THEME;
+}
+
+/**
+ * Print a confirmation such as "✓ Installed debug-help"
+ *
+ * The check mark is green on color terminals and plain otherwise; nothing is
+ * printed with --quiet.
+ *
+ * @param message - What was done, without the check mark
+ */
+export function success(message: string): void {
+	if (quiet) {
+		return;
+	}
+	console.log(`${getTheme().success("✓")} ${message}`);
+}
+
+/**
+ * Print a hint for people at a terminal (welcome messages, suggestions)
+ *
+ * Hints go to stderr and are skipped with --quiet and when stderr is not a
+ * terminal, so scripted runs never see them.
+ *
+ * @param message - Hint to print
+ */
+export function notice(message: string): void {
+	if (isQuiet() || !process.stderr.isTTY) {
+		return;
+	}
+	console.error(message);
+}
+
+/**
+ * Print a warning such as "Warning: editor exited with code 1"
+ *
+ * Warnings go to stderr with the label in yellow on color terminals; nothing
+ * is printed with --quiet.
+ *
+ * @param message - What went wrong, without the label
+ */
+export function warning(message: string): void {
+	if (quiet) {
+		return;
+	}
+	console.warn(`${getTheme(process.stderr).warning("Warning:")} ${message}`);
+}
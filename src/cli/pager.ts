@@ -0,0 +1,50 @@
+import { cliLogger } from "../utils/logger.js";
+
+/**
+ * Resolve the pager command from the environment
+ *
+ * @param env - Environment to read (defaults to process.env)
+ * @returns Pager executable followed by its arguments
+ */
+export function resolvePager(env: NodeJS.ProcessEnv = process.env): string[] {
+	const configured = (env.PAGER || "").trim();
+	return configured ? configured.split(/\s+/) : ["less"];
+}
+
+/**
+ * Print text, through the user's pager when stdout is a terminal
+ *
+ * less is run with LESS=FRX unless LESS is already set, so short output is
+ * printed directly and colors pass through. If the pager cannot be started
+ * the text is printed as usual.
+ *
+ * @param text - Text to display
+ * @param usePager - Set to false to never page (--no-pager)
+ */
+export async function printWithPager(
+	text: string,
+	usePager = true,
+): Promise<void> {
+	if (!usePager || !process.stdout.isTTY) {
+		console.log(text);
+		return;
+	}
+
+	const [pager, ...args] = resolvePager();
+	try {
+		const proc = Bun.spawn([pager ?? "less", ...args], {
+			stdin: "pipe",
+			stdout: "inherit",
+			stderr: "inherit",
+			env: { LESS: "FRX", ...process.env },
+		});
+		proc.stdin.write(`${text}\n`);
+		await proc.stdin.end();
+		await proc.exited;
+	} catch (error) {
+		cliLogger.debug("pager unavailable, printing directly: {error}", {
+			error: error instanceof Error ? error.message : String(error),
+		});
+		console.log(text);
+	}
+}
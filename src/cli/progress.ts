@@ -0,0 +1,198 @@
+import type IClock from "../interfaces/IClock.js";
+import type IProgressReporter from "../interfaces/IProgressReporter.js";
+import type { EventBus } from "../services/EventBus.js";
+import SystemClock from "../services/SystemClock.js";
+import { formatDuration, formatFileSize } from "../utils/format.js";
+
+/**
+ * Minimal writable terminal stream used for progress output
+ */
+export interface ProgressStream {
+	readonly isTTY?: boolean;
+	readonly columns?: number;
+	write(chunk: string): unknown;
+}
+
+/**
+ * Progress reporter that discards everything (quiet mode, pipes, CI logs)
+ */
+export class SilentProgressReporter implements IProgressReporter {
+	start(): void {}
+	advance(): void {}
+	finish(): void {}
+}
+
+/**
+ * Progress reporter that publishes fetch_started, download_progress and
+ * fetch_finished events instead of drawing (--json-events)
+ */
+export class EventProgressReporter implements IProgressReporter {
+	private label: string | undefined;
+	private total: number | undefined;
+	private steps = 0;
+	private bytes = 0;
+
+	constructor(private readonly events: EventBus) {}
+
+	start(label: string, total?: number): void {
+		this.finish();
+		this.label = label;
+		this.total = total;
+		this.steps = 0;
+		this.bytes = 0;
+		this.events.emit({ type: "fetch_started", label, total });
+	}
+
+	advance(steps = 1, bytes = 0): void {
+		if (this.label === undefined) {
+			return;
+		}
+		this.steps += steps;
+		this.bytes += bytes;
+		this.events.emit({
+			type: "download_progress",
+			label: this.label,
+			steps: this.steps,
+			total: this.total,
+			bytes: this.bytes,
+		});
+	}
+
+	finish(): void {
+		if (this.label === undefined) {
+			return;
+		}
+		this.events.emit({
+			type: "fetch_finished",
+			label: this.label,
+			steps: this.steps,
+			bytes: this.bytes,
+		});
+		this.label = undefined;
+	}
+}
+
+const BAR_WIDTH = 20;
+const RENDER_INTERVAL_MS = 100;
+const SPINNER = ["⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"];
+
+/**
+ * Progress reporter that redraws a single status line on a terminal
+ *
+ * Shows a bar, step count, downloaded bytes and ETA for tasks with a known
+ * size, and a spinner with elapsed time otherwise. The line is redrawn on a
+ * timer so slow downloads visibly keep going instead of looking hung.
+ */
+export class TerminalProgressReporter implements IProgressReporter {
+	private label = "";
+	private total: number | undefined;
+	private steps = 0;
+	private bytes = 0;
+	private startedAt = 0;
+	private frame = 0;
+	private timer: ReturnType<typeof setInterval> | undefined;
+
+	constructor(
+		private readonly stream: ProgressStream,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	start(label: string, total?: number): void {
+		this.finish();
+		this.label = label;
+		this.total = total;
+		this.steps = 0;
+		this.bytes = 0;
+		this.startedAt = this.clock.now();
+		this.render();
+
+		this.timer = setInterval(() => this.render(), RENDER_INTERVAL_MS);
+		// Never keep the process alive just to animate
+		this.timer.unref?.();
+	}
+
+	advance(steps = 1, bytes = 0): void {
+		this.steps += steps;
+		this.bytes += bytes;
+		this.render();
+	}
+
+	finish(): void {
+		if (this.timer === undefined) {
+			return;
+		}
+		clearInterval(this.timer);
+		this.timer = undefined;
+		this.stream.write("\r\x1b[2K");
+	}
+
+	/**
+	 * Build the status line for the current state
+	 */
+	formatLine(): string {
+		const elapsed = this.clock.now() - this.startedAt;
+		const parts = [this.label];
+
+		if (this.total !== undefined && this.total > 0) {
+			const ratio = Math.min(1, this.steps / this.total);
+			const filled = Math.round(ratio * BAR_WIDTH);
+			parts.push(
+				`[${"█".repeat(filled)}${"░".repeat(BAR_WIDTH - filled)}]`,
+				`${this.steps}/${this.total}`,
+			);
+		} else {
+			parts.push(SPINNER[this.frame % SPINNER.length] ?? "");
+		}
+
+		if (this.bytes > 0) {
+			parts.push(formatFileSize(this.bytes));
+		}
+
+		const eta = this.estimateRemaining(elapsed);
+		parts.push(
+			eta === undefined
+				? formatDuration(elapsed)
+				: `ETA ${formatDuration(eta)}`,
+		);
+
+		return parts.join("  ");
+	}
+
+	private estimateRemaining(elapsed: number): number | undefined {
+		if (!this.total || this.steps === 0 || this.steps >= this.total) {
+			return undefined;
+		}
+		return (elapsed / this.steps) * (this.total - this.steps);
+	}
+
+	private render(): void {
+		this.frame++;
+		let line = this.formatLine();
+		const columns = this.stream.columns;
+		if (columns && line.length >= columns) {
+			line = line.slice(0, columns - 1);
+		}
+		this.stream.write(`\r\x1b[2K${line}`);
+	}
+}
+
+/**
+ * Pick the progress reporter for the current output
+ *
+ * Progress is only drawn on interactive terminals and never with --quiet,
+ * so redirected output and CI logs stay clean.
+ *
+ * @param options.quiet - Suppress progress output
+ * @param options.stream - Stream to draw on (default: stderr)
+ * @returns A reporter suitable for the stream
+ */
+export function createProgressReporter(options: {
+	quiet?: boolean;
+	stream?: ProgressStream;
+}): IProgressReporter {
+	const stream = options.stream ?? process.stderr;
+	if (options.quiet || !stream.isTTY) {
+		return new SilentProgressReporter();
+	}
+	return new TerminalProgressReporter(stream);
+}
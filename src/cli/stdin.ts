@@ -0,0 +1,53 @@
+/**
+ * Error thrown when stdin cannot provide the input a command expects
+ */
+export class StdinError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Read a stream to the end as UTF-8 text
+ *
+ * @param stream - Stream to read (e.g., process.stdin)
+ * @param maxBytes - Largest input accepted
+ * @throws StdinError if the input is empty or larger than maxBytes
+ */
+export async function readStream(
+	stream: AsyncIterable<Uint8Array | string>,
+	maxBytes: number,
+): Promise<string> {
+	const chunks: Buffer[] = [];
+	let size = 0;
+	for await (const chunk of stream) {
+		const buffer = typeof chunk === "string" ? Buffer.from(chunk) : chunk;
+		size += buffer.length;
+		if (size > maxBytes) {
+			throw new StdinError(`Input is larger than ${maxBytes} bytes`);
+		}
+		chunks.push(Buffer.from(buffer));
+	}
+
+	const text = Buffer.concat(chunks).toString("utf8");
+	if (text.trim() === "") {
+		throw new StdinError("No input on stdin");
+	}
+	return text;
+}
+
+/**
+ * Read all of stdin, refusing to wait for input typed on a terminal
+ *
+ * @param maxBytes - Largest input accepted
+ * @throws StdinError if stdin is a terminal, empty or too large
+ */
+export async function readStdin(maxBytes: number): Promise<string> {
+	if (process.stdin.isTTY) {
+		throw new StdinError(
+			"stdin is a terminal; pipe the command file in (e.g., cat cmd.md | claude-cmd add my-command -)",
+		);
+	}
+	return readStream(process.stdin, maxBytes);
+}
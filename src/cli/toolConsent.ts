@@ -0,0 +1,102 @@
+import type { Config, IConfigService } from "../interfaces/IConfigService.js";
+import type IUserInteractionService from "../interfaces/IUserInteractionService.js";
+import type { Choice } from "../interfaces/IUserInteractionService.js";
+import type { CommandParser } from "../services/CommandParser.js";
+import {
+	decisionCovers,
+	isToolDecision,
+	powerfulTools,
+} from "../utils/toolConsent.js";
+import { ExitCode } from "./exitCodes.js";
+import { warning } from "./output.js";
+
+const CONSENT_CHOICES: readonly Choice<"allow" | "deny">[] = [
+	{ value: "allow", key: "y", label: "install" },
+	{ value: "deny", key: "n", label: "skip" },
+];
+
+/**
+ * Services the allowed-tools review reads from
+ */
+export interface ToolConsentDependencies {
+	readonly commandParser: CommandParser;
+	readonly userInteractionService: IUserInteractionService;
+	/** Config holding remembered decisions (the user config) */
+	readonly configService: IConfigService;
+}
+
+/**
+ * Ask before installing a command that requests powerful tools
+ *
+ * Commands whose allowed-tools include Bash or unrestricted editing tools are
+ * summarized and the user is asked once per command; the answer is remembered
+ * in the user config and reused until the command requests more. Without a
+ * terminal to ask, such commands are only installed with --yes; otherwise
+ * they are skipped and the exit code reports the failure.
+ *
+ * @param commandName - Command as named in the repository
+ * @param content - Command file that would be installed
+ * @param yes - Whether --yes was given
+ * @param deps - Parsing, prompt and config access
+ * @returns True if the command may be installed
+ */
+export async function confirmToolUse(
+	commandName: string,
+	content: string,
+	yes: boolean,
+	deps: ToolConsentDependencies,
+): Promise<boolean> {
+	let requested: string[] | string;
+	try {
+		const command = await deps.commandParser.parseCommandFile(
+			content,
+			commandName,
+		);
+		requested = command["allowed-tools"];
+	} catch {
+		// Invalid files are rejected by installCommand with a better message
+		return true;
+	}
+	const tools = powerfulTools(
+		Array.isArray(requested) ? requested : [requested],
+	);
+	if (tools.length === 0 || yes) {
+		return true;
+	}
+
+	const config: Config = (await deps.configService.getConfig()) ?? {};
+	const remembered = config.toolConsent?.[commandName];
+	if (isToolDecision(remembered) && decisionCovers(remembered, tools)) {
+		if (!remembered.allow) {
+			warning(
+				`Skipped ${commandName}: its tools were declined before (${tools.join(", ")})`,
+			);
+		}
+		return remembered.allow;
+	}
+
+	console.log(`${commandName} requests powerful tools:`);
+	for (const tool of tools) {
+		console.log(`  ${tool}`);
+	}
+	const choice = await deps.userInteractionService.chooseOption({
+		message: "Install it? Your answer is remembered for this command.",
+		choices: CONSENT_CHOICES,
+	});
+	if (choice === undefined) {
+		warning(
+			`Skipped ${commandName}: it requests ${tools.join(", ")}; install it on a terminal to review them, or pass --yes`,
+		);
+		process.exitCode = ExitCode.Failure;
+		return false;
+	}
+
+	await deps.configService.setConfig({
+		...config,
+		toolConsent: {
+			...config.toolConsent,
+			[commandName]: { allow: choice === "allow", tools },
+		},
+	});
+	return choice === "allow";
+}
@@ -0,0 +1,214 @@
+/**
+ * Library entry point for embedding claude-cmd in other tools
+ *
+ * Editors and bots can list, search and install commands without spawning
+ * the CLI. The client resolves the language and configuration the same way
+ * the CLI does, so both see the same repository, cache and install targets.
+ */
+
+import type { Config } from "./interfaces/IConfigService.js";
+import type { EventListener } from "./services/EventBus.js";
+import HTTPRepository from "./services/HTTPRepository.js";
+import {
+	type CoreDependencies,
+	createServices,
+	type Services,
+} from "./services/serviceFactory.js";
+import type {
+	Command,
+	CommandFilterOptions,
+	CommandSearchOptions,
+	CommandServiceOptions,
+} from "./types/Command.js";
+import type { InstallOptions, RemoveOptions } from "./types/Installation.js";
+
+/**
+ * Options for ClaudeCmdClient.install()
+ */
+export interface ClientInstallOptions {
+	/** Target directory (default: defaultTarget setting, else personal) */
+	readonly target?: "personal" | "project";
+	/** Overwrite an existing command */
+	readonly force?: boolean;
+	/** Language to install (default: the effective language) */
+	readonly language?: string;
+	/** Local name to install under (defaults to the command name) */
+	readonly installAs?: string;
+	/** Exact repository version to install; pins the command to it */
+	readonly version?: string;
+}
+
+/**
+ * Installation options with the language and target resolved
+ */
+export type ResolvedInstallOptions = InstallOptions &
+	Required<Pick<InstallOptions, "language" | "target">>;
+
+/**
+ * Client over the claude-cmd service graph
+ *
+ * Every call resolves the effective language (CLAUDE_CMD_LANG, project and
+ * user config, then the system locale) unless a language is passed.
+ * Interactive concerns (prompts, tool consent, hooks, progress) are left to
+ * the caller.
+ */
+export class ClaudeCmdClient {
+	/**
+	 * @param services - Service graph to use (see createServices())
+	 */
+	constructor(readonly services: Services) {}
+
+	/**
+	 * Create a client with its own service graph
+	 *
+	 * @param overrides - Dependencies to use instead of the production defaults
+	 *   (e.g., another projectRoot or an in-memory file service)
+	 */
+	static create(overrides: Partial<CoreDependencies> = {}): ClaudeCmdClient {
+		return new ClaudeCmdClient(createServices(overrides));
+	}
+
+	/**
+	 * Receive progress, installation and error events
+	 *
+	 * @returns Function that stops delivery to the listener
+	 */
+	onEvent(listener: EventListener): () => void {
+		return this.services.eventBus.subscribe(listener);
+	}
+
+	/**
+	 * Get the language commands are read in when none is given
+	 */
+	async getLanguage(): Promise<string> {
+		return this.services.configManager.getEffectiveLanguage();
+	}
+
+	/**
+	 * Get the effective (project over user) configuration
+	 */
+	async getConfig(): Promise<Config> {
+		return this.services.configManager.getEffectiveConfig();
+	}
+
+	/**
+	 * List the repository's commands in canonical order
+	 *
+	 * @param options - Language, tag filter and cache control
+	 */
+	async listCommands(
+		options: CommandFilterOptions = {},
+	): Promise<readonly Command[]> {
+		return this.services.commandQueryService.listCommands(
+			await this.withLanguage(options),
+		);
+	}
+
+	/**
+	 * Search the repository's commands by name, description or tag
+	 *
+	 * @param query - Text to search for (case-insensitive)
+	 * @param options - Language, tag filter, content search and cache control
+	 */
+	async searchCommands(
+		query: string,
+		options: CommandSearchOptions = {},
+	): Promise<readonly Command[]> {
+		return this.services.commandQueryService.searchCommands(
+			query,
+			await this.withLanguage(options),
+		);
+	}
+
+	/**
+	 * Get a repository command's manifest entry
+	 *
+	 * @throws CommandNotFoundError if the repository has no such command
+	 */
+	async getCommand(
+		commandName: string,
+		options: CommandServiceOptions = {},
+	): Promise<Command> {
+		return this.services.commandQueryService.getCommandInfo(
+			commandName,
+			await this.withLanguage(options),
+		);
+	}
+
+	/**
+	 * Fill in the defaults the CLI applies to an installation
+	 *
+	 * @param options - Options given by the caller
+	 * @returns Options with language, target and provenance resolved
+	 */
+	async resolveInstallOptions(
+		options: ClientInstallOptions = {},
+	): Promise<ResolvedInstallOptions> {
+		const config = await this.getConfig();
+		return {
+			...options,
+			language: options.language || (await this.getLanguage()),
+			target: options.target || config.defaultTarget || "personal",
+			provenanceSource: config.recordProvenance
+				? config.repositoryURL || HTTPRepository.BASE_URL
+				: undefined,
+		};
+	}
+
+	/**
+	 * Install a command from the repository
+	 *
+	 * The installation is recorded in history, so `claude-cmd undo` can
+	 * revert it.
+	 *
+	 * @returns The options the command was installed with
+	 * @throws CommandExistsError if the command exists and force is not set
+	 * @throws InstallationError if the command cannot be downloaded or written
+	 */
+	async install(
+		commandName: string,
+		options: ClientInstallOptions = {},
+	): Promise<ResolvedInstallOptions> {
+		const installOptions = await this.resolveInstallOptions(options);
+		await this.services.operationHistory.batch(`add ${commandName}`, () =>
+			this.services.installationService.installCommand(
+				commandName,
+				installOptions,
+			),
+		);
+		return installOptions;
+	}
+
+	/**
+	 * Remove an installed command (to the trash unless purge is set)
+	 */
+	async remove(
+		commandName: string,
+		options: RemoveOptions = {},
+	): Promise<void> {
+		await this.services.operationHistory.batch(`remove ${commandName}`, () =>
+			this.services.installationService.removeCommand(commandName, {
+				yes: true,
+				...options,
+			}),
+		);
+	}
+
+	/**
+	 * List the commands installed in the personal and project directories
+	 */
+	async listInstalled(): Promise<readonly Command[]> {
+		return this.services.installationService.listInstalledCommands();
+	}
+
+	/**
+	 * Default the language of an option set to the effective language
+	 */
+	private async withLanguage<T extends CommandServiceOptions>(
+		options: T,
+	): Promise<T> {
+		return options.language
+			? options
+			: { ...options, language: await this.getLanguage() };
+	}
+}
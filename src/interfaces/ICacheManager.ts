@@ -0,0 +1,127 @@
+import type { Manifest } from "../types/Command.js";
+
+/**
+ * Health of a cached manifest file
+ *
+ * - valid: parseable and within max age
+ * - expired: parseable but older than max age
+ * - empty: cleared with clear()
+ * - corrupted: unparseable or not a manifest
+ */
+export type CacheStatus = "valid" | "expired" | "empty" | "corrupted";
+
+/**
+ * Details about the cached manifest of one language
+ */
+export interface CacheInspection {
+	/** Language code */
+	readonly language: string;
+	/** Cache file path */
+	readonly path: string;
+	/** Cache file size in bytes */
+	readonly sizeBytes: number;
+	/** Cache file health */
+	readonly status: CacheStatus;
+	/** When the manifest was cached (valid and expired entries only) */
+	readonly timestamp?: number;
+	/** Number of cached commands (valid and expired entries only) */
+	readonly commandCount?: number;
+}
+
+/**
+ * A manifest as it was cached at some point
+ */
+export interface CachedManifest {
+	/** The cached manifest */
+	readonly manifest: Manifest;
+	/** When it was cached (milliseconds since Unix epoch) */
+	readonly timestamp: number;
+}
+
+/**
+ * Language-specific cache of command manifests
+ *
+ * Shared contract of the manifest cache so services and test doubles depend
+ * on the same surface instead of the concrete file-based implementation.
+ */
+export default interface ICacheManager {
+	/**
+	 * Retrieve cached manifest for a specific language
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Cached manifest if exists and not expired, null otherwise
+	 */
+	get(language: string): Promise<Manifest | null>;
+
+	/**
+	 * Store manifest in cache for a specific language
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @param manifest - Manifest to cache
+	 * @param timestamp - Optional timestamp, defaults to current time
+	 */
+	set(language: string, manifest: Manifest, timestamp?: number): Promise<void>;
+
+	/**
+	 * Check if cached manifest for a language is expired
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @param maxAge - Optional custom maximum age in milliseconds
+	 * @returns True if cache doesn't exist or is expired, false otherwise
+	 */
+	isExpired(language: string, maxAge?: number): Promise<boolean>;
+
+	/**
+	 * Empty the cached manifest of a language so it reads as cleared
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 */
+	clear(language: string): Promise<void>;
+
+	/**
+	 * Delete the cache file of a language entirely
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns True if a cache file was removed
+	 */
+	remove(language: string): Promise<boolean>;
+
+	/**
+	 * List languages that have a cache file, sorted by language code
+	 *
+	 * @returns Language codes with a cached manifest (in any state)
+	 */
+	listLanguages(): Promise<string[]>;
+
+	/**
+	 * Inspect the cache file of a language without modifying it
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Cache details, or null if no cache file exists
+	 */
+	inspect(language: string): Promise<CacheInspection | null>;
+
+	/**
+	 * List the cached manifests of a language, newest first
+	 *
+	 * The first entry is the current cache file (even when expired), followed
+	 * by the manifests it replaced.
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Cached manifests with the time each was cached
+	 */
+	getHistory(language: string): Promise<CachedManifest[]>;
+
+	/**
+	 * Get the root cache directory (one subdirectory per language)
+	 */
+	getCacheDir(): string;
+
+	/**
+	 * Get the file path for cached manifest of a specific language
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Full path to the cache file
+	 */
+	getCachePath(language: string): string;
+}
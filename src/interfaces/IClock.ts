@@ -0,0 +1,21 @@
+/**
+ * Clock interface for time-dependent behavior
+ *
+ * Cache TTLs, staleness checks and relative time output read the current
+ * time through this interface so tests can control time passage instead of
+ * depending on the real clock.
+ *
+ * @example
+ * ```typescript
+ * const clock: IClock = new SystemClock();
+ * const ageMs = clock.now() - entry.timestamp;
+ * ```
+ */
+export default interface IClock {
+	/**
+	 * Get the current time
+	 *
+	 * @returns Milliseconds since the Unix epoch
+	 */
+	now(): number;
+}
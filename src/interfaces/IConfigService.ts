@@ -1,3 +1,7 @@
+import type { HookCommands, HookEvent, HookTrust } from "../utils/hooks.js";
+import type { ColorMode } from "../utils/style.js";
+import type { ToolDecision } from "../utils/toolConsent.js";
+
 /**
  * Available languages supported by claude-cmd
  */
@@ -30,12 +34,88 @@ export interface LanguageStatus {
 	}>;
 }
 
+/**
+ * Supported repository source types
+ */
+export type RepositoryType = "http" | "git";
+
+/**
+ * Error thrown when a configuration, key or value is invalid
+ */
+export class InvalidConfigError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = "InvalidConfigError";
+	}
+}
+
 /**
  * Unified configuration structure for both user and project configs
  */
 export interface Config {
 	preferredLanguage?: string;
+	/** Commands repository URL; file:// URLs read a local directory in place */
 	repositoryURL?: string;
+	/** HTTP(S) or file:// repositories whose commands are listed after repositoryURL's */
+	additionalRepositories?: string[];
+	/** additionalRepositories entries left out until enabled again (see `repo disable`) */
+	disabledRepositories?: string[];
+	/** Namespace put before the command names of a repository, by repository URL */
+	repositoryPrefixes?: Record<string, string>;
+	/** Repository source type: "http" (default) or "git" (clone/pull repositoryURL) */
+	repositoryType?: RepositoryType;
+	/** Branch or tag checked out for git repositories (default: remote HEAD) */
+	repositoryRef?: string;
+	/** minisign public key repositoryURL's manifests must be signed with (default: unverified) */
+	manifestPublicKey?: string;
+	/** minisign public key of each additional repository, by repository URL (default: unverified) */
+	repositoryPublicKeys?: Record<string, string>;
+	/** Fail instead of serving English when a translation is missing (default: false) */
+	strictLanguage?: boolean;
+	/** Record source, version, checksum and install time in installed files' frontmatter (default: false) */
+	recordProvenance?: boolean;
+	/** Where `add` installs without --target: "personal" (default) or "project" */
+	defaultTarget?: "personal" | "project";
+	/** Commands a project uses; `init --project` installs the missing ones */
+	commands?: string[];
+	/** Remembered answers to the allowed-tools prompt of `add`, by command name */
+	toolConsent?: Record<string, ToolDecision>;
+	/** Short names for installed commands: alias to `[personal:|project:]name` */
+	aliases?: Record<string, string>;
+	/** Shell commands run before/after add, remove and cache update (project config) */
+	hooks?: Partial<Record<HookEvent, HookCommands>>;
+	/** Answers to the project hooks prompt by project root; only read from the user config */
+	projectHookTrust?: Record<string, HookTrust>;
+	/** Seconds a hook may run before it is killed (default: 60) */
+	hookTimeoutSeconds?: number;
+	/** Cache directory; CLAUDE_CMD_CACHE_DIR takes precedence (default: platform cache dir) */
+	cacheDir?: string;
+	/** Claude Code's directory; CLAUDE_CONFIG_DIR takes precedence (default: ~/.claude) */
+	claudeDir?: string;
+	/** Delete namespace directories left empty by removals (default: true) */
+	cleanupEmptyDirectories?: boolean;
+	/** Directory name patterns skipped when scanning commands directories (default: .*, node_modules, vendor) */
+	scanIgnore?: string[];
+	/** Deepest namespace level scanned in commands directories (default: 10) */
+	scanMaxDepth?: number;
+	/** Write an anonymized crash report to the cache directory on crashes (default: false) */
+	crashReports?: boolean;
+	/** Maximum HTTP requests started per second (default: unlimited) */
+	maxRequestsPerSecond?: number;
+	/** Maximum HTTP requests in flight at once (default: unlimited) */
+	maxParallelDownloads?: number;
+	/** Maximum average download rate in bytes per second (default: unlimited) */
+	maxBytesPerSecond?: number;
+	/** Largest manifest accepted from the repository in bytes (default: 10 MiB) */
+	maxManifestBytes?: number;
+	/** Largest command file accepted from the repository in bytes (default: 1 MiB) */
+	maxCommandBytes?: number;
+	/** Lines shown by info --detailed before truncating (default: all, or 10 with previewCharacters) */
+	previewLines?: number;
+	/** Characters shown by info --detailed before truncating (default: all, or 500 with previewLines) */
+	previewCharacters?: number;
+	/** When to color output: "auto" (default), "always" or "never"; --no-color wins */
+	outputColor?: ColorMode;
 	[key: string]: any; // Allow additional fields for forward compatibility
 }
 
@@ -86,6 +166,29 @@ export interface IConfigService {
 	getLanguageStatus(): Promise<LanguageStatus>;
 }
 
+/**
+ * Where the effective language came from, in precedence order
+ */
+export type LanguageSource =
+	| "flag"
+	| "env"
+	| "project"
+	| "user"
+	| "locale"
+	| "default";
+
+/**
+ * Outcome of language resolution, with the raw value of every source
+ */
+export interface LanguageResolution {
+	/** Language code that will be used */
+	language: string;
+	/** Source that supplied the language */
+	source: LanguageSource;
+	/** Raw value of each source (empty string when unset) */
+	sources: Record<Exclude<LanguageSource, "default">, string>;
+}
+
 /**
  * Service interface for managing configuration precedence and resolution
  */
@@ -103,4 +206,11 @@ export interface IConfigManager {
 	 * @returns Language code that should be used
 	 */
 	getEffectiveLanguage(): Promise<string>;
+
+	/**
+	 * Explain which source supplies the effective language
+	 *
+	 * @returns The effective language, the winning source and every source's value
+	 */
+	getLanguageResolution(): Promise<LanguageResolution>;
 }
@@ -0,0 +1,88 @@
+import type {
+	CommandScanResult,
+	DirectoryInfo,
+} from "../types/Installation.js";
+import type IFileService from "./IFileService.js";
+import type { ScanOptions } from "./IFileService.js";
+
+/**
+ * Locates the personal and project commands directories
+ *
+ * Shared contract of directory detection so services and test doubles depend
+ * on the same surface instead of the concrete implementation.
+ */
+export default interface IDirectoryDetector {
+	/** File service the directories are read with */
+	readonly fileService: IFileService;
+
+	/**
+	 * Get all Claude directories (personal and project-specific)
+	 * @returns Array of directory information
+	 */
+	getClaudeDirectories(): Promise<DirectoryInfo[]>;
+
+	/**
+	 * Get the personal Claude commands directory path
+	 * @returns Absolute path to personal directory
+	 */
+	getPersonalDirectory(): Promise<string>;
+
+	/**
+	 * Get the project-specific Claude commands directory path
+	 * @param absolute Whether to return absolute path (default: false)
+	 * @returns Path to project directory
+	 */
+	getProjectDirectory(absolute?: boolean): Promise<string>;
+
+	/**
+	 * Get the commands directories of the project and the projects enclosing
+	 * it, nearest first
+	 * @param absolute Whether to return absolute paths (default: false)
+	 * @returns Paths to the project directories
+	 */
+	getProjectDirectories(absolute?: boolean): Promise<string[]>;
+
+	/**
+	 * Get the root of the project whose commands are in scope
+	 * @returns Absolute project root
+	 */
+	getProjectRoot(): string;
+
+	/**
+	 * Get the roots of the project and the projects enclosing it
+	 * @returns Absolute project roots, nearest first
+	 */
+	getProjectRoots(): string[];
+
+	/**
+	 * Ensure a directory exists, creating it if necessary
+	 * @param dirPath Path to the directory
+	 */
+	ensureDirectoryExists(dirPath: string): Promise<void>;
+
+	/**
+	 * Get the preferred installation location based on target preference
+	 * @param target Target directory type (defaults to "personal")
+	 * @returns Path to preferred directory
+	 */
+	getPreferredInstallLocation(target?: "personal" | "project"): Promise<string>;
+
+	/**
+	 * Get the configured scan limits, with defaults for unset ones
+	 * @returns Depth limit and ignored directory patterns
+	 */
+	getScanOptions(): Promise<Required<ScanOptions>>;
+
+	/**
+	 * Recursively scan a directory for command files (.md files only)
+	 * @param directoryPath Path to scan
+	 * @returns Array of absolute paths to .md files
+	 */
+	scanForCommandFiles(directoryPath: string): Promise<string[]>;
+
+	/**
+	 * Scan all Claude directories (both personal and project) for command files
+	 * @returns Object with command files categorized by location
+	 */
+	scanAllClaudeDirectories(): Promise<CommandScanResult>;
+}
@@ -86,6 +86,75 @@ export default interface IFileService {
 	 */
 	writeFile(path: string, content: string): Promise<void>;
 
+	/**
+	 * Read raw bytes from a file
+	 *
+	 * @param path - Absolute or relative path to the file
+	 * @returns Promise resolving to the file content as bytes
+	 * @throws FileNotFoundError when file doesn't exist
+	 * @throws FilePermissionError when read access is denied
+	 * @throws FileIOError for other I/O failures
+	 */
+	readBinaryFile(path: string): Promise<Uint8Array>;
+
+	/**
+	 * Write raw bytes to a file, creating directories as needed
+	 *
+	 * @param path - Absolute or relative path to the file
+	 * @param data - Bytes to write to the file
+	 * @returns Promise that resolves when write is complete
+	 * @throws FilePermissionError when write access is denied
+	 * @throws FileIOError for disk space or other I/O failures
+	 */
+	writeBinaryFile(path: string, data: Uint8Array): Promise<void>;
+
+	/**
+	 * Write bytes to a file as they arrive, creating directories as needed
+	 *
+	 * Only one chunk is held at a time, so large downloads never sit in
+	 * memory. Errors raised by the chunks themselves propagate unchanged and
+	 * leave the partly written file behind for the caller to delete.
+	 *
+	 * @param path - Absolute or relative path to the file
+	 * @param chunks - Bytes to write, in order
+	 * @returns Promise that resolves once every chunk is written
+	 * @throws FilePermissionError when write access is denied
+	 * @throws FileIOError for disk space or other I/O failures
+	 */
+	writeBinaryStream(
+		path: string,
+		chunks: AsyncIterable<Uint8Array>,
+	): Promise<void>;
+
+	/**
+	 * Allow a file to be run as a program
+	 *
+	 * Sets mode 0755 where the file system has permission bits.
+	 *
+	 * @param path - Absolute or relative path to the file
+	 * @returns Promise that resolves when the mode has been changed
+	 * @throws FileNotFoundError when file doesn't exist
+	 * @throws FilePermissionError when the mode cannot be changed
+	 * @throws FileIOError for other I/O failures
+	 */
+	makeExecutable(path: string): Promise<void>;
+
+	/**
+	 * Create a file only if nothing exists at the path yet
+	 *
+	 * The existence check and creation are one atomic step, so of several
+	 * concurrent callers exactly one succeeds. Parent directories are created
+	 * as needed.
+	 *
+	 * @param path - Absolute or relative path to the file
+	 * @param content - Content to write to the new file
+	 * @returns Promise resolving to true if the file was created, false if
+	 *   the path already existed
+	 * @throws FilePermissionError when write access is denied
+	 * @throws FileIOError for disk space or other I/O failures
+	 */
+	createFile(path: string, content: string): Promise<boolean>;
+
 	/**
 	 * Check if a file or directory exists
 	 *
@@ -116,6 +185,33 @@ export default interface IFileService {
 	 */
 	deleteFile(path: string): Promise<void>;
 
+	/**
+	 * Remove a directory if it is empty
+	 *
+	 * @param path - Absolute or relative path to the directory
+	 * @returns Promise resolving to true if the directory was removed, false
+	 *   if it still contains files or subdirectories
+	 * @throws FileNotFoundError when directory doesn't exist
+	 * @throws FilePermissionError when delete access is denied
+	 * @throws FileIOError for other I/O failures
+	 */
+	removeEmptyDirectory(path: string): Promise<boolean>;
+
+	/**
+	 * Rename a file, replacing the destination if it exists
+	 *
+	 * Within one directory the replacement is atomic: readers see either the
+	 * old or the new content, never a partially written file.
+	 *
+	 * @param from - Existing file path
+	 * @param to - New file path
+	 * @returns Promise that resolves when the file has been renamed
+	 * @throws FileNotFoundError when the source file doesn't exist
+	 * @throws FilePermissionError when write access is denied
+	 * @throws FileIOError for other I/O failures
+	 */
+	rename(from: string, to: string): Promise<void>;
+
 	/**
 	 * List files in a directory
 	 *
@@ -130,13 +226,49 @@ export default interface IFileService {
 	/**
 	 * List files recursively in a directory and all subdirectories
 	 *
+	 * Directories deeper than options.maxDepth or named like an
+	 * options.ignore pattern are pruned without being read.
+	 *
 	 * @param path - Absolute or relative path to the directory
+	 * @param options - Depth limit and ignored directories (default: none)
 	 * @returns Promise resolving to array of relative file paths from the root directory
 	 * @throws FileNotFoundError when directory doesn't exist
 	 * @throws FilePermissionError when read access is denied
 	 * @throws FileIOError for other I/O failures
 	 */
-	listFilesRecursive(path: string): Promise<string[]>;
+	listFilesRecursive(path: string, options?: ScanOptions): Promise<string[]>;
+
+	/**
+	 * List subdirectories recursively in a directory
+	 *
+	 * Pruned like listFilesRecursive(): ignored and too deep directories are
+	 * listed, as they exist, but not read.
+	 *
+	 * @param path - Absolute or relative path to the directory
+	 * @param options - Depth limit and ignored directories (default: none)
+	 * @returns Promise resolving to array of relative directory paths from the
+	 *   root directory, parents before their children
+	 * @throws FileNotFoundError when directory doesn't exist
+	 * @throws FilePermissionError when read access is denied
+	 * @throws FileIOError for other I/O failures
+	 */
+	listDirectoriesRecursive(
+		path: string,
+		options?: ScanOptions,
+	): Promise<string[]>;
+
+	/**
+	 * Resolve symbolic links and junctions in a path
+	 *
+	 * The path does not need to exist: its deepest existing ancestor is
+	 * resolved and the remaining segments are appended unchanged.
+	 *
+	 * @param path - Absolute or relative path to resolve
+	 * @returns Promise resolving to the absolute path with links resolved
+	 * @throws FilePermissionError when an ancestor cannot be read
+	 * @throws FileIOError for other I/O failures
+	 */
+	realPath(path: string): Promise<string>;
 
 	/**
 	 * Check if a path is writable
@@ -150,8 +282,12 @@ export default interface IFileService {
 	/**
 	 * Scan directory hierarchy for command files
 	 *
+	 * Directories deeper than options.maxDepth or named like an
+	 * options.ignore pattern are pruned without being read, so a commands
+	 * directory accidentally placed above a large tree stays cheap to scan.
+	 *
 	 * @param basePath - Base directory to scan from
-	 * @param maxDepth - Maximum depth to scan (default: 10)
+	 * @param options - Depth limit and ignored directories
 	 * @returns Promise resolving to array of file paths with namespace information
 	 * @throws FileNotFoundError when base directory doesn't exist
 	 * @throws FilePermissionError when read access is denied
@@ -159,10 +295,20 @@ export default interface IFileService {
 	 */
 	scanNamespaceHierarchy(
 		basePath: string,
-		maxDepth?: number,
+		options?: ScanOptions,
 	): Promise<NamespacedFile[]>;
 }
 
+/**
+ * Limits of a namespace hierarchy scan
+ */
+export interface ScanOptions {
+	/** Deepest directory level scanned; files of basePath are level 0 (default: 10) */
+	readonly maxDepth?: number;
+	/** Directory name patterns (* and ? wildcards) skipped entirely (default: none) */
+	readonly ignore?: readonly string[];
+}
+
 /**
  * Represents a file found during namespace hierarchy scanning
  */
@@ -0,0 +1,70 @@
+/**
+ * Error thrown when a git operation fails
+ */
+export class GitError extends Error {
+	constructor(
+		message: string,
+		public readonly operation: string,
+		public readonly stderr?: string,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Options for cloning or updating a git working tree
+ */
+export interface GitCheckoutOptions {
+	/** Branch or tag to check out (default: remote HEAD) */
+	readonly ref?: string;
+}
+
+/**
+ * Git client interface for repository synchronization
+ *
+ * Provides the minimal set of git operations needed to mirror a commands
+ * repository into a local working tree. Implementations shell out to git,
+ * so SSH URLs and credential helpers configured for the user work unchanged.
+ *
+ * @example
+ * ```typescript
+ * const git: IGitClient = new BunGitClient();
+ *
+ * await git.clone("git@github.com:acme/commands.git", "/tmp/commands", { ref: "v1.2.0" });
+ * await git.update("/tmp/commands", { ref: "main" });
+ * ```
+ */
+export default interface IGitClient {
+	/**
+	 * Clone a repository into a directory using a shallow clone
+	 *
+	 * @param url - Repository URL (https, ssh, or scp-like "git@host:path")
+	 * @param directory - Target directory (must not exist yet)
+	 * @param options - Optional branch or tag to check out
+	 * @throws GitError when git is unavailable or the clone fails
+	 */
+	clone(
+		url: string,
+		directory: string,
+		options?: GitCheckoutOptions,
+	): Promise<void>;
+
+	/**
+	 * Fetch the latest revision of a ref and check it out
+	 *
+	 * @param directory - Existing working tree created by clone()
+	 * @param options - Optional branch or tag to check out
+	 * @throws GitError when the fetch or checkout fails
+	 */
+	update(directory: string, options?: GitCheckoutOptions): Promise<void>;
+
+	/**
+	 * Get the commit SHA currently checked out
+	 *
+	 * @param directory - Existing working tree
+	 * @returns Full commit SHA of HEAD
+	 * @throws GitError when the directory is not a git working tree
+	 */
+	getHeadCommit(directory: string): Promise<string>;
+}
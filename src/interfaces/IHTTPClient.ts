@@ -6,6 +6,18 @@ export interface HTTPOptions {
 	readonly timeout?: number;
 	/** Request headers as key-value pairs */
 	readonly headers?: Record<string, string>;
+	/** Largest response body accepted in bytes (default: unlimited) */
+	readonly maxBytes?: number;
+	/**
+	 * How the body is decoded (default: "utf8"); "latin1" maps every byte to
+	 * one character, so binary bodies survive as Buffer.from(body, "latin1")
+	 */
+	readonly encoding?: "utf8" | "latin1";
+	/**
+	 * Aborts the request, e.g. once the caller stopped waiting for it; an
+	 * aborted request fails like a timed out one
+	 */
+	readonly signal?: AbortSignal;
 }
 
 /**
@@ -24,6 +36,18 @@ export interface HTTPResponse {
 	readonly url: string;
 }
 
+/**
+ * HTTP response whose body is read from the network as it is consumed
+ */
+export interface HTTPStreamResponse extends Omit<HTTPResponse, "body"> {
+	/**
+	 * Body chunks; HTTPOptions.maxBytes is enforced while reading, and
+	 * read errors surface from the iteration. Iterate it to the end or break
+	 * out of the loop so the connection is released.
+	 */
+	readonly body: AsyncIterable<Uint8Array>;
+}
+
 /**
  * Base class for all HTTP-related errors
  */
@@ -63,6 +87,19 @@ export class HTTPNetworkError extends HTTPError {
 	}
 }
 
+/**
+ * Error thrown when a response body is larger than HTTPOptions.maxBytes
+ */
+export class HTTPResponseTooLargeError extends HTTPError {
+	/** Size limit in bytes that was exceeded */
+	public readonly maxBytes: number;
+
+	constructor(url: string, maxBytes: number) {
+		super(`Response exceeds the limit of ${maxBytes} bytes`, url);
+		this.maxBytes = maxBytes;
+	}
+}
+
 /**
  * Error thrown when server returns non-2xx status code
  */
@@ -79,6 +116,25 @@ export class HTTPStatusError extends HTTPError {
 	}
 }
 
+/**
+ * Error thrown when the server rate-limits requests (HTTP 429, or 403 with
+ * an exhausted GitHub rate limit)
+ */
+export class HTTPRateLimitError extends HTTPStatusError {
+	/** Epoch milliseconds before which the server should not be asked again */
+	public readonly retryAt?: number;
+
+	constructor(
+		url: string,
+		status: number,
+		statusText: string,
+		retryAt?: number,
+	) {
+		super(url, status, statusText);
+		this.retryAt = retryAt;
+	}
+}
+
 /**
  * HTTP client interface for network operations
  *
@@ -95,6 +151,24 @@ export default interface IHTTPClient {
 	 * @throws HTTPTimeoutError when request times out
 	 * @throws HTTPNetworkError when network fails
 	 * @throws HTTPStatusError when server returns error status
+	 * @throws HTTPResponseTooLargeError when the body exceeds options.maxBytes
 	 */
 	get(url: string, options?: HTTPOptions): Promise<HTTPResponse>;
+
+	/**
+	 * Perform an HTTP GET request without reading the body up front
+	 *
+	 * For large bodies that are processed as they arrive (see
+	 * ManifestDecoder). options.encoding does not apply.
+	 *
+	 * @param url - The URL to request
+	 * @param options - Optional request configuration
+	 * @returns Promise resolving once the response headers arrived
+	 * @throws HTTPTimeoutError when request times out
+	 * @throws HTTPNetworkError when network fails
+	 * @throws HTTPStatusError when server returns error status
+	 * @throws HTTPResponseTooLargeError when the declared size exceeds
+	 *   options.maxBytes (a larger body read anyway fails while iterating)
+	 */
+	stream(url: string, options?: HTTPOptions): Promise<HTTPStreamResponse>;
 }
@@ -1,9 +1,18 @@
 import type { Command, CommandServiceOptions } from "../types/Command.js";
 import type {
+	CopyOptions,
+	ExportOptions,
+	ExportResult,
+	ImportOptions,
 	InstallationInfo,
 	InstallationSummary,
+	InstalledCommandLocation,
 	InstallOptions,
+	MoveOptions,
+	MoveResult,
 	RemoveOptions,
+	RestoreOptions,
+	TrashEntry,
 } from "../types/Installation.js";
 
 /**
@@ -21,6 +30,17 @@ export default interface IInstallationService {
 	 */
 	installCommand(commandName: string, options?: InstallOptions): Promise<void>;
 
+	/**
+	 * List the commands a command transitively requires that are not installed
+	 * @param commandName Name of the command about to be installed
+	 * @param language Language to read requirements in (default: en)
+	 * @returns Promise resolving to missing commands in install order
+	 */
+	getMissingDependencies(
+		commandName: string,
+		language?: string,
+	): Promise<string[]>;
+
 	/**
 	 * Remove an installed command from local directory
 	 * @param commandName Name of the command to remove
@@ -29,6 +49,62 @@ export default interface IInstallationService {
 	 */
 	removeCommand(commandName: string, options?: RemoveOptions): Promise<void>;
 
+	/**
+	 * Restore the most recently removed version of a command from the trash
+	 * @param commandName Name the command was installed under
+	 * @param options Location to restore from and overwrite flag
+	 * @returns Promise resolving to the restored trash entry
+	 */
+	restoreCommand(
+		commandName: string,
+		options?: RestoreOptions,
+	): Promise<TrashEntry>;
+
+	/**
+	 * Move an installed command to a new name and/or location
+	 * @param commandName Current name of the command
+	 * @param newName New name (may change the namespace)
+	 * @param options Move options (source/target location, overwrite, etc.)
+	 * @returns Promise resolving to the old and new file paths
+	 */
+	moveCommand(
+		commandName: string,
+		newName: string,
+		options?: MoveOptions,
+	): Promise<MoveResult>;
+
+	/**
+	 * Copy an installed command between the personal and project directories
+	 * @param commandName Name of the command
+	 * @param options Destination location and overwrite flag
+	 * @returns Promise resolving to the source and destination file paths
+	 */
+	copyCommand(commandName: string, options: CopyOptions): Promise<MoveResult>;
+
+	/**
+	 * Install a command file from outside the repository under a name
+	 * @param commandName Name to install under (may be namespaced)
+	 * @param content Command file content, already validated
+	 * @param options Target location and overwrite flag
+	 * @returns Promise resolving to the path of the installed file
+	 */
+	importCommand(
+		commandName: string,
+		content: string,
+		options?: ImportOptions,
+	): Promise<string>;
+
+	/**
+	 * Copy installed command files, namespaces included, for sharing or backup
+	 * @param destination Archive to create (.tar.gz or .tgz) or directory
+	 * @param options Commands to export and overwrite flag
+	 * @returns Promise resolving to what was written
+	 */
+	exportCommands(
+		destination: string,
+		options?: ExportOptions,
+	): Promise<ExportResult>;
+
 	/**
 	 * List all installed commands from local Claude directories
 	 * @param options Optional language override and cache control
@@ -59,11 +135,23 @@ export default interface IInstallationService {
 	 */
 	getInstallationPath(commandName: string): Promise<string | null>;
 
+	/**
+	 * Find the installed file of a command, checking the personal directory first
+	 * @param commandName Name of the command (supports namespaced commands)
+	 * @param location Only look in this location
+	 * @returns Promise resolving to the file and its location, or null
+	 */
+	findInstalledCommand(
+		commandName: string,
+		location?: "personal" | "project",
+	): Promise<InstalledCommandLocation | null>;
+
 	/**
 	 * Get detailed information about all installed commands
+	 * @param language Language whose collation orders names (default: byte order)
 	 * @returns Promise resolving to array of installation info
 	 */
-	getAllInstallationInfo(): Promise<InstallationInfo[]>;
+	getAllInstallationInfo(language?: string): Promise<InstallationInfo[]>;
 
 	/**
 	 * Get summary information about all installed commands
@@ -0,0 +1,39 @@
+/**
+ * Progress reporting for long-running operations
+ *
+ * Services report what they are doing through this interface; the CLI
+ * decides how (or whether) to show it, so services never write to the
+ * terminal themselves.
+ *
+ * @example
+ * ```typescript
+ * progress.start("Exporting en commands", commands.length);
+ * for (const command of commands) {
+ *   const content = await repository.getCommand(command.name, "en");
+ *   progress.advance(1, Buffer.byteLength(content));
+ * }
+ * progress.finish();
+ * ```
+ */
+export default interface IProgressReporter {
+	/**
+	 * Begin reporting a task
+	 *
+	 * @param label - What is being done (e.g., "Downloading manifest")
+	 * @param total - Number of steps, if known; omit for open-ended tasks
+	 */
+	start(label: string, total?: number): void;
+
+	/**
+	 * Record completed work
+	 *
+	 * @param steps - Steps completed since the last call (default: 1)
+	 * @param bytes - Bytes downloaded since the last call (default: 0)
+	 */
+	advance(steps?: number, bytes?: number): void;
+
+	/**
+	 * End the current task and clear any transient output
+	 */
+	finish(): void;
+}
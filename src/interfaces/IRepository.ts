@@ -1,4 +1,6 @@
 import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { resolveCacheDir } from "../utils/cacheDir.js";
 
 /**
  * Information about a language and its available commands
@@ -75,24 +77,12 @@ export class CacheConfig {
 	}
 
 	/**
-	 * Get OS-specific cache directory
+	 * Get the cache directory used when none is given
 	 *
-	 * @returns Default cache directory path for the current OS
+	 * @returns CLAUDE_CMD_CACHE_DIR if set, otherwise the platform default
 	 */
 	private getDefaultCacheDir(): string {
-		const platform = process.platform;
-
-		switch (platform) {
-			case "darwin": // macOS
-				return `${process.env.HOME}/Library/Caches/claude-cmd`;
-			case "linux":
-				return `${process.env.HOME}/.cache/claude-cmd`;
-			case "win32": // Windows
-				return `${process.env.LOCALAPPDATA}\\claude-cmd`;
-			default:
-				// Fallback for unknown platforms
-				return "/tmp/claude-cmd-cache";
-		}
+		return resolveCacheDir();
 	}
 
 	/**
@@ -202,4 +192,16 @@ export default interface IRepository {
 	 * @throws RepositoryError for cache access failures
 	 */
 	getAvailableLanguages(): Promise<LanguageStatusInfo[]>;
+
+	/**
+	 * Retrieve the repository's descriptive metadata
+	 *
+	 * Reads the optional about.json at the commands root, overlaid with the
+	 * language directory's about.json when present.
+	 *
+	 * @param language - ISO 639-1 language code (e.g., "en", "fr", "es")
+	 * @returns Promise resolving to the metadata, or null when the repository
+	 *   publishes none or it cannot be retrieved
+	 */
+	getAbout(language: string): Promise<RepositoryAbout | null>;
 }
@@ -0,0 +1,92 @@
+import type {
+	AllLanguagesStatus,
+	CacheUsage,
+	DiskUsage,
+	StatusOutputFormat,
+	SystemStatus,
+} from "../types/Status.js";
+import type { Theme } from "../utils/style.js";
+import type { Template } from "../utils/template.js";
+
+/**
+ * Presentation options for human-readable status output
+ */
+export interface StatusFormatOptions {
+	/** Styles for the default format (default: plain) */
+	readonly theme?: Theme;
+	/** Language of lines with dates, ages and counts (default: English) */
+	readonly locale?: string;
+}
+
+/**
+ * Formatter for status, cache and disk usage output
+ *
+ * Shared contract of the status command's output so commands and test
+ * doubles depend on the same surface instead of one concrete formatter.
+ */
+export default interface IStatusFormatter {
+	/**
+	 * Format system status in the specified output format
+	 *
+	 * @param status - System status data to format
+	 * @param format - Output format to use
+	 * @param options - Theme and language of human-readable formats
+	 * @returns Formatted status string
+	 */
+	format(
+		status: SystemStatus,
+		format: StatusOutputFormat,
+		options?: StatusFormatOptions,
+	): string;
+
+	/**
+	 * Render system status with a user template (global --format)
+	 *
+	 * @param status - System status data to format
+	 * @param template - Parsed template
+	 * @param language - Language whose cache fills .Cache
+	 * @returns The rendered template
+	 * @throws TemplateError if the template names an unknown field
+	 */
+	formatTemplate(
+		status: SystemStatus,
+		template: Template,
+		language: string,
+	): string;
+
+	/**
+	 * Format the per-language cache breakdown in the specified output format
+	 *
+	 * @param status - Cache status of every cached language
+	 * @param format - Output format to use
+	 * @param options - Language of human-readable formats
+	 * @returns Formatted status string
+	 */
+	formatAllLanguages(
+		status: AllLanguagesStatus,
+		format: StatusOutputFormat,
+		options?: StatusFormatOptions,
+	): string;
+
+	/**
+	 * Format disk usage in the specified output format
+	 *
+	 * @param usage - Disk space used by caches and installed commands
+	 * @param format - Output format to use
+	 * @param options - Language of human-readable formats
+	 * @returns Formatted usage string
+	 */
+	formatDiskUsage(
+		usage: DiskUsage,
+		format: StatusOutputFormat,
+		options?: StatusFormatOptions,
+	): string;
+
+	/**
+	 * Format the disk space used by the caches of each language
+	 *
+	 * @param usage - Cache usage per language
+	 * @returns One line per language, or a note that nothing is cached
+	 */
+	formatCacheUsage(usage: readonly CacheUsage[]): string;
+}
@@ -0,0 +1,73 @@
+/**
+ * Kind of recorded event
+ */
+export type UsageAction = "install" | "remove";
+
+/**
+ * A single install or removal
+ */
+export interface UsageEvent {
+	/** Command name as installed (e.g., "frontend:component") */
+	readonly command: string;
+	/** What happened */
+	readonly action: UsageAction;
+	/** When it happened (milliseconds since Unix epoch) */
+	readonly at: number;
+	/** Language of the installed content */
+	readonly language?: string;
+	/** Install target directory type */
+	readonly target?: "personal" | "project";
+}
+
+/**
+ * Aggregated statistics of one command
+ */
+export interface CommandUsage {
+	/** Command name */
+	readonly command: string;
+	/** Number of times the command was installed */
+	readonly installs: number;
+	/** Number of times the command was removed */
+	readonly removals: number;
+	/** Last install or removal (milliseconds since Unix epoch) */
+	readonly lastUsedAt: number;
+}
+
+/**
+ * Local record of installs and removals
+ *
+ * Shared contract of usage statistics so commands and test doubles depend
+ * on the same surface instead of the concrete file-based implementation.
+ */
+export default interface IUsageStatsService {
+	/**
+	 * Get the path of the stats file
+	 */
+	getStatsPath(): string;
+
+	/**
+	 * Record an install or removal; never fails the operation being recorded
+	 *
+	 * @param event - What happened, without a timestamp
+	 */
+	record(event: Omit<UsageEvent, "at">): Promise<void>;
+
+	/**
+	 * Get per-command statistics, most installed first
+	 */
+	getCommandUsage(): Promise<CommandUsage[]>;
+
+	/**
+	 * Get recorded events, most recent first
+	 *
+	 * @param limit - Maximum number of events to return
+	 */
+	getHistory(limit?: number): Promise<UsageEvent[]>;
+
+	/**
+	 * Delete all recorded statistics
+	 *
+	 * @returns True if a stats file was removed
+	 */
+	clear(): Promise<boolean>;
+}
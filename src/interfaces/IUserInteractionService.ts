@@ -15,6 +15,38 @@ export interface ConfirmationOptions {
 	readonly skipWithYes?: boolean;
 }
 
+/**
+ * One answer offered by a choice prompt
+ */
+export interface Choice<T extends string = string> {
+	/** Value returned when this choice is picked */
+	readonly value: T;
+	/** Single-letter shortcut the user can type */
+	readonly key: string;
+	/** Label shown in the prompt */
+	readonly label: string;
+}
+
+/**
+ * Options for choice prompts
+ */
+export interface ChoiceOptions<T extends string = string> {
+	/** Message to display to the user */
+	readonly message: string;
+	/** Answers to offer, in display order */
+	readonly choices: readonly Choice<T>[];
+}
+
+/**
+ * Options for free-text prompts
+ */
+export interface TextPromptOptions {
+	/** Message to display to the user */
+	readonly message: string;
+	/** Answer used for empty input and when no terminal is available */
+	readonly defaultValue: string;
+}
+
 /**
  * Service for handling interactive user prompts in the terminal
  * Supports confirmation prompts with --yes flag bypassing
@@ -27,6 +59,25 @@ export default interface IUserInteractionService {
 	 */
 	confirmAction(options: ConfirmationOptions): Promise<boolean>;
 
+	/**
+	 * Ask the user to pick one of several answers
+	 * @param options - Prompt configuration
+	 * @returns Promise resolving to the picked value, or undefined when no
+	 *   terminal is available to ask (callers fall back to their
+	 *   non-interactive behavior)
+	 */
+	chooseOption<T extends string>(
+		options: ChoiceOptions<T>,
+	): Promise<T | undefined>;
+
+	/**
+	 * Ask the user to type an answer
+	 * @param options - Prompt configuration
+	 * @returns Promise resolving to the trimmed answer, or the default when
+	 *   the answer is empty, --yes mode is active or no terminal is available
+	 */
+	askText(options: TextPromptOptions): Promise<string>;
+
 	/**
 	 * Set whether the service should skip prompts (--yes flag)
 	 * @param yesMode - true to skip all prompts with defaults
@@ -2,42 +2,92 @@
 
 import { join } from "node:path";
 import { Command } from "commander";
-import { configureLogger, enableVerboseLogging } from "./utils/logger.js";
+import {
+	cliLogger,
+	configureLogger,
+	enableVerboseLogging,
+	parseLogLevel,
+} from "./utils/logger.js";
 
-// Early check for verbose flag and environment variable before configuring LogTape
+// Early check for verbose/debug flags and environment variable before configuring LogTape
 const hasVerboseFlag =
 	process.argv.includes("-V") || process.argv.includes("--verbose");
+const hasDebugFlag = process.argv.includes("--debug");
 const envLogLevel = process.env.LOG_LEVEL;
 
 let initialLogLevel = "info"; // Default log level
 
-// Prioritize the --verbose flag for debug level if present
-if (hasVerboseFlag) {
+// --debug shows everything (HTTP requests, cache hits/misses, file writes);
+// --verbose stops at debug level
+if (hasDebugFlag) {
+	initialLogLevel = "trace";
+} else if (hasVerboseFlag) {
 	initialLogLevel = "debug";
 }
 
 // Environment variable can override the flag
-if (envLogLevel) {
-	const lowerEnvLogLevel = envLogLevel.toLowerCase();
-	if (["debug", "info", "warn", "error", "fatal"].includes(lowerEnvLogLevel)) {
-		initialLogLevel = lowerEnvLogLevel;
-	}
+if (envLogLevel && parseLogLevel(envLogLevel)) {
+	initialLogLevel = envLogLevel;
 }
 
 // Configure LogTape immediately based on early check
-await configureLogger(initialLogLevel);
+await configureLogger(initialLogLevel, { structured: hasDebugFlag });
 
 // Now import commands after logger is configured
+import {
+	checkOutputTemplate,
+	handleError,
+	parseLanguageCode,
+	setPorcelain,
+} from "./cli/cliUtils.js";
+import { registerCommands } from "./cli/commandGroups.js";
 import { addCommand } from "./cli/commands/add.js";
+import { aliasCommand } from "./cli/commands/alias.js";
+import { bundleCommand } from "./cli/commands/bundle.js";
 import { cacheCommand } from "./cli/commands/cache.js";
 import { completionCommand } from "./cli/commands/completion.js";
+import { configCommand } from "./cli/commands/config.js";
+import { conflictsCommand } from "./cli/commands/conflicts.js";
+import { copyCommand } from "./cli/commands/copy.js";
+import { editCommand } from "./cli/commands/edit.js";
+import { exportCommand } from "./cli/commands/export.js";
+import { grepCommand } from "./cli/commands/grep.js";
+import { importCommand } from "./cli/commands/import.js";
 import { infoCommand } from "./cli/commands/info.js";
+import { initCommand, showFirstUseMessage } from "./cli/commands/init.js";
 import { installedCommand } from "./cli/commands/installed.js";
 import { languageCommand } from "./cli/commands/language.js";
 import { listCommand } from "./cli/commands/list.js";
+import { manifestCommand } from "./cli/commands/manifest.js";
+import { mvCommand } from "./cli/commands/mv.js";
+import { recoverCommand } from "./cli/commands/recover.js";
 import { removeCommand } from "./cli/commands/remove.js";
+import { repoCommand } from "./cli/commands/repo.js";
+import { restoreCommand } from "./cli/commands/restore.js";
 import { searchCommand } from "./cli/commands/search.js";
+import { selfUpdateCommand } from "./cli/commands/selfUpdate.js";
+import { showCommand } from "./cli/commands/show.js";
+import { statsCommand } from "./cli/commands/stats.js";
 import { statusCommand } from "./cli/commands/status.js";
+import { undoCommand } from "./cli/commands/undo.js";
+import { versionCommand } from "./cli/commands/version.js";
+import { watchCommand } from "./cli/commands/watch.js";
+import { whatsnewCommand } from "./cli/commands/whatsnew.js";
+import { whichCommand } from "./cli/commands/which.js";
+import {
+	exitWithCrashReport,
+	setCrashReportVersion,
+} from "./cli/crashReport.js";
+import { enableJsonEvents } from "./cli/events.js";
+import { ExitCode } from "./cli/exitCodes.js";
+import { configureOutput, warning } from "./cli/output.js";
+import {
+	createDefaultDependencies,
+	createServices,
+	getServices,
+	setServices,
+} from "./services/serviceFactory.js";
+import { migrateLegacyCacheDir } from "./utils/cacheDir.js";
 
 // Read version from package.json using Bun's file API with error handling
 let version = "0.0.0";
@@ -50,6 +100,17 @@ try {
 		"Warning: Could not read version from package.json, using default version",
 	);
 }
+setCrashReportVersion(version);
+
+// Errors no command handled are crashes
+for (const event of ["uncaughtException", "unhandledRejection"] as const) {
+	process.on(event, (error: unknown) => {
+		console.error(
+			`Unexpected error: ${error instanceof Error ? (error.stack ?? error.message) : String(error)}`,
+		);
+		void exitWithCrashReport(error, ExitCode.Failure);
+	});
+}
 
 const program = new Command();
 
@@ -62,40 +123,163 @@ program
 	.addHelpText(
 		"after",
 		"\nEnvironment variables:\n" +
-			"  LOG_LEVEL         Set logging level (debug, info, warn, error, fatal)\n" +
-			"  CLAUDE_CMD_LANG   Set language for commands (e.g., en, fr, de)",
+			"  LOG_LEVEL         Set logging level (trace, debug, info, warn, error, fatal)\n" +
+			"  CLAUDE_CMD_LANG   Set language for commands (e.g., en, fr, de)\n" +
+			"  CLAUDE_CMD_CACHE_DIR  Cache directory (overrides cacheDir and the platform default)\n" +
+			"  CLAUDE_CONFIG_DIR     Claude Code's directory, also CLAUDE_HOME (overrides claudeDir; default ~/.claude)\n" +
+			"  CLAUDE_CMD_STRICT_DEPRECATIONS  Fail instead of warning on deprecated usage (1, true)\n" +
+			"  CLAUDE_CMD_NO_PROJECT_DISCOVERY  Use ./.claude only, like --no-project-discovery (1, true)\n" +
+			"  NO_COLOR          Disable colored output unless outputColor is always (any value)\n" +
+			"\nExit codes:\n" +
+			"  0  Success\n" +
+			"  1  Other failure\n" +
+			"  2  Command, file or installation not found\n" +
+			"  3  Network or repository unreachable\n" +
+			"  4  Local cache unreadable or corrupted\n" +
+			"  5  Invalid input",
 	)
 	.option(
 		"--format <format>",
-		"Output format (default, compact, json)",
+		"Output format (default, compact, json), or a template for list and status such as '{{.Name}}'",
 		"default",
 	)
+	.option(
+		"-q, --quiet",
+		"Suppress progress, confirmations and hints; only results and errors are printed",
+	)
+	.option("--no-color", "Disable colored output (also NO_COLOR)")
+	.option(
+		"--language <lang>",
+		"Language for every command (overrides CLAUDE_CMD_LANG and configuration)",
+		parseLanguageCode,
+	)
+	.option(
+		"--porcelain",
+		"Machine-readable output: tab-separated records without headers, errors as 'error<TAB>code<TAB>name<TAB>message'",
+	)
+	.option(
+		"--json-events",
+		"Write progress, installation and error events as JSON lines on stderr (fetch_started, download_progress, fetch_finished, installed, error)",
+	)
 	.option(
 		"-V, --verbose",
 		"Enable verbose debug logging for cache, HTTP, and file operations. Useful for debugging/reporting issues.",
 	)
+	.option(
+		"--debug",
+		"Enable trace logging of HTTP requests, cache hits/misses, and file writes as structured lines on stderr.",
+	)
+	.option(
+		"--no-project-discovery",
+		"Use ./.claude as the project scope instead of the nearest parent directory with .claude",
+	)
 	.helpOption("-h, --help", "help for claude-cmd")
-	.hook("preAction", (thisCommand, actionCommand) => {
+	.hook("preAction", async (thisCommand, actionCommand) => {
 		const opts = thisCommand.opts();
-		if (opts.verbose) {
+		setPorcelain(Boolean(opts.porcelain));
+		// Services are built on first use, so this comes before getServices()
+		if (!opts.projectDiscovery) {
+			setServices(
+				createServices(createDefaultDependencies({ discoverProject: false })),
+			);
+		}
+		const { outputColor } =
+			await getServices().configManager.getEffectiveConfig();
+		configureOutput({
+			quiet: Boolean(opts.quiet),
+			color:
+				!opts.color || opts.porcelain ? "never" : (outputColor ?? "auto"),
+		});
+		getServices().languageDetector.setCliFlag(opts.language ?? "");
+		if (opts.jsonEvents) {
+			enableJsonEvents(getServices().eventBus);
+		}
+		// Only some commands render templates; fail rather than ignore one
+		try {
+			checkOutputTemplate(actionCommand);
+		} catch (error) {
+			await handleError(error, "Invalid --format template");
+		}
+		if (opts.debug) {
+			enableVerboseLogging("debug");
+		} else if (opts.verbose) {
 			enableVerboseLogging();
 		}
+		cliLogger.debug("running {command} {args} {options}", {
+			command: actionCommand.name(),
+			args: actionCommand.args,
+			options: actionCommand.opts(),
+		});
+
+		let topLevel = actionCommand;
+		while (topLevel.parent && topLevel.parent !== thisCommand) {
+			topLevel = topLevel.parent;
+		}
+		const { fileService, cacheDir, userConfigService } = getServices();
+		await showFirstUseMessage(fileService, topLevel.name(), {
+			userConfigPath: userConfigService.getConfigPath(),
+			cacheDir,
+		});
+
+		// Earlier releases kept the cache elsewhere; move it once
+		await migrateLegacyCacheDir(fileService, cacheDir);
+
+		// A previous run died midway; point at recovery before touching anything
+		if (actionCommand.name() !== "recover") {
+			try {
+				const pending = await getServices().transactionJournal.listPending();
+				if (pending.length > 0) {
+					warning(
+						`${pending.length} interrupted operation(s) found (${pending.map((t) => t.name).join(", ")}). Run 'claude-cmd recover' to roll back or complete them.`,
+					);
+				}
+			} catch (error) {
+				cliLogger.debug("pending transaction check failed: {error}", {
+					error: error instanceof Error ? error.message : String(error),
+				});
+			}
+		}
 	});
 
-// Add modular commands
-program.addCommand(addCommand);
-program.addCommand(cacheCommand);
-program.addCommand(listCommand);
-program.addCommand(searchCommand);
-program.addCommand(infoCommand);
-program.addCommand(installedCommand);
-program.addCommand(removeCommand);
-program.addCommand(statusCommand);
-program.addCommand(languageCommand);
-program.addCommand(completionCommand);
+// Add modular commands; each one declares its help group (see commandGroups.ts)
+registerCommands(program, [
+	addCommand,
+	cacheCommand,
+	listCommand,
+	searchCommand,
+	grepCommand,
+	infoCommand,
+	showCommand,
+	whatsnewCommand,
+	installedCommand,
+	whichCommand,
+	conflictsCommand,
+	removeCommand,
+	restoreCommand,
+	mvCommand,
+	copyCommand,
+	importCommand,
+	exportCommand,
+	editCommand,
+	aliasCommand,
+	watchCommand,
+	statusCommand,
+	statsCommand,
+	recoverCommand,
+	undoCommand,
+	selfUpdateCommand,
+	versionCommand,
+	languageCommand,
+	configCommand,
+	initCommand,
+	repoCommand,
+	bundleCommand,
+	manifestCommand,
+	completionCommand,
+]);
 
 // Commander.js automatically provides help command and --help flag
 // No need for custom help command
 
 // Parse arguments
-program.parse();
+await program.parseAsync();
@@ -0,0 +1,184 @@
+import { dirname } from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import type IHTTPClient from "../interfaces/IHTTPClient.js";
+import type {
+	HTTPOptions,
+	HTTPResponse,
+	HTTPStreamResponse,
+} from "../interfaces/IHTTPClient.js";
+import { HTTPRateLimitError } from "../interfaces/IHTTPClient.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { httpLogger } from "../utils/logger.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Name of the state file in the cache directory
+ */
+export const BACKOFF_STATE_FILE = "backoff.json";
+
+/**
+ * Wait applied when a server rate-limits without saying for how long
+ */
+export const DEFAULT_BACKOFF_MS = 60 * 1000;
+
+/**
+ * Longest wait honored, so a bogus Retry-After cannot lock the cache in
+ */
+export const MAX_BACKOFF_MS = 60 * 60 * 1000;
+
+/**
+ * Persisted backoff state: host name to "retry not before" (epoch ms)
+ */
+type BackoffState = Record<string, number>;
+
+/**
+ * HTTP client that remembers rate limits across runs
+ *
+ * When the wrapped client reports a rate limit, the time the server asked
+ * to wait for (Retry-After or GitHub's reset header) is stored per host in
+ * a state file in the cache directory. Until then, requests to that host
+ * fail immediately with HTTPRateLimitError instead of hitting the server
+ * again, which lets callers fall back to cached data. A successful response
+ * clears the host's entry.
+ *
+ * @example
+ * ```typescript
+ * const client = new BackoffHTTPClient(
+ *   new BunHTTPClient(),
+ *   fileService,
+ *   path.join(cacheDir, BACKOFF_STATE_FILE),
+ * );
+ * ```
+ */
+export class BackoffHTTPClient implements IHTTPClient {
+	private state?: Promise<BackoffState>;
+
+	/**
+	 * @param client - Client performing the actual requests
+	 * @param fileService - File service for the state file
+	 * @param statePath - Path of the state file
+	 * @param clock - Clock for backoff windows (defaults to system time)
+	 */
+	constructor(
+		private readonly client: IHTTPClient,
+		private readonly fileService: IFileService,
+		private readonly statePath: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	get(url: string, options?: HTTPOptions): Promise<HTTPResponse> {
+		return this.send(url, () => this.client.get(url, options));
+	}
+
+	stream(url: string, options?: HTTPOptions): Promise<HTTPStreamResponse> {
+		return this.send(url, () => this.client.stream(url, options));
+	}
+
+	/**
+	 * Perform a request unless its host is backing off, and record the rate
+	 * limits it reports
+	 */
+	private async send<T>(url: string, request: () => Promise<T>): Promise<T> {
+		const host = hostOf(url);
+		const state = await this.loadState();
+		const retryAt = state[host];
+		if (retryAt !== undefined && this.clock.now() < retryAt) {
+			httpLogger.debug("skipping {url}: rate-limited until {retryAt}", {
+				url,
+				retryAt: new Date(retryAt).toISOString(),
+			});
+			throw new HTTPRateLimitError(url, 429, "Too Many Requests", retryAt);
+		}
+
+		try {
+			const response = await request();
+			if (retryAt !== undefined) {
+				delete state[host];
+				await this.saveState(state);
+			}
+			return response;
+		} catch (error) {
+			if (!(error instanceof HTTPRateLimitError)) {
+				throw error;
+			}
+			const now = this.clock.now();
+			const until = Math.min(
+				Math.max(error.retryAt ?? now + DEFAULT_BACKOFF_MS, now),
+				now + MAX_BACKOFF_MS,
+			);
+			httpLogger.warn("rate-limited by {host} until {retryAt}", {
+				host,
+				retryAt: new Date(until).toISOString(),
+			});
+			const retryNotBefore = Math.max(state[host] ?? 0, until);
+			state[host] = retryNotBefore;
+			await this.saveState(state);
+			throw new HTTPRateLimitError(
+				url,
+				error.status,
+				error.statusText,
+				retryNotBefore,
+			);
+		}
+	}
+
+	private loadState(): Promise<BackoffState> {
+		this.state ??= this.readState();
+		return this.state;
+	}
+
+	private async readState(): Promise<BackoffState> {
+		try {
+			const parsed: unknown = JSON.parse(
+				await this.fileService.readFile(this.statePath),
+			);
+			const state: BackoffState = {};
+			if (typeof parsed === "object" && parsed !== null) {
+				for (const [host, retryAt] of Object.entries(parsed)) {
+					if (typeof retryAt === "number") {
+						state[host] = retryAt;
+					}
+				}
+			}
+			return state;
+		} catch {
+			// Missing or unreadable state means no backoff is in effect
+			return {};
+		}
+	}
+
+	/**
+	 * Write the state, dropping windows that have already passed
+	 *
+	 * Failures are logged only; losing the state merely costs an extra request.
+	 */
+	private async saveState(state: BackoffState): Promise<void> {
+		const now = this.clock.now();
+		for (const [host, retryAt] of Object.entries(state)) {
+			if (retryAt <= now) {
+				delete state[host];
+			}
+		}
+		try {
+			await this.fileService.mkdir(dirname(this.statePath));
+			await writeFileAtomic(
+				this.fileService,
+				this.statePath,
+				JSON.stringify(state, null, 2),
+			);
+		} catch (error) {
+			httpLogger.debug("backoff state not saved: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+}
+
+function hostOf(url: string): string {
+	try {
+		return new URL(url).host;
+	} catch {
+		return url;
+	}
+}
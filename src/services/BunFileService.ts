@@ -1,20 +1,29 @@
 import { constants } from "node:fs";
 import {
 	access,
+	chmod,
+	type FileHandle,
 	mkdir as fsMkdir,
+	open,
 	readdir,
+	realpath,
+	rename as fsRename,
+	rmdir,
 	stat,
 	unlink,
+	writeFile as fsWriteFile,
 } from "node:fs/promises";
-import { dirname, join, relative } from "node:path";
+import { basename, dirname, join, relative, resolve } from "node:path";
 import type IFileService from "../interfaces/IFileService.ts";
 import {
 	FileIOError,
 	FileNotFoundError,
 	FilePermissionError,
 	type NamespacedFile,
+	type ScanOptions,
 } from "../interfaces/IFileService.ts";
 import { fileLogger } from "../utils/logger.js";
+import { matchesDirectoryPattern } from "../utils/paths.js";
 
 /**
  * Interface for Node.js system errors with error codes
@@ -101,6 +110,137 @@ export default class BunFileService implements IFileService {
 		}
 	}
 
+	/**
+	 * Create a file exclusively using Node.js fs.writeFile() with the "wx" flag
+	 */
+	async createFile(path: string, content: string): Promise<boolean> {
+		try {
+			const dir = dirname(path);
+			if (dir !== path) {
+				await this.mkdir(dir);
+			}
+
+			await fsWriteFile(path, content, { flag: "wx" });
+			fileLogger.debug("createFile success: {path}", { path });
+			return true;
+		} catch (error) {
+			if ((error as SystemError).code === "EEXIST") {
+				fileLogger.debug("createFile skipped, already exists: {path}", {
+					path,
+				});
+				return false;
+			}
+			fileLogger.error("createFile failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "write");
+		}
+	}
+
+	/**
+	 * Read raw bytes from a file using Bun.file()
+	 */
+	async readBinaryFile(path: string): Promise<Uint8Array> {
+		try {
+			const data = await Bun.file(path).bytes();
+			fileLogger.debug("read success: {path} ({bytes} bytes)", {
+				path,
+				bytes: data.length,
+			});
+			return data;
+		} catch (error) {
+			fileLogger.error("read failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "read");
+		}
+	}
+
+	/**
+	 * Write raw bytes to a file using Bun.write(), creating directories as needed
+	 */
+	async writeBinaryFile(path: string, data: Uint8Array): Promise<void> {
+		try {
+			const dir = dirname(path);
+			if (dir !== path) {
+				await this.mkdir(dir);
+			}
+
+			await Bun.write(path, data);
+			fileLogger.debug("write success: {path} ({bytes} bytes)", {
+				path,
+				bytes: data.length,
+			});
+		} catch (error) {
+			fileLogger.error("write failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "write");
+		}
+	}
+
+	/**
+	 * Write chunks to a file through a Node.js file handle as they arrive
+	 */
+	async writeBinaryStream(
+		path: string,
+		chunks: AsyncIterable<Uint8Array>,
+	): Promise<void> {
+		let file: FileHandle;
+		try {
+			const dir = dirname(path);
+			if (dir !== path) {
+				await this.mkdir(dir);
+			}
+			file = await open(path, "w");
+		} catch (error) {
+			fileLogger.error("write failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "write");
+		}
+
+		let bytes = 0;
+		try {
+			// Errors of the chunks themselves are the caller's, not ours
+			for await (const chunk of chunks) {
+				try {
+					await file.write(chunk);
+				} catch (error) {
+					fileLogger.error("write failed: {path} (error: {error})", {
+						path,
+						error: error instanceof Error ? error.message : String(error),
+					});
+					this.mapSystemError(error, path, "write");
+				}
+				bytes += chunk.length;
+			}
+		} finally {
+			await file.close();
+		}
+		fileLogger.debug("write success: {path} ({bytes} bytes)", { path, bytes });
+	}
+
+	/**
+	 * Make a file executable using Node.js fs.chmod()
+	 */
+	async makeExecutable(path: string): Promise<void> {
+		try {
+			await chmod(path, 0o755);
+			fileLogger.debug("makeExecutable success: {path}", { path });
+		} catch (error) {
+			fileLogger.error("makeExecutable failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "write");
+		}
+	}
+
 	/**
 	 * Check if a file or directory exists using fs.stat()
 	 */
@@ -173,6 +313,44 @@ export default class BunFileService implements IFileService {
 		}
 	}
 
+	/**
+	 * Remove an empty directory using Node.js fs.rmdir() (never recursive)
+	 */
+	async removeEmptyDirectory(path: string): Promise<boolean> {
+		try {
+			await rmdir(path);
+			fileLogger.debug("removeEmptyDirectory success: {path}", { path });
+			return true;
+		} catch (error) {
+			const code = (error as SystemError).code;
+			if (code === "ENOTEMPTY" || code === "EEXIST") {
+				return false;
+			}
+			fileLogger.error("removeEmptyDirectory failed: {path} (error: {error})", {
+				path,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, path, "delete");
+		}
+	}
+
+	/**
+	 * Rename a file using Node.js fs.rename() (atomic within a file system)
+	 */
+	async rename(from: string, to: string): Promise<void> {
+		try {
+			await fsRename(from, to);
+			fileLogger.debug("rename success: {from} -> {to}", { from, to });
+		} catch (error) {
+			fileLogger.error("rename failed: {from} -> {to} (error: {error})", {
+				from,
+				to,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			this.mapSystemError(error, from, "write");
+		}
+	}
+
 	/**
 	 * List files in a directory using Node.js fs.readdir()
 	 */
@@ -200,7 +378,10 @@ export default class BunFileService implements IFileService {
 	/**
 	 * List files recursively in a directory and all subdirectories
 	 */
-	async listFilesRecursive(path: string): Promise<string[]> {
+	async listFilesRecursive(
+		path: string,
+		{ maxDepth = Number.POSITIVE_INFINITY, ignore = [] }: ScanOptions = {},
+	): Promise<string[]> {
 		try {
 			const entries = await readdir(path, { withFileTypes: true });
 			const files: string[] = [];
@@ -208,10 +389,17 @@ export default class BunFileService implements IFileService {
 			for (const entry of entries) {
 				if (entry.isFile()) {
 					files.push(entry.name);
-				} else if (entry.isDirectory()) {
-					// Recursively scan subdirectories
+				} else if (
+					entry.isDirectory() &&
+					maxDepth > 0 &&
+					!matchesDirectoryPattern(entry.name, ignore)
+				) {
+					// Recursively scan subdirectories, pruning before reading them
 					const subDir = join(path, entry.name);
-					const subFiles = await this.listFilesRecursive(subDir);
+					const subFiles = await this.listFilesRecursive(subDir, {
+						maxDepth: maxDepth - 1,
+						ignore,
+					});
 
 					// Add subdirectory files with relative paths
 					for (const subFile of subFiles) {
@@ -226,6 +414,60 @@ export default class BunFileService implements IFileService {
 		}
 	}
 
+	/**
+	 * List subdirectories recursively using Node.js fs.readdir()
+	 */
+	async listDirectoriesRecursive(
+		path: string,
+		{ maxDepth = Number.POSITIVE_INFINITY, ignore = [] }: ScanOptions = {},
+	): Promise<string[]> {
+		try {
+			const entries = await readdir(path, { withFileTypes: true });
+			const directories: string[] = [];
+
+			for (const entry of entries) {
+				if (!entry.isDirectory()) {
+					continue;
+				}
+				directories.push(entry.name);
+				if (maxDepth > 0 && !matchesDirectoryPattern(entry.name, ignore)) {
+					const subDirectories = await this.listDirectoriesRecursive(
+						join(path, entry.name),
+						{ maxDepth: maxDepth - 1, ignore },
+					);
+					for (const subDirectory of subDirectories) {
+						directories.push(join(entry.name, subDirectory));
+					}
+				}
+			}
+
+			return directories;
+		} catch (error) {
+			this.mapSystemError(error, path, "list");
+		}
+	}
+
+	/**
+	 * Resolve links in a path, starting from its deepest existing ancestor
+	 */
+	async realPath(path: string): Promise<string> {
+		const missing: string[] = [];
+		let existing = resolve(path);
+		for (;;) {
+			try {
+				const real = await realpath(existing);
+				return join(real, ...missing.reverse());
+			} catch (error) {
+				const parent = dirname(existing);
+				if ((error as SystemError).code !== "ENOENT" || parent === existing) {
+					this.mapSystemError(error, path, "read");
+				}
+				missing.push(basename(existing));
+				existing = parent;
+			}
+		}
+	}
+
 	/**
 	 * Check if a path is writable
 	 */
@@ -245,11 +487,14 @@ export default class BunFileService implements IFileService {
 	 */
 	async scanNamespaceHierarchy(
 		basePath: string,
-		maxDepth = 10,
+		{ maxDepth = 10, ignore = [] }: ScanOptions = {},
 	): Promise<NamespacedFile[]> {
 		try {
 			const files: NamespacedFile[] = [];
-			await this.scanDirectoryRecursive(basePath, basePath, files, 0, maxDepth);
+			await this.scanDirectoryRecursive(basePath, basePath, files, 0, {
+				maxDepth,
+				ignore,
+			});
 			fileLogger.debug(
 				"scanNamespaceHierarchy success: {basePath} ({count} files)",
 				{
@@ -278,9 +523,9 @@ export default class BunFileService implements IFileService {
 		currentPath: string,
 		files: NamespacedFile[],
 		currentDepth: number,
-		maxDepth: number,
+		limits: Required<ScanOptions>,
 	): Promise<void> {
-		if (currentDepth > maxDepth) {
+		if (currentDepth > limits.maxDepth) {
 			return;
 		}
 
@@ -303,14 +548,18 @@ export default class BunFileService implements IFileService {
 						fileName: entry.name,
 						depth: currentDepth,
 					});
-				} else if (entry.isDirectory()) {
-					// Recursively scan subdirectories
+				} else if (
+					entry.isDirectory() &&
+					currentDepth < limits.maxDepth &&
+					!matchesDirectoryPattern(entry.name, limits.ignore)
+				) {
+					// Recursively scan subdirectories, pruning before reading them
 					await this.scanDirectoryRecursive(
 						basePath,
 						fullPath,
 						files,
 						currentDepth + 1,
-						maxDepth,
+						limits,
 					);
 				}
 			}
@@ -0,0 +1,125 @@
+import type IGitClient from "../interfaces/IGitClient.js";
+import { type GitCheckoutOptions, GitError } from "../interfaces/IGitClient.js";
+import { repoLogger } from "../utils/logger.js";
+
+/**
+ * Git client implementation that shells out to the git executable via Bun.spawn
+ *
+ * Using the system git binary means SSH keys, credential helpers and
+ * ~/.gitconfig settings apply exactly as they do for the user's own git usage,
+ * which is what makes private repositories over SSH work without extra setup.
+ *
+ * Working trees are kept shallow (depth 1) since only the latest files of the
+ * selected ref are needed to build a manifest.
+ *
+ * @example
+ * ```typescript
+ * const git = new BunGitClient();
+ * await git.clone("git@github.com:acme/commands.git", cacheDir, { ref: "v2.0.0" });
+ * ```
+ */
+export default class BunGitClient implements IGitClient {
+	constructor(private readonly gitBinary: string = "git") {}
+
+	async clone(
+		url: string,
+		directory: string,
+		options?: GitCheckoutOptions,
+	): Promise<void> {
+		const args = ["clone", "--depth", "1", "--quiet"];
+		if (options?.ref) {
+			args.push("--branch", options.ref);
+		}
+		// "--" prevents a URL starting with "-" from being parsed as an option
+		args.push("--", url, directory);
+
+		await this.run("clone", args);
+	}
+
+	async update(directory: string, options?: GitCheckoutOptions): Promise<void> {
+		const ref = options?.ref ?? "HEAD";
+
+		await this.run("fetch", [
+			"-C",
+			directory,
+			"fetch",
+			"--depth",
+			"1",
+			"--quiet",
+			"origin",
+			ref,
+		]);
+		await this.run("checkout", [
+			"-C",
+			directory,
+			"checkout",
+			"--force",
+			"--quiet",
+			"FETCH_HEAD",
+		]);
+	}
+
+	async getHeadCommit(directory: string): Promise<string> {
+		const output = await this.run("rev-parse", [
+			"-C",
+			directory,
+			"rev-parse",
+			"HEAD",
+		]);
+		return output.trim();
+	}
+
+	/**
+	 * Run a git command and return its standard output
+	 *
+	 * @param operation - Operation name used in errors and logs
+	 * @param args - Arguments passed to the git binary
+	 * @returns Captured standard output
+	 * @throws GitError when git cannot be started or exits with a non-zero code
+	 */
+	private async run(operation: string, args: string[]): Promise<string> {
+		repoLogger.debug("git {operation}: {args}", {
+			operation,
+			args: args.join(" "),
+		});
+
+		let proc: ReturnType<typeof Bun.spawn>;
+		try {
+			proc = Bun.spawn([this.gitBinary, ...args], {
+				stdin: "ignore",
+				stdout: "pipe",
+				stderr: "pipe",
+				env: {
+					...process.env,
+					// Never block waiting for a password prompt
+					GIT_TERMINAL_PROMPT: "0",
+				},
+			});
+		} catch (error) {
+			throw new GitError(
+				`Failed to run git: ${error instanceof Error ? error.message : error}`,
+				operation,
+			);
+		}
+
+		const [stdout, stderr, exitCode] = await Promise.all([
+			new Response(proc.stdout as ReadableStream).text(),
+			new Response(proc.stderr as ReadableStream).text(),
+			proc.exited,
+		]);
+
+		if (exitCode !== 0) {
+			repoLogger.debug("git {operation} failed: {stderr}", {
+				operation,
+				stderr: stderr.trim(),
+			});
+			throw new GitError(
+				`git ${operation} failed (exit code ${exitCode}): ${stderr.trim() || "no output"}`,
+				operation,
+				stderr,
+			);
+		}
+
+		return stdout;
+	}
+}
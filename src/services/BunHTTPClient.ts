@@ -1,11 +1,18 @@
 import type IHTTPClient from "../interfaces/IHTTPClient.ts";
-import type { HTTPOptions, HTTPResponse } from "../interfaces/IHTTPClient.ts";
+import type {
+	HTTPOptions,
+	HTTPResponse,
+	HTTPStreamResponse,
+} from "../interfaces/IHTTPClient.ts";
 import {
 	HTTPNetworkError,
+	HTTPRateLimitError,
+	HTTPResponseTooLargeError,
 	HTTPStatusError,
 	HTTPTimeoutError,
 } from "../interfaces/IHTTPClient.ts";
 import { httpLogger } from "../utils/logger.js";
+import { isRateLimitResponse, parseRetryAt } from "../utils/rateLimit.js";
 
 /**
  * Real HTTP client implementation using Bun's Web APIs
@@ -59,10 +66,14 @@ export default class BunHTTPClient implements IHTTPClient {
 	 * @param options - Optional request configuration
 	 * @param options.timeout - Request timeout in milliseconds (default: 5000)
 	 * @param options.headers - Request headers as key-value pairs
+	 * @param options.maxBytes - Largest response body accepted in bytes
+	 * @param options.encoding - Body decoding: "utf8" (default) or "latin1"
+	 * @param options.signal - Aborts the request before it completes
 	 * @returns Promise resolving to HTTP response with all required fields
-	 * @throws HTTPTimeoutError when request times out
+	 * @throws HTTPTimeoutError when request times out or options.signal aborts
 	 * @throws HTTPNetworkError when network connectivity fails or URL is invalid
 	 * @throws HTTPStatusError when server returns non-2xx status code
+	 * @throws HTTPResponseTooLargeError when the body exceeds options.maxBytes
 	 *
 	 * @example Successful request
 	 * ```typescript
@@ -80,6 +91,49 @@ export default class BunHTTPClient implements IHTTPClient {
 	 * ```
 	 */
 	async get(url: string, options?: HTTPOptions): Promise<HTTPResponse> {
+		const response = await this.stream(url, options);
+
+		const chunks: Uint8Array[] = [];
+		for await (const chunk of response.body) {
+			chunks.push(chunk);
+		}
+		const body = Buffer.concat(chunks).toString(options?.encoding ?? "utf8");
+
+		const contentLength =
+			response.headers["content-length"] ?? body.length.toString();
+		httpLogger.debug(
+			"response success: {url} - {status} {statusText} (content-length: {contentLength})",
+			{
+				url,
+				status: response.status,
+				statusText: response.statusText,
+				contentLength,
+			},
+		);
+
+		return { ...response, body };
+	}
+
+	/**
+	 * Perform an HTTP GET request, leaving the body to be read as needed
+	 *
+	 * Same request handling as get(); the body is read from the network chunk
+	 * by chunk as the caller iterates it, and an oversized or endless body is
+	 * cut off as soon as it exceeds options.maxBytes.
+	 *
+	 * @param url - The URL to request (must be a valid HTTP/HTTPS URL)
+	 * @param options - Optional request configuration (encoding is ignored)
+	 * @returns Promise resolving once the response headers arrived
+	 * @throws HTTPTimeoutError when request times out or options.signal aborts
+	 * @throws HTTPNetworkError when network connectivity fails or URL is invalid
+	 * @throws HTTPStatusError when server returns non-2xx status code
+	 * @throws HTTPResponseTooLargeError when the declared body size exceeds
+	 *   options.maxBytes
+	 */
+	async stream(
+		url: string,
+		options?: HTTPOptions,
+	): Promise<HTTPStreamResponse> {
 		// Extract timeout with safe fallback to default
 		const timeout = options?.timeout ?? BunHTTPClient.DEFAULT_TIMEOUT;
 
@@ -106,61 +160,72 @@ export default class BunHTTPClient implements IHTTPClient {
 			// Build Web-standard Request configuration
 			const requestInit: RequestInit = {
 				method: "GET",
-				signal: controller.signal,
+				signal: options?.signal
+					? AbortSignal.any([controller.signal, options.signal])
+					: controller.signal,
 				headers: this.processHeaders(options?.headers),
 			};
 
 			// Perform the Web-standard fetch request
+			httpLogger.trace("request: GET {url} (timeout: {timeout}ms)", {
+				url,
+				timeout,
+			});
 			const response = await fetch(url, requestInit);
 
 			// Clear timeout since request completed successfully
 			clearTimeout(timeoutId);
 
+			const headers = this.processResponseHeaders(response.headers);
+
 			// Check for HTTP status errors (non-2xx responses)
 			if (!response.ok) {
+				if (isRateLimitResponse(response.status, headers)) {
+					throw new HTTPRateLimitError(
+						url,
+						response.status,
+						response.statusText,
+						parseRetryAt(headers, Date.now()),
+					);
+				}
 				throw new HTTPStatusError(url, response.status, response.statusText);
 			}
 
-			// Process response headers and body concurrently for better performance
-			const [headers, body] = await Promise.all([
-				this.processResponseHeaders(response.headers),
-				response.text(),
-			]);
-
-			const contentLength = headers["content-length"] ?? body.length.toString();
-			httpLogger.debug(
-				"response success: {url} - {status} {statusText} (content-length: {contentLength})",
-				{
-					url,
-					status: response.status,
-					statusText: response.statusText,
-					contentLength,
-				},
-			);
+			// Refuse early when the server announces an oversized body
+			const maxBytes = options?.maxBytes;
+			if (
+				maxBytes !== undefined &&
+				Number(headers["content-length"]) > maxBytes
+			) {
+				await response.body?.cancel();
+				throw new HTTPResponseTooLargeError(url, maxBytes);
+			}
 
-			// Return properly formatted HTTP response
 			return {
 				status: response.status,
 				statusText: response.statusText,
 				headers,
-				body,
+				body: this.readChunks(response, url, timeout, maxBytes),
 				url: response.url, // Final URL after any redirects
 			};
 		} catch (error) {
 			// Always clear timeout on any error to prevent memory leaks
 			clearTimeout(timeoutId);
-
-			// Log error before mapping and throwing
-			httpLogger.error("request failed: {url} (error: {error})", {
-				url,
-				error: error instanceof Error ? error.message : String(error),
-			});
-
-			// Map Web API errors to custom error types
-			throw this.mapError(error, url, timeout);
+			throw this.fail(error, url, timeout);
 		}
 	}
 
+	/**
+	 * Log a failed request and throw the matching custom error type
+	 */
+	private fail(error: unknown, url: string, timeout: number): never {
+		httpLogger.error("request failed: {url} (error: {error})", {
+			url,
+			error: error instanceof Error ? error.message : String(error),
+		});
+		throw this.mapError(error, url, timeout);
+	}
+
 	/**
 	 * Validate URL format and content
 	 *
@@ -254,6 +319,52 @@ export default class BunHTTPClient implements IHTTPClient {
 		return headers;
 	}
 
+	/**
+	 * Read the response body chunk by chunk, stopping as soon as it exceeds
+	 * maxBytes
+	 *
+	 * Chunks are read only as they are consumed, so an oversized or endless
+	 * response is cut off early instead of being buffered whole.
+	 *
+	 * @param response - Response to read
+	 * @param url - The request URL, for errors
+	 * @param timeout - The configured timeout, for errors
+	 * @param maxBytes - Largest body accepted, if limited
+	 * @throws HTTPResponseTooLargeError when the body exceeds maxBytes
+	 * @throws HTTPNetworkError when the connection fails while reading
+	 */
+	private async *readChunks(
+		response: Response,
+		url: string,
+		timeout: number,
+		maxBytes: number | undefined,
+	): AsyncGenerator<Uint8Array> {
+		if (!response.body) {
+			return;
+		}
+
+		const reader = response.body.getReader();
+		let received = 0;
+		try {
+			for (;;) {
+				const { done, value } = await reader.read();
+				if (done) {
+					return;
+				}
+				received += value.byteLength;
+				if (maxBytes !== undefined && received > maxBytes) {
+					throw new HTTPResponseTooLargeError(url, maxBytes);
+				}
+				yield value;
+			}
+		} catch (error) {
+			throw this.fail(error, url, timeout);
+		} finally {
+			// Releases the connection when the caller stopped early
+			await reader.cancel().catch(() => undefined);
+		}
+	}
+
 	/**
 	 * Map Web API and other errors to custom error types
 	 *
@@ -264,8 +375,11 @@ export default class BunHTTPClient implements IHTTPClient {
 	 * @throws Appropriate custom error type
 	 */
 	private mapError(error: unknown, url: string, timeout: number): never {
-		// Handle AbortError (timeout)
-		if (error instanceof Error && error.name === "AbortError") {
+		// Handle AbortError (timeout) and TimeoutError (AbortSignal.timeout())
+		if (
+			error instanceof Error &&
+			(error.name === "AbortError" || error.name === "TimeoutError")
+		) {
 			throw new HTTPTimeoutError(url, timeout);
 		}
 
@@ -273,7 +387,8 @@ export default class BunHTTPClient implements IHTTPClient {
 		if (
 			error instanceof HTTPTimeoutError ||
 			error instanceof HTTPNetworkError ||
-			error instanceof HTTPStatusError
+			error instanceof HTTPStatusError ||
+			error instanceof HTTPResponseTooLargeError
 		) {
 			throw error;
 		}
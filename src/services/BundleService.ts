@@ -0,0 +1,379 @@
+import * as path from "node:path";
+import type ICacheManager from "../interfaces/ICacheManager.js";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import type IProgressReporter from "../interfaces/IProgressReporter.js";
+import type IRepository from "../interfaces/IRepository.js";
+import type { Manifest } from "../types/Command.js";
+import { repoLogger } from "../utils/logger.js";
+import { isValidFileName, isValidLanguageCode } from "../utils/naming.js";
+import { createTarGz, extractTarGz, type TarEntry } from "../utils/tar.js";
+import { migrateManifest, type RawManifest } from "./ManifestMigrations.js";
+import SystemClock from "./SystemClock.js";
+import {
+	type JournalOperation,
+	TransactionJournal,
+} from "./TransactionJournal.js";
+
+/**
+ * Current bundle format version, stored in bundle.json
+ */
+export const BUNDLE_FORMAT_VERSION = 1;
+
+/**
+ * Metadata stored as bundle.json at the root of every bundle
+ */
+export interface BundleMetadata {
+	/** Bundle format version */
+	readonly format: number;
+	/** Language of the bundled commands */
+	readonly language: string;
+	/** ISO 8601 timestamp of when the bundle was created */
+	readonly createdAt: string;
+	/** Number of commands in the bundle */
+	readonly commandCount: number;
+}
+
+/**
+ * Result of a bundle export or import
+ */
+export interface BundleResult {
+	/** Language of the bundled commands */
+	readonly language: string;
+	/** Number of commands written or read */
+	readonly commandCount: number;
+	/** Archive path (export) or directory the bundle was imported into (import) */
+	readonly path: string;
+}
+
+/**
+ * Error thrown when a bundle cannot be created or imported
+ */
+export class BundleError extends Error {
+	constructor(
+		message: string,
+		public readonly bundlePath: string,
+		public override readonly cause?: Error,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Offline bundle export and import
+ *
+ * A bundle is a tar.gz archive holding everything needed to install commands
+ * of one language without network access:
+ *
+ * ```
+ * bundle.json                     BundleMetadata
+ * commands/{lang}/manifest.json   the language manifest
+ * commands/{lang}/{file}          every command file listed in the manifest
+ * ```
+ *
+ * Importing extracts the archive into {bundlesDir}/commands/{lang}, which is
+ * read by a FileSystemRepository when the configured repository cannot be
+ * reached, and primes the manifest cache so list/search work immediately.
+ */
+export class BundleService {
+	/**
+	 * @param repository - Repository used to read commands when exporting
+	 * @param fileService - File service for archive and bundle directory I/O
+	 * @param cacheManager - Manifest cache primed on import
+	 * @param bundlesDir - Directory bundles are imported into
+	 * @param clock - Clock for bundle creation timestamps
+	 * @param journal - Journal making imports all-or-nothing
+	 */
+	constructor(
+		private readonly repository: IRepository,
+		private readonly fileService: IFileService,
+		private readonly cacheManager: ICacheManager,
+		private readonly bundlesDir: string,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly journal: TransactionJournal = new TransactionJournal(
+			fileService,
+			path.join(bundlesDir, ".journal"),
+			clock,
+		),
+	) {}
+
+	/**
+	 * Get the directory holding imported commands (one subdirectory per language)
+	 */
+	getCommandsDir(): string {
+		return path.join(this.bundlesDir, "commands");
+	}
+
+	/**
+	 * Package the manifest and all command files of a language
+	 *
+	 * @param language - Language to export
+	 * @param outputPath - Archive path (conventionally ending in .tar.gz)
+	 * @param progress - Receives one step per command file fetched
+	 * @returns Export summary
+	 * @throws RepositoryError when the manifest or a command cannot be fetched
+	 * @throws BundleError when the archive cannot be written
+	 */
+	async exportBundle(
+		language: string,
+		outputPath: string,
+		progress?: IProgressReporter,
+	): Promise<BundleResult> {
+		const manifest = await this.repository.getManifest(language);
+		const entries: TarEntry[] = [];
+
+		const metadata: BundleMetadata = {
+			format: BUNDLE_FORMAT_VERSION,
+			language,
+			createdAt: new Date(this.clock.now()).toISOString(),
+			commandCount: manifest.commands.length,
+		};
+		entries.push({
+			path: "bundle.json",
+			content: JSON.stringify(metadata, null, 2),
+		});
+		entries.push({
+			path: `commands/${language}/manifest.json`,
+			content: JSON.stringify(manifest, null, 2),
+		});
+
+		progress?.start(`Exporting ${language} commands`, manifest.commands.length);
+		try {
+			for (const command of manifest.commands) {
+				this.assertSafeRelativePath(command.file, outputPath);
+				const content = await this.repository.getCommand(
+					command.name,
+					language,
+				);
+				entries.push({
+					path: `commands/${language}/${command.file.replace(/\\/g, "/")}`,
+					content,
+				});
+				progress?.advance(1, Buffer.byteLength(content, "utf8"));
+			}
+		} finally {
+			progress?.finish();
+		}
+
+		try {
+			const archive = createTarGz(entries, new Date(this.clock.now()));
+			await this.fileService.writeBinaryFile(outputPath, archive);
+		} catch (error) {
+			throw new BundleError(
+				`Failed to write bundle: ${error instanceof Error ? error.message : error}`,
+				outputPath,
+				error instanceof Error ? error : undefined,
+			);
+		}
+
+		repoLogger.debug("bundle exported: {path} ({count} commands)", {
+			path: outputPath,
+			count: manifest.commands.length,
+		});
+
+		return {
+			language,
+			commandCount: manifest.commands.length,
+			path: outputPath,
+		};
+	}
+
+	/**
+	 * Import a bundle created by exportBundle()
+	 *
+	 * Replaces any previously imported bundle for the same language.
+	 *
+	 * @param inputPath - Archive to import
+	 * @param progress - Reports the bundle's files as they are written
+	 * @returns Import summary
+	 * @throws BundleError when the archive is missing, malformed or unsafe
+	 */
+	async importBundle(
+		inputPath: string,
+		progress?: IProgressReporter,
+	): Promise<BundleResult> {
+		let entries: TarEntry[];
+		try {
+			entries = extractTarGz(await this.fileService.readBinaryFile(inputPath));
+		} catch (error) {
+			throw new BundleError(
+				`Failed to read bundle: ${error instanceof Error ? error.message : error}`,
+				inputPath,
+				error instanceof Error ? error : undefined,
+			);
+		}
+
+		const metadata = this.parseMetadata(entries, inputPath);
+		const { language } = metadata;
+		const prefix = `commands/${language}/`;
+
+		const manifestEntry = entries.find(
+			(entry) => entry.path === `${prefix}manifest.json`,
+		);
+		if (!manifestEntry) {
+			throw new BundleError(
+				`Bundle does not contain ${prefix}manifest.json`,
+				inputPath,
+			);
+		}
+		const manifest = this.parseManifest(
+			manifestEntry.content,
+			language,
+			inputPath,
+		);
+
+		// Every command listed in the manifest must be present
+		const files = new Map(
+			entries
+				.filter((entry) => entry.path.startsWith(prefix))
+				.map((entry): [string, string] => [
+					entry.path.slice(prefix.length),
+					entry.content,
+				]),
+		);
+		for (const command of manifest.commands) {
+			this.assertSafeRelativePath(command.file, inputPath);
+			if (!files.has(command.file.replace(/\\/g, "/"))) {
+				throw new BundleError(
+					`Bundle is missing file "${command.file}" for command "${command.name}"`,
+					inputPath,
+				);
+			}
+		}
+
+		// Replace the previous import in one transaction so a crash never
+		// leaves a mix of old and new files behind
+		const languageDir = path.join(this.getCommandsDir(), language);
+		const operations: JournalOperation[] = [];
+		for (const [relativePath, content] of files) {
+			this.assertSafeRelativePath(relativePath, inputPath);
+			operations.push({
+				type: "write",
+				path: path.join(languageDir, ...relativePath.split("/")),
+				content,
+			});
+		}
+		const written = new Set(operations.map((operation) => operation.path));
+		for (const stale of await this.listDirectoryFiles(languageDir)) {
+			if (!written.has(stale)) {
+				operations.push({ type: "delete", path: stale });
+			}
+		}
+		progress?.start(`Importing ${language} bundle`, files.size);
+		try {
+			await this.journal.run(`bundle import (${language})`, operations);
+			progress?.advance(files.size);
+		} finally {
+			progress?.finish();
+		}
+
+		await this.cacheManager.set(language, manifest);
+
+		repoLogger.debug("bundle imported: {path} ({count} commands)", {
+			path: languageDir,
+			count: manifest.commands.length,
+		});
+
+		return {
+			language,
+			commandCount: manifest.commands.length,
+			path: languageDir,
+		};
+	}
+
+	private parseMetadata(
+		entries: readonly TarEntry[],
+		inputPath: string,
+	): BundleMetadata {
+		const entry = entries.find((e) => e.path === "bundle.json");
+		if (!entry) {
+			throw new BundleError(
+				"Not a claude-cmd bundle (bundle.json missing)",
+				inputPath,
+			);
+		}
+
+		let metadata: Partial<BundleMetadata>;
+		try {
+			metadata = JSON.parse(entry.content);
+		} catch {
+			throw new BundleError("Invalid bundle.json", inputPath);
+		}
+
+		if (metadata.format !== BUNDLE_FORMAT_VERSION) {
+			throw new BundleError(
+				`Unsupported bundle format ${metadata.format} (expected ${BUNDLE_FORMAT_VERSION})`,
+				inputPath,
+			);
+		}
+		if (
+			typeof metadata.language !== "string" ||
+			!isValidLanguageCode(metadata.language)
+		) {
+			throw new BundleError(
+				`Invalid bundle language "${metadata.language}"`,
+				inputPath,
+			);
+		}
+
+		return metadata as BundleMetadata;
+	}
+
+	private parseManifest(
+		content: string,
+		language: string,
+		inputPath: string,
+	): Manifest {
+		let manifest: unknown;
+		try {
+			manifest = JSON.parse(content);
+		} catch {
+			throw new BundleError("Invalid manifest.json in bundle", inputPath);
+		}
+
+		if (
+			!manifest ||
+			typeof manifest !== "object" ||
+			!Array.isArray((manifest as { commands?: unknown }).commands)
+		) {
+			throw new BundleError(
+				"Bundle manifest does not contain valid commands array",
+				inputPath,
+			);
+		}
+
+		try {
+			return migrateManifest(manifest as RawManifest, language);
+		} catch (error) {
+			throw new BundleError(
+				error instanceof Error ? error.message : String(error),
+				inputPath,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
+	/**
+	 * Reject paths that could escape the language directory
+	 */
+	private assertSafeRelativePath(file: string, bundlePath: string): void {
+		if (!isValidFileName(file)) {
+			throw new BundleError(
+				`Unsafe file path in bundle: "${file}"`,
+				bundlePath,
+			);
+		}
+	}
+
+	/**
+	 * List files of a previously imported language so removed commands do not linger
+	 */
+	private async listDirectoryFiles(directory: string): Promise<string[]> {
+		if (!(await this.fileService.exists(directory))) {
+			return [];
+		}
+		const files = await this.fileService.listFilesRecursive(directory);
+		return files.map((file) => path.join(directory, file));
+	}
+}
@@ -1,9 +1,21 @@
-import * as os from "node:os";
 import * as path from "node:path";
+import type ICacheManager from "../interfaces/ICacheManager";
+import type {
+	CacheInspection,
+	CachedManifest,
+} from "../interfaces/ICacheManager";
+import type IClock from "../interfaces/IClock";
 import type IFileService from "../interfaces/IFileService";
 import { FileNotFoundError } from "../interfaces/IFileService";
 import type { Manifest } from "../types/Command";
+import { writeFileAtomic } from "../utils/atomicWrite";
+import { resolveCacheDir } from "../utils/cacheDir";
+import { withFileLock } from "../utils/fileLock";
+import { cacheLogger } from "../utils/logger";
+import { isValidLanguageCode } from "../utils/naming";
+import { compareStrings } from "../utils/ordering";
 import { LanguageDetector } from "./LanguageDetector";
+import SystemClock from "./SystemClock";
 
 /**
  * Cache entry structure that stores the manifest with metadata
@@ -15,6 +27,11 @@ interface CacheEntry {
 	timestamp: number;
 }
 
+/**
+ * Number of replaced manifests kept per language by default
+ */
+export const DEFAULT_MANIFEST_HISTORY = 5;
+
 /**
  * Error thrown when cache operations fail
  */
@@ -42,8 +59,18 @@ export class CacheError extends Error {
  * - Graceful error handling with proper fallbacks
  * - Cache invalidation support
  * - Robust handling of corrupted cache files
+ * - Safe concurrent use from several processes
+ *
+ * Writers take a per-language lock file next to the manifest so parallel
+ * invocations (e.g., CI jobs) serialize their updates. Readers do not lock:
+ * every write replaces the manifest atomically, so a reader sees either the
+ * old or the new manifest.
+ *
+ * When a write changes the cached commands, the replaced manifest is moved
+ * to a history directory next to it (one file per timestamp) so later runs
+ * can tell what changed between updates.
  */
-export class CacheManager {
+export class CacheManager implements ICacheManager {
 	private readonly cacheDir: string;
 	private readonly defaultMaxAge: number = 604800000; // 1 week (7 days) in milliseconds
 	private readonly languageDetector = new LanguageDetector();
@@ -52,14 +79,18 @@ export class CacheManager {
 	 * Create a new CacheManager instance
 	 *
 	 * @param fileService - File service implementation for I/O operations
-	 * @param cacheDir - Optional custom cache directory (defaults to ~/.cache/claude-cmd/commands)
+	 * @param cacheDir - Optional custom cache directory (defaults to the
+	 *   commands directory under the resolved cache directory)
+	 * @param clock - Clock used for timestamps and expiration (defaults to system time)
+	 * @param historySize - Replaced manifests kept per language (0 keeps none)
 	 */
 	constructor(
 		private readonly fileService: IFileService,
 		cacheDir?: string,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly historySize: number = DEFAULT_MANIFEST_HISTORY,
 	) {
-		this.cacheDir =
-			cacheDir ?? path.join(os.homedir(), ".cache", "claude-cmd", "commands");
+		this.cacheDir = cacheDir ?? path.join(resolveCacheDir(), "commands");
 	}
 
 	/**
@@ -77,20 +108,30 @@ export class CacheManager {
 
 			// Handle empty files (cleared cache)
 			if (!content.trim()) {
+				cacheLogger.debug("cache miss: {language} (cleared)", { language });
 				return null;
 			}
 
 			const entry = this.parseCacheEntry(content);
 			if (!entry) {
+				cacheLogger.warn("cache miss: {language} (unreadable cache file)", {
+					language,
+					cachePath,
+				});
 				return null;
 			}
 
 			// Check if cache is expired
-			const now = Date.now();
-			if (now - entry.timestamp > this.defaultMaxAge) {
+			const ageMs = this.clock.now() - entry.timestamp;
+			if (ageMs > this.defaultMaxAge) {
+				cacheLogger.debug("cache miss: {language} (expired)", {
+					language,
+					ageMs,
+				});
 				return null;
 			}
 
+			cacheLogger.debug("cache hit: {language}", { language, ageMs });
 			return entry.manifest;
 		} catch (error) {
 			return this.handleCacheReadError(error, language);
@@ -115,17 +156,23 @@ export class CacheManager {
 			const cachePath = this.getCachePath(language);
 			const entry: CacheEntry = {
 				manifest,
-				timestamp: timestamp ?? Date.now(),
+				timestamp: timestamp ?? this.clock.now(),
 			};
 
 			// Ensure cache directory exists
 			const cacheDir = path.dirname(cachePath);
 			await this.fileService.mkdir(cacheDir);
 
-			await this.fileService.writeFile(
-				cachePath,
-				JSON.stringify(entry, null, 2),
-			);
+			// Atomic so a crash mid-write never leaves a truncated manifest
+			await this.withLock(language, async () => {
+				await this.archivePrevious(language, entry);
+				await writeFileAtomic(
+					this.fileService,
+					cachePath,
+					JSON.stringify(entry, null, 2),
+				);
+			});
+			cacheLogger.debug("cache written: {language}", { language, cachePath });
 		} catch (error) {
 			throw new CacheError(
 				`Failed to store cache for language "${language}"`,
@@ -160,7 +207,7 @@ export class CacheManager {
 				return true; // Invalid cache entry is considered expired
 			}
 
-			const now = Date.now();
+			const now = this.clock.now();
 			return now - entry.timestamp > effectiveMaxAge;
 		} catch (error) {
 			// Handle string errors from InMemoryFileService
@@ -184,12 +231,13 @@ export class CacheManager {
 		const cachePath = this.getCachePath(language);
 
 		try {
-			const exists = await this.fileService.exists(cachePath);
-			if (exists) {
-				// Since we don't have a delete method in IFileService,
-				// we'll write an empty file to effectively "clear" it
-				await this.fileService.writeFile(cachePath, "");
-			}
+			await this.withLock(language, async () => {
+				const exists = await this.fileService.exists(cachePath);
+				if (exists) {
+					// Write an empty file so the cache reads as cleared
+					await writeFileAtomic(this.fileService, cachePath, "");
+				}
+			});
 		} catch (error) {
 			// Ignore errors when clearing non-existent cache
 			if (!(error instanceof FileNotFoundError)) {
@@ -198,6 +246,144 @@ export class CacheManager {
 		}
 	}
 
+	/**
+	 * Delete the cache file of a language entirely
+	 *
+	 * Unlike clear(), which empties the file, this removes it so corrupted
+	 * entries disappear from listings.
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns True if a cache file was removed
+	 */
+	async remove(language: string): Promise<boolean> {
+		this.validateLanguage(language);
+
+		const cachePath = this.getCachePath(language);
+		if (!(await this.fileService.exists(cachePath))) {
+			return false;
+		}
+
+		return this.withLock(language, async () => {
+			try {
+				await this.fileService.deleteFile(cachePath);
+				return true;
+			} catch (error) {
+				if (error instanceof FileNotFoundError) {
+					return false;
+				}
+				throw error;
+			}
+		});
+	}
+
+	/**
+	 * List languages that have a cache file, sorted by language code
+	 *
+	 * @returns Language codes with a cached manifest (in any state)
+	 */
+	async listLanguages(): Promise<string[]> {
+		if (!(await this.fileService.exists(this.cacheDir))) {
+			return [];
+		}
+
+		const languages = new Set<string>();
+		for (const file of await this.fileService.listFilesRecursive(
+			this.cacheDir,
+		)) {
+			const [language, name, ...rest] = file.split(/[/\\]/);
+			if (
+				language &&
+				name === "manifest.json" &&
+				rest.length === 0 &&
+				isValidLanguageCode(language)
+			) {
+				languages.add(language);
+			}
+		}
+
+		return [...languages].sort(compareStrings);
+	}
+
+	/**
+	 * Inspect the cache file of a language without modifying it
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Cache details, or null if no cache file exists
+	 */
+	async inspect(language: string): Promise<CacheInspection | null> {
+		this.validateLanguage(language);
+		const cachePath = this.getCachePath(language);
+
+		let content: string;
+		try {
+			content = await this.fileService.readFile(cachePath);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return null;
+			}
+			throw error;
+		}
+
+		const base = {
+			language,
+			path: cachePath,
+			sizeBytes: Buffer.byteLength(content, "utf8"),
+		};
+
+		if (!content.trim()) {
+			return { ...base, status: "empty" };
+		}
+
+		const entry = this.parseCacheEntry(content);
+		if (!entry || !Array.isArray(entry.manifest.commands)) {
+			return { ...base, status: "corrupted" };
+		}
+
+		return {
+			...base,
+			status:
+				this.clock.now() - entry.timestamp > this.defaultMaxAge
+					? "expired"
+					: "valid",
+			timestamp: entry.timestamp,
+			commandCount: entry.manifest.commands.length,
+		};
+	}
+
+	/**
+	 * List the cached manifests of a language, newest first
+	 *
+	 * The first entry is the current cache file (even when expired), followed
+	 * by the manifests it replaced. Unreadable files are skipped.
+	 *
+	 * @param language - Language code (e.g., "en", "es")
+	 * @returns Cached manifests with the time each was cached
+	 */
+	async getHistory(language: string): Promise<CachedManifest[]> {
+		this.validateLanguage(language);
+
+		const entries: CachedManifest[] = [];
+		const current = await this.readEntry(this.getCachePath(language));
+		if (current) {
+			entries.push(current);
+		}
+		for (const file of await this.listHistoryFiles(language)) {
+			const entry = await this.readEntry(file);
+			if (entry) {
+				entries.push(entry);
+			}
+		}
+
+		return entries.sort((a, b) => b.timestamp - a.timestamp);
+	}
+
+	/**
+	 * Get the root cache directory (one subdirectory per language)
+	 */
+	getCacheDir(): string {
+		return this.cacheDir;
+	}
+
 	/**
 	 * Get the file path for cached manifest of a specific language
 	 *
@@ -208,6 +394,108 @@ export class CacheManager {
 		return path.join(this.cacheDir, language, "manifest.json");
 	}
 
+	/**
+	 * Get the directory holding the replaced manifests of a language
+	 */
+	private getHistoryDir(language: string): string {
+		return path.join(this.cacheDir, language, "history");
+	}
+
+	/**
+	 * Move the current manifest to the history if the new entry changes its
+	 * commands, then drop the oldest entries beyond the history size
+	 *
+	 * Must be called while holding the language's lock.
+	 *
+	 * @param language - Language code whose cache is replaced
+	 * @param next - Entry about to be written
+	 */
+	private async archivePrevious(
+		language: string,
+		next: CacheEntry,
+	): Promise<void> {
+		if (this.historySize <= 0) {
+			return;
+		}
+
+		const previous = await this.readEntry(this.getCachePath(language));
+		if (
+			!previous ||
+			JSON.stringify(previous.manifest.commands) ===
+				JSON.stringify(next.manifest.commands)
+		) {
+			return;
+		}
+
+		const historyDir = this.getHistoryDir(language);
+		await this.fileService.mkdir(historyDir);
+		await writeFileAtomic(
+			this.fileService,
+			path.join(historyDir, `${previous.timestamp}.json`),
+			JSON.stringify(previous, null, 2),
+		);
+
+		// Newest first, so everything past the history size is the oldest
+		const files = await this.listHistoryFiles(language);
+		for (const file of files.slice(this.historySize)) {
+			await this.fileService.deleteFile(file);
+		}
+		cacheLogger.debug("cache archived: {language}", {
+			language,
+			timestamp: previous.timestamp,
+		});
+	}
+
+	/**
+	 * List the history files of a language, newest first
+	 */
+	private async listHistoryFiles(language: string): Promise<string[]> {
+		const historyDir = this.getHistoryDir(language);
+		if (!(await this.fileService.exists(historyDir))) {
+			return [];
+		}
+
+		return (await this.fileService.listFiles(historyDir))
+			.map((file) => path.basename(file))
+			.filter((file) => /^\d+\.json$/.test(file))
+			.sort((a, b) => Number.parseInt(b, 10) - Number.parseInt(a, 10))
+			.map((file) => path.join(historyDir, file));
+	}
+
+	/**
+	 * Read a cache entry file, or null if it is missing or unreadable
+	 */
+	private async readEntry(filePath: string): Promise<CacheEntry | null> {
+		try {
+			const entry = this.parseCacheEntry(
+				await this.fileService.readFile(filePath),
+			);
+			return entry && Array.isArray(entry.manifest.commands) ? entry : null;
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return null;
+			}
+			throw error;
+		}
+	}
+
+	/**
+	 * Run a cache mutation while holding the language's lock file
+	 *
+	 * @param language - Language code whose cache is modified
+	 * @param fn - Mutation to run
+	 * @returns The result of fn
+	 * @throws LockTimeoutError if another process holds the lock too long
+	 */
+	private withLock<T>(language: string, fn: () => Promise<T>): Promise<T> {
+		return withFileLock(
+			this.fileService,
+			`${this.getCachePath(language)}.lock`,
+			fn,
+			{ clock: this.clock },
+		);
+	}
+
 	/**
 	 * Validate language code using LanguageDetector
 	 *
@@ -262,16 +550,20 @@ export class CacheManager {
 	 * @param language - Language code for context
 	 * @returns null for recoverable errors
 	 */
-	private handleCacheReadError(error: unknown, _language: string): null {
+	private handleCacheReadError(error: unknown, language: string): null {
 		// Handle string errors from InMemoryFileService
 		if (
 			error instanceof FileNotFoundError ||
 			(typeof error === "string" && error.includes("File not found"))
 		) {
+			cacheLogger.debug("cache miss: {language} (not cached)", { language });
 			return null;
 		}
 
-		console.error("Unexpected error reading cache:", error);
+		cacheLogger.warn("unexpected error reading cache: {language} ({error})", {
+			language,
+			error: error instanceof Error ? error.message : String(error),
+		});
 
 		// For any other errors, we'll return null to trigger cache regeneration
 		// This provides resilience against corrupted cache files
@@ -4,6 +4,7 @@ import type {
 	CommandChange,
 	ManifestComparisonResult,
 } from "../types/index.js";
+import { PLAIN_THEME, type Theme } from "../utils/style.js";
 
 /**
  * Service for formatting change detection results for display
@@ -14,11 +15,17 @@ import type {
 export class ChangeDisplayFormatter {
 	/**
 	 * Format cache update results with changes for display
+	 *
+	 * @param result - Outcome of the cache update
+	 * @param theme - Styles for the counts (default: plain)
 	 */
-	formatUpdateSummary(result: CacheUpdateResultWithChanges): string {
+	formatUpdateSummary(
+		result: CacheUpdateResultWithChanges,
+		theme: Theme = PLAIN_THEME,
+	): string {
 		const lines: string[] = [];
 
-		lines.push("Command manifest updated successfully!");
+		lines.push(theme.success("Command manifest updated successfully!"));
 		lines.push(`Language: ${result.language}`);
 		lines.push(`Commands available: ${result.commandCount}`);
 		lines.push(`Updated at: ${new Date(result.timestamp).toLocaleString()}`);
@@ -26,16 +33,24 @@ export class ChangeDisplayFormatter {
 
 		if (result.hasChanges) {
 			const totalChanges = result.added + result.removed + result.modified;
-			lines.push(`📊 Changes detected: ${totalChanges} total`);
+			lines.push(
+				theme.heading(`📊 Changes detected: ${totalChanges} total`),
+			);
 
 			if (result.added > 0) {
-				lines.push(`  ➕ Added: ${result.added} commands`);
+				lines.push(
+					`  ➕ ${theme.success(`Added: ${result.added} commands`)}`,
+				);
 			}
 			if (result.modified > 0) {
-				lines.push(`  🔄 Modified: ${result.modified} commands`);
+				lines.push(
+					`  🔄 ${theme.warning(`Modified: ${result.modified} commands`)}`,
+				);
 			}
 			if (result.removed > 0) {
-				lines.push(`  ➖ Removed: ${result.removed} commands`);
+				lines.push(
+					`  ➖ ${theme.error(`Removed: ${result.removed} commands`)}`,
+				);
 			}
 		} else {
 			lines.push("✅ No changes detected");
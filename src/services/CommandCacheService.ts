@@ -1,17 +1,31 @@
+import type ICacheManager from "../interfaces/ICacheManager.js";
+import type IClock from "../interfaces/IClock.js";
 import type IManifestComparison from "../interfaces/IManifestComparison.js";
 import type IRepository from "../interfaces/IRepository.js";
 import type {
 	CacheUpdateResult,
 	CacheUpdateResultWithChanges,
 	CommandServiceOptions,
+	Manifest,
+	PrefetchProgress,
+	PrefetchResult,
 } from "../types/Command.js";
 import type { ManifestComparisonResult } from "../types/ManifestComparison.js";
-import type { CacheManager } from "./CacheManager.js";
+import { mapConcurrent } from "../utils/concurrency.js";
+import { cacheLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+import type { ContentSearchIndex } from "./ContentSearchIndex.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 import {
 	resolveLanguage,
 	withErrorHandling,
 } from "./shared/CommandServiceHelpers.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Default number of command files downloaded in parallel by prefetch
+ */
+export const DEFAULT_PREFETCH_CONCURRENCY = 8;
 
 /**
  * CommandCacheService handles cache management and update operations.
@@ -19,14 +33,18 @@ import {
  * Responsibilities:
  * - Update local cache with fresh manifest data
  * - Detect changes between cached and new manifests
+ * - Prefetch command files so later installs work offline
+ * - Keep an existing content search index in step with updated manifests
  * - Coordinate with repository and manifest comparison services
  */
 export class CommandCacheService {
 	constructor(
 		private readonly repository: IRepository,
-		private readonly cacheManager: CacheManager,
+		private readonly cacheManager: ICacheManager,
 		private readonly languageDetector: LanguageDetector,
 		private readonly manifestComparison: IManifestComparison,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly contentIndex?: ContentSearchIndex,
 	) {}
 
 	/**
@@ -45,10 +63,11 @@ export class CommandCacheService {
 
 			// Update cache with fresh manifest
 			await this.cacheManager.set(language, manifest);
+			await this.refreshContentIndex(language, manifest);
 
 			return {
 				language,
-				timestamp: Date.now(),
+				timestamp: this.clock.now(),
 				commandCount: manifest.commands.length,
 			};
 		});
@@ -95,10 +114,11 @@ export class CommandCacheService {
 
 			// Update cache with fresh manifest
 			await this.cacheManager.set(language, newManifest);
+			await this.refreshContentIndex(language, newManifest);
 
 			return {
 				language,
-				timestamp: Date.now(),
+				timestamp: this.clock.now(),
 				commandCount: newManifest.commands.length,
 				hasChanges,
 				added,
@@ -108,4 +128,96 @@ export class CommandCacheService {
 			};
 		});
 	}
+
+	/**
+	 * Download every command file referenced by the manifest
+	 *
+	 * Files are fetched through the repository, whose content cache keeps
+	 * them for offline use. Failures are collected per command rather than
+	 * aborting the whole prefetch.
+	 *
+	 * @param options - Language selection, parallelism and progress callback
+	 * @returns Counts of fetched and failed command files
+	 */
+	async prefetchCommands(
+		options?: CommandServiceOptions & {
+			readonly concurrency?: number;
+			readonly onProgress?: (progress: PrefetchProgress) => void;
+		},
+	): Promise<PrefetchResult> {
+		const language = resolveLanguage(options, this.languageDetector);
+
+		return withErrorHandling("prefetchCommands", language, async () => {
+			const manifest = await this.repository.getManifest(language);
+			const total = manifest.commands.length;
+			const failed: { commandName: string; error: string }[] = [];
+			let completed = 0;
+
+			await mapConcurrent(
+				manifest.commands,
+				options?.concurrency ?? DEFAULT_PREFETCH_CONCURRENCY,
+				async ({ name }) => {
+					let ok = true;
+					let bytes = 0;
+					try {
+						const content = await this.repository.getCommand(name, language, {
+							forceRefresh: options?.forceRefresh,
+						});
+						bytes = Buffer.byteLength(content, "utf8");
+					} catch (error) {
+						ok = false;
+						failed.push({
+							commandName: name,
+							error: error instanceof Error ? error.message : String(error),
+						});
+					}
+					completed++;
+					options?.onProgress?.({
+						completed,
+						total,
+						commandName: name,
+						ok,
+						bytes,
+					});
+				},
+			);
+
+			return {
+				language,
+				total,
+				fetched: total - failed.length,
+				failed: failed.sort((a, b) =>
+					compareStrings(a.commandName, b.commandName),
+				),
+			};
+		});
+	}
+
+	/**
+	 * Re-index changed command files if content search was used before
+	 *
+	 * Languages never searched by content get no index, so updates do not
+	 * start downloading every command file on their own.
+	 */
+	private async refreshContentIndex(
+		language: string,
+		manifest: Manifest,
+	): Promise<void> {
+		const contentIndex = this.contentIndex;
+		if (!contentIndex || !(await contentIndex.exists(language))) {
+			return;
+		}
+
+		const refresh = await contentIndex.refresh(
+			language,
+			manifest,
+			(commandName) => this.repository.getCommand(commandName, language),
+		);
+		if (refresh.failed.length > 0) {
+			cacheLogger.warn(
+				"content index left out {count} command(s) that could not be fetched",
+				{ count: refresh.failed.length },
+			);
+		}
+	}
 }
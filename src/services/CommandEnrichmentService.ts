@@ -1,3 +1,4 @@
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
 import type {
 	Command,
 	CommandServiceOptions,
@@ -5,8 +6,9 @@ import type {
 	InstallationStatus,
 } from "../types/Command.js";
 import { CommandNotFoundError } from "../types/Command.js";
+import { repoLogger } from "../utils/logger.js";
+import { constructCommandPath } from "../utils/namespace.js";
 import type { CommandQueryService } from "./CommandQueryService.js";
-import type { DirectoryDetector } from "./DirectoryDetector.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 import type { LocalCommandRepository } from "./LocalCommandRepository.js";
 import {
@@ -28,7 +30,7 @@ export class CommandEnrichmentService {
 	constructor(
 		private readonly commandQueryService: CommandQueryService,
 		private readonly localCommandRepository: LocalCommandRepository,
-		private readonly directoryDetector: DirectoryDetector,
+		private readonly directoryDetector: IDirectoryDetector,
 		private readonly languageDetector: LanguageDetector,
 	) {}
 
@@ -86,8 +88,15 @@ export class CommandEnrichmentService {
 						availableInSources.push("project");
 					}
 				}
-			} catch (_error) {
-				// Ignore local repository errors
+			} catch (error) {
+				// Local commands are optional; enrich from the repository alone
+				repoLogger.debug(
+					"local command lookup failed: {commandName} ({error})",
+					{
+						commandName,
+						error: error instanceof Error ? error.message : String(error),
+					},
+				);
 			}
 
 			// Determine which command to use and its source
@@ -103,11 +112,11 @@ export class CommandEnrichmentService {
 					source = "personal";
 					const personalDir =
 						await this.directoryDetector.getPersonalDirectory();
-					_installPath = `${personalDir}/${commandName}.md`;
+					_installPath = constructCommandPath(commandName, personalDir);
 				} else if (availableInSources.includes("project")) {
 					source = "project";
 					const projectDir = await this.directoryDetector.getProjectDirectory();
-					_installPath = `${projectDir}/${commandName}.md`;
+					_installPath = constructCommandPath(commandName, projectDir);
 				} else {
 					source = "personal"; // Fallback
 				}
@@ -124,6 +133,7 @@ export class CommandEnrichmentService {
 				const isInstalled = localCommand !== undefined;
 				let installLocation: "personal" | "project" | undefined;
 				let detectedInstallPath: string | undefined;
+				let projectRoot: string | undefined;
 				let hasLocalChanges = false;
 
 				if (isInstalled && localCommand) {
@@ -132,12 +142,26 @@ export class CommandEnrichmentService {
 						installLocation = "personal";
 						const personalDir =
 							await this.directoryDetector.getPersonalDirectory();
-						detectedInstallPath = `${personalDir}/${commandName}.md`;
+						detectedInstallPath = constructCommandPath(
+							commandName,
+							personalDir,
+						);
 					} else if (availableInSources.includes("project")) {
 						installLocation = "project";
-						const projectDir =
-							await this.directoryDetector.getProjectDirectory();
-						detectedInstallPath = `${projectDir}/${commandName}.md`;
+						// The nearest project holding it, which may enclose the current one
+						const directories =
+							await this.directoryDetector.getClaudeDirectories();
+						for (const dir of directories) {
+							const candidate = constructCommandPath(commandName, dir.path);
+							if (
+								dir.type === "project" &&
+								(await this.directoryDetector.fileService.exists(candidate))
+							) {
+								detectedInstallPath = candidate;
+								projectRoot = dir.projectRoot;
+								break;
+							}
+						}
 					}
 
 					// Compare content to detect local changes if both versions exist
@@ -159,12 +183,19 @@ export class CommandEnrichmentService {
 					isInstalled,
 					installLocation,
 					installPath: detectedInstallPath,
+					...(projectRoot ? { projectRoot } : {}),
 					hasLocalChanges,
 				};
 			}
 
+			// Tags and categories only exist in the repository manifest
 			const enhancedCommand: EnhancedCommandInfo = {
 				...baseCommand,
+				category: baseCommand.category ?? repositoryCommand?.category,
+				tags: baseCommand.tags ?? repositoryCommand?.tags,
+				requires: baseCommand.requires ?? repositoryCommand?.requires,
+				deprecated: baseCommand.deprecated ?? repositoryCommand?.deprecated,
+				replaced_by: baseCommand.replaced_by ?? repositoryCommand?.replaced_by,
 				source,
 				installationStatus,
 				availableInSources,
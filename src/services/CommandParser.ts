@@ -2,6 +2,11 @@ import { basename, dirname } from "node:path";
 import matter from "gray-matter";
 import type INamespaceService from "../interfaces/INamespaceService.js";
 import type { Command } from "../types/Command.js";
+import { isAllowedTool } from "../utils/allowedTools.js";
+import { normalizeArgumentHint } from "../utils/argumentHint.js";
+import { normalizeRequires } from "../utils/dependencies.js";
+import { parseExamples } from "../utils/examples.js";
+import { InvalidNameError, validateFileName } from "../utils/naming.js";
 
 /**
  * Error thrown when command parsing fails
@@ -27,24 +32,6 @@ export class CommandParser {
 	constructor(namespaceService: INamespaceService) {
 		this.namespaceService = namespaceService;
 	}
-	/**
-	 * Whitelist of allowed core Claude Code tools
-	 */
-	private readonly allowedTools = new Set([
-		"Edit",
-		"Glob",
-		"Grep",
-		"LS",
-		"MultiEdit",
-		"NotebookEdit",
-		"NotebookRead",
-		"Read",
-		"Task",
-		"TodoWrite",
-		"WebFetch",
-		"WebSearch",
-		"Write",
-	]);
 
 	/**
 	 * Parse a command file with optional YAML frontmatter and namespace support
@@ -113,8 +100,26 @@ export class CommandParser {
 				}
 
 				// Add optional argument-hint if present
-				if (parsed.data["argument-hint"]) {
-					(command as any)["argument-hint"] = parsed.data["argument-hint"];
+				const argumentHint = normalizeArgumentHint(
+					parsed.data["argument-hint"],
+				);
+				if (argumentHint) {
+					(command as any)["argument-hint"] = argumentHint;
+				}
+
+				// Add optional requires if present
+				const requires = normalizeRequires(parsed.data.requires);
+				if (requires.length > 0) {
+					(command as any).requires = requires;
+				}
+
+				// Add usable examples; `manifest lint` reports the others
+				const { examples } = parseExamples(
+					parsed.data.examples,
+					fullCommandName,
+				);
+				if (examples.length > 0) {
+					(command as any).examples = examples;
 				}
 
 				return command;
@@ -266,16 +271,15 @@ export class CommandParser {
 	private validateSecurity(data: any, commandName: string): void {
 		// Check for dangerous file paths
 		if (data.file) {
-			if (data.file.includes("..")) {
-				throw new CommandParseError(
-					"Security violation: file path contains path traversal",
-					commandName,
-				);
-			}
-
-			if (data.file.startsWith("/") || data.file.match(/^[A-Za-z]:/)) {
+			try {
+				validateFileName(String(data.file));
+			} catch (error) {
+				const reason =
+					error instanceof InvalidNameError && error.reason === "absolute"
+						? "file path must be relative"
+						: "file path contains path traversal";
 				throw new CommandParseError(
-					"Security violation: file path must be relative",
+					`Security violation: ${reason}`,
 					commandName,
 				);
 			}
@@ -311,7 +315,7 @@ export class CommandParser {
 	 */
 	private validateAllowedTools(tools: string[], commandName: string): void {
 		for (const tool of tools) {
-			if (!this.isAllowedTool(tool)) {
+			if (!isAllowedTool(tool)) {
 				throw new CommandParseError(
 					`Security violation: tool '${tool}' is not allowed`,
 					commandName,
@@ -320,33 +324,6 @@ export class CommandParser {
 		}
 	}
 
-	/**
-	 * Check if a tool is in the allowed whitelist
-	 * @param tool Tool to check
-	 * @returns True if allowed
-	 */
-	private isAllowedTool(tool: string): boolean {
-		// Direct match against core tools
-		if (this.allowedTools.has(tool)) {
-			return true;
-		}
-
-		// Check against MCP tool pattern: mcp__<server-name>__<prompt-name>
-		const mcpPattern = /^mcp__[a-zA-Z0-9_]+__[a-zA-Z0-9_]+$/;
-		if (mcpPattern.test(tool)) {
-			return true;
-		}
-
-		// Allow any Bash command pattern: Bash(command:*) or Bash(cmd1:*, cmd2:*)
-		// This gives users flexibility to use any bash tools in their slash commands
-		const bashPattern = /^Bash\([a-zA-Z0-9_\-,:*\s]+\)$/;
-		if (bashPattern.test(tool)) {
-			return true;
-		}
-
-		return false;
-	}
-
 	/**
 	 * Extract command information from file paths, supporting both legacy and namespace modes
 	 */
@@ -1,7 +1,21 @@
+import type ICacheManager from "../interfaces/ICacheManager.js";
 import type IRepository from "../interfaces/IRepository.js";
-import type { Command, CommandServiceOptions } from "../types/Command.js";
-import { CommandNotFoundError } from "../types/Command.js";
-import type { CacheManager } from "./CacheManager.js";
+import type {
+	Command,
+	CommandFilterOptions,
+	CommandSearchOptions,
+	CommandServiceOptions,
+	Manifest,
+} from "../types/Command.js";
+import {
+	AmbiguousCommandError,
+	CommandNotFoundError,
+} from "../types/Command.js";
+import { repoLogger } from "../utils/logger.js";
+import { ManifestIndex } from "../utils/manifestIndex.js";
+import { compareStrings, sortCommands } from "../utils/ordering.js";
+import { commandTags } from "../utils/tags.js";
+import type { ContentSearchIndex } from "./ContentSearchIndex.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 import {
 	resolveLanguage,
@@ -15,52 +29,106 @@ import {
  *
  * Responsibilities:
  * - List all available commands from repository
- * - Search commands by name/description
+ * - Search commands by name/description, and optionally by content
  * - Get specific command information
  * - Coordinate repository access with caching
  */
 export class CommandQueryService {
+	/** Index of the manifest last loaded per language, by manifest identity */
+	private readonly indexes = new Map<
+		string,
+		{ readonly identity: string; readonly index: ManifestIndex }
+	>();
+
+	/**
+	 * @param contentIndex - Index used by content searches; without one,
+	 *   searches only look at names, descriptions and tags
+	 * @param repositoryPrefixes - Reads the prefixes repositories are
+	 *   configured with, so bare names find prefixed commands
+	 */
 	constructor(
 		private readonly repository: IRepository,
-		private readonly cacheManager: CacheManager,
+		private readonly cacheManager: ICacheManager,
 		private readonly languageDetector: LanguageDetector,
+		private readonly contentIndex?: ContentSearchIndex,
+		private readonly repositoryPrefixes?: () => Promise<readonly string[]>,
 	) {}
 
 	/**
 	 * List all available commands from the repository
+	 *
+	 * Commands are returned in canonical order (see utils/ordering.ts),
+	 * optionally restricted to a tag or category.
 	 */
 	async listCommands(
-		options?: CommandServiceOptions,
+		options?: CommandFilterOptions,
 	): Promise<readonly Command[]> {
 		const language = resolveLanguage(options, this.languageDetector);
 
 		return withErrorHandling("listCommands", language, async () => {
-			// Check cache first (unless force refresh)
-			if (!options?.forceRefresh) {
-				const cachedManifest = await this.cacheManager.get(language);
-				if (cachedManifest && !(await this.cacheManager.isExpired(language))) {
-					return cachedManifest.commands;
-				}
-			}
+			const index = await this.loadIndex(language, options);
+			return options?.tag ? index.withTag(options.tag) : index.commands;
+		});
+	}
 
-			// Fetch fresh manifest from repository
-			const manifest = await this.repository.getManifest(language, {
-				forceRefresh: options?.forceRefresh,
-			});
+	/**
+	 * Load the manifest commands in canonical order, indexed for lookups
+	 *
+	 * The index is kept per language and rebuilt only when the manifest's
+	 * identity (see manifestIdentity()) changes, so repeated calls skip
+	 * sorting and indexing.
+	 */
+	private async loadIndex(
+		language: string,
+		options?: CommandServiceOptions,
+	): Promise<ManifestIndex> {
+		const manifest = await this.loadManifest(language, options);
+		const identity = manifestIdentity(manifest);
+		const cached = this.indexes.get(language);
+		if (cached?.identity === identity) {
+			return cached.index;
+		}
+		const index = new ManifestIndex(sortCommands(manifest.commands, language));
+		this.indexes.set(language, { identity, index });
+		return index;
+	}
 
-			// Cache the fresh manifest
-			await this.cacheManager.set(language, manifest);
+	/**
+	 * Load the manifest, preferring a fresh cache
+	 */
+	private async loadManifest(
+		language: string,
+		options?: CommandServiceOptions,
+	): Promise<Manifest> {
+		// Check cache first (unless force refresh)
+		if (!options?.forceRefresh) {
+			const cachedManifest = await this.cacheManager.get(language);
+			if (cachedManifest && !(await this.cacheManager.isExpired(language))) {
+				return cachedManifest;
+			}
+		}
 
-			return manifest.commands;
+		// Fetch fresh manifest from repository
+		const manifest = await this.repository.getManifest(language, {
+			forceRefresh: options?.forceRefresh,
 		});
+
+		// Cache the fresh manifest
+		await this.cacheManager.set(language, manifest);
+
+		return manifest;
 	}
 
 	/**
-	 * Search for commands by name or description
+	 * Search for commands by name, description or tag
+	 *
+	 * With options.content, commands whose file contains every word of the
+	 * query match too. The content index is brought up to date with the
+	 * manifest first, which downloads only command files not indexed yet.
 	 */
 	async searchCommands(
 		query: string,
-		options?: CommandServiceOptions,
+		options?: CommandSearchOptions,
 	): Promise<readonly Command[]> {
 		validateSearchQuery(query);
 		const language = resolveLanguage(options, this.languageDetector);
@@ -68,19 +136,54 @@ export class CommandQueryService {
 		return withErrorHandling("searchCommands", language, async () => {
 			// Get all commands first
 			const allCommands = await this.listCommands(options);
+			const contentMatches = options?.content
+				? await this.searchContent(query, language, options)
+				: undefined;
 
-			// Filter by query (case-insensitive search in name and description)
+			// Filter by query (case-insensitive search in name, description and tags)
 			const queryLower = query.toLowerCase().trim();
 			const matchingCommands = allCommands.filter(
 				(command) =>
 					command.name.toLowerCase().includes(queryLower) ||
-					command.description.toLowerCase().includes(queryLower),
+					command.description.toLowerCase().includes(queryLower) ||
+					commandTags(command).some((tag) => tag.includes(queryLower)) ||
+					contentMatches?.has(command.name),
 			);
 
 			return matchingCommands;
 		});
 	}
 
+	/**
+	 * Find the commands whose file contains the words of a query
+	 */
+	private async searchContent(
+		query: string,
+		language: string,
+		options?: CommandServiceOptions,
+	): Promise<ReadonlySet<string>> {
+		if (!this.contentIndex) {
+			return new Set();
+		}
+
+		const refresh = await this.contentIndex.refresh(
+			language,
+			await this.loadManifest(language, options),
+			(commandName) =>
+				this.repository.getCommand(commandName, language, {
+					forceRefresh: options?.forceRefresh,
+				}),
+		);
+		if (refresh.failed.length > 0) {
+			repoLogger.warn(
+				"content search skipped {count} command(s) that could not be fetched",
+				{ count: refresh.failed.length },
+			);
+		}
+
+		return (await this.contentIndex.search(language, query)) ?? new Set();
+	}
+
 	/**
 	 * Get detailed information about a specific command
 	 */
@@ -92,11 +195,10 @@ export class CommandQueryService {
 		const language = resolveLanguage(options, this.languageDetector);
 
 		return withErrorHandling("getCommandInfo", language, async () => {
-			// Get all commands first
-			const allCommands = await this.listCommands(options);
-
-			// Find the specific command
-			const command = allCommands.find((cmd) => cmd.name === commandName);
+			const index = await this.loadIndex(language, options);
+			const command = index.get(
+				await this.resolveName(index, commandName, language),
+			);
 			if (!command) {
 				throw new CommandNotFoundError(commandName, language);
 			}
@@ -104,4 +206,64 @@ export class CommandQueryService {
 			return command;
 		});
 	}
+
+	/**
+	 * Get the name a command is listed under
+	 *
+	 * Names of listed commands are returned as is. Other names are looked up
+	 * in the prefixed repositories, so "deploy" finds "team:deploy" when the
+	 * repository prefixed "team" is the only one providing it.
+	 *
+	 * @returns The listed name, or commandName when nothing matches
+	 * @throws AmbiguousCommandError if several prefixed repositories have it
+	 */
+	async resolveCommandName(
+		commandName: string,
+		options?: CommandServiceOptions,
+	): Promise<string> {
+		validateCommandName(commandName);
+		const language = resolveLanguage(options, this.languageDetector);
+
+		return withErrorHandling("resolveCommandName", language, async () =>
+			this.resolveName(
+				await this.loadIndex(language, options),
+				commandName,
+				language,
+			),
+		);
+	}
+
+	private async resolveName(
+		index: ManifestIndex,
+		commandName: string,
+		language: string,
+	): Promise<string> {
+		if (index.get(commandName) || !this.repositoryPrefixes) {
+			return commandName;
+		}
+		const candidates = (await this.repositoryPrefixes())
+			.map((prefix) => `${prefix}:${commandName}`)
+			.filter((name) => index.get(name) !== undefined)
+			.sort(compareStrings);
+		if (candidates.length > 1) {
+			throw new AmbiguousCommandError(commandName, language, candidates);
+		}
+		return candidates[0] ?? commandName;
+	}
+}
+
+/**
+ * Identify a manifest by version, update time and size
+ *
+ * Manifests are read again from the cache on each call, so object identity
+ * does not survive between calls; a republished manifest gets a new update
+ * time, and built manifests get a new one each time they are built.
+ */
+function manifestIdentity(manifest: Manifest): string {
+	return [
+		manifest.version,
+		manifest.schemaVersion ?? "",
+		manifest.updated,
+		manifest.commands.length,
+	].join("\0");
 }
@@ -2,6 +2,7 @@ import type {
 	Config,
 	IConfigManager,
 	IConfigService,
+	LanguageResolution,
 } from "../interfaces/IConfigService.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 
@@ -66,7 +67,7 @@ export class ConfigManager implements IConfigManager {
 	 * Get the effective language to use based on all sources
 	 *
 	 * Follows the complete precedence order:
-	 * 1. CLI flag (not applicable in this context)
+	 * 1. Global --language flag (set on the language detector)
 	 * 2. CLAUDE_CMD_LANG environment variable
 	 * 3. Project configuration
 	 * 4. User configuration
@@ -77,6 +78,17 @@ export class ConfigManager implements IConfigManager {
 	 * @throws Never throws - always returns a valid language code
 	 */
 	async getEffectiveLanguage(): Promise<string> {
+		return (await this.getLanguageResolution()).language;
+	}
+
+	/**
+	 * Explain which source supplies the effective language
+	 *
+	 * Uses the same sources and precedence as getEffectiveLanguage().
+	 *
+	 * @returns The effective language, the winning source and every source's value
+	 */
+	async getLanguageResolution(): Promise<LanguageResolution> {
 		const [projectConfig, userConfig] = await Promise.all([
 			this.projectConfigService.getConfig(),
 			this.userConfigService.getConfig(),
@@ -84,7 +96,7 @@ export class ConfigManager implements IConfigManager {
 
 		// Build detection context with all sources
 		const context = {
-			cliFlag: "", // Not used in this context (CLI flag would override this method)
+			cliFlag: "", // The detector applies the global --language flag itself
 			envVar: process.env.CLAUDE_CMD_LANG || "",
 			projectConfig: projectConfig?.preferredLanguage || "",
 			userConfig: userConfig?.preferredLanguage || "",
@@ -92,7 +104,7 @@ export class ConfigManager implements IConfigManager {
 				process.env.LC_ALL || process.env.LC_MESSAGES || process.env.LANG || "",
 		};
 
-		return this.languageDetector.detect(context);
+		return this.languageDetector.resolve(context);
 	}
 
 	/**
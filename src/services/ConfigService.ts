@@ -1,11 +1,13 @@
 import path from "node:path";
-import type {
-	Config,
-	IConfigManager,
-	IConfigService,
-	LanguageStatus,
+import {
+	type Config,
+	type IConfigManager,
+	type IConfigService,
+	InvalidConfigError,
+	type LanguageStatus,
 } from "../interfaces/IConfigService.js";
 import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
 import type IRepository from "../interfaces/IRepository.js";
 /**
  * Available languages supported by claude-cmd
@@ -19,8 +21,41 @@ export interface LanguageInfo {
 	available: boolean;
 }
 
+import { isAliasMap } from "../utils/aliases.js";
+import {
+	CONFIG_KEYS,
+	GIT_REF_PATTERN,
+	isAdditionalRepositoryURL,
+	isScanIgnorePattern,
+	isValidURL,
+	SCP_LIKE_GIT_URL,
+} from "../utils/configKeys.js";
+import { isHookMap, isHookTrustMap } from "../utils/hooks.js";
+import { configLogger } from "../utils/logger.js";
+import { parsePublicKey } from "../utils/minisign.js";
+import { isValidCommandName } from "../utils/naming.js";
+import {
+	isRepositoryKeyMap,
+	isRepositoryPrefixMap,
+} from "../utils/repositorySource.js";
+import { COLOR_MODES } from "../utils/style.js";
+import { isToolDecision } from "../utils/toolConsent.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 
+/**
+ * Check whether a value is a finite number greater than zero
+ */
+function isPositiveNumber(value: unknown): boolean {
+	return typeof value === "number" && Number.isFinite(value) && value > 0;
+}
+
+/**
+ * Check whether a value is an integer greater than zero
+ */
+function isPositiveInteger(value: unknown): boolean {
+	return Number.isInteger(value) && (value as number) > 0;
+}
+
 /**
  * Service for managing configuration files
  *
@@ -50,6 +85,11 @@ import type { LanguageDetector } from "./LanguageDetector.js";
 export class ConfigService implements IConfigService {
 	private readonly configDir: string;
 
+	/**
+	 * Whether a problem with the file was already reported this run
+	 */
+	private problemReported = false;
+
 	/**
 	 * Known languages with their display names
 	 */
@@ -70,7 +110,7 @@ export class ConfigService implements IConfigService {
 	 *
 	 * @param configPath - Path to the configuration file
 	 * @param fileService - File service implementation for configuration persistence
-	 * @param repository - Repository service for checking language availability
+	 * @param repository - Repository service for discovering published languages
 	 * @param languageDetector - Language detector for validation
 	 * @param configManager - Optional config manager for getting effective language
 	 */
@@ -97,11 +137,25 @@ export class ConfigService implements IConfigService {
 
 			// Validate the configuration before returning
 			if (!this.validateConfig(config)) {
+				this.reportProblem("{path} is invalid and ignored");
 				return null;
 			}
 
+			// Unknown keys are kept for newer releases, but are usually typos
+			const unknown = Object.keys(config).filter(
+				(key) => !Object.hasOwn(CONFIG_KEYS, key),
+			);
+			if (unknown.length > 0) {
+				this.reportProblem("{path}: unknown key(s) {keys} have no effect", {
+					keys: unknown.join(", "),
+				});
+			}
+
 			return config;
-		} catch {
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				this.reportProblem("{path} is unreadable and ignored");
+			}
 			// Return null for any errors (missing file, invalid JSON, etc.)
 			// This provides graceful degradation to defaults
 			return null;
@@ -116,7 +170,7 @@ export class ConfigService implements IConfigService {
 	 */
 	async setConfig(config: Config): Promise<void> {
 		if (!this.validateConfig(config)) {
-			throw new Error("Invalid configuration");
+			throw new InvalidConfigError("Invalid configuration");
 		}
 
 		try {
@@ -138,39 +192,42 @@ export class ConfigService implements IConfigService {
 	/**
 	 * Get list of all supported languages with their availability status
 	 *
-	 * Checks the remote repository to determine which languages have available
-	 * command manifests. English is always considered available.
+	 * Availability comes from the languages the repository publishes, so new
+	 * languages appear without a CLI release. Well-known languages are always
+	 * listed as suggestions; those the repository does not list are checked by
+	 * fetching their manifest, for repositories without a languages index.
+	 * English is always considered available.
 	 *
 	 * @returns Array of language information including availability status
-	 * @throws Never throws - network errors result in languages marked as unavailable
+	 * @throws Never throws - discovery errors result in languages marked as unavailable
 	 */
 	async getAvailableLanguages(): Promise<LanguageInfo[]> {
-		// Process each known language in parallel for better performance
-		const availabilityChecks = Array.from(this.knownLanguages.entries()).map(
-			async ([code, name]) => {
-				let available = false;
+		const published = await this.repository
+			.getAvailableLanguages()
+			.catch(() => []);
+		const publishedCodes = new Set(published.map((lang) => lang.code));
 
+		// Process each known language in parallel for better performance
+		const known = await Promise.all(
+			Array.from(this.knownLanguages.entries()).map(async ([code, name]) => {
 				// English is always considered available as the fallback language
-				if (code === "en") {
-					available = true;
-				} else {
+				let available = code === "en" || publishedCodes.has(code);
+				if (!available) {
 					// Test availability by attempting to fetch the language manifest
-					try {
-						await this.repository.getManifest(code);
-						available = true;
-					} catch {
-						// Language is not available in repository
-						available = false;
-					}
+					available = await this.repository.getManifest(code).then(
+						() => true,
+						() => false,
+					);
 				}
 
 				return { code, name, available };
-			},
+			}),
 		);
+		const discovered = published
+			.filter((lang) => !this.knownLanguages.has(lang.code))
+			.map((lang) => ({ code: lang.code, name: lang.name, available: true }));
 
-		// Wait for all availability checks to complete
-		const results = await Promise.all(availabilityChecks);
-		return results;
+		return [...known, ...discovered];
 	}
 
 	/**
@@ -182,6 +239,26 @@ export class ConfigService implements IConfigService {
 		return this.configPath;
 	}
 
+	/**
+	 * Warn once per run about a problem with the config file
+	 *
+	 * @param message - LogTape message template; {path} is the file
+	 * @param properties - Further message properties
+	 */
+	private reportProblem(
+		message: string,
+		properties: Record<string, unknown> = {},
+	): void {
+		if (this.problemReported) {
+			return;
+		}
+		this.problemReported = true;
+		configLogger.warn(
+			`${message}; run 'claude-cmd config validate' for details`,
+			{ path: this.configPath, ...properties },
+		);
+	}
+
 	/**
 	 * Validate configuration structure and values
 	 *
@@ -216,14 +293,208 @@ export class ConfigService implements IConfigService {
 				return false;
 			}
 
-			// Basic URL validation
+			// Git sources also accept scp-like SSH URLs (git@host:owner/repo.git)
+			if (
+				!isValidURL(config.repositoryURL) &&
+				!(
+					config.repositoryType === "git" &&
+					SCP_LIKE_GIT_URL.test(config.repositoryURL)
+				)
+			) {
+				return false;
+			}
+		}
+
+		// Validate additionalRepositories if present
+		if (
+			config.additionalRepositories !== undefined &&
+			!(
+				Array.isArray(config.additionalRepositories) &&
+				config.additionalRepositories.every(
+					(url: unknown) =>
+						typeof url === "string" && isAdditionalRepositoryURL(url),
+				)
+			)
+		) {
+			return false;
+		}
+
+		// Validate disabledRepositories if present
+		if (
+			config.disabledRepositories !== undefined &&
+			!(
+				Array.isArray(config.disabledRepositories) &&
+				config.disabledRepositories.every(
+					(url: unknown) =>
+						typeof url === "string" && isAdditionalRepositoryURL(url),
+				)
+			)
+		) {
+			return false;
+		}
+
+		// Validate scanIgnore if present
+		if (
+			config.scanIgnore !== undefined &&
+			!(
+				Array.isArray(config.scanIgnore) &&
+				config.scanIgnore.every(
+					(pattern: unknown) =>
+						typeof pattern === "string" && isScanIgnorePattern(pattern),
+				)
+			)
+		) {
+			return false;
+		}
+
+		// Validate repositoryType if present
+		if (
+			config.repositoryType !== undefined &&
+			config.repositoryType !== "http" &&
+			config.repositoryType !== "git"
+		) {
+			return false;
+		}
+
+		// Validate repositoryRef if present
+		if (config.repositoryRef !== undefined) {
+			if (
+				typeof config.repositoryRef !== "string" ||
+				!GIT_REF_PATTERN.test(config.repositoryRef)
+			) {
+				return false;
+			}
+		}
+
+		// Validate manifestPublicKey if present
+		if (config.manifestPublicKey !== undefined) {
+			if (typeof config.manifestPublicKey !== "string") {
+				return false;
+			}
 			try {
-				new URL(config.repositoryURL);
+				parsePublicKey(config.manifestPublicKey);
 			} catch {
 				return false;
 			}
 		}
 
+		// Validate defaultTarget if present
+		if (
+			config.defaultTarget !== undefined &&
+			config.defaultTarget !== "personal" &&
+			config.defaultTarget !== "project"
+		) {
+			return false;
+		}
+
+		// Validate outputColor if present
+		if (
+			config.outputColor !== undefined &&
+			!COLOR_MODES.includes(config.outputColor)
+		) {
+			return false;
+		}
+
+		// Validate commands if present
+		if (
+			config.commands !== undefined &&
+			!(
+				Array.isArray(config.commands) &&
+				config.commands.every(
+					(name: unknown) =>
+						typeof name === "string" && isValidCommandName(name),
+				)
+			)
+		) {
+			return false;
+		}
+
+		// Validate remembered tool decisions if present
+		if (
+			config.toolConsent !== undefined &&
+			!(
+				typeof config.toolConsent === "object" &&
+				config.toolConsent !== null &&
+				!Array.isArray(config.toolConsent) &&
+				Object.entries(config.toolConsent).every(
+					([name, decision]) =>
+						isValidCommandName(name) && isToolDecision(decision),
+				)
+			)
+		) {
+			return false;
+		}
+
+		// Validate repository prefixes if present
+		if (
+			config.repositoryPrefixes !== undefined &&
+			!isRepositoryPrefixMap(config.repositoryPrefixes)
+		) {
+			return false;
+		}
+		if (
+			config.repositoryPublicKeys !== undefined &&
+			!isRepositoryKeyMap(config.repositoryPublicKeys)
+		) {
+			return false;
+		}
+
+		// Validate command aliases if present
+		if (config.aliases !== undefined && !isAliasMap(config.aliases)) {
+			return false;
+		}
+
+		// Validate hooks if present
+		if (config.hooks !== undefined && !isHookMap(config.hooks)) {
+			return false;
+		}
+		if (
+			config.projectHookTrust !== undefined &&
+			!isHookTrustMap(config.projectHookTrust)
+		) {
+			return false;
+		}
+
+		// Validate directory settings if present
+		for (const key of ["cacheDir", "claudeDir"] as const) {
+			const value = config[key];
+			if (value !== undefined && (typeof value !== "string" || !value.trim())) {
+				return false;
+			}
+		}
+
+		// Validate boolean switches if present
+		for (const key of [
+			"cleanupEmptyDirectories",
+			"crashReports",
+			"recordProvenance",
+			"strictLanguage",
+		]) {
+			if (config[key] !== undefined && typeof config[key] !== "boolean") {
+				return false;
+			}
+		}
+
+		// Validate HTTP limits and preview limits if present
+		for (const key of ["maxRequestsPerSecond", "maxBytesPerSecond"]) {
+			if (config[key] !== undefined && !isPositiveNumber(config[key])) {
+				return false;
+			}
+		}
+		for (const key of [
+			"maxParallelDownloads",
+			"maxManifestBytes",
+			"maxCommandBytes",
+			"hookTimeoutSeconds",
+			"scanMaxDepth",
+			"previewLines",
+			"previewCharacters",
+		]) {
+			if (config[key] !== undefined && !isPositiveInteger(config[key])) {
+				return false;
+			}
+		}
+
 		// Configuration is valid (unknown fields are allowed for forward compatibility)
 		return true;
 	}
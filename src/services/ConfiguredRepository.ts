@@ -0,0 +1,60 @@
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+
+/**
+ * Repository that delegates to an implementation selected from configuration
+ *
+ * The concrete repository (HTTP or git) depends on the effective configuration,
+ * but the configuration services themselves need a repository to check language
+ * availability. Resolving lazily on first use breaks that construction cycle:
+ * services receive this wrapper up front, and the real repository is chosen
+ * the first time any of them touches it.
+ *
+ * @example
+ * ```typescript
+ * const repository = new ConfiguredRepository(async () => {
+ *   const config = await configManager.getEffectiveConfig();
+ *   return config.repositoryType === "git" ? gitRepository : httpRepository;
+ * });
+ * ```
+ */
+export class ConfiguredRepository implements IRepository {
+	private resolved: Promise<IRepository> | null = null;
+
+	/**
+	 * @param resolver - Returns the repository to delegate to; called once
+	 */
+	constructor(private readonly resolver: () => Promise<IRepository>) {}
+
+	async getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		return (await this.resolve()).getManifest(language, options);
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		return (await this.resolve()).getCommand(commandName, language, options);
+	}
+
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return (await this.resolve()).getAvailableLanguages();
+	}
+
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		return (await this.resolve()).getAbout(language);
+	}
+
+	private resolve(): Promise<IRepository> {
+		if (!this.resolved) {
+			this.resolved = this.resolver();
+		}
+		return this.resolved;
+	}
+}
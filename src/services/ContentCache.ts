@@ -0,0 +1,270 @@
+import { createHash } from "node:crypto";
+import { join } from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { repoLogger } from "../utils/logger.js";
+import { isValidFileName, isValidLanguageCode } from "../utils/naming.js";
+import { compareStrings } from "../utils/ordering.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * A cached command file with its integrity metadata
+ */
+export interface ContentCacheEntry {
+	/** Raw markdown content of the command file */
+	readonly content: string;
+	/** SHA-256 of the content (hex) */
+	readonly sha256: string;
+	/** "updated" field of the manifest the content was fetched under */
+	readonly manifestUpdated?: string;
+	/** When the content was cached (milliseconds since Unix epoch) */
+	readonly cachedAt: number;
+}
+
+/**
+ * Summary of the cached command files of one language
+ */
+export interface ContentCacheStats {
+	/** Number of cached command files */
+	readonly fileCount: number;
+	/** Total size of the cached command files in bytes */
+	readonly sizeBytes: number;
+	/** When the most recently cached file was stored, if any */
+	readonly newestCachedAt?: number;
+}
+
+/**
+ * Metadata stored next to a cached command file
+ */
+type ContentCacheMeta = Omit<ContentCacheEntry, "content">;
+
+/**
+ * Local cache of command file bodies, keyed by file path and checksum
+ *
+ * Layout under the cache root:
+ *
+ *   pages/{lang}/files/{file}       raw command markdown
+ *   pages/{lang}/meta/{file}.json   checksum and provenance of that file
+ *
+ * Entries whose content no longer matches the recorded checksum (partial
+ * writes, manual edits) are treated as misses.
+ */
+export class ContentCache {
+	/**
+	 * @param fileService - File service for cache I/O
+	 * @param cacheDir - Cache root directory
+	 * @param clock - Clock used to stamp entries (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly cacheDir: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Compute the checksum used to key cached content
+	 *
+	 * @param content - Command file content
+	 * @returns Lowercase hex SHA-256 digest
+	 */
+	static checksum(content: string): string {
+		return createHash("sha256").update(content, "utf8").digest("hex");
+	}
+
+	/**
+	 * Look up a cached command file
+	 *
+	 * @param language - Language code (e.g., "en")
+	 * @param file - Command file path relative to the language directory
+	 * @param expectedSha256 - Checksum the content must have, if known
+	 * @returns The cached entry, or null on a miss or checksum mismatch
+	 */
+	async get(
+		language: string,
+		file: string,
+		expectedSha256?: string,
+	): Promise<ContentCacheEntry | null> {
+		if (!this.isCacheable(language, file)) {
+			return null;
+		}
+
+		try {
+			const meta = this.parseMeta(
+				await this.fileService.readFile(this.getMetaPath(language, file)),
+			);
+			if (!meta) {
+				return null;
+			}
+			if (expectedSha256 && meta.sha256 !== expectedSha256.toLowerCase()) {
+				return null;
+			}
+
+			const content = await this.fileService.readFile(
+				this.getFilePath(language, file),
+			);
+			if (ContentCache.checksum(content) !== meta.sha256) {
+				repoLogger.warn("cached content checksum mismatch: {file}", { file });
+				return null;
+			}
+
+			return { ...meta, content };
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				repoLogger.debug("content cache read error: {file} ({error})", {
+					file,
+					error: error instanceof Error ? error.message : String(error),
+				});
+			}
+			return null;
+		}
+	}
+
+	/**
+	 * Store a command file
+	 *
+	 * Failures are logged rather than thrown; the cache is an optimization.
+	 *
+	 * @param language - Language code (e.g., "en")
+	 * @param file - Command file path relative to the language directory
+	 * @param content - Raw markdown content
+	 * @param manifestUpdated - "updated" field of the current manifest
+	 */
+	async set(
+		language: string,
+		file: string,
+		content: string,
+		manifestUpdated?: string,
+	): Promise<void> {
+		if (!this.isCacheable(language, file)) {
+			return;
+		}
+
+		const meta: ContentCacheMeta = {
+			sha256: ContentCache.checksum(content),
+			manifestUpdated,
+			cachedAt: this.clock.now(),
+		};
+
+		try {
+			// Content first: a crash in between leaves a checksum mismatch, not
+			// metadata vouching for the wrong content
+			await writeFileAtomic(
+				this.fileService,
+				this.getFilePath(language, file),
+				content,
+			);
+			await writeFileAtomic(
+				this.fileService,
+				this.getMetaPath(language, file),
+				JSON.stringify(meta, null, 2),
+			);
+		} catch (error) {
+			repoLogger.error("content cache write failed: {file} ({error})", {
+				file,
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	/**
+	 * List languages with cached command files, sorted by language code
+	 *
+	 * @returns Language codes found under pages/
+	 */
+	async listLanguages(): Promise<string[]> {
+		const pagesDir = join(this.cacheDir, "pages");
+		if (!(await this.fileService.exists(pagesDir))) {
+			return [];
+		}
+
+		const languages = new Set<string>();
+		for (const file of await this.fileService.listFilesRecursive(pagesDir)) {
+			const [language, kind] = file.split(/[/\\]/);
+			if (language && kind === "files" && isValidLanguageCode(language)) {
+				languages.add(language);
+			}
+		}
+		return [...languages].sort(compareStrings);
+	}
+
+	/**
+	 * Summarize the cached command files of a language
+	 *
+	 * @param language - Language code (e.g., "en")
+	 * @returns File count, total size and newest cache time
+	 */
+	async getStats(language: string): Promise<ContentCacheStats> {
+		const filesDir = this.getFilesDir(language);
+		if (
+			!isValidLanguageCode(language) ||
+			!(await this.fileService.exists(filesDir))
+		) {
+			return { fileCount: 0, sizeBytes: 0 };
+		}
+
+		let fileCount = 0;
+		let sizeBytes = 0;
+		let newestCachedAt: number | undefined;
+		for (const file of await this.fileService.listFilesRecursive(filesDir)) {
+			try {
+				const content = await this.fileService.readFile(join(filesDir, file));
+				fileCount++;
+				sizeBytes += Buffer.byteLength(content, "utf8");
+
+				const meta = this.parseMeta(
+					await this.fileService.readFile(this.getMetaPath(language, file)),
+				);
+				if (meta && (newestCachedAt ?? 0) < meta.cachedAt) {
+					newestCachedAt = meta.cachedAt;
+				}
+			} catch (error) {
+				if (!(error instanceof FileNotFoundError)) {
+					throw error;
+				}
+			}
+		}
+
+		return { fileCount, sizeBytes, newestCachedAt };
+	}
+
+	/**
+	 * Get the directory holding cached command files of a language
+	 *
+	 * @param language - Language code (e.g., "en")
+	 */
+	getFilesDir(language: string): string {
+		return join(this.cacheDir, "pages", language, "files");
+	}
+
+	private getFilePath(language: string, file: string): string {
+		return join(this.getFilesDir(language), file);
+	}
+
+	private getMetaPath(language: string, file: string): string {
+		return join(this.cacheDir, "pages", language, "meta", `${file}.json`);
+	}
+
+	/**
+	 * Manifest-supplied paths must stay inside the cache directory
+	 */
+	private isCacheable(language: string, file: string): boolean {
+		return isValidLanguageCode(language) && isValidFileName(file);
+	}
+
+	private parseMeta(content: string): ContentCacheMeta | null {
+		try {
+			const parsed = JSON.parse(content);
+			if (
+				typeof parsed?.sha256 !== "string" ||
+				typeof parsed.cachedAt !== "number"
+			) {
+				return null;
+			}
+			return parsed as ContentCacheMeta;
+		} catch {
+			return null;
+		}
+	}
+}
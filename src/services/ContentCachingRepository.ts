@@ -0,0 +1,112 @@
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import { CommandContentError, CommandNotFoundError } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { repoLogger } from "../utils/logger.js";
+import { ManifestIndex } from "../utils/manifestIndex.js";
+import { ContentCache } from "./ContentCache.js";
+
+/**
+ * Repository that keeps fetched command files in a local content cache
+ *
+ * Command bodies are reused without contacting the source when they are
+ * known to be current:
+ * - the manifest publishes a sha256 for the file and the cached copy has it, or
+ * - the manifest has no checksum and its "updated" stamp is unchanged since
+ *   the file was cached.
+ *
+ * Otherwise the file is fetched and cached. If fetching fails (offline), the
+ * last cached copy is served as long as its checksum still verifies, so
+ * installs and previews keep working once a file has been fetched.
+ */
+export class ContentCachingRepository implements IRepository {
+	constructor(
+		private readonly repository: IRepository,
+		private readonly contentCache: ContentCache,
+	) {}
+
+	getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		return this.repository.getManifest(language, options);
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		const manifest = await this.repository.getManifest(language, options);
+		const command = ManifestIndex.of(manifest).get(commandName);
+		if (!command) {
+			throw new CommandNotFoundError(commandName, language);
+		}
+
+		if (!options?.forceRefresh) {
+			const cached = await this.contentCache.get(
+				language,
+				command.file,
+				command.sha256,
+			);
+			if (
+				cached &&
+				(command.sha256 || cached.manifestUpdated === manifest.updated)
+			) {
+				repoLogger.debug("content cache hit: {file}", { file: command.file });
+				return cached.content;
+			}
+		}
+
+		let content: string;
+		try {
+			content = await this.repository.getCommand(
+				commandName,
+				language,
+				options,
+			);
+		} catch (error) {
+			if (!(error instanceof CommandContentError)) {
+				throw error;
+			}
+			// Offline: any verified copy beats failing, even if possibly outdated
+			const cached = await this.contentCache.get(language, command.file);
+			if (!cached) {
+				throw error;
+			}
+			repoLogger.warn("using cached content for {commandName}: {error}", {
+				commandName,
+				error: error.message,
+			});
+			return cached.content;
+		}
+
+		if (
+			command.sha256 &&
+			ContentCache.checksum(content) !== command.sha256.toLowerCase()
+		) {
+			repoLogger.warn(
+				"content of {commandName} does not match manifest checksum",
+				{ commandName },
+			);
+		} else {
+			await this.contentCache.set(
+				language,
+				command.file,
+				content,
+				manifest.updated,
+			);
+		}
+
+		return content;
+	}
+
+	getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return this.repository.getAvailableLanguages();
+	}
+
+	getAbout(language: string): Promise<RepositoryAbout | null> {
+		return this.repository.getAbout(language);
+	}
+}
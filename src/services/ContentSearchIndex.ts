@@ -0,0 +1,342 @@
+import { join } from "node:path";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import type { Command, Manifest } from "../types/Command.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { mapConcurrent } from "../utils/concurrency.js";
+import { cacheLogger } from "../utils/logger.js";
+import { isValidLanguageCode } from "../utils/naming.js";
+import { compareStrings } from "../utils/ordering.js";
+
+/**
+ * Number of command files fetched at the same time while indexing
+ */
+export const CONTENT_INDEX_CONCURRENCY = 8;
+
+/**
+ * Version of the persisted index layout; indexes of other versions are rebuilt
+ */
+const INDEX_FORMAT = 1;
+
+/**
+ * Index as stored in search/{lang}.json
+ */
+interface PersistedIndex {
+	readonly format: number;
+	/** Content version of each indexed command, by name (see versionOf()) */
+	readonly documents: Record<string, string>;
+	/** Names of the commands containing each term, sorted */
+	readonly postings: Record<string, string[]>;
+}
+
+/**
+ * Index of one language as held in memory
+ */
+interface LoadedIndex {
+	readonly documents: Map<string, string>;
+	readonly postings: Map<string, Set<string>>;
+}
+
+/**
+ * Outcome of bringing an index up to date with a manifest
+ */
+export interface ContentIndexRefresh {
+	/** Command files fetched and (re)indexed */
+	readonly indexed: number;
+	/** Commands whose index entry was current and kept */
+	readonly reused: number;
+	/** Commands no longer in the manifest, dropped from the index */
+	readonly removed: number;
+	/** Commands whose file could not be fetched; retried on the next refresh */
+	readonly failed: readonly { commandName: string; error: string }[];
+}
+
+/**
+ * Split text into the distinct lowercase words it contains
+ *
+ * Words are runs of letters and digits, so "code-review" yields "code" and
+ * "review".
+ */
+export function tokenize(text: string): string[] {
+	return [
+		...new Set(
+			text
+				.toLowerCase()
+				.split(/[^\p{L}\p{N}]+/u)
+				.filter((word) => word !== ""),
+		),
+	];
+}
+
+/**
+ * Content version a command is indexed under
+ *
+ * The published checksum when there is one, otherwise the command's (or
+ * failing that, the manifest's) "updated" stamp.
+ */
+function versionOf(command: Command, manifest: Manifest): string {
+	return command.sha256?.toLowerCase() ?? command.updated ?? manifest.updated;
+}
+
+/**
+ * Inverted index over command file bodies, persisted in the cache directory
+ *
+ * Layout under the cache root:
+ *
+ *   search/{lang}.json   word -> names of the commands containing it
+ *
+ * refresh() brings an index in line with a manifest by fetching only the
+ * commands that are new or whose content version changed, so `search
+ * --content` pays for a full download once and `cache update` afterwards
+ * touches just what the repository changed. Like the content cache, the
+ * index is an optimization: write failures are logged, never thrown.
+ */
+export class ContentSearchIndex {
+	/** Indexes read or refreshed by this instance, by language */
+	private readonly loaded = new Map<string, LoadedIndex>();
+
+	/**
+	 * @param fileService - File service for index I/O
+	 * @param cacheDir - Cache root directory
+	 * @param concurrency - Command files fetched at once while indexing
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly cacheDir: string,
+		private readonly concurrency = CONTENT_INDEX_CONCURRENCY,
+	) {}
+
+	/**
+	 * Check whether an index was built for a language
+	 */
+	async exists(language: string): Promise<boolean> {
+		return (
+			isValidLanguageCode(language) &&
+			(await this.fileService.exists(this.getIndexPath(language)))
+		);
+	}
+
+	/**
+	 * Bring the index of a language up to date with a manifest
+	 *
+	 * @param language - Language code (e.g., "en")
+	 * @param manifest - Manifest listing the commands to index
+	 * @param fetch - Fetches the body of a command by name
+	 * @returns What was fetched, kept and dropped
+	 */
+	async refresh(
+		language: string,
+		manifest: Manifest,
+		fetch: (commandName: string) => Promise<string>,
+	): Promise<ContentIndexRefresh> {
+		const persisted = await this.exists(language);
+		const index = await this.load(language);
+		const current = new Map(
+			manifest.commands.map(
+				(command) => [command.name, versionOf(command, manifest)] as const,
+			),
+		);
+
+		// Drop commands that left the manifest or changed, then fetch those missing
+		const stale = new Set<string>();
+		for (const [name, version] of index.documents) {
+			if (current.get(name) !== version) {
+				stale.add(name);
+			}
+		}
+		const removed = [...stale].filter((name) => !current.has(name)).length;
+		if (stale.size > 0) {
+			this.remove(index, stale);
+		}
+		const missing = [...current.keys()].filter(
+			(name) => !index.documents.has(name),
+		);
+
+		const failed: { commandName: string; error: string }[] = [];
+		await mapConcurrent(missing, this.concurrency, async (name) => {
+			try {
+				const terms = tokenize(await fetch(name));
+				for (const term of terms) {
+					let names = index.postings.get(term);
+					if (!names) {
+						names = new Set();
+						index.postings.set(term, names);
+					}
+					names.add(name);
+				}
+				index.documents.set(name, current.get(name) as string);
+			} catch (error) {
+				failed.push({
+					commandName: name,
+					error: error instanceof Error ? error.message : String(error),
+				});
+			}
+		});
+
+		const indexed = missing.length - failed.length;
+		if (stale.size > 0 || indexed > 0 || !persisted) {
+			await this.save(language, index);
+		}
+
+		cacheLogger.debug(
+			"content index refreshed: {language} ({indexed} indexed, {removed} removed, {failed} failed)",
+			{ language, indexed, removed, failed: failed.length },
+		);
+
+		return {
+			indexed,
+			reused: current.size - missing.length,
+			removed,
+			failed: failed.sort((a, b) =>
+				compareStrings(a.commandName, b.commandName),
+			),
+		};
+	}
+
+	/**
+	 * Find the commands whose body contains every word of a query
+	 *
+	 * Query words match indexed words they are a prefix of, so "deplo"
+	 * finds "deploy" and "deployment".
+	 *
+	 * @param language - Language code (e.g., "en")
+	 * @param query - Words to look for
+	 * @returns Names of the matching commands, or null without an index
+	 */
+	async search(language: string, query: string): Promise<Set<string> | null> {
+		if (!this.loaded.has(language) && !(await this.exists(language))) {
+			return null;
+		}
+		const index = await this.load(language);
+
+		let matches: Set<string> | undefined;
+		for (const word of tokenize(query)) {
+			const found = new Set<string>();
+			for (const [term, names] of index.postings) {
+				if (term.startsWith(word)) {
+					for (const name of names) {
+						if (!matches || matches.has(name)) {
+							found.add(name);
+						}
+					}
+				}
+			}
+			matches = found;
+			if (matches.size === 0) {
+				break;
+			}
+		}
+		return matches ?? new Set();
+	}
+
+	/**
+	 * Get the file holding the index of a language
+	 *
+	 * @param language - Language code (e.g., "en")
+	 */
+	getIndexPath(language: string): string {
+		return join(this.cacheDir, "search", `${language}.json`);
+	}
+
+	private remove(index: LoadedIndex, names: ReadonlySet<string>): void {
+		for (const name of names) {
+			index.documents.delete(name);
+		}
+		for (const [term, postings] of index.postings) {
+			for (const name of names) {
+				postings.delete(name);
+			}
+			if (postings.size === 0) {
+				index.postings.delete(term);
+			}
+		}
+	}
+
+	/**
+	 * Read the index of a language, starting empty when there is none
+	 */
+	private async load(language: string): Promise<LoadedIndex> {
+		const cached = this.loaded.get(language);
+		if (cached) {
+			return cached;
+		}
+
+		let index: LoadedIndex = { documents: new Map(), postings: new Map() };
+		if (isValidLanguageCode(language)) {
+			try {
+				index =
+					this.parse(
+						await this.fileService.readFile(this.getIndexPath(language)),
+					) ?? index;
+			} catch (error) {
+				if (!(error instanceof FileNotFoundError)) {
+					cacheLogger.debug("content index read error: {language} ({error})", {
+						language,
+						error: error instanceof Error ? error.message : String(error),
+					});
+				}
+			}
+		}
+
+		this.loaded.set(language, index);
+		return index;
+	}
+
+	private async save(language: string, index: LoadedIndex): Promise<void> {
+		if (!isValidLanguageCode(language)) {
+			return;
+		}
+
+		const persisted: PersistedIndex = {
+			format: INDEX_FORMAT,
+			documents: Object.fromEntries(
+				[...index.documents].sort(([a], [b]) => compareStrings(a, b)),
+			),
+			postings: Object.fromEntries(
+				[...index.postings]
+					.sort(([a], [b]) => compareStrings(a, b))
+					.map(([term, names]) => [term, [...names].sort(compareStrings)]),
+			),
+		};
+
+		try {
+			await writeFileAtomic(
+				this.fileService,
+				this.getIndexPath(language),
+				JSON.stringify(persisted),
+			);
+		} catch (error) {
+			cacheLogger.error("content index write failed: {language} ({error})", {
+				language,
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	/**
+	 * Parse a persisted index; anything unreadable is rebuilt from scratch
+	 */
+	private parse(content: string): LoadedIndex | null {
+		try {
+			const parsed = JSON.parse(content) as Partial<PersistedIndex>;
+			if (
+				parsed?.format !== INDEX_FORMAT ||
+				typeof parsed.documents !== "object" ||
+				typeof parsed.postings !== "object"
+			) {
+				return null;
+			}
+			return {
+				documents: new Map(Object.entries(parsed.documents)),
+				postings: new Map(
+					Object.entries(parsed.postings).map(([term, names]) => [
+						term,
+						new Set(names),
+					]),
+				),
+			};
+		} catch {
+			return null;
+		}
+	}
+}
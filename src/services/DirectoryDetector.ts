@@ -1,40 +1,84 @@
 import os from "node:os";
 import path from "node:path";
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
 import type IFileService from "../interfaces/IFileService.js";
+import type { ScanOptions } from "../interfaces/IFileService.js";
 import type {
 	CommandScanResult,
 	DirectoryInfo,
 } from "../types/Installation.js";
+import { pathSegments } from "../utils/paths.js";
+
+/**
+ * Directories skipped when scanning commands directories: hidden directories
+ * (which never hold commands) and dependency trees
+ */
+export const DEFAULT_SCAN_IGNORE: readonly string[] = [
+	".*",
+	"node_modules",
+	"vendor",
+];
+
+/**
+ * Deepest namespace level scanned in commands directories
+ */
+export const DEFAULT_SCAN_MAX_DEPTH = 10;
 
 /**
  * DirectoryDetector handles detection and management of Claude command directories
  * across different platforms and installation locations.
  */
-export class DirectoryDetector {
-	constructor(public readonly fileService: IFileService) {}
+export class DirectoryDetector implements IDirectoryDetector {
+	/**
+	 * @param fileService File service used to inspect the directories
+	 * @param claudeDir User's Claude directory holding the personal commands
+	 *   (defaults to ~/.claude; see resolveClaudeDir())
+	 * @param projectRoot Directory whose .claude holds the project commands
+	 *   (defaults to the working directory; see resolveProjectRoots())
+	 * @param scanOptions Resolves the configured scan limits (scanIgnore and
+	 *   scanMaxDepth) on each scan; unset limits use the defaults
+	 * @param outerProjectRoots Roots of the projects enclosing projectRoot,
+	 *   nearest first (e.g., the root of a monorepo whose packages have their
+	 *   own .claude; see resolveProjectRoots())
+	 */
+	constructor(
+		public readonly fileService: IFileService,
+		private readonly claudeDir?: string,
+		private readonly projectRoot?: string,
+		private readonly scanOptions: () => Promise<ScanOptions> = async () => ({}),
+		private readonly outerProjectRoots: readonly string[] = [],
+	) {}
 
 	/**
 	 * Get all Claude directories (personal and project-specific)
+	 *
+	 * The project directory comes before those of enclosing projects, so a
+	 * command of the nearest project shadows one of the same name further up.
+	 *
 	 * @returns Array of directory information
 	 */
 	async getClaudeDirectories(): Promise<DirectoryInfo[]> {
 		const personalPath = await this.getPersonalDirectory();
-		const projectPath = await this.getProjectDirectory();
-
-		const [personalExists, projectExists] = await Promise.all([
-			this.fileService.exists(personalPath),
-			this.fileService.exists(projectPath),
-		]);
+		const personalExists = await this.fileService.exists(personalPath);
 
 		// Check writability - assume writable if directory exists or parent directory is writable
 		const personalWritable = await this.checkWritability(
 			personalPath,
 			personalExists,
 		);
-		const projectWritable = await this.checkWritability(
-			projectPath,
-			projectExists,
-		);
+
+		const projects: DirectoryInfo[] = [];
+		for (const projectRoot of this.getProjectRoots()) {
+			const projectPath = this.commandsDirectoryOf(projectRoot);
+			const projectExists = await this.fileService.exists(projectPath);
+			projects.push({
+				path: projectPath,
+				type: "project",
+				exists: projectExists,
+				writable: await this.checkWritability(projectPath, projectExists),
+				projectRoot,
+			});
+		}
 
 		return [
 			{
@@ -43,12 +87,7 @@ export class DirectoryDetector {
 				exists: personalExists,
 				writable: personalWritable,
 			},
-			{
-				path: projectPath,
-				type: "project",
-				exists: projectExists,
-				writable: projectWritable,
-			},
+			...projects,
 		];
 	}
 
@@ -57,8 +96,9 @@ export class DirectoryDetector {
 	 * @returns Absolute path to personal directory
 	 */
 	async getPersonalDirectory(): Promise<string> {
-		const homeDir = this.getHomeDirectory();
-		const personalPath = path.join(homeDir, ".claude", "commands");
+		const claudeDir =
+			this.claudeDir ?? path.join(this.getHomeDirectory(), ".claude");
+		const personalPath = path.join(claudeDir, "commands");
 
 		// For cross-platform compatibility, only resolve if the path doesn't start with a drive letter
 		// This prevents issues when running Unix tests with Windows paths
@@ -71,11 +111,15 @@ export class DirectoryDetector {
 
 	/**
 	 * Get the project-specific Claude commands directory path
+	 *
+	 * The relative form is relative to the working directory, so it reads
+	 * "../../.claude/commands" when run from a subdirectory of the project.
+	 *
 	 * @param absolute Whether to return absolute path (default: false)
 	 * @returns Path to project directory
 	 */
 	async getProjectDirectory(absolute = false): Promise<string> {
-		const projectPath = path.join(".claude", "commands");
+		const projectPath = this.commandsDirectoryOf(this.projectRoot);
 
 		if (absolute) {
 			return path.resolve(projectPath);
@@ -84,6 +128,47 @@ export class DirectoryDetector {
 		return projectPath;
 	}
 
+	/**
+	 * Get the commands directories of the project and the projects enclosing
+	 * it, nearest first
+	 *
+	 * @param absolute Whether to return absolute paths (default: false)
+	 * @returns Paths to the project directories
+	 */
+	async getProjectDirectories(absolute = false): Promise<string[]> {
+		return this.getProjectRoots().map((root) => {
+			const projectPath = this.commandsDirectoryOf(root);
+			return absolute ? path.resolve(projectPath) : projectPath;
+		});
+	}
+
+	/**
+	 * Get the root of the project whose commands are in scope
+	 *
+	 * This is the directory holding the project's .claude, which differs from
+	 * the working directory when it was found by walking upwards.
+	 *
+	 * @returns Absolute project root
+	 */
+	getProjectRoot(): string {
+		return path.resolve(this.projectRoot ?? ".");
+	}
+
+	/**
+	 * Get the roots of the project and the projects enclosing it
+	 *
+	 * Commands are installed into the first; the others only add the
+	 * commands they hold.
+	 *
+	 * @returns Absolute project roots, nearest first
+	 */
+	getProjectRoots(): string[] {
+		return [
+			this.getProjectRoot(),
+			...this.outerProjectRoots.map((root) => path.resolve(root)),
+		];
+	}
+
 	/**
 	 * Ensure a directory exists, creating it if necessary
 	 * @param dirPath Path to the directory
@@ -109,8 +194,24 @@ export class DirectoryDetector {
 		return await this.getPersonalDirectory();
 	}
 
+	/**
+	 * Get the configured scan limits, with defaults for unset ones
+	 * @returns Depth limit and ignored directory patterns
+	 */
+	async getScanOptions(): Promise<Required<ScanOptions>> {
+		const { maxDepth, ignore } = await this.scanOptions();
+		return {
+			maxDepth: maxDepth ?? DEFAULT_SCAN_MAX_DEPTH,
+			ignore: ignore ?? DEFAULT_SCAN_IGNORE,
+		};
+	}
+
 	/**
 	 * Recursively scan a directory for command files (.md files only)
+	 *
+	 * Directories matching the ignore patterns or deeper than the depth limit
+	 * are pruned before being read, so a commands directory inside a large
+	 * tree does not stall installed and status.
 	 * @param directoryPath Path to scan
 	 * @returns Array of absolute paths to .md files
 	 */
@@ -122,8 +223,10 @@ export class DirectoryDetector {
 			}
 
 			// Use the existing scanNamespaceHierarchy method for consistency
-			const namespacedFiles =
-				await this.fileService.scanNamespaceHierarchy(directoryPath);
+			const namespacedFiles = await this.fileService.scanNamespaceHierarchy(
+				directoryPath,
+				await this.getScanOptions(),
+			);
 
 			// Extract full file paths, filter .md files, and exclude hidden files/directories
 			const commandFiles = namespacedFiles
@@ -139,8 +242,7 @@ export class DirectoryDetector {
 					}
 
 					// Exclude files in hidden directories (any path segment starting with .)
-					const pathSegments = file.relativePath.split("/");
-					for (const segment of pathSegments) {
+					for (const segment of pathSegments(file.relativePath)) {
 						if (segment.startsWith(".")) {
 							return false;
 						}
@@ -160,23 +262,39 @@ export class DirectoryDetector {
 
 	/**
 	 * Scan all Claude directories (both personal and project) for command files
+	 *
+	 * Project files list the project's own commands before those of enclosing
+	 * projects.
+	 *
 	 * @returns Object with command files categorized by location
 	 */
 	async scanAllClaudeDirectories(): Promise<CommandScanResult> {
 		const personalDir = await this.getPersonalDirectory();
-		const projectDir = await this.getProjectDirectory(false); // Use relative path for consistency with tests
+		const projectDirs = await this.getProjectDirectories(false); // Use relative paths for consistency with tests
 
 		const [personalFiles, projectFiles] = await Promise.all([
 			this.scanForCommandFiles(personalDir),
-			this.scanForCommandFiles(projectDir),
+			Promise.all(projectDirs.map((dir) => this.scanForCommandFiles(dir))),
 		]);
 
 		return {
 			personal: personalFiles,
-			project: projectFiles,
+			project: projectFiles.flat(),
 		};
 	}
 
+	/**
+	 * Get the commands directory of a project, relative to the working
+	 * directory
+	 *
+	 * @param root Project root (defaults to the working directory)
+	 * @returns Path to the project's commands directory
+	 */
+	private commandsDirectoryOf(root?: string): string {
+		const relativeRoot = root ? path.relative(process.cwd(), root) : "";
+		return path.join(relativeRoot, ".claude", "commands");
+	}
+
 	/**
 	 * Get the home directory for the current user
 	 * Cross-platform implementation that handles Windows, macOS, and Linux
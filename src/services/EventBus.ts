@@ -0,0 +1,43 @@
+import type { ClaudeCmdEvent } from "../types/Events.js";
+
+/**
+ * Receives every event published on a bus
+ */
+export type EventListener = (event: ClaudeCmdEvent) => void;
+
+/**
+ * Publish/subscribe channel for progress, installation and error events
+ *
+ * Services publish what happens without knowing who listens; the CLI
+ * subscribes to print --json-events and embedders subscribe to drive their
+ * own UI. Listeners run synchronously in subscription order, and a listener
+ * that throws never breaks the operation that published the event.
+ */
+export class EventBus {
+	private readonly listeners = new Set<EventListener>();
+
+	/**
+	 * Receive events from now on
+	 *
+	 * @returns Function that stops delivery to the listener
+	 */
+	subscribe(listener: EventListener): () => void {
+		this.listeners.add(listener);
+		return () => {
+			this.listeners.delete(listener);
+		};
+	}
+
+	/**
+	 * Deliver an event to every listener
+	 */
+	emit(event: ClaudeCmdEvent): void {
+		for (const listener of this.listeners) {
+			try {
+				listener(event);
+			} catch {
+				// Listeners observe; they must not fail the operation
+			}
+		}
+	}
+}
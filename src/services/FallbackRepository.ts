@@ -0,0 +1,106 @@
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import { CommandContentError, ManifestError } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { repoLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+
+/**
+ * Repository that falls back to a secondary source when the primary fails
+ *
+ * Used to serve imported offline bundles: the configured repository is always
+ * tried first, and only when it cannot deliver a manifest or command file
+ * (network down, air-gapped machine) is the fallback consulted. A command that
+ * the primary reports as missing is not looked up in the fallback, so stale
+ * bundles never shadow the live repository.
+ */
+export class FallbackRepository implements IRepository {
+	constructor(
+		private readonly primary: IRepository,
+		private readonly fallback: IRepository,
+	) {}
+
+	async getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		try {
+			return await this.primary.getManifest(language, options);
+		} catch (error) {
+			return this.tryFallback(error, "manifest", language, () =>
+				this.fallback.getManifest(language, options),
+			);
+		}
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		try {
+			return await this.primary.getCommand(commandName, language, options);
+		} catch (error) {
+			return this.tryFallback(error, commandName, language, () =>
+				this.fallback.getCommand(commandName, language, options),
+			);
+		}
+	}
+
+	/**
+	 * Merge languages from both sources, preferring primary information
+	 */
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		const [primary, fallback] = await Promise.all([
+			this.primary.getAvailableLanguages().catch(() => []),
+			this.fallback.getAvailableLanguages().catch(() => []),
+		]);
+
+		const codes = new Set(primary.map((language) => language.code));
+		return [
+			...primary,
+			...fallback.filter((language) => !codes.has(language.code)),
+		].sort(
+			(a, b) =>
+				b.commandCount - a.commandCount || compareStrings(a.code, b.code),
+		);
+	}
+
+	/**
+	 * Describe the primary repository only; bundles are not the catalog being
+	 * browsed, even when they serve its commands
+	 */
+	getAbout(language: string): Promise<RepositoryAbout | null> {
+		return this.primary.getAbout(language);
+	}
+
+	/**
+	 * Run the fallback for retrieval errors, rethrowing the original error if
+	 * the fallback cannot help either
+	 */
+	private async tryFallback<T>(
+		error: unknown,
+		subject: string,
+		language: string,
+		fetch: () => Promise<T>,
+	): Promise<T> {
+		// CommandNotFoundError means the primary answered; only retrieval failures fall back
+		if (
+			!(error instanceof ManifestError || error instanceof CommandContentError)
+		) {
+			throw error;
+		}
+
+		try {
+			const result = await fetch();
+			repoLogger.warn(
+				"using offline bundle for {subject} ({language}): {error}",
+				{ subject, language, error: error.message },
+			);
+			return result;
+		} catch {
+			throw error;
+		}
+	}
+}
@@ -0,0 +1,398 @@
+import { join } from "node:path";
+import { fileURLToPath } from "node:url";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type { Command, Manifest, RepositoryOptions } from "../types/Command.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+	ManifestSignatureError,
+} from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { repoLogger } from "../utils/logger.js";
+import { ManifestIndex } from "../utils/manifestIndex.js";
+import {
+	type MinisignPublicKey,
+	parsePublicKey,
+	SignatureError,
+	verifySignature,
+} from "../utils/minisign.js";
+import {
+	isValidFileName,
+	isValidLanguageCode,
+	normalizeLanguageCode,
+} from "../utils/naming.js";
+import { compareStrings, sortCommands } from "../utils/ordering.js";
+import type { CommandParser } from "./CommandParser.js";
+import { migrateManifest, type RawManifest } from "./ManifestMigrations.js";
+import { ABOUT_FILE, loadRepositoryAbout } from "./shared/repositoryAbout.js";
+
+/**
+ * Local filesystem repository implementation
+ *
+ * Reads manifests and command files directly from a directory on disk laid
+ * out like the published commands repository:
+ *
+ * ```
+ * {commandsDir}/
+ *   en/
+ *     manifest.json      (optional)
+ *     debug-help.md
+ *     frontend/component.md
+ * ```
+ *
+ * When a language directory has no manifest.json, the manifest is built by
+ * parsing every .md file in it. The repository keeps no cache of its own, so
+ * every call reflects the files as they are on disk, which is what makes this
+ * source useful while developing a commands repository and in air-gapped
+ * environments (run `claude-cmd cache update` to refresh the manifest cache).
+ *
+ * Selected with `repositoryURL: "file:///path/to/commands"`. GitRepository
+ * also uses it to read its cloned working tree.
+ *
+ * With a public key, manifest.json must come with a valid detached signature
+ * (manifest.json.minisig) like on the HTTP source, and language directories
+ * without manifest.json are refused since there is nothing to verify.
+ *
+ * @example
+ * ```typescript
+ * const repository = new FileSystemRepository(fileService, commandParser, "/src/commands/commands");
+ * const manifest = await repository.getManifest("en");
+ * ```
+ */
+export default class FileSystemRepository implements IRepository {
+	private readonly publicKey?: MinisignPublicKey;
+
+	/**
+	 * @param fileService - File service used for all reads
+	 * @param commandParser - Parser used to build manifests from .md files
+	 * @param commandsDir - Directory containing one subdirectory per language
+	 * @param manifestPublicKey - minisign public key manifests must be signed
+	 *   with, if any
+	 * @throws SignatureError if the public key is malformed
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly commandParser: CommandParser,
+		private readonly commandsDir: string,
+		manifestPublicKey?: string,
+	) {
+		if (!commandsDir || typeof commandsDir !== "string") {
+			throw new Error("Commands directory is required");
+		}
+		if (manifestPublicKey) {
+			this.publicKey = parsePublicKey(manifestPublicKey);
+		}
+	}
+
+	/**
+	 * Create a repository from a file:// URL
+	 *
+	 * The URL may point either at a repository checkout (containing a commands/
+	 * directory) or directly at the commands directory itself.
+	 *
+	 * @param url - file:// URL (e.g., "file:///home/me/commands")
+	 * @param manifestPublicKey - minisign public key manifests must be signed
+	 *   with, if any
+	 * @throws Error if the URL is not a file:// URL
+	 */
+	static async fromFileURL(
+		fileService: IFileService,
+		commandParser: CommandParser,
+		url: string,
+		manifestPublicKey?: string,
+	): Promise<FileSystemRepository> {
+		if (!FileSystemRepository.isFileURL(url)) {
+			throw new Error(`Not a file:// URL: ${url}`);
+		}
+
+		const root = fileURLToPath(url);
+		const nested = join(root, "commands");
+		const commandsDir = (await fileService.exists(nested)) ? nested : root;
+
+		return new FileSystemRepository(
+			fileService,
+			commandParser,
+			commandsDir,
+			manifestPublicKey,
+		);
+	}
+
+	/**
+	 * Check whether a repository URL selects the local filesystem source
+	 */
+	static isFileURL(url: string): boolean {
+		return url.toLowerCase().startsWith("file://");
+	}
+
+	/**
+	 * Get the directory this repository reads from
+	 */
+	getCommandsDir(): string {
+		return this.commandsDir;
+	}
+
+	async getManifest(
+		language: string,
+		_options?: RepositoryOptions,
+	): Promise<Manifest> {
+		const validatedLanguage = this.validateLanguageCode(language);
+		const langDir = this.languageDir(validatedLanguage);
+
+		if (!(await this.fileService.exists(langDir))) {
+			throw new ManifestError(
+				validatedLanguage,
+				`Language not available in repository (${langDir} not found)`,
+				true,
+			);
+		}
+
+		const manifestPath = join(langDir, "manifest.json");
+		if (await this.fileService.exists(manifestPath)) {
+			const body = await this.fileService.readFile(manifestPath);
+			if (this.publicKey) {
+				await this.verifyManifest(
+					manifestPath,
+					body,
+					validatedLanguage,
+					this.publicKey,
+				);
+			}
+
+			let manifest: unknown;
+			try {
+				manifest = JSON.parse(body);
+			} catch (error) {
+				throw new ManifestError(
+					validatedLanguage,
+					`Invalid JSON format in manifest.json: ${error instanceof Error ? error.message : error}`,
+				);
+			}
+
+			if (
+				!manifest ||
+				typeof manifest !== "object" ||
+				!Array.isArray((manifest as { commands?: unknown }).commands)
+			) {
+				throw new ManifestError(
+					validatedLanguage,
+					"Manifest does not contain valid commands array",
+				);
+			}
+
+			return migrateManifest(manifest as RawManifest, validatedLanguage);
+		}
+
+		if (this.publicKey) {
+			throw new ManifestSignatureError(
+				validatedLanguage,
+				"manifest is not signed (no manifest.json to verify)",
+			);
+		}
+		return this.buildManifest(validatedLanguage, langDir);
+	}
+
+	/**
+	 * Check a manifest against its detached signature
+	 *
+	 * @throws ManifestSignatureError if the signature is missing or invalid
+	 */
+	private async verifyManifest(
+		manifestPath: string,
+		body: string,
+		language: string,
+		publicKey: MinisignPublicKey,
+	): Promise<void> {
+		const signaturePath = `${manifestPath}.minisig`;
+		if (!(await this.fileService.exists(signaturePath))) {
+			throw new ManifestSignatureError(language, "manifest is not signed");
+		}
+
+		try {
+			verifySignature(
+				body,
+				await this.fileService.readFile(signaturePath),
+				publicKey,
+			);
+		} catch (error) {
+			if (error instanceof SignatureError) {
+				throw new ManifestSignatureError(language, error.message);
+			}
+			throw error;
+		}
+		repoLogger.debug("manifest signature verified: {path} (key {keyId})", {
+			path: manifestPath,
+			keyId: publicKey.keyId,
+		});
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		if (!commandName || typeof commandName !== "string") {
+			throw new CommandNotFoundError(commandName, language);
+		}
+
+		const validatedLanguage = this.validateLanguageCode(language);
+		const manifest = await this.getManifest(validatedLanguage, options);
+		const command = ManifestIndex.of(manifest).get(commandName);
+
+		if (!command) {
+			throw new CommandNotFoundError(commandName, validatedLanguage);
+		}
+
+		// Manifest paths must stay inside the language directory
+		if (!isValidFileName(command.file)) {
+			throw new CommandContentError(
+				commandName,
+				validatedLanguage,
+				"Command file path is missing or invalid in manifest",
+			);
+		}
+
+		const filePath = join(this.languageDir(validatedLanguage), command.file);
+		try {
+			return await this.fileService.readFile(filePath);
+		} catch (error) {
+			throw new CommandContentError(
+				commandName,
+				validatedLanguage,
+				`Failed to read command file ${filePath}: ${error instanceof Error ? error.message : error}`,
+				error instanceof FileNotFoundError,
+			);
+		}
+	}
+
+	/**
+	 * Discover available languages from the language subdirectories
+	 *
+	 * @returns Languages sorted by command count (descending), then code
+	 */
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		let files: string[];
+		try {
+			if (!(await this.fileService.exists(this.commandsDir))) {
+				return [];
+			}
+			files = await this.fileService.listFilesRecursive(this.commandsDir);
+		} catch (error) {
+			repoLogger.debug("language discovery failed: {path} ({error})", {
+				path: this.commandsDir,
+				error: error instanceof Error ? error.message : error,
+			});
+			return [];
+		}
+
+		const codes = new Set<string>();
+		for (const file of files) {
+			const [code] = file.split(/[/\\]/);
+			if (code && isValidLanguageCode(code)) {
+				codes.add(code);
+			}
+		}
+
+		const languages: LanguageStatusInfo[] = [];
+		for (const code of codes) {
+			try {
+				const manifest = await this.getManifest(code);
+				languages.push({
+					code,
+					name: code,
+					commandCount: manifest.commands.length,
+				});
+			} catch {
+				// Skip languages whose manifest cannot be read
+			}
+		}
+
+		return languages.sort(
+			(a, b) =>
+				b.commandCount - a.commandCount || compareStrings(a.code, b.code),
+		);
+	}
+
+	/**
+	 * Read about.json from the commands directory and the language directory
+	 */
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		const validatedLanguage = this.validateLanguageCode(language);
+
+		return loadRepositoryAbout(
+			async (path) =>
+				(await this.fileService.exists(path))
+					? this.fileService.readFile(path)
+					: null,
+			[
+				join(this.commandsDir, ABOUT_FILE),
+				join(this.languageDir(validatedLanguage), ABOUT_FILE),
+			],
+		);
+	}
+
+	/**
+	 * Build a manifest by parsing every .md file of a language directory
+	 */
+	private async buildManifest(
+		language: string,
+		langDir: string,
+	): Promise<Manifest> {
+		const files = (await this.fileService.listFilesRecursive(langDir)).filter(
+			(file) => file.endsWith(".md"),
+		);
+
+		const commands: Command[] = [];
+		for (const file of files) {
+			const relativePath = file.replace(/\\/g, "/");
+			try {
+				const content = await this.fileService.readFile(join(langDir, file));
+				commands.push(
+					await this.commandParser.parseCommandFile(content, relativePath),
+				);
+			} catch (error) {
+				repoLogger.warn("skipping unparsable command file: {file} ({error})", {
+					file: relativePath,
+					error: error instanceof Error ? error.message : error,
+				});
+			}
+		}
+
+		repoLogger.debug(
+			"built manifest from {path}: {language} ({count} commands)",
+			{ path: langDir, language, count: commands.length },
+		);
+
+		return {
+			version: "1.0.0",
+			updated: new Date().toISOString(),
+			commands: sortCommands(commands),
+		};
+	}
+
+	private languageDir(language: string): string {
+		return join(this.commandsDir, language);
+	}
+
+	private validateLanguageCode(language: string): string {
+		if (!language || typeof language !== "string") {
+			throw new ManifestError(
+				language,
+				"Language code must be a non-empty string",
+			);
+		}
+
+		const normalized = normalizeLanguageCode(language);
+		if (!normalized) {
+			throw new ManifestError(
+				language,
+				"Language code must be 2-3 lowercase letters (ISO 639 format)",
+			);
+		}
+
+		return normalized;
+	}
+}
@@ -0,0 +1,304 @@
+import { createHash } from "node:crypto";
+import { join } from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import type IGitClient from "../interfaces/IGitClient.js";
+import { GitError } from "../interfaces/IGitClient.js";
+import type IRepository from "../interfaces/IRepository.js";
+import {
+	CacheConfig,
+	type LanguageStatusInfo,
+} from "../interfaces/IRepository.js";
+import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import { CommandNotFoundError, ManifestError } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { repoLogger } from "../utils/logger.js";
+import { normalizeLanguageCode } from "../utils/naming.js";
+import type { CommandParser } from "./CommandParser.js";
+import FileSystemRepository from "./FileSystemRepository.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Configuration for a git-backed repository
+ */
+export interface GitRepositoryOptions {
+	/** Clone URL (https, ssh, or scp-like "git@host:owner/repo.git") */
+	readonly url: string;
+	/** Branch or tag to check out (default: remote HEAD) */
+	readonly ref?: string;
+	/** Cache configuration; the working tree lives under cacheDir/git */
+	readonly cacheConfig?: CacheConfig;
+	/** Clock used for sync staleness (default: system time) */
+	readonly clock?: IClock;
+	/** minisign public key manifests in the working tree must be signed with */
+	readonly manifestPublicKey?: string;
+}
+
+/**
+ * Sync marker persisted next to the working tree
+ */
+interface SyncState {
+	url: string;
+	ref?: string;
+	timestamp: number;
+}
+
+/**
+ * Git-based repository implementation
+ *
+ * Mirrors a commands repository into the cache directory with a shallow clone
+ * and serves manifests and command files straight from the working tree. The
+ * repository uses the same layout as the HTTP source:
+ *
+ * ```
+ * commands/
+ *   en/
+ *     manifest.json      (optional)
+ *     debug-help.md
+ *     frontend/component.md
+ * ```
+ *
+ * Reads are delegated to a FileSystemRepository over the working tree, so a
+ * language directory without manifest.json gets a manifest built from its .md
+ * files and private repositories need no manifest generation step.
+ *
+ * The working tree is pulled again when it is older than the cache TTL or
+ * when forceRefresh is requested. Pinning to a tag is done through the ref
+ * option.
+ *
+ * @example
+ * ```typescript
+ * const repository = new GitRepository(new BunGitClient(), fileService, commandParser, {
+ *   url: "git@github.com:acme/commands.git",
+ *   ref: "v1.4.0",
+ * });
+ * const manifest = await repository.getManifest("en");
+ * ```
+ */
+export default class GitRepository implements IRepository {
+	private readonly cacheConfig: CacheConfig;
+	private readonly clock: IClock;
+	private readonly workTree: string;
+	private readonly syncFile: string;
+	private readonly tree: FileSystemRepository;
+
+	/** In-flight or completed sync for this process, shared across calls */
+	private syncPromise: Promise<void> | null = null;
+
+	constructor(
+		private readonly gitClient: IGitClient,
+		private readonly fileService: IFileService,
+		commandParser: CommandParser,
+		private readonly options: GitRepositoryOptions,
+	) {
+		if (!options?.url || typeof options.url !== "string") {
+			throw new Error("Git repository URL is required");
+		}
+
+		this.cacheConfig = options.cacheConfig ?? new CacheConfig();
+		this.clock = options.clock ?? new SystemClock();
+
+		// One working tree per (url, ref) pair so switching refs never mixes files
+		const key = createHash("sha256")
+			.update(`${options.url}#${options.ref ?? ""}`)
+			.digest("hex")
+			.slice(0, 12);
+		const gitDir = join(this.cacheConfig.cacheDir, "git");
+		this.workTree = join(gitDir, key);
+		this.syncFile = join(gitDir, `${key}.sync.json`);
+		this.tree = new FileSystemRepository(
+			fileService,
+			commandParser,
+			join(this.workTree, "commands"),
+			options.manifestPublicKey,
+		);
+	}
+
+	/**
+	 * Get the local working tree path for this repository
+	 *
+	 * @returns Absolute path of the working tree inside the cache directory
+	 */
+	getWorkTreePath(): string {
+		return this.workTree;
+	}
+
+	async getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		await this.syncForLanguage(language, options);
+		return this.tree.getManifest(language);
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		if (!commandName || typeof commandName !== "string") {
+			throw new CommandNotFoundError(commandName, language);
+		}
+
+		await this.syncForLanguage(language, options);
+		return this.tree.getCommand(commandName, language);
+	}
+
+	/**
+	 * Discover available languages from the local working tree
+	 *
+	 * Only inspects an existing working tree; nothing is cloned, matching the
+	 * HTTP repository which reports languages from its cache.
+	 */
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return this.tree.getAvailableLanguages();
+	}
+
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		try {
+			await this.syncForLanguage(language);
+		} catch (error) {
+			repoLogger.debug("about.json unavailable: {url} ({error})", {
+				url: this.options.url,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return null;
+		}
+		return this.tree.getAbout(language);
+	}
+
+	/**
+	 * Validate the language code, then make sure the working tree is usable
+	 *
+	 * @throws ManifestError when the language is invalid or the sync fails
+	 */
+	private async syncForLanguage(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<void> {
+		const validatedLanguage = this.validateLanguageCode(language);
+
+		try {
+			await this.ensureWorkTree(options);
+		} catch (error) {
+			throw new ManifestError(
+				validatedLanguage,
+				`Failed to sync git repository: ${error instanceof Error ? error.message : error}`,
+			);
+		}
+	}
+
+	/**
+	 * Clone the repository if missing, or pull it when stale or forced
+	 */
+	private async ensureWorkTree(options?: RepositoryOptions): Promise<void> {
+		if (this.syncPromise && !options?.forceRefresh) {
+			return this.syncPromise;
+		}
+
+		this.syncPromise = this.sync(options?.forceRefresh ?? false);
+		try {
+			await this.syncPromise;
+		} catch (error) {
+			// Allow a later call to retry after a failed sync
+			this.syncPromise = null;
+			throw error;
+		}
+	}
+
+	private async sync(force: boolean): Promise<void> {
+		const { url, ref } = this.options;
+		const checkout = ref ? { ref } : undefined;
+
+		if (!(await this.fileService.exists(this.workTree))) {
+			repoLogger.debug("git clone: {url} (ref: {ref})", {
+				url,
+				ref: ref ?? "HEAD",
+			});
+			await this.fileService.mkdir(join(this.cacheConfig.cacheDir, "git"));
+			await this.gitClient.clone(url, this.workTree, checkout);
+			await this.writeSyncState();
+			return;
+		}
+
+		if (!force && !(await this.isStale())) {
+			repoLogger.debug("git working tree is fresh: {path}", {
+				path: this.workTree,
+			});
+			return;
+		}
+
+		try {
+			repoLogger.debug("git update: {url} (ref: {ref})", {
+				url,
+				ref: ref ?? "HEAD",
+			});
+			await this.gitClient.update(this.workTree, checkout);
+			await this.writeSyncState();
+		} catch (error) {
+			// An existing working tree is still usable when offline
+			if (error instanceof GitError && !force) {
+				repoLogger.warn(
+					"git update failed, using existing working tree: {error}",
+					{ error: error.message },
+				);
+				return;
+			}
+			throw error;
+		}
+	}
+
+	private async isStale(): Promise<boolean> {
+		try {
+			const state = JSON.parse(
+				await this.fileService.readFile(this.syncFile),
+			) as Partial<SyncState>;
+			if (typeof state.timestamp !== "number") {
+				return true;
+			}
+			return this.clock.now() - state.timestamp >= this.cacheConfig.ttl;
+		} catch {
+			return true;
+		}
+	}
+
+	private async writeSyncState(): Promise<void> {
+		const state: SyncState = {
+			url: this.options.url,
+			ref: this.options.ref,
+			timestamp: this.clock.now(),
+		};
+		try {
+			await writeFileAtomic(
+				this.fileService,
+				this.syncFile,
+				JSON.stringify(state, null, 2),
+			);
+		} catch (error) {
+			// Failing to record the sync only means the next run pulls again
+			repoLogger.error("git sync state write failed: {error}", {
+				error: error instanceof Error ? error.message : error,
+			});
+		}
+	}
+
+	private validateLanguageCode(language: string): string {
+		if (!language || typeof language !== "string") {
+			throw new ManifestError(
+				language,
+				"Language code must be a non-empty string",
+			);
+		}
+
+		const normalized = normalizeLanguageCode(language);
+		if (!normalized) {
+			throw new ManifestError(
+				language,
+				"Language code must be 2-3 lowercase letters (ISO 639 format)",
+			);
+		}
+
+		return normalized;
+	}
+}
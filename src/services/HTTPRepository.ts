@@ -1,8 +1,12 @@
+import { createHash } from "node:crypto";
 import { join } from "node:path";
+import type IClock from "../interfaces/IClock.js";
 import type IFileService from "../interfaces/IFileService.js";
 import type IHTTPClient from "../interfaces/IHTTPClient.js";
 import {
 	HTTPNetworkError,
+	HTTPRateLimitError,
+	HTTPResponseTooLargeError,
 	HTTPStatusError,
 	HTTPTimeoutError,
 } from "../interfaces/IHTTPClient.js";
@@ -16,8 +20,46 @@ import {
 	CommandContentError,
 	CommandNotFoundError,
 	ManifestError,
+	ManifestSignatureError,
 } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
 import { repoLogger } from "../utils/logger.js";
+import { ManifestIndex } from "../utils/manifestIndex.js";
+import {
+	createVerifier,
+	type MinisignPublicKey,
+	parsePublicKey,
+	SignatureError,
+	type SignatureVerifier,
+} from "../utils/minisign.js";
+import { normalizeLanguageCode } from "../utils/naming.js";
+import { compareStrings } from "../utils/ordering.js";
+import { ManifestDecoder } from "./ManifestDecoder.js";
+import { ABOUT_FILE, loadRepositoryAbout } from "./shared/repositoryAbout.js";
+import {
+	LANGUAGES_FILE,
+	type PublishedLanguage,
+	parseRepositoryLanguages,
+} from "./shared/repositoryLanguages.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Largest responses accepted from the repository, so a broken or malicious
+ * server cannot exhaust memory (unset means the defaults below)
+ */
+export interface ResponseSizeLimits {
+	/** Manifests and other metadata files (about.json, signatures) */
+	readonly maxManifestBytes?: number;
+	/** Command files */
+	readonly maxCommandBytes?: number;
+}
+
+/** Default manifest size limit (10 MiB) */
+export const DEFAULT_MAX_MANIFEST_BYTES = 10 * 1024 * 1024;
+
+/** Default command file size limit (1 MiB) */
+export const DEFAULT_MAX_COMMAND_BYTES = 1024 * 1024;
 
 /**
  * GitHub-based HTTP repository implementation
@@ -61,28 +103,69 @@ export default class HTTPRepository implements IRepository {
 	private readonly httpClient: IHTTPClient;
 	private readonly fileService: IFileService;
 	private readonly cacheConfig: CacheConfig;
+	private readonly clock: IClock;
+	private readonly baseUrl: string;
+	private readonly publicKey?: MinisignPublicKey;
+	private readonly maxManifestBytes: number;
+	private readonly maxCommandBytes: number;
 
 	/**
 	 * Base URL for the GitHub repository containing command definitions
 	 * Points to the main branch of the claude-cmd/commands repository
 	 */
-	private static readonly BASE_URL =
+	static readonly BASE_URL =
 		"https://raw.githubusercontent.com/claude-code-commands/commands/refs/heads/main";
 
 	/**
-	 * Regular expression for validating language codes (ISO 639-1 format)
-	 * Accepts 2-letter language codes like 'en', 'fr', 'es', etc.
+	 * @param httpClient - HTTP client for repository requests
+	 * @param fileService - File service for the local cache
+	 * @param cacheConfig - Cache location and TTL (defaults to OS-specific)
+	 * @param clock - Clock used for cache expiry (defaults to system time)
+	 * @param baseUrl - Repository root containing commands/ (defaults to
+	 *   BASE_URL); other repositories are cached in their own subdirectory
+	 * @param manifestPublicKey - minisign public key; when given, manifests
+	 *   must come with a valid detached signature (manifest.json.minisig)
+	 * @param sizeLimits - Largest responses accepted (defaults to 10 MiB for
+	 *   manifests and 1 MiB for command files)
+	 * @throws SignatureError if the public key is malformed
 	 */
-	private static readonly LANGUAGE_CODE_PATTERN = /^[a-z]{2}$/;
-
 	constructor(
 		httpClient: IHTTPClient,
 		fileService: IFileService,
 		cacheConfig?: CacheConfig,
+		clock?: IClock,
+		baseUrl: string = HTTPRepository.BASE_URL,
+		manifestPublicKey?: string,
+		sizeLimits: ResponseSizeLimits = {},
 	) {
 		this.httpClient = httpClient;
 		this.fileService = fileService;
-		this.cacheConfig = cacheConfig ?? new CacheConfig();
+		this.maxManifestBytes =
+			sizeLimits.maxManifestBytes ?? DEFAULT_MAX_MANIFEST_BYTES;
+		this.maxCommandBytes =
+			sizeLimits.maxCommandBytes ?? DEFAULT_MAX_COMMAND_BYTES;
+		this.clock = clock ?? new SystemClock();
+		this.baseUrl = baseUrl.replace(/\/+$/, "");
+		if (manifestPublicKey) {
+			this.publicKey = parsePublicKey(manifestPublicKey);
+		}
+
+		const cache = cacheConfig ?? new CacheConfig();
+		if (this.baseUrl === HTTPRepository.BASE_URL && !this.publicKey) {
+			this.cacheConfig = cache;
+		} else {
+			// Keep each repository's manifests apart so switching never mixes
+			// them, and never serve manifests cached before verification
+			const key = createHash("sha256")
+				.update(this.baseUrl)
+				.update(this.publicKey ? `#${this.publicKey.keyId}` : "")
+				.digest("hex")
+				.slice(0, 12);
+			this.cacheConfig = new CacheConfig({
+				cacheDir: join(cache.cacheDir, "http", key),
+				ttl: cache.ttl,
+			});
+		}
 
 		// Validate dependencies at construction time
 		if (!httpClient) {
@@ -93,6 +176,20 @@ export default class HTTPRepository implements IRepository {
 		}
 	}
 
+	/**
+	 * Check whether a repository URL can be served over HTTP(S)
+	 */
+	static isHTTPURL(url: string): boolean {
+		return /^https?:\/\//i.test(url);
+	}
+
+	/**
+	 * Get the repository root URL requests are made against
+	 */
+	getBaseUrl(): string {
+		return this.baseUrl;
+	}
+
 	/**
 	 * Sanitize path components to prevent directory traversal attacks
 	 * Removes potentially dangerous characters and patterns that could escape cache directory
@@ -143,16 +240,16 @@ export default class HTTPRepository implements IRepository {
 			);
 		}
 
-		const trimmed = language.trim().toLowerCase();
+		const normalized = normalizeLanguageCode(language);
 
-		if (!HTTPRepository.LANGUAGE_CODE_PATTERN.test(trimmed)) {
+		if (!normalized) {
 			throw new ManifestError(
 				language,
-				"Language code must be 2 lowercase letters (ISO 639-1 format)",
+				"Language code must be 2-3 lowercase letters (ISO 639 format)",
 			);
 		}
 
-		return trimmed;
+		return normalized;
 	}
 
 	/**
@@ -164,6 +261,9 @@ export default class HTTPRepository implements IRepository {
 	 * 3. Cache the fresh data with timestamp for future use
 	 * 4. Return the data (cached or fresh)
 	 *
+	 * If the fetch fails because the server is rate-limiting, expired cached
+	 * data is returned instead with a warning, even when force-refreshing.
+	 *
 	 * Cache failures are handled gracefully - the operation will continue with HTTP
 	 * requests even if caching fails, ensuring repository operations are resilient.
 	 *
@@ -181,6 +281,7 @@ export default class HTTPRepository implements IRepository {
 		options?: RepositoryOptions,
 	): Promise<T> {
 		const cachePath = join(this.cacheConfig.cacheDir, cacheKey);
+		let stale: { data: T; timestamp: number } | undefined;
 
 		// Phase 1: Check cache first (unless force refresh requested)
 		if (!options?.forceRefresh) {
@@ -205,7 +306,8 @@ export default class HTTPRepository implements IRepository {
 						}
 
 						// Check if cache has expired based on TTL
-						const cacheAge = Date.now() - cachedData.timestamp;
+						const cacheAge = this.clock.now() - cachedData.timestamp;
+						stale = cachedData;
 						if (cacheAge < this.cacheConfig.ttl) {
 							// Cache hit - return cached data
 							repoLogger.debug(
@@ -241,7 +343,29 @@ export default class HTTPRepository implements IRepository {
 
 		// Phase 2: Fetch fresh data from source
 		repoLogger.debug("fetching fresh data: {cacheKey}", { cacheKey });
-		const freshData = await dataFetcher();
+		let freshData: T;
+		try {
+			freshData = await dataFetcher();
+		} catch (error) {
+			// While the server is rate-limiting, expired data beats no data
+			const retryAt = rateLimitRetryAt(error);
+			if (retryAt === undefined) {
+				throw error;
+			}
+			stale ??= await this.readExpiredCache(cachePath, dataValidator);
+			if (!stale) {
+				throw error;
+			}
+			repoLogger.warn(
+				"repository is rate-limiting requests; using cached {cacheKey} from {cachedAt} until {retryAt}",
+				{
+					cacheKey,
+					cachedAt: new Date(stale.timestamp).toISOString(),
+					retryAt: new Date(retryAt).toISOString(),
+				},
+			);
+			return stale.data;
+		}
 
 		// Phase 3: Cache the fresh data for future use
 		try {
@@ -250,11 +374,12 @@ export default class HTTPRepository implements IRepository {
 
 			const cacheData = {
 				data: freshData,
-				timestamp: Date.now(),
+				timestamp: this.clock.now(),
 				version: "1.0", // For future cache format migration support
 			};
 
-			await this.fileService.writeFile(
+			await writeFileAtomic(
+				this.fileService,
 				cachePath,
 				JSON.stringify(cacheData, null, 2),
 			);
@@ -273,6 +398,36 @@ export default class HTTPRepository implements IRepository {
 		return freshData;
 	}
 
+	/**
+	 * Read a cache entry regardless of its age
+	 *
+	 * @returns The entry, or undefined if it is missing or invalid
+	 */
+	private async readExpiredCache<T>(
+		cachePath: string,
+		dataValidator: (data: unknown) => boolean,
+	): Promise<{ data: T; timestamp: number } | undefined> {
+		try {
+			const cachedData = JSON.parse(await this.fileService.readFile(cachePath));
+			return typeof cachedData === "object" &&
+				cachedData !== null &&
+				typeof cachedData.timestamp === "number" &&
+				dataValidator(cachedData)
+				? cachedData
+				: undefined;
+		} catch {
+			return undefined;
+		}
+	}
+
+	/**
+	 * Describe a rate limit for ManifestError and CommandContentError
+	 */
+	private describeRateLimit(error: HTTPRateLimitError): string {
+		const retryAt = error.retryAt ?? this.clock.now();
+		return `Server is rate-limiting requests (${error.status} ${error.statusText}); retry after ${new Date(retryAt).toISOString()}`;
+	}
+
 	/**
 	 * Retrieve the command manifest for a specific language
 	 *
@@ -283,6 +438,8 @@ export default class HTTPRepository implements IRepository {
 	 * @param options - Optional caching and refresh configuration
 	 * @returns Promise resolving to the complete manifest for the language
 	 * @throws ManifestError when manifest cannot be retrieved, parsed, or language is invalid
+	 * @throws ManifestSignatureError when a public key is configured and the
+	 *   manifest is unsigned or its signature does not verify
 	 */
 	async getManifest(
 		language: string,
@@ -306,45 +463,38 @@ export default class HTTPRepository implements IRepository {
 		// Fetcher function that retrieves fresh manifest data from GitHub
 		const manifestFetcher = async (): Promise<Manifest> => {
 			try {
-				const manifestUrl = `${HTTPRepository.BASE_URL}/commands/${validatedLanguage}/manifest.json`;
-				const response = await this.httpClient.get(manifestUrl);
-
-				// Validate response has content
-				if (!response.body || response.body.trim() === "") {
-					throw new ManifestError(
-						validatedLanguage,
-						"Empty response received from server",
-					);
+				const manifestUrl = `${this.baseUrl}/commands/${validatedLanguage}/manifest.json`;
+
+				// The signature is fetched first so the body can be checked as
+				// it streams in, and decoded without ever being held whole
+				// (see ManifestDecoder)
+				const verifier = this.publicKey
+					? await this.createManifestVerifier(
+							manifestUrl,
+							validatedLanguage,
+							this.publicKey,
+						)
+					: undefined;
+				const response = await this.httpClient.stream(manifestUrl, {
+					maxBytes: this.maxManifestBytes,
+				});
+				const decoder = new ManifestDecoder(validatedLanguage);
+				for await (const chunk of response.body) {
+					verifier?.update(chunk);
+					decoder.write(chunk);
 				}
 
-				// Parse and validate manifest JSON structure
-				let manifest: unknown;
-				try {
-					manifest = JSON.parse(response.body);
-				} catch (parseError) {
-					throw new ManifestError(
-						validatedLanguage,
-						`Invalid JSON format received from server: ${parseError instanceof Error ? parseError.message : parseError}`,
-					);
+				if (verifier) {
+					this.verifyManifest(manifestUrl, verifier, validatedLanguage);
 				}
-
-				// Basic manifest structure validation
-				if (
-					!manifest ||
-					typeof manifest !== "object" ||
-					!Array.isArray((manifest as { commands?: unknown }).commands)
-				) {
-					throw new ManifestError(
-						validatedLanguage,
-						"Manifest does not contain valid commands array",
-					);
-				}
-
-				return manifest as Manifest;
+				return decoder.end();
 			} catch (error) {
 				// Transform HTTP and other errors to ManifestError with proper context
-				if (error instanceof ManifestError) {
-					// Already a ManifestError, re-throw as-is
+				if (
+					error instanceof ManifestError ||
+					error instanceof ManifestSignatureError
+				) {
+					// Already classified, re-throw as-is
 					throw error;
 				} else if (error instanceof HTTPTimeoutError) {
 					throw new ManifestError(
@@ -356,10 +506,23 @@ export default class HTTPRepository implements IRepository {
 						validatedLanguage,
 						`Network connection failed: ${error.cause || "Connection error"}`,
 					);
+				} else if (error instanceof HTTPResponseTooLargeError) {
+					throw new ManifestError(
+						validatedLanguage,
+						`Manifest is larger than the limit of ${error.maxBytes} bytes (maxManifestBytes)`,
+					);
+				} else if (error instanceof HTTPRateLimitError) {
+					throw new ManifestError(
+						validatedLanguage,
+						this.describeRateLimit(error),
+						false,
+						error.retryAt ?? this.clock.now(),
+					);
 				} else if (error instanceof HTTPStatusError) {
 					throw new ManifestError(
 						validatedLanguage,
 						`Server returned ${error.status} ${error.statusText} for manifest request`,
+						error.status === 404,
 					);
 				} else {
 					throw new ManifestError(
@@ -378,6 +541,73 @@ export default class HTTPRepository implements IRepository {
 		);
 	}
 
+	/**
+	 * Fetch a manifest's detached signature and start verifying it
+	 *
+	 * A missing, malformed or foreign signature yields a verifier that fails,
+	 * so errors about the manifest itself (such as a 404) are still reported
+	 * first.
+	 */
+	private async createManifestVerifier(
+		manifestUrl: string,
+		language: string,
+		publicKey: MinisignPublicKey,
+	): Promise<SignatureVerifier> {
+		const failing = (reason: string): SignatureVerifier => ({
+			update: () => {},
+			verify: () => {
+				throw new ManifestSignatureError(language, reason);
+			},
+		});
+
+		let signature: string;
+		try {
+			signature = (
+				await this.httpClient.get(`${manifestUrl}.minisig`, {
+					maxBytes: this.maxManifestBytes,
+				})
+			).body;
+		} catch (error) {
+			if (error instanceof HTTPStatusError && error.status === 404) {
+				return failing("manifest is not signed");
+			}
+			throw error;
+		}
+
+		try {
+			return createVerifier(signature, publicKey);
+		} catch (error) {
+			if (error instanceof SignatureError) {
+				return failing(error.message);
+			}
+			throw error;
+		}
+	}
+
+	/**
+	 * Check a manifest, read through the verifier, against its signature
+	 *
+	 * @throws ManifestSignatureError if the signature does not match
+	 */
+	private verifyManifest(
+		manifestUrl: string,
+		verifier: SignatureVerifier,
+		language: string,
+	): void {
+		try {
+			verifier.verify();
+		} catch (error) {
+			if (error instanceof SignatureError) {
+				throw new ManifestSignatureError(language, error.message);
+			}
+			throw error;
+		}
+		repoLogger.debug("manifest signature verified: {url} (key {keyId})", {
+			url: manifestUrl,
+			keyId: this.publicKey?.keyId,
+		});
+	}
+
 	/**
 	 * Retrieve the content of a specific command file
 	 *
@@ -406,7 +636,7 @@ export default class HTTPRepository implements IRepository {
 		// First verify the command exists in the manifest (this validates language too)
 		const validatedLanguage = this.validateLanguageCode(language);
 		const manifest = await this.getManifest(validatedLanguage, options);
-		const command = manifest.commands.find((cmd) => cmd.name === commandName);
+		const command = ManifestIndex.of(manifest).get(commandName);
 
 		if (!command) {
 			throw new CommandNotFoundError(commandName, validatedLanguage);
@@ -434,8 +664,10 @@ export default class HTTPRepository implements IRepository {
 		// Fetcher function that retrieves fresh command content from GitHub
 		const contentFetcher = async (): Promise<string> => {
 			try {
-				const commandUrl = `${HTTPRepository.BASE_URL}/commands/${validatedLanguage}/${command.file}`;
-				const response = await this.httpClient.get(commandUrl);
+				const commandUrl = `${this.baseUrl}/commands/${validatedLanguage}/${command.file}`;
+				const response = await this.httpClient.get(commandUrl, {
+					maxBytes: this.maxCommandBytes,
+				});
 
 				// Validate response has content
 				if (response.body === undefined || response.body === null) {
@@ -472,11 +704,26 @@ export default class HTTPRepository implements IRepository {
 						validatedLanguage,
 						`Network connection failed: ${error.cause || "Connection error"}`,
 					);
+				} else if (error instanceof HTTPResponseTooLargeError) {
+					throw new CommandContentError(
+						commandName,
+						validatedLanguage,
+						`Command file is larger than the limit of ${error.maxBytes} bytes (maxCommandBytes)`,
+					);
+				} else if (error instanceof HTTPRateLimitError) {
+					throw new CommandContentError(
+						commandName,
+						validatedLanguage,
+						this.describeRateLimit(error),
+						false,
+						error.retryAt ?? this.clock.now(),
+					);
 				} else if (error instanceof HTTPStatusError) {
 					throw new CommandContentError(
 						commandName,
 						validatedLanguage,
 						`Server returned ${error.status} ${error.statusText} for command file request`,
+						error.status === 404,
 					);
 				} else {
 					throw new CommandContentError(
@@ -497,17 +744,131 @@ export default class HTTPRepository implements IRepository {
 	}
 
 	/**
-	 * Discover available languages from the repository cache
+	 * Retrieve about.json metadata from the commands root and language directory
+	 *
+	 * Results, including "not published", are cached like manifests. Network
+	 * failures are not cached and yield null.
+	 *
+	 * @param language - ISO 639-1 language code (e.g., 'en', 'fr', 'es')
+	 * @returns Promise resolving to the merged metadata, or null
+	 */
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		const validatedLanguage = this.validateLanguageCode(language);
+		const sanitizedLanguage = this.sanitizePathComponent(validatedLanguage);
+		const cacheKey = `about-${sanitizedLanguage}.json`;
+
+		// Fetcher that treats 404 as "not published" and fails on anything else,
+		// so a partial result from a flaky network is never cached
+		const aboutFetcher = async (): Promise<RepositoryAbout | null> => {
+			let failure: unknown;
+			const about = await loadRepositoryAbout(
+				async (url) => {
+					try {
+						const maxBytes = this.maxManifestBytes;
+						return (await this.httpClient.get(url, { maxBytes })).body;
+					} catch (error) {
+						if (error instanceof HTTPStatusError && error.status === 404) {
+							return null;
+						}
+						failure = error;
+						throw error;
+					}
+				},
+				[
+					`${this.baseUrl}/commands/${ABOUT_FILE}`,
+					`${this.baseUrl}/commands/${validatedLanguage}/${ABOUT_FILE}`,
+				],
+			);
+			if (failure) {
+				throw failure;
+			}
+			return about;
+		};
+
+		try {
+			return await this.getCachedData(
+				cacheKey,
+				aboutFetcher,
+				(cachedData) => "data" in (cachedData as object),
+			);
+		} catch (error) {
+			repoLogger.debug("about.json unavailable: {language} ({error})", {
+				language: validatedLanguage,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return null;
+		}
+	}
+
+	/**
+	 * Retrieve the languages the repository publishes in languages.json
+	 *
+	 * The index is cached next to the manifests, including "not published".
+	 * Network failures are not cached and yield an empty list.
+	 *
+	 * @returns Published languages, or an empty array
+	 */
+	async getPublishedLanguages(): Promise<PublishedLanguage[]> {
+		const languagesFetcher = async (): Promise<PublishedLanguage[]> => {
+			try {
+				const response = await this.httpClient.get(
+					`${this.baseUrl}/commands/${LANGUAGES_FILE}`,
+					{ maxBytes: this.maxManifestBytes },
+				);
+				const languages = parseRepositoryLanguages(response.body);
+				if (!languages) {
+					repoLogger.warn("ignoring malformed {file}", {
+						file: LANGUAGES_FILE,
+					});
+				}
+				return languages ?? [];
+			} catch (error) {
+				if (error instanceof HTTPStatusError && error.status === 404) {
+					return [];
+				}
+				throw error;
+			}
+		};
+
+		try {
+			return await this.getCachedData(
+				LANGUAGES_FILE,
+				languagesFetcher,
+				(cachedData) =>
+					Array.isArray((cachedData as { data?: unknown }).data),
+			);
+		} catch (error) {
+			repoLogger.debug("{file} unavailable ({error})", {
+				file: LANGUAGES_FILE,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return [];
+		}
+	}
+
+	/**
+	 * Discover available languages from the repository
 	 *
-	 * Dynamically discovers which languages have command manifests available by
-	 * scanning the cache directory structure. Falls back to attempting network
-	 * discovery if cache is empty. Provides command counts for each language
-	 * to help users assess the value of each language option.
+	 * Combines the languages.json index published at the commands root with
+	 * the manifests already in the cache, so new languages show up without a
+	 * CLI release. Command counts come from cached manifests; published
+	 * languages that have not been fetched yet report 0.
 	 *
 	 * @returns Promise resolving to array of language information with command counts
 	 */
 	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		const published = await this.getPublishedLanguages();
+		const unfetched = (language: PublishedLanguage): LanguageStatusInfo => ({
+			code: language.code,
+			name: language.name ?? this.getLanguageName(language.code),
+			commandCount: 0,
+		});
 		const languages: LanguageStatusInfo[] = [];
+		const sortLanguages = () =>
+			languages.sort(
+				(a, b) =>
+					b.commandCount - a.commandCount || compareStrings(a.code, b.code),
+			);
 
 		try {
 			// Check if cache directory exists
@@ -515,8 +876,9 @@ export default class HTTPRepository implements IRepository {
 				this.cacheConfig.cacheDir,
 			);
 			if (!cacheDirExists) {
-				// No cache yet, return empty array (caller can use common languages)
-				return [];
+				// Nothing cached yet; only the published index is known
+				languages.push(...published.map(unfetched));
+				return sortLanguages();
 			}
 
 			// List all files in the cache directory
@@ -525,7 +887,7 @@ export default class HTTPRepository implements IRepository {
 			);
 
 			// Find all manifest files (format: manifest-{lang}.json)
-			const manifestPattern = /^manifest-([a-z]{2})\.json$/;
+			const manifestPattern = /^manifest-([a-z]{2,3})\.json$/;
 
 			for (const entry of entries) {
 				const match = entry.match(manifestPattern);
@@ -551,8 +913,10 @@ export default class HTTPRepository implements IRepository {
 						continue;
 					}
 
-					// Try to get language name from known languages or use code as fallback
-					const languageName = this.getLanguageName(languageCode);
+					// Prefer the published name, then known names, then the code
+					const languageName =
+						published.find((language) => language.code === languageCode)
+							?.name ?? this.getLanguageName(languageCode);
 
 					languages.push({
 						code: languageCode,
@@ -571,16 +935,22 @@ export default class HTTPRepository implements IRepository {
 				}
 			}
 
-			// Sort languages by command count (descending) for better UX
-			languages.sort((a, b) => b.commandCount - a.commandCount);
+			// Published languages whose manifest has not been cached yet
+			for (const language of published) {
+				if (!languages.some((known) => known.code === language.code)) {
+					languages.push(unfetched(language));
+				}
+			}
 
-			return languages;
+			// Sort languages by command count (descending) for better UX,
+			// breaking ties by language code for deterministic output
+			return sortLanguages();
 		} catch (error) {
-			// If we can't read the cache directory, return empty array
+			// If we can't read the cache directory, fall back to the index
 			repoLogger.debug("error reading cache directory (error: {error})", {
 				error: error instanceof Error ? error.message : String(error),
 			});
-			return [];
+			return published.map(unfetched);
 		}
 	}
 
@@ -618,3 +988,18 @@ export default class HTTPRepository implements IRepository {
 		return knownLanguages.get(code) || code.toUpperCase();
 	}
 }
+
+/**
+ * Find out whether an error was caused by the server rate-limiting requests
+ *
+ * @returns When the server may be asked again, or undefined for other errors
+ */
+function rateLimitRetryAt(error: unknown): number | undefined {
+	if (error instanceof HTTPRateLimitError) {
+		return error.retryAt ?? 0;
+	}
+	if (error instanceof ManifestError || error instanceof CommandContentError) {
+		return error.retryAt;
+	}
+	return undefined;
+}
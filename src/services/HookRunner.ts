@@ -0,0 +1,138 @@
+import type { HookEvent } from "../utils/hooks.js";
+import { installLogger } from "../utils/logger.js";
+
+/**
+ * Default time a hook may run before it is killed
+ */
+export const DEFAULT_HOOK_TIMEOUT_MS = 60000;
+
+/**
+ * What a hook runs around, passed to it as CLAUDE_CMD_* variables
+ */
+export interface HookContext {
+	/** Command being added or removed (CLAUDE_CMD_COMMAND) */
+	readonly command?: string;
+	/** Language of the command or manifest (CLAUDE_CMD_LANGUAGE) */
+	readonly language?: string;
+	/** Install target: personal or project (CLAUDE_CMD_TARGET) */
+	readonly target?: string;
+}
+
+/**
+ * Error thrown when a hook exits with a non-zero code or times out
+ */
+export class HookError extends Error {
+	constructor(
+		message: string,
+		public readonly event: HookEvent,
+		public readonly hook: string,
+		public readonly exitCode?: number,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Runs project hooks as shell commands from the project root
+ *
+ * Each hook gets the caller's environment plus variables describing the
+ * operation:
+ *
+ * - CLAUDE_CMD_HOOK: the event, e.g. "post-add"
+ * - CLAUDE_CMD_OPERATION: add, remove or update
+ * - CLAUDE_CMD_COMMAND, CLAUDE_CMD_LANGUAGE, CLAUDE_CMD_TARGET: see HookContext
+ *   (empty when they do not apply)
+ * - CLAUDE_CMD_PROJECT_ROOT: the directory the hook runs in
+ *
+ * Hook output goes to stderr so it never mixes with porcelain output.
+ */
+export class HookRunner {
+	/**
+	 * @param projectRoot - Directory hooks run in
+	 * @param timeoutMs - Time each hook may run before it is killed
+	 */
+	constructor(
+		private readonly projectRoot: string,
+		private readonly timeoutMs: number = DEFAULT_HOOK_TIMEOUT_MS,
+	) {}
+
+	/**
+	 * Run the hooks of an event one after another, stopping at the first
+	 * failure
+	 *
+	 * @param event - Event being run
+	 * @param hooks - Shell commands to run
+	 * @param context - Operation details for the environment
+	 * @throws HookError when a hook fails or times out
+	 */
+	async run(
+		event: HookEvent,
+		hooks: readonly string[],
+		context: HookContext = {},
+	): Promise<void> {
+		for (const hook of hooks) {
+			await this.runOne(event, hook, context);
+		}
+	}
+
+	private async runOne(
+		event: HookEvent,
+		hook: string,
+		context: HookContext,
+	): Promise<void> {
+		installLogger.debug("running {event} hook: {hook}", { event, hook });
+
+		const shell =
+			process.platform === "win32"
+				? ["cmd", "/d", "/s", "/c", hook]
+				: ["sh", "-c", hook];
+		let proc: ReturnType<typeof Bun.spawn>;
+		try {
+			proc = Bun.spawn(shell, {
+				cwd: this.projectRoot,
+				stdin: "ignore",
+				stdout: 2,
+				stderr: "inherit",
+				env: {
+					...process.env,
+					CLAUDE_CMD_HOOK: event,
+					CLAUDE_CMD_OPERATION: event.slice(event.indexOf("-") + 1),
+					CLAUDE_CMD_COMMAND: context.command ?? "",
+					CLAUDE_CMD_LANGUAGE: context.language ?? "",
+					CLAUDE_CMD_TARGET: context.target ?? "",
+					CLAUDE_CMD_PROJECT_ROOT: this.projectRoot,
+				},
+			});
+		} catch (error) {
+			throw new HookError(
+				`Failed to start ${event} hook '${hook}': ${error instanceof Error ? error.message : error}`,
+				event,
+				hook,
+			);
+		}
+
+		let timedOut = false;
+		const timer = setTimeout(() => {
+			timedOut = true;
+			proc.kill();
+		}, this.timeoutMs);
+		const exitCode = await proc.exited.finally(() => clearTimeout(timer));
+
+		if (timedOut) {
+			throw new HookError(
+				`${event} hook '${hook}' timed out after ${this.timeoutMs}ms`,
+				event,
+				hook,
+			);
+		}
+		if (exitCode !== 0) {
+			throw new HookError(
+				`${event} hook '${hook}' failed (exit code ${exitCode})`,
+				event,
+				hook,
+				exitCode,
+			);
+		}
+	}
+}
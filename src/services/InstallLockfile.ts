@@ -0,0 +1,261 @@
+import path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { withFileLock } from "../utils/fileLock.js";
+import { installLogger } from "../utils/logger.js";
+import SystemClock from "./SystemClock.js";
+import type { JournalOperation } from "./TransactionJournal.js";
+
+/**
+ * File name of the lockfile, stored next to the commands directory
+ * (e.g. ~/.claude/claude-cmd.lock.json or .claude/claude-cmd.lock.json)
+ */
+export const INSTALL_LOCKFILE_NAME = "claude-cmd.lock.json";
+
+/**
+ * Current lockfile format version
+ */
+export const INSTALL_LOCKFILE_VERSION = 1;
+
+/**
+ * What was installed for one command
+ */
+export interface LockEntry {
+	/** Repository manifest version the command was installed from */
+	readonly version: string;
+	/** SHA-256 (hex) of the installed content */
+	readonly sha256: string;
+	/** Language the command was installed in */
+	readonly language: string;
+	/** Installation time (ISO 8601) */
+	readonly installedAt: string;
+	/** Whether the user asked for this exact version (name@version) */
+	readonly pinned?: boolean;
+}
+
+interface LockfileContent {
+	version: number;
+	commands: Record<string, LockEntry>;
+}
+
+/**
+ * A lockfile entry to record or forget
+ */
+export interface LockChange {
+	/** Commands directory the command is installed in */
+	readonly commandsDir: string;
+	/** Installed command name */
+	readonly commandName: string;
+	/** New entry, or undefined to forget the command */
+	readonly entry: LockEntry | undefined;
+}
+
+/**
+ * Per-location record of the upstream version of each installed command
+ *
+ * The project lockfile can be committed so a team stays on the same versions.
+ * Like usage statistics, recording with set() never fails the install or
+ * removal being recorded: write errors are logged and dropped. Changes made
+ * with transact() are written together with the command files instead.
+ */
+export class InstallLockfile {
+	/**
+	 * @param fileService - File service for lockfile I/O
+	 * @param clock - Clock used for lock staleness (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Get the lockfile path for a commands directory
+	 *
+	 * @param commandsDir - Personal or project commands directory
+	 */
+	static pathFor(commandsDir: string): string {
+		return path.join(path.dirname(commandsDir), INSTALL_LOCKFILE_NAME);
+	}
+
+	/**
+	 * Look up the entry of an installed command
+	 *
+	 * @param commandsDir - Commands directory the command is installed in
+	 * @param commandName - Installed command name
+	 * @returns The entry, or undefined if the command was not recorded
+	 */
+	async get(
+		commandsDir: string,
+		commandName: string,
+	): Promise<LockEntry | undefined> {
+		const { commands } = await this.load(InstallLockfile.pathFor(commandsDir));
+		return Object.hasOwn(commands, commandName)
+			? commands[commandName]
+			: undefined;
+	}
+
+	/**
+	 * Record or forget an installed command
+	 *
+	 * @param commandsDir - Commands directory the command is installed in
+	 * @param commandName - Installed command name
+	 * @param entry - New entry, or undefined to remove the command
+	 */
+	async set(
+		commandsDir: string,
+		commandName: string,
+		entry: LockEntry | undefined,
+	): Promise<void> {
+		const lockfilePath = InstallLockfile.pathFor(commandsDir);
+		try {
+			await withFileLock(
+				this.fileService,
+				`${lockfilePath}.lock`,
+				async () => {
+					const content = await this.load(lockfilePath);
+					if (!applyChange(content, { commandsDir, commandName, entry })) {
+						return;
+					}
+					await writeFileAtomic(
+						this.fileService,
+						lockfilePath,
+						serialize(content),
+					);
+				},
+				{ clock: this.clock },
+			);
+		} catch (error) {
+			installLogger.warn("failed to update lockfile {lockfilePath}: {error}", {
+				lockfilePath,
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	/**
+	 * Change lockfiles together with other files
+	 *
+	 * Holds the locks of the affected lockfiles while apply runs and passes
+	 * it the lockfile writes the changes amount to, so they can be applied in
+	 * the same transaction as the command files (see TransactionJournal).
+	 * Unlike set(), errors are not dropped: command files and lockfiles
+	 * change together or not at all.
+	 *
+	 * @param changes - Entries to record or forget, in order
+	 * @param apply - Applies the writes, one per lockfile that changes
+	 * @returns The result of apply
+	 */
+	async transact<T>(
+		changes: readonly LockChange[],
+		apply: (operations: JournalOperation[]) => Promise<T>,
+	): Promise<T> {
+		const lockfilePaths = [
+			...new Set(
+				changes.map((change) => InstallLockfile.pathFor(change.commandsDir)),
+			),
+		].sort();
+		return this.withLocks(lockfilePaths, async () => {
+			const operations: JournalOperation[] = [];
+			for (const lockfilePath of lockfilePaths) {
+				const content = await this.load(lockfilePath);
+				let changed = false;
+				for (const change of changes) {
+					if (InstallLockfile.pathFor(change.commandsDir) === lockfilePath) {
+						changed = applyChange(content, change) || changed;
+					}
+				}
+				if (changed) {
+					operations.push({
+						type: "write",
+						path: lockfilePath,
+						content: serialize(content),
+					});
+				}
+			}
+			return apply(operations);
+		});
+	}
+
+	/**
+	 * Run a function while holding the locks of several lockfiles, taken in
+	 * the order given
+	 */
+	private async withLocks<T>(
+		lockfilePaths: readonly string[],
+		fn: () => Promise<T>,
+	): Promise<T> {
+		const [first, ...rest] = lockfilePaths;
+		if (first === undefined) {
+			return fn();
+		}
+		return withFileLock(
+			this.fileService,
+			`${first}.lock`,
+			() => this.withLocks(rest, fn),
+			{ clock: this.clock },
+		);
+	}
+
+	/**
+	 * Read a lockfile, treating a missing or unreadable file as empty
+	 */
+	private async load(lockfilePath: string): Promise<LockfileContent> {
+		const empty: LockfileContent = {
+			version: INSTALL_LOCKFILE_VERSION,
+			commands: {},
+		};
+
+		let content: string;
+		try {
+			content = await this.fileService.readFile(lockfilePath);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return empty;
+			}
+			throw error;
+		}
+
+		try {
+			const parsed = JSON.parse(content);
+			if (
+				typeof parsed?.commands !== "object" ||
+				parsed.commands === null ||
+				Array.isArray(parsed.commands)
+			) {
+				throw new Error("unexpected structure");
+			}
+			return {
+				version: INSTALL_LOCKFILE_VERSION,
+				commands: parsed.commands,
+			};
+		} catch (error) {
+			installLogger.warn("ignoring unreadable lockfile: {path} ({error})", {
+				path: lockfilePath,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return empty;
+		}
+	}
+}
+
+/**
+ * Record or forget an entry in lockfile content
+ *
+ * @returns Whether the content changed
+ */
+function applyChange(content: LockfileContent, change: LockChange): boolean {
+	if (change.entry) {
+		content.commands[change.commandName] = change.entry;
+	} else if (Object.hasOwn(content.commands, change.commandName)) {
+		delete content.commands[change.commandName];
+	} else {
+		return false;
+	}
+	return true;
+}
+
+function serialize(content: LockfileContent): string {
+	return `${JSON.stringify(content, null, 2)}\n`;
+}
@@ -1,24 +1,64 @@
+import { createHash } from "node:crypto";
 import path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
 import type IFileService from "../interfaces/IFileService.js";
 import type IInstallationService from "../interfaces/IInstallationService.js";
 import type IRepository from "../interfaces/IRepository.js";
 import type IUserInteractionService from "../interfaces/IUserInteractionService.js";
 import type { Command, CommandServiceOptions } from "../types/Command.js";
 import type {
+	CopyOptions,
+	DirectoryInfo,
+	ExportOptions,
+	ExportResult,
+	ImportOptions,
 	InstallationInfo,
 	InstallationSummary,
+	InstalledCommandLocation,
 	InstallOptions,
+	MoveOptions,
+	MoveResult,
 	RemoveOptions,
+	RestoreOptions,
+	TrashEntry,
 } from "../types/Installation.js";
 import {
 	CommandExistsError,
 	CommandNotInstalledError,
+	CommandPinnedError,
 	InstallationError,
+	VersionNotAvailableError,
 } from "../types/Installation.js";
+import { removeEmptyParentDirectories } from "../utils/emptyDirectories.js";
+import { resolveDependencies } from "../utils/dependencies.js";
+import {
+	addProvenance,
+	readProvenance,
+	rewriteFrontmatterField,
+	stripProvenance,
+} from "../utils/frontmatter.js";
 import { installLogger } from "../utils/logger.js";
+import { ManifestIndex } from "../utils/manifestIndex.js";
+import {
+	constructCommandPath,
+	UnsafeCommandNameError,
+} from "../utils/namespace.js";
+import { sortByOrderingKey } from "../utils/ordering.js";
+import { isInsideDirectory, pathSegments } from "../utils/paths.js";
+import { createTarGz, type TarEntry } from "../utils/tar.js";
 import type { CommandParser } from "./CommandParser.js";
-import type { DirectoryDetector } from "./DirectoryDetector.js";
+import type { EventBus } from "./EventBus.js";
 import type { LocalCommandRepository } from "./LocalCommandRepository.js";
+import { InstallLockfile, type LockChange } from "./InstallLockfile.js";
+import type { OperationHistory } from "./OperationHistory.js";
+import SystemClock from "./SystemClock.js";
+import {
+	applyJournalOperation,
+	type JournalOperation,
+	type TransactionJournal,
+} from "./TransactionJournal.js";
+import type { TrashService } from "./TrashService.js";
 
 // Re-export error classes for convenience
 export { InstallationError, CommandExistsError, CommandNotInstalledError };
@@ -42,10 +82,19 @@ export class InstallationService implements IInstallationService {
 	constructor(
 		private readonly repository: IRepository,
 		private readonly fileService: IFileService,
-		private readonly directoryDetector: DirectoryDetector,
+		private readonly directoryDetector: IDirectoryDetector,
 		private readonly commandParser: CommandParser,
 		private readonly localCommandRepository: LocalCommandRepository,
 		private readonly userInteractionService: IUserInteractionService,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly history?: OperationHistory,
+		private readonly trash?: TrashService,
+		private readonly lockfile: InstallLockfile = new InstallLockfile(
+			fileService,
+			clock,
+		),
+		private readonly events?: EventBus,
+		private readonly journal?: TransactionJournal,
 	) {}
 
 	/**
@@ -56,9 +105,12 @@ export class InstallationService implements IInstallationService {
 	 * to prevent path traversal attacks.
 	 *
 	 * @param commandName Name of the command to install (supports namespaced commands)
-	 * @param options Installation options (target directory, force overwrite, language)
+	 * @param options Installation options (target directory, force overwrite, language,
+	 *   local name via installAs)
 	 * @throws InstallationError if installation fails or command name is invalid
 	 * @throws CommandExistsError if command already exists and force is not specified
+	 * @throws VersionNotAvailableError if options.version is not the repository's
+	 * @throws CommandPinnedError if overwriting a command pinned to another version
 	 */
 	async installCommand(
 		commandName: string,
@@ -67,11 +119,21 @@ export class InstallationService implements IInstallationService {
 		try {
 			// Get command content from repository
 			const language = options?.language ?? "en";
+			const installName = options?.installAs ?? commandName;
 			const content = await this.repository.getCommand(commandName, language);
 
 			// Get repository manifest for version info
 			const manifest = await this.repository.getManifest(language);
 
+			// The repository serves a single version, so only it can be requested
+			if (options?.version && options.version !== manifest.version) {
+				throw new VersionNotAvailableError(
+					commandName,
+					options.version,
+					manifest.version,
+				);
+			}
+
 			// Validate command content
 			const isValid = await this.commandParser.validateCommandFile(content);
 			if (!isValid) {
@@ -91,28 +153,80 @@ export class InstallationService implements IInstallationService {
 			// Ensure target directory exists
 			await this.directoryDetector.ensureDirectoryExists(targetDir);
 
+			// Build a validated path (prevents path traversal attacks); namespaced
+			// commands map to nested directories, matching findCommandFile()
+			const filePath = this.buildCommandPath(installName, targetDir);
+			await this.assertNoLinkEscape(filePath, targetDir, installName);
+
 			// Check for existing installation
-			const filePath = path.join(targetDir, `${commandName}.md`);
 			const exists = await this.fileService.exists(filePath);
+			const previous = exists
+				? await this.lockfile.get(targetDir, installName)
+				: undefined;
 
-			if (exists && !options?.force) {
-				throw new CommandExistsError(commandName, filePath);
+			// A pin is only moved by explicitly requesting a version
+			if (
+				previous?.pinned &&
+				!options?.version &&
+				previous.version !== manifest.version
+			) {
+				throw new CommandPinnedError(
+					installName,
+					previous.version,
+					manifest.version,
+				);
 			}
 
-			// Validate command name for security (prevent path traversal attacks)
-			this.validateCommandName(commandName);
+			if (exists && !options?.force) {
+				throw new CommandExistsError(installName, filePath);
+			}
 
 			// Install the command
-			const installedAt = new Date();
-			await this.fileService.writeFile(filePath, content);
+			const installedAt = new Date(this.clock.now());
+			installLogger.debug(
+				"writing {commandName} ({language}) to {filePath} (overwrite: {exists})",
+				{ commandName, language, filePath, exists },
+			);
+			const sha256 = createHash("sha256").update(content, "utf8").digest("hex");
+			const pinned = options?.version !== undefined || previous?.pinned;
+			await this.applyChanges(
+				`install (${installName})`,
+				[
+					{
+						type: "write",
+						path: filePath,
+						content: options?.provenanceSource
+							? addProvenance(content, {
+									source: options.provenanceSource,
+									version: manifest.version,
+									sha256,
+									installedAt: installedAt.toISOString(),
+								})
+							: content,
+					},
+				],
+				[
+					{
+						commandsDir: targetDir,
+						commandName: installName,
+						entry: {
+							version: manifest.version,
+							sha256,
+							language,
+							installedAt: installedAt.toISOString(),
+							...(pinned ? { pinned: true } : {}),
+						},
+					},
+				],
+			);
 
 			// Determine the installation location type
 			const personalDir = await this.directoryDetector.getPersonalDirectory();
-			const isPersonal = !path.relative(personalDir, filePath).startsWith("..");
+			const isPersonal = isInsideDirectory(filePath, personalDir);
 			const locationType = isPersonal ? "personal" : "project";
 
 			// Store installation metadata in cache (use location-aware key)
-			const cacheKey = `${commandName}#${locationType}`;
+			const cacheKey = `${installName}#${locationType}`;
 			this.installationMetadataCache.set(cacheKey, {
 				source: "repository",
 				version: manifest.version,
@@ -126,9 +240,16 @@ export class InstallationService implements IInstallationService {
 			});
 
 			installLogger.info(
-				"installCommand success: {commandName} installed to {filePath} ({locationType})",
-				{ commandName, filePath, locationType },
+				"installCommand success: {commandName} ({language}) installed to {filePath} ({locationType})",
+				{ commandName, language, filePath, locationType },
 			);
+			this.events?.emit({
+				type: "installed",
+				command: installName,
+				language,
+				target: locationType,
+				path: filePath,
+			});
 		} catch (error) {
 			if (error instanceof InstallationError) {
 				throw error;
@@ -143,12 +264,65 @@ export class InstallationService implements IInstallationService {
 		}
 	}
 
+	/**
+	 * List the commands a command requires that are not installed yet
+	 *
+	 * Requirements come from the manifest entry, or the command file's
+	 * frontmatter when the manifest does not declare them, and are followed
+	 * transitively.
+	 *
+	 * @param commandName Name of the command about to be installed
+	 * @param language Language to read requirements in (default: en)
+	 * @returns Missing commands in install order
+	 * @throws DependencyCycleError if the requirements are circular
+	 * @throws CommandNotFoundError if a required command does not exist
+	 */
+	async getMissingDependencies(
+		commandName: string,
+		language = "en",
+	): Promise<string[]> {
+		const manifest = await this.repository.getManifest(language);
+		const required = await resolveDependencies(commandName, async (name) => {
+			const entry = ManifestIndex.of(manifest).get(name);
+			if (entry?.requires) {
+				return entry.requires;
+			}
+			// Also surfaces unknown requirements as CommandNotFoundError
+			const content = await this.repository.getCommand(name, language);
+			try {
+				const parsed = await this.commandParser.parseCommandFile(
+					content,
+					name,
+				);
+				return parsed.requires ?? [];
+			} catch {
+				// Invalid files are reported when they are installed
+				return [];
+			}
+		});
+
+		const missing: string[] = [];
+		for (const name of required) {
+			if (!(await this.isInstalled(name))) {
+				missing.push(name);
+			}
+		}
+		installLogger.debug(
+			"getMissingDependencies: {commandName} requires {required}, missing {missing}",
+			{ commandName, required, missing },
+		);
+		return missing;
+	}
+
 	async removeCommand(
 		commandName: string,
 		options?: RemoveOptions,
 	): Promise<void> {
 		try {
-			const installationPath = await this.getInstallationPath(commandName);
+			const installationPath = options?.location
+				? ((await this.findInstalledCommand(commandName, options.location))
+						?.filePath ?? null)
+				: await this.getInstallationPath(commandName);
 
 			if (!installationPath) {
 				throw new CommandNotInstalledError(commandName);
@@ -160,10 +334,12 @@ export class InstallationService implements IInstallationService {
 			// If --yes flag is provided, skip confirmation entirely
 			if (!options?.yes) {
 				// Get installation info for detailed confirmation message
-				const installationInfo = await this.getInstallationInfo(commandName);
+				const installationInfo = options?.location
+					? null
+					: await this.getInstallationInfo(commandName);
 				const locationText = installationInfo
 					? installationInfo.location
-					: "unknown";
+					: (options?.location ?? "unknown");
 				const pathText = installationInfo
 					? installationInfo.filePath
 					: installationPath;
@@ -186,11 +362,32 @@ export class InstallationService implements IInstallationService {
 
 			// Remove the file
 			if (await this.fileService.exists(installationPath)) {
-				await this.fileService.deleteFile(installationPath);
+				const commandsDir = await this.commandsDirOf(installationPath);
+				// Keep a copy so `claude-cmd restore` can bring it back
+				if (this.trash && !options?.purge) {
+					await this.trash.put(
+						{
+							name: commandName,
+							location: await this.locationOf(installationPath),
+							originalPath: installationPath,
+						},
+						await this.fileService.readFile(installationPath),
+						await this.lockfile.get(commandsDir, commandName),
+					);
+				}
+				await this.applyChanges(
+					`remove (${commandName})`,
+					[{ type: "delete", path: installationPath }],
+					[{ commandsDir, commandName, entry: undefined }],
+				);
 
 				// Clear cache entries for this command
 				this.invalidateCommandCache(commandName);
 
+				if (!options?.keepEmptyDirectories) {
+					await this.removeEmptyNamespaceDirectories(installationPath);
+				}
+
 				installLogger.info(
 					"command removed successfully: {commandName} (path: {path})",
 					{ commandName, path: installationPath },
@@ -210,15 +407,417 @@ export class InstallationService implements IInstallationService {
 		}
 	}
 
+	/**
+	 * Restore the most recently removed version of a command from the trash
+	 *
+	 * @param commandName Name the command was installed under
+	 * @param options Location to restore from and overwrite flag
+	 * @returns The restored trash entry
+	 * @throws InstallationError if the command is not in the trash
+	 * @throws CommandExistsError if the original path is taken and force is not set
+	 */
+	async restoreCommand(
+		commandName: string,
+		options?: RestoreOptions,
+	): Promise<TrashEntry> {
+		try {
+			const notInTrash = () =>
+				new InstallationError(
+					`Command '${commandName}' is not in the trash`,
+					"restore",
+					commandName,
+				);
+			const found = await this.trash?.find(commandName, options?.from);
+			if (!this.trash || !found) {
+				throw notInTrash();
+			}
+			const { entry, content, lock } = found;
+			if (
+				(await this.fileService.exists(entry.originalPath)) &&
+				!options?.force
+			) {
+				throw new CommandExistsError(commandName, entry.originalPath);
+			}
+
+			const commandsDir = await this.commandsDirOf(entry.originalPath);
+			await this.directoryDetector.ensureDirectoryExists(commandsDir);
+			// The lockfile entry comes back with the file, so an upgrade still
+			// knows the installed version
+			await this.applyChanges(
+				`restore (${commandName})`,
+				[{ type: "write", path: entry.originalPath, content }],
+				[{ commandsDir, commandName, entry: lock }],
+			);
+			// Only now that the file is back: a failed write keeps it in the trash
+			await this.trash.delete(entry.id);
+			this.invalidateCommandCache(commandName);
+
+			installLogger.info("command restored: {commandName} ({path})", {
+				commandName,
+				path: entry.originalPath,
+			});
+			return entry;
+		} catch (error) {
+			if (error instanceof InstallationError) {
+				throw error;
+			}
+
+			throw new InstallationError(
+				`Failed to restore command '${commandName}': ${error instanceof Error ? error.message : String(error)}`,
+				"restore",
+				commandName,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
+	/**
+	 * Move an installed command to a new name and/or location
+	 *
+	 * Supports renames within a location, moves between namespaces and moves
+	 * between the personal and project directories. The new file is written
+	 * before the old one is deleted, so a failure never loses the command.
+	 *
+	 * @param commandName Current name of the command
+	 * @param newName New name (supports namespaced commands)
+	 * @param options Source/target location, overwrite and frontmatter rewrite
+	 * @returns Old and new file paths
+	 * @throws CommandNotInstalledError if the command is not installed
+	 * @throws CommandExistsError if the destination exists and force is not set
+	 * @throws InstallationError if either name is invalid or the paths coincide
+	 */
+	async moveCommand(
+		commandName: string,
+		newName: string,
+		options?: MoveOptions,
+	): Promise<MoveResult> {
+		try {
+			const source = await this.findInstalledCommand(
+				commandName,
+				options?.from,
+			);
+			if (!source) {
+				throw new CommandNotInstalledError(commandName);
+			}
+
+			// The source may sit in an enclosing project, with its own lockfile
+			const sourceDir = await this.commandsDirOf(source.filePath);
+			const location = options?.target ?? source.location;
+			const targetDir =
+				location === source.location
+					? sourceDir
+					: await this.directoryDetector.getPreferredInstallLocation(location);
+			const toPath = this.buildCommandPath(newName, targetDir);
+			await this.assertNoLinkEscape(toPath, targetDir, newName);
+
+			if (path.resolve(toPath) === path.resolve(source.filePath)) {
+				throw new InstallationError(
+					`'${commandName}' is already installed at ${toPath}`,
+					"move",
+					commandName,
+				);
+			}
+			if ((await this.fileService.exists(toPath)) && !options?.force) {
+				throw new CommandExistsError(newName, toPath);
+			}
+
+			let content = await this.fileService.readFile(source.filePath);
+			if (options?.rewriteName) {
+				content = rewriteFrontmatterField(content, "name", newName);
+			}
+
+			// Carry installation metadata over to the new name and location
+			const lockEntry = await this.lockfile.get(sourceDir, commandName);
+
+			await this.directoryDetector.ensureDirectoryExists(targetDir);
+			await this.applyChanges(
+				`move (${commandName} -> ${newName})`,
+				[
+					{ type: "write", path: toPath, content },
+					{ type: "delete", path: source.filePath },
+				],
+				lockEntry
+					? [
+							{ commandsDir: sourceDir, commandName, entry: undefined },
+							{
+								commandsDir: targetDir,
+								commandName: newName,
+								entry: lockEntry,
+							},
+						]
+					: [],
+			);
+			if (!options?.keepEmptyDirectories) {
+				await this.removeEmptyNamespaceDirectories(source.filePath);
+			}
+
+			const metadata = this.installationMetadataCache.get(
+				`${commandName}#${source.location}`,
+			);
+			this.invalidateCommandCache(commandName);
+			if (metadata) {
+				this.installationMetadataCache.set(`${newName}#${location}`, {
+					...metadata,
+					location,
+				});
+			}
+
+			installLogger.info(
+				"command moved: {commandName} -> {newName} ({fromPath} -> {toPath})",
+				{ commandName, newName, fromPath: source.filePath, toPath },
+			);
+			return { fromPath: source.filePath, toPath, location };
+		} catch (error) {
+			if (error instanceof InstallationError) {
+				throw error;
+			}
+
+			throw new InstallationError(
+				`Failed to move command '${commandName}': ${error instanceof Error ? error.message : String(error)}`,
+				"move",
+				commandName,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
+	/**
+	 * Copy an installed command between the personal and project directories
+	 *
+	 * Lets a personal experiment be promoted into the shared project directory
+	 * (or a project command be kept personally) under the same name.
+	 *
+	 * @param commandName Name of the command (supports namespaced commands)
+	 * @param options Destination location and overwrite flag
+	 * @returns Source and destination file paths
+	 * @throws CommandNotInstalledError if the command is not in the other location
+	 * @throws CommandExistsError if the destination exists and force is not set
+	 */
+	async copyCommand(
+		commandName: string,
+		options: CopyOptions,
+	): Promise<MoveResult> {
+		try {
+			const from = options.to === "project" ? "personal" : "project";
+			const source = await this.findInstalledCommand(commandName, from);
+			if (!source) {
+				throw new CommandNotInstalledError(commandName);
+			}
+
+			const targetDir =
+				await this.directoryDetector.getPreferredInstallLocation(options.to);
+			const toPath = this.buildCommandPath(commandName, targetDir);
+			await this.assertNoLinkEscape(toPath, targetDir, commandName);
+			if ((await this.fileService.exists(toPath)) && !options.force) {
+				throw new CommandExistsError(commandName, toPath);
+			}
+
+			const content = await this.fileService.readFile(source.filePath);
+			const lockEntry = await this.lockfile.get(
+				await this.commandsDirOf(source.filePath),
+				commandName,
+			);
+			await this.directoryDetector.ensureDirectoryExists(targetDir);
+			await this.applyChanges(
+				`copy (${commandName})`,
+				[{ type: "write", path: toPath, content }],
+				lockEntry
+					? [{ commandsDir: targetDir, commandName, entry: lockEntry }]
+					: [],
+			);
+			const metadata = this.installationMetadataCache.get(
+				`${commandName}#${from}`,
+			);
+			if (metadata) {
+				this.installationMetadataCache.set(`${commandName}#${options.to}`, {
+					...metadata,
+					location: options.to,
+				});
+			}
+
+			installLogger.info(
+				"command copied: {commandName} ({fromPath} -> {toPath})",
+				{ commandName, fromPath: source.filePath, toPath },
+			);
+			return { fromPath: source.filePath, toPath, location: options.to };
+		} catch (error) {
+			if (error instanceof InstallationError) {
+				throw error;
+			}
+
+			throw new InstallationError(
+				`Failed to copy command '${commandName}': ${error instanceof Error ? error.message : String(error)}`,
+				"copy",
+				commandName,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
+	/**
+	 * Install a command file from outside the repository under a name
+	 *
+	 * Used to bring hand-written or shared command files into the managed
+	 * layout. The command is local: it gets no lockfile entry, and overwriting
+	 * a command installed from the repository forgets its entry, so it is
+	 * never upgraded.
+	 *
+	 * @param commandName Name to install under (supports namespaced commands)
+	 * @param content Command file content, already validated by the caller
+	 * @param options Target location, overwrite flag and provenance
+	 * @returns Path of the installed file
+	 * @throws CommandExistsError if the command exists and force is not set
+	 */
+	async importCommand(
+		commandName: string,
+		content: string,
+		options?: ImportOptions,
+	): Promise<string> {
+		try {
+			const targetDir =
+				await this.directoryDetector.getPreferredInstallLocation(
+					options?.target ?? "personal",
+				);
+			const filePath = this.buildCommandPath(commandName, targetDir);
+			await this.assertNoLinkEscape(filePath, targetDir, commandName);
+			if ((await this.fileService.exists(filePath)) && !options?.force) {
+				throw new CommandExistsError(commandName, filePath);
+			}
+
+			await this.directoryDetector.ensureDirectoryExists(targetDir);
+			await this.applyChanges(
+				`import (${commandName})`,
+				[
+					{
+						type: "write",
+						path: filePath,
+						content: options?.provenance
+							? addProvenance(content, options.provenance)
+							: content,
+					},
+				],
+				[{ commandsDir: targetDir, commandName, entry: undefined }],
+			);
+			this.invalidateCommandCache(commandName);
+
+			installLogger.info("command imported: {commandName} ({filePath})", {
+				commandName,
+				filePath,
+			});
+			return filePath;
+		} catch (error) {
+			if (error instanceof InstallationError) {
+				throw error;
+			}
+
+			throw new InstallationError(
+				`Failed to import command '${commandName}': ${error instanceof Error ? error.message : String(error)}`,
+				"import",
+				commandName,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
+	/**
+	 * Copy installed command files for sharing or backup
+	 *
+	 * Files keep their paths below the commands directory, so namespaces
+	 * survive and the result can be brought back with `import`. Provenance
+	 * recorded at install time is stripped, leaving the files as published.
+	 *
+	 * @param destination Archive to create (.tar.gz or .tgz) or directory
+	 * @param options Commands to export and overwrite flag
+	 * @returns What was written
+	 * @throws InstallationError if a destination file exists and force is not set
+	 */
+	async exportCommands(
+		destination: string,
+		options?: ExportOptions,
+	): Promise<ExportResult> {
+		const scope = options?.scope ?? "all";
+		const locations =
+			scope === "all" ? (["personal", "project"] as const) : [scope];
+		const format = /\.(tar\.gz|tgz)$/i.test(destination)
+			? "archive"
+			: "directory";
+
+		try {
+			const entries: TarEntry[] = [];
+			for (const location of locations) {
+				const dir =
+					await this.directoryDetector.getPreferredInstallLocation(location);
+				const files = await this.directoryDetector.scanForCommandFiles(dir);
+				for (const filePath of files) {
+					const relativePath = pathSegments(path.relative(dir, filePath)).join(
+						"/",
+					);
+					entries.push({
+						path:
+							scope === "all" ? `${location}/${relativePath}` : relativePath,
+						content: stripProvenance(await this.fileService.readFile(filePath)),
+					});
+				}
+			}
+
+			const targets =
+				format === "archive"
+					? [destination]
+					: entries.map((entry) => path.join(destination, entry.path));
+			if (!options?.force) {
+				for (const target of targets) {
+					if (await this.fileService.exists(target)) {
+						throw new InstallationError(
+							`${target} already exists (use --force to overwrite)`,
+							"export",
+						);
+					}
+				}
+			}
+
+			if (format === "archive") {
+				await this.fileService.writeBinaryFile(
+					destination,
+					createTarGz(entries, new Date(this.clock.now())),
+				);
+			} else {
+				for (const entry of entries) {
+					await this.fileService.writeFile(
+						path.join(destination, entry.path),
+						entry.content,
+					);
+				}
+			}
+
+			installLogger.info("commands exported: {path} ({count} commands)", {
+				path: destination,
+				count: entries.length,
+			});
+			return { path: destination, format, commandCount: entries.length };
+		} catch (error) {
+			if (error instanceof InstallationError) {
+				throw error;
+			}
+
+			throw new InstallationError(
+				`Failed to export commands: ${error instanceof Error ? error.message : String(error)}`,
+				"export",
+				undefined,
+				error instanceof Error ? error : undefined,
+			);
+		}
+	}
+
 	async listInstalledCommands(
 		options?: CommandServiceOptions,
 	): Promise<readonly Command[]> {
 		try {
 			// Use LocalCommandRepository for sophisticated local command discovery
 			// This provides proper namespace support and consistent metadata extraction
-			const manifest = await this.localCommandRepository.getManifest("en", {
-				forceRefresh: options?.forceRefresh,
-			});
+			const manifest = await this.localCommandRepository.getManifest(
+				options?.language ?? "en",
+				{ forceRefresh: options?.forceRefresh },
+			);
 
 			return manifest.commands;
 		} catch (error) {
@@ -261,7 +860,7 @@ export class InstallationService implements IInstallationService {
 					const info = await this.getInstallationInfoFromPath(
 						commandName,
 						filePath,
-						dir.type as "personal" | "project",
+						dir,
 					);
 					if (info && info.installedAt > mostRecentTime) {
 						mostRecentInfo = info;
@@ -307,76 +906,113 @@ export class InstallationService implements IInstallationService {
 	}
 
 	/**
-	 * Validates command name to prevent path traversal attacks
-	 * @param commandName Command name to validate
-	 * @throws InstallationError if command name is invalid
+	 * Find the installed file of a command, personal directory first
+	 * @param commandName Command name (may include namespace)
+	 * @param location Only look in this location
+	 * @returns File path and location, or null if not installed
+	 * @throws InstallationError if the command name is invalid
 	 */
-	private validateCommandName(commandName: string): void {
-		if (!commandName || commandName.trim() === "") {
-			throw new InstallationError(
-				"Command name cannot be empty",
-				"validation",
-				commandName,
-			);
-		}
+	async findInstalledCommand(
+		commandName: string,
+		location?: "personal" | "project",
+	): Promise<InstalledCommandLocation | null> {
+		const directories = await this.directoryDetector.getClaudeDirectories();
 
-		// Check for dangerous path segments that could enable directory traversal
-		const unsafe = /(^\\.{1,2}$|[\\\\\\/]{2,}|^\\s*$)/;
-		const segments = commandName.split(/[:/]/);
+		for (const dir of directories) {
+			if (!dir.exists || (location && dir.type !== location)) continue;
 
-		if (segments.some((segment) => unsafe.test(segment))) {
-			throw new InstallationError(
-				`Invalid command name '${commandName}': contains dangerous path segments`,
-				"validation",
-				commandName,
-			);
+			const filePath = this.buildCommandPath(commandName, dir.path);
+			if (await this.fileService.exists(filePath)) {
+				return {
+					filePath,
+					location: dir.type,
+					...(dir.projectRoot ? { projectRoot: dir.projectRoot } : {}),
+				};
+			}
 		}
 
-		// Additional validation: reject absolute paths
-		if (path.isAbsolute(commandName)) {
-			throw new InstallationError(
-				`Invalid command name '${commandName}': absolute paths not allowed`,
-				"validation",
-				commandName,
-			);
-		}
+		return null;
 	}
 
 	/**
 	 * Builds a safe file path for a command in a given directory
-	 * Consolidates namespace parsing and path construction logic
 	 * @param commandName Command name (may include namespace)
 	 * @param baseDir Base directory path
 	 * @returns Safe file path for the command
+	 * @throws InstallationError if command name is invalid
 	 */
 	private buildCommandPath(commandName: string, baseDir: string): string {
-		this.validateCommandName(commandName);
-
-		let filePath: string;
-		if (commandName.includes(":") || commandName.includes("/")) {
-			// Handle namespaced commands
-			const parts = commandName.split(/[:/]/);
-			const actualCommandName = parts.pop() || commandName;
-			const namespacePath = parts.join("/");
-			filePath = namespacePath
-				? path.join(baseDir, namespacePath, `${actualCommandName}.md`)
-				: path.join(baseDir, `${actualCommandName}.md`);
-		} else {
-			filePath = path.join(baseDir, `${commandName}.md`);
+		try {
+			return constructCommandPath(commandName, baseDir);
+		} catch (error) {
+			if (error instanceof UnsafeCommandNameError) {
+				throw new InstallationError(
+					error.message,
+					"validation",
+					commandName,
+					error,
+				);
+			}
+			throw error;
 		}
+	}
 
-		// Security check: ensure result is within the base directory
-		const resolvedBase = path.resolve(baseDir);
-		const resolvedFile = path.resolve(filePath);
-		if (!resolvedFile.startsWith(resolvedBase)) {
+	/**
+	 * Refuse paths that leave the commands directory through a link
+	 *
+	 * buildCommandPath() only checks the path as written. A namespace
+	 * directory that is a symlink or a Windows junction can still point
+	 * elsewhere, so both sides are compared with links resolved. The commands
+	 * directory itself may be a link (e.g., into a dotfiles repository).
+	 *
+	 * @param filePath Command file about to be written
+	 * @param baseDir Commands directory the file belongs to
+	 * @param commandName Command being written, for the error message
+	 * @throws InstallationError if the resolved file is outside baseDir
+	 */
+	private async assertNoLinkEscape(
+		filePath: string,
+		baseDir: string,
+		commandName: string,
+	): Promise<void> {
+		const [realFile, realBase] = await Promise.all([
+			this.fileService.realPath(filePath),
+			this.fileService.realPath(baseDir),
+		]);
+		if (!isInsideDirectory(realFile, realBase)) {
 			throw new InstallationError(
-				`Invalid command name '${commandName}': path escapes base directory`,
+				`Refusing to write '${commandName}': ${filePath} resolves to ${realFile}, outside ${baseDir}`,
 				"validation",
 				commandName,
 			);
 		}
+	}
 
-		return filePath;
+	/**
+	 * Removes namespace directories left empty by deleting a command file
+	 * @param filePath Path of the deleted command file
+	 */
+	private async removeEmptyNamespaceDirectories(
+		filePath: string,
+	): Promise<void> {
+		const directories = await this.directoryDetector.getClaudeDirectories();
+		const baseDir = directories.find(
+			(dir) => isInsideDirectory(filePath, dir.path),
+		)?.path;
+		if (!baseDir) {
+			return;
+		}
+
+		const removed = await removeEmptyParentDirectories(
+			this.fileService,
+			filePath,
+			baseDir,
+		);
+		if (removed.length > 0) {
+			installLogger.debug("removed empty namespace directories: {removed}", {
+				removed,
+			});
+		}
 	}
 
 	/**
@@ -391,11 +1027,70 @@ export class InstallationService implements IInstallationService {
 		}
 	}
 
+	/**
+	 * Change command files and their lockfile entries together
+	 *
+	 * Every path is captured for undo first, and the changes are applied as
+	 * one journaled transaction (see TransactionJournal), so an interrupted
+	 * run can be recovered instead of leaving a file without its lockfile
+	 * entry. Without a journal the changes are applied in order.
+	 *
+	 * @param name - What is being done, shown during recovery
+	 * @param files - Command file writes and deletions, in order
+	 * @param lockChanges - Lockfile entries to record or forget
+	 */
+	private async applyChanges(
+		name: string,
+		files: readonly JournalOperation[],
+		lockChanges: readonly LockChange[],
+	): Promise<void> {
+		await this.lockfile.transact(lockChanges, async (lockOperations) => {
+			const operations = [...files, ...lockOperations];
+			for (const operation of operations) {
+				await this.history?.capture(operation.path);
+			}
+			if (this.journal) {
+				await this.journal.run(name, operations);
+				return;
+			}
+			for (const operation of operations) {
+				await applyJournalOperation(this.fileService, operation);
+			}
+		});
+	}
+
+	/**
+	 * Get the location (personal or project) of an installed command file
+	 * @param filePath Path of an installed command file
+	 */
+	private async locationOf(
+		filePath: string,
+	): Promise<"personal" | "project"> {
+		const personalDir = await this.directoryDetector.getPersonalDirectory();
+		return isInsideDirectory(filePath, personalDir) ? "personal" : "project";
+	}
+
+	/**
+	 * Get the commands directory (personal or project) containing a file
+	 * @param filePath Path of an installed command file
+	 */
+	private async commandsDirOf(filePath: string): Promise<string> {
+		// A project enclosing the current one has its own directory and lockfile
+		const directories = await this.directoryDetector.getClaudeDirectories();
+		return (
+			directories.find((dir) => isInsideDirectory(filePath, dir.path))?.path ??
+			this.directoryDetector.getPreferredInstallLocation(
+				await this.locationOf(filePath),
+			)
+		);
+	}
+
 	private async getInstallationInfoFromPath(
 		commandName: string,
 		filePath: string,
-		locationType: "personal" | "project",
+		dir: DirectoryInfo,
 	): Promise<InstallationInfo | null> {
+		const locationType = dir.type;
 		try {
 			// Get file stats
 			const content = await this.fileService.readFile(filePath);
@@ -405,15 +1100,29 @@ export class InstallationService implements IInstallationService {
 			const cacheKey = `${commandName}#${locationType}`;
 			const cachedMetadata = this.installationMetadataCache.get(cacheKey);
 
-			// Determine source - if we have cache info, use it; otherwise, assume local
-			const source = cachedMetadata ? cachedMetadata.source : "local";
-			const version = cachedMetadata?.version;
-			const installedAt = cachedMetadata?.installedAt || new Date(); // Fallback for existing files
+			// Commands installed by earlier runs are known from the lockfile, or
+			// from provenance fields when the lockfile was lost or not copied
+			const lockEntry = await this.lockfile.get(dir.path, commandName);
+			const provenance = lockEntry ? undefined : readProvenance(content);
+
+			// Determine source - if we have install info, use it; otherwise, assume local
+			const source = cachedMetadata
+				? cachedMetadata.source
+				: lockEntry || provenance
+					? "repository"
+					: "local";
+			const version =
+				cachedMetadata?.version ?? lockEntry?.version ?? provenance?.version;
+			const installedAt =
+				cachedMetadata?.installedAt ||
+				(lockEntry && new Date(lockEntry.installedAt)) ||
+				(provenance && new Date(provenance.installedAt)) ||
+				new Date(this.clock.now()); // Fallback for existing files
 
 			// Build metadata object
 			const metadata = cachedMetadata?.metadata || {
-				language: "en",
-				repositoryVersion: undefined,
+				language: lockEntry?.language ?? "en",
+				repositoryVersion: lockEntry?.version ?? provenance?.version,
 				installationOptions: undefined,
 			};
 
@@ -421,10 +1130,13 @@ export class InstallationService implements IInstallationService {
 				name: commandName,
 				filePath,
 				location: locationType,
+				...(dir.projectRoot ? { projectRoot: dir.projectRoot } : {}),
 				installedAt,
 				size,
 				source,
 				version,
+				pinned: lockEntry?.pinned ?? false,
+				sha256: lockEntry?.sha256 ?? provenance?.sha256,
 				metadata,
 			};
 		} catch (_error) {
@@ -437,12 +1149,16 @@ export class InstallationService implements IInstallationService {
 	 *
 	 * Scans all Claude directories and returns comprehensive metadata for every
 	 * installed command. Commands existing in multiple locations are included
-	 * separately with their respective location metadata.
+	 * separately with their respective location metadata. Results are sorted by
+	 * name, then location.
 	 *
+	 * @param language - Language whose collation orders names (default: byte order)
 	 * @returns Promise resolving to array of installation info objects
 	 * @throws InstallationError if scanning fails
 	 */
-	async getAllInstallationInfo(): Promise<InstallationInfo[]> {
+	async getAllInstallationInfo(
+		language?: string,
+	): Promise<InstallationInfo[]> {
 		try {
 			// Get all installed commands first
 			const commands = await this.listInstalledCommands();
@@ -462,7 +1178,7 @@ export class InstallationService implements IInstallationService {
 						const info = await this.getInstallationInfoFromPath(
 							command.name,
 							filePath,
-							dir.type as "personal" | "project",
+							dir,
 						);
 						if (info) {
 							installationInfos.push(info);
@@ -471,7 +1187,11 @@ export class InstallationService implements IInstallationService {
 				}
 			}
 
-			return installationInfos;
+			return sortByOrderingKey(
+				installationInfos,
+				(info) => ({ name: info.name, source: info.location }),
+				language,
+			);
 		} catch (error) {
 			throw new InstallationError(
 				`Failed to get all installation info: ${error instanceof Error ? error.message : String(error)}`,
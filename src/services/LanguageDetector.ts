@@ -1,3 +1,12 @@
+import type {
+	LanguageResolution,
+	LanguageSource,
+} from "../interfaces/IConfigService.js";
+import {
+	isValidLanguageCode,
+	normalizeLanguageCode,
+} from "../utils/naming.js";
+
 /**
  * DetectionContext contains all the language detection sources in precedence order.
  * Each field represents a different source of language information, with empty strings
@@ -37,40 +46,69 @@ export class InvalidLanguageCodeError extends Error {
  * the user's preferred language for command retrieval and display.
  */
 export class LanguageDetector {
+	private cliFlag = "";
+
+	/**
+	 * SetCliFlag records the global --language flag so every detection in this
+	 * process honors it, including callers that pass an empty context.cliFlag.
+	 */
+	setCliFlag(code: string): void {
+		this.cliFlag = code;
+	}
+
+	/**
+	 * GetCliFlag returns the global --language flag, or an empty string if unset.
+	 */
+	getCliFlag(): string {
+		return this.cliFlag;
+	}
+
 	/**
 	 * Detect determines the language to use based on the detection context,
 	 * following the precedence order: CLI flag → env var → project config → user config → POSIX locale → fallback.
 	 */
 	detect(context: DetectionContext): string {
+		return this.resolve(context).language;
+	}
+
+	/**
+	 * Resolve works like detect but also reports which source won and the raw
+	 * value of every source, for explaining the choice to users.
+	 */
+	resolve(context: DetectionContext): LanguageResolution {
+		const sources: LanguageResolution["sources"] = {
+			flag: context.cliFlag || this.cliFlag,
+			env: context.envVar,
+			project: context.projectConfig ?? "",
+			user: context.userConfig ?? "",
+			locale: context.posixLocale,
+		};
+
 		// Process string-based sources in precedence order
-		const stringSources = [
-			context.cliFlag,
-			context.envVar,
-			context.projectConfig ?? "",
-			context.userConfig ?? "",
-		];
+		const stringSources: Array<Exclude<LanguageSource, "default" | "locale">> =
+			["flag", "env", "project", "user"];
 
 		for (const source of stringSources) {
-			if (source !== "") {
-				const normalized = this.sanitizeLanguageCode(source);
+			if (sources[source] !== "") {
+				const normalized = this.sanitizeLanguageCode(sources[source]);
 				if (normalized !== "") {
-					return normalized;
+					return { language: normalized, source, sources };
 				}
 			}
 		}
 
 		// 5. POSIX locale - system-level language preference (requires special parsing)
-		if (context.posixLocale !== "") {
+		if (sources.locale !== "") {
 			try {
-				const lang = this.parseLocale(context.posixLocale);
-				return lang;
+				const lang = this.parseLocale(sources.locale);
+				return { language: lang, source: "locale", sources };
 			} catch {
 				// Ignore parsing errors and continue to fallback
 			}
 		}
 
 		// 6. Fallback to English when no language source is available
-		return "en";
+		return { language: "en", source: "default", sources };
 	}
 
 	/**
@@ -137,15 +175,7 @@ export class LanguageDetector {
 			return "";
 		}
 
-		// Convert to lowercase and trim whitespace for consistency
-		const normalized = code.toLowerCase().trim();
-
-		// Validate language code format (2-3 lowercase letters)
-		if (!this.isValidLanguageCode(normalized)) {
-			return "";
-		}
-
-		return normalized;
+		return normalizeLanguageCode(code) ?? "";
 	}
 
 	/**
@@ -153,6 +183,6 @@ export class LanguageDetector {
 	 * Valid language codes are 2-3 lowercase letters only.
 	 */
 	isValidLanguageCode(code: string): boolean {
-		return /^[a-z]{2,3}$/.test(code);
+		return isValidLanguageCode(code);
 	}
 }
@@ -0,0 +1,119 @@
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type { Manifest, RepositoryOptions } from "../types/Command.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+} from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { repoLogger } from "../utils/logger.js";
+
+/**
+ * Language served when a translation is missing
+ */
+export const FALLBACK_LANGUAGE = "en";
+
+/**
+ * Repository that serves English content for untranslated languages
+ *
+ * Partially translated repositories often lack a language's manifest or some
+ * of its command files. Instead of failing, the English version is returned
+ * with a notice. Only "does not exist" answers fall back; network failures
+ * and other errors are reported as they are, and if English is missing too
+ * the original error is rethrown.
+ *
+ * Setting strictLanguage in the configuration disables the fallback.
+ */
+export class LanguageFallbackRepository implements IRepository {
+	private readonly noticed = new Set<string>();
+
+	/**
+	 * @param repository - Repository to read from
+	 * @param isStrict - Whether the fallback is disabled; called on each miss
+	 */
+	constructor(
+		private readonly repository: IRepository,
+		private readonly isStrict: () => Promise<boolean>,
+	) {}
+
+	async getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		try {
+			return await this.repository.getManifest(language, options);
+		} catch (error) {
+			if (!(error instanceof ManifestError && error.notFound)) {
+				throw error;
+			}
+			return this.tryFallback(error, language, "commands", () =>
+				this.repository.getManifest(FALLBACK_LANGUAGE, options),
+			);
+		}
+	}
+
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		try {
+			return await this.repository.getCommand(commandName, language, options);
+		} catch (error) {
+			if (
+				!(
+					error instanceof CommandNotFoundError ||
+					(error instanceof ManifestError && error.notFound) ||
+					(error instanceof CommandContentError && error.notFound)
+				)
+			) {
+				throw error;
+			}
+			return this.tryFallback(error, language, commandName, () =>
+				this.repository.getCommand(commandName, FALLBACK_LANGUAGE, options),
+			);
+		}
+	}
+
+	getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return this.repository.getAvailableLanguages();
+	}
+
+	getAbout(language: string): Promise<RepositoryAbout | null> {
+		return this.repository.getAbout(language);
+	}
+
+	/**
+	 * Fetch the English version, rethrowing the original error if the
+	 * fallback is disabled or cannot help
+	 */
+	private async tryFallback<T>(
+		error: Error,
+		language: string,
+		subject: string,
+		fetch: () => Promise<T>,
+	): Promise<T> {
+		if (language === FALLBACK_LANGUAGE || (await this.isStrict())) {
+			throw error;
+		}
+
+		let result: T;
+		try {
+			result = await fetch();
+		} catch {
+			throw error;
+		}
+
+		// One notice per language and subject is enough for a run
+		const key = `${language}:${subject}`;
+		if (!this.noticed.has(key)) {
+			this.noticed.add(key);
+			repoLogger.warn(
+				"no {language} version of {subject}, using English instead (set strictLanguage to disable)",
+				{ language, subject },
+			);
+		}
+		return result;
+	}
+}
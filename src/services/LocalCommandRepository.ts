@@ -1,10 +1,14 @@
 import path from "node:path";
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
 import type IRepository from "../interfaces/IRepository.js";
 import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
 import type { Command, Manifest, RepositoryOptions } from "../types/Command.js";
 import { CommandNotFoundError } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { repoLogger } from "../utils/logger.js";
+import { sortCommands } from "../utils/ordering.js";
+import { isInsideDirectory } from "../utils/paths.js";
 import type { CommandParser } from "./CommandParser.js";
-import type { DirectoryDetector } from "./DirectoryDetector.js";
 
 /**
  * Local command repository implementation
@@ -38,7 +42,7 @@ import type { DirectoryDetector } from "./DirectoryDetector.js";
  */
 export class LocalCommandRepository implements IRepository {
 	constructor(
-		private readonly directoryDetector: DirectoryDetector,
+		private readonly directoryDetector: IDirectoryDetector,
 		private readonly commandParser: CommandParser,
 	) {}
 
@@ -47,14 +51,15 @@ export class LocalCommandRepository implements IRepository {
 	 *
 	 * Scans both personal and project directories for command files, parses their metadata,
 	 * and creates a manifest. Personal directory commands take precedence over project
-	 * directory commands if there are naming conflicts.
+	 * directory commands if there are naming conflicts, and a project's commands over
+	 * those of the projects enclosing it.
 	 *
-	 * @param language - Language code (ignored for local commands)
+	 * @param language - Language whose collation orders the commands
 	 * @param options - Repository options (ignored for local commands)
 	 * @returns Promise resolving to the complete manifest of local commands
 	 */
 	async getManifest(
-		_language: string,
+		language: string,
 		_options?: RepositoryOptions,
 	): Promise<Manifest> {
 		try {
@@ -90,19 +95,30 @@ export class LocalCommandRepository implements IRepository {
 
 					commands.push(command);
 					processedNames.add(command.name);
-				} catch (_error) {}
+				} catch (error) {
+					repoLogger.debug(
+						"skipping unreadable local command: {filePath} ({error})",
+						{
+							filePath,
+							error: error instanceof Error ? error.message : String(error),
+						},
+					);
+				}
 			}
 
-			// Create manifest with current timestamp
+			// Create manifest with current timestamp, commands in canonical order
 			const manifest: Manifest = {
 				version: "1.0.0",
 				updated: new Date().toISOString(),
-				commands: commands,
+				commands: sortCommands(commands, language),
 			};
 
 			return manifest;
-		} catch (_error) {
+		} catch (error) {
 			// If directory scanning fails, return empty manifest
+			repoLogger.debug("local command scan failed: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
 			return {
 				version: "1.0.0",
 				updated: new Date().toISOString(),
@@ -187,27 +203,17 @@ export class LocalCommandRepository implements IRepository {
 	private async getRelativeCommandPath(absolutePath: string): Promise<string> {
 		try {
 			const personalDir = await this.directoryDetector.getPersonalDirectory();
-			const projectDir =
-				await this.directoryDetector.getProjectDirectory(false); // Use relative path for consistency
-
-			// Check if path is in personal directory
-			if (absolutePath.startsWith(personalDir)) {
-				const relativePath = path.relative(personalDir, absolutePath);
-				return relativePath;
-			}
-
-			// Check if path is in project directory
-			// For project directory, we need to handle both absolute and relative paths
-			const absoluteProjectDir = path.resolve(projectDir);
-			if (
-				absolutePath.startsWith(absoluteProjectDir) ||
-				absolutePath.startsWith(projectDir)
-			) {
-				const baseDir = absolutePath.startsWith(absoluteProjectDir)
-					? absoluteProjectDir
-					: projectDir;
-				const relativePath = path.relative(baseDir, absolutePath);
-				return relativePath;
+			const projectDirs =
+				await this.directoryDetector.getProjectDirectories(false); // Use relative paths for consistency
+
+			// Relative and absolute paths both resolve against the current directory
+			for (const baseDir of [personalDir, ...projectDirs]) {
+				if (isInsideDirectory(absolutePath, baseDir)) {
+					return path.relative(
+						path.resolve(baseDir),
+						path.resolve(absolutePath),
+					);
+				}
 			}
 
 			// Fallback - extract relative path from filename if path doesn't match expected directories
@@ -233,4 +239,11 @@ export class LocalCommandRepository implements IRepository {
 			},
 		];
 	}
+
+	/**
+	 * Local commands are not a published catalog, so there is no metadata
+	 */
+	async getAbout(_language: string): Promise<RepositoryAbout | null> {
+		return null;
+	}
 }
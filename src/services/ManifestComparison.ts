@@ -7,6 +7,7 @@ import type {
 	Manifest,
 	ManifestComparisonResult,
 } from "../types/index.js";
+import { compareStrings } from "../utils/ordering.js";
 
 /**
  * Service for comparing manifests and detecting changes between versions
@@ -63,6 +64,11 @@ export class ManifestComparison implements IManifestComparison {
 			}
 		}
 
+		// Sort changes by name, then change type, for stable output
+		changes.sort(
+			(a, b) => compareStrings(a.name, b.name) || compareStrings(a.type, b.type),
+		);
+
 		// Generate summary statistics
 		const summary = this.generateChangeSummary(changes);
 
@@ -0,0 +1,371 @@
+import type { Manifest } from "../types/Command.js";
+import { ManifestError } from "../types/Command.js";
+import { migrateManifest, type RawManifest } from "./ManifestMigrations.js";
+
+/**
+ * Where the decoder is in the top-level manifest object
+ */
+type DecoderState =
+	| "start"
+	| "firstKey"
+	| "key"
+	| "colon"
+	| "value"
+	| "afterValue"
+	| "commands"
+	| "firstCommand"
+	| "command"
+	| "afterCommand"
+	| "done";
+
+/**
+ * Progress through a JSON value that may span several chunks
+ */
+interface ValueScan {
+	/** Offset of the value's first character in the pending text */
+	start: number;
+	/** Offset of the next character to look at */
+	index: number;
+	depth: number;
+	inString: boolean;
+	escaped: boolean;
+}
+
+/**
+ * Incremental decoder for manifest.json
+ *
+ * Takes the manifest in chunks as they arrive and decodes each entry of the
+ * "commands" array on its own as soon as it is complete, so neither the
+ * whole body nor a parse tree of it is ever held: besides the decoded
+ * manifest, memory is one chunk and the entry being read. Other top-level
+ * fields are decoded the same way. The result goes through migrateManifest()
+ * like a manifest parsed whole.
+ *
+ * Errors are kept until end() rather than thrown by write(), so a caller can
+ * still read the rest of the body (to check its signature, say) first.
+ *
+ * @example
+ * ```typescript
+ * const decoder = new ManifestDecoder("en");
+ * for await (const chunk of response.body) {
+ *   decoder.write(chunk);
+ * }
+ * const manifest = decoder.end();
+ * ```
+ */
+export class ManifestDecoder {
+	private readonly text = new TextDecoder();
+	private state: DecoderState = "start";
+	/** Text received but not decoded yet */
+	private pending = "";
+	/** Offset of the next character to look at in pending */
+	private index = 0;
+	/** Characters already dropped from pending, for error positions */
+	private dropped = 0;
+	private scan?: ValueScan;
+	private key = "";
+	private readonly fields: Record<string, unknown> = {};
+	private commands: unknown[] = [];
+	private received = false;
+	private error?: ManifestError;
+
+	/**
+	 * @param language - Language code for error reporting
+	 */
+	constructor(private readonly language: string) {}
+
+	/**
+	 * Decode the next chunk of the manifest
+	 *
+	 * @param chunk - UTF-8 bytes, split anywhere
+	 */
+	write(chunk: Uint8Array): void {
+		if (this.error) {
+			return;
+		}
+		this.feed(this.text.decode(chunk, { stream: true }));
+	}
+
+	/**
+	 * Finish decoding once the whole body was written
+	 *
+	 * @returns Manifest in the current schema
+	 * @throws ManifestError if the body is empty, not valid JSON or has no
+	 *   commands array
+	 * @throws ManifestSchemaError if the schema version is invalid or too new
+	 */
+	end(): Manifest {
+		if (!this.error) {
+			this.feed(this.text.decode());
+		}
+		if (this.error) {
+			throw this.error;
+		}
+		if (!this.received) {
+			throw new ManifestError(
+				this.language,
+				"Empty response received from server",
+			);
+		}
+		if (this.state !== "done") {
+			throw this.invalid("Unexpected end of JSON input");
+		}
+		if (!Array.isArray(this.fields.commands)) {
+			throw new ManifestError(
+				this.language,
+				"Manifest does not contain valid commands array",
+			);
+		}
+		return migrateManifest(this.fields as RawManifest, this.language);
+	}
+
+	private feed(text: string): void {
+		this.pending += text;
+		try {
+			this.decode();
+		} catch (error) {
+			this.error =
+				error instanceof ManifestError
+					? error
+					: this.invalid(error instanceof Error ? error.message : error);
+		}
+
+		// Drop what has been decoded; only the value being read is kept
+		const keep = this.scan?.start ?? this.index;
+		this.pending = this.pending.slice(keep);
+		this.dropped += keep;
+		this.index -= keep;
+		if (this.scan) {
+			this.scan.index -= keep;
+			this.scan.start = 0;
+		}
+	}
+
+	/**
+	 * Advance through the pending text as far as it goes
+	 */
+	private decode(): void {
+		for (;;) {
+			if (this.scan) {
+				const value = this.readValue(this.scan);
+				if (value === undefined) {
+					return;
+				}
+				this.scan = undefined;
+				this.store(JSON.parse(value));
+				continue;
+			}
+
+			const char = this.nextToken();
+			if (char === undefined) {
+				return;
+			}
+			this.received = true;
+			this.step(char);
+		}
+	}
+
+	/**
+	 * Skip whitespace and return the next character, if any has arrived
+	 */
+	private nextToken(): string | undefined {
+		while (this.index < this.pending.length) {
+			const char = this.pending[this.index];
+			if (char !== " " && char !== "\t" && char !== "\n" && char !== "\r") {
+				return char;
+			}
+			this.index++;
+		}
+		return undefined;
+	}
+
+	/**
+	 * Handle the next structural character of the top-level object
+	 */
+	private step(char: string): void {
+		switch (this.state) {
+			case "start":
+				this.expect(char, "{");
+				this.state = "firstKey";
+				return;
+			case "firstKey":
+				if (char === "}") {
+					this.index++;
+					this.state = "done";
+					return;
+				}
+				this.startKey(char);
+				return;
+			case "key":
+				this.startKey(char);
+				return;
+			case "colon":
+				this.expect(char, ":");
+				this.state = this.key === "commands" ? "commands" : "value";
+				return;
+			case "commands":
+				if (char === "[") {
+					this.index++;
+					this.commands = [];
+					this.state = "firstCommand";
+					return;
+				}
+				// Not an array: keep the value so end() can report it
+				this.state = "value";
+				this.startValue();
+				return;
+			case "value":
+				this.startValue();
+				return;
+			case "afterValue":
+				this.index++;
+				if (char === ",") {
+					this.state = "key";
+				} else if (char === "}") {
+					this.state = "done";
+				} else {
+					throw this.unexpected(char);
+				}
+				return;
+			case "firstCommand":
+				if (char === "]") {
+					this.index++;
+					this.endCommands();
+					return;
+				}
+				this.startValue();
+				return;
+			case "command":
+				this.startValue();
+				return;
+			case "afterCommand":
+				this.index++;
+				if (char === ",") {
+					this.state = "command";
+				} else if (char === "]") {
+					this.endCommands();
+				} else {
+					throw this.unexpected(char);
+				}
+				return;
+			case "done":
+				throw this.unexpected(char);
+		}
+	}
+
+	private startKey(char: string): void {
+		if (char !== '"') {
+			throw this.unexpected(char);
+		}
+		this.startValue();
+	}
+
+	private startValue(): void {
+		this.scan = {
+			start: this.index,
+			index: this.index,
+			depth: 0,
+			inString: false,
+			escaped: false,
+		};
+	}
+
+	/**
+	 * Record a complete value according to where it appeared
+	 */
+	private store(value: unknown): void {
+		switch (this.state) {
+			case "firstKey":
+			case "key":
+				this.key = value as string;
+				this.state = "colon";
+				return;
+			case "value":
+				this.fields[this.key] = value;
+				this.state = "afterValue";
+				return;
+			default:
+				this.commands.push(value);
+				this.state = "afterCommand";
+		}
+	}
+
+	private endCommands(): void {
+		this.fields.commands = this.commands;
+		this.commands = [];
+		this.state = "afterValue";
+	}
+
+	/**
+	 * Continue scanning a value
+	 *
+	 * @returns The value's text once complete, undefined if more input is
+	 *   needed
+	 */
+	private readValue(scan: ValueScan): string | undefined {
+		const text = this.pending;
+		for (; scan.index < text.length; scan.index++) {
+			const char = text[scan.index];
+			if (scan.inString) {
+				if (scan.escaped) {
+					scan.escaped = false;
+				} else if (char === "\\") {
+					scan.escaped = true;
+				} else if (char === '"') {
+					scan.inString = false;
+					if (scan.depth === 0) {
+						return this.takeValue(scan, scan.index + 1);
+					}
+				}
+			} else if (char === '"') {
+				scan.inString = true;
+			} else if (char === "{" || char === "[") {
+				scan.depth++;
+			} else if (char === "}" || char === "]") {
+				if (scan.depth === 0) {
+					// End of a number, literal or of the enclosing container
+					return this.takeValue(scan, scan.index);
+				}
+				scan.depth--;
+				if (scan.depth === 0) {
+					return this.takeValue(scan, scan.index + 1);
+				}
+			} else if (
+				scan.depth === 0 &&
+				(char === "," ||
+					char === " " ||
+					char === "\t" ||
+					char === "\n" ||
+					char === "\r")
+			) {
+				return this.takeValue(scan, scan.index);
+			}
+		}
+		return undefined;
+	}
+
+	private takeValue(scan: ValueScan, end: number): string {
+		this.index = end;
+		return this.pending.slice(scan.start, end);
+	}
+
+	private expect(char: string, expected: string): void {
+		if (char !== expected) {
+			throw this.unexpected(char);
+		}
+		this.index++;
+	}
+
+	private unexpected(char: string): ManifestError {
+		return this.invalid(
+			`Unexpected token '${char}' at position ${this.dropped + this.index}`,
+		);
+	}
+
+	private invalid(reason: unknown): ManifestError {
+		return new ManifestError(
+			this.language,
+			`Invalid JSON format received from server: ${reason}`,
+		);
+	}
+}
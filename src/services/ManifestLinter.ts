@@ -0,0 +1,388 @@
+import { dirname, join } from "node:path";
+import matter from "gray-matter";
+import type IFileService from "../interfaces/IFileService.js";
+import type { Command, Manifest } from "../types/Command.js";
+import { ManifestError } from "../types/Command.js";
+import { isAllowedTool } from "../utils/allowedTools.js";
+import {
+	argumentHintProblem,
+	normalizeArgumentHint,
+} from "../utils/argumentHint.js";
+import { normalizeRequires } from "../utils/dependencies.js";
+import {
+	parseExamples,
+	parseManifestExamples,
+} from "../utils/examples.js";
+import { isValidCommandName, isValidFileName } from "../utils/naming.js";
+import { compareStrings } from "../utils/ordering.js";
+import ManifestParser from "./ManifestParser.js";
+
+/**
+ * How serious a lint finding is; only errors fail the lint
+ */
+export type LintSeverity = "error" | "warning";
+
+/**
+ * Checks the linter runs
+ *
+ * - schema: the manifest is not valid JSON or does not match the schema
+ * - invalid-name: a command name is not a valid (namespaced) name
+ * - duplicate-name: several commands share a name
+ * - dangling-reference: a file path is absolute or leaves the files directory
+ * - missing-file: a referenced file does not exist
+ * - duplicate-file: several commands share a file
+ * - unreferenced-file: a .md file no command refers to
+ * - allowed-tools: an empty or non-whitelisted allowed-tools entry
+ * - unknown-requirement: "requires" names a command not in the manifest
+ * - argument-hint: a malformed argument-hint in the manifest or a command file
+ * - examples: an `examples` entry of the manifest or a command file cannot be
+ *   run as shown
+ */
+export type LintRule =
+	| "schema"
+	| "invalid-name"
+	| "duplicate-name"
+	| "dangling-reference"
+	| "missing-file"
+	| "duplicate-file"
+	| "unreferenced-file"
+	| "allowed-tools"
+	| "unknown-requirement"
+	| "argument-hint"
+	| "examples";
+
+/**
+ * One lint finding
+ */
+export interface LintIssue {
+	readonly severity: LintSeverity;
+	readonly rule: LintRule;
+	readonly message: string;
+	/** Command the finding is about, if any */
+	readonly command?: string;
+}
+
+/**
+ * Outcome of linting one manifest
+ */
+export interface ManifestLintResult {
+	/** Number of commands, or 0 when the manifest did not parse */
+	readonly commandCount: number;
+	/** Findings, errors first */
+	readonly issues: readonly LintIssue[];
+}
+
+/**
+ * Lints a repository manifest against the command files next to it
+ *
+ * Schema validation is the same ManifestParser the client uses, so a manifest
+ * that lints clean is one the client accepts. On top of it the linter checks
+ * what the parser cannot see from one file: whether names are unique, whether
+ * every referenced file exists, and whether every command file is listed.
+ */
+export class ManifestLinter {
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly parser: ManifestParser = new ManifestParser(),
+	) {}
+
+	/**
+	 * Lint a manifest file
+	 *
+	 * @param manifestPath - Path to the manifest (e.g., index.json)
+	 * @param filesDir - Directory command files are relative to (defaults to
+	 *   the manifest's directory)
+	 * @returns Findings; the manifest passes when none is an error
+	 * @throws FileNotFoundError if the manifest does not exist
+	 */
+	async lint(
+		manifestPath: string,
+		filesDir: string = dirname(manifestPath),
+	): Promise<ManifestLintResult> {
+		const content = await this.fileService.readFile(manifestPath);
+
+		let manifest: Manifest;
+		try {
+			// "en" keeps the parser's message free of a language prefix
+			manifest = this.parser.parseManifest(content, "en");
+		} catch (error) {
+			const message =
+				error instanceof ManifestError
+					? (error.cause ?? error.message)
+					: error instanceof Error
+						? error.message
+						: String(error);
+			return {
+				commandCount: 0,
+				issues: [{ severity: "error", rule: "schema", message }],
+			};
+		}
+
+		// The parser normalizes hints and examples, so check them as written
+		const raw = rawCommands(content);
+		const issues = [
+			...this.checkNames(manifest.commands),
+			...(await this.checkFiles(manifest.commands, filesDir)),
+			...manifest.commands.flatMap((command) => this.checkTools(command)),
+			...this.checkRequirements(manifest.commands),
+			...manifest.commands.flatMap((command, index) => [
+				...this.checkArgumentHint(command, raw[index]?.["argument-hint"]),
+				...this.checkExamples(
+					command,
+					parseManifestExamples,
+					raw[index]?.examples,
+				),
+			]),
+			...(await this.checkCommandFiles(manifest.commands, filesDir)),
+		];
+
+		return {
+			commandCount: manifest.commands.length,
+			issues: issues.sort(
+				(a, b) =>
+					compareStrings(a.severity, b.severity) ||
+					compareStrings(a.command ?? "", b.command ?? ""),
+			),
+		};
+	}
+
+	/**
+	 * Report invalid and duplicate command names
+	 */
+	private checkNames(commands: readonly Command[]): LintIssue[] {
+		const issues: LintIssue[] = [];
+		const seen = new Set<string>();
+		for (const command of commands) {
+			if (!isValidCommandName(command.name)) {
+				issues.push({
+					severity: "error",
+					rule: "invalid-name",
+					message: `'${command.name}' is not a valid command name`,
+					command: command.name,
+				});
+			}
+			if (seen.has(command.name)) {
+				issues.push({
+					severity: "error",
+					rule: "duplicate-name",
+					message: `${command.name} is listed more than once`,
+					command: command.name,
+				});
+			}
+			seen.add(command.name);
+		}
+		return issues;
+	}
+
+	/**
+	 * Report file references that cannot resolve and files nobody references
+	 */
+	private async checkFiles(
+		commands: readonly Command[],
+		filesDir: string,
+	): Promise<LintIssue[]> {
+		const issues: LintIssue[] = [];
+		const referenced = new Map<string, string>();
+		for (const command of commands) {
+			const file = command.file.replace(/\\/g, "/");
+			if (!isValidFileName(file)) {
+				issues.push({
+					severity: "error",
+					rule: "dangling-reference",
+					message: `file '${command.file}' points outside the files directory`,
+					command: command.name,
+				});
+				continue;
+			}
+
+			const owner = referenced.get(file);
+			if (owner !== undefined) {
+				issues.push({
+					severity: "warning",
+					rule: "duplicate-file",
+					message: `${file} is also the file of ${owner}`,
+					command: command.name,
+				});
+			} else {
+				referenced.set(file, command.name);
+			}
+
+			if (!(await this.fileService.exists(join(filesDir, file)))) {
+				issues.push({
+					severity: "error",
+					rule: "missing-file",
+					message: `${file} does not exist in ${filesDir}`,
+					command: command.name,
+				});
+			}
+		}
+
+		if (!(await this.fileService.exists(filesDir))) {
+			return issues;
+		}
+		for (const file of await this.fileService.listFilesRecursive(filesDir)) {
+			const relativePath = file.replace(/\\/g, "/");
+			if (relativePath.endsWith(".md") && !referenced.has(relativePath)) {
+				issues.push({
+					severity: "warning",
+					rule: "unreferenced-file",
+					message: `${relativePath} is not listed in the manifest`,
+				});
+			}
+		}
+		return issues;
+	}
+
+	/**
+	 * Report empty and non-whitelisted allowed-tools entries
+	 */
+	private checkTools(command: Command): LintIssue[] {
+		const value = command["allowed-tools"];
+		const tools = (typeof value === "string" ? value.split(",") : value).map(
+			(tool) => tool.trim(),
+		);
+
+		const issues: LintIssue[] = [];
+		if (tools.includes("") && value !== "") {
+			issues.push({
+				severity: "error",
+				rule: "allowed-tools",
+				message: "allowed-tools has an empty entry",
+				command: command.name,
+			});
+		}
+		for (const tool of tools) {
+			if (tool !== "" && !isAllowedTool(tool)) {
+				issues.push({
+					severity: "error",
+					rule: "allowed-tools",
+					message: `allowed-tools entry '${tool}' is not an allowed tool`,
+					command: command.name,
+				});
+			}
+		}
+		return issues;
+	}
+
+	/**
+	 * Report requirements on commands the manifest does not contain
+	 */
+	private checkRequirements(commands: readonly Command[]): LintIssue[] {
+		const names = new Set(commands.map((command) => command.name));
+		return commands.flatMap((command) =>
+			normalizeRequires(command.requires)
+				.filter((required) => !names.has(required))
+				.map(
+					(required): LintIssue => ({
+						severity: "warning",
+						rule: "unknown-requirement",
+						message: `requires ${required}, which is not in the manifest`,
+						command: command.name,
+					}),
+				),
+		);
+	}
+
+	/**
+	 * Report a malformed argument-hint
+	 *
+	 * @param hint - The hint of the command, from the manifest or its file
+	 * @param fileName - The command file the hint is from, if any
+	 */
+	private checkArgumentHint(
+		command: Command,
+		hint: unknown,
+		fileName?: string,
+	): LintIssue[] {
+		if (hint === undefined) {
+			return [];
+		}
+		const normalized = normalizeArgumentHint(hint);
+		const problem =
+			normalized === undefined
+				? "argument-hint must be a non-empty string"
+				: argumentHintProblem(normalized);
+		return problem
+			? [
+					{
+						severity: "warning",
+						rule: "argument-hint",
+						message: fileName ? `${fileName}: ${problem}` : problem,
+						command: command.name,
+					},
+				]
+			: [];
+	}
+
+	/**
+	 * Report examples that are left out when parsed
+	 *
+	 * @param parse - How the examples are parsed where they are from
+	 * @param examples - The examples of the command, from the manifest or its
+	 *   file
+	 */
+	private checkExamples(
+		command: Command,
+		parse: typeof parseExamples,
+		examples: unknown,
+	): LintIssue[] {
+		return parse(examples, command.name).problems.map(
+			(problem): LintIssue => ({
+				severity: "warning",
+				rule: "examples",
+				message: problem,
+				command: command.name,
+			}),
+		);
+	}
+
+	/**
+	 * Report frontmatter the client reads from command files but cannot use:
+	 * malformed argument-hints and examples that are left out when parsed
+	 *
+	 * Files that are missing or whose frontmatter does not parse are reported
+	 * elsewhere or by the client, so they are skipped here.
+	 */
+	private async checkCommandFiles(
+		commands: readonly Command[],
+		filesDir: string,
+	): Promise<LintIssue[]> {
+		const issues: LintIssue[] = [];
+		for (const command of commands) {
+			const file = command.file.replace(/\\/g, "/");
+			const filePath = join(filesDir, file);
+			if (
+				!isValidFileName(file) ||
+				!(await this.fileService.exists(filePath))
+			) {
+				continue;
+			}
+
+			let frontmatter: Record<string, unknown>;
+			try {
+				frontmatter = matter(await this.fileService.readFile(filePath)).data;
+			} catch {
+				continue;
+			}
+			issues.push(
+				...this.checkArgumentHint(
+					command,
+					frontmatter["argument-hint"],
+					file,
+				),
+			);
+			issues.push(
+				...this.checkExamples(command, parseExamples, frontmatter.examples),
+			);
+		}
+		return issues;
+	}
+}
+
+/**
+ * Read the commands of a manifest that parsed, as written
+ */
+function rawCommands(content: string): Record<string, unknown>[] {
+	return (JSON.parse(content) as { commands: Record<string, unknown>[] })
+		.commands;
+}
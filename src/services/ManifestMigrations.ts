@@ -0,0 +1,103 @@
+import type { Manifest } from "../types/Command.js";
+import { ManifestError } from "../types/Command.js";
+
+/**
+ * Manifest schema versioning
+ *
+ * Manifests declare the schema they follow in a top-level "schemaVersion"
+ * field. Older manifests are upgraded step by step to the current schema so
+ * the rest of the CLI only ever sees one shape; manifests newer than this
+ * release fail with a clear error instead of being misread.
+ *
+ * | Version | Changes                                                           |
+ * | ------- | ----------------------------------------------------------------- |
+ * | 1       | Original format without schemaVersion; allowed-tools may be a     |
+ * |         | comma-separated string                                            |
+ * | 2       | schemaVersion field; allowed-tools is always an array             |
+ */
+export const CURRENT_MANIFEST_SCHEMA = 2;
+
+/**
+ * Error thrown for manifests with an invalid or unsupported schema version
+ */
+export class ManifestSchemaError extends ManifestError {
+	constructor(
+		language: string,
+		public readonly schemaVersion: unknown,
+	) {
+		super(
+			language,
+			Number.isInteger(schemaVersion) &&
+				(schemaVersion as number) > CURRENT_MANIFEST_SCHEMA
+				? `Manifest schema version ${schemaVersion} is newer than supported version ${CURRENT_MANIFEST_SCHEMA}. Upgrade claude-cmd to use this repository.`
+				: `Invalid manifest schema version: ${JSON.stringify(schemaVersion)}`,
+		);
+	}
+}
+
+/**
+ * Parsed manifest JSON whose basic structure has been checked
+ */
+export type RawManifest = Record<string, unknown> & { commands: unknown[] };
+
+/**
+ * Migrations keyed by the schema version they upgrade from
+ */
+const MIGRATIONS: Record<number, (manifest: RawManifest) => RawManifest> = {
+	1: (manifest) => ({
+		...manifest,
+		commands: manifest.commands.map((command) => {
+			if (
+				!command ||
+				typeof command !== "object" ||
+				typeof (command as Record<string, unknown>)["allowed-tools"] !==
+					"string"
+			) {
+				return command;
+			}
+			const tools = (command as Record<string, string>)["allowed-tools"];
+			return {
+				...command,
+				"allowed-tools": tools
+					.split(",")
+					.map((tool) => tool.trim())
+					.filter((tool) => tool.length > 0),
+			};
+		}),
+	}),
+};
+
+/**
+ * Upgrade a manifest to the current schema version
+ *
+ * Expects the basic structure (an object with a commands array) to have been
+ * checked already. Unknown fields are preserved.
+ *
+ * @param manifest - Parsed manifest JSON
+ * @param language - Language code for error reporting
+ * @returns Manifest in the current schema, with schemaVersion set
+ * @throws ManifestSchemaError if the schema version is invalid or too new
+ */
+export function migrateManifest(
+	manifest: RawManifest,
+	language: string,
+): Manifest {
+	const declared = manifest.schemaVersion ?? 1;
+	if (
+		typeof declared !== "number" ||
+		!Number.isInteger(declared) ||
+		declared < 1 ||
+		declared > CURRENT_MANIFEST_SCHEMA
+	) {
+		throw new ManifestSchemaError(language, declared);
+	}
+
+	let migrated = manifest;
+	for (let version = declared; version < CURRENT_MANIFEST_SCHEMA; version++) {
+		migrated = MIGRATIONS[version]?.(migrated) ?? migrated;
+	}
+	return {
+		...migrated,
+		schemaVersion: CURRENT_MANIFEST_SCHEMA,
+	} as unknown as Manifest;
+}
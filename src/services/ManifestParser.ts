@@ -1,11 +1,14 @@
 import { z } from "zod";
 import type { Manifest } from "../types/Command.js";
 import { ManifestError } from "../types/Command.js";
+import { normalizeArgumentHint } from "../utils/argumentHint.js";
+import { parseManifestExamples } from "../utils/examples.js";
+import { migrateManifest, type RawManifest } from "./ManifestMigrations.js";
 
 /**
  * Zod schema for validating Command objects
  */
-const CommandSchema = z.object({
+const CommandFieldsSchema = z.object({
 	name: z.string({ message: "Invalid field type: name must be string" }),
 	description: z.string({
 		message: "Invalid field type: description must be string",
@@ -23,6 +26,40 @@ const CommandSchema = z.object({
 			message: "Invalid allowed-tools array: all elements must be strings",
 		},
 	),
+	category: z
+		.string({ message: "Invalid field type: category must be string" })
+		.optional(),
+	// Read like the frontmatter field; ManifestLinter reports malformed hints
+	"argument-hint": z.unknown().transform(normalizeArgumentHint).optional(),
+	updated: z
+		.string({ message: "Invalid field type: updated must be string" })
+		.optional(),
+	tags: z
+		.array(z.string(), { message: "Invalid field type: tags must be array" })
+		.optional(),
+	requires: z
+		.array(z.string(), {
+			message: "Invalid field type: requires must be array",
+		})
+		.optional(),
+	deprecated: z
+		.union([z.boolean(), z.string()], {
+			message: "Invalid field type: deprecated must be boolean or string",
+		})
+		.optional(),
+	replaced_by: z
+		.string({ message: "Invalid field type: replaced_by must be string" })
+		.optional(),
+	// Unusable examples are left out; ManifestLinter reports them
+	examples: z.unknown().optional(),
+});
+
+/**
+ * Command objects with their examples parsed
+ */
+const CommandSchema = CommandFieldsSchema.transform(({ examples, ...command }) => {
+	const parsed = parseManifestExamples(examples, command.name).examples;
+	return parsed.length > 0 ? { ...command, examples: parsed } : command;
 });
 
 /**
@@ -30,6 +67,7 @@ const CommandSchema = z.object({
  */
 const ManifestSchema = z.object({
 	version: z.string({ message: "Invalid field type: version must be string" }),
+	schemaVersion: z.number().optional(),
 	updated: z.string({ message: "Invalid field type: updated must be string" }),
 	commands: z.array(CommandSchema, {
 		message: "Invalid field type: commands must be array",
@@ -42,6 +80,9 @@ const ManifestSchema = z.object({
  * Handles parsing and validation of the manifest.json manifest format used by
  * the Claude command repository. Provides comprehensive validation and error
  * recovery for malformed manifest data using Zod schemas.
+ *
+ * Parses manifests held whole, as the linter reads them. Manifests fetched
+ * over HTTP are streamed through ManifestDecoder instead.
  */
 export default class ManifestParser {
 	/**
@@ -51,6 +92,7 @@ export default class ManifestParser {
 	 * @param language - Language code for error reporting
 	 * @returns Validated manifest object
 	 * @throws ManifestError for invalid JSON or validation failures
+	 * @throws ManifestSchemaError for manifests newer than this release supports
 	 */
 	parseManifest(jsonString: string, language: string): Manifest {
 		// 1. Parse JSON
@@ -73,7 +115,12 @@ export default class ManifestParser {
 			throw new ManifestError(language, "Invalid JSON format");
 		}
 
-		// 2. Validate with Zod schema
+		// 2. Upgrade older schema versions; newer ones are rejected
+		if (Array.isArray((rawData as { commands?: unknown }).commands)) {
+			rawData = migrateManifest(rawData as RawManifest, language);
+		}
+
+		// 3. Validate with Zod schema
 		const result = ManifestSchema.safeParse(rawData);
 
 		if (!result.success) {
@@ -92,7 +139,7 @@ export default class ManifestParser {
 			throw error;
 		}
 
-		// 3. Return validated manifest
+		// 4. Return validated manifest
 		return result.data;
 	}
 
@@ -0,0 +1,225 @@
+import type IRepository from "../interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../interfaces/IRepository.js";
+import type {
+	Command,
+	Manifest,
+	RepositoryOptions,
+} from "../types/Command.js";
+import { CommandNotFoundError } from "../types/Command.js";
+import type { RepositoryAbout } from "../types/Repository.js";
+import { mapConcurrent } from "../utils/concurrency.js";
+import { repoLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+
+/**
+ * Number of repository manifests fetched at the same time
+ */
+export const MANIFEST_FETCH_CONCURRENCY = 4;
+
+/**
+ * A repository of a MultiRepository with a name for messages
+ */
+export interface RepositorySource {
+	/** URL or other description of the source */
+	readonly name: string;
+	readonly repository: IRepository;
+	/** Namespace put before the names of its commands (e.g., "team") */
+	readonly prefix?: string;
+}
+
+/**
+ * Put a source's prefix before a command's name and the names it refers to
+ *
+ * Requirements and replacements naming commands of the same source are
+ * prefixed too, so they keep pointing at that source's commands.
+ *
+ * @param command - Command as the source lists it
+ * @param prefix - Namespace of the source
+ * @param local - Names of every command of the source
+ */
+function prefixCommand(
+	command: Command,
+	prefix: string,
+	local: ReadonlySet<string>,
+): Command {
+	const prefixed = (name: string) =>
+		local.has(name) ? `${prefix}:${name}` : name;
+	return {
+		...command,
+		name: `${prefix}:${command.name}`,
+		namespace: command.namespace ? `${prefix}:${command.namespace}` : prefix,
+		...(command.requires ? { requires: command.requires.map(prefixed) } : {}),
+		...(command.replaced_by
+			? { replaced_by: prefixed(command.replaced_by) }
+			: {}),
+	};
+}
+
+/**
+ * A source whose manifest could not be fetched
+ */
+interface SourceFailure {
+	readonly source: string;
+	readonly error: Error;
+}
+
+/**
+ * Repository combining the commands of several repositories
+ *
+ * Manifests are fetched concurrently (at most MANIFEST_FETCH_CONCURRENCY at
+ * a time) and merged in source order: when two sources have a command of the
+ * same name, the earlier source's wins, and every command records the name
+ * of the source it came from (Command.repository). Sources with a prefix
+ * list their commands as "{prefix}:{name}", so they never collide with the
+ * others'. A source that fails is
+ * logged and left out, so one unreachable repository never hides the others;
+ * only when every source fails is the first source's error thrown.
+ */
+export class MultiRepository implements IRepository {
+	/** Source index serving each command, by language */
+	private readonly owners = new Map<string, Map<string, number>>();
+
+	/**
+	 * @param sources - Repositories in priority order (at least one)
+	 * @param concurrency - Maximum number of manifests fetched at once
+	 */
+	constructor(
+		private readonly sources: readonly RepositorySource[],
+		private readonly concurrency = MANIFEST_FETCH_CONCURRENCY,
+	) {}
+
+	async getManifest(
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<Manifest> {
+		const results = await mapConcurrent(
+			this.sources,
+			this.concurrency,
+			async ({ repository }) => {
+				try {
+					return await repository.getManifest(language, options);
+				} catch (error) {
+					return error instanceof Error ? error : new Error(String(error));
+				}
+			},
+		);
+
+		const failures: SourceFailure[] = [];
+		const owners = new Map<string, number>();
+		const commands: Command[] = [];
+		let primary: Manifest | undefined;
+		for (const [index, result] of results.entries()) {
+			const source = this.sources[index]?.name ?? String(index);
+			if (result instanceof Error) {
+				failures.push({ source, error: result });
+				continue;
+			}
+			primary ??= result;
+			const prefix = this.sources[index]?.prefix;
+			const local = prefix
+				? new Set(result.commands.map((command) => command.name))
+				: new Set<string>();
+			for (const listed of result.commands) {
+				const command = prefix ? prefixCommand(listed, prefix, local) : listed;
+				if (!owners.has(command.name)) {
+					owners.set(command.name, index);
+					commands.push({ ...command, repository: source });
+				}
+			}
+		}
+
+		if (!primary) {
+			throw failures[0]?.error ?? new Error("No repositories configured");
+		}
+		for (const { source, error } of failures) {
+			repoLogger.warn(
+				"skipping repository {source} for {language}: {error}",
+				{ source, language, error: error.message },
+			);
+		}
+
+		this.owners.set(language, owners);
+		return { ...primary, commands };
+	}
+
+	/**
+	 * Fetch a command file from the source whose manifest lists it
+	 *
+	 * Before any manifest was fetched, sources are tried in order (prefixed
+	 * sources only for names carrying their prefix).
+	 */
+	async getCommand(
+		commandName: string,
+		language: string,
+		options?: RepositoryOptions,
+	): Promise<string> {
+		const owner = this.owners.get(language)?.get(commandName);
+		const source = owner === undefined ? undefined : this.sources[owner];
+		if (source) {
+			return source.repository.getCommand(
+				unprefixed(commandName, source.prefix) ?? commandName,
+				language,
+				options,
+			);
+		}
+
+		let firstError: unknown;
+		for (const { repository, prefix } of this.sources) {
+			const name = unprefixed(commandName, prefix);
+			if (name === undefined) {
+				continue;
+			}
+			try {
+				return await repository.getCommand(name, language, options);
+			} catch (error) {
+				firstError ??= error;
+			}
+		}
+		throw firstError ?? new CommandNotFoundError(commandName, language);
+	}
+
+	/**
+	 * Merge languages from every source, preferring earlier sources
+	 */
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		const perSource = await mapConcurrent(
+			this.sources,
+			this.concurrency,
+			({ repository }) => repository.getAvailableLanguages().catch(() => []),
+		);
+
+		const languages = new Map<string, LanguageStatusInfo>();
+		for (const language of perSource.flat()) {
+			if (!languages.has(language.code)) {
+				languages.set(language.code, language);
+			}
+		}
+		return [...languages.values()].sort(
+			(a, b) =>
+				b.commandCount - a.commandCount || compareStrings(a.code, b.code),
+		);
+	}
+
+	/**
+	 * Describe the first source; it is the catalog the others extend
+	 */
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		const first = this.sources[0];
+		return first ? first.repository.getAbout(language) : null;
+	}
+}
+
+/**
+ * Get the name a source knows a command by
+ *
+ * @returns The name without the source's prefix, or undefined if a prefixed
+ *   source cannot have the command
+ */
+function unprefixed(commandName: string, prefix?: string): string | undefined {
+	if (!prefix) {
+		return commandName;
+	}
+	return commandName.startsWith(`${prefix}:`)
+		? commandName.slice(prefix.length + 1)
+		: undefined;
+}
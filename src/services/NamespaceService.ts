@@ -5,6 +5,7 @@ import {
 	type NamespaceValidationOptions,
 	type ParsedNamespace,
 } from "../interfaces/INamespaceService.js";
+import { NAMESPACE_SEGMENT_PATTERN } from "../utils/naming.js";
 
 /**
  * Default validation options for namespace validation
@@ -12,7 +13,7 @@ import {
 const DEFAULT_VALIDATION_OPTIONS: Required<NamespaceValidationOptions> = {
 	maxDepth: 5,
 	minDepth: 1,
-	segmentPattern: /^[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]$|^[a-zA-Z0-9]$/,
+	segmentPattern: NAMESPACE_SEGMENT_PATTERN,
 	allowEmptySegments: false,
 };
 
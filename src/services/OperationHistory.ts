@@ -0,0 +1,304 @@
+import { randomBytes } from "node:crypto";
+import * as path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { fileLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Current history file format version
+ */
+export const HISTORY_VERSION = 1;
+
+/**
+ * Number of completed operations kept for undo
+ */
+export const MAX_HISTORY_BATCHES = 10;
+
+/**
+ * A file changed by an operation, with its content before and after
+ */
+interface HistoryStep {
+	readonly path: string;
+	/** Content before the operation, or null if the file did not exist */
+	readonly previous: string | null;
+	/** Content after the operation, or null if the file was deleted */
+	readonly current: string | null;
+}
+
+/**
+ * On-disk history document
+ */
+interface HistoryFile {
+	version: number;
+	id: string;
+	name: string;
+	at: number;
+	steps: HistoryStep[];
+}
+
+/**
+ * A completed operation that can be undone
+ */
+export interface HistoryBatch {
+	/** Batch id */
+	readonly id: string;
+	/** What was done (e.g., "add review") */
+	readonly name: string;
+	/** When the operation finished (milliseconds since Unix epoch) */
+	readonly at: number;
+	/** Paths the operation changed, in order */
+	readonly paths: readonly string[];
+}
+
+/**
+ * Error thrown when there is nothing to undo or undoing would lose changes
+ */
+export class UndoError extends Error {
+	constructor(
+		message: string,
+		/** Files changed since the operation, if that is why undo failed */
+		public readonly modifiedPaths: readonly string[] = [],
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Record of recent add/remove/move/copy operations for `claude-cmd undo`
+ *
+ * CLI commands wrap their work in batch(); services call capture() before
+ * changing a file. The first capture of a path in a batch saves its previous
+ * content, and when the batch ends the resulting content is saved too, so
+ * undo can restore the old files and notice later edits it would clobber.
+ * Captures outside a batch are ignored.
+ */
+export class OperationHistory {
+	private active: {
+		name: string;
+		previous: Map<string, string | null>;
+	} | null = null;
+
+	/**
+	 * @param fileService - File service for history and target file I/O
+	 * @param historyDir - Directory holding one file per batch
+	 * @param clock - Clock used to stamp batches (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly historyDir: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Run an operation as one undoable batch
+	 *
+	 * Whatever was captured is recorded even if the operation fails midway,
+	 * so partial changes can be undone too. Nested batches join the outer one.
+	 *
+	 * @param name - Description shown by undo (e.g., "add review")
+	 * @param fn - The operation
+	 */
+	async batch<T>(name: string, fn: () => Promise<T>): Promise<T> {
+		if (this.active) {
+			return fn();
+		}
+
+		this.active = { name, previous: new Map() };
+		try {
+			return await fn();
+		} finally {
+			const batch = this.active;
+			this.active = null;
+			await this.save(batch.name, batch.previous);
+		}
+	}
+
+	/**
+	 * Remember a file's content before the current batch changes it
+	 *
+	 * @param filePath - File about to be written, renamed or deleted
+	 */
+	async capture(filePath: string): Promise<void> {
+		if (!this.active || this.active.previous.has(filePath)) {
+			return;
+		}
+		this.active.previous.set(filePath, await this.readIfExists(filePath));
+	}
+
+	/**
+	 * List recorded operations, newest first
+	 */
+	async list(): Promise<HistoryBatch[]> {
+		const batches = await this.loadAll();
+		return batches.map((batch) => ({
+			id: batch.id,
+			name: batch.name,
+			at: batch.at,
+			paths: batch.steps.map((step) => step.path),
+		}));
+	}
+
+	/**
+	 * Revert the most recent operation and drop it from the history
+	 *
+	 * @param options.force - Restore files even if they changed since
+	 * @returns The undone operation
+	 * @throws UndoError if there is nothing to undo, or files changed since
+	 *   the operation and force is not set
+	 */
+	async undo(options: { force?: boolean } = {}): Promise<HistoryBatch> {
+		const [latest] = await this.loadAll();
+		if (!latest) {
+			throw new UndoError("Nothing to undo.");
+		}
+
+		if (!options.force) {
+			const modified: string[] = [];
+			for (const step of latest.steps) {
+				if ((await this.readIfExists(step.path)) !== step.current) {
+					modified.push(step.path);
+				}
+			}
+			if (modified.length > 0) {
+				throw new UndoError(
+					`Cannot undo ${latest.name}: ${modified.length} file(s) changed since. Use --force to restore them anyway.`,
+					modified,
+				);
+			}
+		}
+
+		for (const step of [...latest.steps].reverse()) {
+			if (step.previous === null) {
+				await this.deleteIfExists(step.path);
+			} else {
+				await writeFileAtomic(this.fileService, step.path, step.previous);
+			}
+		}
+		await this.deleteIfExists(this.getBatchPath(latest.id));
+		fileLogger.info("operation undone: {name}", { name: latest.name });
+
+		return {
+			id: latest.id,
+			name: latest.name,
+			at: latest.at,
+			paths: latest.steps.map((step) => step.path),
+		};
+	}
+
+	private async save(
+		name: string,
+		previous: ReadonlyMap<string, string | null>,
+	): Promise<void> {
+		const steps: HistoryStep[] = [];
+		for (const [filePath, before] of previous) {
+			const current = await this.readIfExists(filePath);
+			if (current !== before) {
+				steps.push({ path: filePath, previous: before, current });
+			}
+		}
+		if (steps.length === 0) {
+			return;
+		}
+
+		const at = this.clock.now();
+		const history: HistoryFile = {
+			version: HISTORY_VERSION,
+			id: `${at}-${randomBytes(4).toString("hex")}`,
+			name,
+			at,
+			steps,
+		};
+		try {
+			await writeFileAtomic(
+				this.fileService,
+				this.getBatchPath(history.id),
+				JSON.stringify(history, null, 2),
+			);
+			await this.prune();
+		} catch (error) {
+			// Undo is a safety net; never fail the operation itself over it
+			fileLogger.warn("failed to record operation history: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	private async prune(): Promise<void> {
+		const batches = await this.loadAll();
+		for (const batch of batches.slice(MAX_HISTORY_BATCHES)) {
+			await this.deleteIfExists(this.getBatchPath(batch.id));
+		}
+	}
+
+	private async loadAll(): Promise<HistoryFile[]> {
+		let files: string[];
+		try {
+			files = await this.fileService.listFiles(this.historyDir);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return [];
+			}
+			throw error;
+		}
+
+		const batches: HistoryFile[] = [];
+		for (const file of files.filter((name) => name.endsWith(".json"))) {
+			const batch = await this.load(path.basename(file, ".json"));
+			if (batch) {
+				batches.push(batch);
+			}
+		}
+		return batches.sort((a, b) => b.at - a.at || compareStrings(b.id, a.id));
+	}
+
+	private async load(id: string): Promise<HistoryFile | null> {
+		try {
+			const parsed = JSON.parse(
+				await this.fileService.readFile(this.getBatchPath(id)),
+			);
+			if (
+				parsed?.version !== HISTORY_VERSION ||
+				!Array.isArray(parsed.steps)
+			) {
+				throw new Error("unexpected structure");
+			}
+			return parsed as HistoryFile;
+		} catch (error) {
+			fileLogger.warn("ignoring unreadable history entry: {id} ({error})", {
+				id,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return null;
+		}
+	}
+
+	private async readIfExists(filePath: string): Promise<string | null> {
+		try {
+			return await this.fileService.readFile(filePath);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return null;
+			}
+			throw error;
+		}
+	}
+
+	private async deleteIfExists(filePath: string): Promise<void> {
+		try {
+			await this.fileService.deleteFile(filePath);
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				throw error;
+			}
+		}
+	}
+
+	private getBatchPath(id: string): string {
+		return path.join(this.historyDir, `${id}.json`);
+	}
+}
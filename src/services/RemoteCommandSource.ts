@@ -0,0 +1,230 @@
+import { basename } from "node:path";
+import { fileURLToPath } from "node:url";
+import type IFileService from "../interfaces/IFileService.js";
+import type IHTTPClient from "../interfaces/IHTTPClient.js";
+import { httpLogger } from "../utils/logger.js";
+import { DEFAULT_MAX_COMMAND_BYTES } from "./HTTPRepository.js";
+
+/**
+ * GitHub API endpoint gists are read from
+ */
+export const GIST_API_URL = "https://api.github.com/gists";
+
+/**
+ * Error thrown when a URL or gist does not lead to a single command file
+ */
+export class RemoteCommandError extends Error {
+	constructor(
+		message: string,
+		public readonly spec: string,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * A command file downloaded from outside the repository
+ */
+export interface RemoteCommandFile {
+	/** URL the content was downloaded from (recorded as provenance) */
+	readonly url: string;
+	/** File name, from the URL or the gist (e.g., "review.md") */
+	readonly fileName: string;
+	/** Raw markdown content */
+	readonly content: string;
+}
+
+/**
+ * A gist reference: its id and optionally which of its files to use
+ */
+export interface GistSpec {
+	readonly id: string;
+	readonly file?: string;
+}
+
+interface GistFile {
+	raw_url?: unknown;
+	content?: unknown;
+	truncated?: unknown;
+}
+
+interface GistResponse {
+	files?: Record<string, GistFile>;
+}
+
+/**
+ * Parse a gist reference
+ *
+ * Accepts "gist:<id>", "gist:<user>/<id>" and gist.github.com URLs, each
+ * optionally followed by "#<file name>" to pick a file of the gist.
+ *
+ * @returns The gist, or undefined if spec does not name one
+ */
+export function parseGistSpec(spec: string): GistSpec | undefined {
+	const match =
+		/^gist:(?:[\w-]+\/)?([0-9a-f]+)(?:#(.+))?$/i.exec(spec) ??
+		/^https:\/\/gist\.github\.com\/(?:[\w-]+\/)?([0-9a-f]+)\/?(?:#(.+))?$/i.exec(
+			spec,
+		);
+	if (!match?.[1]) {
+		return undefined;
+	}
+	return match[2] ? { id: match[1], file: match[2] } : { id: match[1] };
+}
+
+/**
+ * Rewrite GitHub file page URLs to the raw file they show
+ *
+ * "https://github.com/<owner>/<repo>/blob/<ref>/<path>" becomes
+ * "https://raw.githubusercontent.com/<owner>/<repo>/<ref>/<path>"; other URLs
+ * are returned unchanged.
+ */
+export function rawFileURL(url: string): string {
+	return url.replace(
+		/^https:\/\/github\.com\/([^/]+)\/([^/]+)\/blob\//,
+		"https://raw.githubusercontent.com/$1/$2/",
+	);
+}
+
+/**
+ * Downloads single command files shared by URL or as gists
+ *
+ * Nothing here reads the manifest: the caller validates the file and
+ * installs it under a name of its choosing (see `add --url`). Downloads
+ * go over https only; file:// URLs read a local file, for trying a command
+ * out before sharing it.
+ */
+export class RemoteCommandSource {
+	/**
+	 * @param httpClient - Client for downloads and the GitHub API
+	 * @param fileService - File service for file:// URLs
+	 * @param maxBytes - Largest command file accepted
+	 */
+	constructor(
+		private readonly httpClient: IHTTPClient,
+		private readonly fileService: IFileService,
+		private readonly maxBytes = DEFAULT_MAX_COMMAND_BYTES,
+	) {}
+
+	/**
+	 * Download the command file a URL or gist reference points at
+	 *
+	 * @param spec - https or file:// URL, GitHub file page, or gist reference
+	 *   (see parseGistSpec())
+	 * @throws RemoteCommandError if spec is not an https or file:// URL or a
+	 *   gist, the file is too large, or the gist does not have exactly one
+	 *   matching markdown file
+	 * @throws HTTPError subclasses when the download fails
+	 */
+	async fetch(spec: string): Promise<RemoteCommandFile> {
+		const gist = parseGistSpec(spec);
+		if (gist) {
+			return this.fetchGist(gist, spec);
+		}
+
+		let url: URL;
+		try {
+			url = new URL(rawFileURL(spec));
+		} catch {
+			throw new RemoteCommandError(`Not a URL or gist: ${spec}`, spec);
+		}
+		if (url.protocol === "file:") {
+			return this.readFile(url, spec);
+		}
+		if (url.protocol !== "https:") {
+			throw new RemoteCommandError(
+				`Only https and file:// URLs can be installed from: ${spec}`,
+				spec,
+			);
+		}
+
+		const response = await this.httpClient.get(url.href, {
+			maxBytes: this.maxBytes,
+		});
+		const fileName = decodeURIComponent(
+			url.pathname.split("/").filter(Boolean).at(-1) ?? "",
+		);
+		httpLogger.debug("fetched command from {url} ({bytes} bytes)", {
+			url: url.href,
+			bytes: response.body.length,
+		});
+		return { url: url.href, fileName, content: response.body };
+	}
+
+	private async readFile(url: URL, spec: string): Promise<RemoteCommandFile> {
+		const filePath = fileURLToPath(url);
+		const content = await this.fileService.readFile(filePath);
+		if (Buffer.byteLength(content, "utf8") > this.maxBytes) {
+			throw new RemoteCommandError(
+				`${filePath} is larger than ${this.maxBytes} bytes`,
+				spec,
+			);
+		}
+		return { url: url.href, fileName: basename(filePath), content };
+	}
+
+	private async fetchGist(
+		gist: GistSpec,
+		spec: string,
+	): Promise<RemoteCommandFile> {
+		const apiUrl = `${GIST_API_URL}/${gist.id}`;
+		const response = await this.httpClient.get(apiUrl, {
+			headers: { Accept: "application/vnd.github+json" },
+		});
+
+		let files: Record<string, GistFile>;
+		try {
+			files = (JSON.parse(response.body) as GistResponse).files ?? {};
+		} catch {
+			throw new RemoteCommandError(
+				`Invalid gist information for ${spec}`,
+				spec,
+			);
+		}
+
+		const candidates = Object.entries(files).filter(([name]) =>
+			gist.file ? name === gist.file : name.toLowerCase().endsWith(".md"),
+		);
+		const [only, ...others] = candidates;
+		if (!only) {
+			throw new RemoteCommandError(
+				gist.file
+					? `Gist ${gist.id} has no file named ${gist.file}`
+					: `Gist ${gist.id} has no markdown file`,
+				spec,
+			);
+		}
+		if (others.length > 0) {
+			throw new RemoteCommandError(
+				`Gist ${gist.id} has several markdown files; pick one with ${spec}#<file>: ${candidates.map(([name]) => name).join(", ")}`,
+				spec,
+			);
+		}
+
+		const [fileName, file] = only;
+		if (typeof file.raw_url !== "string") {
+			throw new RemoteCommandError(
+				`Invalid gist information for ${spec}`,
+				spec,
+			);
+		}
+		// The API truncates large files; the raw URL always has all of it
+		const content =
+			typeof file.content === "string" && file.truncated !== true
+				? file.content
+				: (
+						await this.httpClient.get(file.raw_url, {
+							maxBytes: this.maxBytes,
+						})
+					).body;
+		if (Buffer.byteLength(content, "utf8") > this.maxBytes) {
+			throw new RemoteCommandError(
+				`${fileName} is larger than ${this.maxBytes} bytes`,
+				spec,
+			);
+		}
+
+		return { url: file.raw_url, fileName, content };
+	}
+}
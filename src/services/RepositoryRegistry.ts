@@ -0,0 +1,160 @@
+import type { Config } from "../interfaces/IConfigService.js";
+import type IRepository from "../interfaces/IRepository.js";
+import { mapConcurrent } from "../utils/concurrency.js";
+import {
+	isSameRepository,
+	repositoryPublicKeyFor,
+} from "../utils/repositorySource.js";
+import HTTPRepository from "./HTTPRepository.js";
+import { MANIFEST_FETCH_CONCURRENCY } from "./MultiRepository.js";
+
+/**
+ * A repository named in the configuration
+ */
+export interface ConfiguredRepositoryEntry {
+	/** Repository URL as configured */
+	readonly url: string;
+	/** Whether this is repositoryURL (or the default), not an additional one */
+	readonly primary: boolean;
+	/** Whether its commands are listed (see disabledRepositories) */
+	readonly enabled: boolean;
+}
+
+/**
+ * Health of a configured repository
+ *
+ * - ok: the manifest was fetched and is valid
+ * - failed: the repository is unreachable or its manifest is unusable (the
+ *   error says which)
+ * - disabled: not checked
+ */
+export type RepositoryHealthStatus = "ok" | "failed" | "disabled";
+
+/**
+ * Result of checking one configured repository
+ */
+export interface RepositoryHealth extends ConfiguredRepositoryEntry {
+	readonly status: RepositoryHealthStatus;
+	/** Commands in its manifest when status is "ok" */
+	readonly commandCount?: number;
+	/** Why the check failed */
+	readonly error?: string;
+}
+
+/**
+ * List the repositories a configuration names, primary first
+ *
+ * Disabled entries are included; check `enabled` to leave them out.
+ */
+export function listConfiguredRepositories(
+	config: Config,
+): ConfiguredRepositoryEntry[] {
+	const disabled = config.disabledRepositories ?? [];
+	return [
+		{
+			url: config.repositoryURL || HTTPRepository.BASE_URL,
+			primary: true,
+			enabled: true,
+		},
+		...(config.additionalRepositories ?? []).map((url) => ({
+			url,
+			primary: false,
+			enabled: !disabled.some((entry) => isSameRepository(entry, url)),
+		})),
+	];
+}
+
+/**
+ * Narrow a configuration to one of the repositories it names
+ *
+ * Additional repositories are read over HTTP (or in place for file:// URLs)
+ * and verified with their own repositoryPublicKeys entry, never with the
+ * primary repository's manifestPublicKey.
+ *
+ * @param config - Effective configuration
+ * @param entry - Repository to keep (see listConfiguredRepositories())
+ * @returns A configuration naming only that repository
+ */
+export function configForRepository(
+	config: Config,
+	entry: Pick<ConfiguredRepositoryEntry, "url" | "primary">,
+): Config {
+	if (entry.primary) {
+		return { ...config, additionalRepositories: [] };
+	}
+	return {
+		...config,
+		repositoryURL: entry.url,
+		repositoryType: "http",
+		manifestPublicKey: repositoryPublicKeyFor(
+			config.repositoryPublicKeys,
+			entry.url,
+		),
+		additionalRepositories: [],
+	};
+}
+
+/**
+ * Reports on the repositories commands are read from
+ *
+ * Each repository is checked on its own, bypassing caches, bundles and the
+ * other repositories, so the report shows what its server answers right now.
+ */
+export class RepositoryRegistry {
+	/**
+	 * @param getConfig - Reads the effective configuration
+	 * @param createRepository - Creates the repository a single-source
+	 *   configuration points at
+	 * @param concurrency - Repositories checked at once
+	 */
+	constructor(
+		private readonly getConfig: () => Promise<Config>,
+		private readonly createRepository: (config: Config) => IRepository,
+		private readonly concurrency = MANIFEST_FETCH_CONCURRENCY,
+	) {}
+
+	/**
+	 * List the configured repositories, primary first
+	 */
+	async list(): Promise<ConfiguredRepositoryEntry[]> {
+		return listConfiguredRepositories(await this.getConfig());
+	}
+
+	/**
+	 * Fetch the manifest of every enabled repository
+	 *
+	 * @param language - Language whose manifest is fetched (e.g., "en")
+	 * @returns One result per configured repository, in list() order
+	 */
+	async check(language: string): Promise<RepositoryHealth[]> {
+		const config = await this.getConfig();
+		return mapConcurrent(
+			listConfiguredRepositories(config),
+			this.concurrency,
+			async (entry): Promise<RepositoryHealth> => {
+				if (!entry.enabled) {
+					return { ...entry, status: "disabled" };
+				}
+				const repository = this.createRepository(
+					configForRepository(config, entry),
+				);
+				try {
+					const manifest = await repository.getManifest(language, {
+						forceRefresh: true,
+					});
+					return {
+						...entry,
+						status: "ok",
+						commandCount: manifest.commands.length,
+					};
+				} catch (error) {
+					return {
+						...entry,
+						status: "failed",
+						error: error instanceof Error ? error.message : String(error),
+					};
+				}
+			},
+		);
+	}
+}
@@ -0,0 +1,321 @@
+import { createHash, type Hash } from "node:crypto";
+import * as path from "node:path";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import type IHTTPClient from "../interfaces/IHTTPClient.js";
+import { httpLogger } from "../utils/logger.js";
+import { compareVersions } from "../utils/versions.js";
+
+/**
+ * GitHub repository claude-cmd releases are published to
+ */
+export const RELEASES_REPOSITORY = "claude-code-commands/claude-cmd";
+
+/**
+ * Release asset listing the SHA-256 of every binary (sha256sum format)
+ */
+export const CHECKSUMS_ASSET = "SHA256SUMS";
+
+/**
+ * Time allowed to download a release binary
+ */
+const DOWNLOAD_TIMEOUT_MS = 300000;
+
+/**
+ * Largest binary accepted from a release
+ */
+const MAX_BINARY_BYTES = 512 * 1024 * 1024;
+
+/**
+ * Error thrown when a release cannot be found, verified or installed
+ */
+export class SelfUpdateError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * A published release and the binary for this platform
+ */
+export interface ReleaseInfo {
+	/** Version of the release, without the leading "v" of its tag */
+	readonly version: string;
+	/** Release page */
+	readonly url: string;
+	/** Whether the release is newer than the running version */
+	readonly newer: boolean;
+	/** Name of the binary asset for this platform */
+	readonly assetName: string;
+	/** Download URL of the binary, if the release has one for this platform */
+	readonly binaryUrl?: string;
+	/** Download URL of the checksums file, if the release has one */
+	readonly checksumsUrl?: string;
+}
+
+interface GitHubRelease {
+	tag_name?: unknown;
+	html_url?: unknown;
+	assets?: unknown;
+}
+
+/**
+ * Name of the release binary built for a platform
+ *
+ * Binaries are published as claude-cmd-<os>-<arch>, with ".exe" on Windows
+ * (e.g., "claude-cmd-linux-x64", "claude-cmd-windows-x64.exe").
+ *
+ * @throws SelfUpdateError for platforms no binary is built for
+ */
+export function binaryAssetName(
+	platform: NodeJS.Platform,
+	arch: string,
+): string {
+	const os = platform === "win32" ? "windows" : platform;
+	if (!["linux", "darwin", "windows"].includes(os)) {
+		throw new SelfUpdateError(`No release binary is built for ${platform}`);
+	}
+	if (arch !== "x64" && arch !== "arm64") {
+		throw new SelfUpdateError(`No release binary is built for ${arch}`);
+	}
+	return `claude-cmd-${os}-${arch}${platform === "win32" ? ".exe" : ""}`;
+}
+
+/**
+ * Find the checksum of a file in sha256sum output
+ *
+ * @returns The lower-case hex digest, or undefined if the file is not listed
+ */
+export function findChecksum(
+	checksums: string,
+	fileName: string,
+): string | undefined {
+	for (const line of checksums.split(/\r?\n/)) {
+		// "<digest>  <name>", or "<digest> *<name>" for binary mode
+		const match = /^([0-9a-fA-F]{64})\s+\*?(.+)$/.exec(line.trim());
+		if (match?.[2] === fileName) {
+			return match[1]?.toLowerCase();
+		}
+	}
+	return undefined;
+}
+
+/**
+ * Pass chunks through while adding them to a hash
+ */
+async function* hashed(
+	chunks: AsyncIterable<Uint8Array>,
+	hash: Hash,
+): AsyncIterable<Uint8Array> {
+	for await (const chunk of chunks) {
+		hash.update(chunk);
+		yield chunk;
+	}
+}
+
+/**
+ * Checks GitHub releases for a newer claude-cmd and installs it
+ */
+export class SelfUpdateService {
+	/**
+	 * @param httpClient - Client for the GitHub API and release downloads
+	 * @param fileService - File service for staging and swapping binaries
+	 * @param repository - GitHub "owner/name" releases are read from
+	 */
+	constructor(
+		private readonly httpClient: IHTTPClient,
+		private readonly fileService: IFileService,
+		private readonly repository: string = RELEASES_REPOSITORY,
+	) {}
+
+	/**
+	 * Look up the latest release
+	 *
+	 * @param currentVersion - Version of the running claude-cmd
+	 * @param platform - Platform the binary is for (default: this one)
+	 * @param arch - CPU architecture the binary is for (default: this one)
+	 * @param signal - Aborts the lookup, e.g. once the caller stopped waiting
+	 * @throws SelfUpdateError when the release information is malformed
+	 */
+	async check(
+		currentVersion: string,
+		platform: NodeJS.Platform = process.platform,
+		arch: string = process.arch,
+		signal?: AbortSignal,
+	): Promise<ReleaseInfo> {
+		const apiUrl = `https://api.github.com/repos/${this.repository}/releases/latest`;
+		const response = await this.httpClient.get(apiUrl, {
+			headers: { Accept: "application/vnd.github+json" },
+			signal,
+		});
+
+		let release: GitHubRelease;
+		try {
+			release = JSON.parse(response.body);
+		} catch {
+			throw new SelfUpdateError(`Invalid release information from ${apiUrl}`);
+		}
+		if (typeof release.tag_name !== "string") {
+			throw new SelfUpdateError(`Latest release has no tag (${apiUrl})`);
+		}
+
+		const assets = new Map<string, string>();
+		for (const asset of Array.isArray(release.assets) ? release.assets : []) {
+			if (
+				typeof asset?.name === "string" &&
+				typeof asset.browser_download_url === "string"
+			) {
+				assets.set(asset.name, asset.browser_download_url);
+			}
+		}
+
+		const version = release.tag_name.replace(/^v/, "");
+		const assetName = binaryAssetName(platform, arch);
+		return {
+			version,
+			url:
+				typeof release.html_url === "string"
+					? release.html_url
+					: `https://github.com/${this.repository}/releases`,
+			newer: compareVersions(version, currentVersion) > 0,
+			assetName,
+			binaryUrl: assets.get(assetName),
+			checksumsUrl: assets.get(CHECKSUMS_ASSET),
+		};
+	}
+
+	/**
+	 * Install a release binary in place of an executable
+	 *
+	 * The binary is downloaded to "<executable>.new" and swapped in once
+	 * verified (see download() and replaceExecutable()).
+	 *
+	 * @param release - Release to install, as returned by check()
+	 * @param executablePath - Executable to replace
+	 * @param platform - Platform rules to follow (default: this one)
+	 * @throws SelfUpdateError when the executable's directory is not writable
+	 *   or the binary cannot be verified
+	 */
+	async install(
+		release: ReleaseInfo,
+		executablePath: string,
+		platform: NodeJS.Platform = process.platform,
+	): Promise<void> {
+		const dir = path.dirname(executablePath);
+		if (!(await this.fileService.isWritable(dir))) {
+			throw new SelfUpdateError(
+				`Cannot write to ${dir}; re-run with permission to replace ${executablePath}`,
+			);
+		}
+
+		const staged = `${executablePath}.new`;
+		await this.download(release, staged);
+		await this.replaceExecutable(executablePath, staged, platform);
+	}
+
+	/**
+	 * Download the binary of a release to a file and verify it against
+	 * SHA256SUMS
+	 *
+	 * The binary is written to the file as it arrives rather than held in
+	 * memory, and the file is removed again if the download fails or the
+	 * checksum differs.
+	 *
+	 * @param release - Release to download, as returned by check()
+	 * @param destination - File to write the binary to
+	 * @throws SelfUpdateError when an asset is missing or the checksum differs
+	 */
+	async download(release: ReleaseInfo, destination: string): Promise<void> {
+		if (!release.binaryUrl) {
+			throw new SelfUpdateError(
+				`Release ${release.version} has no ${release.assetName} binary`,
+			);
+		}
+		if (!release.checksumsUrl) {
+			throw new SelfUpdateError(
+				`Release ${release.version} has no ${CHECKSUMS_ASSET}; refusing to install an unverified binary`,
+			);
+		}
+
+		const checksums = await this.httpClient.get(release.checksumsUrl);
+		const expected = findChecksum(checksums.body, release.assetName);
+		if (!expected) {
+			throw new SelfUpdateError(
+				`${CHECKSUMS_ASSET} of release ${release.version} does not list ${release.assetName}`,
+			);
+		}
+
+		httpLogger.debug("Downloading {url}", { url: release.binaryUrl });
+		const response = await this.httpClient.stream(release.binaryUrl, {
+			timeout: DOWNLOAD_TIMEOUT_MS,
+			maxBytes: MAX_BINARY_BYTES,
+		});
+		const hash = createHash("sha256");
+		try {
+			await this.fileService.writeBinaryStream(
+				destination,
+				hashed(response.body, hash),
+			);
+
+			const actual = hash.digest("hex");
+			if (actual !== expected) {
+				throw new SelfUpdateError(
+					`Checksum mismatch for ${release.assetName}: expected ${expected}, got ${actual}`,
+				);
+			}
+		} catch (error) {
+			await this.removeIfExists(destination);
+			throw error;
+		}
+	}
+
+	/**
+	 * Replace an executable with a downloaded binary
+	 *
+	 * The binary should be next to the executable so the swap is a rename on
+	 * the same file system. Windows does not allow replacing a running
+	 * executable, but does allow renaming it: the old binary is moved to
+	 * "<executable>.old" (removed on the next update) before the new one
+	 * takes its place.
+	 *
+	 * @param executablePath - Executable to replace
+	 * @param binaryPath - New executable, removed if it cannot be swapped in
+	 * @param platform - Platform rules to follow (default: this one)
+	 */
+	async replaceExecutable(
+		executablePath: string,
+		binaryPath: string,
+		platform: NodeJS.Platform = process.platform,
+	): Promise<void> {
+		try {
+			await this.fileService.makeExecutable(binaryPath);
+			if (platform === "win32") {
+				const old = `${executablePath}.old`;
+				await this.removeIfExists(old);
+				await this.fileService.rename(executablePath, old);
+				try {
+					await this.fileService.rename(binaryPath, executablePath);
+				} catch (error) {
+					await this.fileService.rename(old, executablePath);
+					throw error;
+				}
+			} else {
+				await this.fileService.rename(binaryPath, executablePath);
+			}
+		} catch (error) {
+			await this.removeIfExists(binaryPath);
+			throw error;
+		}
+	}
+
+	private async removeIfExists(filePath: string): Promise<void> {
+		try {
+			await this.fileService.deleteFile(filePath);
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				throw error;
+			}
+		}
+	}
+}
@@ -1,9 +1,140 @@
+import type IStatusFormatter from "../interfaces/IStatusFormatter.js";
+import type { StatusFormatOptions } from "../interfaces/IStatusFormatter.js";
 import type {
+	AllLanguagesStatus,
 	CacheInfo,
+	CacheUsage,
+	DiskUsage,
+	HealthRecommendation,
 	InstallationInfo,
+	LanguageCacheStatus,
 	StatusOutputFormat,
 	SystemStatus,
 } from "../types/Status.js";
+import {
+	formatCount,
+	formatFileSize,
+	formatQuantity,
+	formatRelativeAge,
+} from "../utils/format.js";
+import { PLAIN_THEME, type Theme } from "../utils/style.js";
+import {
+	renderTemplate,
+	type Template,
+	type TemplateData,
+} from "../utils/template.js";
+
+/**
+ * Icon shown before a recommendation of each severity
+ */
+const RECOMMENDATION_ICONS: Record<HealthRecommendation["severity"], string> =
+	{
+		error: "❌",
+		warning: "⚠️ ",
+		info: "ℹ️ ",
+	};
+
+/**
+ * Theme style of a recommendation of each severity
+ */
+const RECOMMENDATION_STYLES: Record<
+	HealthRecommendation["severity"],
+	keyof Theme
+> = {
+	error: "error",
+	warning: "warning",
+	info: "dim",
+};
+
+/**
+ * Theme style of each overall health status
+ */
+const HEALTH_STYLES: Record<SystemStatus["health"]["status"], keyof Theme> = {
+	healthy: "success",
+	degraded: "warning",
+	error: "error",
+};
+
+/**
+ * Wording of the status lines that show dates, ages and counts
+ *
+ * Each line is written in one language: values are formatted in the
+ * language of the words around them.
+ */
+interface StatusWording {
+	/** Language dates, ages and counts are formatted in */
+	readonly locale: string;
+	readonly noManifest: string;
+	readonly unknownCommands: string;
+	readonly expired: string;
+	collectedAt(date: string): string;
+	updated(age: string): string;
+	commands(count: string): string;
+	commandsInstalled(count: string): string;
+	commandCount(count: number): string;
+	fileCount(count: number): string;
+	updatedAgo(age: string): string;
+	filesCached(files: string): string;
+	newest(age: string): string;
+	installedCommands(
+		type: InstallationInfo["type"],
+		size: string,
+		commands: string,
+	): string;
+}
+
+const ENGLISH_WORDING: StatusWording = {
+	locale: "en",
+	noManifest: "no manifest",
+	unknownCommands: "? commands",
+	expired: "expired",
+	collectedAt: (date) => `Status collected at: ${date}`,
+	updated: (age) => `Updated: ${age}`,
+	commands: (count) => `Commands: ${count}`,
+	commandsInstalled: (count) => `Commands Installed: ${count}`,
+	commandCount: (count) => formatQuantity(count, "command", "commands", "en"),
+	fileCount: (count) => formatQuantity(count, "file", "files", "en"),
+	updatedAgo: (age) => `updated ${age}`,
+	filesCached: (files) => `${files} cached`,
+	newest: (age) => `newest ${age}`,
+	installedCommands: (type, size, commands) =>
+		`${type === "user" ? "Personal" : "Project"} Commands: ${size} in ${commands}`,
+};
+
+const FRENCH_WORDING: StatusWording = {
+	locale: "fr",
+	noManifest: "aucun manifeste",
+	unknownCommands: "? commandes",
+	expired: "expiré",
+	collectedAt: (date) => `État relevé le : ${date}`,
+	updated: (age) => `Mis à jour : ${age}`,
+	commands: (count) => `Commandes : ${count}`,
+	commandsInstalled: (count) => `Commandes installées : ${count}`,
+	commandCount: (count) =>
+		formatQuantity(count, "commande", "commandes", "fr"),
+	fileCount: (count) => formatQuantity(count, "fichier", "fichiers", "fr"),
+	updatedAgo: (age) => `mis à jour ${age}`,
+	filesCached: (files) => `${files} en cache`,
+	newest: (age) => `le plus récent ${age}`,
+	installedCommands: (type, size, commands) =>
+		`Commandes ${type === "user" ? "personnelles" : "du projet"} : ${size} dans ${commands}`,
+};
+
+/**
+ * Wording for each language status lines are translated to; other
+ * languages get English lines
+ */
+const STATUS_WORDINGS: Readonly<Record<string, StatusWording>> = {
+	en: ENGLISH_WORDING,
+	fr: FRENCH_WORDING,
+};
+
+/**
+ * Pick the wording of a language, falling back to English
+ */
+function wordingFor(locale: string | undefined): StatusWording {
+	return (locale && STATUS_WORDINGS[locale]) || ENGLISH_WORDING;
+}
 
 /**
  * Formatter for system status output in various formats
@@ -17,15 +148,20 @@ import type {
  * - JSON format for programmatic consumption
  * - Consistent styling and messaging
  */
-export class StatusFormatter {
+export class StatusFormatter implements IStatusFormatter {
 	/**
 	 * Format system status in the specified output format
 	 *
 	 * @param status - System status data to format
 	 * @param format - Output format to use
+	 * @param options - Theme and language of human-readable formats
 	 * @returns Formatted status string
 	 */
-	format(status: SystemStatus, format: StatusOutputFormat): string {
+	format(
+		status: SystemStatus,
+		format: StatusOutputFormat,
+		options: StatusFormatOptions = {},
+	): string {
 		switch (format) {
 			case "json":
 				return this.formatJson(status);
@@ -33,79 +169,317 @@ export class StatusFormatter {
 				return this.formatCompact(status);
 			case "default":
 			default:
-				return this.formatDefault(status);
+				return this.formatDefault(
+					status,
+					options.theme ?? PLAIN_THEME,
+					wordingFor(options.locale),
+				);
+		}
+	}
+
+	/**
+	 * Render system status with a user template (global --format)
+	 *
+	 * Fields: .Health.Status, .Health.Score, .Cache.Language, .Cache.Exists,
+	 * .Cache.Expired, .Cache.CommandCount, .Cache.AgeSeconds, .Cache.SizeBytes,
+	 * .Installed.TotalCount, .Installed.PersonalCount,
+	 * .Installed.ProjectCount and .Untracked.Count.
+	 *
+	 * @param status - System status data to format
+	 * @param template - Parsed template
+	 * @param language - Language whose cache fills .Cache
+	 * @returns The rendered template
+	 * @throws TemplateError if the template names an unknown field
+	 */
+	formatTemplate(
+		status: SystemStatus,
+		template: Template,
+		language: string,
+	): string {
+		const cache = status.cache.find((info) => info.language === language);
+		const countIn = (type: InstallationInfo["type"]) =>
+			status.installations
+				.filter((install) => install.type === type)
+				.reduce((sum, install) => sum + install.commandCount, 0);
+		const data: TemplateData = {
+			Health: {
+				Status: status.health.status,
+				Score: status.health.score,
+			},
+			Cache: {
+				Language: language,
+				Exists: cache?.exists ?? false,
+				Expired: cache?.isExpired ?? true,
+				CommandCount: cache?.commandCount ?? 0,
+				AgeSeconds:
+					cache?.ageMs === undefined
+						? undefined
+						: Math.floor(cache.ageMs / 1000),
+				SizeBytes: cache?.sizeBytes ?? 0,
+			},
+			Installed: {
+				TotalCount: countIn("user") + countIn("project"),
+				PersonalCount: countIn("user"),
+				ProjectCount: countIn("project"),
+			},
+			Untracked: {
+				Count: status.untracked?.length ?? 0,
+			},
+		};
+		return renderTemplate(template, data);
+	}
+
+	/**
+	 * Format the per-language cache breakdown in the specified output format
+	 *
+	 * @param status - Cache status of every cached language
+	 * @param format - Output format to use
+	 * @param options - Language of human-readable formats
+	 * @returns Formatted status string
+	 */
+	formatAllLanguages(
+		status: AllLanguagesStatus,
+		format: StatusOutputFormat,
+		options: StatusFormatOptions = {},
+	): string {
+		switch (format) {
+			case "json":
+				return JSON.stringify(status, null, 2);
+			case "compact":
+				return status.languages
+					.map(
+						(lang) =>
+							`${lang.language}${lang.active ? "*" : ""}: ${formatQuantity(lang.commandCount ?? 0, "command", "commands")}, ${formatQuantity(lang.cachedFiles, "file", "files")}`,
+					)
+					.join(" | ");
+			case "default":
+			default: {
+				const wording = wordingFor(options.locale);
+				const lines = [`Language Caches (active: ${status.activeLanguage}):`];
+				const width = Math.max(
+					...status.languages.map((lang) => lang.language.length),
+				);
+				for (const lang of status.languages) {
+					lines.push(
+						`  ${lang.active ? "*" : " "} ${lang.language.padEnd(width)}  ${this.describeLanguageCache(lang, wording)}`,
+					);
+				}
+				return lines.join("\n");
+			}
 		}
 	}
 
+	/**
+	 * Format disk usage in the specified output format
+	 *
+	 * @param usage - Disk space used by caches and installed commands
+	 * @param format - Output format to use
+	 * @param options - Language of human-readable formats
+	 * @returns Formatted usage string
+	 */
+	formatDiskUsage(
+		usage: DiskUsage,
+		format: StatusOutputFormat,
+		options: StatusFormatOptions = {},
+	): string {
+		const cacheBytes = usage.cache.reduce(
+			(sum, lang) => sum + lang.sizeBytes,
+			0,
+		);
+		const label = (type: InstallationInfo["type"]) =>
+			type === "user" ? "Personal" : "Project";
+		const wording = wordingFor(options.locale);
+
+		switch (format) {
+			case "json":
+				return JSON.stringify(usage, null, 2);
+			case "compact":
+				return [
+					`Cache: ${formatFileSize(cacheBytes)}`,
+					...usage.installations.map(
+						(install) =>
+							`${label(install.type)}: ${formatFileSize(install.sizeBytes)}`,
+					),
+					`Total: ${formatFileSize(usage.totalBytes)}`,
+				].join(" | ");
+			case "default":
+			default: {
+				const lines = ["Disk Usage", "=========="];
+
+				lines.push("", `Cache: ${formatFileSize(cacheBytes)}`);
+				for (const line of this.formatCacheUsage(usage.cache).split("\n")) {
+					lines.push(`  ${line}`);
+				}
+
+				for (const install of usage.installations) {
+					lines.push(
+						"",
+						wording.installedCommands(
+							install.type,
+							formatFileSize(install.sizeBytes),
+							wording.commandCount(install.commandCount),
+						),
+						`  ${install.path}`,
+					);
+					for (const [namespace, bytes] of Object.entries(install.namespaces)) {
+						lines.push(`    ${namespace}: ${formatFileSize(bytes)}`);
+					}
+				}
+
+				lines.push("", `Total: ${formatFileSize(usage.totalBytes)}`);
+				return lines.join("\n");
+			}
+		}
+	}
+
+	/**
+	 * Format the disk space used by the caches of each language
+	 *
+	 * @param usage - Cache usage per language
+	 * @returns One line per language, or a note that nothing is cached
+	 */
+	formatCacheUsage(usage: readonly CacheUsage[]): string {
+		if (usage.length === 0) {
+			return "Nothing cached";
+		}
+		const width = Math.max(...usage.map((lang) => lang.language.length));
+		return usage
+			.map(
+				(lang) =>
+					`${lang.language.padEnd(width)}  ${formatFileSize(lang.sizeBytes)} (manifest ${formatFileSize(lang.manifestBytes)}, files ${formatFileSize(lang.filesBytes)})`,
+			)
+			.join("\n");
+	}
+
+	/**
+	 * Describe the manifest, cached files and size of one language
+	 */
+	private describeLanguageCache(
+		lang: LanguageCacheStatus,
+		wording: StatusWording,
+	): string {
+		const manifest = lang.hasManifest
+			? [
+					lang.commandCount === undefined
+						? wording.unknownCommands
+						: wording.commandCount(lang.commandCount),
+					lang.manifestAgeMs === undefined
+						? undefined
+						: wording.updatedAgo(
+								formatRelativeAge(lang.manifestAgeMs, wording.locale),
+							),
+					lang.isExpired ? wording.expired : undefined,
+				]
+					.filter(Boolean)
+					.join(", ")
+			: wording.noManifest;
+		const cached = wording.filesCached(wording.fileCount(lang.cachedFiles));
+		const files =
+			lang.filesAgeMs === undefined
+				? cached
+				: `${cached}, ${wording.newest(formatRelativeAge(lang.filesAgeMs, wording.locale))}`;
+		return `${manifest} | ${files} | ${formatFileSize(lang.sizeBytes)}`;
+	}
+
 	/**
 	 * Format status in default human-readable format
 	 *
 	 * @param status - System status data
+	 * @param theme - Styles to apply
+	 * @param wording - Wording of lines with dates, ages and counts
 	 * @returns Formatted status string
 	 */
-	private formatDefault(status: SystemStatus): string {
+	private formatDefault(
+		status: SystemStatus,
+		theme: Theme,
+		wording: StatusWording,
+	): string {
 		const lines: string[] = [];
 
 		// Header
-		lines.push("Claude CMD System Status");
+		lines.push(theme.heading("Claude CMD System Status"));
 		lines.push("=======================");
-		const dateFormatter = new Intl.DateTimeFormat(undefined, {
+		const dateFormatter = new Intl.DateTimeFormat(wording.locale, {
 			dateStyle: "full",
 			timeStyle: "long",
 		});
 		lines.push(
-			`Status collected at: ${dateFormatter.format(new Date(status.timestamp))}`,
+			wording.collectedAt(dateFormatter.format(new Date(status.timestamp))),
 		);
 		lines.push("");
 
 		// System Health
-		lines.push("System Health:");
+		lines.push(theme.heading("System Health:"));
 		const healthIcon = this.getHealthIcon(status.health.status);
+		const healthStyle = theme[HEALTH_STYLES[status.health.status]];
 		lines.push(
-			`  Overall Status: ${healthIcon} ${status.health.status.toUpperCase()}`,
+			`  Overall Status: ${healthIcon} ${healthStyle(status.health.status.toUpperCase())}`,
 		);
 		lines.push(
-			`  Cache Accessible: ${status.health.cacheAccessible ? "✅ Yes" : "❌ No"}`,
+			`  Cache Accessible: ${this.formatYesNo(status.health.cacheAccessible, theme)}`,
 		);
 		lines.push(
-			`  Installation Possible: ${status.health.installationPossible ? "✅ Yes" : "❌ No"}`,
+			`  Installation Possible: ${this.formatYesNo(status.health.installationPossible, theme)}`,
 		);
+		if (status.health.repositoryReachable !== undefined) {
+			lines.push(
+				`  Repository Reachable: ${this.formatYesNo(status.health.repositoryReachable, theme)}`,
+			);
+		}
+		lines.push(`  Health Score: ${status.health.score}/100`);
 
 		if (status.health.messages.length > 0) {
 			lines.push("  Messages:");
 			for (const message of status.health.messages) {
-				lines.push(`    ⚠️  ${message}`);
+				lines.push(`    ⚠️  ${theme.warning(message)}`);
 			}
 		}
 		lines.push("");
 
+		if (status.health.recommendations.length > 0) {
+			lines.push(theme.heading("Recommendations:"));
+			status.health.recommendations.forEach((recommendation, index) => {
+				const icon = RECOMMENDATION_ICONS[recommendation.severity];
+				const style = theme[RECOMMENDATION_STYLES[recommendation.severity]];
+				lines.push(
+					`  ${index + 1}. ${icon} ${style(recommendation.problem)}`,
+				);
+				lines.push(`     → ${recommendation.action}`);
+			});
+			lines.push("");
+		}
+
 		// Cache Status
-		lines.push("Cache Status:");
+		lines.push(theme.heading("Cache Status:"));
 		if (status.cache.length === 0) {
 			lines.push("  No cache information available");
 		} else {
 			for (const cache of status.cache) {
 				lines.push(`  Language: ${cache.language}`);
-				lines.push(`    Exists: ${cache.exists ? "✅ Yes" : "❌ No"}`);
+				lines.push(`    Exists: ${this.formatYesNo(cache.exists, theme)}`);
 				if (cache.exists) {
-					lines.push(`    Expired: ${cache.isExpired ? "⚠️  Yes" : "✅ No"}`);
+					lines.push(
+						`    Expired: ${cache.isExpired ? theme.warning("⚠️  Yes") : theme.success("✅ No")}`,
+					);
 					if (cache.ageMs !== undefined) {
-						lines.push(`    Age: ${this.formatDuration(cache.ageMs)}`);
+						const age = formatRelativeAge(cache.ageMs, wording.locale);
+						lines.push(`    ${wording.updated(age)}`);
 					}
 					if (cache.sizeBytes !== undefined) {
-						lines.push(`    Size: ${this.formatFileSize(cache.sizeBytes)}`);
+						lines.push(`    Size: ${formatFileSize(cache.sizeBytes)}`);
 					}
 					if (cache.commandCount !== undefined) {
-						lines.push(`    Commands: ${cache.commandCount}`);
+						const count = formatCount(cache.commandCount, wording.locale);
+						lines.push(`    ${wording.commands(count)}`);
 					}
 				}
-				lines.push(`    Path: ${cache.path}`);
+				lines.push(`    Path: ${theme.dim(cache.path)}`);
 				lines.push("");
 			}
 		}
 
 		// Installation Directories
-		lines.push("Installation Directories:");
+		lines.push(theme.heading("Installation Directories:"));
 		if (status.installations.length === 0) {
 			lines.push("  No installation directories found");
 		} else {
@@ -113,22 +487,47 @@ export class StatusFormatter {
 				lines.push(
 					`  ${install.type.charAt(0).toUpperCase() + install.type.slice(1)} Directory:`,
 				);
-				lines.push(`    Exists: ${install.exists ? "✅ Yes" : "❌ No"}`);
+				lines.push(`    Exists: ${this.formatYesNo(install.exists, theme)}`);
 				if (install.exists) {
-					lines.push(`    Writable: ${install.writable ? "✅ Yes" : "❌ No"}`);
-					lines.push(`    Commands Installed: ${install.commandCount}`);
+					lines.push(
+						`    Writable: ${this.formatYesNo(install.writable, theme)}`,
+					);
+					const count = formatCount(install.commandCount, wording.locale);
+					lines.push(`    ${wording.commandsInstalled(count)}`);
+					const namespaces = Object.entries(install.namespaces ?? {});
+					if (namespaces.length > 0) {
+						lines.push(
+							`    By Namespace: ${namespaces.map(([namespace, count]) => `${namespace} (${count})`).join(", ")}`,
+						);
+					}
+				}
+				lines.push(`    Path: ${theme.dim(install.path)}`);
+				if (install.projectRoot) {
+					lines.push(`    Project Root: ${install.projectRoot}`);
 				}
-				lines.push(`    Path: ${install.path}`);
 				lines.push("");
 			}
 		}
 
+		// Installed commands the repository does not list
+		if (status.untracked && status.untracked.length > 0) {
+			lines.push(theme.heading("Untracked Commands:"));
+			for (const command of status.untracked) {
+				lines.push(
+					`  ${command.name} (${command.reason === "removed-upstream" ? "removed upstream" : "local only"})`,
+				);
+			}
+			lines.push("");
+		}
+
 		return lines.join("\n").trim();
 	}
 
 	/**
 	 * Format status in compact format for quick scanning
 	 *
+	 * Locale-neutral like the porcelain output: English words, plain digits.
+	 *
 	 * @param status - System status data
 	 * @returns Compact formatted status string
 	 */
@@ -138,6 +537,7 @@ export class StatusFormatter {
 		// One-line summary
 		const healthIcon = this.getHealthIcon(status.health.status);
 		lines.push(`Status: ${healthIcon} ${status.health.status.toUpperCase()}`);
+		lines.push(`Score: ${status.health.score}`);
 
 		// Cache summary
 		const validCaches = status.cache.filter(
@@ -156,9 +556,13 @@ export class StatusFormatter {
 			0,
 		);
 		lines.push(
-			`Installs: ${writableInstalls}/${totalInstalls} writable, ${totalCommands} commands`,
+			`Installs: ${writableInstalls}/${totalInstalls} writable, ${formatQuantity(totalCommands, "command", "commands")}`,
 		);
 
+		if (status.untracked && status.untracked.length > 0) {
+			lines.push(`Untracked: ${status.untracked.length}`);
+		}
+
 		// Warnings if any
 		if (status.health.messages.length > 0) {
 			lines.push(`Warnings: ${status.health.messages.length}`);
@@ -177,6 +581,13 @@ export class StatusFormatter {
 		return JSON.stringify(status, null, 2);
 	}
 
+	/**
+	 * Format a check result as a green "✅ Yes" or a red "❌ No"
+	 */
+	private formatYesNo(value: boolean, theme: Theme): string {
+		return value ? theme.success("✅ Yes") : theme.error("❌ No");
+	}
+
 	/**
 	 * Get appropriate icon for health status
 	 *
@@ -195,47 +606,4 @@ export class StatusFormatter {
 				return "❓";
 		}
 	}
-
-	/**
-	 * Format duration in human-readable format
-	 *
-	 * @param ms - Duration in milliseconds
-	 * @returns Formatted duration string
-	 */
-	private formatDuration(ms: number): string {
-		const seconds = Math.floor(ms / 1000);
-		const minutes = Math.floor(seconds / 60);
-		const hours = Math.floor(minutes / 60);
-		const days = Math.floor(hours / 24);
-
-		if (days > 0) {
-			return `${days}d ${hours % 24}h`;
-		}
-		if (hours > 0) {
-			return `${hours}h ${minutes % 60}m`;
-		}
-		if (minutes > 0) {
-			return `${minutes}m ${seconds % 60}s`;
-		}
-		return `${seconds}s`;
-	}
-
-	/**
-	 * Format file size in human-readable format
-	 *
-	 * @param bytes - Size in bytes
-	 * @returns Formatted size string
-	 */
-	private formatFileSize(bytes: number): string {
-		const units = ["B", "KB", "MB", "GB"];
-		let size = bytes;
-		let unitIndex = 0;
-
-		while (size >= 1024 && unitIndex < units.length - 1) {
-			size /= 1024;
-			unitIndex++;
-		}
-
-		return `${size.toFixed(unitIndex === 0 ? 0 : 1)} ${units[unitIndex]}`;
-	}
 }
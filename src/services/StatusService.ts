@@ -1,17 +1,64 @@
 import * as path from "node:path";
+import type ICacheManager from "../interfaces/ICacheManager.js";
+import type IClock from "../interfaces/IClock.js";
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
 import type IFileService from "../interfaces/IFileService.js";
 import type {
+	AllLanguagesStatus,
 	CacheInfo,
+	CacheUsage,
+	DiskUsage,
+	HealthIssue,
+	HealthRecommendation,
 	InstallationInfo,
+	InstallationUsage,
+	LanguageCacheStatus,
 	SystemHealth,
 	SystemStatus,
+	UntrackedCommand,
 } from "../types/Status.js";
 import { StatusError } from "../types/Status.js";
-import type { CacheManager } from "./CacheManager.js";
+import { findEmptyDirectories } from "../utils/emptyDirectories.js";
+import { formatDuration } from "../utils/format.js";
+import { extractNamespaceFromPath } from "../utils/namespace.js";
+import { compareStrings } from "../utils/ordering.js";
+import { SnapshotCache } from "../utils/snapshot.js";
 import type { ConfigManager } from "./ConfigManager.js";
-import type { DirectoryDetector } from "./DirectoryDetector.js";
+import type { ContentCache } from "./ContentCache.js";
 import type { LanguageDetector } from "./LanguageDetector.js";
 import type { LocalCommandRepository } from "./LocalCommandRepository.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Order of recommendation severities, most serious first
+ */
+const SEVERITY_RANK: Record<HealthRecommendation["severity"], number> = {
+	error: 0,
+	warning: 1,
+	info: 2,
+};
+
+/**
+ * Health score lost per recommendation of each severity
+ */
+const SCORE_PENALTY: Record<HealthRecommendation["severity"], number> = {
+	error: 40,
+	warning: 15,
+	info: 5,
+};
+
+/**
+ * Score health from 0 to 100 by the recommendations made
+ */
+export function scoreHealth(
+	recommendations: readonly HealthRecommendation[],
+): number {
+	const penalty = recommendations.reduce(
+		(sum, { severity }) => sum + SCORE_PENALTY[severity],
+		0,
+	);
+	return Math.max(0, 100 - penalty);
+}
 
 /**
  * Service for collecting comprehensive system status information
@@ -24,8 +71,19 @@ import type { LocalCommandRepository } from "./LocalCommandRepository.js";
  * - Installation directory analysis (locations, accessibility, command counts)
  * - System health indicators with diagnostic messages
  * - Comprehensive error handling with graceful degradation
+ *
+ * Each status is recomputed on every call unless options.snapshotTtlMs is
+ * set, in which case it is served from a snapshot for that long, so that a
+ * long-running caller can poll cheaply. Either way, concurrent calls share
+ * one computation. Call invalidate() after changing installed commands or
+ * caches to make the next call recompute.
  */
 export class StatusService {
+	private readonly systemSnapshot: SnapshotCache<SystemStatus>;
+	private readonly checkedSystemSnapshot: SnapshotCache<SystemStatus>;
+	private readonly languagesSnapshot: SnapshotCache<AllLanguagesStatus>;
+	private readonly repositoryProbe?: (language: string) => Promise<unknown>;
+
 	/**
 	 * Create a new StatusService instance
 	 *
@@ -35,38 +93,107 @@ export class StatusService {
 	 * @param localCommandRepository - Repository for local command analysis
 	 * @param languageDetector - Language detector for language support
 	 * @param configManager - Config manager for effective language detection
+	 * @param clock - Clock used for status timestamps and cache age
+	 * @param contentCache - Cache of command files, reported per language
+	 * @param options.snapshotTtlMs - How long a collected status is served
+	 *   before it is recomputed (default: 0, always recompute)
+	 * @param options.repositoryProbe - Fetches from the repository, bypassing
+	 *   caches, to tell whether it is reachable (see getSystemStatus())
 	 */
 	constructor(
 		private readonly fileService: IFileService,
-		private readonly cacheManager: CacheManager,
-		private readonly directoryDetector: DirectoryDetector,
+		private readonly cacheManager: ICacheManager,
+		private readonly directoryDetector: IDirectoryDetector,
 		private readonly localCommandRepository: LocalCommandRepository,
 		private readonly languageDetector: LanguageDetector,
 		private readonly configManager: ConfigManager,
-	) {}
+		private readonly clock: IClock = new SystemClock(),
+		private readonly contentCache?: ContentCache,
+		options: {
+			snapshotTtlMs?: number;
+			repositoryProbe?: (language: string) => Promise<unknown>;
+		} = {},
+	) {
+		const ttlMs = options.snapshotTtlMs ?? 0;
+		this.systemSnapshot = new SnapshotCache(clock, ttlMs);
+		this.checkedSystemSnapshot = new SnapshotCache(clock, ttlMs);
+		this.languagesSnapshot = new SnapshotCache(clock, ttlMs);
+		this.repositoryProbe = options.repositoryProbe;
+	}
+
+	/**
+	 * Drop status snapshots so the next calls collect fresh status
+	 */
+	invalidate(): void {
+		this.systemSnapshot.invalidate();
+		this.checkedSystemSnapshot.invalidate();
+		this.languagesSnapshot.invalidate();
+	}
 
 	/**
 	 * Collect complete system status information
 	 *
+	 * Only local state is consulted unless checkRepository is set: the
+	 * repository is then asked for the effective language's manifest, and
+	 * health reports whether it answered.
+	 *
+	 * @param options.checkRepository - Also check the repository is reachable
 	 * @returns Promise resolving to comprehensive system status
 	 * @throws StatusError if critical status collection fails
 	 */
-	async getSystemStatus(): Promise<SystemStatus> {
+	getSystemStatus(
+		options: { checkRepository?: boolean } = {},
+	): Promise<SystemStatus> {
+		const checkRepository =
+			options.checkRepository === true && this.repositoryProbe !== undefined;
+		return (
+			checkRepository ? this.checkedSystemSnapshot : this.systemSnapshot
+		).get(() => this.collectSystemStatus(checkRepository));
+	}
+
+	private async collectSystemStatus(
+		checkRepository: boolean,
+	): Promise<SystemStatus> {
 		try {
-			const timestamp = Date.now();
+			const timestamp = this.clock.now();
+			const language = await this.configManager.getEffectiveLanguage();
 
 			// Collect status information in parallel for better performance
-			const [cache, installations, health] = await Promise.all([
-				this.collectCacheStatus(),
-				this.collectInstallationStatus(),
-				this.assessSystemHealth(),
-			]);
+			const [cache, installations, health, untracked, repositoryError] =
+				await Promise.all([
+					this.collectCacheStatus(),
+					this.collectInstallationStatus(),
+					this.assessSystemHealth(),
+					this.findUntrackedCommands().catch(() => null),
+					checkRepository ? this.probeRepository(language) : undefined,
+				]);
+
+			const recommendations = this.recommend({
+				language,
+				cache,
+				installations,
+				health,
+				untracked,
+				repositoryError,
+			});
 
 			return {
 				timestamp,
 				cache,
 				installations,
-				health,
+				...(untracked ? { untracked } : {}),
+				health: {
+					...health,
+					...(checkRepository
+						? { repositoryReachable: repositoryError === null }
+						: {}),
+					score: scoreHealth(recommendations),
+					recommendations,
+					messages: [
+						...health.messages,
+						...this.describeEmptyDirectories(installations),
+					],
+				},
 			};
 		} catch (error) {
 			throw new StatusError(
@@ -76,6 +203,236 @@ export class StatusService {
 		}
 	}
 
+	/**
+	 * Find installed commands that are not in the cached manifest
+	 *
+	 * A command counts as removed upstream when its file was fetched from the
+	 * repository before (it is in the content cache), and as local-only
+	 * otherwise. Language variants installed with "add --also" (name-fr) are
+	 * tracked through their base command when a manifest or command files of
+	 * their language are cached. Only cached data is consulted; no network
+	 * requests are made.
+	 *
+	 * @returns Untracked commands sorted by name, or null when no manifest is
+	 *   cached for the effective language
+	 */
+	async findUntrackedCommands(): Promise<UntrackedCommand[] | null> {
+		const language = await this.configManager.getEffectiveLanguage();
+		const manifest = await this.cacheManager.get(language);
+		if (!manifest) {
+			return null;
+		}
+
+		const upstream = new Set(manifest.commands.map((command) => command.name));
+		// Variants carry the code of a language fetched for them, so only those
+		// suffixes count: "x-api" is not a variant of "x"
+		const variantLanguages = new Set([
+			...(await this.cacheManager.listLanguages()),
+			...((await this.contentCache?.listLanguages()) ?? []),
+		]);
+		const isTracked = (name: string): boolean => {
+			if (upstream.has(name)) {
+				return true;
+			}
+			for (const variantLanguage of variantLanguages) {
+				const suffix = `-${variantLanguage}`;
+				if (
+					name.endsWith(suffix) &&
+					upstream.has(name.slice(0, -suffix.length))
+				) {
+					return true;
+				}
+			}
+			return false;
+		};
+
+		const installed = await this.localCommandRepository.getManifest(language);
+		const untracked: UntrackedCommand[] = [];
+		for (const command of installed.commands) {
+			if (isTracked(command.name)) {
+				continue;
+			}
+			const fetched = await this.contentCache?.get(language, command.file);
+			untracked.push({
+				name: command.name,
+				file: command.file,
+				reason: fetched ? "removed-upstream" : "local-only",
+			});
+		}
+
+		return untracked.sort((a, b) => compareStrings(a.name, b.name));
+	}
+
+	/**
+	 * Summarize the caches of every language, not only the effective one
+	 *
+	 * Languages are those with a cached manifest or cached command files.
+	 * The effective language is always included, even when nothing is cached.
+	 *
+	 * @returns Per-language counts, sizes and ages
+	 * @throws StatusError if the caches cannot be enumerated
+	 */
+	getAllLanguagesStatus(): Promise<AllLanguagesStatus> {
+		return this.languagesSnapshot.get(() => this.collectAllLanguagesStatus());
+	}
+
+	private async collectAllLanguagesStatus(): Promise<AllLanguagesStatus> {
+		try {
+			const timestamp = this.clock.now();
+			const activeLanguage = await this.configManager.getEffectiveLanguage();
+
+			const languages = new Set([
+				activeLanguage,
+				...(await this.cacheManager.listLanguages()),
+				...((await this.contentCache?.listLanguages()) ?? []),
+			]);
+
+			const statuses: LanguageCacheStatus[] = [];
+			for (const language of [...languages].sort(compareStrings)) {
+				statuses.push(
+					await this.summarizeLanguage(language, language === activeLanguage),
+				);
+			}
+
+			return { timestamp, activeLanguage, languages: statuses };
+		} catch (error) {
+			throw new StatusError(
+				"Failed to collect language cache status",
+				error instanceof Error ? error : new Error(String(error)),
+			);
+		}
+	}
+
+	/**
+	 * Measure the disk space used by caches and installed commands
+	 *
+	 * Cache usage covers every language with a cached manifest or cached
+	 * command files; installations cover the project and personal commands
+	 * directories, broken down by namespace.
+	 *
+	 * @returns Sizes in bytes
+	 * @throws StatusError if the caches cannot be enumerated
+	 */
+	async getDiskUsage(): Promise<DiskUsage> {
+		try {
+			const timestamp = this.clock.now();
+			const languages = new Set([
+				...(await this.cacheManager.listLanguages()),
+				...((await this.contentCache?.listLanguages()) ?? []),
+			]);
+
+			const cache: CacheUsage[] = [];
+			for (const language of [...languages].sort(compareStrings)) {
+				const manifestBytes =
+					(await this.analyzeCacheForLanguage(language)).sizeBytes ?? 0;
+				const filesBytes =
+					(await this.contentCache?.getStats(language))?.sizeBytes ?? 0;
+				cache.push({
+					language,
+					manifestBytes,
+					filesBytes,
+					sizeBytes: manifestBytes + filesBytes,
+				});
+			}
+
+			const installations: InstallationUsage[] = [];
+			const projectDir = await this.directoryDetector.getProjectDirectory();
+			if (projectDir) {
+				installations.push(
+					await this.measureInstallationDirectory(projectDir, "project"),
+				);
+			}
+			installations.push(
+				await this.measureInstallationDirectory(
+					await this.directoryDetector.getPersonalDirectory(),
+					"user",
+				),
+			);
+
+			const totalBytes = [...cache, ...installations].reduce(
+				(sum, usage) => sum + usage.sizeBytes,
+				0,
+			);
+			return { timestamp, cache, installations, totalBytes };
+		} catch (error) {
+			throw new StatusError(
+				"Failed to measure disk usage",
+				error instanceof Error ? error : new Error(String(error)),
+			);
+		}
+	}
+
+	/**
+	 * Measure the command files of a commands directory
+	 */
+	private async measureInstallationDirectory(
+		dirPath: string,
+		type: "project" | "user",
+	): Promise<InstallationUsage> {
+		let commandCount = 0;
+		let sizeBytes = 0;
+		const namespaces = new Map<string, number>();
+
+		if (await this.fileService.exists(dirPath)) {
+			for (const filePath of await this.directoryDetector.scanForCommandFiles(
+				dirPath,
+			)) {
+				try {
+					const { namespace } = extractNamespaceFromPath(
+						path.relative(dirPath, filePath),
+					);
+					const bytes = Buffer.byteLength(
+						await this.fileService.readFile(filePath),
+						"utf8",
+					);
+					commandCount++;
+					sizeBytes += bytes;
+					if (namespace) {
+						namespaces.set(namespace, (namespaces.get(namespace) ?? 0) + bytes);
+					}
+				} catch {
+					// Unsafe names are not installed commands; vanished files are gone
+				}
+			}
+		}
+
+		return {
+			type,
+			path: dirPath,
+			commandCount,
+			sizeBytes,
+			namespaces: Object.fromEntries(
+				[...namespaces].sort(([a], [b]) => compareStrings(a, b)),
+			),
+		};
+	}
+
+	private async summarizeLanguage(
+		language: string,
+		active: boolean,
+	): Promise<LanguageCacheStatus> {
+		const manifest = await this.analyzeCacheForLanguage(language);
+		const files = (await this.contentCache?.getStats(language)) ?? {
+			fileCount: 0,
+			sizeBytes: 0,
+		};
+
+		return {
+			language,
+			active,
+			hasManifest: manifest.exists,
+			isExpired: manifest.isExpired,
+			commandCount: manifest.commandCount,
+			manifestAgeMs: manifest.ageMs,
+			cachedFiles: files.fileCount,
+			sizeBytes: (manifest.sizeBytes ?? 0) + files.sizeBytes,
+			filesAgeMs:
+				files.newestCachedAt === undefined
+					? undefined
+					: this.clock.now() - files.newestCachedAt,
+		};
+	}
+
 	/**
 	 * Collect cache status information for all existing cached languages
 	 *
@@ -224,7 +581,7 @@ export class StatusService {
 				// Parse timestamp from cache entry to calculate age
 				const parsed = JSON.parse(content);
 				if (parsed && typeof parsed.timestamp === "number") {
-					ageMs = Date.now() - parsed.timestamp;
+					ageMs = this.clock.now() - parsed.timestamp;
 				}
 			} catch {
 				// Continue without file stats if they can't be determined
@@ -263,12 +620,14 @@ export class StatusService {
 		const installations: InstallationInfo[] = [];
 
 		try {
-			// Check project-specific directory
-			const projectDir = await this.directoryDetector.getProjectDirectory();
-			if (projectDir) {
+			// Check the project directory and those of enclosing projects
+			const directories = await this.directoryDetector.getClaudeDirectories();
+			for (const dir of directories) {
+				if (dir.type !== "project") continue;
 				const projectInfo = await this.analyzeInstallationDirectory(
-					projectDir,
+					dir.path,
 					"project",
+					dir.projectRoot,
 				);
 				installations.push(projectInfo);
 			}
@@ -296,15 +655,19 @@ export class StatusService {
 	 *
 	 * @param dirPath - Directory path to analyze
 	 * @param type - Directory type (project or user)
+	 * @param projectRoot - Root of the project the directory belongs to
 	 * @returns Promise resolving to installation information
 	 */
 	private async analyzeInstallationDirectory(
 		dirPath: string,
 		type: "project" | "user",
+		projectRoot?: string,
 	): Promise<InstallationInfo> {
 		const exists = await this.fileService.exists(dirPath);
 		let writable = false;
 		let commandCount = 0;
+		let namespaces: Record<string, number> = {};
+		let emptyDirectories: string[] = [];
 
 		if (exists) {
 			try {
@@ -320,8 +683,9 @@ export class StatusService {
 				} catch {
 					// If we can't get commands, at least try to count files
 					try {
-						const files = await this.fileService.listFilesRecursive(dirPath);
-						commandCount = files.filter((file) => file.endsWith(".md")).length;
+						const files =
+							await this.directoryDetector.scanForCommandFiles(dirPath);
+						commandCount = files.length;
 					} catch {
 						// Leave commandCount as 0 if we can't determine it
 					}
@@ -329,6 +693,22 @@ export class StatusService {
 			} catch {
 				// Continue with defaults if checks fail
 			}
+
+			try {
+				namespaces = await this.countNamespaces(dirPath);
+			} catch {
+				// Leave the breakdown empty if the directory cannot be scanned
+			}
+
+			try {
+				emptyDirectories = await findEmptyDirectories(
+					this.fileService,
+					dirPath,
+					await this.directoryDetector.getScanOptions(),
+				);
+			} catch {
+				// Leave the list empty if the directory cannot be scanned
+			}
 		}
 
 		return {
@@ -337,15 +717,182 @@ export class StatusService {
 			exists,
 			writable,
 			commandCount,
+			namespaces,
+			emptyDirectories,
+			...(projectRoot ? { projectRoot } : {}),
 		};
 	}
 
+	/**
+	 * Count the command files of each namespace in a commands directory
+	 *
+	 * @returns Counts by namespace, sorted by namespace
+	 */
+	private async countNamespaces(
+		dirPath: string,
+	): Promise<Record<string, number>> {
+		const counts = new Map<string, number>();
+		for (const filePath of await this.directoryDetector.scanForCommandFiles(
+			dirPath,
+		)) {
+			try {
+				const { namespace } = extractNamespaceFromPath(
+					path.relative(dirPath, filePath),
+				);
+				if (namespace) {
+					counts.set(namespace, (counts.get(namespace) ?? 0) + 1);
+				}
+			} catch {
+				// Files with unsafe names are not installed commands
+			}
+		}
+		return Object.fromEntries(
+			[...counts].sort(([a], [b]) => compareStrings(a, b)),
+		);
+	}
+
+	/**
+	 * Ask the repository for a manifest, bypassing caches
+	 *
+	 * @returns null if the repository answered, otherwise why it did not
+	 */
+	private async probeRepository(language: string): Promise<string | null> {
+		try {
+			await this.repositoryProbe?.(language);
+			return null;
+		} catch (error) {
+			return error instanceof Error ? error.message : String(error);
+		}
+	}
+
+	/**
+	 * Turn collected status into recommendations, most important first
+	 */
+	private recommend(status: {
+		language: string;
+		cache: readonly CacheInfo[];
+		installations: readonly InstallationInfo[];
+		health: Pick<SystemHealth, "cacheAccessible" | "installationPossible">;
+		untracked: readonly UntrackedCommand[] | null;
+		repositoryError?: string | null;
+	}): HealthRecommendation[] {
+		const recommendations: HealthRecommendation[] = [];
+		const add = (
+			issue: HealthIssue,
+			severity: HealthRecommendation["severity"],
+			problem: string,
+			action: string,
+		) => recommendations.push({ issue, severity, problem, action });
+
+		if (!status.health.cacheAccessible) {
+			add(
+				"cache-not-writable",
+				"error",
+				"The cache directory is not writable",
+				`Fix the permissions of ${path.dirname(path.dirname(this.cacheManager.getCachePath(status.language)))} or set CLAUDE_CMD_CACHE_DIR to a writable directory`,
+			);
+		}
+		if (!status.health.installationPossible) {
+			add(
+				"no-writable-directory",
+				"error",
+				"No installation directory is writable, so commands cannot be installed",
+				"Run 'claude-cmd init' to create your commands directory, or fix its permissions",
+			);
+		}
+		if (status.repositoryError) {
+			add(
+				"repository-unreachable",
+				"error",
+				`The repository is unreachable: ${status.repositoryError}`,
+				"Check your network connection and 'claude-cmd config get repositoryURL'; cached commands keep working meanwhile",
+			);
+		}
+		for (const install of status.installations) {
+			if (install.exists && !install.writable) {
+				add(
+					"directory-not-writable",
+					"warning",
+					`The ${install.type === "user" ? "personal" : "project"} commands directory is not writable`,
+					`Fix the permissions of ${install.path}`,
+				);
+			}
+		}
+
+		const cached = status.cache.find(
+			(info) => info.language === status.language && info.exists,
+		);
+		if (!cached) {
+			add(
+				"cache-missing",
+				"warning",
+				`No manifest is cached for ${status.language}, so listing and searching need the network`,
+				"Run 'claude-cmd cache update'",
+			);
+		} else if (cached.isExpired) {
+			add(
+				"cache-stale",
+				"warning",
+				cached.ageMs !== undefined
+					? `The cached manifest for ${status.language} is ${formatDuration(cached.ageMs)} old`
+					: `The cached manifest for ${status.language} is expired`,
+				"Run 'claude-cmd cache update'",
+			);
+		}
+
+		const removed = (status.untracked ?? []).filter(
+			(command) => command.reason === "removed-upstream",
+		);
+		if (removed.length > 0) {
+			add(
+				"removed-upstream",
+				"warning",
+				`${removed.length} installed command(s) no longer exist upstream: ${removed.map((command) => command.name).join(", ")}`,
+				"Keep them as local commands, or run 'claude-cmd remove <name>' for those you no longer need",
+			);
+		}
+
+		for (const install of status.installations) {
+			if ((install.emptyDirectories?.length ?? 0) > 0) {
+				add(
+					"empty-directories",
+					"info",
+					`Empty namespace directories in ${install.path}: ${install.emptyDirectories?.join(", ")}`,
+					"Delete them; they are left over from removed commands",
+				);
+			}
+		}
+
+		return recommendations.sort(
+			(a, b) => SEVERITY_RANK[a.severity] - SEVERITY_RANK[b.severity],
+		);
+	}
+
+	/**
+	 * Describe stray empty namespace directories as health warnings
+	 *
+	 * @param installations - Analyzed installation directories
+	 * @returns One message per directory that has empty namespaces
+	 */
+	private describeEmptyDirectories(
+		installations: readonly InstallationInfo[],
+	): string[] {
+		return installations
+			.filter((install) => (install.emptyDirectories?.length ?? 0) > 0)
+			.map(
+				(install) =>
+					`Empty namespace directories in ${install.path}: ${install.emptyDirectories?.join(", ")} (safe to delete)`,
+			);
+	}
+
 	/**
 	 * Assess overall system health
 	 *
 	 * @returns Promise resolving to system health information
 	 */
-	private async assessSystemHealth(): Promise<SystemHealth> {
+	private async assessSystemHealth(): Promise<
+		Omit<SystemHealth, "score" | "recommendations">
+	> {
 		const messages: string[] = [];
 		let cacheAccessible = true;
 		let installationPossible = false;
@@ -0,0 +1,10 @@
+import type IClock from "../interfaces/IClock.js";
+
+/**
+ * Real clock backed by Date.now()
+ */
+export default class SystemClock implements IClock {
+	now(): number {
+		return Date.now();
+	}
+}
@@ -0,0 +1,181 @@
+import type IClock from "../interfaces/IClock.js";
+import type { Config } from "../interfaces/IConfigService.js";
+import type IHTTPClient from "../interfaces/IHTTPClient.js";
+import type {
+	HTTPOptions,
+	HTTPResponse,
+	HTTPStreamResponse,
+} from "../interfaces/IHTTPClient.js";
+import { httpLogger } from "../utils/logger.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Politeness limits for outgoing HTTP requests (unset means unlimited)
+ */
+export interface HTTPLimits {
+	/** Maximum number of requests started per second */
+	readonly maxRequestsPerSecond?: number;
+	/** Maximum number of requests in flight at once */
+	readonly maxParallelDownloads?: number;
+	/** Maximum average download rate in bytes per second */
+	readonly maxBytesPerSecond?: number;
+}
+
+/**
+ * Read HTTP limits from the effective configuration
+ *
+ * @param config - Effective configuration
+ * @returns Limits set in the configuration
+ */
+export function httpLimitsFromConfig(config: Config): HTTPLimits {
+	return {
+		maxRequestsPerSecond: config.maxRequestsPerSecond,
+		maxParallelDownloads: config.maxParallelDownloads,
+		maxBytesPerSecond: config.maxBytesPerSecond,
+	};
+}
+
+/**
+ * HTTP client that spaces out requests to stay within configured limits
+ *
+ * Wraps another client and delays requests so that batch operations such as
+ * prefetching or bundle export do not trip rate limiters on artifact servers
+ * or saturate slow links:
+ * - maxParallelDownloads caps requests in flight, regardless of how many
+ *   callers run concurrently;
+ * - maxRequestsPerSecond spaces request starts evenly;
+ * - maxBytesPerSecond delays the next request until the bytes already
+ *   received fit the budget. The cap applies to the average rate over a
+ *   batch rather than to each transfer.
+ *
+ * A streamed request holds its slot until its body has been read.
+ *
+ * With no limits set, requests pass through unchanged.
+ *
+ * @example
+ * ```typescript
+ * const client = new ThrottledHTTPClient(new BunHTTPClient(), {
+ *   maxRequestsPerSecond: 5,
+ *   maxParallelDownloads: 2,
+ * });
+ * ```
+ */
+export class ThrottledHTTPClient implements IHTTPClient {
+	private active = 0;
+	private readonly waiting: Array<() => void> = [];
+	private nextStartAt = 0;
+	private bandwidthFreeAt = 0;
+
+	/**
+	 * @param client - Client performing the actual requests
+	 * @param limits - Limits to enforce
+	 * @param clock - Clock used for scheduling (defaults to system time)
+	 * @param sleep - Waits the given number of milliseconds
+	 */
+	constructor(
+		private readonly client: IHTTPClient,
+		private readonly limits: HTTPLimits,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly sleep: (ms: number) => Promise<void> = (ms) =>
+			new Promise((resolve) => setTimeout(resolve, ms)),
+	) {}
+
+	async get(url: string, options?: HTTPOptions): Promise<HTTPResponse> {
+		await this.acquireSlot();
+		try {
+			await this.waitForTurn(url);
+			const response = await this.client.get(url, options);
+			this.consumeBandwidth(Buffer.byteLength(response.body));
+			return response;
+		} finally {
+			this.releaseSlot();
+		}
+	}
+
+	async stream(
+		url: string,
+		options?: HTTPOptions,
+	): Promise<HTTPStreamResponse> {
+		await this.acquireSlot();
+		let response: HTTPStreamResponse;
+		try {
+			await this.waitForTurn(url);
+			response = await this.client.stream(url, options);
+		} catch (error) {
+			this.releaseSlot();
+			throw error;
+		}
+		return { ...response, body: this.meter(response.body) };
+	}
+
+	/**
+	 * Count a streamed body against the bandwidth budget as it is read, and
+	 * free the request's slot once reading stops
+	 */
+	private async *meter(
+		body: AsyncIterable<Uint8Array>,
+	): AsyncGenerator<Uint8Array> {
+		try {
+			for await (const chunk of body) {
+				this.consumeBandwidth(chunk.byteLength);
+				yield chunk;
+			}
+		} finally {
+			this.releaseSlot();
+		}
+	}
+
+	/**
+	 * Wait until fewer than maxParallelDownloads requests are in flight
+	 */
+	private async acquireSlot(): Promise<void> {
+		const max = this.limits.maxParallelDownloads;
+		if (max !== undefined && this.active >= max) {
+			await new Promise<void>((resolve) => this.waiting.push(resolve));
+			// The releasing request handed its slot over; active is unchanged
+			return;
+		}
+		this.active++;
+	}
+
+	private releaseSlot(): void {
+		const next = this.waiting.shift();
+		if (next) {
+			next();
+		} else {
+			this.active--;
+		}
+	}
+
+	/**
+	 * Wait for the request rate and bandwidth budgets
+	 *
+	 * The start time is reserved before sleeping so concurrent callers queue
+	 * behind each other instead of all waking at once.
+	 */
+	private async waitForTurn(url: string): Promise<void> {
+		const now = this.clock.now();
+		let startAt = Math.max(now, this.bandwidthFreeAt);
+
+		const rate = this.limits.maxRequestsPerSecond;
+		if (rate !== undefined) {
+			startAt = Math.max(startAt, this.nextStartAt);
+			this.nextStartAt = startAt + 1000 / rate;
+		}
+
+		const delay = startAt - now;
+		if (delay > 0) {
+			httpLogger.trace("throttling {url} for {delay}ms", { url, delay });
+			await this.sleep(delay);
+		}
+	}
+
+	private consumeBandwidth(bytes: number): void {
+		const cap = this.limits.maxBytesPerSecond;
+		if (cap === undefined) {
+			return;
+		}
+		const from = Math.max(this.clock.now(), this.bandwidthFreeAt);
+		this.bandwidthFreeAt = from + (bytes * 1000) / cap;
+	}
+}
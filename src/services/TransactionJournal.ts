@@ -0,0 +1,319 @@
+import { randomBytes } from "node:crypto";
+import * as os from "node:os";
+import * as path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { isProcessAlive } from "../utils/fileLock.js";
+import { fileLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Current journal file format version
+ */
+export const JOURNAL_VERSION = 1;
+
+/**
+ * A file change that is part of a transaction
+ */
+export type JournalOperation =
+	| { readonly type: "write"; readonly path: string; readonly content: string }
+	| { readonly type: "delete"; readonly path: string };
+
+/**
+ * A recorded operation together with what it replaces
+ */
+interface JournalStep {
+	readonly operation: JournalOperation;
+	/** Content before the transaction, or null if the file did not exist */
+	readonly previous: string | null;
+}
+
+/**
+ * On-disk journal document
+ */
+interface JournalFile {
+	version: number;
+	id: string;
+	name: string;
+	startedAt: number;
+	pid: number;
+	hostname: string;
+	steps: JournalStep[];
+}
+
+/**
+ * A transaction that did not finish, found by a later invocation
+ */
+export interface PendingTransaction {
+	/** Transaction id */
+	readonly id: string;
+	/** What was being done (e.g., "bundle import (en)") */
+	readonly name: string;
+	/** When the transaction started (milliseconds since Unix epoch) */
+	readonly startedAt: number;
+	/** Paths the transaction changes, in order */
+	readonly paths: readonly string[];
+}
+
+/**
+ * Error thrown when a pending transaction cannot be found or is malformed
+ */
+export class TransactionError extends Error {
+	constructor(
+		message: string,
+		public readonly transactionId?: string,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Apply one file operation; deleting a missing file is not an error
+ *
+ * Used by the journal, and directly where no journal is configured.
+ */
+export async function applyJournalOperation(
+	fileService: IFileService,
+	operation: JournalOperation,
+): Promise<void> {
+	if (operation.type === "write") {
+		await writeFileAtomic(fileService, operation.path, operation.content);
+		return;
+	}
+
+	try {
+		await fileService.deleteFile(operation.path);
+	} catch (error) {
+		if (!(error instanceof FileNotFoundError)) {
+			throw error;
+		}
+	}
+}
+
+/**
+ * Intent log that makes multi-file changes all-or-nothing
+ *
+ * Before touching any file, run() records every planned operation and the
+ * content it replaces in a journal file, written atomically. The journal is
+ * removed once all operations are applied. If the process dies in between,
+ * the journal survives and a later invocation can either roll the change
+ * back (restore the recorded content) or complete it (apply the remaining
+ * operations again; every operation is idempotent).
+ *
+ * Journals of processes that are still running on this host are not
+ * reported, so concurrent invocations do not recover each other's work.
+ */
+export class TransactionJournal {
+	/**
+	 * @param fileService - File service for journal and target file I/O
+	 * @param journalDir - Directory holding journal files
+	 * @param clock - Clock used to stamp transactions (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly journalDir: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Apply file operations as one transaction
+	 *
+	 * @param name - Human-readable description shown during recovery
+	 * @param operations - Operations to apply, in order
+	 * @throws Errors of the underlying file operations; the journal is kept
+	 *   so the transaction can be recovered
+	 */
+	async run(
+		name: string,
+		operations: readonly JournalOperation[],
+	): Promise<void> {
+		const steps: JournalStep[] = [];
+		for (const operation of operations) {
+			steps.push({
+				operation,
+				previous: await this.readIfExists(operation.path),
+			});
+		}
+
+		const journal: JournalFile = {
+			version: JOURNAL_VERSION,
+			id: `${this.clock.now()}-${randomBytes(4).toString("hex")}`,
+			name,
+			startedAt: this.clock.now(),
+			pid: process.pid,
+			hostname: os.hostname(),
+			steps,
+		};
+		const journalPath = this.getJournalPath(journal.id);
+
+		await writeFileAtomic(
+			this.fileService,
+			journalPath,
+			JSON.stringify(journal, null, 2),
+		);
+		fileLogger.debug("transaction started: {name} ({count} operations)", {
+			name,
+			count: steps.length,
+		});
+
+		for (const step of steps) {
+			await this.apply(step.operation);
+		}
+
+		await this.fileService.deleteFile(journalPath);
+		fileLogger.debug("transaction committed: {name}", { name });
+	}
+
+	/**
+	 * List transactions left behind by interrupted invocations
+	 *
+	 * @returns Pending transactions, oldest first
+	 */
+	async listPending(): Promise<PendingTransaction[]> {
+		let files: string[];
+		try {
+			files = await this.fileService.listFiles(this.journalDir);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return [];
+			}
+			throw error;
+		}
+
+		const pending: PendingTransaction[] = [];
+		for (const file of files.filter((name) => name.endsWith(".json"))) {
+			const journal = await this.load(path.basename(file, ".json"));
+			if (!journal || this.isRunning(journal)) {
+				continue;
+			}
+			pending.push({
+				id: journal.id,
+				name: journal.name,
+				startedAt: journal.startedAt,
+				paths: journal.steps.map((step) => step.operation.path),
+			});
+		}
+
+		return pending.sort(
+			(a, b) => a.startedAt - b.startedAt || compareStrings(a.id, b.id),
+		);
+	}
+
+	/**
+	 * Undo an interrupted transaction, restoring every file it touched
+	 *
+	 * @param id - Transaction id from listPending()
+	 * @throws TransactionError if the transaction is unknown
+	 */
+	async rollback(id: string): Promise<void> {
+		const journal = await this.require(id);
+
+		for (const step of [...journal.steps].reverse()) {
+			if (step.previous === null) {
+				await this.apply({ type: "delete", path: step.operation.path });
+			} else {
+				await this.apply({
+					type: "write",
+					path: step.operation.path,
+					content: step.previous,
+				});
+			}
+		}
+
+		await this.fileService.deleteFile(this.getJournalPath(id));
+		fileLogger.info("transaction rolled back: {name}", {
+			name: journal.name,
+		});
+	}
+
+	/**
+	 * Finish an interrupted transaction by applying all of its operations
+	 *
+	 * @param id - Transaction id from listPending()
+	 * @throws TransactionError if the transaction is unknown
+	 */
+	async complete(id: string): Promise<void> {
+		const journal = await this.require(id);
+
+		for (const step of journal.steps) {
+			await this.apply(step.operation);
+		}
+
+		await this.fileService.deleteFile(this.getJournalPath(id));
+		fileLogger.info("transaction completed: {name}", {
+			name: journal.name,
+		});
+	}
+
+	private async apply(operation: JournalOperation): Promise<void> {
+		await applyJournalOperation(this.fileService, operation);
+	}
+
+	private async readIfExists(filePath: string): Promise<string | null> {
+		try {
+			return await this.fileService.readFile(filePath);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return null;
+			}
+			throw error;
+		}
+	}
+
+	private isRunning(journal: JournalFile): boolean {
+		return (
+			journal.pid !== process.pid &&
+			journal.hostname === os.hostname() &&
+			isProcessAlive(journal.pid)
+		);
+	}
+
+	private async require(id: string): Promise<JournalFile> {
+		const journal = await this.load(id);
+		if (!journal) {
+			throw new TransactionError(`No pending transaction '${id}'`, id);
+		}
+		return journal;
+	}
+
+	private async load(id: string): Promise<JournalFile | null> {
+		if (!/^[\w-]+$/.test(id)) {
+			return null;
+		}
+
+		let content: string;
+		try {
+			content = await this.fileService.readFile(this.getJournalPath(id));
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return null;
+			}
+			throw error;
+		}
+
+		try {
+			const parsed = JSON.parse(content);
+			if (
+				parsed?.version !== JOURNAL_VERSION ||
+				!Array.isArray(parsed.steps)
+			) {
+				throw new Error("unexpected structure");
+			}
+			return parsed as JournalFile;
+		} catch (error) {
+			fileLogger.warn("ignoring unreadable journal: {id} ({error})", {
+				id,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return null;
+		}
+	}
+
+	private getJournalPath(id: string): string {
+		return path.join(this.journalDir, `${id}.json`);
+	}
+}
@@ -0,0 +1,233 @@
+import { randomBytes } from "node:crypto";
+import * as path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import type { TrashEntry } from "../types/Installation.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { fileLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+import type { LockEntry } from "./InstallLockfile.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Current trash entry format version
+ */
+export const TRASH_VERSION = 1;
+
+/**
+ * How long removed commands are kept before pruning (30 days)
+ */
+export const TRASH_RETENTION_MS = 30 * 24 * 60 * 60 * 1000;
+
+/**
+ * Maximum number of removed commands kept
+ */
+export const MAX_TRASH_ENTRIES = 100;
+
+/**
+ * On-disk trash entry: metadata plus the removed file's content and
+ * lockfile entry
+ */
+interface TrashFile extends TrashEntry {
+	version: number;
+	content: string;
+	lock?: LockEntry;
+}
+
+/**
+ * Recycle bin for removed command files
+ *
+ * Each removed command is stored as one JSON file holding its content and
+ * where it came from. Entries older than TRASH_RETENTION_MS, and the oldest
+ * beyond MAX_TRASH_ENTRIES, are pruned whenever something is trashed.
+ */
+export class TrashService {
+	/**
+	 * @param fileService - File service for trash I/O
+	 * @param trashDir - Directory holding trash entries
+	 * @param clock - Clock used to stamp and prune entries (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly trashDir: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Get the trash directory
+	 */
+	getTrashDir(): string {
+		return this.trashDir;
+	}
+
+	/**
+	 * Keep a command file that is about to be deleted
+	 *
+	 * @param entry - Command name, location and path of the file
+	 * @param content - Content of the file
+	 * @param lock - Lockfile entry of the command, if it has one
+	 * @returns The stored entry
+	 */
+	async put(
+		entry: Omit<TrashEntry, "id" | "trashedAt">,
+		content: string,
+		lock?: LockEntry,
+	): Promise<TrashEntry> {
+		const trashedAt = this.clock.now();
+		const file: TrashFile = {
+			version: TRASH_VERSION,
+			id: `${trashedAt}-${randomBytes(4).toString("hex")}`,
+			name: entry.name,
+			location: entry.location,
+			originalPath: entry.originalPath,
+			trashedAt,
+			content,
+			...(lock ? { lock } : {}),
+		};
+		await writeFileAtomic(
+			this.fileService,
+			this.getEntryPath(file.id),
+			JSON.stringify(file, null, 2),
+		);
+		await this.prune();
+
+		return toEntry(file);
+	}
+
+	/**
+	 * List removed commands, most recently removed first
+	 */
+	async list(): Promise<TrashEntry[]> {
+		return (await this.loadAll()).map(toEntry);
+	}
+
+	/**
+	 * Find the most recently removed version of a command
+	 *
+	 * The entry stays in the trash; delete() it once the command has been
+	 * written back.
+	 *
+	 * @param name - Command name as it was installed
+	 * @param location - Only consider commands removed from this location
+	 * @returns The entry, its content and lockfile entry, or null if the
+	 *   command is not in the trash
+	 */
+	async find(
+		name: string,
+		location?: "personal" | "project",
+	): Promise<{ entry: TrashEntry; content: string; lock?: LockEntry } | null> {
+		const file = (await this.loadAll()).find(
+			(candidate) =>
+				candidate.name === name &&
+				(location === undefined || candidate.location === location),
+		);
+		if (!file) {
+			return null;
+		}
+		return { entry: toEntry(file), content: file.content, lock: file.lock };
+	}
+
+	/**
+	 * Delete an entry from the trash
+	 *
+	 * @param id - Entry ID; deleting a missing entry is not an error
+	 */
+	async delete(id: string): Promise<void> {
+		await this.deleteEntry(id);
+	}
+
+	/**
+	 * Delete expired and excess entries
+	 *
+	 * @returns Number of entries deleted
+	 */
+	async prune(): Promise<number> {
+		const cutoff = this.clock.now() - TRASH_RETENTION_MS;
+		const files = await this.loadAll();
+		const expired = files.filter(
+			(file, index) => file.trashedAt < cutoff || index >= MAX_TRASH_ENTRIES,
+		);
+		for (const file of expired) {
+			await this.deleteEntry(file.id);
+		}
+		if (expired.length > 0) {
+			fileLogger.debug("pruned {count} trash entries", {
+				count: expired.length,
+			});
+		}
+		return expired.length;
+	}
+
+	private async loadAll(): Promise<TrashFile[]> {
+		let names: string[];
+		try {
+			names = await this.fileService.listFiles(this.trashDir);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return [];
+			}
+			throw error;
+		}
+
+		const files: TrashFile[] = [];
+		for (const name of names.filter((file) => file.endsWith(".json"))) {
+			const file = await this.load(path.basename(name, ".json"));
+			if (file) {
+				files.push(file);
+			}
+		}
+		return files.sort(
+			(a, b) => b.trashedAt - a.trashedAt || compareStrings(b.id, a.id),
+		);
+	}
+
+	private async load(id: string): Promise<TrashFile | null> {
+		try {
+			const parsed = JSON.parse(
+				await this.fileService.readFile(this.getEntryPath(id)),
+			);
+			if (
+				parsed?.version !== TRASH_VERSION ||
+				typeof parsed.name !== "string" ||
+				typeof parsed.content !== "string"
+			) {
+				throw new Error("unexpected structure");
+			}
+			return parsed as TrashFile;
+		} catch (error) {
+			fileLogger.warn("ignoring unreadable trash entry: {id} ({error})", {
+				id,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return null;
+		}
+	}
+
+	private async deleteEntry(id: string): Promise<void> {
+		try {
+			await this.fileService.deleteFile(this.getEntryPath(id));
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				throw error;
+			}
+		}
+	}
+
+	private getEntryPath(id: string): string {
+		return path.join(this.trashDir, `${id}.json`);
+	}
+}
+
+/**
+ * Strip the content and format version from a trash file
+ */
+function toEntry(file: TrashFile): TrashEntry {
+	return {
+		id: file.id,
+		name: file.name,
+		location: file.location,
+		originalPath: file.originalPath,
+		trashedAt: file.trashedAt,
+	};
+}
@@ -0,0 +1,183 @@
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { httpLogger } from "../utils/logger.js";
+import { compareVersions } from "../utils/versions.js";
+import type { SelfUpdateService } from "./SelfUpdateService.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * How long a looked up latest release is trusted before asking GitHub again
+ */
+export const UPDATE_CHECK_TTL_MS = 24 * 60 * 60 * 1000;
+
+/**
+ * How long to wait after a failed lookup before trying again
+ */
+export const UPDATE_CHECK_RETRY_MS = 60 * 60 * 1000;
+
+/**
+ * On-disk record of the last release lookup
+ */
+interface UpdateCheckFile {
+	/** When the latest release was looked up (milliseconds since Unix epoch) */
+	checkedAt: number;
+	/** Version of the latest release, if any lookup has succeeded */
+	latestVersion?: string;
+	/** Whether the lookup at checkedAt failed */
+	failed?: boolean;
+}
+
+/**
+ * Tells whether a newer claude-cmd release exists without slowing commands
+ *
+ * Answers come from a small file in the cache directory and never wait for
+ * the network. When the file is older than the TTL a lookup starts in the
+ * background and its result is recorded for the next run; failed lookups
+ * are recorded too and retried after a shorter delay, so an offline
+ * machine does not ask on every run. Network and file errors are logged
+ * and treated as "no news".
+ */
+export class UpdateNotifier {
+	private pending: Promise<unknown> = Promise.resolve();
+
+	/**
+	 * @param selfUpdateService - Service looking up the latest release
+	 * @param fileService - File service for the state file
+	 * @param statePath - Path of the state file
+	 * @param clock - Clock used for the TTL (defaults to system time)
+	 * @param ttlMs - How long a successful lookup is trusted
+	 * @param retryMs - How long a failed lookup is trusted
+	 */
+	constructor(
+		private readonly selfUpdateService: SelfUpdateService,
+		private readonly fileService: IFileService,
+		private readonly statePath: string,
+		private readonly clock: IClock = new SystemClock(),
+		private readonly ttlMs: number = UPDATE_CHECK_TTL_MS,
+		private readonly retryMs: number = UPDATE_CHECK_RETRY_MS,
+	) {}
+
+	/**
+	 * Get the latest recorded release version if it is newer than the
+	 * running one
+	 *
+	 * When the record is stale a lookup is started but not waited for; see
+	 * whenRefreshed().
+	 *
+	 * @param currentVersion - Version of the running claude-cmd
+	 * @param timeoutMs - Longest a background lookup may take before it is
+	 *   aborted and recorded as failed
+	 * @returns The newer version, or undefined if there is none or it is unknown
+	 */
+	async getNewerVersion(
+		currentVersion: string,
+		timeoutMs: number,
+	): Promise<string | undefined> {
+		const state = await this.load();
+		if (this.isStale(state)) {
+			this.pending = this.refresh(
+				currentVersion,
+				AbortSignal.timeout(timeoutMs),
+				state?.latestVersion,
+			);
+		}
+
+		const latestVersion = state?.latestVersion;
+		return latestVersion && compareVersions(latestVersion, currentVersion) > 0
+			? latestVersion
+			: undefined;
+	}
+
+	/**
+	 * Wait for a lookup started by getNewerVersion() to be recorded
+	 */
+	async whenRefreshed(): Promise<void> {
+		await this.pending;
+	}
+
+	/**
+	 * Remember the latest release version, e.g. after self-update looked it up
+	 */
+	async record(latestVersion: string): Promise<void> {
+		await this.save({ checkedAt: this.clock.now(), latestVersion });
+	}
+
+	private isStale(state: UpdateCheckFile | undefined): boolean {
+		if (!state) {
+			return true;
+		}
+		const ttlMs = state.failed ? this.retryMs : this.ttlMs;
+		return this.clock.now() - state.checkedAt >= ttlMs;
+	}
+
+	/**
+	 * Look up the latest release and record the outcome
+	 *
+	 * @param signal - Aborts the lookup
+	 * @param knownVersion - Latest version recorded so far, kept on failure
+	 */
+	private async refresh(
+		currentVersion: string,
+		signal: AbortSignal,
+		knownVersion: string | undefined,
+	): Promise<void> {
+		try {
+			const { version } = await this.selfUpdateService.check(
+				currentVersion,
+				process.platform,
+				process.arch,
+				signal,
+			);
+			await this.record(version);
+		} catch (error) {
+			httpLogger.debug("update check failed: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
+			await this.save({
+				checkedAt: this.clock.now(),
+				...(knownVersion ? { latestVersion: knownVersion } : {}),
+				failed: true,
+			});
+		}
+	}
+
+	private async save(state: UpdateCheckFile): Promise<void> {
+		try {
+			await writeFileAtomic(
+				this.fileService,
+				this.statePath,
+				JSON.stringify(state, null, 2),
+			);
+		} catch (error) {
+			httpLogger.debug("failed to record update check: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	/**
+	 * Read the state file, treating a missing or malformed file as absent
+	 */
+	private async load(): Promise<UpdateCheckFile | undefined> {
+		try {
+			const content = await this.fileService.readFile(this.statePath);
+			const parsed = JSON.parse(content);
+			if (
+				typeof parsed?.checkedAt === "number" &&
+				(parsed.latestVersion === undefined ||
+					typeof parsed.latestVersion === "string")
+			) {
+				return parsed;
+			}
+		} catch (error) {
+			if (!(error instanceof FileNotFoundError)) {
+				httpLogger.debug("ignoring unreadable update check: {error}", {
+					error: error instanceof Error ? error.message : String(error),
+				});
+			}
+		}
+		return undefined;
+	}
+}
@@ -0,0 +1,191 @@
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import type IUsageStatsService from "../interfaces/IUsageStatsService.js";
+import type {
+	CommandUsage,
+	UsageEvent,
+} from "../interfaces/IUsageStatsService.js";
+import { writeFileAtomic } from "../utils/atomicWrite.js";
+import { withFileLock } from "../utils/fileLock.js";
+import { installLogger } from "../utils/logger.js";
+import { compareStrings } from "../utils/ordering.js";
+import SystemClock from "./SystemClock.js";
+
+/**
+ * Current stats file format version
+ */
+export const USAGE_STATS_VERSION = 1;
+
+/**
+ * Number of history events kept in the stats file
+ */
+export const MAX_HISTORY_ENTRIES = 200;
+
+/**
+ * On-disk stats document
+ */
+interface UsageStatsFile {
+	version: number;
+	commands: Record<string, Omit<CommandUsage, "command">>;
+	history: UsageEvent[];
+}
+
+/**
+ * Local, telemetry-free record of install activity
+ *
+ * Counts installs and removals per command and keeps a bounded history in a
+ * JSON file next to the user configuration. Nothing leaves the machine.
+ * Recording never fails the operation being recorded: errors are logged and
+ * the event is dropped.
+ */
+export class UsageStatsService implements IUsageStatsService {
+	/**
+	 * @param fileService - File service for stats I/O
+	 * @param statsPath - Path of the stats file
+	 * @param clock - Clock used to stamp events (defaults to system time)
+	 */
+	constructor(
+		private readonly fileService: IFileService,
+		private readonly statsPath: string,
+		private readonly clock: IClock = new SystemClock(),
+	) {}
+
+	/**
+	 * Get the path of the stats file
+	 */
+	getStatsPath(): string {
+		return this.statsPath;
+	}
+
+	/**
+	 * Record an install or removal
+	 *
+	 * @param event - What happened, without a timestamp
+	 */
+	async record(event: Omit<UsageEvent, "at">): Promise<void> {
+		const stamped: UsageEvent = { ...event, at: this.clock.now() };
+
+		try {
+			await withFileLock(
+				this.fileService,
+				`${this.statsPath}.lock`,
+				async () => {
+					const stats = await this.load();
+					const current = stats.commands[stamped.command] ?? {
+						installs: 0,
+						removals: 0,
+						lastUsedAt: stamped.at,
+					};
+					stats.commands[stamped.command] = {
+						installs: current.installs + (stamped.action === "install" ? 1 : 0),
+						removals: current.removals + (stamped.action === "remove" ? 1 : 0),
+						lastUsedAt: stamped.at,
+					};
+					stats.history = [...stats.history, stamped].slice(
+						-MAX_HISTORY_ENTRIES,
+					);
+					await writeFileAtomic(
+						this.fileService,
+						this.statsPath,
+						JSON.stringify(stats, null, 2),
+					);
+				},
+				{ clock: this.clock },
+			);
+		} catch (error) {
+			installLogger.warn("failed to record usage stats: {error}", {
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+
+	/**
+	 * Get per-command statistics, most installed first
+	 *
+	 * Ties are broken by most recent use, then by name.
+	 */
+	async getCommandUsage(): Promise<CommandUsage[]> {
+		const stats = await this.load();
+		return Object.entries(stats.commands)
+			.map(([command, usage]) => ({ command, ...usage }))
+			.sort(
+				(a, b) =>
+					b.installs - a.installs ||
+					b.lastUsedAt - a.lastUsedAt ||
+					compareStrings(a.command, b.command),
+			);
+	}
+
+	/**
+	 * Get recorded events, most recent first
+	 *
+	 * @param limit - Maximum number of events to return
+	 */
+	async getHistory(limit?: number): Promise<UsageEvent[]> {
+		const { history } = await this.load();
+		const newestFirst = [...history].reverse();
+		return limit === undefined ? newestFirst : newestFirst.slice(0, limit);
+	}
+
+	/**
+	 * Delete all recorded statistics
+	 *
+	 * @returns True if a stats file was removed
+	 */
+	async clear(): Promise<boolean> {
+		try {
+			await this.fileService.deleteFile(this.statsPath);
+			return true;
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return false;
+			}
+			throw error;
+		}
+	}
+
+	/**
+	 * Read the stats file, treating a missing or unreadable file as empty
+	 */
+	private async load(): Promise<UsageStatsFile> {
+		const empty: UsageStatsFile = {
+			version: USAGE_STATS_VERSION,
+			commands: {},
+			history: [],
+		};
+
+		let content: string;
+		try {
+			content = await this.fileService.readFile(this.statsPath);
+		} catch (error) {
+			if (error instanceof FileNotFoundError) {
+				return empty;
+			}
+			throw error;
+		}
+
+		try {
+			const parsed = JSON.parse(content);
+			if (
+				typeof parsed?.commands !== "object" ||
+				parsed.commands === null ||
+				!Array.isArray(parsed.history)
+			) {
+				throw new Error("unexpected structure");
+			}
+			return {
+				version: USAGE_STATS_VERSION,
+				commands: parsed.commands,
+				history: parsed.history,
+			};
+		} catch (error) {
+			// Stats are a convenience; start over rather than block installs
+			installLogger.warn("ignoring unreadable stats file: {path} ({error})", {
+				path: this.statsPath,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			return empty;
+		}
+	}
+}
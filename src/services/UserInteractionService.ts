@@ -2,12 +2,16 @@ import { stdin, stdout } from "node:process";
 import type { Interface as ReadlineInterface } from "node:readline";
 import { createInterface } from "node:readline";
 import type IUserInteractionService from "../interfaces/IUserInteractionService.js";
-import type { ConfirmationOptions } from "../interfaces/IUserInteractionService.js";
+import type {
+	ChoiceOptions,
+	ConfirmationOptions,
+	TextPromptOptions,
+} from "../interfaces/IUserInteractionService.js";
 import { interactionLogger } from "../utils/logger.js";
 
 /**
  * Service for handling interactive user prompts in terminal environments
- * Supports confirmation prompts with --yes flag bypassing
+ * Supports confirmation prompts with --yes flag bypassing and choice prompts
  */
 export class UserInteractionService implements IUserInteractionService {
 	private yesMode = false;
@@ -133,4 +137,83 @@ export class UserInteractionService implements IUserInteractionService {
 			rl.close();
 		}
 	}
+
+	/**
+	 * Ask the user to type an answer
+	 * Returns the default without prompting in --yes mode or when not interactive
+	 */
+	async askText(options: TextPromptOptions): Promise<string> {
+		if (this.yesMode || !this.shouldPrompt()) {
+			return options.defaultValue;
+		}
+
+		const rl = this.createReadlineInterface();
+
+		try {
+			const hint = options.defaultValue ? ` [${options.defaultValue}]` : "";
+			const answer = (
+				await this.askQuestion(rl, `${options.message}${hint}: `)
+			).trim();
+			interactionLogger.debug("askText: user response: {answer}", { answer });
+			return answer || options.defaultValue;
+		} catch (error) {
+			// Handle interruption gracefully
+			if (error instanceof Error && error.message.includes("interrupt")) {
+				return options.defaultValue;
+			}
+			throw error;
+		} finally {
+			rl.close();
+		}
+	}
+
+	/**
+	 * Ask the user to pick one of several answers
+	 * Returns undefined without prompting when not in interactive mode
+	 */
+	async chooseOption<T extends string>(
+		options: ChoiceOptions<T>,
+	): Promise<T | undefined> {
+		if (!this.shouldPrompt()) {
+			return undefined;
+		}
+
+		const rl = this.createReadlineInterface();
+
+		try {
+			const menu = options.choices
+				.map((choice) => `[${choice.key}] ${choice.label}`)
+				.join(", ");
+			const prompt = `${options.message}\n  ${menu}: `;
+
+			while (true) {
+				try {
+					const answer = (await this.askQuestion(rl, prompt))
+						.trim()
+						.toLowerCase();
+					const choice = options.choices.find(
+						(c) => c.key === answer || c.value === answer,
+					);
+					if (choice) {
+						interactionLogger.debug("chooseOption: user response: {value}", {
+							value: choice.value,
+						});
+						return choice.value;
+					}
+
+					// Invalid input - prompt again
+					const keys = options.choices.map((c) => `'${c.key}'`).join(", ");
+					stdout.write(`Please enter one of ${keys}.\n`);
+				} catch (error) {
+					// Handle interruption gracefully
+					if (error instanceof Error && error.message.includes("interrupt")) {
+						return undefined;
+					}
+					throw error;
+				}
+			}
+		} finally {
+			rl.close();
+		}
+	}
 }
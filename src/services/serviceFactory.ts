@@ -1,7 +1,27 @@
 import * as os from "node:os";
 import * as path from "node:path";
+import type IClock from "../interfaces/IClock.js";
+import type IDirectoryDetector from "../interfaces/IDirectoryDetector.js";
+import type IFileService from "../interfaces/IFileService.js";
+import type IGitClient from "../interfaces/IGitClient.js";
+import type { Config } from "../interfaces/IConfigService.js";
+import type IHTTPClient from "../interfaces/IHTTPClient.js";
+import type IRepository from "../interfaces/IRepository.js";
+import { CacheConfig } from "../interfaces/IRepository.js";
+import type IStatusFormatter from "../interfaces/IStatusFormatter.js";
+import type IUsageStatsService from "../interfaces/IUsageStatsService.js";
+import { readConfiguredDirectory, resolveCacheDir } from "../utils/cacheDir.js";
+import { resolveClaudeDir } from "../utils/claudeDir.js";
+import { resolveProjectRoots } from "../utils/projectRoot.js";
+import { repositoryPrefixFor } from "../utils/repositorySource.js";
+import {
+	BACKOFF_STATE_FILE,
+	BackoffHTTPClient,
+} from "./BackoffHTTPClient.js";
 import BunFileService from "./BunFileService.js";
+import BunGitClient from "./BunGitClient.js";
 import BunHTTPClient from "./BunHTTPClient.js";
+import { BundleService } from "./BundleService.js";
 import { CacheManager } from "./CacheManager.js";
 import { ChangeDisplayFormatter } from "./ChangeDisplayFormatter.js";
 import { CommandCacheService } from "./CommandCacheService.js";
@@ -12,202 +32,580 @@ import { CommandParser } from "./CommandParser.js";
 import { CommandQueryService } from "./CommandQueryService.js";
 import { ConfigManager } from "./ConfigManager.js";
 import { ConfigService } from "./ConfigService.js";
+import { ConfiguredRepository } from "./ConfiguredRepository.js";
+import { ContentCache } from "./ContentCache.js";
+import { ContentCachingRepository } from "./ContentCachingRepository.js";
+import { ContentSearchIndex } from "./ContentSearchIndex.js";
 import { DirectoryDetector } from "./DirectoryDetector.js";
+import { EventBus } from "./EventBus.js";
+import { FallbackRepository } from "./FallbackRepository.js";
+import FileSystemRepository from "./FileSystemRepository.js";
+import GitRepository from "./GitRepository.js";
 import HTTPRepository from "./HTTPRepository.js";
 import { InstallationService } from "./InstallationService.js";
+import { InstallLockfile } from "./InstallLockfile.js";
 import { LanguageDetector } from "./LanguageDetector.js";
+import { LanguageFallbackRepository } from "./LanguageFallbackRepository.js";
 import { LocalCommandRepository } from "./LocalCommandRepository.js";
 import { ManifestComparison } from "./ManifestComparison.js";
+import { MultiRepository } from "./MultiRepository.js";
 import NamespaceService from "./NamespaceService.js";
+import { OperationHistory } from "./OperationHistory.js";
+import { RemoteCommandSource } from "./RemoteCommandSource.js";
+import {
+	configForRepository,
+	listConfiguredRepositories,
+	RepositoryRegistry,
+} from "./RepositoryRegistry.js";
+import { SelfUpdateService } from "./SelfUpdateService.js";
 import { StatusFormatter } from "./StatusFormatter.js";
 import { StatusService } from "./StatusService.js";
+import SystemClock from "./SystemClock.js";
+import {
+	httpLimitsFromConfig,
+	ThrottledHTTPClient,
+} from "./ThrottledHTTPClient.js";
+import { TransactionJournal } from "./TransactionJournal.js";
+import { TrashService } from "./TrashService.js";
+import { UpdateNotifier } from "./UpdateNotifier.js";
+import { UsageStatsService } from "./UsageStatsService.js";
 import { UserInteractionService } from "./UserInteractionService.js";
 
 /**
  * Service factory that creates and manages singleton instances of core services.
  * Centralizes dependency injection setup to eliminate code duplication across CLI commands.
  *
- * This factory follows the singleton pattern to ensure services are instantiated only once,
- * improving performance and maintaining consistent state across command executions.
+ * The whole service graph is built once from a small set of platform
+ * dependencies (file system, HTTP, git, config locations). CLI commands only
+ * ever obtain services through getServices(), so substituting those
+ * dependencies via createServices()/setServices() swaps them consistently for
+ * every command.
  */
 
-// Create singleton instances of services
-let services: {
-	commandQueryService: CommandQueryService;
-	commandContentService: CommandContentService;
-	commandCacheService: CommandCacheService;
-	commandEnrichmentService: CommandEnrichmentService;
-	commandInstalledService: CommandInstalledService;
-	languageDetector: LanguageDetector;
-	installationService: InstallationService;
-	userConfigService: ConfigService;
-	projectConfigService: ConfigService;
-	configManager: ConfigManager;
-	localCommandRepository: LocalCommandRepository;
-	userInteractionService: UserInteractionService;
-	manifestComparison: ManifestComparison;
-	changeDisplayFormatter: ChangeDisplayFormatter;
-	statusService: StatusService;
-	statusFormatter: StatusFormatter;
-	cacheManager: CacheManager;
-	fileService: BunFileService;
-} | null = null;
+/**
+ * Platform dependencies the service graph is built from
+ */
+export interface CoreDependencies {
+	/** File system access for caches, configs and installed commands */
+	fileService: IFileService;
+	/** HTTP client for the default repository */
+	httpClient: IHTTPClient;
+	/** Git client for git repository sources */
+	gitClient: IGitClient;
+	/** Clock for cache TTLs, staleness checks and timestamps */
+	clock: IClock;
+	/** User configuration file path */
+	userConfigPath: string;
+	/** Project configuration file path */
+	projectConfigPath: string;
+	/** Directory whose .claude holds the project's commands and config */
+	projectRoot: string;
+	/** Roots of the projects enclosing projectRoot, nearest first */
+	outerProjectRoots: readonly string[];
+	/** Root of every cache (manifests, command files, history, trash) */
+	cacheDir: string;
+	/** User's Claude directory; personal commands live in its commands/ */
+	claudeDir: string;
+}
 
 /**
- * Initialize and return singleton service instances.
- * Services are created on first access and reused for subsequent calls.
+ * Create the production platform dependencies
  *
- * @returns Object containing configured service instances
+ * @returns Bun-backed clients, the default config file locations and the
+ *   cache directory (CLAUDE_CMD_CACHE_DIR, then configured cacheDir, then
+ *   the platform default) and the Claude directory (CLAUDE_CONFIG_DIR or
+ *   CLAUDE_HOME, then configured claudeDir, then ~/.claude)
+ * @param options.discoverProject - Search parent directories for the
+ *   project root (default: true; see resolveProjectRoot())
  */
-export function getServices() {
-	if (!services) {
-		// Initialize core dependencies
-		const fileService = new BunFileService();
-		const httpClient = new BunHTTPClient();
-		const repository = new HTTPRepository(httpClient, fileService);
-		const cacheManager = new CacheManager(fileService);
-		const languageDetector = new LanguageDetector();
-
-		// Initialize InstallationService dependencies
-		const directoryDetector = new DirectoryDetector(fileService);
-		const namespaceService = new NamespaceService();
-		const commandParser = new CommandParser(namespaceService);
-
-		// Create LocalCommandRepository for local command management
-		const localCommandRepository = new LocalCommandRepository(
-			directoryDetector,
-			commandParser,
-		);
-
-		// Create UserInteractionService
-		const userInteractionService = new UserInteractionService();
+export function createDefaultDependencies({
+	discoverProject = true,
+}: { discoverProject?: boolean } = {}): CoreDependencies {
+	const userConfigPath = path.join(
+		os.homedir(),
+		".config",
+		"claude-cmd",
+		"config.claude-cmd.json",
+	);
+	const [projectRoot = process.cwd(), ...outerProjectRoots] =
+		resolveProjectRoots(discoverProject);
+	const projectConfigPath = path.join(
+		path.relative(process.cwd(), projectRoot),
+		".claude",
+		"config.claude-cmd.json",
+	);
+	const configPaths = [projectConfigPath, userConfigPath];
 
-		// Create ManifestComparison service
-		const manifestComparison = new ManifestComparison();
+	return {
+		fileService: new BunFileService(),
+		httpClient: new BunHTTPClient(),
+		gitClient: new BunGitClient(),
+		clock: new SystemClock(),
+		userConfigPath,
+		projectConfigPath,
+		projectRoot,
+		outerProjectRoots,
+		cacheDir: resolveCacheDir(readConfiguredDirectory(configPaths, "cacheDir")),
+		claudeDir: resolveClaudeDir(
+			readConfiguredDirectory(configPaths, "claudeDir"),
+		),
+	};
+}
 
-		// Create ChangeDisplayFormatter service
-		const changeDisplayFormatter = new ChangeDisplayFormatter();
+/**
+ * Clients and parser repositories are built from
+ */
+type RepositoryDependencies = Pick<
+	CoreDependencies,
+	"fileService" | "httpClient" | "gitClient" | "clock" | "cacheDir"
+> & { commandParser: CommandParser };
 
-		// Create InstallationService with UserInteractionService dependency
-		const installationService = new InstallationService(
+/**
+ * Create the repository a configuration points at
+ *
+ * This is the single place repositoryURL, repositoryType and repositoryRef
+ * are interpreted, so every command reads from the same source:
+ * - git: clone/pull repositoryURL
+ * - file:// URL: read the directory in place
+ * - http(s) URL: HTTP repository rooted at repositoryURL
+ * - otherwise: the default HTTP repository
+ *
+ * With additionalRepositories, their commands are merged after the main
+ * repository's (see MultiRepository), except for those in
+ * disabledRepositories; repositoryPrefixes namespaces a repository's
+ * commands. manifestPublicKey verifies the main repository's manifests and
+ * repositoryPublicKeys those of additional repositories, whatever their type.
+ *
+ * @param config - Effective (project over user) configuration
+ * @param dependencies - Clients and parser the repository is built from
+ * @returns The repository to read commands from
+ */
+export function createRepositoryForConfig(
+	config: Config,
+	dependencies: RepositoryDependencies,
+): IRepository {
+	const repository = createSingleRepository(config, dependencies);
+	const [primary, ...additional] = listConfiguredRepositories(config).filter(
+		(entry) => entry.enabled,
+	);
+	const prefixes = config.repositoryPrefixes;
+	if (
+		!primary ||
+		(additional.length === 0 && !repositoryPrefixFor(prefixes, primary.url))
+	) {
+		return repository;
+	}
+	return new MultiRepository([
+		{
+			name: primary.url,
 			repository,
-			fileService,
-			directoryDetector,
-			commandParser,
-			localCommandRepository,
-			userInteractionService,
-		);
+			prefix: repositoryPrefixFor(prefixes, primary.url),
+		},
+		...additional.map((entry) => ({
+			name: entry.url,
+			repository: createSingleRepository(
+				configForRepository(config, entry),
+				dependencies,
+			),
+			prefix: repositoryPrefixFor(prefixes, entry.url),
+		})),
+	]);
+}
 
-		// Create ConfigService instances with shared LanguageDetector
-		const userConfigPath = path.join(
-			os.homedir(),
-			".config",
-			"claude-cmd",
-			"config.claude-cmd.json",
-		);
-		const projectConfigPath = path.join(".claude", "config.claude-cmd.json");
+/**
+ * Create the repository of a single repositoryURL
+ */
+function createSingleRepository(
+	config: Config,
+	dependencies: RepositoryDependencies,
+): IRepository {
+	const { fileService, httpClient, gitClient, clock, commandParser } =
+		dependencies;
+	const cacheConfig = new CacheConfig({ cacheDir: dependencies.cacheDir });
 
-		// First create ConfigService instances without ConfigManager
-		const userConfigService = new ConfigService(
-			userConfigPath,
-			fileService,
-			repository,
-			languageDetector,
+	if (config.repositoryType === "git" && config.repositoryURL) {
+		return new GitRepository(gitClient, fileService, commandParser, {
+			url: config.repositoryURL,
+			ref: config.repositoryRef,
+			cacheConfig,
+			clock,
+			manifestPublicKey: config.manifestPublicKey,
+		});
+	}
+	// file:// URLs are read in place unless explicitly cloned with git; the
+	// commands directory is found on first use
+	const fileURL = config.repositoryURL;
+	if (fileURL && FileSystemRepository.isFileURL(fileURL)) {
+		return new ConfiguredRepository(() =>
+			FileSystemRepository.fromFileURL(
+				fileService,
+				commandParser,
+				fileURL,
+				config.manifestPublicKey,
+			),
 		);
+	}
+	// Politeness limits from config apply to every request of the run; rate
+	// limits reported by the server are remembered across runs
+	return new HTTPRepository(
+		new ThrottledHTTPClient(
+			new BackoffHTTPClient(
+				httpClient,
+				fileService,
+				path.join(dependencies.cacheDir, BACKOFF_STATE_FILE),
+				clock,
+			),
+			httpLimitsFromConfig(config),
+			clock,
+		),
+		fileService,
+		cacheConfig,
+		clock,
+		config.repositoryURL && HTTPRepository.isHTTPURL(config.repositoryURL)
+			? config.repositoryURL
+			: undefined,
+		config.manifestPublicKey,
+		{
+			maxManifestBytes: config.maxManifestBytes,
+			maxCommandBytes: config.maxCommandBytes,
+		},
+	);
+}
 
-		const projectConfigService = new ConfigService(
-			projectConfigPath,
-			fileService,
-			repository,
-			languageDetector,
-		);
+/**
+ * Build the complete service graph
+ *
+ * @param overrides - Dependencies to use instead of the production defaults
+ * @returns Object containing configured service instances
+ */
+export function createServices(overrides: Partial<CoreDependencies> = {}) {
+	const {
+		fileService,
+		httpClient,
+		gitClient,
+		clock,
+		userConfigPath,
+		projectConfigPath,
+		projectRoot,
+		outerProjectRoots,
+		cacheDir,
+		claudeDir,
+	} = {
+		...createDefaultDependencies(),
+		// Enclosing projects were found for the discovered root, not this one
+		...(overrides.projectRoot ? { outerProjectRoots: [] } : {}),
+		...overrides,
+	};
 
-		// Create ConfigManager to orchestrate precedence
-		const configManager = new ConfigManager(
-			userConfigService,
-			projectConfigService,
-			languageDetector,
-		);
+	// Progress, installation and error events for --json-events and embedders
+	const eventBus = new EventBus();
 
-		// Now recreate userConfigService with ConfigManager for getLanguageStatus
-		const userConfigServiceWithManager = new ConfigService(
-			userConfigPath,
-			fileService,
-			repository,
-			languageDetector,
-			configManager,
-		);
+	const cacheManager = new CacheManager(
+		fileService,
+		path.join(cacheDir, "commands"),
+		clock,
+	);
+	const languageDetector = new LanguageDetector();
 
-		// Create specialized command services
-		const commandQueryService = new CommandQueryService(
-			repository,
-			cacheManager,
-			languageDetector,
-		);
+	// Initialize InstallationService dependencies
+	const directoryDetector: IDirectoryDetector = new DirectoryDetector(
+		fileService,
+		claudeDir,
+		projectRoot,
+		async () => {
+			const config = await configManager.getEffectiveConfig();
+			return { maxDepth: config.scanMaxDepth, ignore: config.scanIgnore };
+		},
+		outerProjectRoots,
+	);
+	const namespaceService = new NamespaceService();
+	const commandParser = new CommandParser(namespaceService);
 
-		const commandContentService = new CommandContentService(
-			repository,
-			languageDetector,
-			commandQueryService,
-		);
+	// Select the repository source from the effective configuration on first use
+	// (configManager is created below; the resolver only runs after setup)
+	const repositoryDependencies: RepositoryDependencies = {
+		fileService,
+		httpClient,
+		gitClient,
+		clock,
+		commandParser,
+		cacheDir,
+	};
+	const configuredRepository = new ConfiguredRepository(async () =>
+		createRepositoryForConfig(
+			await configManager.getEffectiveConfig(),
+			repositoryDependencies,
+		),
+	);
 
-		const commandCacheService = new CommandCacheService(
-			repository,
-			cacheManager,
-			languageDetector,
-			manifestComparison,
-		);
+	// Multi-file changes are journaled so an interrupted run can be recovered
+	const transactionJournal = new TransactionJournal(
+		fileService,
+		path.join(path.dirname(userConfigPath), "journal"),
+		clock,
+	);
 
-		const commandEnrichmentService = new CommandEnrichmentService(
-			commandQueryService,
-			localCommandRepository,
-			directoryDetector,
-			languageDetector,
-		);
+	// Imported offline bundles are served when the repository is unreachable
+	const bundlesDir = path.join(cacheDir, "bundles");
+	const bundleService = new BundleService(
+		configuredRepository,
+		fileService,
+		cacheManager,
+		bundlesDir,
+		clock,
+		transactionJournal,
+	);
+	// Fetched command files are kept so repeated installs and previews work offline
+	const contentCache = new ContentCache(fileService, cacheDir, clock);
+	// Untranslated manifests and commands are served in English unless strict
+	const repository = new LanguageFallbackRepository(
+		new ContentCachingRepository(
+			new FallbackRepository(
+				configuredRepository,
+				new FileSystemRepository(
+					fileService,
+					commandParser,
+					bundleService.getCommandsDir(),
+				),
+			),
+			contentCache,
+		),
+		async () =>
+			(await configManager.getEffectiveConfig()).strictLanguage === true,
+	);
 
-		const commandInstalledService = new CommandInstalledService(
-			installationService,
-			languageDetector,
-		);
+	// Create LocalCommandRepository for local command management
+	const localCommandRepository = new LocalCommandRepository(
+		directoryDetector,
+		commandParser,
+	);
 
-		// Create StatusService with all its dependencies
-		const statusService = new StatusService(
-			fileService,
-			cacheManager,
-			directoryDetector,
-			localCommandRepository,
-			languageDetector,
-			configManager,
-		);
+	// Create UserInteractionService
+	const userInteractionService = new UserInteractionService();
+
+	// Create ManifestComparison service
+	const manifestComparison = new ManifestComparison();
+
+	// Create ChangeDisplayFormatter service
+	const changeDisplayFormatter = new ChangeDisplayFormatter();
+
+	// Changes to installed commands are recorded so `undo` can revert them
+	const operationHistory = new OperationHistory(
+		fileService,
+		path.join(cacheDir, "history"),
+		clock,
+	);
+
+	// Removed commands are kept for a while so they can be restored
+	const trashService = new TrashService(
+		fileService,
+		path.join(cacheDir, "trash"),
+		clock,
+	);
+
+	// Create InstallationService with UserInteractionService dependency
+	const installationService = new InstallationService(
+		repository,
+		fileService,
+		directoryDetector,
+		commandParser,
+		localCommandRepository,
+		userInteractionService,
+		clock,
+		operationHistory,
+		trashService,
+		new InstallLockfile(fileService, clock),
+		eventBus,
+		transactionJournal,
+	);
+
+	// Create ConfigService instances with shared LanguageDetector
+	// First create ConfigService instances without ConfigManager
+	const userConfigService = new ConfigService(
+		userConfigPath,
+		fileService,
+		repository,
+		languageDetector,
+	);
+
+	const projectConfigService = new ConfigService(
+		projectConfigPath,
+		fileService,
+		repository,
+		languageDetector,
+	);
+
+	// Create ConfigManager to orchestrate precedence
+	const configManager = new ConfigManager(
+		userConfigService,
+		projectConfigService,
+		languageDetector,
+	);
+
+	// Now recreate userConfigService with ConfigManager for getLanguageStatus
+	const userConfigServiceWithManager = new ConfigService(
+		userConfigPath,
+		fileService,
+		repository,
+		languageDetector,
+		configManager,
+	);
+
+	// Create specialized command services
+	const contentSearchIndex = new ContentSearchIndex(fileService, cacheDir);
+	const commandQueryService = new CommandQueryService(
+		repository,
+		cacheManager,
+		languageDetector,
+		contentSearchIndex,
+		async () =>
+			Object.values(
+				(await configManager.getEffectiveConfig()).repositoryPrefixes ?? {},
+			),
+	);
+
+	const commandContentService = new CommandContentService(
+		repository,
+		languageDetector,
+		commandQueryService,
+	);
+
+	const commandCacheService = new CommandCacheService(
+		repository,
+		cacheManager,
+		languageDetector,
+		manifestComparison,
+		clock,
+		contentSearchIndex,
+	);
+
+	const commandEnrichmentService = new CommandEnrichmentService(
+		commandQueryService,
+		localCommandRepository,
+		directoryDetector,
+		languageDetector,
+	);
+
+	const commandInstalledService = new CommandInstalledService(
+		installationService,
+		languageDetector,
+	);
+
+	// Create StatusService with all its dependencies
+	const statusService = new StatusService(
+		fileService,
+		cacheManager,
+		directoryDetector,
+		localCommandRepository,
+		languageDetector,
+		configManager,
+		clock,
+		contentCache,
+		{
+			// The configured source itself: bundles and caches would hide an outage
+			repositoryProbe: (language) =>
+				configuredRepository.getManifest(language, { forceRefresh: true }),
+		},
+	);
+
+	// Create StatusFormatter (no dependencies)
+	const statusFormatter: IStatusFormatter = new StatusFormatter();
+
+	// Install statistics live next to the user configuration and never leave it
+	const usageStatsService: IUsageStatsService = new UsageStatsService(
+		fileService,
+		path.join(path.dirname(userConfigPath), "stats.json"),
+		clock,
+	);
+
+	// Releases are always read from GitHub, whatever repository is configured
+	const selfUpdateService = new SelfUpdateService(httpClient, fileService);
+	const updateNotifier = new UpdateNotifier(
+		selfUpdateService,
+		fileService,
+		path.join(cacheDir, "update-check.json"),
+		clock,
+	);
 
-		// Create StatusFormatter (no dependencies)
-		const statusFormatter = new StatusFormatter();
-
-		services = {
-			commandQueryService,
-			commandContentService,
-			commandCacheService,
-			commandEnrichmentService,
-			commandInstalledService,
-			languageDetector,
-			installationService,
-			userConfigService: userConfigServiceWithManager,
-			projectConfigService,
-			configManager,
-			localCommandRepository,
-			userInteractionService,
-			manifestComparison,
-			changeDisplayFormatter,
-			statusService,
-			statusFormatter,
-			cacheManager,
-			fileService,
-		};
+	// Each configured repository on its own, for `repo list`
+	const repositoryRegistry = new RepositoryRegistry(
+		() => configManager.getEffectiveConfig(),
+		(config) => createRepositoryForConfig(config, repositoryDependencies),
+	);
+
+	// Single command files shared by URL or gist (add --url)
+	const remoteCommandSource = new RemoteCommandSource(httpClient, fileService);
+
+	return {
+		eventBus,
+		commandQueryService,
+		commandContentService,
+		commandCacheService,
+		commandEnrichmentService,
+		commandInstalledService,
+		languageDetector,
+		installationService,
+		commandParser,
+		userConfigService: userConfigServiceWithManager,
+		projectConfigService,
+		configManager,
+		localCommandRepository,
+		userInteractionService,
+		manifestComparison,
+		changeDisplayFormatter,
+		statusService,
+		statusFormatter,
+		usageStatsService,
+		selfUpdateService,
+		updateNotifier,
+		remoteCommandSource,
+		repositoryRegistry,
+		transactionJournal,
+		operationHistory,
+		trashService,
+		cacheManager,
+		directoryDetector,
+		fileService,
+		bundleService,
+		repository,
+		clock,
+		cacheDir,
+		claudeDir,
+		projectRoot,
+	};
+}
+
+/**
+ * Service container type returned by createServices()
+ */
+export type Services = ReturnType<typeof createServices>;
+
+let services: Services | null = null;
+
+/**
+ * Initialize and return singleton service instances.
+ * Services are created on first access and reused for subsequent calls.
+ *
+ * @returns Object containing configured service instances
+ */
+export function getServices(): Services {
+	if (!services) {
+		services = createServices();
 	}
 
 	return services;
 }
 
+/**
+ * Install a service container for all subsequent getServices() calls
+ * (primarily for testing with substituted dependencies)
+ *
+ * @param container - Container built with createServices()
+ */
+export function setServices(container: Services): void {
+	services = container;
+}
+
 /**
  * Reset service instances (primarily for testing purposes)
  * Allows tests to start with fresh service instances
@@ -1,5 +1,10 @@
 import type { CommandServiceOptions } from "../../types/Command.js";
-import { CommandNotFoundError } from "../../types/Command.js";
+import {
+	AmbiguousCommandError,
+	CommandNotFoundError,
+} from "../../types/Command.js";
+import { validateCommandName as validateCanonicalCommandName } from
+	"../../utils/naming.js";
 import type { LanguageDetector } from "../LanguageDetector.js";
 import { CommandServiceError } from "./CommandServiceError.js";
 
@@ -40,6 +45,16 @@ export function validateCommandName(commandName: string): void {
 			"unknown",
 		);
 	}
+
+	try {
+		validateCanonicalCommandName(commandName);
+	} catch (error) {
+		throw new CommandServiceError(
+			error instanceof Error ? error.message : String(error),
+			"validation",
+			"unknown",
+		);
+	}
 }
 
 /**
@@ -76,6 +91,7 @@ export async function withErrorHandling<T>(
 	} catch (error) {
 		if (
 			error instanceof CommandNotFoundError ||
+			error instanceof AmbiguousCommandError ||
 			error instanceof CommandServiceError ||
 			(error instanceof Error && error.name === "ManifestError") ||
 			(error instanceof Error && error.name === "CommandContentError") ||
@@ -0,0 +1,122 @@
+import type {
+	RepositoryAbout,
+	RepositoryMaintainer,
+} from "../../types/Repository.js";
+import { repoLogger } from "../../utils/logger.js";
+
+/**
+ * Name of the metadata file, both at the commands root and per language
+ */
+export const ABOUT_FILE = "about.json";
+
+/**
+ * Load and merge about.json documents from most to least general
+ *
+ * Missing or malformed documents are skipped so that optional metadata can
+ * never break command listing.
+ *
+ * @param read - Reads one location, resolving to null when it does not exist
+ * @param locations - Locations ordered from repository-wide to per-language
+ * @returns Merged metadata, or null if no location had any
+ */
+export async function loadRepositoryAbout(
+	read: (location: string) => Promise<string | null>,
+	locations: readonly string[],
+): Promise<RepositoryAbout | null> {
+	let merged: RepositoryAbout | null = null;
+
+	for (const location of locations) {
+		let content: string | null;
+		try {
+			content = await read(location);
+		} catch (error) {
+			repoLogger.debug("about.json unavailable: {location} ({error})", {
+				location,
+				error: error instanceof Error ? error.message : String(error),
+			});
+			continue;
+		}
+		if (content === null) {
+			continue;
+		}
+
+		const about = parseRepositoryAbout(content);
+		if (!about) {
+			repoLogger.warn("ignoring malformed about.json: {location}", {
+				location,
+			});
+			continue;
+		}
+
+		merged = {
+			...merged,
+			...about,
+			...(merged?.maintainer || about.maintainer
+				? { maintainer: { ...merged?.maintainer, ...about.maintainer } }
+				: {}),
+		};
+	}
+
+	return merged;
+}
+
+/**
+ * Parse an about.json document, keeping only well-typed fields
+ *
+ * @param content - Raw JSON
+ * @returns Parsed metadata, or null if the content is not a JSON object
+ */
+export function parseRepositoryAbout(content: string): RepositoryAbout | null {
+	let parsed: unknown;
+	try {
+		parsed = JSON.parse(content);
+	} catch {
+		return null;
+	}
+	if (!isRecord(parsed)) {
+		return null;
+	}
+
+	const about: {
+		-readonly [K in keyof RepositoryAbout]: RepositoryAbout[K];
+	} = {};
+	for (const field of ["title", "description", "homepage"] as const) {
+		const value = stringField(parsed, field);
+		if (value) {
+			about[field] = value;
+		}
+	}
+
+	// "maintainer" may be a bare name or a contact object
+	const maintainer = parsed.maintainer;
+	if (typeof maintainer === "string" && maintainer.trim()) {
+		about.maintainer = { name: maintainer.trim() };
+	} else if (isRecord(maintainer)) {
+		const contact: {
+			-readonly [K in keyof RepositoryMaintainer]: RepositoryMaintainer[K];
+		} = {};
+		for (const field of ["name", "email", "url"] as const) {
+			const value = stringField(maintainer, field);
+			if (value) {
+				contact[field] = value;
+			}
+		}
+		if (Object.keys(contact).length > 0) {
+			about.maintainer = contact;
+		}
+	}
+
+	return about;
+}
+
+function isRecord(value: unknown): value is Record<string, unknown> {
+	return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+
+function stringField(
+	record: Record<string, unknown>,
+	field: string,
+): string | undefined {
+	const value = record[field];
+	return typeof value === "string" && value.trim() ? value.trim() : undefined;
+}
@@ -0,0 +1,73 @@
+import { isValidLanguageCode } from "../../utils/naming.js";
+
+/**
+ * Name of the language index at the commands root
+ */
+export const LANGUAGES_FILE = "languages.json";
+
+/**
+ * A language listed in a repository's languages.json
+ */
+export interface PublishedLanguage {
+	/** ISO 639-1 language code */
+	readonly code: string;
+	/** Display name, if the repository provides one */
+	readonly name?: string;
+}
+
+/**
+ * Parse a languages.json document
+ *
+ * Accepts either a bare array or an object with a "languages" array, whose
+ * items are language codes or { code, name } objects:
+ *
+ *   ["en", "fr"]
+ *   { "languages": [{ "code": "pt", "name": "Português" }] }
+ *
+ * Invalid entries are skipped so one bad line cannot hide every language.
+ *
+ * @param content - Raw JSON
+ * @returns Languages in document order without duplicates, or null if the
+ *   document has no languages array
+ */
+export function parseRepositoryLanguages(
+	content: string,
+): PublishedLanguage[] | null {
+	let parsed: unknown;
+	try {
+		parsed = JSON.parse(content);
+	} catch {
+		return null;
+	}
+
+	const items = Array.isArray(parsed)
+		? parsed
+		: typeof parsed === "object" &&
+				parsed !== null &&
+				Array.isArray((parsed as { languages?: unknown }).languages)
+			? (parsed as { languages: unknown[] }).languages
+			: null;
+	if (!items) {
+		return null;
+	}
+
+	const languages = new Map<string, PublishedLanguage>();
+	for (const item of items as unknown[]) {
+		const entry: { code?: unknown; name?: unknown } =
+			typeof item === "object" && item !== null ? item : {};
+		const code = typeof item === "string" ? item : entry.code;
+		if (
+			typeof code !== "string" ||
+			!isValidLanguageCode(code) ||
+			languages.has(code)
+		) {
+			continue;
+		}
+		const name =
+			typeof entry.name === "string" && entry.name.trim()
+				? entry.name.trim()
+				: undefined;
+		languages.set(code, name ? { code, name } : { code });
+	}
+	return [...languages.values()];
+}
@@ -1,3 +1,4 @@
+import type { CommandExample } from "../utils/examples.js";
 import type { ManifestComparisonResult } from "./ManifestComparison.js";
 
 /**
@@ -21,6 +22,33 @@ export interface Command {
 
 	/** Optional namespace for hierarchical command organization (e.g., "frontend", "backend:auth") */
 	readonly namespace?: string;
+
+	/** Optional SHA-256 (hex) of the command file, used to validate cached content */
+	readonly sha256?: string;
+
+	/** Optional category grouping related commands (e.g., "debugging") */
+	readonly category?: string;
+
+	/** Optional ISO 8601 timestamp of the command's last change */
+	readonly updated?: string;
+
+	/** Optional free-form tags used for filtering (e.g., ["git", "review"]) */
+	readonly tags?: readonly string[];
+
+	/** Optional names of other commands this command depends on */
+	readonly requires?: readonly string[];
+
+	/** Optional deprecation flag, or a note explaining the deprecation */
+	readonly deprecated?: boolean | string;
+
+	/** Optional name of the command that replaces a deprecated command */
+	readonly replaced_by?: string;
+
+	/** Optional usage examples, from the command file's frontmatter */
+	readonly examples?: readonly CommandExample[];
+
+	/** Repository the command was merged from, when several are configured */
+	readonly repository?: string;
 }
 
 /**
@@ -30,6 +58,9 @@ export interface Manifest {
 	/** Version of the manifest format */
 	readonly version: string;
 
+	/** Schema the manifest follows (see ManifestMigrations.ts; absent means 1) */
+	readonly schemaVersion?: number;
+
 	/** ISO 8601 timestamp of when the manifest was last updated */
 	readonly updated: string;
 
@@ -71,6 +102,46 @@ export interface CacheUpdateResultWithChanges extends CacheUpdateResult {
 	readonly comparisonResult?: ManifestComparisonResult;
 }
 
+/**
+ * Progress of a command file prefetch, reported after each file
+ */
+export interface PrefetchProgress {
+	/** Files processed so far (fetched or failed) */
+	readonly completed: number;
+
+	/** Total number of files to prefetch */
+	readonly total: number;
+
+	/** Command that was just processed */
+	readonly commandName: string;
+
+	/** Whether the command file was fetched */
+	readonly ok: boolean;
+
+	/** Size of the fetched command file in bytes (0 on failure) */
+	readonly bytes: number;
+}
+
+/**
+ * Result of prefetching every command file of a manifest
+ */
+export interface PrefetchResult {
+	/** Language code that was prefetched */
+	readonly language: string;
+
+	/** Number of commands in the manifest */
+	readonly total: number;
+
+	/** Number of command files now available locally */
+	readonly fetched: number;
+
+	/** Commands whose files could not be fetched */
+	readonly failed: readonly {
+		readonly commandName: string;
+		readonly error: string;
+	}[];
+}
+
 /**
  * Source of a command for attribution
  */
@@ -86,6 +157,9 @@ export interface InstallationStatus {
 	/** Installation location if installed */
 	readonly installLocation?: "personal" | "project";
 
+	/** Absolute root of the project it is installed in (project only) */
+	readonly projectRoot?: string;
+
 	/** Full path to installed file if available */
 	readonly installPath?: string;
 
@@ -147,19 +221,66 @@ export class CommandNotFoundError extends RepositoryError {
 	}
 }
 
+/**
+ * Error thrown when a bare command name matches commands of several
+ * prefixed repositories (see the repositoryPrefixes setting)
+ */
+export class AmbiguousCommandError extends RepositoryError {
+	constructor(
+		public readonly commandName: string,
+		language: string,
+		public readonly candidates: readonly string[],
+	) {
+		super(
+			`Command "${commandName}" is ambiguous; use one of: ${candidates.join(", ")}`,
+			language,
+		);
+	}
+}
+
 /**
  * Error thrown when the manifest cannot be retrieved or parsed
  */
 export class ManifestError extends RepositoryError {
 	/** The underlying cause of the manifest error */
 	public override readonly cause?: string;
+	/** Whether the repository has no manifest for the language (e.g. HTTP 404) */
+	public readonly notFound: boolean;
+	/** When the server was rate-limiting, the time it may be asked again */
+	public readonly retryAt?: number;
 
-	constructor(language: string, cause?: string) {
+	constructor(
+		language: string,
+		cause?: string,
+		notFound = false,
+		retryAt?: number,
+	) {
 		super(
 			`Failed to retrieve manifest for language "${language}": ${cause || "Unknown error"}`,
 			language,
 		);
 		this.cause = cause;
+		this.notFound = notFound;
+		this.retryAt = retryAt;
+	}
+}
+
+/**
+ * Error thrown when manifest signature verification is enabled and a
+ * manifest is unsigned or its signature does not verify
+ *
+ * Unlike ManifestError this is not a retrieval failure, so it never falls
+ * back to offline bundles.
+ */
+export class ManifestSignatureError extends RepositoryError {
+	constructor(
+		language: string,
+		public readonly reason: string,
+	) {
+		super(
+			`Refusing manifest for language "${language}": ${reason}`,
+			language,
+		);
 	}
 }
 
@@ -171,14 +292,26 @@ export class CommandContentError extends RepositoryError {
 	public readonly commandName: string;
 	/** The underlying cause of the content error */
 	public override readonly cause?: string;
+	/** Whether the command file does not exist (e.g. HTTP 404) */
+	public readonly notFound: boolean;
+	/** When the server was rate-limiting, the time it may be asked again */
+	public readonly retryAt?: number;
 
-	constructor(commandName: string, language: string, cause?: string) {
+	constructor(
+		commandName: string,
+		language: string,
+		cause?: string,
+		notFound = false,
+		retryAt?: number,
+	) {
 		super(
 			`Failed to retrieve content for command "${commandName}" in language "${language}": ${cause || "Unknown error"}`,
 			language,
 		);
 		this.commandName = commandName;
 		this.cause = cause;
+		this.notFound = notFound;
+		this.retryAt = retryAt;
 	}
 }
 
@@ -191,3 +324,19 @@ export interface CommandServiceOptions {
 	/** Force refresh from remote source, bypassing cache */
 	readonly forceRefresh?: boolean;
 }
+
+/**
+ * Options for listing and searching repository commands
+ */
+export interface CommandFilterOptions extends CommandServiceOptions {
+	/** Only include commands with this tag or category (case-insensitive) */
+	readonly tag?: string;
+}
+
+/**
+ * Options for searching repository commands
+ */
+export interface CommandSearchOptions extends CommandFilterOptions {
+	/** Also match words in command file bodies (see ContentSearchIndex) */
+	readonly content?: boolean;
+}
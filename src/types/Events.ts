@@ -0,0 +1,73 @@
+/**
+ * Events published while claude-cmd works, for wrappers that render their
+ * own progress (see EventBus and the --json-events flag)
+ */
+
+/**
+ * A long-running task (download, prefetch, bundle export) began
+ */
+export interface FetchStartedEvent {
+	readonly type: "fetch_started";
+	/** What is being done (e.g., "Prefetching command files") */
+	readonly label: string;
+	/** Number of steps, if known */
+	readonly total?: number;
+}
+
+/**
+ * Work was completed on the current task
+ */
+export interface DownloadProgressEvent {
+	readonly type: "download_progress";
+	readonly label: string;
+	/** Steps completed so far */
+	readonly steps: number;
+	readonly total?: number;
+	/** Bytes downloaded so far */
+	readonly bytes: number;
+}
+
+/**
+ * The current task ended
+ */
+export interface FetchFinishedEvent {
+	readonly type: "fetch_finished";
+	readonly label: string;
+	readonly steps: number;
+	readonly bytes: number;
+}
+
+/**
+ * A command file was written to a commands directory
+ */
+export interface InstalledEvent {
+	readonly type: "installed";
+	/** Name the command was installed under */
+	readonly command: string;
+	readonly language: string;
+	readonly target: "personal" | "project";
+	/** Path of the installed file */
+	readonly path: string;
+}
+
+/**
+ * The operation failed
+ */
+export interface ErrorEvent {
+	readonly type: "error";
+	/** Error class name (e.g., "CommandNotFoundError") */
+	readonly name: string;
+	readonly message: string;
+	/** Exit code the CLI exits with (see exitCodes.ts) */
+	readonly exitCode: number;
+}
+
+/**
+ * Any event published on the event bus
+ */
+export type ClaudeCmdEvent =
+	| FetchStartedEvent
+	| DownloadProgressEvent
+	| FetchFinishedEvent
+	| InstalledEvent
+	| ErrorEvent;
@@ -1,3 +1,5 @@
+import type { Provenance } from "../utils/frontmatter.js";
+
 /**
  * Installation-related types for the claude-cmd package manager
  */
@@ -14,6 +16,8 @@ export interface DirectoryInfo {
 	readonly exists: boolean;
 	/** Whether the directory is writable */
 	readonly writable: boolean;
+	/** Absolute project root the directory belongs to (project only) */
+	readonly projectRoot?: string;
 }
 
 /**
@@ -26,6 +30,12 @@ export interface InstallOptions {
 	readonly force?: boolean;
 	/** Language for the command (defaults to auto-detect) */
 	readonly language?: string;
+	/** Local name to install under (defaults to the command name) */
+	readonly installAs?: string;
+	/** Exact repository version to install; pins the command to it */
+	readonly version?: string;
+	/** Repository URL to record as provenance in the frontmatter, if any */
+	readonly provenanceSource?: string;
 }
 
 /**
@@ -36,6 +46,125 @@ export interface RemoveOptions {
 	readonly yes?: boolean;
 	/** Language for the command (defaults to auto-detect) */
 	readonly language?: string;
+	/** Keep namespace directories that the removal leaves empty */
+	readonly keepEmptyDirectories?: boolean;
+	/** Delete the file permanently instead of moving it to the trash */
+	readonly purge?: boolean;
+	/** Only remove the copy in this location (default: the first one found) */
+	readonly location?: "personal" | "project";
+}
+
+/**
+ * A removed command kept in the trash for restoring
+ */
+export interface TrashEntry {
+	/** Entry id */
+	readonly id: string;
+	/** Command name as it was installed */
+	readonly name: string;
+	/** Location it was removed from */
+	readonly location: "personal" | "project";
+	/** Path it was removed from */
+	readonly originalPath: string;
+	/** When it was removed (milliseconds since Unix epoch) */
+	readonly trashedAt: number;
+}
+
+/**
+ * Options for restoring a removed command from the trash
+ */
+export interface RestoreOptions {
+	/** Only restore a command removed from this location */
+	readonly from?: "personal" | "project";
+	/** Overwrite a command installed at the original path since */
+	readonly force?: boolean;
+}
+
+/**
+ * Where an installed command file lives
+ */
+export interface InstalledCommandLocation {
+	/** Path of the command file */
+	readonly filePath: string;
+	/** Directory type the file was found in */
+	readonly location: "personal" | "project";
+	/** Absolute root of the project the file belongs to (project only) */
+	readonly projectRoot?: string;
+}
+
+/**
+ * Options for moving or renaming an installed command
+ */
+export interface MoveOptions {
+	/** Location to move from when the command is installed in both */
+	readonly from?: "personal" | "project";
+	/** Location to move to (defaults to the current location) */
+	readonly target?: "personal" | "project";
+	/** Overwrite a command already installed under the new name */
+	readonly force?: boolean;
+	/** Rewrite the frontmatter name field to the new name when present */
+	readonly rewriteName?: boolean;
+	/** Keep namespace directories that the move leaves empty */
+	readonly keepEmptyDirectories?: boolean;
+}
+
+/**
+ * Options for copying an installed command to the other location
+ */
+export interface CopyOptions {
+	/** Location to copy to; the command is read from the other location */
+	readonly to: "personal" | "project";
+	/** Overwrite a copy already present in the destination */
+	readonly force?: boolean;
+}
+
+/**
+ * Options for importing a command file that was not installed by claude-cmd
+ */
+export interface ImportOptions {
+	/** Location to import into (defaults to "personal") */
+	readonly target?: "personal" | "project";
+	/** Overwrite a command already installed under the same name */
+	readonly force?: boolean;
+	/** Where the file came from, recorded in its frontmatter */
+	readonly provenance?: Provenance;
+}
+
+/**
+ * Options for exporting installed commands
+ */
+export interface ExportOptions {
+	/**
+	 * Commands to export (defaults to "all"). With "all", personal and project
+	 * commands go into "personal/" and "project/" subdirectories.
+	 */
+	readonly scope?: "personal" | "project" | "all";
+	/** Overwrite existing files at the destination */
+	readonly force?: boolean;
+}
+
+/**
+ * Outcome of exporting installed commands
+ */
+export interface ExportResult {
+	/** Archive file or directory written */
+	readonly path: string;
+	/** Whether a .tar.gz archive or a plain directory was written */
+	readonly format: "archive" | "directory";
+	/** Number of command files exported */
+	readonly commandCount: number;
+}
+
+/**
+ * Outcome of moving or copying an installed command
+ */
+export interface MoveResult {
+	/** Previous file path */
+	readonly fromPath: string;
+	/** New file path */
+	readonly toPath: string;
+	/** Location of the moved or copied file */
+	readonly location: "personal" | "project";
 }
 
 /**
@@ -60,6 +189,8 @@ export interface InstallationInfo {
 	readonly filePath: string;
 	/** Directory type where command is installed */
 	readonly location: "personal" | "project";
+	/** Absolute root of the project the command belongs to (project only) */
+	readonly projectRoot?: string;
 	/** Installation timestamp */
 	readonly installedAt: Date;
 	/** File size in bytes */
@@ -68,6 +199,10 @@ export interface InstallationInfo {
 	readonly source: "repository" | "local";
 	/** Command version identifier */
 	readonly version?: string;
+	/** Whether the command is pinned to its version */
+	readonly pinned?: boolean;
+	/** SHA-256 (hex) of the command file as installed from the repository */
+	readonly sha256?: string;
 	/** Detailed installation metadata */
 	readonly metadata: InstallationMetadata;
 }
@@ -115,7 +250,10 @@ export class InstallationError extends Error {
  * Error thrown when a command already exists and force is not specified
  */
 export class CommandExistsError extends InstallationError {
-	constructor(commandName: string, existingPath: string) {
+	constructor(
+		commandName: string,
+		public readonly existingPath: string,
+	) {
 		super(
 			`Command '${commandName}' already exists at ${existingPath}. Use --force to overwrite.`,
 			"install",
@@ -132,3 +270,37 @@ export class CommandNotInstalledError extends InstallationError {
 		super(`Command '${commandName}' is not installed.`, "remove", commandName);
 	}
 }
+
+/**
+ * Error thrown when a requested command version is not in the repository
+ */
+export class VersionNotAvailableError extends InstallationError {
+	constructor(
+		commandName: string,
+		public readonly requestedVersion: string,
+		public readonly availableVersion: string,
+	) {
+		super(
+			`Version ${requestedVersion} of '${commandName}' is not available. The repository provides version ${availableVersion} only.`,
+			"install",
+			commandName,
+		);
+	}
+}
+
+/**
+ * Error thrown when overwriting a pinned command with a different version
+ */
+export class CommandPinnedError extends InstallationError {
+	constructor(
+		commandName: string,
+		public readonly pinnedVersion: string,
+		public readonly availableVersion: string,
+	) {
+		super(
+			`Command '${commandName}' is pinned to version ${pinnedVersion}. Use 'claude-cmd add ${commandName}@${availableVersion} --force' to move the pin to version ${availableVersion}.`,
+			"install",
+			commandName,
+		);
+	}
+}
@@ -0,0 +1,29 @@
+/**
+ * Contact details of a repository maintainer
+ */
+export interface RepositoryMaintainer {
+	/** Maintainer or team name */
+	readonly name?: string;
+	/** Contact email address */
+	readonly email?: string;
+	/** Contact page or profile URL */
+	readonly url?: string;
+}
+
+/**
+ * Descriptive metadata a commands repository publishes in about.json
+ *
+ * Lets users verify which catalog they are browsing. Every field is
+ * optional; a language directory may ship its own about.json whose fields
+ * override the repository-wide one.
+ */
+export interface RepositoryAbout {
+	/** Catalog title (e.g., "Claude Code Commands") */
+	readonly title?: string;
+	/** One-paragraph description of the catalog */
+	readonly description?: string;
+	/** Homepage URL */
+	readonly homepage?: string;
+	/** Who maintains the catalog */
+	readonly maintainer?: RepositoryMaintainer;
+}
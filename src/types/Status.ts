@@ -32,6 +32,59 @@ export interface InstallationInfo {
 	readonly writable: boolean;
 	/** Number of installed commands in this directory */
 	readonly commandCount: number;
+	/** Installed commands per namespace (e.g., { frontend: 3, "backend:api": 1 }); top-level commands are not counted */
+	readonly namespaces?: Readonly<Record<string, number>>;
+	/** Namespace directories without any command files (relative paths) */
+	readonly emptyDirectories?: readonly string[];
+	/** Absolute root of the project the directory belongs to (project only) */
+	readonly projectRoot?: string;
+}
+
+/**
+ * Why an installed command has no entry in the cached manifest
+ *
+ * - local-only: never fetched from the repository (written by hand or copied)
+ * - removed-upstream: fetched from the repository before, but no longer listed
+ */
+export type UntrackedReason = "local-only" | "removed-upstream";
+
+/**
+ * An installed command that the repository does not know about
+ */
+export interface UntrackedCommand {
+	/** Command name (e.g., "frontend:component") */
+	readonly name: string;
+	/** Command file path relative to its commands directory */
+	readonly file: string;
+	/** Why the command is not in the manifest */
+	readonly reason: UntrackedReason;
+}
+
+/**
+ * Problem a health recommendation addresses
+ */
+export type HealthIssue =
+	| "cache-not-writable"
+	| "no-writable-directory"
+	| "repository-unreachable"
+	| "directory-not-writable"
+	| "cache-missing"
+	| "cache-stale"
+	| "removed-upstream"
+	| "empty-directories";
+
+/**
+ * Something wrong with the setup and what to do about it
+ */
+export interface HealthRecommendation {
+	/** Problem addressed */
+	readonly issue: HealthIssue;
+	/** How much the problem gets in the way */
+	readonly severity: "error" | "warning" | "info";
+	/** What is wrong (e.g., "Cached manifest for en is expired") */
+	readonly problem: string;
+	/** What to do about it (e.g., "Run 'claude-cmd cache update'") */
+	readonly action: string;
 }
 
 /**
@@ -42,8 +95,14 @@ export interface SystemHealth {
 	readonly cacheAccessible: boolean;
 	/** Whether at least one installation directory is writable */
 	readonly installationPossible: boolean;
+	/** Whether the repository answered (omitted unless it was checked) */
+	readonly repositoryReachable?: boolean;
 	/** Overall system status */
 	readonly status: "healthy" | "degraded" | "error";
+	/** 0 to 100: 100 without recommendations, lower the more serious they are */
+	readonly score: number;
+	/** What to fix, most important first */
+	readonly recommendations: readonly HealthRecommendation[];
 	/** Any error messages or warnings */
 	readonly messages: string[];
 }
@@ -54,14 +113,96 @@ export interface SystemHealth {
 export interface SystemStatus {
 	/** Timestamp when status was collected */
 	readonly timestamp: number;
-	/** Cache information for all detected languages */
+	/** Cache information for all detected languages, sorted by language code */
 	readonly cache: readonly CacheInfo[];
-	/** Installation directory information */
+	/** Installation directory information, project directory first, then user */
 	readonly installations: readonly InstallationInfo[];
+	/** Installed commands missing from the cached manifest (omitted without one) */
+	readonly untracked?: readonly UntrackedCommand[];
 	/** Overall system health */
 	readonly health: SystemHealth;
 }
 
+/**
+ * Cache summary of one language (manifest and command files)
+ */
+export interface LanguageCacheStatus {
+	/** Language code */
+	readonly language: string;
+	/** Whether this is the effective language */
+	readonly active: boolean;
+	/** Whether a manifest is cached */
+	readonly hasManifest: boolean;
+	/** Whether the cached manifest is expired (true if missing) */
+	readonly isExpired: boolean;
+	/** Number of commands in the cached manifest */
+	readonly commandCount?: number;
+	/** Manifest age in milliseconds */
+	readonly manifestAgeMs?: number;
+	/** Number of cached command files */
+	readonly cachedFiles: number;
+	/** Size of the manifest and cached command files in bytes */
+	readonly sizeBytes: number;
+	/** Age of the most recently cached command file in milliseconds */
+	readonly filesAgeMs?: number;
+}
+
+/**
+ * Cache status of every cached language
+ */
+export interface AllLanguagesStatus {
+	/** Timestamp when status was collected */
+	readonly timestamp: number;
+	/** Effective language */
+	readonly activeLanguage: string;
+	/** Languages with a cached manifest or command files, sorted by code */
+	readonly languages: readonly LanguageCacheStatus[];
+}
+
+/**
+ * Disk space used by the cache of one language
+ */
+export interface CacheUsage {
+	/** Language code */
+	readonly language: string;
+	/** Size of the cached manifest in bytes (0 without one) */
+	readonly manifestBytes: number;
+	/** Size of the cached command files in bytes */
+	readonly filesBytes: number;
+	/** Manifest and command files together */
+	readonly sizeBytes: number;
+}
+
+/**
+ * Disk space used by the commands installed in one directory
+ */
+export interface InstallationUsage {
+	/** Directory type */
+	readonly type: "project" | "user";
+	/** Full path to the directory */
+	readonly path: string;
+	/** Number of command files */
+	readonly commandCount: number;
+	/** Size of all command files in bytes */
+	readonly sizeBytes: number;
+	/** Bytes per namespace, sorted by namespace; top-level commands are not counted */
+	readonly namespaces: Readonly<Record<string, number>>;
+}
+
+/**
+ * Disk space used by claude-cmd
+ */
+export interface DiskUsage {
+	/** Timestamp when usage was collected */
+	readonly timestamp: number;
+	/** Cache usage of every cached language, sorted by language code */
+	readonly cache: readonly CacheUsage[];
+	/** Installation directories, project directory first, then user */
+	readonly installations: readonly InstallationUsage[];
+	/** Everything above together, in bytes */
+	readonly totalBytes: number;
+}
+
 /**
  * Output format options for status display
  */
@@ -1,4 +1,6 @@
 // Re-export all types from individual type files
 export * from "./Command.js";
+export * from "./Events.js";
 export * from "./Installation.js";
 export * from "./ManifestComparison.js";
+export * from "./Repository.js";
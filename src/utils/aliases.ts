@@ -0,0 +1,57 @@
+import { isValidCommandName } from "./naming.js";
+
+/**
+ * Installed command an alias stands for
+ */
+export interface AliasTarget {
+	/** Command name, possibly namespaced (e.g., "backend:code-review") */
+	readonly name: string;
+	/** Location to look in; unset means project first, then personal */
+	readonly location?: "personal" | "project";
+}
+
+/**
+ * Parse the target of an alias
+ *
+ * Targets are command names, optionally prefixed with the location to use:
+ * `project:backend:code-review` is `backend:code-review` in the project.
+ *
+ * @param text - Target as stored under the `aliases` config key
+ * @returns The target, or undefined if it is not a command name
+ */
+export function parseAliasTarget(text: string): AliasTarget | undefined {
+	const match = text.trim().match(/^(personal|project):(.+)$/);
+	const name = match?.[2] ?? text.trim();
+	if (!isValidCommandName(name)) {
+		return undefined;
+	}
+	const location = match?.[1] as AliasTarget["location"];
+	return location ? { name, location } : { name };
+}
+
+/**
+ * Format an alias target the way it is stored
+ */
+export function formatAliasTarget(target: AliasTarget): string {
+	return target.location ? `${target.location}:${target.name}` : target.name;
+}
+
+/**
+ * Check the shape of the `aliases` config key
+ *
+ * @param value - Value read from a config file
+ * @returns True if every alias name is a command name and every target parses
+ */
+export function isAliasMap(value: unknown): value is Record<string, string> {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		!Array.isArray(value) &&
+		Object.entries(value).every(
+			([alias, target]) =>
+				isValidCommandName(alias) &&
+				typeof target === "string" &&
+				parseAliasTarget(target) !== undefined,
+		)
+	);
+}
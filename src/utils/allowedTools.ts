@@ -0,0 +1,49 @@
+/**
+ * The allowed-tools whitelist shared by command parsing and manifest linting
+ */
+
+/**
+ * Core Claude Code tools a command may request
+ */
+const CORE_TOOLS = new Set([
+	"Edit",
+	"Glob",
+	"Grep",
+	"LS",
+	"MultiEdit",
+	"NotebookEdit",
+	"NotebookRead",
+	"Read",
+	"Task",
+	"TodoWrite",
+	"WebFetch",
+	"WebSearch",
+	"Write",
+]);
+
+/**
+ * MCP tools: mcp__<server-name>__<prompt-name>
+ */
+const MCP_TOOL_PATTERN = /^mcp__[a-zA-Z0-9_]+__[a-zA-Z0-9_]+$/;
+
+/**
+ * Bash command patterns: Bash(command:*) or Bash(cmd1:*, cmd2:*)
+ */
+const BASH_TOOL_PATTERN = /^Bash\([a-zA-Z0-9_\-,:*\s]+\)$/;
+
+/**
+ * Check if a tool is in the allowed whitelist
+ *
+ * Core tools, MCP tools and any Bash command pattern are allowed; the
+ * latter gives authors flexibility to use any bash tools in their commands.
+ *
+ * @param tool - allowed-tools entry
+ * @returns True if allowed
+ */
+export function isAllowedTool(tool: string): boolean {
+	return (
+		CORE_TOOLS.has(tool) ||
+		MCP_TOOL_PATTERN.test(tool) ||
+		BASH_TOOL_PATTERN.test(tool)
+	);
+}
@@ -0,0 +1,67 @@
+/**
+ * Longest argument-hint shown in full by Claude Code's autocompletion
+ */
+export const MAX_ARGUMENT_HINT_LENGTH = 100;
+
+/**
+ * Bracket pairs that delimit placeholders in an argument-hint
+ */
+const BRACKETS: Readonly<Record<string, string>> = { "[": "]", "<": ">" };
+
+/**
+ * Read the `argument-hint` frontmatter field as the text Claude Code shows
+ *
+ * Unquoted hints like `[message]` are YAML lists, so lists of strings are
+ * written back as bracketed placeholders ("[message]").
+ *
+ * @param value - The field as parsed from YAML
+ * @returns The hint, or undefined if absent, empty or of another type
+ */
+export function normalizeArgumentHint(value: unknown): string | undefined {
+	let hint: string | undefined;
+	if (typeof value === "string") {
+		hint = value.trim();
+	} else if (
+		Array.isArray(value) &&
+		value.every((entry) => typeof entry === "string")
+	) {
+		hint = value.map((entry) => `[${entry.trim()}]`).join(" ");
+	}
+	return hint || undefined;
+}
+
+/**
+ * Check the format of an argument-hint
+ *
+ * Hints are a single line of at most MAX_ARGUMENT_HINT_LENGTH characters
+ * whose [optional] and <required> placeholders are closed and not nested.
+ *
+ * @param hint - The hint, as normalizeArgumentHint() returns it
+ * @returns Why the hint is malformed, or undefined if it is fine
+ */
+export function argumentHintProblem(hint: string): string | undefined {
+	if (/[\r\n]/.test(hint)) {
+		return "argument-hint must fit on one line";
+	}
+	if (hint.length > MAX_ARGUMENT_HINT_LENGTH) {
+		return `argument-hint is longer than ${MAX_ARGUMENT_HINT_LENGTH} characters`;
+	}
+
+	let closing: string | undefined;
+	for (const char of hint) {
+		if (closing === undefined) {
+			if (BRACKETS[char]) {
+				closing = BRACKETS[char];
+			} else if (char === "]" || char === ">") {
+				return `argument-hint has an unmatched '${char}'`;
+			}
+		} else if (char === closing) {
+			closing = undefined;
+		} else if (BRACKETS[char]) {
+			return "argument-hint has nested placeholders";
+		}
+	}
+	return closing === undefined
+		? undefined
+		: `argument-hint has an unclosed placeholder (missing '${closing}')`;
+}
@@ -0,0 +1,44 @@
+import { randomBytes } from "node:crypto";
+import * as path from "node:path";
+import type IFileService from "../interfaces/IFileService.js";
+
+/**
+ * Write a file so that readers never observe partial content
+ *
+ * The content is written to a temporary file in the destination directory
+ * and then renamed over the destination. A crash mid-write leaves at most a
+ * stray temporary file behind; the destination keeps its previous content.
+ *
+ * @param fileService - File service used for the write and rename
+ * @param filePath - Destination file path
+ * @param content - Content to write
+ * @throws FileSystemError subclasses from the underlying write or rename
+ */
+export async function writeFileAtomic(
+	fileService: IFileService,
+	filePath: string,
+	content: string,
+): Promise<void> {
+	const tempPath = getTempPath(filePath);
+
+	try {
+		await fileService.writeFile(tempPath, content);
+		await fileService.rename(tempPath, filePath);
+	} catch (error) {
+		await fileService.deleteFile(tempPath).catch(() => {});
+		throw error;
+	}
+}
+
+/**
+ * Build a unique hidden temporary path next to the destination
+ *
+ * Same directory means same file system, which keeps the rename atomic.
+ */
+function getTempPath(filePath: string): string {
+	const suffix = `${process.pid}.${randomBytes(4).toString("hex")}`;
+	return path.join(
+		path.dirname(filePath),
+		`.${path.basename(filePath)}.${suffix}.tmp`,
+	);
+}
@@ -0,0 +1,33 @@
+/**
+ * Build metadata of the running claude-cmd
+ *
+ * Release builds inline CLAUDE_CMD_BUILD_COMMIT and CLAUDE_CMD_BUILD_DATE
+ * (see the build script); runs from source report them as "unknown".
+ */
+export interface BuildInfo {
+	/** claude-cmd version (e.g., "0.1.0") */
+	readonly version: string;
+	/** Git commit the binary was built from */
+	readonly commit: string;
+	/** When the binary was built (ISO 8601) */
+	readonly date: string;
+	/** Runtime executing claude-cmd (e.g., "bun 1.2.19") */
+	readonly runtime: string;
+	/** Operating system and CPU architecture (e.g., "linux/x64") */
+	readonly platform: string;
+}
+
+/**
+ * Collect the build metadata of the running claude-cmd
+ *
+ * @param version - claude-cmd version from package.json
+ */
+export function getBuildInfo(version: string): BuildInfo {
+	return {
+		version,
+		commit: process.env.CLAUDE_CMD_BUILD_COMMIT || "unknown",
+		date: process.env.CLAUDE_CMD_BUILD_DATE || "unknown",
+		runtime: `bun ${Bun.version}`,
+		platform: `${process.platform}/${process.arch}`,
+	};
+}
@@ -0,0 +1,161 @@
+import { readFileSync } from "node:fs";
+import * as os from "node:os";
+import * as path from "node:path";
+import type IFileService from "../interfaces/IFileService.js";
+import { cacheLogger } from "./logger.js";
+
+/**
+ * Environment variable overriding the cache directory
+ */
+export const CACHE_DIR_ENV = "CLAUDE_CMD_CACHE_DIR";
+
+/**
+ * Get the platform's default cache directory for claude-cmd
+ *
+ * - Linux and other Unix: $XDG_CACHE_HOME/claude-cmd (default ~/.cache)
+ * - macOS: ~/Library/Caches/claude-cmd
+ * - Windows: %LOCALAPPDATA%\claude-cmd
+ *
+ * @param env - Environment to read (defaults to process.env)
+ * @param platform - Platform to resolve for (defaults to the current one)
+ * @param home - Home directory (defaults to the current user's)
+ */
+export function defaultCacheDir(
+	env: NodeJS.ProcessEnv = process.env,
+	platform: NodeJS.Platform = process.platform,
+	home: string = os.homedir(),
+): string {
+	switch (platform) {
+		case "darwin":
+			return path.join(home, "Library", "Caches", "claude-cmd");
+		case "win32":
+			return path.win32.join(
+				env.LOCALAPPDATA || path.win32.join(home, "AppData", "Local"),
+				"claude-cmd",
+			);
+		default: {
+			// XDG requires relative values to be ignored
+			const xdg = env.XDG_CACHE_HOME;
+			const base =
+				xdg && path.isAbsolute(xdg) ? xdg : path.join(home, ".cache");
+			return path.join(base, "claude-cmd");
+		}
+	}
+}
+
+/**
+ * Resolve the cache directory: CLAUDE_CMD_CACHE_DIR, then the configured
+ * cacheDir, then the platform default
+ *
+ * @param configured - cacheDir from the effective configuration, if any
+ * @param env - Environment to read (defaults to process.env)
+ * @returns Absolute cache directory
+ */
+export function resolveCacheDir(
+	configured?: string,
+	env: NodeJS.ProcessEnv = process.env,
+): string {
+	const override = env[CACHE_DIR_ENV]?.trim() || configured?.trim();
+	return override ? path.resolve(expandHome(override)) : defaultCacheDir(env);
+}
+
+/**
+ * Read a directory setting from config files, the first one that sets it
+ * winning
+ *
+ * Services are built synchronously, so this reads the files directly instead
+ * of going through ConfigService. Missing or unreadable files are skipped.
+ *
+ * @param configPaths - Config files in precedence order (project, then user)
+ * @param key - Setting to read
+ */
+export function readConfiguredDirectory(
+	configPaths: readonly string[],
+	key: "cacheDir" | "claudeDir",
+): string | undefined {
+	for (const configPath of configPaths) {
+		try {
+			const value = JSON.parse(readFileSync(configPath, "utf-8"))[key];
+			if (typeof value === "string" && value.trim()) {
+				return value;
+			}
+		} catch {
+			// No config here; try the next one
+		}
+	}
+	return undefined;
+}
+
+/**
+ * Cache locations used by earlier releases
+ *
+ * Only locations inside the home directory are listed: a cache in a shared
+ * directory such as /tmp may have been planted by another user.
+ *
+ * @param home - Home directory (defaults to the current user's)
+ */
+export function legacyCacheDirs(home: string = os.homedir()): string[] {
+	return [path.join(home, ".cache", "claude-cmd")];
+}
+
+/**
+ * Move a cache left at an old location to the current cache directory
+ *
+ * Runs only when the current directory is the platform default and does not
+ * exist yet, so an existing cache is never overwritten and directories chosen
+ * with CLAUDE_CMD_CACHE_DIR or cacheDir are left alone. Failures (e.g.,
+ * moving across devices) are logged and leave the old cache in place; it is
+ * only a cache.
+ *
+ * @param fileService - File service used to move the directory
+ * @param cacheDir - Current cache directory
+ * @param candidates - Old locations to look for, most likely first
+ * @param defaultDir - The platform default (see defaultCacheDir())
+ * @returns The location moved from, or null if nothing was moved
+ */
+export async function migrateLegacyCacheDir(
+	fileService: IFileService,
+	cacheDir: string,
+	candidates: readonly string[] = legacyCacheDirs(),
+	defaultDir: string = defaultCacheDir(),
+): Promise<string | null> {
+	if (
+		path.resolve(cacheDir) !== path.resolve(defaultDir) ||
+		(await fileService.exists(cacheDir))
+	) {
+		return null;
+	}
+
+	for (const legacy of candidates) {
+		if (path.resolve(legacy) === path.resolve(cacheDir)) {
+			continue;
+		}
+		if (!(await fileService.exists(legacy))) {
+			continue;
+		}
+		try {
+			await fileService.mkdir(path.dirname(cacheDir));
+			await fileService.rename(legacy, cacheDir);
+			cacheLogger.info("moved cache from {from} to {to}", {
+				from: legacy,
+				to: cacheDir,
+			});
+			return legacy;
+		} catch (error) {
+			cacheLogger.warn("could not move cache from {from}: {error}", {
+				from: legacy,
+				error: error instanceof Error ? error.message : String(error),
+			});
+		}
+	}
+	return null;
+}
+
+/**
+ * Expand a leading ~ to the home directory
+ */
+export function expandHome(value: string): string {
+	return value === "~" || value.startsWith("~/")
+		? path.join(os.homedir(), value.slice(1))
+		: value;
+}
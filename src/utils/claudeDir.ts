@@ -0,0 +1,34 @@
+import * as os from "node:os";
+import * as path from "node:path";
+import { expandHome } from "./cacheDir.js";
+
+/**
+ * Environment variables relocating Claude Code's directory, checked in order
+ *
+ * CLAUDE_CONFIG_DIR is the variable Claude Code itself reads; CLAUDE_HOME is
+ * accepted as an alias.
+ */
+export const CLAUDE_DIR_ENVS = ["CLAUDE_CONFIG_DIR", "CLAUDE_HOME"] as const;
+
+/**
+ * Resolve the user's Claude directory (the parent of the personal commands
+ * directory): CLAUDE_CONFIG_DIR or CLAUDE_HOME, then the configured
+ * claudeDir, then ~/.claude
+ *
+ * @param configured - claudeDir from the effective configuration, if any
+ * @param env - Environment to read (defaults to process.env)
+ * @param home - Home directory (defaults to the current user's)
+ * @returns Absolute Claude directory
+ */
+export function resolveClaudeDir(
+	configured?: string,
+	env: NodeJS.ProcessEnv = process.env,
+	home: string = os.homedir(),
+): string {
+	const override =
+		CLAUDE_DIR_ENVS.map((name) => env[name]?.trim()).find(Boolean) ||
+		configured?.trim();
+	return override
+		? path.resolve(expandHome(override))
+		: path.join(home, ".claude");
+}
@@ -0,0 +1,48 @@
+import type { Command } from "../types/Command.js";
+
+/**
+ * Check whether the repository has deprecated a command
+ *
+ * @param command - Command from a manifest
+ * @returns True if "deprecated" is true or a non-empty note
+ */
+export function isDeprecated(command: Command): boolean {
+	return (
+		command.deprecated === true ||
+		(typeof command.deprecated === "string" && command.deprecated.trim() !== "")
+	);
+}
+
+/**
+ * Describe a deprecated command's status and replacement
+ *
+ * @param command - Deprecated command from a manifest
+ * @returns e.g. "deprecated: merged into review; use 'review' instead"
+ */
+export function formatDeprecation(command: Command): string {
+	let text = "deprecated";
+	if (typeof command.deprecated === "string" && command.deprecated.trim()) {
+		text += `: ${command.deprecated.trim()}`;
+	}
+	if (command.replaced_by) {
+		text += `; use '${command.replaced_by}' instead`;
+	}
+	return text;
+}
+
+/**
+ * Find installed commands the repository has deprecated
+ *
+ * @param installedNames - Names of installed commands
+ * @param repositoryCommands - Commands of the current manifest
+ * @returns Deprecated manifest entries that are installed, in manifest order
+ */
+export function findDeprecatedInstalled(
+	installedNames: Iterable<string>,
+	repositoryCommands: readonly Command[],
+): Command[] {
+	const installed = new Set(installedNames);
+	return repositoryCommands.filter(
+		(command) => installed.has(command.name) && isDeprecated(command),
+	);
+}
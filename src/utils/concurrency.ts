@@ -0,0 +1,31 @@
+/**
+ * Map items through an async function with at most `limit` calls in flight
+ *
+ * Results keep the order of the input. The first rejection rejects the
+ * whole call (in-flight calls still settle); callers that want per-item
+ * failures should catch inside fn.
+ *
+ * @param items - Items to process
+ * @param limit - Maximum number of concurrent calls (at least 1)
+ * @param fn - Async function applied to each item
+ * @returns Results in input order
+ */
+export async function mapConcurrent<T, R>(
+	items: readonly T[],
+	limit: number,
+	fn: (item: T, index: number) => Promise<R>,
+): Promise<R[]> {
+	const results = new Array<R>(items.length);
+	let next = 0;
+
+	const worker = async (): Promise<void> => {
+		while (next < items.length) {
+			const index = next++;
+			results[index] = await fn(items[index] as T, index);
+		}
+	};
+
+	const workers = Math.max(1, Math.min(Math.floor(limit), items.length));
+	await Promise.all(Array.from({ length: workers }, worker));
+	return results;
+}
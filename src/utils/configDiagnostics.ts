@@ -0,0 +1,294 @@
+import { CONFIG_KEYS, checkConfigValue } from "./configKeys.js";
+import { editDistance } from "./suggestions.js";
+
+/**
+ * Problem found in a config file, with its 1-based position
+ */
+export interface ConfigDiagnostic {
+	readonly severity: "error" | "warning";
+	readonly message: string;
+	readonly line: number;
+	readonly column: number;
+	/** Key the problem is about, if any */
+	readonly key?: string;
+}
+
+/**
+ * Syntax error found while scanning JSON, at a character offset
+ */
+class JSONSyntaxError extends Error {
+	constructor(
+		message: string,
+		public readonly offset: number,
+	) {
+		super(message);
+		this.name = "JSONSyntaxError";
+	}
+}
+
+/**
+ * Check the text of a config file
+ *
+ * Reports JSON syntax errors, unknown keys (with the closest known key as a
+ * suggestion) and values that do not fit their key, each at the line and
+ * column where it occurs. An empty list means the file is valid.
+ *
+ * @param content - Config file contents
+ * @returns Problems in the order they appear in the file
+ */
+export function diagnoseConfig(content: string): ConfigDiagnostic[] {
+	const at = (offset: number) => positionOf(content, offset);
+
+	let keys: Array<{ key: string; offset: number }>;
+	try {
+		keys = new JSONScanner(content).scan();
+	} catch (error) {
+		if (error instanceof JSONSyntaxError) {
+			return [
+				{ severity: "error", message: error.message, ...at(error.offset) },
+			];
+		}
+		throw error;
+	}
+
+	const config = JSON.parse(content);
+	if (typeof config !== "object" || config === null || Array.isArray(config)) {
+		return [
+			{
+				severity: "error",
+				message: "configuration must be a JSON object",
+				...at(content.search(/\S/)),
+			},
+		];
+	}
+
+	const diagnostics: ConfigDiagnostic[] = [];
+	const seen = new Set<string>();
+	for (const { key, offset } of keys) {
+		if (seen.has(key)) {
+			diagnostics.push({
+				severity: "warning",
+				message: `duplicate key '${key}'; the last value wins`,
+				key,
+				...at(offset),
+			});
+			continue;
+		}
+		seen.add(key);
+
+		if (!Object.hasOwn(CONFIG_KEYS, key)) {
+			const suggestion = closestKey(key);
+			diagnostics.push({
+				severity: "error",
+				message: suggestion
+					? `unknown key '${key}' (did you mean '${suggestion}'?)`
+					: `unknown key '${key}'`,
+				key,
+				...at(offset),
+			});
+			continue;
+		}
+
+		const problem = checkConfigValue(key, config[key]);
+		if (problem) {
+			diagnostics.push({
+				severity: "error",
+				message: `invalid value for ${key}: ${JSON.stringify(config[key])} (${problem})`,
+				key,
+				...at(offset),
+			});
+		}
+	}
+	return diagnostics;
+}
+
+/**
+ * Format a diagnostic the way compilers do: path:line:column: severity: message
+ */
+export function formatDiagnostic(
+	configPath: string,
+	diagnostic: ConfigDiagnostic,
+): string {
+	return `${configPath}:${diagnostic.line}:${diagnostic.column}: ${diagnostic.severity}: ${diagnostic.message}`;
+}
+
+/**
+ * Find the known key a misspelled one most likely meant
+ *
+ * Keys are also matched without their first word, so that a misspelled
+ * `languge` suggests `preferredLanguage`.
+ *
+ * @returns The closest known key within a few edits, or undefined
+ */
+export function closestKey(key: string): string | undefined {
+	const typed = key.toLowerCase();
+	let best: string | undefined;
+	let bestDistance = Math.max(2, Math.floor(key.length / 3)) + 1;
+	for (const known of Object.keys(CONFIG_KEYS)) {
+		const tail = known.replace(/^[a-z]+/, "").toLowerCase();
+		const distance = Math.min(
+			editDistance(typed, known.toLowerCase()),
+			tail ? editDistance(typed, tail) : Number.POSITIVE_INFINITY,
+		);
+		if (distance < bestDistance) {
+			best = known;
+			bestDistance = distance;
+		}
+	}
+	return best;
+}
+
+/**
+ * Convert a character offset to a 1-based line and column
+ */
+function positionOf(
+	content: string,
+	offset: number,
+): { line: number; column: number } {
+	const before = content.slice(0, Math.max(0, offset));
+	const lineStart = before.lastIndexOf("\n") + 1;
+	return {
+		line: before.split("\n").length,
+		column: offset - lineStart + 1,
+	};
+}
+
+/**
+ * Minimal JSON scanner locating syntax errors and top-level keys
+ *
+ * JSON.parse error messages differ between runtimes and do not always carry
+ * a position, so the text is walked once here to report where it breaks.
+ */
+class JSONScanner {
+	private offset = 0;
+	private readonly keys: Array<{ key: string; offset: number }> = [];
+
+	constructor(private readonly text: string) {}
+
+	/**
+	 * Scan the whole text
+	 *
+	 * @returns Keys of the top-level object with their offsets
+	 * @throws JSONSyntaxError at the first syntax error
+	 */
+	scan(): Array<{ key: string; offset: number }> {
+		this.skipWhitespace();
+		if (this.offset >= this.text.length) {
+			throw new JSONSyntaxError("file is empty", 0);
+		}
+		this.value(0);
+		this.skipWhitespace();
+		if (this.offset < this.text.length) {
+			throw this.unexpected();
+		}
+		return this.keys;
+	}
+
+	private value(depth: number): void {
+		const char = this.text[this.offset];
+		if (char === "{") {
+			this.object(depth);
+		} else if (char === "[") {
+			this.array(depth);
+		} else if (char === '"') {
+			this.string();
+		} else if (char !== undefined && /[-\d]/.test(char)) {
+			this.literal(/-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?/y);
+		} else {
+			this.literal(/true|false|null/y);
+		}
+	}
+
+	private object(depth: number): void {
+		this.offset++;
+		this.skipWhitespace();
+		if (this.text[this.offset] === "}") {
+			this.offset++;
+			return;
+		}
+		for (;;) {
+			if (this.text[this.offset] !== '"') {
+				throw this.unexpected("expected a quoted key");
+			}
+			const keyOffset = this.offset;
+			const key = this.string();
+			if (depth === 0) {
+				this.keys.push({ key, offset: keyOffset });
+			}
+			this.skipWhitespace();
+			this.expect(":");
+			this.skipWhitespace();
+			this.value(depth + 1);
+			this.skipWhitespace();
+			if (this.text[this.offset] === "}") {
+				this.offset++;
+				return;
+			}
+			this.expect(",", "expected ',' or '}'");
+			this.skipWhitespace();
+			if (this.text[this.offset] === "}") {
+				throw this.unexpected("trailing comma is not allowed");
+			}
+		}
+	}
+
+	private array(depth: number): void {
+		this.offset++;
+		this.skipWhitespace();
+		if (this.text[this.offset] === "]") {
+			this.offset++;
+			return;
+		}
+		for (;;) {
+			this.value(depth + 1);
+			this.skipWhitespace();
+			if (this.text[this.offset] === "]") {
+				this.offset++;
+				return;
+			}
+			this.expect(",", "expected ',' or ']'");
+			this.skipWhitespace();
+		}
+	}
+
+	private string(): string {
+		const start = this.offset;
+		const match = /"(?:[^"\\\n]|\\(?:["\\/bfnrt]|u[\da-fA-F]{4}))*"/y;
+		match.lastIndex = start;
+		if (!match.test(this.text)) {
+			throw new JSONSyntaxError("unterminated or invalid string", start);
+		}
+		this.offset = match.lastIndex;
+		return JSON.parse(this.text.slice(start, this.offset));
+	}
+
+	private literal(pattern: RegExp): void {
+		pattern.lastIndex = this.offset;
+		if (!pattern.test(this.text)) {
+			throw this.unexpected("expected a value");
+		}
+		this.offset = pattern.lastIndex;
+	}
+
+	private expect(char: string, message = `expected '${char}'`): void {
+		if (this.text[this.offset] !== char) {
+			throw this.unexpected(message);
+		}
+		this.offset++;
+	}
+
+	private skipWhitespace(): void {
+		while (/[ \t\r\n]/.test(this.text[this.offset] ?? "")) {
+			this.offset++;
+		}
+	}
+
+	private unexpected(expected?: string): JSONSyntaxError {
+		const char = this.text[this.offset];
+		const found = char === undefined ? "end of file" : `'${char}'`;
+		return new JSONSyntaxError(
+			expected ? `unexpected ${found}, ${expected}` : `unexpected ${found}`,
+			this.offset,
+		);
+	}
+}
@@ -0,0 +1,441 @@
+import * as path from "node:path";
+import { InvalidConfigError } from "../interfaces/IConfigService.js";
+import { parseAliasTarget } from "./aliases.js";
+import {
+	HOOK_EVENTS,
+	isHookCommands,
+	isHookEvent,
+	isHookTrust,
+} from "./hooks.js";
+import { parsePublicKey } from "./minisign.js";
+import {
+	isValidCommandName,
+	NAMESPACE_SEGMENT_PATTERN,
+	normalizeLanguageCode,
+} from "./naming.js";
+import { COLOR_MODES } from "./style.js";
+import { isToolDecision } from "./toolConsent.js";
+
+/**
+ * scp-like git URL accepted for git repositories (e.g., git@github.com:acme/commands.git)
+ */
+export const SCP_LIKE_GIT_URL = /^[\w.-]+@[\w.-]+:[\w./~-]+$/;
+
+/**
+ * Branch or tag name accepted for repositoryRef (no leading dash, no "..")
+ */
+export const GIT_REF_PATTERN = /^(?!-)(?!.*\.\.)[\w./-]+$/;
+
+/**
+ * Check whether a string parses as an absolute URL
+ */
+export function isValidURL(value: string): boolean {
+	try {
+		new URL(value);
+		return true;
+	} catch {
+		return false;
+	}
+}
+
+/**
+ * Check whether a URL can be used in additionalRepositories
+ */
+export function isAdditionalRepositoryURL(value: string): boolean {
+	return isValidURL(value) && /^(https?|file):/i.test(value);
+}
+
+/**
+ * Check whether a pattern can be used in scanIgnore (a single name segment)
+ */
+export function isScanIgnorePattern(value: string): boolean {
+	return value.trim() !== "" && !/[\\/]/.test(value);
+}
+
+/**
+ * Value type of a configuration key
+ */
+export type ConfigValueType =
+	| "string"
+	| "boolean"
+	| "integer"
+	| "number"
+	| "list"
+	| "map";
+
+/**
+ * Description of a configuration key settable with `claude-cmd config set`
+ */
+export interface ConfigKey {
+	readonly type: ConfigValueType;
+	readonly description: string;
+	/** Allowed values, for keys with a fixed set */
+	readonly values?: readonly string[];
+	/** Further check of strings, list items and map keys; returns what is wrong */
+	readonly check?: (value: string) => string | undefined;
+	/** Check of map values; returns what is wrong */
+	readonly checkEntry?: (value: unknown) => string | undefined;
+}
+
+/**
+ * Configuration keys known to this release
+ *
+ * Config files may contain other keys (they are kept for forward
+ * compatibility), but the config command only writes these.
+ */
+export const CONFIG_KEYS: Readonly<Record<string, ConfigKey>> = {
+	preferredLanguage: {
+		type: "string",
+		description: "Language for commands (e.g., en, fr)",
+		check: (value) =>
+			normalizeLanguageCode(value) ? undefined : "expected a language code",
+	},
+	repositoryURL: {
+		type: "string",
+		description: "Commands repository URL (http(s), git or file://)",
+		check: (value) =>
+			isValidURL(value) || SCP_LIKE_GIT_URL.test(value)
+				? undefined
+				: "expected a URL",
+	},
+	additionalRepositories: {
+		type: "list",
+		description:
+			"Further HTTP(S) or file:// repositories listed after repositoryURL (comma-separated in config set)",
+		check: (value) =>
+			isAdditionalRepositoryURL(value)
+				? undefined
+				: `'${value}' is not an http(s) or file:// URL`,
+	},
+	disabledRepositories: {
+		type: "list",
+		description:
+			"additionalRepositories entries to leave out until enabled again (see repo disable)",
+		check: (value) =>
+			isAdditionalRepositoryURL(value)
+				? undefined
+				: `'${value}' is not an http(s) or file:// URL`,
+	},
+	repositoryPrefixes: {
+		type: "map",
+		description:
+			'Namespace put before the command names of a repository, e.g. {"https://example.com/team": "team"} lists its deploy as team:deploy (JSON in config set)',
+		check: (url) =>
+			isAdditionalRepositoryURL(url)
+				? undefined
+				: `'${url}' is not an http(s) or file:// URL`,
+		checkEntry: (value) =>
+			typeof value === "string" && NAMESPACE_SEGMENT_PATTERN.test(value)
+				? undefined
+				: "expected a namespace (letters, digits and inner hyphens)",
+	},
+	repositoryPublicKeys: {
+		type: "map",
+		description:
+			"minisign public key the manifests of an additional repository must be signed with, by repository URL (JSON in config set)",
+		check: (url) =>
+			isAdditionalRepositoryURL(url)
+				? undefined
+				: `'${url}' is not an http(s) or file:// URL`,
+		checkEntry: (value) => {
+			if (typeof value !== "string") {
+				return "expected a minisign public key";
+			}
+			try {
+				parsePublicKey(value);
+				return undefined;
+			} catch (error) {
+				return error instanceof Error ? error.message : String(error);
+			}
+		},
+	},
+	repositoryType: {
+		type: "string",
+		description: "Repository source type",
+		values: ["http", "git"],
+	},
+	repositoryRef: {
+		type: "string",
+		description: "Branch or tag checked out for git repositories",
+		check: (value) =>
+			GIT_REF_PATTERN.test(value) ? undefined : "expected a branch or tag name",
+	},
+	manifestPublicKey: {
+		type: "string",
+		description:
+			"minisign public key; repositoryURL's manifests must then carry a valid manifest.json.minisig",
+		check: (value) => {
+			try {
+				parsePublicKey(value);
+				return undefined;
+			} catch (error) {
+				return error instanceof Error ? error.message : String(error);
+			}
+		},
+	},
+	strictLanguage: {
+		type: "boolean",
+		description: "Fail instead of serving English when a translation is missing",
+	},
+	recordProvenance: {
+		type: "boolean",
+		description:
+			"Record source, version, checksum and install time in installed frontmatter",
+	},
+	defaultTarget: {
+		type: "string",
+		description: "Where add installs without --target",
+		values: ["personal", "project"],
+	},
+	cacheDir: {
+		type: "string",
+		description: "Cache directory (CLAUDE_CMD_CACHE_DIR takes precedence)",
+		check: (value) => (value.trim() ? undefined : "expected a directory"),
+	},
+	claudeDir: {
+		type: "string",
+		description:
+			"Claude Code's directory holding personal commands (CLAUDE_CONFIG_DIR takes precedence)",
+		check: (value) => (value.trim() ? undefined : "expected a directory"),
+	},
+	commands: {
+		type: "list",
+		description: "Commands a project uses (comma-separated in config set)",
+		check: (value) =>
+			isValidCommandName(value)
+				? undefined
+				: `'${value}' is not a command name`,
+	},
+	toolConsent: {
+		type: "map",
+		description:
+			"Remembered answers to the allowed-tools prompt, by command (JSON in config set)",
+		check: (name) =>
+			isValidCommandName(name) ? undefined : `'${name}' is not a command name`,
+		checkEntry: (value) =>
+			isToolDecision(value)
+				? undefined
+				: 'expected {"allow": true|false, "tools": [...]}',
+	},
+	aliases: {
+		type: "map",
+		description:
+			"Short names for installed commands, e.g. {\"review\": \"project:backend:code-review\"} (JSON in config set; see claude-cmd alias)",
+		check: (name) =>
+			isValidCommandName(name) ? undefined : `'${name}' is not a command name`,
+		checkEntry: (value) =>
+			typeof value === "string" && parseAliasTarget(value)
+				? undefined
+				: "expected a command name, optionally prefixed with personal: or project:",
+	},
+	hooks: {
+		type: "map",
+		description:
+			'Shell commands run around add, remove and cache update, e.g. {"post-add": "make docs"} (project config; JSON in config set)',
+		check: (event) =>
+			isHookEvent(event)
+				? undefined
+				: `expected one of: ${HOOK_EVENTS.join(", ")}`,
+		checkEntry: (value) =>
+			isHookCommands(value)
+				? undefined
+				: "expected a shell command or a list of them",
+	},
+	projectHookTrust: {
+		type: "map",
+		description:
+			"Remembered answers to the project hooks prompt, by project root (global config only; JSON in config set)",
+		check: (root) =>
+			path.isAbsolute(root) ? undefined : `'${root}' is not an absolute path`,
+		checkEntry: (value) =>
+			isHookTrust(value)
+				? undefined
+				: 'expected {"allow": true|false, "fingerprint": "<sha256>"}',
+	},
+	hookTimeoutSeconds: {
+		type: "integer",
+		description: "Seconds a hook may run before it is killed",
+	},
+	cleanupEmptyDirectories: {
+		type: "boolean",
+		description: "Delete namespace directories left empty by removals",
+	},
+	scanIgnore: {
+		type: "list",
+		description:
+			"Directory names skipped when scanning commands directories, * and ? wildcards (default: .*, node_modules, vendor; comma-separated in config set)",
+		check: (value) =>
+			isScanIgnorePattern(value)
+				? undefined
+				: `'${value}' is not a directory name pattern`,
+	},
+	scanMaxDepth: {
+		type: "integer",
+		description: "Deepest namespace level scanned in commands directories",
+	},
+	crashReports: {
+		type: "boolean",
+		description:
+			"Write an anonymized crash report when claude-cmd crashes (default: false)",
+	},
+	maxRequestsPerSecond: {
+		type: "number",
+		description: "Maximum HTTP requests started per second",
+	},
+	maxParallelDownloads: {
+		type: "integer",
+		description: "Maximum HTTP requests in flight at once",
+	},
+	maxBytesPerSecond: {
+		type: "number",
+		description: "Maximum average download rate in bytes per second",
+	},
+	maxManifestBytes: {
+		type: "integer",
+		description: "Largest manifest accepted from the repository, in bytes",
+	},
+	maxCommandBytes: {
+		type: "integer",
+		description: "Largest command file accepted from the repository, in bytes",
+	},
+	previewLines: {
+		type: "integer",
+		description: "Lines shown by info --detailed before truncating (default: all)",
+	},
+	previewCharacters: {
+		type: "integer",
+		description: "Characters shown by info --detailed before truncating (default: all)",
+	},
+	outputColor: {
+		type: "string",
+		description:
+			"When to color output: auto (terminals without NO_COLOR), always or never",
+		values: COLOR_MODES,
+	},
+};
+
+/**
+ * Look up a configuration key
+ *
+ * @param key - Key name as written in config files
+ * @returns The key description
+ * @throws InvalidConfigError if the key is unknown
+ */
+export function getConfigKey(key: string): ConfigKey {
+	if (!Object.hasOwn(CONFIG_KEYS, key)) {
+		throw new InvalidConfigError(
+			`Unknown configuration key '${key}'. Known keys: ${Object.keys(CONFIG_KEYS).join(", ")}`,
+		);
+	}
+	return CONFIG_KEYS[key] as ConfigKey;
+}
+
+/**
+ * Check a value decoded from a config file against its key
+ *
+ * @param key - Known key name
+ * @param value - Value as found in the file
+ * @returns What is wrong with the value, or undefined if it fits
+ */
+export function checkConfigValue(
+	key: string,
+	value: unknown,
+): string | undefined {
+	const { type, values, check, checkEntry } = getConfigKey(key);
+
+	switch (type) {
+		case "boolean":
+			return typeof value === "boolean" ? undefined : "expected true or false";
+		case "integer":
+			return Number.isInteger(value) && (value as number) > 0
+				? undefined
+				: "expected a positive integer";
+		case "number":
+			return typeof value === "number" && Number.isFinite(value) && value > 0
+				? undefined
+				: "expected a positive number";
+		case "string":
+			if (typeof value !== "string") {
+				return "expected a string";
+			}
+			if (values && !values.includes(value)) {
+				return `expected one of ${values.join(", ")}`;
+			}
+			return check?.(value);
+		case "list":
+			if (
+				!Array.isArray(value) ||
+				!value.every((item) => typeof item === "string")
+			) {
+				return "expected a list of strings";
+			}
+			return value.map((item) => check?.(item)).find(Boolean);
+		case "map":
+			if (typeof value !== "object" || value === null || Array.isArray(value)) {
+				return "expected a JSON object";
+			}
+			return Object.entries(value)
+				.map(([name, entry]) => check?.(name) ?? checkEntry?.(entry))
+				.find(Boolean);
+	}
+}
+
+/**
+ * Convert a command-line value to the type of a configuration key
+ *
+ * @param key - Key name
+ * @param raw - Value as typed by the user
+ * @returns The typed value
+ * @throws InvalidConfigError if the key is unknown or the value does not fit
+ */
+export function parseConfigValue(
+	key: string,
+	raw: string,
+): string | number | boolean | string[] | Record<string, unknown> {
+	const value = convert(getConfigKey(key).type, raw);
+	const problem = checkConfigValue(key, value);
+	if (problem) {
+		throw new InvalidConfigError(
+			`Invalid value for ${key}: '${raw}' (${problem})`,
+		);
+	}
+	return value;
+}
+
+/**
+ * Convert typed text to a value of the given type, leaving text that does not
+ * convert for checkConfigValue to reject
+ */
+function convert(
+	type: ConfigValueType,
+	raw: string,
+): string | number | boolean | string[] | Record<string, unknown> {
+	switch (type) {
+		case "boolean": {
+			const normalized = raw.trim().toLowerCase();
+			if (["true", "yes", "on", "1"].includes(normalized)) {
+				return true;
+			}
+			if (["false", "no", "off", "0"].includes(normalized)) {
+				return false;
+			}
+			return raw;
+		}
+		case "integer":
+		case "number":
+			return raw.trim() ? Number(raw) : Number.NaN;
+		case "string":
+			return raw;
+		case "list":
+			return raw
+				.split(",")
+				.map((item) => item.trim())
+				.filter(Boolean);
+		case "map":
+			try {
+				return JSON.parse(raw);
+			} catch {
+				return raw;
+			}
+	}
+}
@@ -0,0 +1,164 @@
+/**
+ * Crash report contents
+ *
+ * A crash is a failure claude-cmd did not anticipate: an error thrown by the
+ * runtime itself (TypeError, RangeError, ...) rather than one of the typed
+ * errors services raise for expected conditions like a missing command or an
+ * unreachable repository. Crash reports collect what a bug report needs and
+ * are anonymized: the home directory becomes "~", credentials in URLs and
+ * secret-looking config values are replaced.
+ */
+
+/**
+ * Errors the runtime throws for programming mistakes
+ */
+const CRASH_ERRORS = [
+	TypeError,
+	RangeError,
+	ReferenceError,
+	SyntaxError,
+	EvalError,
+	URIError,
+];
+
+/**
+ * Config keys whose values are never written to a crash report
+ */
+const SECRET_KEY = /token|secret|password|credential|auth|api[-_]?key/i;
+
+/**
+ * Replacement for redacted values
+ */
+export const REDACTED = "[redacted]";
+
+/**
+ * Check whether an error is a crash rather than an anticipated failure
+ *
+ * Wrapper errors count as crashes when their cause is one.
+ */
+export function isCrash(error: unknown): boolean {
+	if (CRASH_ERRORS.some((type) => error?.constructor === type)) {
+		return true;
+	}
+	return error instanceof Error && error.cause !== undefined
+		? isCrash(error.cause)
+		: false;
+}
+
+/**
+ * Anonymize a string: home directory paths and URL credentials
+ *
+ * @param text - Text to anonymize
+ * @param homeDir - Home directory to replace with "~"
+ */
+export function redactText(text: string, homeDir: string): string {
+	const withoutCredentials = text.replace(
+		/([a-z][a-z0-9+.-]*:\/\/)[^/\s@]+@/gi,
+		`$1${REDACTED}@`,
+	);
+	return homeDir
+		? withoutCredentials.split(homeDir).join("~")
+		: withoutCredentials;
+}
+
+/**
+ * Anonymize command line arguments for a crash report
+ *
+ * Besides redactText(), the value of a secret-looking option is replaced
+ * whether it is given as "--token=value" or as the next argument, and so is
+ * the argument after a secret-looking config key (`config set apiToken abc`).
+ *
+ * @param args - Arguments after the executable and script
+ * @param homeDir - Home directory to replace with "~"
+ */
+export function redactArgs(
+	args: readonly string[],
+	homeDir: string,
+): string[] {
+	let redactNext = false;
+	return args.map((arg) => {
+		if (redactNext) {
+			redactNext = false;
+			if (!arg.startsWith("-")) {
+				return REDACTED;
+			}
+		}
+		const option = /^(--?[^=]+)=/.exec(arg);
+		if (option?.[1] && SECRET_KEY.test(option[1])) {
+			return `${option[1]}=${REDACTED}`;
+		}
+		redactNext = SECRET_KEY.test(arg);
+		return redactText(arg, homeDir);
+	});
+}
+
+/**
+ * Anonymize a configuration for a crash report
+ *
+ * Secret-looking keys are redacted. Hook commands are shell lines that may
+ * carry credentials, so only the events they run on are kept.
+ *
+ * @param value - Config (or any JSON value inside it)
+ * @param homeDir - Home directory to replace with "~"
+ */
+export function redactConfig(value: unknown, homeDir: string): unknown {
+	if (typeof value === "string") {
+		return redactText(value, homeDir);
+	}
+	if (Array.isArray(value)) {
+		return value.map((item) => redactConfig(item, homeDir));
+	}
+	if (value && typeof value === "object") {
+		return Object.fromEntries(
+			Object.entries(value).map(([key, entry]) => {
+				if (SECRET_KEY.test(key)) {
+					return [key, REDACTED];
+				}
+				if (key === "hooks" && entry && typeof entry === "object") {
+					return [
+						key,
+						Object.fromEntries(
+							Object.keys(entry).map((event) => [event, REDACTED]),
+						),
+					];
+				}
+				return [key, redactConfig(entry, homeDir)];
+			}),
+		);
+	}
+	return value;
+}
+
+/**
+ * An error as written to a crash report, causes included
+ */
+export interface CrashReportError {
+	readonly name: string;
+	readonly message: string;
+	readonly stack?: string;
+	readonly cause?: CrashReportError;
+}
+
+/**
+ * Describe an error and its causes for a crash report
+ *
+ * @param error - Error to describe
+ * @param homeDir - Home directory to replace with "~"
+ */
+export function describeCrashError(
+	error: unknown,
+	homeDir: string,
+): CrashReportError {
+	if (!(error instanceof Error)) {
+		return { name: "Error", message: redactText(String(error), homeDir) };
+	}
+	return {
+		name: error.name,
+		message: redactText(error.message, homeDir),
+		stack: error.stack ? redactText(error.stack, homeDir) : undefined,
+		cause:
+			error.cause !== undefined
+				? describeCrashError(error.cause, homeDir)
+				: undefined,
+	};
+}
@@ -0,0 +1,78 @@
+/**
+ * Resolution of the "requires" field commands use to declare other commands
+ * they depend on
+ */
+
+/**
+ * Error thrown when command requirements form a cycle
+ */
+export class DependencyCycleError extends Error {
+	/** Commands along the cycle, starting and ending with the same name */
+	public readonly cycle: readonly string[];
+
+	constructor(cycle: readonly string[]) {
+		super(`Circular command requirements: ${cycle.join(" -> ")}`);
+		this.name = this.constructor.name;
+		this.cycle = cycle;
+	}
+}
+
+/**
+ * Normalize a "requires" value from frontmatter or a manifest
+ *
+ * Accepts an array or a comma-separated string, like allowed-tools.
+ *
+ * @param value - Raw field value
+ * @returns Trimmed, non-empty, de-duplicated command names
+ */
+export function normalizeRequires(value: unknown): string[] {
+	const names =
+		typeof value === "string"
+			? value.split(",")
+			: Array.isArray(value)
+				? value.filter((name): name is string => typeof name === "string")
+				: [];
+	return [
+		...new Set(names.map((name) => name.trim()).filter((name) => name !== "")),
+	];
+}
+
+/**
+ * Collect everything a command transitively requires
+ *
+ * @param commandName - Command being installed
+ * @param getRequires - Looks up the direct requirements of a command
+ * @returns Required commands in install order (requirements before the
+ *   commands that need them), excluding commandName itself
+ * @throws DependencyCycleError if the requirements are circular
+ */
+export async function resolveDependencies(
+	commandName: string,
+	getRequires: (name: string) => Promise<readonly string[]>,
+): Promise<string[]> {
+	const ordered: string[] = [];
+	const done = new Set<string>();
+	const path: string[] = [];
+
+	const visit = async (name: string): Promise<void> => {
+		const start = path.indexOf(name);
+		if (start !== -1) {
+			throw new DependencyCycleError([...path.slice(start), name]);
+		}
+		if (done.has(name)) {
+			return;
+		}
+
+		path.push(name);
+		for (const required of await getRequires(name)) {
+			await visit(required);
+		}
+		path.pop();
+
+		done.add(name);
+		ordered.push(name);
+	};
+
+	await visit(commandName);
+	return ordered.filter((name) => name !== commandName);
+}
@@ -0,0 +1,103 @@
+/**
+ * Line-based diff of two text files, used to show what an overwrite changes
+ */
+
+/**
+ * One line of a diff: unchanged, removed from the old text or added by the new
+ */
+export interface DiffLine {
+	readonly kind: "same" | "removed" | "added";
+	readonly text: string;
+}
+
+/**
+ * Compute a line diff using the longest common subsequence
+ *
+ * Command files are small, so the quadratic table is not a concern.
+ *
+ * @param before - Original text
+ * @param after - Replacement text
+ * @returns Lines of both texts in order, tagged with how they changed
+ */
+export function diffLines(before: string, after: string): DiffLine[] {
+	const a = before.split("\n");
+	const b = after.split("\n");
+
+	// lengths[i][j] = LCS length of a[i..] and b[j..]
+	const lengths: number[][] = Array.from({ length: a.length + 1 }, () =>
+		new Array<number>(b.length + 1).fill(0),
+	);
+	const length = (i: number, j: number) => lengths[i]?.[j] ?? 0;
+	for (let i = a.length - 1; i >= 0; i--) {
+		const row = lengths[i] ?? [];
+		for (let j = b.length - 1; j >= 0; j--) {
+			row[j] =
+				a[i] === b[j]
+					? length(i + 1, j + 1) + 1
+					: Math.max(length(i + 1, j), length(i, j + 1));
+		}
+	}
+
+	const lines: DiffLine[] = [];
+	let i = 0;
+	let j = 0;
+	while (i < a.length && j < b.length) {
+		if (a[i] === b[j]) {
+			lines.push({ kind: "same", text: a[i] ?? "" });
+			i++;
+			j++;
+		} else if (length(i + 1, j) >= length(i, j + 1)) {
+			lines.push({ kind: "removed", text: a[i] ?? "" });
+			i++;
+		} else {
+			lines.push({ kind: "added", text: b[j] ?? "" });
+			j++;
+		}
+	}
+	for (; i < a.length; i++) {
+		lines.push({ kind: "removed", text: a[i] ?? "" });
+	}
+	for (; j < b.length; j++) {
+		lines.push({ kind: "added", text: b[j] ?? "" });
+	}
+	return lines;
+}
+
+/**
+ * Format a line diff with "-"/"+" markers
+ *
+ * @param before - Original text
+ * @param after - Replacement text
+ * @returns Diff text, or an empty string if the texts are identical
+ */
+export function formatLineDiff(before: string, after: string): string {
+	const lines = diffLines(before, after);
+	if (lines.every((line) => line.kind === "same")) {
+		return "";
+	}
+	const markers = { same: " ", removed: "-", added: "+" } as const;
+	return lines.map((line) => `${markers[line.kind]} ${line.text}`).join("\n");
+}
+
+/**
+ * Count the lines a replacement adds and removes
+ *
+ * @param before - Original text
+ * @param after - Replacement text
+ * @returns Added and removed line counts, both 0 for identical text
+ */
+export function countDiffLines(
+	before: string,
+	after: string,
+): { added: number; removed: number } {
+	let added = 0;
+	let removed = 0;
+	for (const line of diffLines(before, after)) {
+		if (line.kind === "added") {
+			added++;
+		} else if (line.kind === "removed") {
+			removed++;
+		}
+	}
+	return { added, removed };
+}
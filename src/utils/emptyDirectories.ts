@@ -0,0 +1,104 @@
+import * as path from "node:path";
+import type IFileService from "../interfaces/IFileService.js";
+import type { ScanOptions } from "../interfaces/IFileService.js";
+import { compareStrings } from "./ordering.js";
+import { isInsideDirectory, matchesDirectoryPattern } from "./paths.js";
+
+/**
+ * Helpers for namespace directories left empty when commands are removed
+ *
+ * Namespaced commands live in nested directories under a commands directory
+ * ("frontend:react:component" -> "frontend/react/component.md"). Removing the
+ * last command of a namespace would otherwise leave its directories behind.
+ */
+
+/**
+ * Remove the empty directories between a deleted file and its base directory
+ *
+ * Walks up from the file's parent and stops at the first directory that
+ * still has content. The base directory itself is never removed, and
+ * nothing outside it is touched.
+ *
+ * @param fileService - File service used to remove directories
+ * @param filePath - Path of the file that was deleted
+ * @param baseDir - Commands directory the file belonged to
+ * @returns Removed directories, deepest first
+ */
+export async function removeEmptyParentDirectories(
+	fileService: IFileService,
+	filePath: string,
+	baseDir: string,
+): Promise<string[]> {
+	const base = path.resolve(baseDir);
+	const removed: string[] = [];
+	let current = path.dirname(path.resolve(filePath));
+
+	while (isInsideDirectory(current, base)) {
+		const directory = path.join(baseDir, path.relative(base, current));
+		try {
+			if (!(await fileService.removeEmptyDirectory(directory))) {
+				break;
+			}
+		} catch {
+			// Already gone or not removable; either way there is nothing to do
+			break;
+		}
+		removed.push(directory);
+		current = path.dirname(current);
+	}
+
+	return removed;
+}
+
+/**
+ * Find directories under a base directory that contain no files at any depth
+ *
+ * Only the outermost directory of an empty tree is reported, so "a" stands
+ * for both "a" and "a/b" when neither holds a file. Ignored and too deep
+ * directories are pruned like in a command scan: they are not read, so they
+ * and the directories above them never count as empty.
+ *
+ * @param fileService - File service used to scan the directory
+ * @param baseDir - Commands directory to scan
+ * @param options - Depth limit and ignored directories (default: none)
+ * @returns Relative paths of empty directories, sorted
+ */
+export async function findEmptyDirectories(
+	fileService: IFileService,
+	baseDir: string,
+	options?: ScanOptions,
+): Promise<string[]> {
+	const [directories, files] = await Promise.all([
+		fileService.listDirectoriesRecursive(baseDir, options),
+		fileService.listFilesRecursive(baseDir, options),
+	]);
+	const normalize = (relative: string) => relative.split(path.sep).join("/");
+	const filePaths = files.map(normalize);
+	const directoryPaths = directories.map(normalize);
+
+	// Pruned directories were not read, so their content is unknown
+	const { maxDepth = Number.POSITIVE_INFINITY, ignore = [] } = options ?? {};
+	const unread = directoryPaths.filter((directory) => {
+		const names = directory.split("/");
+		return (
+			names.length > maxDepth ||
+			names.some((name) => matchesDirectoryPattern(name, ignore))
+		);
+	});
+
+	const empty = directoryPaths
+		.filter(
+			(directory) =>
+				!filePaths.some((file) => file.startsWith(`${directory}/`)) &&
+				!unread.some(
+					(other) =>
+						other === directory || other.startsWith(`${directory}/`),
+				),
+		)
+		.sort(compareStrings);
+
+	return empty.filter(
+		(directory) =>
+			!empty.some((other) => directory.startsWith(`${other}/`)),
+	);
+}
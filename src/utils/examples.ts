@@ -0,0 +1,158 @@
+/**
+ * A usage example of a command, from the `examples` list of its frontmatter
+ */
+export interface CommandExample {
+	/** Arguments the command is run with (empty for none) */
+	readonly arguments: string;
+	/** What running it does */
+	readonly description?: string;
+}
+
+/**
+ * Examples of a command, with the entries that had to be left out
+ */
+export interface ParsedExamples {
+	readonly examples: CommandExample[];
+	/** Why each left-out entry is unusable, in list order */
+	readonly problems: string[];
+}
+
+/**
+ * Parse the `examples` frontmatter field
+ *
+ * Entries are either a string, the arguments or the whole invocation (e.g.,
+ * "src/app.ts" or "/review src/app.ts"), or an object with `args` and
+ * `description`:
+ *
+ * ```yaml
+ * examples:
+ *   - /review src/app.ts
+ *   - args: --staged
+ *     description: Review the staged changes
+ * ```
+ *
+ * Invocations must name the command itself, by its full or its last name
+ * segment, and every example must fit on one line.
+ *
+ * @param value - The field as parsed from YAML (undefined when absent)
+ * @param commandName - Name of the command the examples belong to
+ * @returns Usable examples and the problems of the others
+ */
+export function parseExamples(
+	value: unknown,
+	commandName: string,
+): ParsedExamples {
+	if (value === undefined || value === null) {
+		return { examples: [], problems: [] };
+	}
+	if (!Array.isArray(value)) {
+		return { examples: [], problems: ["examples must be a list"] };
+	}
+
+	const examples: CommandExample[] = [];
+	const problems: string[] = [];
+	for (const [index, entry] of value.entries()) {
+		const parsed = parseExample(entry, commandName);
+		if (typeof parsed === "string") {
+			problems.push(`example ${index + 1}: ${parsed}`);
+		} else {
+			examples.push(parsed);
+		}
+	}
+	return { examples, problems };
+}
+
+/**
+ * Parse the `examples` field of a manifest entry
+ *
+ * Manifests built from command files list examples as parsed, with
+ * `arguments` and `description`; entries written as in frontmatter (see
+ * parseExamples()) are read too.
+ *
+ * @param value - The field as parsed from JSON (undefined when absent)
+ * @param commandName - Name of the command the examples belong to
+ * @returns Usable examples and the problems of the others
+ */
+export function parseManifestExamples(
+	value: unknown,
+	commandName: string,
+): ParsedExamples {
+	return parseExamples(
+		Array.isArray(value) ? value.map(asFrontmatterEntry) : value,
+		commandName,
+	);
+}
+
+/**
+ * Write a parsed example ({arguments, description}) as a frontmatter entry
+ */
+function asFrontmatterEntry(entry: unknown): unknown {
+	if (
+		typeof entry !== "object" ||
+		entry === null ||
+		!("arguments" in entry) ||
+		"args" in entry
+	) {
+		return entry;
+	}
+	const { arguments: args, ...fields } = entry as Record<string, unknown>;
+	return { ...fields, args };
+}
+
+/**
+ * Parse one example
+ *
+ * @returns The example, or why it is unusable
+ */
+function parseExample(
+	entry: unknown,
+	commandName: string,
+): CommandExample | string {
+	let text: unknown = entry;
+	let description: string | undefined;
+	if (typeof entry === "object" && entry !== null && !Array.isArray(entry)) {
+		const fields = entry as Record<string, unknown>;
+		text = fields.args ?? "";
+		if (typeof fields.description === "string") {
+			description = fields.description;
+		} else if (fields.description !== undefined) {
+			return "description must be a string";
+		}
+	}
+	if (typeof text !== "string") {
+		return "expected arguments, an invocation or {args, description}";
+	}
+	if (/[\r\n]/.test(text)) {
+		return "must fit on one line";
+	}
+
+	let args = text.trim();
+	if (args.startsWith("/")) {
+		const [invoked = "", ...rest] = args.slice(1).split(/\s+/);
+		const lastSegment = commandName.split(":").at(-1);
+		if (invoked !== commandName && invoked !== lastSegment) {
+			return `runs /${invoked}, not /${commandName}`;
+		}
+		args = rest.join(" ");
+	}
+
+	const trimmed = description?.trim();
+	return trimmed
+		? { arguments: args, description: trimmed }
+		: { arguments: args };
+}
+
+/**
+ * Format an example as the slash command line that runs it
+ *
+ * @param commandName - Name the command is run by (e.g., "frontend:component")
+ * @param example - Example to format
+ */
+export function formatExample(
+	commandName: string,
+	example: CommandExample,
+): string {
+	return example.arguments
+		? `/${commandName} ${example.arguments}`
+		: `/${commandName}`;
+}
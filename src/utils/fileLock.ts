@@ -0,0 +1,224 @@
+import { randomBytes } from "node:crypto";
+import * as os from "node:os";
+import type IClock from "../interfaces/IClock.js";
+import type IFileService from "../interfaces/IFileService.js";
+import { FileNotFoundError } from "../interfaces/IFileService.js";
+import SystemClock from "../services/SystemClock.js";
+
+/**
+ * Contents of a lock file, used to detect abandoned locks
+ */
+interface LockOwner {
+	/** Process id of the holder */
+	pid: number;
+	/** Host name of the holder (pid checks only work on the same host) */
+	hostname: string;
+	/** When the lock was acquired (milliseconds since Unix epoch) */
+	acquiredAt: number;
+	/** Random token distinguishing holders within one process */
+	token: string;
+}
+
+/**
+ * Options for acquiring a file lock
+ */
+export interface FileLockOptions {
+	/** Clock used to stamp and age locks (defaults to system time) */
+	clock?: IClock;
+	/** Age after which a lock is considered abandoned (default: 30s) */
+	staleMs?: number;
+	/** How long to wait for the lock before giving up (default: 10s) */
+	timeoutMs?: number;
+	/** Delay between acquisition attempts (default: 50ms) */
+	retryDelayMs?: number;
+}
+
+/**
+ * Error thrown when a lock cannot be acquired in time
+ */
+export class LockTimeoutError extends Error {
+	constructor(
+		public readonly lockPath: string,
+		public readonly timeoutMs: number,
+	) {
+		super(
+			`Timed out after ${timeoutMs}ms waiting for lock: ${lockPath}. ` +
+				"If no other claude-cmd process is running, delete the lock file.",
+		);
+		this.name = this.constructor.name;
+	}
+}
+
+const DEFAULT_STALE_MS = 30000;
+const DEFAULT_TIMEOUT_MS = 10000;
+const DEFAULT_RETRY_DELAY_MS = 50;
+
+/**
+ * Run a function while holding an advisory lock file
+ *
+ * The lock is a file created exclusively at lockPath and removed when the
+ * function settles. Cooperating processes wait for it; a lock whose holder
+ * died (same host, process gone) or that is older than staleMs is taken
+ * over. Locks are advisory: code that does not call this is not blocked.
+ *
+ * @param fileService - File service used to create and remove the lock
+ * @param lockPath - Path of the lock file
+ * @param fn - Function to run while holding the lock
+ * @param options - Timing options
+ * @returns The result of fn
+ * @throws LockTimeoutError if the lock is not acquired within timeoutMs
+ */
+export async function withFileLock<T>(
+	fileService: IFileService,
+	lockPath: string,
+	fn: () => Promise<T>,
+	options: FileLockOptions = {},
+): Promise<T> {
+	const owner = await acquire(fileService, lockPath, options);
+	try {
+		return await fn();
+	} finally {
+		await release(fileService, lockPath, owner);
+	}
+}
+
+async function acquire(
+	fileService: IFileService,
+	lockPath: string,
+	options: FileLockOptions,
+): Promise<string> {
+	const clock = options.clock ?? new SystemClock();
+	const staleMs = options.staleMs ?? DEFAULT_STALE_MS;
+	const timeoutMs = options.timeoutMs ?? DEFAULT_TIMEOUT_MS;
+	const retryDelayMs = options.retryDelayMs ?? DEFAULT_RETRY_DELAY_MS;
+	// Attempts rather than elapsed time, so a fake clock cannot stall the wait
+	const maxAttempts = Math.max(1, Math.ceil(timeoutMs / retryDelayMs));
+
+	for (let attempt = 1; ; attempt++) {
+		const owner = JSON.stringify({
+			pid: process.pid,
+			hostname: os.hostname(),
+			acquiredAt: clock.now(),
+			token: randomBytes(4).toString("hex"),
+		} satisfies LockOwner);
+
+		if (await fileService.createFile(lockPath, owner)) {
+			return owner;
+		}
+
+		if (await removeIfStale(fileService, lockPath, clock.now(), staleMs)) {
+			continue;
+		}
+
+		if (attempt >= maxAttempts) {
+			throw new LockTimeoutError(lockPath, timeoutMs);
+		}
+		await new Promise((resolve) => setTimeout(resolve, retryDelayMs));
+	}
+}
+
+async function release(
+	fileService: IFileService,
+	lockPath: string,
+	owner: string,
+): Promise<void> {
+	// Only remove our own lock; it may have been taken over as stale
+	const current = await readLock(fileService, lockPath);
+	if (current === owner) {
+		await fileService.deleteFile(lockPath).catch(() => {});
+	}
+}
+
+/**
+ * Remove the lock file if its holder is gone or it is too old
+ *
+ * The content is re-read right before deleting so a lock that was replaced
+ * in the meantime is left alone. A tiny window remains between that read
+ * and the delete; it only matters when two waiters recover the same stale
+ * lock at once.
+ *
+ * @returns True if a stale lock was removed (or had already disappeared)
+ */
+async function removeIfStale(
+	fileService: IFileService,
+	lockPath: string,
+	now: number,
+	staleMs: number,
+): Promise<boolean> {
+	const content = await readLock(fileService, lockPath);
+	if (content === undefined) {
+		return true;
+	}
+
+	if (!isStale(parseOwner(content), now, staleMs)) {
+		return false;
+	}
+
+	if ((await readLock(fileService, lockPath)) !== content) {
+		return true;
+	}
+	await fileService.deleteFile(lockPath).catch(() => {});
+	return true;
+}
+
+function isStale(
+	owner: LockOwner | undefined,
+	now: number,
+	staleMs: number,
+): boolean {
+	// An unreadable lock may be mid-creation by another process; leave it to
+	// the timeout rather than risk breaking a live lock
+	if (!owner) {
+		return false;
+	}
+	if (now - owner.acquiredAt > staleMs) {
+		return true;
+	}
+	return owner.hostname === os.hostname() && !isProcessAlive(owner.pid);
+}
+
+/**
+ * Check whether a process with the given id is running on this host
+ *
+ * @param pid - Process id
+ * @returns False only if the process is known to be gone
+ */
+export function isProcessAlive(pid: number): boolean {
+	try {
+		// Signal 0 checks for existence without affecting the process
+		process.kill(pid, 0);
+		return true;
+	} catch (error) {
+		return (error as NodeJS.ErrnoException).code !== "ESRCH";
+	}
+}
+
+async function readLock(
+	fileService: IFileService,
+	lockPath: string,
+): Promise<string | undefined> {
+	try {
+		return await fileService.readFile(lockPath);
+	} catch (error) {
+		if (error instanceof FileNotFoundError) {
+			return undefined;
+		}
+		throw error;
+	}
+}
+
+function parseOwner(content: string): LockOwner | undefined {
+	try {
+		const parsed = JSON.parse(content);
+		if (
+			typeof parsed?.pid === "number" &&
+			typeof parsed.hostname === "string" &&
+			typeof parsed.acquiredAt === "number"
+		) {
+			return parsed as LockOwner;
+		}
+	} catch {
+		// Fall through to treat the lock as unreadable
+	}
+	return undefined;
+}
@@ -0,0 +1,171 @@
+/**
+ * Human-readable formatting helpers shared by CLI output formatters
+ */
+
+/**
+ * Format duration in human-readable format
+ *
+ * @param ms - Duration in milliseconds
+ * @returns Formatted duration string (e.g., "2d 3h", "5m 10s")
+ */
+export function formatDuration(ms: number): string {
+	const seconds = Math.floor(ms / 1000);
+	const minutes = Math.floor(seconds / 60);
+	const hours = Math.floor(minutes / 60);
+	const days = Math.floor(hours / 24);
+
+	if (days > 0) {
+		return `${days}d ${hours % 24}h`;
+	}
+	if (hours > 0) {
+		return `${hours}h ${minutes % 60}m`;
+	}
+	if (minutes > 0) {
+		return `${minutes}m ${seconds % 60}s`;
+	}
+	return `${seconds}s`;
+}
+
+/**
+ * Units used for relative ages, largest first, with their length in seconds
+ */
+const RELATIVE_TIME_UNITS: readonly [Intl.RelativeTimeFormatUnit, number][] = [
+	["day", 24 * 60 * 60],
+	["hour", 60 * 60],
+	["minute", 60],
+];
+
+/**
+ * Format how long ago something happened, in the words of a language
+ *
+ * Uses the largest whole unit, so ages read like "3 days ago" in English or
+ * "il y a 3 jours" in French.
+ *
+ * @param ms - Age in milliseconds
+ * @param locale - Language code (default: English)
+ * @returns Localized relative time (e.g., "2 hours ago", "yesterday")
+ */
+export function formatRelativeAge(ms: number, locale = "en"): string {
+	const seconds = Math.max(Math.floor(ms / 1000), 0);
+	const formatter = new Intl.RelativeTimeFormat(locale, { numeric: "auto" });
+	for (const [unit, length] of RELATIVE_TIME_UNITS) {
+		if (seconds >= length) {
+			return formatter.format(-Math.floor(seconds / length), unit);
+		}
+	}
+	return formatter.format(-seconds, "second");
+}
+
+/**
+ * Format a count with the digit grouping of a language
+ *
+ * Without a language the digits are not grouped, so machine-read output is
+ * the same on every system.
+ *
+ * @param count - Number to format
+ * @param locale - Language code (default: none)
+ * @returns Localized number (e.g., "12,345" in English, "12 345" in French)
+ */
+export function formatCount(count: number, locale?: string): string {
+	return locale === undefined
+		? String(count)
+		: new Intl.NumberFormat(locale).format(count);
+}
+
+/**
+ * Format a count followed by the singular or plural form of a noun
+ *
+ * The nouns are in the given language, whose plural rules pick the form
+ * (English without a language).
+ *
+ * @param count - Number of things
+ * @param singular - Noun for one thing (e.g., "command")
+ * @param plural - Noun for several things (e.g., "commands")
+ * @param locale - Language code (default: none, see formatCount())
+ * @returns The count and noun (e.g., "1 command", "1,024 commands")
+ */
+export function formatQuantity(
+	count: number,
+	singular: string,
+	plural: string,
+	locale?: string,
+): string {
+	const rules = new Intl.PluralRules(locale ?? "en");
+	const noun = rules.select(count) === "one" ? singular : plural;
+	return `${formatCount(count, locale)} ${noun}`;
+}
+
+/**
+ * Format file size in human-readable format
+ *
+ * @param bytes - Size in bytes
+ * @returns Formatted size string (e.g., "512 B", "1.5 KB")
+ */
+export function formatFileSize(bytes: number): string {
+	const units = ["B", "KB", "MB", "GB"];
+	let size = bytes;
+	let unitIndex = 0;
+
+	while (size >= 1024 && unitIndex < units.length - 1) {
+		size /= 1024;
+		unitIndex++;
+	}
+
+	return `${size.toFixed(unitIndex === 0 ? 0 : 1)} ${units[unitIndex]}`;
+}
+
+/**
+ * Limits for previews of command content
+ */
+export interface PreviewLimits {
+	/** Maximum number of lines to show */
+	readonly maxLines: number;
+	/** Maximum number of characters to show */
+	readonly maxCharacters: number;
+}
+
+/**
+ * Preview limits for info --detailed when only one of them is configured
+ */
+export const DEFAULT_PREVIEW_LIMITS: PreviewLimits = {
+	maxLines: 10,
+	maxCharacters: 500,
+};
+
+/**
+ * Truncate content to a preview, cutting at whichever limit is reached first
+ *
+ * @param content - Full content
+ * @param limits - Line and character limits
+ * @returns The preview and the number of lines left out (0 if nothing was cut)
+ */
+export function truncatePreview(
+	content: string,
+	limits: PreviewLimits,
+): { preview: string; omittedLines: number } {
+	const lines = content.split("\n");
+	let preview = lines.slice(0, limits.maxLines).join("\n");
+	if (preview.length > limits.maxCharacters) {
+		preview = preview.slice(0, limits.maxCharacters);
+	}
+	if (preview.length === content.length) {
+		return { preview, omittedLines: 0 };
+	}
+
+	const shownLines = preview.split("\n").length;
+	return { preview, omittedLines: Math.max(lines.length - shownLines, 1) };
+}
+
+/**
+ * Shorten text to a width, marking the cut with an ellipsis
+ *
+ * @param text - Text to shorten
+ * @param width - Maximum length in characters
+ * @returns The text, or its first width - 1 characters followed by "…"
+ */
+export function truncateText(text: string, width: number): string {
+	if (text.length <= width) {
+		return text;
+	}
+	return width > 1 ? `${text.slice(0, width - 1)}…` : text.slice(0, width);
+}
@@ -0,0 +1,155 @@
+/**
+ * Line-level edits of YAML frontmatter that leave the rest of the file,
+ * including formatting and comments, untouched
+ */
+
+const FRONTMATTER_PATTERN = /^---\r?\n([\s\S]*?)\r?\n---/;
+
+/**
+ * Replace the value of a top-level frontmatter field
+ *
+ * Files without frontmatter or without the field are returned unchanged:
+ * adding frontmatter would switch the parser into frontmatter mode, which
+ * requires a description.
+ *
+ * @param content - Command file content
+ * @param key - Top-level field name (e.g., "name")
+ * @param value - New value, written as a quoted YAML string
+ * @returns Content with the field rewritten
+ */
+export function rewriteFrontmatterField(
+	content: string,
+	key: string,
+	value: string,
+): string {
+	const match = content.match(FRONTMATTER_PATTERN);
+	if (!match?.[1]) {
+		return content;
+	}
+
+	const escapedKey = key.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+	const field = new RegExp(`^${escapedKey}:.*$`, "m");
+	if (!field.test(match[1])) {
+		return content;
+	}
+
+	const body = match[1].replace(
+		field,
+		() => `${key}: ${JSON.stringify(value)}`,
+	);
+	return content.replace(match[1], () => body);
+}
+
+/**
+ * Where an installed command came from, recorded in its frontmatter
+ */
+export interface Provenance {
+	/** Repository URL the command was installed from */
+	readonly source: string;
+	/** Repository version at install time */
+	readonly version: string;
+	/** SHA-256 (hex) of the file as published, without these fields */
+	readonly sha256: string;
+	/** ISO 8601 timestamp of the installation */
+	readonly installedAt: string;
+}
+
+/**
+ * Prefix of the provenance fields, kept apart from fields Claude Code reads
+ */
+const PROVENANCE_PREFIX = "claude-cmd-";
+
+const PROVENANCE_FIELDS: Record<keyof Provenance, string> = {
+	source: `${PROVENANCE_PREFIX}source`,
+	version: `${PROVENANCE_PREFIX}version`,
+	sha256: `${PROVENANCE_PREFIX}sha256`,
+	installedAt: `${PROVENANCE_PREFIX}installed-at`,
+};
+
+const PROVENANCE_KEYS = Object.keys(PROVENANCE_FIELDS) as Array<
+	keyof Provenance
+>;
+
+const PROVENANCE_LINE = new RegExp(
+	`^(?:${Object.values(PROVENANCE_FIELDS).join("|")}):.*(?:\\r?\\n|$)`,
+	"gm",
+);
+
+/**
+ * Append provenance fields to the end of the frontmatter
+ *
+ * Existing provenance is replaced. Files without frontmatter are returned
+ * unchanged, for the same reason as in rewriteFrontmatterField().
+ *
+ * @param content - Command file content as published
+ * @param provenance - Where the file came from
+ * @returns Content with the provenance fields
+ */
+export function addProvenance(content: string, provenance: Provenance): string {
+	const stripped = stripProvenance(content);
+	const match = stripped.match(FRONTMATTER_PATTERN);
+	if (match?.[1] === undefined) {
+		return stripped;
+	}
+
+	const newline = stripped.includes("\r\n") ? "\r\n" : "\n";
+	const lines = PROVENANCE_KEYS.map(
+		(key) => `${PROVENANCE_FIELDS[key]}: ${JSON.stringify(provenance[key])}`,
+	);
+	const body = [match[1], ...lines].filter(Boolean).join(newline);
+	return stripped.replace(match[0], () => `---${newline}${body}${newline}---`);
+}
+
+/**
+ * Remove provenance fields, restoring the file as published
+ *
+ * @param content - Installed command file content
+ * @returns Content without provenance fields; unchanged if there were none
+ */
+export function stripProvenance(content: string): string {
+	const match = content.match(FRONTMATTER_PATTERN);
+	if (!match?.[1]) {
+		return content;
+	}
+	const body = match[1].replace(PROVENANCE_LINE, "").replace(/\r?\n$/, "");
+	return content.replace(match[1], () => body);
+}
+
+/**
+ * Read the provenance fields of an installed command file
+ *
+ * @param content - Installed command file content
+ * @returns The provenance, or undefined unless every field is present
+ */
+export function readProvenance(content: string): Provenance | undefined {
+	const frontmatter = content.match(FRONTMATTER_PATTERN)?.[1];
+	if (!frontmatter) {
+		return undefined;
+	}
+
+	const values: Partial<Record<keyof Provenance, string>> = {};
+	for (const key of PROVENANCE_KEYS) {
+		const line = frontmatter.match(
+			new RegExp(`^${PROVENANCE_FIELDS[key]}:\\s*(.*?)\\s*$`, "m"),
+		);
+		if (!line?.[1]) {
+			return undefined;
+		}
+		values[key] = unquote(line[1]);
+	}
+	return values as Provenance;
+}
+
+/**
+ * Read a scalar written by addProvenance() (a JSON string) or by hand
+ */
+function unquote(value: string): string {
+	if (value.startsWith('"')) {
+		try {
+			return JSON.parse(value);
+		} catch {
+			// Not JSON after all; use it as written
+		}
+	}
+	return value.replace(/^'(.*)'$/, "$1");
+}
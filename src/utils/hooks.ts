@@ -0,0 +1,162 @@
+import { createHash } from "node:crypto";
+import * as path from "node:path";
+
+/**
+ * Project hooks: shell commands a project runs around add, remove and
+ * cache update
+ */
+
+/**
+ * Operations hooks can run around
+ */
+export const HOOK_OPERATIONS = ["add", "remove", "update"] as const;
+
+/**
+ * Operation a hook runs around
+ */
+export type HookOperation = (typeof HOOK_OPERATIONS)[number];
+
+/**
+ * Events hooks are configured for, e.g. "pre-add" or "post-update"
+ */
+export const HOOK_EVENTS = HOOK_OPERATIONS.flatMap((operation) => [
+	`pre-${operation}` as const,
+	`post-${operation}` as const,
+]);
+
+/**
+ * Event hooks are configured for
+ */
+export type HookEvent = `${"pre" | "post"}-${HookOperation}`;
+
+/**
+ * Shell commands configured for one event: one command or several, run in
+ * order
+ */
+export type HookCommands = string | readonly string[];
+
+/**
+ * Check whether a value names a hook event
+ */
+export function isHookEvent(value: string): value is HookEvent {
+	return (HOOK_EVENTS as readonly string[]).includes(value);
+}
+
+/**
+ * Check the value configured for one event
+ */
+export function isHookCommands(value: unknown): value is HookCommands {
+	return (
+		(typeof value === "string" && value.trim() !== "") ||
+		(Array.isArray(value) &&
+			value.every(
+				(command) => typeof command === "string" && command.trim() !== "",
+			))
+	);
+}
+
+/**
+ * Check the shape of the `hooks` config key
+ *
+ * @param value - Value read from a config file
+ * @returns True if every key is a hook event and every value hook commands
+ */
+export function isHookMap(
+	value: unknown,
+): value is Partial<Record<HookEvent, HookCommands>> {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		!Array.isArray(value) &&
+		Object.entries(value).every(
+			([event, commands]) => isHookEvent(event) && isHookCommands(commands),
+		)
+	);
+}
+
+/**
+ * List the shell commands configured for an event
+ *
+ * @param hooks - The `hooks` config value, if any
+ * @param event - Event about to happen
+ * @returns Commands in the order they run
+ */
+export function hookCommandsFor(
+	hooks: Partial<Record<HookEvent, HookCommands>> | undefined,
+	event: HookEvent,
+): string[] {
+	const commands = hooks?.[event];
+	if (commands === undefined) {
+		return [];
+	}
+	return typeof commands === "string" ? [commands] : [...commands];
+}
+
+/**
+ * Answer remembered for the hooks of one project
+ *
+ * Stored by project root under the `projectHookTrust` config key. It only
+ * applies while the hooks are unchanged, so edited hooks are reviewed again.
+ */
+export interface HookTrust {
+	/** Whether the hooks may run */
+	readonly allow: boolean;
+	/** hooksFingerprint() of the hooks the answer was given for */
+	readonly fingerprint: string;
+}
+
+/**
+ * Identify a set of hook definitions
+ *
+ * Events are taken in HOOK_EVENTS order, so the fingerprint only changes
+ * when a command does.
+ *
+ * @param hooks - The `hooks` config value of a project
+ * @returns SHA-256 of the definitions, as hex
+ */
+export function hooksFingerprint(
+	hooks: Partial<Record<HookEvent, HookCommands>>,
+): string {
+	const definitions = HOOK_EVENTS.map((event) => [
+		event,
+		hookCommandsFor(hooks, event),
+	]);
+	return createHash("sha256")
+		.update(JSON.stringify(definitions), "utf8")
+		.digest("hex");
+}
+
+/**
+ * Check the shape of a value read from the `projectHookTrust` config key
+ *
+ * @param value - One entry of the map
+ * @returns True if the value is a HookTrust
+ */
+export function isHookTrust(value: unknown): value is HookTrust {
+	if (typeof value !== "object" || value === null) {
+		return false;
+	}
+	const { allow, fingerprint } = value as Record<string, unknown>;
+	return (
+		typeof allow === "boolean" &&
+		typeof fingerprint === "string" &&
+		/^[0-9a-f]{64}$/.test(fingerprint)
+	);
+}
+
+/**
+ * Check the shape of the `projectHookTrust` config key: absolute project
+ * roots mapped to the answer given for their hooks
+ */
+export function isHookTrustMap(
+	value: unknown,
+): value is Record<string, HookTrust> {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		!Array.isArray(value) &&
+		Object.entries(value).every(
+			([root, trust]) => path.isAbsolute(root) && isHookTrust(trust),
+		)
+	);
+}
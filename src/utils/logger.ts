@@ -1,4 +1,11 @@
-import { configure, getConsoleSink, getLogger } from "@logtape/logtape";
+import {
+	configure,
+	getConsoleSink,
+	getLogger,
+	type LogLevel,
+	type LogRecord,
+	type Sink,
+} from "@logtape/logtape";
 
 /**
  * Centralized logger configuration for claude-cmd
@@ -8,29 +15,71 @@ import { configure, getConsoleSink, getLogger } from "@logtape/logtape";
  *   - file (real/mock)
  *   - http (real/mock)
  *   - repo (real)
+ *   - cache (real)
  *   - install (real)
  *   - interaction (real/mock)
+ *   - cli (real)
+ *
+ * Verbosity:
+ * - default: info and above, human-readable
+ * - --verbose (-V): debug and above, human-readable
+ * - --debug: everything including trace, one structured line per record
+ *   (timestamp, level, category, message and key=value properties) on stderr
  */
 
 let isConfigured = false;
 
+/**
+ * Log levels accepted from LOG_LEVEL, mapped to LogTape levels
+ */
+const LOG_LEVEL_ALIASES: Record<string, LogLevel> = {
+	trace: "trace",
+	debug: "debug",
+	info: "info",
+	warn: "warning",
+	warning: "warning",
+	error: "error",
+	fatal: "fatal",
+};
+
+/**
+ * Parse a user-supplied log level name
+ *
+ * @param value - Level name, case-insensitive (e.g., "debug", "WARN")
+ * @returns The LogTape level, or undefined if the name is unknown
+ */
+export function parseLogLevel(value: string): LogLevel | undefined {
+	return LOG_LEVEL_ALIASES[value.toLowerCase()];
+}
+
+/**
+ * Logger output options
+ */
+export interface LoggerOptions {
+	/** Emit one structured line per record instead of console output */
+	structured?: boolean;
+}
+
 /**
  * Configure LogTape with the specified log level
  * This function should be called only once, early in the application lifecycle
  */
-export async function configureLogger(level: string = "info"): Promise<void> {
+export async function configureLogger(
+	level: string = "info",
+	options: LoggerOptions = {},
+): Promise<void> {
 	if (isConfigured) {
 		return; // Already configured, ignore subsequent calls
 	}
 
 	await configure({
 		sinks: {
-			console: getConsoleSink(),
+			console: options.structured ? getStructuredSink() : getConsoleSink(),
 		},
 		loggers: [
 			{
 				category: "claude-cmd",
-				lowestLevel: level as any, // Use the provided level
+				lowestLevel: parseLogLevel(level) ?? "info",
 				sinks: ["console"],
 			},
 			{
@@ -44,6 +93,42 @@ export async function configureLogger(level: string = "info"): Promise<void> {
 	isConfigured = true;
 }
 
+/**
+ * Format a log record as a single structured line
+ *
+ * @example
+ * ```
+ * 2025-01-15T10:00:00.000Z DEBUG claude-cmd.cache: cache hit: en language="en" ageMs=1200
+ * ```
+ */
+export function formatStructuredRecord(record: LogRecord): string {
+	const message = record.message
+		.map((part) => (typeof part === "string" ? part : JSON.stringify(part)))
+		.join("");
+	const properties = Object.entries(record.properties)
+		.map(([key, value]) => `${key}=${JSON.stringify(value)}`)
+		.join(" ");
+
+	return [
+		new Date(record.timestamp).toISOString(),
+		record.level.toUpperCase(),
+		`${record.category.join(".")}:`,
+		message,
+		properties,
+	]
+		.filter(Boolean)
+		.join(" ");
+}
+
+/**
+ * Sink writing structured lines to stderr, keeping stdout clean for output
+ */
+function getStructuredSink(): Sink {
+	return (record) => {
+		process.stderr.write(`${formatStructuredRecord(record)}\n`);
+	};
+}
+
 // Root logger - will be properly initialized after configure() is called
 let rootLogger: ReturnType<typeof getLogger>;
 
@@ -61,18 +146,26 @@ export function getRootLogger() {
 export const fileLogger = getLogger(["claude-cmd", "file"]);
 export const httpLogger = getLogger(["claude-cmd", "http"]);
 export const repoLogger = getLogger(["claude-cmd", "repo"]);
+export const cacheLogger = getLogger(["claude-cmd", "cache"]);
 export const installLogger = getLogger(["claude-cmd", "install"]);
 export const interactionLogger = getLogger(["claude-cmd", "interaction"]);
+export const cliLogger = getLogger(["claude-cmd", "cli"]);
+export const configLogger = getLogger(["claude-cmd", "config"]);
 
 // Export root logger getter for main.ts verbose flag control
 export { getRootLogger as rootLogger };
 
 /**
  * Enable verbose logging message
- * Called when --verbose flag is detected (but LogTape is already configured with debug level)
+ * Called when --verbose or --debug is detected (but LogTape is already configured
+ * with the matching level)
  */
-export function enableVerboseLogging(): void {
+export function enableVerboseLogging(
+	mode: "verbose" | "debug" = "verbose",
+): void {
 	// LogTape is already configured with the correct level based on early argument parsing
 	// This function just logs a confirmation message
-	getRootLogger().info("Verbose logging enabled.");
+	getRootLogger().info(
+		mode === "debug" ? "Debug logging enabled." : "Verbose logging enabled.",
+	);
 }
@@ -0,0 +1,75 @@
+import type { Command, Manifest } from "../types/Command.js";
+import { commandTags } from "./tags.js";
+
+const indexes = new WeakMap<Manifest, ManifestIndex>();
+
+/**
+ * Lookup tables over a manifest's commands
+ *
+ * Looking a command up by name or tag costs the length of the
+ * key instead of a scan of the whole manifest, which matters for manifests
+ * with thousands of commands (see bench/ManifestIndex.bench.ts). Results keep
+ * the order of the commands the index was built from; when several commands
+ * share a name, the first one wins, like Array.prototype.find().
+ */
+export class ManifestIndex {
+	private readonly byName = new Map<string, Command>();
+	/** Built on first use, so name lookups stay cheap */
+	private byTag: Map<string, Command[]> | undefined;
+
+	/**
+	 * @param commands - Commands to index, in the order results should keep
+	 */
+	constructor(readonly commands: readonly Command[]) {
+		for (const command of commands) {
+			if (!this.byName.has(command.name)) {
+				this.byName.set(command.name, command);
+			}
+		}
+	}
+
+	/**
+	 * Get the index of a manifest, building it on first use
+	 *
+	 * Indexes are kept as long as the manifest object is, so repeated
+	 * lookups in the same manifest share one index.
+	 */
+	static of(manifest: Manifest): ManifestIndex {
+		let index = indexes.get(manifest);
+		if (!index) {
+			index = new ManifestIndex(manifest.commands);
+			indexes.set(manifest, index);
+		}
+		return index;
+	}
+
+	/**
+	 * Get the command with a name
+	 */
+	get(name: string): Command | undefined {
+		return this.byName.get(name);
+	}
+
+	/**
+	 * Get the commands with a tag or category (case-insensitive)
+	 */
+	withTag(tag: string): readonly Command[] {
+		this.byTag ??= this.buildTagIndex();
+		return this.byTag.get(tag.trim().toLowerCase()) ?? [];
+	}
+
+	private buildTagIndex(): Map<string, Command[]> {
+		const byTag = new Map<string, Command[]>();
+		for (const command of this.byName.values()) {
+			for (const tag of commandTags(command)) {
+				const tagged = byTag.get(tag);
+				if (tagged) {
+					tagged.push(command);
+				} else {
+					byTag.set(tag, [command]);
+				}
+			}
+		}
+		return byTag;
+	}
+}
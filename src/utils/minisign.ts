@@ -0,0 +1,199 @@
+import {
+	createHash,
+	createPublicKey,
+	type KeyObject,
+	verify,
+} from "node:crypto";
+
+/**
+ * Verification of minisign signatures
+ *
+ * minisign (https://jedisct1.github.io/minisign/) signs files with Ed25519.
+ * A public key is the base64 line of a `minisign.pub` file; a detached
+ * signature is a `.minisig` file of four lines:
+ *
+ * ```
+ * untrusted comment: <text>
+ * <base64: algorithm (2) | key id (8) | signature (64)>
+ * trusted comment: <text>
+ * <base64: signature over the signature above and the trusted comment>
+ * ```
+ *
+ * The "Ed" algorithm signs the file itself, "ED" (the default of current
+ * minisign releases) signs its BLAKE2b-512 hash.
+ */
+
+/**
+ * Error thrown for malformed keys and signatures that do not verify
+ */
+export class SignatureError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * A parsed minisign public key
+ */
+export interface MinisignPublicKey {
+	/** Key id as written by minisign (16 upper-case hex digits) */
+	readonly keyId: string;
+	/** The Ed25519 key */
+	readonly key: KeyObject;
+}
+
+/**
+ * Parse a minisign public key
+ *
+ * @param text - The base64 key line, or a whole minisign.pub file
+ * @returns The parsed key
+ * @throws SignatureError if the text is not an Ed25519 minisign public key
+ */
+export function parsePublicKey(text: string): MinisignPublicKey {
+	const bytes = decodeLine(lastLine(text), 42, "public key");
+	if (bytes.subarray(0, 2).toString("latin1") !== "Ed") {
+		throw new SignatureError("Unsupported public key algorithm");
+	}
+	return {
+		keyId: formatKeyId(bytes.subarray(2, 10)),
+		key: createPublicKey({
+			key: {
+				kty: "OKP",
+				crv: "Ed25519",
+				x: bytes.subarray(10).toString("base64url"),
+			},
+			format: "jwk",
+		}),
+	};
+}
+
+/**
+ * Verify a detached minisign signature
+ *
+ * @param data - Signed content
+ * @param signature - Content of the .minisig file
+ * @param publicKey - Key the content must be signed with
+ * @returns The trusted comment of the signature
+ * @throws SignatureError if the signature is malformed, made with another
+ *   key or does not match the content
+ */
+export function verifySignature(
+	data: string | Uint8Array,
+	signature: string,
+	publicKey: MinisignPublicKey,
+): string {
+	const verifier = createVerifier(signature, publicKey);
+	verifier.update(typeof data === "string" ? Buffer.from(data, "utf8") : data);
+	return verifier.verify();
+}
+
+/**
+ * Verification of content read in chunks, such as a streamed download
+ */
+export interface SignatureVerifier {
+	/** Add the next chunk of the signed content */
+	update(chunk: Uint8Array): void;
+	/**
+	 * Check the signature against the content added so far
+	 *
+	 * @returns The trusted comment of the signature
+	 * @throws SignatureError if the signature does not match the content
+	 */
+	verify(): string;
+}
+
+/**
+ * Start verifying a detached minisign signature over content read in chunks
+ *
+ * "ED" signatures hash the content as it is added; legacy "Ed" signatures
+ * sign the content itself, which is then kept until verify().
+ *
+ * @param signature - Content of the .minisig file
+ * @param publicKey - Key the content must be signed with
+ * @throws SignatureError if the signature is malformed or made with another
+ *   key
+ */
+export function createVerifier(
+	signature: string,
+	publicKey: MinisignPublicKey,
+): SignatureVerifier {
+	const lines = signature.split(/\r?\n/);
+	const trustedPrefix = "trusted comment: ";
+	if (lines.length < 4 || !lines[2]?.startsWith(trustedPrefix)) {
+		throw new SignatureError("Malformed signature file");
+	}
+
+	const bytes = decodeLine(lines[1] ?? "", 74, "signature");
+	const algorithm = bytes.subarray(0, 2).toString("latin1");
+	if (algorithm !== "Ed" && algorithm !== "ED") {
+		throw new SignatureError(`Unsupported signature algorithm '${algorithm}'`);
+	}
+	const keyId = formatKeyId(bytes.subarray(2, 10));
+	if (keyId !== publicKey.keyId) {
+		throw new SignatureError(
+			`Signed with key ${keyId}, expected ${publicKey.keyId}`,
+		);
+	}
+
+	const hash = algorithm === "ED" ? createHash("blake2b512") : undefined;
+	const chunks: Buffer[] = [];
+	return {
+		update: (chunk) => {
+			if (hash) {
+				hash.update(chunk);
+			} else {
+				chunks.push(Buffer.from(chunk));
+			}
+		},
+		verify: () => {
+			const message = hash ? hash.digest() : Buffer.concat(chunks);
+			const detached = bytes.subarray(10);
+			if (!verify(null, message, publicKey.key, detached)) {
+				throw new SignatureError("Signature does not match the content");
+			}
+
+			// The trusted comment is signed too, so it cannot be swapped
+			const trustedComment = (lines[2] ?? "").slice(trustedPrefix.length);
+			const global = decodeLine(
+				lines[3] ?? "",
+				64,
+				"trusted comment signature",
+			);
+			const signed = Buffer.concat([
+				detached,
+				Buffer.from(trustedComment, "utf8"),
+			]);
+			if (!verify(null, signed, publicKey.key, global)) {
+				throw new SignatureError("Trusted comment signature is invalid");
+			}
+
+			return trustedComment;
+		},
+	};
+}
+
+function lastLine(text: string): string {
+	const lines = text
+		.split(/\r?\n/)
+		.map((line) => line.trim())
+		.filter((line) => line && !line.startsWith("untrusted comment:"));
+	return lines[lines.length - 1] ?? "";
+}
+
+function decodeLine(line: string, length: number, what: string): Buffer {
+	const bytes = /^[A-Za-z0-9+/]+={0,2}$/.test(line.trim())
+		? Buffer.from(line.trim(), "base64")
+		: Buffer.alloc(0);
+	if (bytes.length !== length) {
+		throw new SignatureError(`Malformed ${what}`);
+	}
+	return bytes;
+}
+
+/**
+ * Key ids are stored little-endian and shown as hex by minisign
+ */
+function formatKeyId(bytes: Uint8Array): string {
+	return Buffer.from(bytes).reverse().toString("hex").toUpperCase();
+}
@@ -0,0 +1,253 @@
+import * as path from "node:path";
+import {
+	InvalidNameError,
+	isValidCommandName,
+	validateCommandName,
+} from "./naming.js";
+import { isInsideDirectory } from "./paths.js";
+
+/**
+ * Namespaced command helpers shared by installation, parsing and repositories
+ *
+ * A command name is one or more segments joined by ":" (canonical) or "/":
+ *
+ *   name      = segment *( ( ":" / "/" ) segment )
+ *   namespace = every segment but the last, joined by ":"
+ *
+ * On disk, the namespace maps to nested directories and the last segment to
+ * a markdown file: "frontend:react:component" <-> "frontend/react/component.md".
+ *
+ * The helpers in this module are inverse to one another for every safe name:
+ *
+ *   extractNamespaceFromPath(relative(base, constructCommandPath(name, base))).name
+ *     === parseNamespacedCommand(name).name
+ *
+ * and they reject any name that could resolve outside the base directory.
+ */
+
+/**
+ * Error thrown when a command name is empty or could escape its base directory
+ */
+export class UnsafeCommandNameError extends Error {
+	constructor(
+		message: string,
+		public readonly commandName: string,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * A command name split into namespace and command parts
+ */
+export interface NamespacedCommandName {
+	/** Canonical colon-separated name (e.g., "frontend:component") */
+	readonly name: string;
+	/** Colon-separated namespace, absent for flat commands (e.g., "frontend") */
+	readonly namespace?: string;
+	/** Namespace segments (empty for flat commands) */
+	readonly namespaceSegments: readonly string[];
+	/** Last segment, the file name without extension (e.g., "component") */
+	readonly command: string;
+}
+
+/**
+ * Check a command name for path traversal and emptiness
+ *
+ * @param commandName - Command name, optionally namespaced with ":" or "/"
+ * @throws UnsafeCommandNameError if the name violates the command grammar
+ *   in naming.ts
+ */
+export function assertSafeCommandName(commandName: string): void {
+	try {
+		validateCommandName(commandName);
+	} catch (error) {
+		if (error instanceof InvalidNameError) {
+			throw new UnsafeCommandNameError(error.message, commandName);
+		}
+		throw error;
+	}
+}
+
+/**
+ * Check whether a command name is safe to map onto the filesystem
+ *
+ * @param commandName - Command name to check
+ * @returns True if assertSafeCommandName() would not throw
+ */
+export function isSafeCommandName(commandName: string): boolean {
+	return isValidCommandName(commandName);
+}
+
+/**
+ * Split a command name into namespace and command parts
+ *
+ * @param commandName - Name using ":" or "/" separators (e.g., "frontend/component")
+ * @returns Parsed name with a canonical colon-separated form
+ * @throws UnsafeCommandNameError if the name is unsafe
+ */
+export function parseNamespacedCommand(
+	commandName: string,
+): NamespacedCommandName {
+	assertSafeCommandName(commandName);
+
+	const segments = commandName.split(/[:/]/);
+	const command = segments.pop() as string;
+	const namespace = segments.length > 0 ? segments.join(":") : undefined;
+
+	return {
+		name: namespace ? `${namespace}:${command}` : command,
+		namespace,
+		namespaceSegments: segments,
+		command,
+	};
+}
+
+/**
+ * Build the markdown file path of a command inside a base directory
+ *
+ * @param commandName - Command name, optionally namespaced
+ * @param baseDir - Commands directory (e.g., ".claude/commands")
+ * @param pathApi - Path flavor to build with (defaults to the platform's)
+ * @returns Path of the form {baseDir}/{namespace dirs}/{command}.md
+ * @throws UnsafeCommandNameError if the name is unsafe or the path would
+ *   resolve outside baseDir
+ */
+export function constructCommandPath(
+	commandName: string,
+	baseDir: string,
+	pathApi: path.PlatformPath = path,
+): string {
+	const parsed = parseNamespacedCommand(commandName);
+	const filePath = pathApi.join(
+		baseDir,
+		...parsed.namespaceSegments,
+		`${parsed.command}.md`,
+	);
+
+	// Defense in depth: the resolved file must stay within the base directory
+	if (!isInsideDirectory(filePath, baseDir, pathApi)) {
+		throw new UnsafeCommandNameError(
+			`Invalid command name '${commandName}': path escapes base directory`,
+			commandName,
+		);
+	}
+
+	return filePath;
+}
+
+/**
+ * Derive the command name from a markdown file path relative to a commands directory
+ *
+ * @param relativePath - Path such as "frontend/component.md" (either separator)
+ * @returns Parsed name (e.g., name "frontend:component", namespace "frontend")
+ * @throws UnsafeCommandNameError if the path contains unsafe segments
+ */
+export function extractNamespaceFromPath(
+	relativePath: string,
+): NamespacedCommandName {
+	const withoutExtension = relativePath
+		.replace(/\\/g, "/")
+		.replace(/\.md$/, "");
+	// Tolerate leading "./" and redundant separators produced by path APIs
+	const normalized = withoutExtension
+		.split("/")
+		.filter((segment) => segment !== "" && segment !== ".")
+		.join("/");
+
+	return parseNamespacedCommand(normalized);
+}
+
+/**
+ * Build the local name of a command installed in an additional language
+ *
+ * The suffix goes on the last segment, so namespaced commands stay in their
+ * namespace: ("frontend:component", "fr") -> "frontend:component-fr".
+ *
+ * @param commandName - Command name, optionally namespaced
+ * @param language - Language code of the variant (e.g., "fr")
+ * @returns Canonical name of the language variant
+ * @throws UnsafeCommandNameError if the resulting name is unsafe
+ */
+export function languageVariantName(
+	commandName: string,
+	language: string,
+): string {
+	return parseNamespacedCommand(`${commandName}-${language}`).name;
+}
+
+/**
+ * Command name with an optional requested version
+ */
+export interface VersionedCommandName {
+	/** Command name, optionally namespaced */
+	readonly name: string;
+	/** Version after "@", if one was given */
+	readonly version?: string;
+}
+
+/**
+ * Split a "name@version" command spec
+ *
+ * @param spec - Command name, optionally followed by "@<version>"
+ * @returns The name and the requested version, if any
+ * @throws UnsafeCommandNameError if nothing follows the "@"
+ */
+export function parseVersionedCommand(spec: string): VersionedCommandName {
+	const at = spec.lastIndexOf("@");
+	if (at === -1) {
+		return { name: spec };
+	}
+	const version = spec.slice(at + 1).trim();
+	if (version === "") {
+		throw new UnsafeCommandNameError(
+			`Missing version after '@' in '${spec}'`,
+			spec,
+		);
+	}
+	return { name: spec.slice(0, at), version };
+}
+
+/**
+ * Check whether a command belongs to a namespace
+ *
+ * Nested namespaces belong to their parents: "frontend" includes
+ * "frontend:react:hooks". Either separator is accepted in the namespace.
+ *
+ * @param commandName - Command name, colon-separated
+ * @param namespace - Namespace such as "frontend" or "frontend/react"
+ */
+export function isInNamespace(commandName: string, namespace: string): boolean {
+	const prefix = namespace.replace(/[/:]+$/, "").replace(/\//g, ":");
+	return commandName.startsWith(`${prefix}:`);
+}
+
+/**
+ * Check whether a command name argument is a wildcard pattern
+ */
+export function isCommandPattern(text: string): boolean {
+	return /[*?]/.test(text);
+}
+
+/**
+ * Match a command name against a wildcard pattern
+ *
+ * "*" matches any run of characters, namespace separators included, so
+ * "frontend:*" matches every command under "frontend"; "?" matches one
+ * character. Either separator is accepted in the pattern.
+ *
+ * @param commandName - Command name, colon-separated
+ * @param pattern - Pattern such as "frontend:*" or "*-review"
+ */
+export function matchesCommandPattern(
+	commandName: string,
+	pattern: string,
+): boolean {
+	const source = pattern
+		.replace(/\//g, ":")
+		.replace(/[.+^${}()|[\]\\]/g, "\\$&")
+		.replace(/\*/g, ".*")
+		.replace(/\?/g, ".");
+	return new RegExp(`^${source}$`).test(commandName);
+}
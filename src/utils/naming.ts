@@ -0,0 +1,297 @@
+import * as path from "node:path";
+
+/**
+ * Canonical validators for user- and repository-supplied names
+ *
+ * Every layer that accepts a command name, namespace, language code or
+ * command file path validates it here so the rules cannot drift apart.
+ *
+ * Grammar:
+ *
+ *   language  = 2*3 ALPHA-LOWER                     ; ISO 639-1/639-2 ("en", "deu")
+ *   segment   = ALNUM [ *( ALNUM / "-" ) ALNUM ]     ; namespace segment ("frontend")
+ *   namespace = segment *( ( ":" / "/" ) segment )   ; "frontend:react"
+ *   part      = 1*( any char except ":" "/" "\" NUL ), not blank, "." or ".."
+ *   command   = part *( ( ":" / "/" ) part )         ; "frontend:component"
+ *   filename  = fpart *( ( "/" / "\" ) fpart )       ; "frontend/component.md"
+ *   fpart     = 1*( any char except "/" "\" NUL ), not "." or ".."
+ *
+ * Command names are deliberately looser than namespaces: they are derived
+ * from arbitrary markdown file names in repositories, so only the rules that
+ * keep them inside a commands directory are enforced. Filenames must be
+ * relative (neither POSIX nor Windows absolute).
+ */
+
+/**
+ * Kind of name being validated
+ */
+export type NameKind = "command" | "namespace" | "language" | "filename";
+
+/**
+ * Why a name was rejected
+ */
+export type InvalidNameReason =
+	| "empty"
+	| "absolute"
+	| "characters"
+	| "traversal"
+	| "format";
+
+/**
+ * Error thrown when a name does not match its grammar
+ */
+export class InvalidNameError extends Error {
+	constructor(
+		message: string,
+		public readonly kind: NameKind,
+		public readonly value: string,
+		public readonly reason: InvalidNameReason,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Language code: 2-3 lowercase ASCII letters
+ */
+export const LANGUAGE_CODE_PATTERN = /^[a-z]{2,3}$/;
+
+/**
+ * Namespace segment: alphanumerics, with inner hyphens
+ */
+export const NAMESPACE_SEGMENT_PATTERN =
+	/^[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]$|^[a-zA-Z0-9]$/;
+
+/**
+ * Separators between command name and namespace segments
+ */
+const NAME_SEPARATORS = /[:/]/;
+
+/**
+ * Separators between filename segments
+ */
+const FILE_SEPARATORS = /[/\\]/;
+
+/**
+ * Segment values that can resolve outside their directory or to nothing
+ */
+const UNSAFE_SEGMENT = /^(\.{1,2}|\s*)$/;
+
+/**
+ * Validate a command name, optionally namespaced with ":" or "/"
+ *
+ * @param name - Command name (e.g., "debug-help", "frontend:component")
+ * @throws InvalidNameError if the name is empty, absolute, contains
+ *   backslashes or null bytes, or has an empty, "." or ".." segment
+ */
+export function validateCommandName(name: string): void {
+	if (typeof name !== "string" || name.trim() === "") {
+		throw new InvalidNameError(
+			"Command name cannot be empty",
+			"command",
+			name,
+			"empty",
+		);
+	}
+
+	if (path.isAbsolute(name) || path.win32.isAbsolute(name)) {
+		throw new InvalidNameError(
+			`Invalid command name '${name}': absolute paths not allowed`,
+			"command",
+			name,
+			"absolute",
+		);
+	}
+
+	if (
+		/[\\\0]/.test(name) ||
+		name.split(NAME_SEPARATORS).some((part) => UNSAFE_SEGMENT.test(part))
+	) {
+		throw new InvalidNameError(
+			`Invalid command name '${name}': contains dangerous path segments`,
+			"command",
+			name,
+			"traversal",
+		);
+	}
+}
+
+/**
+ * Validate a namespace such as "frontend" or "frontend:react"
+ *
+ * @param namespace - Namespace using ":" or "/" separators
+ * @throws InvalidNameError if the namespace is empty or a segment does not
+ *   match NAMESPACE_SEGMENT_PATTERN
+ */
+export function validateNamespace(namespace: string): void {
+	if (typeof namespace !== "string" || namespace.trim() === "") {
+		throw new InvalidNameError(
+			"Namespace cannot be empty",
+			"namespace",
+			namespace,
+			"empty",
+		);
+	}
+
+	for (const segment of namespace.split(NAME_SEPARATORS)) {
+		if (!NAMESPACE_SEGMENT_PATTERN.test(segment)) {
+			throw new InvalidNameError(
+				`Invalid namespace '${namespace}': segment "${segment}" must contain only letters, digits and inner hyphens`,
+				"namespace",
+				namespace,
+				segment === "" ? "empty" : "format",
+			);
+		}
+	}
+}
+
+/**
+ * Validate a language code as stored in config, caches and repositories
+ *
+ * The code must already be normalized; use normalizeLanguageCode() for
+ * user input.
+ *
+ * @param code - Language code (e.g., "en", "deu")
+ * @throws InvalidNameError if the code is not 2-3 lowercase letters
+ */
+export function validateLanguageCode(code: string): void {
+	if (typeof code !== "string" || code === "") {
+		throw new InvalidNameError(
+			"Language code cannot be empty",
+			"language",
+			code,
+			"empty",
+		);
+	}
+
+	if (!LANGUAGE_CODE_PATTERN.test(code)) {
+		throw new InvalidNameError(
+			`Invalid language code '${code}': must be 2-3 lowercase letters`,
+			"language",
+			code,
+			"format",
+		);
+	}
+}
+
+/**
+ * Validate a command file path relative to a language directory
+ *
+ * @param file - Relative path (e.g., "frontend/component.md")
+ * @throws InvalidNameError if the path is empty, absolute, contains null
+ *   bytes, or has an empty, "." or ".." segment
+ */
+export function validateFileName(file: string): void {
+	if (typeof file !== "string" || file === "") {
+		throw new InvalidNameError(
+			"File path cannot be empty",
+			"filename",
+			file,
+			"empty",
+		);
+	}
+
+	if (path.isAbsolute(file) || path.win32.isAbsolute(file)) {
+		throw new InvalidNameError(
+			`Invalid file path '${file}': must be relative`,
+			"filename",
+			file,
+			"absolute",
+		);
+	}
+
+	if (file.includes("\0")) {
+		throw new InvalidNameError(
+			`Invalid file path '${file}': contains null bytes`,
+			"filename",
+			file,
+			"characters",
+		);
+	}
+
+	if (
+		file
+			.split(FILE_SEPARATORS)
+			.some((part) => part === "" || part === "." || part === "..")
+	) {
+		throw new InvalidNameError(
+			`Invalid file path '${file}': contains path traversal`,
+			"filename",
+			file,
+			"traversal",
+		);
+	}
+}
+
+/**
+ * Check a command name without throwing
+ */
+export function isValidCommandName(name: string): boolean {
+	return passes(() => validateCommandName(name));
+}
+
+/**
+ * Check a namespace without throwing
+ */
+export function isValidNamespace(namespace: string): boolean {
+	return passes(() => validateNamespace(namespace));
+}
+
+/**
+ * Check a language code without throwing
+ */
+export function isValidLanguageCode(code: string): boolean {
+	return passes(() => validateLanguageCode(code));
+}
+
+/**
+ * Check a command file path without throwing
+ */
+export function isValidFileName(file: string): boolean {
+	return passes(() => validateFileName(file));
+}
+
+/**
+ * Normalize user input to a language code
+ *
+ * @param input - Raw input such as " EN "
+ * @returns Trimmed lowercase code, or undefined if it is not a valid code
+ */
+export function normalizeLanguageCode(input: string): string | undefined {
+	if (typeof input !== "string") {
+		return undefined;
+	}
+	const normalized = input.trim().toLowerCase();
+	return isValidLanguageCode(normalized) ? normalized : undefined;
+}
+
+/**
+ * Turn a hand-chosen file or directory name into a command name segment
+ *
+ * Lowercases, replaces whitespace, underscores and dots with hyphens and
+ * drops other characters, so "Code Review_v2" becomes "code-review-v2".
+ *
+ * @param input - File name without extension, or a directory name
+ * @returns Segment matching NAMESPACE_SEGMENT_PATTERN, or undefined if
+ *   nothing usable is left
+ */
+export function normalizeNameSegment(input: string): string | undefined {
+	const normalized = input
+		.normalize("NFKD")
+		.toLowerCase()
+		.replace(/[\s_.]+/g, "-")
+		.replace(/[^a-z0-9-]/g, "")
+		.replace(/-{2,}/g, "-")
+		.replace(/^-+|-+$/g, "");
+	return NAMESPACE_SEGMENT_PATTERN.test(normalized) ? normalized : undefined;
+}
+
+function passes(validate: () => void): boolean {
+	try {
+		validate();
+		return true;
+	} catch {
+		return false;
+	}
+}
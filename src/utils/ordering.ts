@@ -0,0 +1,129 @@
+/**
+ * Deterministic ordering for every command listing produced by claude-cmd
+ *
+ * All listings (list, search, installed, conflicts and the JSON arrays emitted
+ * by --format json / --output json) are sorted with the same total order so that
+ * diffs of command inventories are stable across runs and machines:
+ *
+ * 1. name (byte order, case-sensitive)
+ * 2. namespace (missing namespace sorts first)
+ * 3. source (missing source sorts first)
+ *
+ * When a listing has a language, each field is compared with that language's
+ * collation instead (see stringComparator()), so accented names sort next to
+ * their unaccented neighbors; strings the collation considers equal still fall
+ * back to byte order, keeping the order total.
+ *
+ * Array.prototype.sort is stable, so items with identical keys keep their
+ * original relative order.
+ */
+
+/**
+ * Comparison function for strings
+ */
+export type StringComparator = (a: string, b: string) => number;
+
+/**
+ * Fields used to order a command-like item
+ */
+export interface OrderingKey {
+	/** Command name (e.g., "debug-help", "frontend:component") */
+	readonly name: string;
+	/** Optional namespace (e.g., "frontend") */
+	readonly namespace?: string;
+	/** Optional source or location (e.g., "repository", "personal", "project") */
+	readonly source?: string;
+}
+
+/**
+ * Compare two strings by UTF-16 code unit order
+ *
+ * @returns Negative if a < b, positive if a > b, zero if equal
+ */
+export function compareStrings(a: string, b: string): number {
+	if (a < b) return -1;
+	if (a > b) return 1;
+	return 0;
+}
+
+/** Comparators by language, created on first use */
+const comparators = new Map<string, StringComparator>();
+
+/**
+ * Get the string comparison used for listings in a language
+ *
+ * Strings are compared with the language's collation (Intl.Collator) and,
+ * when the collation considers them equal, by compareStrings(), so the result
+ * is a deterministic total order. Without a language, or when the runtime
+ * rejects the language tag, this is compareStrings() itself.
+ *
+ * @param language - Resolved language code (e.g., "fr")
+ */
+export function stringComparator(language?: string): StringComparator {
+	if (!language) {
+		return compareStrings;
+	}
+
+	let comparator = comparators.get(language);
+	if (!comparator) {
+		try {
+			const collator = new Intl.Collator(language, { usage: "sort" });
+			comparator = (a, b) => collator.compare(a, b) || compareStrings(a, b);
+		} catch {
+			comparator = compareStrings;
+		}
+		comparators.set(language, comparator);
+	}
+	return comparator;
+}
+
+/**
+ * Compare two ordering keys using name, then namespace, then source
+ *
+ * @param compare - String comparison (default: byte order)
+ * @returns Negative if a sorts before b, positive if after, zero if equal
+ */
+export function compareOrderingKeys(
+	a: OrderingKey,
+	b: OrderingKey,
+	compare: StringComparator = compareStrings,
+): number {
+	return (
+		compare(a.name, b.name) ||
+		compare(a.namespace ?? "", b.namespace ?? "") ||
+		compare(a.source ?? "", b.source ?? "")
+	);
+}
+
+/**
+ * Return a sorted copy of items using the key extracted by keyOf
+ *
+ * @param items - Items to sort (not modified)
+ * @param keyOf - Function extracting the ordering key of an item
+ * @param language - Language whose collation orders names (default: byte order)
+ * @returns New array sorted in canonical order
+ */
+export function sortByOrderingKey<T>(
+	items: readonly T[],
+	keyOf: (item: T) => OrderingKey,
+	language?: string,
+): T[] {
+	const compare = stringComparator(language);
+	return [...items].sort((a, b) =>
+		compareOrderingKeys(keyOf(a), keyOf(b), compare),
+	);
+}
+
+/**
+ * Return a sorted copy of command-like items in canonical order
+ *
+ * @param items - Commands to sort (not modified)
+ * @param language - Language whose collation orders names (default: byte order)
+ * @returns New array sorted by name, then namespace, then source
+ */
+export function sortCommands<T extends OrderingKey>(
+	items: readonly T[],
+	language?: string,
+): T[] {
+	return sortByOrderingKey(items, (item) => item, language);
+}
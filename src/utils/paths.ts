@@ -0,0 +1,62 @@
+import * as path from "node:path";
+
+/**
+ * Check whether a path lies strictly inside a directory
+ *
+ * Compares resolved paths through the path API instead of string prefixes,
+ * so it is not fooled by sibling names sharing a prefix ("commands-old"),
+ * mixed separators, Windows drive letter case or paths on another drive.
+ *
+ * @param filePath - Path to check
+ * @param directory - Directory that should contain it
+ * @param pathApi - Path flavor to compare with (defaults to the platform's)
+ * @returns True if filePath is below directory (not the directory itself)
+ */
+export function isInsideDirectory(
+	filePath: string,
+	directory: string,
+	pathApi: path.PlatformPath = path,
+): boolean {
+	const relative = pathApi.relative(
+		pathApi.resolve(directory),
+		pathApi.resolve(filePath),
+	);
+	return (
+		relative !== "" &&
+		relative !== ".." &&
+		!relative.startsWith(`..${pathApi.sep}`) &&
+		// On Windows, a path on another drive comes back absolute
+		!pathApi.isAbsolute(relative)
+	);
+}
+
+/**
+ * Split a relative path into segments, accepting either separator
+ *
+ * @param relativePath - Path such as "frontend/component.md" or "a\\b.md"
+ */
+export function pathSegments(relativePath: string): string[] {
+	return relativePath.split(/[\\/]/).filter((segment) => segment !== "");
+}
+
+/**
+ * Check whether a directory name matches one of a list of patterns
+ *
+ * Patterns match whole names, with * standing for any run of characters and
+ * ? for a single character (e.g., "node_modules", ".*", "build-?").
+ *
+ * @param name - Directory name (a single path segment)
+ * @param patterns - Name patterns
+ */
+export function matchesDirectoryPattern(
+	name: string,
+	patterns: readonly string[],
+): boolean {
+	return patterns.some((pattern) => {
+		const source = pattern
+			.replace(/[.+^${}()|[\]\\]/g, "\\$&")
+			.replace(/\*/g, ".*")
+			.replace(/\?/g, ".");
+		return new RegExp(`^${source}$`).test(name);
+	});
+}
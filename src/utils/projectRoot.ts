@@ -0,0 +1,84 @@
+import { existsSync } from "node:fs";
+import * as os from "node:os";
+import * as path from "node:path";
+
+/**
+ * Environment variable turning off the upward search for the project root
+ */
+export const NO_PROJECT_DISCOVERY_ENV = "CLAUDE_CMD_NO_PROJECT_DISCOVERY";
+
+/**
+ * Find the project a directory belongs to
+ *
+ * Walks from start towards the filesystem root and returns the nearest
+ * directory containing .claude, so commands run from a subdirectory still
+ * see the project scope. See findProjectRoots() for where the walk stops.
+ *
+ * @param start - Directory to start from (defaults to the working directory)
+ * @param home - Home directory to skip (defaults to the current user's)
+ * @param exists - Existence check (defaults to the real file system)
+ * @returns The project root, or undefined if no ancestor has .claude
+ */
+export function findProjectRoot(
+	start: string = process.cwd(),
+	home: string = os.homedir(),
+	exists: (target: string) => boolean = existsSync,
+): string | undefined {
+	return findProjectRoots(start, home, exists)[0];
+}
+
+/**
+ * Find the project a directory belongs to and the projects enclosing it
+ *
+ * Walks from start towards the filesystem root and collects every directory
+ * containing .claude, so a package of a monorepo sees both its own commands
+ * and those of the repository root. The walk stops at the git root (a
+ * directory containing .git), which is checked itself, and skips the home
+ * directory, whose .claude holds the personal commands rather than a
+ * project's.
+ *
+ * Services are built synchronously, so this checks the file system directly.
+ *
+ * @param start - Directory to start from (defaults to the working directory)
+ * @param home - Home directory to skip (defaults to the current user's)
+ * @param exists - Existence check (defaults to the real file system)
+ * @returns The project roots, nearest first; empty if no ancestor has
+ *   .claude
+ */
+export function findProjectRoots(
+	start: string = process.cwd(),
+	home: string = os.homedir(),
+	exists: (target: string) => boolean = existsSync,
+): string[] {
+	const homeDir = path.resolve(home);
+	const roots: string[] = [];
+	let dir = path.resolve(start);
+	for (;;) {
+		if (dir !== homeDir && exists(path.join(dir, ".claude"))) {
+			roots.push(dir);
+		}
+		const parent = path.dirname(dir);
+		if (parent === dir || exists(path.join(dir, ".git"))) {
+			return roots;
+		}
+		dir = parent;
+	}
+}
+
+/**
+ * Resolve the project root and the roots of the projects enclosing it
+ *
+ * @param discover - Search upwards (false for --no-project-discovery)
+ * @param env - Environment to read (defaults to process.env)
+ * @returns Absolute project roots, nearest first; only the working
+ *   directory when discovery is off or finds nothing
+ */
+export function resolveProjectRoots(
+	discover = true,
+	env: NodeJS.ProcessEnv = process.env,
+): string[] {
+	const value = env[NO_PROJECT_DISCOVERY_ENV]?.toLowerCase();
+	const disabled = value === "1" || value === "true";
+	const roots = discover && !disabled ? findProjectRoots() : [];
+	return roots.length > 0 ? roots : [process.cwd()];
+}
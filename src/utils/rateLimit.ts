@@ -0,0 +1,53 @@
+/**
+ * Check whether a failed response means the client is being rate-limited
+ *
+ * GitHub answers 429 for secondary rate limits and 403 with
+ * `x-ratelimit-remaining: 0` once the primary limit is used up; any other
+ * 403 is a real permission error.
+ *
+ * @param status - HTTP status code
+ * @param headers - Response headers with lower-case names
+ * @returns True if the request should be retried later
+ */
+export function isRateLimitResponse(
+	status: number,
+	headers: Record<string, string>,
+): boolean {
+	return (
+		status === 429 ||
+		(status === 403 && headers["x-ratelimit-remaining"]?.trim() === "0")
+	);
+}
+
+/**
+ * Work out when a rate-limited request may be retried
+ *
+ * Retry-After is honored in both of its forms (delay in seconds or an HTTP
+ * date); without it, GitHub's `x-ratelimit-reset` (epoch seconds) is used.
+ *
+ * @param headers - Response headers with lower-case names
+ * @param now - Current time in epoch milliseconds
+ * @returns Epoch milliseconds to wait for, or undefined if the server did
+ *   not say
+ */
+export function parseRetryAt(
+	headers: Record<string, string>,
+	now: number,
+): number | undefined {
+	const retryAfter = headers["retry-after"]?.trim();
+	if (retryAfter) {
+		if (/^\d+$/.test(retryAfter)) {
+			return now + Number(retryAfter) * 1000;
+		}
+		const date = Date.parse(retryAfter);
+		if (!Number.isNaN(date)) {
+			return date;
+		}
+	}
+
+	const reset = headers["x-ratelimit-reset"]?.trim();
+	if (reset && /^\d+$/.test(reset)) {
+		return Number(reset) * 1000;
+	}
+	return undefined;
+}
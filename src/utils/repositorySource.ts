@@ -0,0 +1,212 @@
+import { InvalidConfigError } from "../interfaces/IConfigService.js";
+import { GIT_REF_PATTERN, isAdditionalRepositoryURL } from "./configKeys.js";
+import { parsePublicKey } from "./minisign.js";
+import { NAMESPACE_SEGMENT_PATTERN } from "./naming.js";
+
+/**
+ * Host GitHub serves raw repository files from
+ */
+export const GITHUB_RAW_ORIGIN = "https://raw.githubusercontent.com";
+
+/**
+ * Branch used when a shorthand names no ref, as in the default repository
+ */
+export const DEFAULT_GITHUB_REF = "main";
+
+/**
+ * A GitHub repository written as "owner/name[@ref]"
+ */
+export interface GitHubShorthand {
+	readonly owner: string;
+	readonly name: string;
+	/** Branch, tag or commit; undefined for the default branch ("main") */
+	readonly ref?: string;
+}
+
+/**
+ * Parse "owner/name[@ref]"
+ *
+ * Owners follow GitHub's rules (letters, digits and single dashes); names may
+ * also contain dots and underscores. Refs must pass GIT_REF_PATTERN.
+ *
+ * @returns The repository, or undefined if value is not a shorthand
+ */
+export function parseGitHubShorthand(
+	value: string,
+): GitHubShorthand | undefined {
+	const match =
+		/^([A-Za-z0-9](?:-?[A-Za-z0-9])*)\/([\w.-]+?)(?:\.git)?(?:@(.+))?$/.exec(
+			value.trim(),
+		);
+	if (!match?.[1] || !match[2] || /^\.+$/.test(match[2])) {
+		return undefined;
+	}
+	const ref = match[3];
+	if (ref !== undefined && !GIT_REF_PATTERN.test(ref)) {
+		return undefined;
+	}
+	return ref
+		? { owner: match[1], name: match[2], ref }
+		: { owner: match[1], name: match[2] };
+}
+
+/**
+ * Expand a shorthand to the raw URL root HTTPRepository reads commands/ from
+ *
+ * Without a ref the branch is spelled "refs/heads/main" like
+ * HTTPRepository.BASE_URL; explicit refs are used as given, which GitHub
+ * resolves for branches, tags and commits alike.
+ */
+export function githubRawURL(repository: GitHubShorthand): string {
+	const ref = repository.ref ?? `refs/heads/${DEFAULT_GITHUB_REF}`;
+	return `${GITHUB_RAW_ORIGIN}/${repository.owner}/${repository.name}/${ref}`;
+}
+
+/**
+ * Resolve what `repo add` was given to a repository URL
+ *
+ * @param source - "owner/name[@ref]", or an http(s) or file:// URL
+ * @returns URL suitable for additionalRepositories
+ * @throws InvalidConfigError if source is neither
+ */
+export function resolveRepositorySource(source: string): string {
+	const shorthand = isAdditionalRepositoryURL(source)
+		? undefined
+		: parseGitHubShorthand(source);
+	if (shorthand) {
+		return githubRawURL(shorthand);
+	}
+	if (!isAdditionalRepositoryURL(source)) {
+		throw new InvalidConfigError(
+			`'${source}' is neither owner/name[@ref] nor an http(s) or file:// URL`,
+		);
+	}
+	return source.replace(/\/+$/, "");
+}
+
+/**
+ * Reduce a repository URL to what identifies the repository it points at
+ *
+ * Trailing slashes are dropped, GitHub owner and repository names compared
+ * case-insensitively, and "refs/heads/<branch>" treated like "<branch>", so
+ * the spellings of one raw GitHub root compare equal.
+ */
+function repositoryKey(url: string): string {
+	const trimmed = url.trim().replace(/\/+$/, "");
+	let parsed: URL;
+	try {
+		parsed = new URL(trimmed);
+	} catch {
+		return trimmed;
+	}
+	if (parsed.origin !== GITHUB_RAW_ORIGIN) {
+		return `${parsed.origin}${parsed.pathname}`;
+	}
+	const [owner = "", name = "", ...ref] = parsed.pathname
+		.split("/")
+		.filter(Boolean);
+	const branch = ref[0] === "refs" && ref[1] === "heads" ? ref.slice(2) : ref;
+	return [
+		GITHUB_RAW_ORIGIN,
+		owner.toLowerCase(),
+		name.toLowerCase(),
+		...branch,
+	].join("/");
+}
+
+/**
+ * Check whether two repository URLs point at the same repository and ref
+ */
+export function isSameRepository(a: string, b: string): boolean {
+	return repositoryKey(a) === repositoryKey(b);
+}
+
+/**
+ * Check the shape of the `repositoryPrefixes` config key: repository URLs
+ * mapped to a namespace segment (e.g., {"https://…/team": "team"})
+ */
+export function isRepositoryPrefixMap(
+	value: unknown,
+): value is Record<string, string> {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		!Array.isArray(value) &&
+		Object.entries(value).every(
+			([url, prefix]) =>
+				isAdditionalRepositoryURL(url) &&
+				typeof prefix === "string" &&
+				NAMESPACE_SEGMENT_PATTERN.test(prefix),
+		)
+	);
+}
+
+/**
+ * Get the prefix configured for a repository
+ *
+ * @param prefixes - The `repositoryPrefixes` config key
+ * @param url - Repository URL, in any spelling isSameRepository() accepts
+ */
+export function repositoryPrefixFor(
+	prefixes: Readonly<Record<string, string>> | undefined,
+	url: string,
+): string | undefined {
+	return settingFor(prefixes, url);
+}
+
+/**
+ * Check the shape of the `repositoryPublicKeys` config key: repository URLs
+ * mapped to a minisign public key
+ */
+export function isRepositoryKeyMap(
+	value: unknown,
+): value is Record<string, string> {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		!Array.isArray(value) &&
+		Object.entries(value).every(
+			([url, key]) =>
+				isAdditionalRepositoryURL(url) &&
+				typeof key === "string" &&
+				isPublicKey(key),
+		)
+	);
+}
+
+/**
+ * Get the public key configured for an additional repository
+ *
+ * @param keys - The `repositoryPublicKeys` config key
+ * @param url - Repository URL, in any spelling isSameRepository() accepts
+ */
+export function repositoryPublicKeyFor(
+	keys: Readonly<Record<string, string>> | undefined,
+	url: string,
+): string | undefined {
+	return settingFor(keys, url);
+}
+
+/**
+ * Find the value a map keyed by repository URL has for a repository
+ */
+function settingFor(
+	settings: Readonly<Record<string, string>> | undefined,
+	url: string,
+): string | undefined {
+	return Object.entries(settings ?? {}).find(([configured]) =>
+		isSameRepository(configured, url),
+	)?.[1];
+}
+
+/**
+ * Check whether text parses as a minisign public key
+ */
+function isPublicKey(text: string): boolean {
+	try {
+		parsePublicKey(text);
+		return true;
+	} catch {
+		return false;
+	}
+}
@@ -0,0 +1,70 @@
+import type IClock from "../interfaces/IClock.js";
+
+/**
+ * A value computed at most once per TTL, shared by concurrent callers
+ *
+ * Callers arriving while the value is being computed wait for that
+ * computation instead of starting their own. Failures are never cached: the
+ * next call computes again. invalidate() drops the snapshot; a computation
+ * already running when it is called still answers its callers but is not
+ * kept, since it may have read the state the caller just changed.
+ *
+ * A TTL of 0 disables caching but still shares concurrent computations.
+ */
+export class SnapshotCache<T> {
+	private snapshot?: { value: T; takenAt: number };
+	private inFlight?: Promise<T>;
+	private generation = 0;
+
+	/**
+	 * @param clock - Clock the TTL is measured with
+	 * @param ttlMs - How long a computed value is served (0: not kept)
+	 */
+	constructor(
+		private readonly clock: IClock,
+		private readonly ttlMs: number,
+	) {}
+
+	/**
+	 * Get the snapshot, computing it if it is missing or older than the TTL
+	 *
+	 * @param compute - Computes a fresh value
+	 */
+	get(compute: () => Promise<T>): Promise<T> {
+		if (
+			this.snapshot &&
+			this.clock.now() - this.snapshot.takenAt < this.ttlMs
+		) {
+			return Promise.resolve(this.snapshot.value);
+		}
+		if (this.inFlight) {
+			return this.inFlight;
+		}
+
+		const generation = this.generation;
+		const takenAt = this.clock.now();
+		const computation = compute()
+			.then((value) => {
+				if (generation === this.generation && this.ttlMs > 0) {
+					this.snapshot = { value, takenAt };
+				}
+				return value;
+			})
+			.finally(() => {
+				if (this.inFlight === computation) {
+					this.inFlight = undefined;
+				}
+			});
+		this.inFlight = computation;
+		return computation;
+	}
+
+	/**
+	 * Drop the snapshot so the next call computes a fresh value
+	 */
+	invalidate(): void {
+		this.snapshot = undefined;
+		this.inFlight = undefined;
+		this.generation++;
+	}
+}
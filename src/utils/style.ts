@@ -0,0 +1,80 @@
+/**
+ * Styles applied to terminal output
+ *
+ * Formatters take a Theme and wrap the parts of their output that carry
+ * meaning; the plain theme leaves text unchanged, so formatters stay pure and
+ * tests see plain text.
+ */
+export interface Theme {
+	/** Installed, healthy, done */
+	success(text: string): string;
+	/** Outdated, deprecated, degraded */
+	warning(text: string): string;
+	/** Missing, failed, removed */
+	error(text: string): string;
+	/** Secondary details such as namespaces and paths */
+	dim(text: string): string;
+	/** Section headings */
+	heading(text: string): string;
+}
+
+/**
+ * When output is colored: "auto" colors terminals unless NO_COLOR is set
+ */
+export type ColorMode = "auto" | "always" | "never";
+
+export const COLOR_MODES: readonly ColorMode[] = ["auto", "always", "never"];
+
+const RESET = "\x1b[0m";
+
+/**
+ * Build a theme from ANSI SGR parameters
+ */
+function ansiTheme(codes: Record<keyof Theme, string>): Theme {
+	const wrap = (code: string) => (text: string) =>
+		text === "" ? text : `\x1b[${code}m${text}${RESET}`;
+	return {
+		success: wrap(codes.success),
+		warning: wrap(codes.warning),
+		error: wrap(codes.error),
+		dim: wrap(codes.dim),
+		heading: wrap(codes.heading),
+	};
+}
+
+/**
+ * Theme that leaves text unchanged (pipes, --no-color, NO_COLOR)
+ */
+export const PLAIN_THEME: Theme = {
+	success: (text) => text,
+	warning: (text) => text,
+	error: (text) => text,
+	dim: (text) => text,
+	heading: (text) => text,
+};
+
+/**
+ * Theme used on color terminals: green, yellow, red, dim and bold
+ */
+export const COLOR_THEME: Theme = ansiTheme({
+	success: "32",
+	warning: "33",
+	error: "31",
+	dim: "2",
+	heading: "1",
+});
+
+/**
+ * Dim the namespace of a command name, leaving the base name as is
+ *
+ * @param name - Command name, possibly namespaced (e.g., "frontend:component")
+ * @param theme - Theme to apply
+ * @returns The styled name ("frontend:" dimmed, "component" plain)
+ */
+export function styleCommandName(name: string, theme: Theme): string {
+	const separator = name.lastIndexOf(":");
+	if (separator === -1) {
+		return name;
+	}
+	return `${theme.dim(name.slice(0, separator + 1))}${name.slice(separator + 1)}`;
+}
@@ -0,0 +1,60 @@
+import { compareStrings } from "./ordering.js";
+
+/**
+ * Levenshtein distance between two strings
+ */
+export function editDistance(a: string, b: string): number {
+	let previous = Array.from({ length: b.length + 1 }, (_, i) => i);
+	for (let i = 1; i <= a.length; i++) {
+		const current = [i];
+		for (let j = 1; j <= b.length; j++) {
+			current[j] = Math.min(
+				(previous[j] as number) + 1,
+				(current[j - 1] as number) + 1,
+				(previous[j - 1] as number) + (a[i - 1] === b[j - 1] ? 0 : 1),
+			);
+		}
+		previous = current;
+	}
+	return previous[b.length] as number;
+}
+
+/**
+ * Find the names a mistyped command name most likely meant
+ *
+ * Names are compared case-insensitively, both whole and without their
+ * namespace, so `debg-help` suggests `tools:debug-help`. Only names within
+ * a few edits (a third of the typed length, at least 2) are suggested.
+ *
+ * @param typed - Name that was not found
+ * @param candidates - Known names; duplicates are ignored
+ * @param max - Maximum number of suggestions
+ * @returns Closest names first, ties in name order
+ */
+export function suggestNames(
+	typed: string,
+	candidates: Iterable<string>,
+	max = 3,
+): string[] {
+	const wanted = typed.toLowerCase();
+	const threshold = Math.max(2, Math.floor(typed.length / 3));
+	const scored: Array<{ name: string; distance: number }> = [];
+	for (const name of new Set(candidates)) {
+		const known = name.toLowerCase();
+		if (known === wanted) {
+			continue;
+		}
+		const baseName = known.slice(known.lastIndexOf(":") + 1);
+		const distance = Math.min(
+			editDistance(wanted, known),
+			editDistance(wanted, baseName),
+		);
+		if (distance <= threshold) {
+			scored.push({ name, distance });
+		}
+	}
+	return scored
+		.sort((a, b) => a.distance - b.distance || compareStrings(a.name, b.name))
+		.slice(0, max)
+		.map(({ name }) => name);
+}
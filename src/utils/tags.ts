@@ -0,0 +1,33 @@
+import type { Command } from "../types/Command.js";
+
+/**
+ * Get the normalized tags of a command, including its category
+ *
+ * Tags are lowercased and trimmed. Manifests are not strictly validated when
+ * fetched, so non-string entries are ignored.
+ *
+ * @param command - Command from a manifest
+ * @returns Unique tags in manifest order, category first
+ */
+export function commandTags(command: Command): string[] {
+	const raw: unknown[] = [
+		command.category,
+		...(Array.isArray(command.tags) ? command.tags : []),
+	];
+	const tags = raw
+		.filter((tag): tag is string => typeof tag === "string")
+		.map((tag) => tag.trim().toLowerCase())
+		.filter((tag) => tag !== "");
+	return [...new Set(tags)];
+}
+
+/**
+ * Check whether a command has a tag or category (case-insensitive)
+ *
+ * @param command - Command from a manifest
+ * @param tag - Tag to look for
+ * @returns True if the command is tagged with the tag
+ */
+export function hasTag(command: Command, tag: string): boolean {
+	return commandTags(command).includes(tag.trim().toLowerCase());
+}
@@ -0,0 +1,304 @@
+import { gunzipSync, gzipSync } from "node:zlib";
+
+/**
+ * Minimal tar.gz support for command bundles
+ *
+ * Writes and reads POSIX ustar archives containing regular UTF-8 text files
+ * only, which is all a command bundle needs. Archives written here extract
+ * with standard `tar -xzf`, and archives created by `tar -czf` from plain
+ * files can be read back: GNU long names and pax path records are applied,
+ * while directories, links and other extended records are skipped.
+ */
+
+/**
+ * A text file stored in an archive
+ */
+export interface TarEntry {
+	/** Relative path using "/" separators (e.g., "commands/en/manifest.json") */
+	readonly path: string;
+	/** File content (UTF-8) */
+	readonly content: string;
+}
+
+/**
+ * Error thrown when an archive cannot be written or parsed
+ */
+export class TarError extends Error {
+	constructor(message: string) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+const BLOCK_SIZE = 512;
+
+/**
+ * Largest uncompressed archive extractTarGz() accepts by default, which is
+ * far more than any command bundle and keeps a gzip bomb from exhausting
+ * memory
+ */
+export const MAX_TAR_SIZE = 64 * 1024 * 1024;
+
+const encoder = new TextEncoder();
+const decoder = new TextDecoder();
+
+/**
+ * Create a gzip-compressed ustar archive
+ *
+ * @param entries - Files to store, in archive order
+ * @param mtime - Modification time recorded for every entry (default: now)
+ * @returns Compressed archive bytes
+ * @throws TarError if a path is too long for the ustar format
+ */
+export function createTarGz(
+	entries: readonly TarEntry[],
+	mtime: Date = new Date(),
+): Uint8Array {
+	const blocks: Uint8Array[] = [];
+	const seconds = Math.floor(mtime.getTime() / 1000);
+
+	for (const entry of entries) {
+		const data = encoder.encode(entry.content);
+		blocks.push(createHeader(entry.path, data.length, seconds));
+		blocks.push(data);
+
+		const padding = (BLOCK_SIZE - (data.length % BLOCK_SIZE)) % BLOCK_SIZE;
+		if (padding > 0) {
+			blocks.push(new Uint8Array(padding));
+		}
+	}
+
+	// End of archive: two zero-filled blocks
+	blocks.push(new Uint8Array(BLOCK_SIZE * 2));
+
+	return new Uint8Array(gzipSync(concat(blocks)));
+}
+
+/**
+ * Extract regular files from a gzip-compressed tar archive
+ *
+ * @param archive - Compressed archive bytes
+ * @param maxSize - Largest accepted uncompressed size in bytes
+ * @returns Files in archive order
+ * @throws TarError if the data is not a valid tar.gz archive or expands
+ *   beyond maxSize
+ */
+export function extractTarGz(
+	archive: Uint8Array,
+	maxSize: number = MAX_TAR_SIZE,
+): TarEntry[] {
+	let tar: Uint8Array;
+	try {
+		tar = new Uint8Array(gunzipSync(archive, { maxOutputLength: maxSize }));
+	} catch (error) {
+		if (error instanceof RangeError) {
+			throw new TarError(`Archive expands to more than ${maxSize} bytes`);
+		}
+		throw new TarError(
+			`Not a gzip archive: ${error instanceof Error ? error.message : error}`,
+		);
+	}
+
+	const entries: TarEntry[] = [];
+	let offset = 0;
+	// Paths from GNU long name and pax headers, which precede their entry
+	let nextPath: string | undefined;
+	let globalPath: string | undefined;
+
+	while (offset + BLOCK_SIZE <= tar.length) {
+		const header = tar.subarray(offset, offset + BLOCK_SIZE);
+		if (header.every((byte) => byte === 0)) {
+			break;
+		}
+
+		verifyChecksum(header, offset);
+
+		const name = readString(header, 0, 100);
+		const prefix = readString(header, 345, 155);
+		const size = readOctal(header, 124, 12);
+		const type = String.fromCharCode(header[156] ?? 0);
+
+		const start = offset + BLOCK_SIZE;
+		const end = start + size;
+		if (end > tar.length) {
+			throw new TarError(`Truncated archive entry: ${name}`);
+		}
+
+		const data = tar.subarray(start, end);
+		switch (type) {
+			// Regular files only ("0" or NUL for old-style archives)
+			case "0":
+			case "\0": {
+				const path = nextPath ?? globalPath ?? joinPath(prefix, name);
+				entries.push({
+					path: path.replace(/^\.\//, ""),
+					content: decoder.decode(data),
+				});
+				nextPath = undefined;
+				break;
+			}
+			case "L":
+				nextPath = readString(data, 0, data.length);
+				break;
+			case "x":
+				nextPath = readPaxPath(data, offset) ?? nextPath;
+				break;
+			case "g":
+				globalPath = readPaxPath(data, offset) ?? globalPath;
+				break;
+			default:
+				// Directories and links are skipped along with their long name
+				nextPath = undefined;
+		}
+
+		offset = start + Math.ceil(size / BLOCK_SIZE) * BLOCK_SIZE;
+	}
+
+	return entries;
+}
+
+function joinPath(prefix: string, name: string): string {
+	return prefix ? `${prefix}/${name}` : name;
+}
+
+/**
+ * Read the path record of a pax extended header
+ *
+ * Records have the form "<length> <key>=<value>\n", where length counts
+ * the bytes of the whole record.
+ *
+ * @param offset - Archive offset of the header, for error messages
+ * @returns The path, or undefined if the header does not set one
+ */
+function readPaxPath(data: Uint8Array, offset: number): string | undefined {
+	let path: string | undefined;
+	let position = 0;
+	while (position < data.length) {
+		const space = data.indexOf(0x20, position);
+		const length = Number(decoder.decode(data.subarray(position, space)));
+		if (
+			space === -1 ||
+			!Number.isInteger(length) ||
+			length <= space - position ||
+			position + length > data.length
+		) {
+			throw new TarError(`Invalid pax header at offset ${offset}`);
+		}
+
+		const record = decoder.decode(
+			data.subarray(space + 1, position + length - 1),
+		);
+		const separator = record.indexOf("=");
+		if (record.slice(0, separator) === "path") {
+			path = record.slice(separator + 1);
+		}
+		position += length;
+	}
+	return path;
+}
+
+function createHeader(path: string, size: number, mtime: number): Uint8Array {
+	const header = new Uint8Array(BLOCK_SIZE);
+	const [prefix, name] = splitPath(path);
+
+	writeString(header, 0, 100, name);
+	writeOctal(header, 100, 8, 0o644);
+	writeOctal(header, 108, 8, 0);
+	writeOctal(header, 116, 8, 0);
+	writeOctal(header, 124, 12, size);
+	writeOctal(header, 136, 12, mtime);
+	header[156] = "0".charCodeAt(0);
+	writeString(header, 257, 6, "ustar");
+	writeString(header, 263, 2, "00");
+	writeString(header, 345, 155, prefix);
+
+	// Checksum is computed with the checksum field filled with spaces
+	header.fill(0x20, 148, 156);
+	const checksum = header.reduce((sum, byte) => sum + byte, 0);
+	writeString(header, 148, 8, `${checksum.toString(8).padStart(6, "0")}\0 `);
+
+	return header;
+}
+
+/**
+ * Split a path into ustar prefix (max 155 bytes) and name (max 100 bytes)
+ */
+function splitPath(path: string): [string, string] {
+	if (encoder.encode(path).length <= 100) {
+		return ["", path];
+	}
+
+	const parts = path.split("/");
+	for (let i = parts.length - 1; i > 0; i--) {
+		const prefix = parts.slice(0, i).join("/");
+		const name = parts.slice(i).join("/");
+		if (
+			encoder.encode(prefix).length <= 155 &&
+			encoder.encode(name).length <= 100
+		) {
+			return [prefix, name];
+		}
+	}
+
+	throw new TarError(`Path too long for tar archive: ${path}`);
+}
+
+function verifyChecksum(header: Uint8Array, offset: number): void {
+	const expected = readOctal(header, 148, 8);
+	let actual = 0;
+	for (let i = 0; i < BLOCK_SIZE; i++) {
+		actual += i >= 148 && i < 156 ? 0x20 : (header[i] ?? 0);
+	}
+	if (actual !== expected) {
+		throw new TarError(`Invalid tar header checksum at offset ${offset}`);
+	}
+}
+
+function writeString(
+	buffer: Uint8Array,
+	offset: number,
+	length: number,
+	value: string,
+): void {
+	buffer.set(encoder.encode(value).subarray(0, length), offset);
+}
+
+function writeOctal(
+	buffer: Uint8Array,
+	offset: number,
+	length: number,
+	value: number,
+): void {
+	writeString(
+		buffer,
+		offset,
+		length,
+		`${value.toString(8).padStart(length - 1, "0")}\0`,
+	);
+}
+
+function readString(buffer: Uint8Array, offset: number, length: number) {
+	const field = buffer.subarray(offset, offset + length);
+	const end = field.indexOf(0);
+	return decoder.decode(end === -1 ? field : field.subarray(0, end));
+}
+
+function readOctal(buffer: Uint8Array, offset: number, length: number) {
+	const text = readString(buffer, offset, length).trim();
+	const value = Number.parseInt(text || "0", 8);
+	if (Number.isNaN(value)) {
+		throw new TarError(`Invalid numeric field in tar header: "${text}"`);
+	}
+	return value;
+}
+
+function concat(chunks: readonly Uint8Array[]): Uint8Array {
+	const total = chunks.reduce((sum, chunk) => sum + chunk.length, 0);
+	const result = new Uint8Array(total);
+	let offset = 0;
+	for (const chunk of chunks) {
+		result.set(chunk, offset);
+		offset += chunk.length;
+	}
+	return result;
+}
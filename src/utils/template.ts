@@ -0,0 +1,206 @@
+/**
+ * Output templates in the style of Go's text/template
+ *
+ * Only field actions are supported: `{{.Name}}`, `{{ .Cache.CommandCount }}`.
+ * Everything outside actions is copied as is, so templates can build shell
+ * prompts and dashboard lines without parsing JSON.
+ */
+
+/**
+ * Error thrown when a template is malformed or names an unknown field
+ */
+export class TemplateError extends Error {
+	constructor(
+		message: string,
+		public readonly template: string,
+	) {
+		super(message);
+		this.name = this.constructor.name;
+	}
+}
+
+/**
+ * Values templates can render: scalars, lists and nested records
+ */
+export type TemplateValue =
+	| string
+	| number
+	| boolean
+	| undefined
+	| readonly string[]
+	| TemplateData;
+
+/**
+ * Data a template is rendered with; field names are capitalized like Go
+ * struct fields
+ */
+export interface TemplateData {
+	readonly [field: string]: TemplateValue;
+}
+
+type TemplatePart =
+	| { readonly text: string }
+	| { readonly path: readonly string[]; readonly action: string };
+
+/**
+ * A parsed template, ready to render
+ */
+export interface Template {
+	readonly source: string;
+	readonly parts: readonly TemplatePart[];
+}
+
+const FIELD_PATH = /^(?:\.[A-Za-z_][A-Za-z0-9_]*)+$/;
+
+/**
+ * Check whether an output format is a template rather than a format name
+ */
+export function isTemplate(format: string): boolean {
+	return format.includes("{{");
+}
+
+/**
+ * Parse a template
+ *
+ * @param source - Template text (e.g., "{{.Name}}\t{{.State}}")
+ * @returns The parsed template
+ * @throws TemplateError if an action is unclosed, empty or not a field
+ */
+export function parseTemplate(source: string): Template {
+	const parts: TemplatePart[] = [];
+	let position = 0;
+
+	while (position < source.length) {
+		const open = source.indexOf("{{", position);
+		const close = source.indexOf("}}", position);
+		if (close !== -1 && (open === -1 || close < open)) {
+			throw new TemplateError(
+				`Unexpected '}}' at offset ${close} without a matching '{{'`,
+				source,
+			);
+		}
+		if (open === -1) {
+			parts.push({ text: source.slice(position) });
+			break;
+		}
+		if (open > position) {
+			parts.push({ text: source.slice(position, open) });
+		}
+		const end = source.indexOf("}}", open + 2);
+		if (end === -1) {
+			throw new TemplateError(
+				`Unclosed action at offset ${open}: expected '}}'`,
+				source,
+			);
+		}
+		const action = source.slice(open + 2, end).trim();
+		if (!FIELD_PATH.test(action)) {
+			throw new TemplateError(
+				action === ""
+					? `Empty action at offset ${open}: expected a field such as {{.Name}}`
+					: `Unsupported action '{{${action}}}' at offset ${open}: only fields such as {{.Name}} are supported`,
+				source,
+			);
+		}
+		parts.push({ path: action.slice(1).split("."), action });
+		position = end + 2;
+	}
+
+	return { source, parts };
+}
+
+/**
+ * Check that every field of a template exists in some data
+ *
+ * Lets callers reject a template before printing anything, even when there
+ * is nothing to render yet.
+ *
+ * @param template - Parsed template
+ * @param data - Data with every field present (values may be undefined)
+ * @throws TemplateError naming the unknown field and the available ones
+ */
+export function checkTemplateFields(
+	template: Template,
+	data: TemplateData,
+): void {
+	for (const part of template.parts) {
+		if ("path" in part) {
+			lookup(part.path, data, template.source);
+		}
+	}
+}
+
+/**
+ * Render a template with data
+ *
+ * Lists are joined with commas and missing values render as nothing.
+ *
+ * @param template - Parsed template
+ * @param data - Data to fill in
+ * @returns The rendered text
+ * @throws TemplateError if a field does not exist in the data
+ */
+export function renderTemplate(template: Template, data: TemplateData): string {
+	return template.parts
+		.map((part) => {
+			if ("text" in part) {
+				return part.text;
+			}
+			const value = lookup(part.path, data, template.source);
+			if (isRecord(value)) {
+				throw new TemplateError(
+					`Field '${part.action}' has fields of its own: ${listFields(value, part.action)}`,
+					template.source,
+				);
+			}
+			if (value === undefined) {
+				return "";
+			}
+			return Array.isArray(value) ? value.join(",") : String(value);
+		})
+		.join("");
+}
+
+/**
+ * Follow a field path through nested records
+ */
+function lookup(
+	path: readonly string[],
+	data: TemplateData,
+	source: string,
+): TemplateValue {
+	let value: TemplateValue = data;
+	for (const [index, field] of path.entries()) {
+		const parent = path
+			.slice(0, index)
+			.map((name) => `.${name}`)
+			.join("");
+		if (!isRecord(value)) {
+			throw new TemplateError(`Field '${parent}' has no fields`, source);
+		}
+		if (!Object.hasOwn(value, field)) {
+			throw new TemplateError(
+				`Unknown field '${parent}.${field}'. Available fields: ${listFields(value, parent)}`,
+				source,
+			);
+		}
+		value = value[field];
+	}
+	return value;
+}
+
+/**
+ * List the fields of a record for error messages
+ */
+function listFields(record: TemplateData, prefix: string): string {
+	return Object.keys(record)
+		.map((name) => `${prefix}.${name}`)
+		.join(", ");
+}
+
+/**
+ * Check whether a value is a nested record of fields
+ */
+function isRecord(value: TemplateValue): value is TemplateData {
+	return typeof value === "object" && !Array.isArray(value);
+}
@@ -0,0 +1,82 @@
+/**
+ * Answer remembered for a command that requests powerful tools
+ *
+ * Stored by command name under the `toolConsent` config key. A decision only
+ * applies while the command requests no powerful tool beyond those it was
+ * made for, so a command that starts asking for more is reviewed again.
+ */
+export interface ToolDecision {
+	/** Whether installing the command was allowed */
+	readonly allow: boolean;
+	/** Powerful tools the decision was made for */
+	readonly tools: readonly string[];
+}
+
+/**
+ * Tools that change files, powerful unless limited to specific paths
+ */
+const EDITING_TOOLS = new Set(["Edit", "MultiEdit", "NotebookEdit", "Write"]);
+
+/**
+ * Path patterns that reach beyond a specific part of the project: anything
+ * starting with a wildcard, absolute or home paths, and parent directories
+ */
+const BROAD_PATH = /^(\*|\/|~|\.\.)/;
+
+/**
+ * Pick the allowed-tools entries that deserve the user's consent
+ *
+ * Any Bash access runs shell commands, so it always counts. Editing tools
+ * count unless they are limited to specific paths (e.g., `Edit(docs/*.md)`).
+ *
+ * @param tools - allowed-tools entries of a command
+ * @returns The powerful entries, in their original order
+ */
+export function powerfulTools(tools: readonly string[]): string[] {
+	return tools.filter((tool) => {
+		const match = tool.trim().match(/^(\w+)(?:\((.*)\))?$/);
+		if (!match?.[1]) {
+			return false;
+		}
+		const [, name, argument] = match;
+		if (name === "Bash") {
+			return true;
+		}
+		return (
+			EDITING_TOOLS.has(name) &&
+			(argument === undefined || BROAD_PATH.test(argument.trim()))
+		);
+	});
+}
+
+/**
+ * Check whether a remembered decision applies to the tools requested now
+ *
+ * @param decision - Remembered decision
+ * @param tools - Powerful tools the command requests
+ * @returns True if every requested tool was covered by the decision
+ */
+export function decisionCovers(
+	decision: ToolDecision,
+	tools: readonly string[],
+): boolean {
+	return tools.every((tool) => decision.tools.includes(tool));
+}
+
+/**
+ * Check the shape of a value read from the `toolConsent` config key
+ *
+ * @param value - One entry of the map
+ * @returns True if the value is a ToolDecision
+ */
+export function isToolDecision(value: unknown): value is ToolDecision {
+	if (typeof value !== "object" || value === null) {
+		return false;
+	}
+	const { allow, tools } = value as Record<string, unknown>;
+	return (
+		typeof allow === "boolean" &&
+		Array.isArray(tools) &&
+		tools.every((tool) => typeof tool === "string")
+	);
+}
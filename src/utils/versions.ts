@@ -0,0 +1,18 @@
+/**
+ * Compare dotted numeric versions ("0.10.0" > "0.9.1")
+ *
+ * A leading "v" (as in release tags such as "v1.2.0") is ignored.
+ *
+ * @returns Negative if a is older, positive if newer, 0 if equal
+ */
+export function compareVersions(a: string, b: string): number {
+	const left = a.replace(/^v/, "").split(".").map(Number);
+	const right = b.replace(/^v/, "").split(".").map(Number);
+	for (let i = 0; i < Math.max(left.length, right.length); i++) {
+		const diff = (left[i] ?? 0) - (right[i] ?? 0);
+		if (diff !== 0) {
+			return diff;
+		}
+	}
+	return 0;
+}
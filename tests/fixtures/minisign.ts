@@ -0,0 +1,67 @@
+import {
+	createHash,
+	generateKeyPairSync,
+	type KeyObject,
+	sign,
+} from "node:crypto";
+
+/**
+ * A throwaway minisign key pair for signing test fixtures
+ */
+export interface TestSigner {
+	/** Public key in minisign.pub format */
+	readonly publicKey: string;
+	/** Create a .minisig file for the content */
+	sign(content: string, algorithm?: "Ed" | "ED"): string;
+}
+
+/**
+ * Create a signer writing minisign-compatible detached signatures
+ *
+ * @param keyIdByte - Fills the 8-byte key id, so signers can be told apart
+ */
+export function createTestSigner(keyIdByte = 1): TestSigner {
+	const { publicKey, privateKey } = generateKeyPairSync("ed25519");
+	const keyId = Buffer.alloc(8, keyIdByte);
+	const { x } = publicKey.export({ format: "jwk" });
+	const raw = Buffer.from(x ?? "", "base64url");
+
+	return {
+		publicKey: [
+			"untrusted comment: minisign public key",
+			Buffer.concat([Buffer.from("Ed"), keyId, raw]).toString("base64"),
+			"",
+		].join("\n"),
+		sign: (content, algorithm = "ED") =>
+			signDetached(content, algorithm, keyId, privateKey),
+	};
+}
+
+function signDetached(
+	content: string,
+	algorithm: "Ed" | "ED",
+	keyId: Buffer,
+	privateKey: KeyObject,
+): string {
+	const message =
+		algorithm === "ED"
+			? createHash("blake2b512").update(content, "utf8").digest()
+			: Buffer.from(content, "utf8");
+	const signature = sign(null, message, privateKey);
+	const trustedComment = "timestamp:1700000000\tfile:manifest.json";
+	const global = sign(
+		null,
+		Buffer.concat([signature, Buffer.from(trustedComment, "utf8")]),
+		privateKey,
+	);
+
+	return [
+		"untrusted comment: signature from minisign secret key",
+		Buffer.concat([Buffer.from(algorithm), keyId, signature]).toString(
+			"base64",
+		),
+		`trusted comment: ${trustedComment}`,
+		global.toString("base64"),
+		"",
+	].join("\n");
+}
@@ -0,0 +1,42 @@
+/**
+ * Deterministic random helpers for property-style tests
+ *
+ * Every property run is driven by a seeded PRNG so failures are reproducible:
+ * a failing assertion can be replayed by re-running with the same seed.
+ */
+
+/**
+ * Small deterministic PRNG (mulberry32)
+ *
+ * @param seed - Any 32-bit integer
+ * @returns Function returning floats in [0, 1)
+ */
+export function createRandom(seed: number): () => number {
+	let state = seed >>> 0;
+	return () => {
+		state = (state + 0x6d2b79f5) >>> 0;
+		let t = state;
+		t = Math.imul(t ^ (t >>> 15), t | 1);
+		t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+		return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+	};
+}
+
+/**
+ * Pick a random element of a non-empty array
+ */
+export function pick<T>(random: () => number, items: readonly T[]): T {
+	return items[Math.floor(random() * items.length)] as T;
+}
+
+/**
+ * Return a shuffled copy of items (Fisher-Yates)
+ */
+export function shuffle<T>(items: readonly T[], random: () => number): T[] {
+	const result = [...items];
+	for (let i = result.length - 1; i > 0; i--) {
+		const j = Math.floor(random() * (i + 1));
+		[result[i], result[j]] = [result[j] as T, result[i] as T];
+	}
+	return result;
+}
@@ -0,0 +1,64 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import { mkdtemp, readFile, realpath, rm } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { HookError, HookRunner } from "../../src/services/HookRunner.ts";
+
+describe.skipIf(process.platform === "win32")("HookRunner", () => {
+	let projectDir: string;
+
+	beforeEach(async () => {
+		projectDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-hooks-")),
+		);
+	});
+
+	afterEach(async () => {
+		await rm(projectDir, { recursive: true, force: true });
+	});
+
+	it("should run hooks in order from the project root", async () => {
+		await new HookRunner(projectDir).run("post-add", [
+			"pwd > log",
+			"echo second >> log",
+		]);
+
+		expect(await readFile(join(projectDir, "log"), "utf8")).toBe(
+			`${projectDir}\nsecond\n`,
+		);
+	});
+
+	it("should describe the operation in the environment", async () => {
+		await new HookRunner(projectDir).run(
+			"pre-add",
+			[
+				'echo "$CLAUDE_CMD_HOOK $CLAUDE_CMD_OPERATION $CLAUDE_CMD_COMMAND $CLAUDE_CMD_LANGUAGE $CLAUDE_CMD_TARGET" > env',
+			],
+			{ command: "frontend:review", language: "fr", target: "project" },
+		);
+
+		expect(await readFile(join(projectDir, "env"), "utf8")).toBe(
+			"pre-add add frontend:review fr project\n",
+		);
+	});
+
+	it("should stop at the first failing hook", async () => {
+		const run = new HookRunner(projectDir).run("pre-remove", [
+			"exit 3",
+			"touch ran",
+		]);
+
+		await expect(run).rejects.toThrow(HookError);
+		await expect(run).rejects.toThrow("failed (exit code 3)");
+		expect(await Bun.file(join(projectDir, "ran")).exists()).toBe(false);
+	});
+
+	it("should kill hooks that run too long", async () => {
+		const started = Date.now();
+
+		await expect(
+			new HookRunner(projectDir, 100).run("post-update", ["sleep 5"]),
+		).rejects.toThrow("timed out after 100ms");
+		expect(Date.now() - started).toBeLessThan(4000);
+	});
+});
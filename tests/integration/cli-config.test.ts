@@ -0,0 +1,144 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import { mkdir, mkdtemp, readFile, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { runCli } from "../testUtils.ts";
+
+describe("CLI Config Command Integration", () => {
+	let projectDir: string;
+
+	beforeEach(async () => {
+		projectDir = await mkdtemp(join(tmpdir(), "claude-cmd-config-"));
+	});
+
+	afterEach(async () => {
+		await rm(projectDir, { recursive: true, force: true });
+	});
+
+	const readProjectConfig = async () =>
+		JSON.parse(
+			await readFile(
+				join(projectDir, ".claude", "config.claude-cmd.json"),
+				"utf-8",
+			),
+		);
+
+	it("should display help for config subcommands", async () => {
+		const { result, stdout } = await runCli(["config", "--help"]);
+
+		expect(result).toBe(0);
+		for (const subcommand of [
+			"list",
+			"get",
+			"set",
+			"unset",
+			"edit",
+			"validate",
+		]) {
+			expect(stdout).toContain(subcommand);
+		}
+	});
+
+	it("should set, get and unset a project setting", async () => {
+		const set = await runCli(
+			["config", "set", "--project", "previewLines", "20"],
+			projectDir,
+		);
+		expect(set.result).toBe(0);
+		expect(set.stdout).toContain("Set previewLines = 20 in project config");
+		expect(await readProjectConfig()).toEqual({ previewLines: 20 });
+
+		const get = await runCli(
+			["config", "get", "--project", "previewLines"],
+			projectDir,
+		);
+		expect(get.stdout.trim()).toBe("20");
+
+		const unset = await runCli(
+			["config", "unset", "--project", "previewLines"],
+			projectDir,
+		);
+		expect(unset.result).toBe(0);
+		expect(await readProjectConfig()).toEqual({});
+
+		const missing = await runCli(
+			["config", "get", "--project", "previewLines"],
+			projectDir,
+		);
+		expect(missing.result).toBe(2);
+		expect(missing.stdout).toBe("");
+	});
+
+	it("should list project settings with their source", async () => {
+		await runCli(
+			["config", "set", "--project", "strictLanguage", "true"],
+			projectDir,
+		);
+
+		const { result, stdout } = await runCli(
+			["config", "list", "--project"],
+			projectDir,
+		);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("strictLanguage = true");
+	});
+
+	it("should reject unknown keys and invalid values", async () => {
+		const unknown = await runCli(
+			["config", "set", "--project", "languge", "fr"],
+			projectDir,
+		);
+		expect(unknown.result).toBe(5);
+		expect(unknown.stderr).toContain("Unknown configuration key 'languge'");
+
+		const invalid = await runCli(
+			["config", "set", "--project", "repositoryType", "svn"],
+			projectDir,
+		);
+		expect(invalid.result).toBe(5);
+		expect(invalid.stderr).toContain("expected one of http, git");
+	});
+
+	it("should report config file problems with their position", async () => {
+		await mkdir(join(projectDir, ".claude"), { recursive: true });
+		await writeFile(
+			join(projectDir, ".claude", "config.claude-cmd.json"),
+			'{\n  "languge": "fr"\n}\n',
+		);
+
+		const { result, stdout } = await runCli(
+			["config", "validate", "--project"],
+			projectDir,
+		);
+
+		expect(result).toBe(5);
+		expect(stdout).toContain(
+			"config.claude-cmd.json:2:3: error: unknown key 'languge' (did you mean 'preferredLanguage'?)",
+		);
+	});
+
+	it("should pass valid config files", async () => {
+		await runCli(
+			["config", "set", "--project", "previewLines", "20"],
+			projectDir,
+		);
+
+		const { result, stdout } = await runCli(
+			["config", "validate", "--project"],
+			projectDir,
+		);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("is valid");
+	});
+
+	it("should reject --project together with --global", async () => {
+		const { result } = await runCli(
+			["config", "list", "--project", "--global"],
+			projectDir,
+		);
+
+		expect(result).not.toBe(0);
+	});
+});
@@ -0,0 +1,73 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import {
+	mkdir,
+	mkdtemp,
+	readFile,
+	realpath,
+	rm,
+	writeFile,
+} from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import { extractTarGz } from "../../src/utils/tar.js";
+import { runCli } from "../testUtils.ts";
+
+const COMMAND = "---\ndescription: Review a pull request\n---\n\n# Review\n";
+
+describe("CLI Export Command Integration", () => {
+	let homeDir: string;
+	let workDir: string;
+
+	beforeEach(async () => {
+		homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-export-")),
+		);
+		const filePath = join(homeDir, ".claude", "commands", "git", "review.md");
+		await mkdir(dirname(filePath), { recursive: true });
+		await writeFile(filePath, COMMAND);
+		workDir = join(homeDir, "work");
+		await mkdir(workDir);
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const runExport = (...args: string[]) =>
+		runCli(["export", ...args], workDir, {
+			HOME: homeDir,
+			CLAUDE_CONFIG_DIR: "",
+			CLAUDE_HOME: "",
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+		});
+
+	it("should copy commands into a directory, keeping namespaces", async () => {
+		const dest = join(homeDir, "backup");
+
+		const { result, stdout } = await runExport("--scope", "personal", dest);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Exported 1 command(s) (personal)");
+		expect(await readFile(join(dest, "git", "review.md"), "utf-8")).toBe(
+			COMMAND,
+		);
+	});
+
+	it("should write a tar.gz archive", async () => {
+		const dest = join(homeDir, "commands.tar.gz");
+
+		const { result } = await runExport(dest);
+
+		expect(result).toBe(0);
+		expect(extractTarGz(new Uint8Array(await readFile(dest)))).toEqual([
+			{ path: "personal/git/review.md", content: COMMAND },
+		]);
+	});
+
+	it("should reject an unknown scope", async () => {
+		const { result, stderr } = await runExport("--scope", "team", homeDir);
+
+		expect(result).not.toBe(0);
+		expect(stderr).toContain("Must be 'personal', 'project' or 'all'.");
+	});
+});
@@ -0,0 +1,79 @@
+import { describe, expect, it } from "bun:test";
+import { runCli } from "../testUtils.ts";
+
+describe("CLI Global --language Flag Integration", () => {
+	it("should document --language in root help", async () => {
+		const { result, stdout } = await runCli(["--help"]);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("--language <lang>");
+	});
+
+	it("should reject an invalid language code", async () => {
+		const { result, stderr } = await runCli([
+			"--language",
+			"not-a-language",
+			"list",
+		]);
+
+		expect(result).not.toBe(0);
+		expect(stderr).toContain("Must be a 2-3 letter language code.");
+	});
+
+	it("should be honored by list", async () => {
+		const { result } = await runCli(["--language", "en", "list", "--force"]);
+
+		expect(result).toBe(0);
+	});
+
+	it("should be honored by search", async () => {
+		const { result, stdout } = await runCli([
+			"--language",
+			"en",
+			"search",
+			"debug",
+		]);
+
+		expect(result).toBe(0);
+		expect(
+			/Found \d+ commands? matching/.test(stdout) ||
+				stdout.includes("No commands found"),
+		).toBe(true);
+	});
+
+	it("should be honored by info", async () => {
+		const { stdout } = await runCli([
+			"--language",
+			"en",
+			"info",
+			"debug-help",
+			"--force",
+		]);
+
+		expect(stdout).toContain("Language: en");
+	});
+
+	it("should be honored by status", async () => {
+		const { result, stdout } = await runCli(["--language", "en", "status"]);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("en");
+	});
+
+	it("should use a non-default language over the environment", async () => {
+		const { result, stdout } = await runCli(
+			["--language", "ja", "status", "--format", "{{.Cache.Language}}"],
+			undefined,
+			{ CLAUDE_CMD_LANG: "fr" },
+		);
+
+		expect(result).toBe(0);
+		expect(stdout.trim()).toBe("ja");
+	});
+
+	it("should be accepted after the subcommand name", async () => {
+		const { result } = await runCli(["installed", "--language", "en"]);
+
+		expect(result).toBe(0);
+	});
+});
@@ -35,6 +35,27 @@ describe("CLI Help Integration", () => {
 		expect(stderr).toBe("");
 	});
 
+	it("should group commands by purpose in root help", async () => {
+		const { stdout } = await runCli(["--help"]);
+
+		const headings = ["Discover:", "Install:", "Maintain:", "Configure:"];
+		const positions = headings.map((heading) => stdout.indexOf(heading));
+		for (const position of positions) {
+			expect(position).toBeGreaterThan(-1);
+		}
+		expect(positions).toEqual([...positions].sort((a, b) => a - b));
+
+		const discover = stdout.slice(positions[0], positions[1]);
+		expect(discover).toContain("list");
+		expect(discover).toContain("search");
+		expect(discover).toContain("info");
+
+		const install = stdout.slice(positions[1], positions[2]);
+		expect(install).toContain("add");
+		expect(install).toContain("remove");
+		expect(install).toContain("installed");
+	});
+
 	it("should display version when --version flag is provided", async () => {
 		const { stdout, stderr } = await runCli(["--version"]);
 
@@ -0,0 +1,149 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import {
+	mkdir,
+	mkdtemp,
+	readFile,
+	realpath,
+	rm,
+	writeFile,
+} from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import { createTarGz } from "../../src/utils/tar.js";
+import { runCli } from "../testUtils.ts";
+
+const COMMAND = "---\ndescription: Review a pull request\n---\n\n# Review\n";
+
+describe("CLI Import Command Integration", () => {
+	let homeDir: string;
+	let sourceDir: string;
+
+	beforeEach(async () => {
+		homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-import-")),
+		);
+		sourceDir = join(homeDir, "old-commands");
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const source = async (relativePath: string, content = COMMAND) => {
+		const filePath = join(sourceDir, relativePath);
+		await mkdir(dirname(filePath), { recursive: true });
+		await writeFile(filePath, content);
+		return filePath;
+	};
+	const personalDir = () => join(homeDir, ".claude", "commands");
+
+	const runImport = (...args: string[]) =>
+		runCli(["import", ...args], homeDir, {
+			HOME: homeDir,
+			CLAUDE_CONFIG_DIR: "",
+			CLAUDE_HOME: "",
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+		});
+
+	it("should map subdirectories to namespaces and normalize names", async () => {
+		await source("Code Review.md");
+		await source("Front End/my_component.md");
+
+		const { result, stdout } = await runImport(sourceDir);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Imported 2 command(s) into personal");
+		expect(await readFile(join(personalDir(), "code-review.md"), "utf-8")).toBe(
+			COMMAND,
+		);
+		expect(
+			await readFile(
+				join(personalDir(), "front-end", "my-component.md"),
+				"utf-8",
+			),
+		).toBe(COMMAND);
+	});
+
+	it("should apply --namespace and --flatten", async () => {
+		await source("nested/deep/review.md");
+
+		const { result } = await runImport(
+			sourceDir,
+			"--namespace",
+			"legacy",
+			"--flatten",
+		);
+
+		expect(result).toBe(0);
+		expect(
+			await readFile(join(personalDir(), "legacy", "review.md"), "utf-8"),
+		).toBe(COMMAND);
+	});
+
+	it("should import files matching a glob pattern", async () => {
+		await source("keep/review.md");
+		await source("skip/other.md");
+
+		const { result } = await runImport(join(sourceDir, "keep", "*.md"));
+
+		expect(result).toBe(0);
+		expect(await readFile(join(personalDir(), "review.md"), "utf-8")).toBe(
+			COMMAND,
+		);
+		await expect(
+			readFile(join(personalDir(), "other.md"), "utf-8"),
+		).rejects.toThrow();
+	});
+
+	it("should read an archive written by export", async () => {
+		const archive = join(homeDir, "commands.tar.gz");
+		await writeFile(
+			archive,
+			createTarGz([
+				{ path: "personal/tools/review.md", content: COMMAND },
+				{ path: "personal/notes.txt", content: "not a command" },
+			]),
+		);
+
+		const { result, stdout } = await runImport(archive);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Imported 1 command(s) into personal");
+		expect(
+			await readFile(join(personalDir(), "tools", "review.md"), "utf-8"),
+		).toBe(COMMAND);
+	});
+
+	it("should not write anything with --dry-run", async () => {
+		await source("review.md");
+
+		const { result, stdout } = await runImport(sourceDir, "--dry-run");
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Would import");
+		await expect(
+			readFile(join(personalDir(), "review.md"), "utf-8"),
+		).rejects.toThrow();
+	});
+
+	it("should refuse to overwrite without --force", async () => {
+		await mkdir(personalDir(), { recursive: true });
+		await writeFile(join(personalDir(), "review.md"), "existing");
+		await source("review.md");
+
+		const { result, stderr } = await runImport(sourceDir);
+
+		expect(result).not.toBe(0);
+		expect(stderr).toContain("already installed");
+		expect(await readFile(join(personalDir(), "review.md"), "utf-8")).toBe(
+			"existing",
+		);
+
+		const forced = await runImport(sourceDir, "--force");
+
+		expect(forced.result).toBe(0);
+		expect(await readFile(join(personalDir(), "review.md"), "utf-8")).toBe(
+			COMMAND,
+		);
+	});
+});
@@ -0,0 +1,168 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import {
+	mkdir,
+	mkdtemp,
+	readFile,
+	rm,
+	stat,
+	writeFile,
+} from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { runCli } from "../testUtils.ts";
+
+describe("CLI Init Command Integration", () => {
+	let homeDir: string;
+
+	beforeEach(async () => {
+		homeDir = await mkdtemp(join(tmpdir(), "claude-cmd-init-"));
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const runInitIn = (cwd: string, ...args: string[]) =>
+		runCli(["init", "--yes", "--no-cache", ...args], cwd, {
+			HOME: homeDir,
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+			CLAUDE_CMD_LANG: "",
+			LC_ALL: "",
+			LANG: "",
+		});
+	const runInit = (...args: string[]) => runInitIn(homeDir, ...args);
+
+	const readGlobalConfig = async () =>
+		JSON.parse(
+			await readFile(
+				join(homeDir, ".config", "claude-cmd", "config.claude-cmd.json"),
+				"utf-8",
+			),
+		);
+
+	it("should write the answers to the global config", async () => {
+		const { result, stdout } = await runInit(
+			"--language",
+			"fr",
+			"--repository",
+			"git@example.com:team/commands.git",
+			"--target",
+			"project",
+		);
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Saved");
+		expect(await readGlobalConfig()).toEqual({
+			preferredLanguage: "fr",
+			defaultTarget: "project",
+			repositoryURL: "git@example.com:team/commands.git",
+			repositoryType: "git",
+		});
+	});
+
+	it("should accept the suggested answers with --yes", async () => {
+		const { result } = await runInit();
+
+		expect(result).toBe(0);
+		expect(await readGlobalConfig()).toEqual({
+			preferredLanguage: "en",
+			defaultTarget: "personal",
+		});
+	});
+
+	it("should scaffold the project with --project", async () => {
+		await writeFile(join(homeDir, ".gitignore"), "node_modules");
+
+		const { result } = await runInit(
+			"--project",
+			"--language",
+			"de",
+			"--command",
+			"review",
+			"--no-install",
+		);
+
+		expect(result).toBe(0);
+		const commandsDir = await stat(join(homeDir, ".claude", "commands"));
+		expect(commandsDir.isDirectory()).toBe(true);
+		expect(
+			JSON.parse(
+				await readFile(
+					join(homeDir, ".claude", "config.claude-cmd.json"),
+					"utf-8",
+				),
+			),
+		).toEqual({ preferredLanguage: "de", commands: ["review"] });
+		expect(await readFile(join(homeDir, ".gitignore"), "utf-8")).toBe(
+			"node_modules\n.claude/settings.local.json\n",
+		);
+	});
+
+	it("should leave .gitignore alone with --no-gitignore", async () => {
+		const { result } = await runInit("--project", "--no-gitignore");
+
+		expect(result).toBe(0);
+		await expect(stat(join(homeDir, ".gitignore"))).rejects.toThrow();
+	});
+
+	describe("from a subdirectory of a project", () => {
+		let projectDir: string;
+		let subDir: string;
+
+		beforeEach(async () => {
+			projectDir = join(homeDir, "project");
+			subDir = join(projectDir, "src", "deep");
+			await mkdir(join(projectDir, ".claude"), { recursive: true });
+			await mkdir(subDir, { recursive: true });
+		});
+
+		it("should update the project found upwards", async () => {
+			const { result } = await runInitIn(
+				subDir,
+				"--project",
+				"--command",
+				"review",
+				"--no-install",
+			);
+
+			expect(result).toBe(0);
+			expect(
+				JSON.parse(
+					await readFile(
+						join(projectDir, ".claude", "config.claude-cmd.json"),
+						"utf-8",
+					),
+				),
+			).toEqual({ preferredLanguage: "en", commands: ["review"] });
+			expect(await readFile(join(projectDir, ".gitignore"), "utf-8")).toBe(
+				".claude/settings.local.json\n",
+			);
+			await expect(stat(join(subDir, ".claude"))).rejects.toThrow();
+		});
+
+		it("should stay in the current directory with --no-project-discovery", async () => {
+			const { result } = await runInitIn(
+				subDir,
+				"--no-project-discovery",
+				"--project",
+				"--no-gitignore",
+				"--no-install",
+			);
+
+			expect(result).toBe(0);
+			expect(
+				(await stat(join(subDir, ".claude", "commands"))).isDirectory(),
+			).toBe(true);
+			await expect(
+				stat(join(projectDir, ".claude", "config.claude-cmd.json")),
+			).rejects.toThrow();
+		});
+	});
+
+	it("should reject an invalid repository URL", async () => {
+		const { result, stderr } = await runInit("--repository", "not a url");
+
+		expect(result).toBe(5);
+		expect(stderr).toContain("Invalid repository URL");
+	});
+});
@@ -35,6 +35,17 @@ describe("CLI Installed Command Integration", () => {
 		expect(result).toBe(0);
 	});
 
+	it("should reject a --format template instead of ignoring it", async () => {
+		const { result, stderr } = await runCli([
+			"--format",
+			"{{.Name}}",
+			"installed",
+		]);
+
+		expect(result).toBe(5);
+		expect(stderr).toContain("'installed' does not support --format templates");
+	});
+
 	describe("Milestone 2.3: Enhanced Display Features", () => {
 		it("should show location indicators for installed commands by default", async () => {
 			const { result, stdout } = await runCli(["installed"]);
@@ -31,6 +31,31 @@ describe("CLI Language Command Integration", () => {
 		});
 	});
 
+	describe("language status", () => {
+		it("should show the winning source and every source", async () => {
+			const { result, stdout } = await runCli(["language", "status"]);
+
+			expect(result).toBe(0);
+			expect(stdout).toMatch(/Current language: \w{2,3} \(from .+\)/);
+			expect(stdout).toContain("CLAUDE_CMD_LANG");
+			expect(stdout).toContain("Project config");
+			expect(stdout).toContain("Global config");
+			expect(stdout).toContain("System locale");
+		});
+
+		it("should attribute the language to the global flag", async () => {
+			const { result, stdout } = await runCli([
+				"--language",
+				"ja",
+				"language",
+				"status",
+			]);
+
+			expect(result).toBe(0);
+			expect(stdout).toContain("Current language: ja (from --language flag)");
+		});
+	});
+
 	describe("language set", () => {
 		let currentLang: string;
 		let validLanguages: string[];
@@ -11,6 +11,11 @@ describe("CLI List Command Integration", () => {
 		);
 		expect(stdout).toContain("--language");
 		expect(stdout).toContain("--force");
+		expect(stdout).toContain("--sort");
+		expect(stdout).toContain("--page");
+		expect(stdout).toContain("--no-truncate");
+		expect(stdout).toContain("--installed-only");
+		expect(stdout).toContain("--not-installed");
 	});
 
 	it("should accept language and force options without argument errors", async () => {
@@ -1,4 +1,8 @@
 import { describe, expect, it } from "bun:test";
+import { mkdir, mkdtemp, realpath, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { join } from "node:path";
+import { hooksFingerprint } from "../../src/utils/hooks.js";
 import { runCli } from "../testUtils.ts";
 
 describe("CLI Remove Command Integration", () => {
@@ -7,7 +11,8 @@ describe("CLI Remove Command Integration", () => {
 
 		expect(result).toBe(0);
 		expect(stdout).toContain("Remove an installed Claude Code command");
-		expect(stdout).toContain("<command-name>");
+		expect(stdout).toContain("[command-name]");
+		expect(stdout).toContain("--namespace");
 		expect(stdout).toContain("--yes");
 	});
 
@@ -18,29 +23,28 @@ describe("CLI Remove Command Integration", () => {
 		expect(stderr).toContain("missing required argument 'command-name'");
 	});
 
-	it("should handle non-existent command gracefully", async () => {
+	it("should exit with NotFound for a command that is not installed", async () => {
 		const { result, stdout } = await runCli([
 			"remove",
 			"nonexistent-command",
 			"--yes",
 		]);
 
-		expect(result).toBe(0);
+		expect(result).toBe(2);
 		expect(stdout).toContain("is not installed");
 	});
 
 	it("should accept --yes option", async () => {
 		const { result } = await runCli(["remove", "test-command", "--yes"]);
 
-		// Should exit cleanly (either with success if command exists or with
-		// "not installed" message). The --yes flag should be accepted without errors.
-		expect(result).toBe(0);
+		// Removed if it exists, NotFound otherwise; --yes is accepted either way
+		expect([0, 2]).toContain(result);
 	});
 
 	it("should accept --yes option shorthand (-y)", async () => {
 		const { result } = await runCli(["remove", "test-command", "-y"]);
 
-		expect(result).toBe(0);
+		expect([0, 2]).toContain(result);
 	});
 
 	it("should show only cancellation message when user cancels removal", async () => {
@@ -48,9 +52,159 @@ describe("CLI Remove Command Integration", () => {
 		// For now, we'll test the behavior when command is not installed (which is fine)
 		const { result, stdout } = await runCli(["remove", "nonexistent-command"]);
 
-		expect(result).toBe(0);
+		expect(result).toBe(2);
 		expect(stdout).toContain("is not installed");
 		// Should NOT contain success message when command doesn't exist
 		expect(stdout).not.toContain("✓ Successfully removed command");
 	});
+
+	it("should suggest installed commands for a mistyped name", async () => {
+		const homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-remove-")),
+		);
+		try {
+			const commandsDir = join(homeDir, ".claude", "commands");
+			await mkdir(commandsDir, { recursive: true });
+			await writeFile(
+				join(commandsDir, "debug-help.md"),
+				"---\ndescription: Debug help\n---\n\n# Debug\n",
+			);
+			const workDir = join(homeDir, "work");
+			await mkdir(workDir);
+
+			const { result, stdout } = await runCli(
+				["remove", "debg-help", "--yes"],
+				workDir,
+				{
+					HOME: homeDir,
+					CLAUDE_CONFIG_DIR: "",
+					CLAUDE_HOME: "",
+					CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+				},
+			);
+
+			expect(result).toBe(0);
+			expect(stdout).toContain("Did you mean: debug-help?");
+		} finally {
+			await rm(homeDir, { recursive: true, force: true });
+		}
+	});
+
+	it("should run project hooks only once the user allows them", async () => {
+		const homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-remove-")),
+		);
+		try {
+			const commandsDir = join(homeDir, ".claude", "commands");
+			await mkdir(commandsDir, { recursive: true });
+			const workDir = join(homeDir, "work");
+			await mkdir(join(workDir, ".claude"), { recursive: true });
+			const hooks = {
+				"post-remove": 'echo "$CLAUDE_CMD_COMMAND" > removed',
+			} as const;
+			await writeFile(
+				join(workDir, ".claude", "config.claude-cmd.json"),
+				JSON.stringify({ hooks }),
+			);
+			const remove = async () => {
+				await writeFile(join(commandsDir, "debug-help.md"), "# Debug\n");
+				return runCli(["remove", "debug-help", "--yes", "--purge"], workDir, {
+					HOME: homeDir,
+					CLAUDE_CONFIG_DIR: "",
+					CLAUDE_HOME: "",
+					CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+				});
+			};
+
+			const skipped = await remove();
+			expect(skipped.result).toBe(0);
+			expect(skipped.stderr).toContain("Skipping project hooks");
+			expect(await Bun.file(join(workDir, "removed")).exists()).toBe(false);
+
+			const userConfigDir = join(homeDir, ".config", "claude-cmd");
+			await mkdir(userConfigDir, { recursive: true });
+			await writeFile(
+				join(userConfigDir, "config.claude-cmd.json"),
+				JSON.stringify({
+					projectHookTrust: {
+						[workDir]: { allow: true, fingerprint: hooksFingerprint(hooks) },
+					},
+				}),
+			);
+
+			const { result } = await remove();
+			expect(result).toBe(0);
+			expect(await Bun.file(join(workDir, "removed")).text()).toBe(
+				"debug-help\n",
+			);
+
+			// Changed hooks are reviewed again
+			await rm(join(workDir, "removed"));
+			await writeFile(
+				join(workDir, ".claude", "config.claude-cmd.json"),
+				JSON.stringify({
+					hooks: { "post-remove": "echo changed > removed" },
+				}),
+			);
+			const changed = await remove();
+			expect(changed.stderr).toContain("Skipping project hooks");
+			expect(await Bun.file(join(workDir, "removed")).exists()).toBe(false);
+		} finally {
+			await rm(homeDir, { recursive: true, force: true });
+		}
+	});
+
+	it("should remove a whole namespace after listing it", async () => {
+		const homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-remove-")),
+		);
+		try {
+			const commandsDir = join(homeDir, ".claude", "commands");
+			await mkdir(join(commandsDir, "frontend", "react"), { recursive: true });
+			await writeFile(join(commandsDir, "frontend", "button.md"), "# Button\n");
+			await writeFile(
+				join(commandsDir, "frontend", "react", "hooks.md"),
+				"# Hooks\n",
+			);
+			await writeFile(join(commandsDir, "review.md"), "# Review\n");
+			const workDir = join(homeDir, "work");
+			await mkdir(workDir);
+			const env = {
+				HOME: homeDir,
+				CLAUDE_CONFIG_DIR: "",
+				CLAUDE_HOME: "",
+				CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+			};
+
+			const { result, stdout } = await runCli(
+				["remove", "frontend:*", "--yes", "--purge"],
+				workDir,
+				env,
+			);
+
+			expect(result).toBe(0);
+			expect(stdout).toContain("The following 2 commands will be removed");
+			expect(stdout).toContain("frontend:button");
+			expect(stdout).toContain("frontend:react:hooks");
+			expect(stdout).toContain("Removed 2 commands.");
+			expect(await Bun.file(join(commandsDir, "review.md")).exists()).toBe(
+				true,
+			);
+			expect(
+				await Bun.file(join(commandsDir, "frontend", "button.md")).exists(),
+			).toBe(false);
+
+			const none = await runCli(
+				["remove", "--namespace", "frontend", "--yes"],
+				workDir,
+				env,
+			);
+			expect(none.result).toBe(2);
+			expect(none.stdout).toContain(
+				"No installed commands in namespace 'frontend'.",
+			);
+		} finally {
+			await rm(homeDir, { recursive: true, force: true });
+		}
+	});
 });
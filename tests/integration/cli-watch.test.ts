@@ -0,0 +1,62 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import { mkdir, mkdtemp, realpath, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import { runCli } from "../testUtils.ts";
+
+describe("CLI Watch Command Integration", () => {
+	let homeDir: string;
+	let projectDir: string;
+
+	beforeEach(async () => {
+		homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-watch-")),
+		);
+		projectDir = join(homeDir, "project");
+		await mkdir(join(projectDir, ".claude", "commands"), { recursive: true });
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const write = async (filePath: string, content: string) => {
+		await mkdir(dirname(filePath), { recursive: true });
+		await writeFile(filePath, content);
+	};
+	const personalDir = () => join(homeDir, ".claude", "commands");
+
+	const run = (...args: string[]) =>
+		runCli(["watch", ...args], projectDir, {
+			HOME: homeDir,
+			CLAUDE_CONFIG_DIR: "",
+			CLAUDE_HOME: "",
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+		});
+
+	it("should pass when every command file is valid", async () => {
+		await write(
+			join(personalDir(), "frontend", "review.md"),
+			"---\ndescription: Review\n---\n\n# Review\n",
+		);
+
+		const { result, stdout } = await run("--once");
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("Checked 1 personal command file(s)");
+		expect(stdout).toContain("Checked 0 project command file(s)");
+	});
+
+	it("should report invalid frontmatter and fail with --once", async () => {
+		await write(
+			join(projectDir, ".claude", "commands", "deploy.md"),
+			"---\ndescription: Deploy\nallowed-tools: Explode\n---\n\n# Deploy\n",
+		);
+
+		const { result, stderr } = await run("--once", "--from", "project");
+
+		expect(result).toBe(1);
+		expect(stderr).toContain("✗ project: deploy.md:");
+		expect(stderr).toContain("Explode");
+	});
+});
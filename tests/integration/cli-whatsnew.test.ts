@@ -0,0 +1,95 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import { mkdir, mkdtemp, realpath, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import type { Manifest } from "../../src/types/Command.ts";
+import { runCli } from "../testUtils.ts";
+
+const manifest = (
+	...commands: Array<[name: string, description: string]>
+): Manifest => ({
+	version: "1.0.0",
+	updated: "2025-01-01T00:00:00Z",
+	commands: commands.map(([name, description]) => ({
+		name,
+		description,
+		file: `${name}.md`,
+		"allowed-tools": ["Read"],
+	})),
+});
+
+describe("CLI Whatsnew Command Integration", () => {
+	let homeDir: string;
+
+	beforeEach(async () => {
+		homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-whatsnew-")),
+		);
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const cacheFile = (relativePath: string) =>
+		join(homeDir, "cache", "commands", "en", relativePath);
+	const seed = async (
+		relativePath: string,
+		cached: Manifest,
+		timestamp: number,
+	) => {
+		const filePath = cacheFile(relativePath);
+		await mkdir(dirname(filePath), { recursive: true });
+		await writeFile(filePath, JSON.stringify({ manifest: cached, timestamp }));
+	};
+
+	const run = (...globalOptions: string[]) =>
+		runCli([...globalOptions, "whatsnew", "--language", "en"], homeDir, {
+			HOME: homeDir,
+			CLAUDE_CONFIG_DIR: "",
+			CLAUDE_HOME: "",
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+		});
+
+	it("should explain when there is nothing to compare", async () => {
+		await seed("manifest.json", manifest(["review", "Review code"]), 2000);
+
+		const { result, stdout } = await run();
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("No earlier manifest cached for en");
+	});
+
+	it("should list changes since the previous manifest", async () => {
+		await seed(
+			"history/1000.json",
+			manifest(["review", "Review code"], ["lint", "Lint files"]),
+			1000,
+		);
+		await seed(
+			"manifest.json",
+			manifest(["review", "Review a pull request"], ["deploy", "Ship it"]),
+			2000,
+		);
+
+		const { result, stdout } = await run();
+
+		expect(result).toBe(0);
+		expect(stdout).toContain("+ deploy: Ship it");
+		expect(stdout).toContain("~ review");
+		expect(stdout).toContain("- lint: Lint files");
+	});
+
+	it("should print porcelain records", async () => {
+		await seed("history/1000.json", manifest(["lint", "Lint files"]), 1000);
+		await seed("manifest.json", manifest(["deploy", "Ship it"]), 2000);
+
+		const { result, stdout } = await run("--porcelain");
+
+		expect(result).toBe(0);
+		expect(stdout.trim().split("\n")).toEqual([
+			"added\tdeploy\tShip it",
+			"removed\tlint\tLint files",
+		]);
+	});
+});
@@ -0,0 +1,89 @@
+import { afterEach, beforeEach, describe, expect, it } from "bun:test";
+import { mkdir, mkdtemp, realpath, rm, writeFile } from "node:fs/promises";
+import { tmpdir } from "node:os";
+import { dirname, join } from "node:path";
+import { runCli } from "../testUtils.ts";
+
+const COMMAND = "---\ndescription: Review a pull request\n---\n\n# Review\n";
+
+describe("CLI Which Command Integration", () => {
+	let homeDir: string;
+	let projectDir: string;
+
+	beforeEach(async () => {
+		homeDir = await realpath(
+			await mkdtemp(join(tmpdir(), "claude-cmd-which-")),
+		);
+		projectDir = join(homeDir, "project");
+		await mkdir(join(projectDir, ".claude", "commands"), { recursive: true });
+	});
+
+	afterEach(async () => {
+		await rm(homeDir, { recursive: true, force: true });
+	});
+
+	const install = async (commandsDir: string, relativePath: string) => {
+		const filePath = join(commandsDir, relativePath);
+		await mkdir(dirname(filePath), { recursive: true });
+		await writeFile(filePath, COMMAND);
+		return filePath;
+	};
+	const personalDir = () => join(homeDir, ".claude", "commands");
+	const projectCommandsDir = () => join(projectDir, ".claude", "commands");
+
+	const run = (args: string[]) =>
+		runCli(args, projectDir, {
+			HOME: homeDir,
+			CLAUDE_CONFIG_DIR: "",
+			CLAUDE_HOME: "",
+			CLAUDE_CMD_CACHE_DIR: join(homeDir, "cache"),
+		});
+	const runWhich = (...args: string[]) => run(["which", ...args]);
+
+	it("should print the path of a namespaced command", async () => {
+		const filePath = await install(personalDir(), "frontend/review.md");
+
+		const { result, stdout } = await runWhich("frontend:review");
+
+		expect(result).toBe(0);
+		expect(stdout.trim()).toBe(filePath);
+	});
+
+	it("should prefer the project's copy", async () => {
+		await install(personalDir(), "review.md");
+		const projectPath = await install(projectCommandsDir(), "review.md");
+
+		const { stdout } = await runWhich("review");
+
+		expect(stdout.trim()).toBe(projectPath);
+	});
+
+	it("should list every copy with --all", async () => {
+		const personalPath = await install(personalDir(), "review.md");
+		const projectPath = await install(projectCommandsDir(), "review.md");
+
+		const { stdout } = await runWhich("review", "--all");
+
+		expect(stdout.trim().split("\n")).toEqual([projectPath, personalPath]);
+	});
+
+	it("should exit with 1 when the command is not installed", async () => {
+		const { result, stdout, stderr } = await runWhich("missing");
+
+		expect(result).toBe(1);
+		expect(stdout).toBe("");
+		expect(stderr).toContain("missing is not installed");
+	});
+
+	it("should resolve aliases, including their location", async () => {
+		const personalPath = await install(personalDir(), "backend/review.md");
+		await install(projectCommandsDir(), "backend/review.md");
+
+		const set = await run(["alias", "set", "rv=personal:backend:review"]);
+		const { result, stdout } = await runWhich("rv");
+
+		expect(set.result).toBe(0);
+		expect(result).toBe(0);
+		expect(stdout.trim()).toBe(personalPath);
+	});
+});
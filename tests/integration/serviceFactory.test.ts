@@ -3,10 +3,20 @@ import crypto from "node:crypto";
 import { rm, rmdir } from "node:fs/promises";
 import path from "node:path";
 import BunFileService from "../../src/services/BunFileService.js";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import FileSystemRepository from "../../src/services/FileSystemRepository.js";
+import GitRepository from "../../src/services/GitRepository.js";
+import HTTPRepository from "../../src/services/HTTPRepository.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
 import {
+	createDefaultDependencies,
+	createRepositoryForConfig,
+	createServices,
 	getServices,
 	resetServices,
+	setServices,
 } from "../../src/services/serviceFactory.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
 
 describe("serviceFactory integration with ConfigService", () => {
 	let testDir: string;
@@ -150,3 +160,93 @@ describe("serviceFactory integration with ConfigService", () => {
 		);
 	});
 });
+
+describe("serviceFactory dependency injection", () => {
+	afterEach(() => {
+		resetServices();
+	});
+
+	it("should build every service on the injected dependencies", async () => {
+		const fileService = new InMemoryFileService();
+		const services = createServices({
+			fileService,
+			userConfigPath: "/home/test/.config/claude-cmd/config.json",
+			projectConfigPath: "/project/.claude/config.json",
+		});
+
+		await services.userConfigService.setConfig({ preferredLanguage: "fr" });
+		await services.cacheManager.set("fr", {
+			version: "1.0.0",
+			updated: "2025-01-01T00:00:00Z",
+			commands: [],
+		});
+
+		expect(services.fileService).toBe(fileService);
+		expect(
+			await fileService.exists("/home/test/.config/claude-cmd/config.json"),
+		).toBe(true);
+		expect(
+			await fileService.exists(services.cacheManager.getCachePath("fr")),
+		).toBe(true);
+		expect(
+			(await services.configManager.getEffectiveConfig()).preferredLanguage,
+		).toBe("fr");
+	});
+
+	it("should serve an installed container from getServices", () => {
+		const services = createServices({ fileService: new InMemoryFileService() });
+
+		setServices(services);
+
+		expect(getServices()).toBe(services);
+	});
+
+	describe("createRepositoryForConfig", () => {
+		const dependencies = {
+			...createDefaultDependencies(),
+			fileService: new InMemoryFileService(),
+			commandParser: new CommandParser(new NamespaceService()),
+		};
+
+		it("should use the default HTTP repository without a repositoryURL", () => {
+			const repository = createRepositoryForConfig({}, dependencies);
+
+			expect(repository).toBeInstanceOf(HTTPRepository);
+			expect((repository as HTTPRepository).getBaseUrl()).toBe(
+				HTTPRepository.BASE_URL,
+			);
+		});
+
+		it("should root the HTTP repository at an http(s) repositoryURL", () => {
+			const repository = createRepositoryForConfig(
+				{ repositoryURL: "https://example.com/team-commands/" },
+				dependencies,
+			);
+
+			expect((repository as HTTPRepository).getBaseUrl()).toBe(
+				"https://example.com/team-commands",
+			);
+		});
+
+		it("should read file:// URLs in place", () => {
+			const repository = createRepositoryForConfig(
+				{ repositoryURL: "file:///srv/commands" },
+				dependencies,
+			);
+
+			expect(repository).toBeInstanceOf(FileSystemRepository);
+		});
+
+		it("should clone git repositories", () => {
+			const repository = createRepositoryForConfig(
+				{
+					repositoryType: "git",
+					repositoryURL: "git@example.com:team/commands.git",
+				},
+				dependencies,
+			);
+
+			expect(repository).toBeInstanceOf(GitRepository);
+		});
+	});
+});
@@ -0,0 +1,52 @@
+import type IClock from "../../src/interfaces/IClock.ts";
+
+/**
+ * Manually controlled clock for deterministic time-dependent tests
+ *
+ * Time only moves when advance() or set() is called.
+ *
+ * @example
+ * ```typescript
+ * const clock = new FakeClock();
+ * await cacheManager.set("en", manifest);
+ * clock.advance(8 * 24 * 60 * 60 * 1000);
+ * expect(await cacheManager.isExpired("en")).toBe(true);
+ * ```
+ */
+class FakeClock implements IClock {
+	private current: number;
+
+	/**
+	 * @param start - Initial time in milliseconds (default: 2025-01-01T00:00:00Z)
+	 */
+	constructor(start: number = Date.UTC(2025, 0, 1)) {
+		this.current = start;
+	}
+
+	now(): number {
+		return this.current;
+	}
+
+	/**
+	 * Move time forward
+	 *
+	 * @param ms - Milliseconds to advance (must not be negative)
+	 */
+	advance(ms: number): void {
+		if (ms < 0) {
+			throw new Error("FakeClock cannot move backwards; use set()");
+		}
+		this.current += ms;
+	}
+
+	/**
+	 * Jump to an absolute time
+	 *
+	 * @param time - Milliseconds since the Unix epoch
+	 */
+	set(time: number): void {
+		this.current = time;
+	}
+}
+
+export default FakeClock;
@@ -1,11 +1,34 @@
+import { resolve } from "node:path";
 import type IFileService from "../../src/interfaces/IFileService.ts";
 import {
 	FileIOError,
 	FileNotFoundError,
 	type NamespacedFile,
+	type ScanOptions,
 } from "../../src/interfaces/IFileService.ts";
-
-type FileEntry = { type: "file"; content: string };
+import { matchesDirectoryPattern } from "../../src/utils/paths.js";
+
+type FileEntry = {
+	type: "file";
+	content: string;
+	bytes?: Uint8Array;
+	executable?: boolean;
+};
+
+/**
+ * Check whether a path lies in a directory that a scan would prune
+ *
+ * @param directories - Directory names from the scanned directory down
+ */
+function isPruned(
+	directories: readonly string[],
+	{ maxDepth = Number.POSITIVE_INFINITY, ignore = [] }: ScanOptions,
+): boolean {
+	return (
+		directories.length > maxDepth ||
+		directories.some((name) => matchesDirectoryPattern(name, ignore))
+	);
+}
 type DirectoryEntry = { type: "directory" };
 type Entry = FileEntry | DirectoryEntry;
 type FileSystem = Record<string, Entry>;
@@ -63,6 +86,53 @@ class InMemoryFileService implements IFileService {
 		this.fs[filePath] = { type: "file", content };
 	}
 
+	async readBinaryFile(path: string): Promise<Uint8Array> {
+		this.operationHistory.push({ operation: "readBinaryFile", path });
+		const entry = this.fs[path];
+		if (!entry || entry.type !== "file") {
+			throw new FileNotFoundError(path);
+		}
+		return entry.bytes
+			? new Uint8Array(entry.bytes)
+			: new TextEncoder().encode(entry.content);
+	}
+
+	async writeBinaryFile(path: string, data: Uint8Array): Promise<void> {
+		// Reuse writeFile for directory handling, then keep the exact bytes
+		await this.writeFile(path, new TextDecoder().decode(data));
+		this.operationHistory.push({ operation: "writeBinaryFile", path });
+		const filePath = path.endsWith("/") ? path.slice(0, -1) : path;
+		const entry = this.fs[filePath];
+		if (entry?.type === "file") {
+			entry.bytes = new Uint8Array(data);
+		}
+	}
+
+	async writeBinaryStream(
+		path: string,
+		chunks: AsyncIterable<Uint8Array>,
+	): Promise<void> {
+		// Like the real file, what arrived before a failing chunk is kept
+		let data = new Uint8Array(0);
+		await this.writeBinaryFile(path, data);
+		for await (const chunk of chunks) {
+			const next = new Uint8Array(data.length + chunk.length);
+			next.set(data);
+			next.set(chunk, data.length);
+			data = next;
+			await this.writeBinaryFile(path, data);
+		}
+	}
+
+	async makeExecutable(path: string): Promise<void> {
+		this.operationHistory.push({ operation: "makeExecutable", path });
+		const entry = this.fs[path];
+		if (!entry || entry.type !== "file") {
+			throw new FileNotFoundError(path);
+		}
+		entry.executable = true;
+	}
+
 	async exists(path: string): Promise<boolean> {
 		this.operationHistory.push({ operation: "exists", path });
 		// Normalize paths for consistent lookups
@@ -139,6 +209,25 @@ class InMemoryFileService implements IFileService {
 		this.operationHistory.length = 0;
 	}
 
+	async createFile(path: string, content: string): Promise<boolean> {
+		this.operationHistory.push({ operation: "createFile", path, content });
+		const filePath = path.endsWith("/") ? path.slice(0, -1) : path;
+
+		if (this.fs[filePath] || this.fs[`${filePath}/`]) {
+			return false;
+		}
+
+		// Claim the path before awaiting so concurrent callers see it
+		this.fs[filePath] = { type: "file", content };
+		try {
+			await this.writeFile(path, content);
+		} catch (error) {
+			delete this.fs[filePath];
+			throw error;
+		}
+		return true;
+	}
+
 	async deleteFile(path: string): Promise<void> {
 		this.operationHistory.push({ operation: "deleteFile", path });
 		const entry = this.fs[path];
@@ -150,6 +239,40 @@ class InMemoryFileService implements IFileService {
 		delete this.fs[path];
 	}
 
+	async removeEmptyDirectory(path: string): Promise<boolean> {
+		this.operationHistory.push({ operation: "removeEmptyDirectory", path });
+		const dirPath = path.endsWith("/") ? path : `${path}/`;
+
+		if (!(await this.exists(path)) || this.fs[dirPath.slice(0, -1)]) {
+			throw new FileNotFoundError(path);
+		}
+
+		const hasChildren = Object.keys(this.fs).some(
+			(existingPath) =>
+				existingPath.startsWith(dirPath) && existingPath !== dirPath,
+		);
+		if (hasChildren) {
+			return false;
+		}
+
+		delete this.fs[dirPath];
+		return true;
+	}
+
+	async rename(from: string, to: string): Promise<void> {
+		this.operationHistory.push({ operation: "rename", path: from });
+		const entry = this.fs[from];
+
+		if (!entry || entry.type !== "file") {
+			throw new FileNotFoundError(from);
+		}
+
+		// Reuse writeFile for directory handling, then move the entry
+		await this.writeFile(to, entry.content);
+		this.fs[to] = entry;
+		delete this.fs[from];
+	}
+
 	async listFiles(path: string): Promise<string[]> {
 		this.operationHistory.push({ operation: "listFiles", path });
 
@@ -233,7 +356,10 @@ class InMemoryFileService implements IFileService {
 	/**
 	 * List all files recursively in a directory and its subdirectories
 	 */
-	async listFilesRecursive(path: string): Promise<string[]> {
+	async listFilesRecursive(
+		path: string,
+		options: ScanOptions = {},
+	): Promise<string[]> {
 		this.operationHistory.push({ operation: "listFilesRecursive", path });
 
 		// Normalize directory path
@@ -260,7 +386,10 @@ class InMemoryFileService implements IFileService {
 
 				// Only include files, not directories
 				const entry = this.fs[filePath];
-				if (entry?.type === "file") {
+				if (
+					entry?.type === "file" &&
+					!isPruned(relativePath.split("/").slice(0, -1), options)
+				) {
 					files.push(relativePath);
 				}
 			}
@@ -269,6 +398,43 @@ class InMemoryFileService implements IFileService {
 		return files;
 	}
 
+	/**
+	 * List all directories recursively (explicit and implied by file paths)
+	 */
+	async listDirectoriesRecursive(
+		path: string,
+		options: ScanOptions = {},
+	): Promise<string[]> {
+		this.operationHistory.push({ operation: "listDirectoriesRecursive", path });
+
+		const dirPath = path.endsWith("/") ? path : `${path}/`;
+		if (!(await this.exists(path))) {
+			throw new FileNotFoundError(path);
+		}
+
+		const directories = new Set<string>();
+		for (const existingPath in this.fs) {
+			if (!existingPath.startsWith(dirPath) || existingPath === dirPath) {
+				continue;
+			}
+			// Every proper prefix of a child path is a directory
+			const segments = existingPath
+				.substring(dirPath.length)
+				.split("/")
+				.filter(Boolean);
+			const isDirectory = this.fs[existingPath]?.type === "directory";
+			const depth = isDirectory ? segments.length : segments.length - 1;
+			for (let i = 1; i <= depth; i++) {
+				if (!isPruned(segments.slice(0, i - 1), options)) {
+					directories.add(segments.slice(0, i).join("/"));
+				}
+			}
+		}
+
+		// Sorting puts parents before their children
+		return Array.from(directories).sort();
+	}
+
 	/**
 	 * Clear all files for clean test state
 	 */
@@ -286,6 +452,12 @@ class InMemoryFileService implements IFileService {
 	/**
 	 * Check if a path is writable (simplified for testing - always returns true for existing paths)
 	 */
+	async realPath(path: string): Promise<string> {
+		this.operationHistory.push({ operation: "realPath", path });
+		// No links in memory: resolving is purely lexical
+		return resolve(path);
+	}
+
 	async isWritable(path: string): Promise<boolean> {
 		this.operationHistory.push({ operation: "isWritable", path });
 
@@ -308,7 +480,7 @@ class InMemoryFileService implements IFileService {
 	 */
 	async scanNamespaceHierarchy(
 		basePath: string,
-		maxDepth = 10,
+		{ maxDepth = 10, ignore = [] }: ScanOptions = {},
 	): Promise<NamespacedFile[]> {
 		this.operationHistory.push({
 			operation: "scanNamespaceHierarchy",
@@ -316,7 +488,10 @@ class InMemoryFileService implements IFileService {
 		});
 
 		const files: NamespacedFile[] = [];
-		await this.scanDirectoryRecursive(basePath, basePath, files, 0, maxDepth);
+		await this.scanDirectoryRecursive(basePath, basePath, files, 0, {
+			maxDepth,
+			ignore,
+		});
 		return files;
 	}
 
@@ -328,9 +503,9 @@ class InMemoryFileService implements IFileService {
 		currentPath: string,
 		files: NamespacedFile[],
 		currentDepth: number,
-		maxDepth: number,
+		limits: Required<ScanOptions>,
 	): Promise<void> {
-		if (currentDepth > maxDepth) {
+		if (currentDepth > limits.maxDepth) {
 			return;
 		}
 
@@ -386,8 +561,14 @@ class InMemoryFileService implements IFileService {
 			}
 		}
 
-		// Recursively scan subdirectories
+		// Recursively scan subdirectories, pruning before reading them
+		if (currentDepth >= limits.maxDepth) {
+			return;
+		}
 		for (const childName of directChildren) {
+			if (matchesDirectoryPattern(childName, limits.ignore)) {
+				continue;
+			}
 			const childPath = dirPath + childName;
 			const entry = this.fs[childPath];
 
@@ -403,7 +584,7 @@ class InMemoryFileService implements IFileService {
 					childPath,
 					files,
 					currentDepth + 1,
-					maxDepth,
+					limits,
 				);
 			}
 		}
@@ -0,0 +1,128 @@
+import type IGitClient from "../../src/interfaces/IGitClient.ts";
+import {
+	type GitCheckoutOptions,
+	GitError,
+} from "../../src/interfaces/IGitClient.ts";
+import type InMemoryFileService from "./InMemoryFileService.ts";
+
+/**
+ * In-memory git client for testing
+ *
+ * Simulates a remote repository as a map of refs to file trees. clone() and
+ * update() copy the tree for the requested ref into the target directory of
+ * an InMemoryFileService, so repository code can be tested without git or a
+ * network connection.
+ *
+ * @example
+ * ```typescript
+ * const fileService = new InMemoryFileService();
+ * const git = new InMemoryGitClient(fileService, {
+ *   "commands/en/hello.md": "---\ndescription: Hello\n---\nHi",
+ * });
+ * await git.clone("git@example.com:acme/commands.git", "/cache/git/abc");
+ * ```
+ */
+export default class InMemoryGitClient implements IGitClient {
+	private readonly refs = new Map<string, Record<string, string>>();
+	private readonly checkouts = new Map<string, string>();
+	private failure: string | null = null;
+	private readonly history: Array<{
+		operation: "clone" | "update";
+		target: string;
+		ref?: string;
+	}> = [];
+
+	/**
+	 * @param fileService - File service that receives checked-out files
+	 * @param files - Files of the default branch, relative to the repository root
+	 */
+	constructor(
+		private readonly fileService: InMemoryFileService,
+		files: Record<string, string> = {},
+	) {
+		this.refs.set("HEAD", files);
+	}
+
+	/**
+	 * Replace the files of a ref (defaults to HEAD) to simulate a new commit
+	 */
+	setRemoteFiles(files: Record<string, string>, ref = "HEAD"): void {
+		this.refs.set(ref, files);
+	}
+
+	/**
+	 * Make subsequent clone/update calls fail with a GitError
+	 */
+	setFailure(message: string | null): void {
+		this.failure = message;
+	}
+
+	getHistory() {
+		return [...this.history];
+	}
+
+	async clone(
+		url: string,
+		directory: string,
+		options?: GitCheckoutOptions,
+	): Promise<void> {
+		this.history.push({ operation: "clone", target: url, ref: options?.ref });
+		if (this.failure) {
+			throw new GitError(this.failure, "clone", this.failure);
+		}
+		if (await this.fileService.exists(directory)) {
+			throw new GitError(
+				`destination path '${directory}' already exists`,
+				"clone",
+			);
+		}
+		this.checkout(directory, options?.ref);
+	}
+
+	async update(directory: string, options?: GitCheckoutOptions): Promise<void> {
+		this.history.push({
+			operation: "update",
+			target: directory,
+			ref: options?.ref,
+		});
+		if (this.failure) {
+			throw new GitError(this.failure, "fetch", this.failure);
+		}
+		if (!this.checkouts.has(directory)) {
+			throw new GitError(`not a git repository: ${directory}`, "fetch");
+		}
+		this.checkout(directory, options?.ref);
+	}
+
+	async getHeadCommit(directory: string): Promise<string> {
+		const ref = this.checkouts.get(directory);
+		if (!ref) {
+			throw new GitError(`not a git repository: ${directory}`, "rev-parse");
+		}
+		return `commit-${ref}`;
+	}
+
+	private checkout(directory: string, ref = "HEAD"): void {
+		const files = this.refs.get(ref);
+		if (!files) {
+			throw new GitError(
+				`Remote branch ${ref} not found in upstream origin`,
+				"checkout",
+			);
+		}
+
+		// Remove files from the previous checkout before writing the new tree
+		const prefix = `${directory}/`;
+		for (const path of Object.keys(this.fileService.fs)) {
+			if (path.startsWith(prefix)) {
+				delete this.fileService.fs[path];
+			}
+		}
+
+		this.fileService.setFile(`${directory}/.git/HEAD`, ref);
+		for (const [path, content] of Object.entries(files)) {
+			this.fileService.setFile(`${directory}/${path}`, content);
+		}
+		this.checkouts.set(directory, ref);
+	}
+}
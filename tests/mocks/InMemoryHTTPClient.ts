@@ -2,9 +2,11 @@ import type IHTTPClient from "../../src/interfaces/IHTTPClient.ts";
 import type {
 	HTTPOptions,
 	HTTPResponse,
+	HTTPStreamResponse,
 } from "../../src/interfaces/IHTTPClient.ts";
 import {
 	HTTPNetworkError,
+	HTTPResponseTooLargeError,
 	HTTPStatusError,
 	HTTPTimeoutError,
 } from "../../src/interfaces/IHTTPClient.ts";
@@ -50,6 +52,10 @@ class InMemoryHTTPClient implements IHTTPClient {
 		matcher: URLMatcher;
 		response: HTTPResponse | Error;
 	}>;
+	/** Size in bytes of the chunks stream() splits bodies into */
+	streamChunkSize = 64;
+	/** How stream() turns bodies into bytes; "latin1" for binary bodies */
+	streamEncoding: BufferEncoding = "utf8";
 	/** History of all requests made to this client instance */
 	private readonly requestHistory: Array<{
 		url: string;
@@ -178,8 +184,11 @@ class InMemoryHTTPClient implements IHTTPClient {
 		// Extract timeout with sensible default
 		const timeout = options?.timeout ?? 5000;
 
-		// Simulate timeout behavior for slow endpoint
-		if (url === "https://api.example.com/slow" && timeout < 1000) {
+		// Simulate timeout behavior for slow endpoint and aborted requests
+		if (
+			(url === "https://api.example.com/slow" && timeout < 1000) ||
+			options?.signal?.aborted
+		) {
 			throw new HTTPTimeoutError(url, timeout);
 		}
 
@@ -191,7 +200,7 @@ class InMemoryHTTPClient implements IHTTPClient {
 			}
 			// Simulate minimal network delay for realism
 			await new Promise((resolve) => setTimeout(resolve, 1));
-			return exactResponse;
+			return this.limitSize(exactResponse, options);
 		}
 
 		// Then check pattern matches
@@ -202,7 +211,7 @@ class InMemoryHTTPClient implements IHTTPClient {
 				}
 				// Simulate minimal network delay for realism
 				await new Promise((resolve) => setTimeout(resolve, 1));
-				return response;
+				return this.limitSize(response, options);
 			}
 		}
 
@@ -210,6 +219,42 @@ class InMemoryHTTPClient implements IHTTPClient {
 		throw new HTTPStatusError(url, 404, "Not Found");
 	}
 
+	/**
+	 * Perform an HTTP GET request whose body is delivered in chunks of
+	 * streamChunkSize bytes
+	 *
+	 * Responses and errors are those of get(), and the request is recorded
+	 * in the same history.
+	 */
+	async stream(
+		url: string,
+		options?: HTTPOptions,
+	): Promise<HTTPStreamResponse> {
+		const { body, ...response } = await this.get(url, options);
+		const bytes = Buffer.from(body, this.streamEncoding);
+		const chunkSize = this.streamChunkSize;
+		async function* chunks(): AsyncGenerator<Uint8Array> {
+			for (let offset = 0; offset < bytes.length; offset += chunkSize) {
+				yield bytes.subarray(offset, offset + chunkSize);
+			}
+		}
+		return { ...response, body: chunks() };
+	}
+
+	/**
+	 * Enforce options.maxBytes like BunHTTPClient does
+	 */
+	private limitSize(
+		response: HTTPResponse,
+		options?: HTTPOptions,
+	): HTTPResponse {
+		const maxBytes = options?.maxBytes;
+		if (maxBytes !== undefined && Buffer.byteLength(response.body) > maxBytes) {
+			throw new HTTPResponseTooLargeError(response.url, maxBytes);
+		}
+		return response;
+	}
+
 	/**
 	 * Helper method to check if a URL matches a given pattern
 	 */
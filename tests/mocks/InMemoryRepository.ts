@@ -10,6 +10,7 @@ import {
 	CommandNotFoundError,
 	ManifestError,
 } from "../../src/types/Command.js";
+import type { RepositoryAbout } from "../../src/types/Repository.js";
 
 /**
  * Request history entry for tracking Repository method calls and dependency usage
@@ -60,6 +61,8 @@ class InMemoryRepository implements IRepository {
 	private readonly manifests: Map<string, Manifest | Error>;
 	/** Pre-configured command content mapped by language:commandName */
 	private readonly commands: Map<string, string | Error>;
+	/** Pre-configured about.json metadata mapped by language */
+	private readonly abouts: Map<string, RepositoryAbout> = new Map();
 	/** History of all requests made to this repository instance (capped at 1000 entries) */
 	private readonly requestHistory: Array<RepositoryRequestHistoryEntry>;
 	/** Maximum number of request history entries to maintain */
@@ -565,6 +568,23 @@ class InMemoryRepository implements IRepository {
 		return languages;
 	}
 
+	/**
+	 * Get the metadata configured with setAbout()
+	 */
+	async getAbout(language: string): Promise<RepositoryAbout | null> {
+		return this.abouts.get(language) ?? null;
+	}
+
+	/**
+	 * Configure repository metadata for a language
+	 *
+	 * @param language - The language code to map
+	 * @param about - Metadata returned by getAbout()
+	 */
+	setAbout(language: string, about: RepositoryAbout): void {
+		this.abouts.set(language, about);
+	}
+
 	/**
 	 * Add a custom manifest for dynamic testing scenarios
 	 *
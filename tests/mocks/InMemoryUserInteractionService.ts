@@ -1,12 +1,29 @@
 import type IUserInteractionService from "../../src/interfaces/IUserInteractionService.js";
-import type { ConfirmationOptions } from "../../src/interfaces/IUserInteractionService.js";
+import type {
+	ChoiceOptions,
+	ConfirmationOptions,
+	TextPromptOptions,
+} from "../../src/interfaces/IUserInteractionService.js";
 
-type InteractionLog = {
-	type: "confirmation";
-	options: ConfirmationOptions;
-	response: boolean;
-	timestamp: Date;
-};
+type InteractionLog =
+	| {
+			type: "confirmation";
+			options: ConfirmationOptions;
+			response: boolean;
+			timestamp: Date;
+	  }
+	| {
+			type: "choice";
+			options: ChoiceOptions;
+			response: string | undefined;
+			timestamp: Date;
+	  }
+	| {
+			type: "text";
+			options: TextPromptOptions;
+			response: string;
+			timestamp: Date;
+	  };
 
 /**
  * In-memory implementation of IUserInteractionService for testing
@@ -21,6 +38,8 @@ export default class InMemoryUserInteractionService
 	private interactionHistory: InteractionLog[] = [];
 	private preConfiguredResponses: Map<string, boolean> = new Map();
 	private defaultResponse?: boolean;
+	private choiceResponses: string[] = [];
+	private textResponses: string[] = [];
 
 	/**
 	 * Set whether the service is in --yes mode (skips prompts with defaults)
@@ -44,6 +63,30 @@ export default class InMemoryUserInteractionService
 		this.defaultResponse = response;
 	}
 
+	/**
+	 * Queue answers for choice prompts, consumed in order
+	 * Once the queue is empty, choice prompts behave as if no terminal is
+	 * available and return undefined
+	 */
+	setChoiceResponses(...responses: string[]): void {
+		this.choiceResponses = [...responses];
+	}
+
+	/**
+	 * Queue answers for text prompts, consumed in order
+	 * Empty answers and an empty queue return the prompt's default
+	 */
+	setTextResponses(...responses: string[]): void {
+		this.textResponses = [...responses];
+	}
+
+	/**
+	 * Get all recorded interactions, oldest first
+	 */
+	getInteractionHistory(): readonly InteractionLog[] {
+		return this.interactionHistory;
+	}
+
 	/**
 	 * Display a confirmation prompt (y/N)
 	 */
@@ -68,6 +111,38 @@ export default class InMemoryUserInteractionService
 		return response;
 	}
 
+	/**
+	 * Pick the next queued choice response
+	 */
+	async chooseOption<T extends string>(
+		options: ChoiceOptions<T>,
+	): Promise<T | undefined> {
+		const next = this.choiceResponses.shift();
+		const response = options.choices.find((c) => c.value === next)?.value;
+		this.interactionHistory.push({
+			type: "choice",
+			options,
+			response,
+			timestamp: new Date(),
+		});
+		return response;
+	}
+
+	/**
+	 * Return the next queued text response, or the default
+	 */
+	async askText(options: TextPromptOptions): Promise<string> {
+		const next = this.yesMode ? undefined : this.textResponses.shift();
+		const response = next?.trim() || options.defaultValue;
+		this.interactionHistory.push({
+			type: "text",
+			options,
+			response,
+			timestamp: new Date(),
+		});
+		return response;
+	}
+
 	/**
 	 * Private helper to log interactions
 	 */
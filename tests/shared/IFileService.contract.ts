@@ -1,4 +1,5 @@
 import { afterEach, beforeEach, describe, expect, test } from "bun:test";
+import { isAbsolute, join } from "node:path";
 import type IFileService from "../../src/interfaces/IFileService.ts";
 import { FileNotFoundError } from "../../src/interfaces/IFileService.ts";
 
@@ -99,6 +100,63 @@ export function createFileServiceContractTests(
 			});
 		});
 
+		describe("binary file operations", () => {
+			test("should write bytes and read them back unchanged", async () => {
+				const filePath = "archive.bin";
+				const data = new Uint8Array([0, 1, 2, 127, 128, 254, 255]);
+
+				await fileService.writeBinaryFile(filePath, data);
+				const result = await fileService.readBinaryFile(filePath);
+
+				expect(Array.from(result)).toEqual(Array.from(data));
+			});
+
+			test("should read text files as UTF-8 bytes", async () => {
+				const filePath = "text.md";
+				await fileService.writeFile(filePath, "héllo");
+
+				const result = await fileService.readBinaryFile(filePath);
+
+				expect(new TextDecoder().decode(result)).toBe("héllo");
+			});
+
+			test("should throw FileNotFoundError when reading missing binary file", async () => {
+				await expect(
+					fileService.readBinaryFile("missing.bin"),
+				).rejects.toThrow(FileNotFoundError);
+			});
+
+			test("should write streamed chunks in order", async () => {
+				async function* chunks() {
+					yield new Uint8Array([0, 1, 2]);
+					yield new Uint8Array([254, 255]);
+				}
+
+				await fileService.writeBinaryStream("nested/stream.bin", chunks());
+				const result = await fileService.readBinaryFile("nested/stream.bin");
+
+				expect(Array.from(result)).toEqual([0, 1, 2, 254, 255]);
+			});
+
+			test("should pass on errors of the streamed chunks", async () => {
+				const failure = new Error("connection reset");
+				async function* chunks() {
+					yield new Uint8Array([1]);
+					throw failure;
+				}
+
+				await expect(
+					fileService.writeBinaryStream("partial.bin", chunks()),
+				).rejects.toBe(failure);
+			});
+
+			test("should throw FileNotFoundError when making a missing file executable", async () => {
+				await expect(fileService.makeExecutable("missing")).rejects.toThrow(
+					FileNotFoundError,
+				);
+			});
+		});
+
 		describe("file existence checks", () => {
 			test("should confirm a file exists after writing it", async () => {
 				const path = "exists-test.txt";
@@ -123,6 +181,71 @@ export function createFileServiceContractTests(
 			});
 		});
 
+		describe("file renaming", () => {
+			test("should move content to the new path", async () => {
+				await fileService.writeFile("rename-from.txt", "content");
+
+				await fileService.rename("rename-from.txt", "rename-to.txt");
+
+				expect(await fileService.exists("rename-from.txt")).toBe(false);
+				expect(await fileService.readFile("rename-to.txt")).toBe("content");
+			});
+
+			test("should replace an existing destination", async () => {
+				await fileService.writeFile("rename-new.txt", "new");
+				await fileService.writeFile("rename-old.txt", "old");
+
+				await fileService.rename("rename-new.txt", "rename-old.txt");
+
+				expect(await fileService.readFile("rename-old.txt")).toBe("new");
+			});
+
+			test("should throw FileNotFoundError when the source is missing", async () => {
+				await expect(
+					fileService.rename("non-existent.txt", "target.txt"),
+				).rejects.toThrow(FileNotFoundError);
+			});
+		});
+
+		describe("exclusive file creation", () => {
+			test("should create a file that does not exist", async () => {
+				expect(await fileService.createFile("exclusive.txt", "first")).toBe(
+					true,
+				);
+				expect(await fileService.readFile("exclusive.txt")).toBe("first");
+			});
+
+			test("should not overwrite an existing file", async () => {
+				await fileService.writeFile("exclusive-existing.txt", "original");
+
+				expect(
+					await fileService.createFile("exclusive-existing.txt", "second"),
+				).toBe(false);
+				expect(await fileService.readFile("exclusive-existing.txt")).toBe(
+					"original",
+				);
+			});
+
+			test("should let exactly one concurrent caller win", async () => {
+				const results = await Promise.all(
+					Array.from({ length: 8 }, (_, index) =>
+						fileService.createFile("exclusive-race.txt", String(index)),
+					),
+				);
+
+				expect(results.filter(Boolean)).toHaveLength(1);
+			});
+
+			test("should create parent directories", async () => {
+				expect(
+					await fileService.createFile("exclusive-dir/nested.txt", "x"),
+				).toBe(true);
+				expect(await fileService.exists("exclusive-dir/nested.txt")).toBe(
+					true,
+				);
+			});
+		});
+
 		describe("directory operations", () => {
 			test("should create directories", async () => {
 				const dirPath = "test-dir";
@@ -162,6 +285,29 @@ export function createFileServiceContractTests(
 				const readContent = await fileService.readFile(filePath);
 				expect(readContent).toBe(content);
 			});
+
+			test("should remove empty directories", async () => {
+				await fileService.mkdir("removable/empty");
+
+				expect(await fileService.removeEmptyDirectory("removable/empty")).toBe(
+					true,
+				);
+				expect(await fileService.exists("removable/empty")).toBe(false);
+				expect(await fileService.exists("removable")).toBe(true);
+			});
+
+			test("should keep directories that have content", async () => {
+				await fileService.writeFile("kept/file.txt", "content");
+
+				expect(await fileService.removeEmptyDirectory("kept")).toBe(false);
+				expect(await fileService.readFile("kept/file.txt")).toBe("content");
+			});
+
+			test("should throw FileNotFoundError when removing a missing directory", async () => {
+				await expect(
+					fileService.removeEmptyDirectory("missing-dir"),
+				).rejects.toThrow(FileNotFoundError);
+			});
 		});
 
 		describe("directory listing operations", () => {
@@ -218,6 +364,19 @@ export function createFileServiceContractTests(
 
 				expect(files).toEqual([]);
 			});
+
+			test("should list directories recursively, including empty ones", async () => {
+				const dirPath = "dirs-recursive";
+				await fileService.writeFile(`${dirPath}/a/file.txt`, "content");
+				await fileService.mkdir(`${dirPath}/a/empty`);
+				await fileService.mkdir(`${dirPath}/b`);
+
+				const directories = await fileService.listDirectoriesRecursive(dirPath);
+
+				expect(
+					directories.map((dir) => dir.replace(/\\/g, "/")).sort(),
+				).toEqual(["a", "a/empty", "b"]);
+			});
 		});
 
 		describe("file deletion", () => {
@@ -248,6 +407,19 @@ export function createFileServiceContractTests(
 			});
 		});
 
+		describe("link resolution", () => {
+			test("should resolve paths that do not exist yet", async () => {
+				await fileService.mkdir("real-dir");
+
+				const resolved = await fileService.realPath("real-dir/missing/cmd.md");
+
+				expect(isAbsolute(resolved)).toBe(true);
+				expect(resolved.endsWith(join("real-dir", "missing", "cmd.md"))).toBe(
+					true,
+				);
+			});
+		});
+
 		describe("error handling", () => {
 			test("should throw FileNotFoundError when reading non-existent file", async () => {
 				await expect(fileService.readFile("non-existent.txt")).rejects.toThrow(
@@ -453,13 +625,36 @@ export function createFileServiceContractTests(
 
 				const namespacedFiles = await fileService.scanNamespaceHierarchy(
 					basePath,
-					2,
+					{ maxDepth: 2 },
 				);
 
 				expect(namespacedFiles).toHaveLength(1);
 				expect(namespacedFiles[0]?.fileName).toBe("shallow.md");
 			});
 
+			test("should skip directories matching ignore patterns", async () => {
+				const basePath = "commands";
+				await fileService.writeFile("commands/kept.md", "# Kept");
+				await fileService.writeFile(
+					"commands/node_modules/pkg/readme.md",
+					"# Vendored",
+				);
+				await fileService.writeFile("commands/.git/notes.md", "# Hidden");
+				await fileService.writeFile(
+					"commands/frontend/component.md",
+					"# Component",
+				);
+
+				const namespacedFiles = await fileService.scanNamespaceHierarchy(
+					basePath,
+					{ ignore: ["node_modules", ".*"] },
+				);
+
+				expect(
+					namespacedFiles.map((file) => file.relativePath).sort(),
+				).toEqual(["frontend/component.md", "kept.md"]);
+			});
+
 			test("should handle empty directory in namespace scanning", async () => {
 				const basePath = "empty-commands";
 				await fileService.mkdir(basePath);
@@ -138,6 +138,33 @@ export function runHttpClientContractTests(
 			});
 		});
 
+		describe("streamed GET requests", () => {
+			test("should deliver the same body as get()", async () => {
+				const url = context.baseUrl
+					? `${context.baseUrl}/json`
+					: "https://api.example.com/json";
+				const expected = (await httpClient.get(url)).body;
+
+				const response = await httpClient.stream(url);
+				const chunks: Uint8Array[] = [];
+				for await (const chunk of response.body) {
+					chunks.push(chunk);
+				}
+
+				expect(response.status).toBe(200);
+				expect(response.url).toBe(url);
+				expect(Buffer.concat(chunks).toString("utf8")).toBe(expected);
+			});
+
+			test("should throw HTTPStatusError before reading the body", () => {
+				const url = context.baseUrl
+					? `${context.baseUrl}/status/404`
+					: "https://api.example.com/not-found";
+
+				expect(httpClient.stream(url)).rejects.toThrow(HTTPStatusError);
+			});
+		});
+
 		describe("error handling", () => {
 			test("should throw HTTPTimeoutError on timeout", () => {
 				const url = context.baseUrl
@@ -149,6 +176,16 @@ export function runHttpClientContractTests(
 				);
 			});
 
+			test("should throw HTTPTimeoutError when the signal aborts", () => {
+				const url = context.baseUrl
+					? `${context.baseUrl}/delay/10`
+					: "https://api.example.com/slow";
+
+				expect(
+					httpClient.get(url, { signal: AbortSignal.abort() }),
+				).rejects.toThrow(HTTPTimeoutError);
+			});
+
 			test("should throw HTTPNetworkError on network failure", async () => {
 				const invalidUrl =
 					"https://invalid-domain-that-does-not-exist-12345.com";
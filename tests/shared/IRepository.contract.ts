@@ -301,6 +301,21 @@ export function runRepositoryContractTests(
 			});
 		});
 
+		describe("repository metadata", () => {
+			test("should resolve getAbout to metadata or null", async () => {
+				const about = await repository.getAbout("en");
+
+				if (about !== null) {
+					expect(typeof about).toBe("object");
+					for (const field of ["title", "description", "homepage"] as const) {
+						if (about[field] !== undefined) {
+							expect(typeof about[field]).toBe("string");
+						}
+					}
+				}
+			});
+		});
+
 		describe("cache configuration handling", () => {
 			test("should accept custom cache configuration", async () => {
 				// This test verifies repository can work with custom cache config
@@ -165,6 +165,21 @@ export function runUserInteractionServiceContractTests(
 			});
 		});
 
+		describe("text prompts", () => {
+			test("should answer with the default in --yes mode", async () => {
+				service.setYesMode(true);
+
+				const answer = await service.askText({
+					message: "Language",
+					defaultValue: "fr",
+				});
+
+				expect(answer).toBe("fr");
+
+				service.setYesMode(false);
+			});
+		});
+
 		describe("error handling and edge cases", () => {
 			test("should handle very long messages", async () => {
 				const longMessage = "A".repeat(1000);
@@ -1,7 +1,11 @@
 import { join } from "node:path";
 import { spawn } from "bun";
 
-export async function runCli(args?: string[], tempDir?: string) {
+export async function runCli(
+	args?: string[],
+	tempDir?: string,
+	env?: Record<string, string>,
+) {
 	const cliPath = join(import.meta.dir, "../index.ts");
 
 	const spawnOptions: Bun.SpawnOptions.OptionsObject<"ignore", "pipe", "pipe"> =
@@ -12,6 +16,9 @@ export async function runCli(args?: string[], tempDir?: string) {
 	if (tempDir) {
 		spawnOptions.cwd = tempDir;
 	}
+	if (env) {
+		spawnOptions.env = { ...process.env, ...env };
+	}
 
 	const cliCmd = ["bun", cliPath];
 	if (args) {
@@ -0,0 +1,143 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import type IHTTPClient from "../../src/interfaces/IHTTPClient.js";
+import {
+	HTTPRateLimitError,
+	HTTPStatusError,
+	type HTTPResponse,
+	type HTTPStreamResponse,
+} from "../../src/interfaces/IHTTPClient.js";
+import {
+	BackoffHTTPClient,
+	DEFAULT_BACKOFF_MS,
+	MAX_BACKOFF_MS,
+} from "../../src/services/BackoffHTTPClient.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+/**
+ * Client that answers with queued outcomes and counts requests
+ */
+class ScriptedClient implements IHTTPClient {
+	readonly outcomes: Array<Error | undefined> = [];
+	requests = 0;
+
+	async get(url: string): Promise<HTTPResponse> {
+		this.requests++;
+		const outcome = this.outcomes.shift();
+		if (outcome) {
+			throw outcome;
+		}
+		return { status: 200, statusText: "OK", headers: {}, body: "ok", url };
+	}
+
+	async stream(url: string): Promise<HTTPStreamResponse> {
+		const { body, ...response } = await this.get(url);
+		async function* chunks() {
+			yield Buffer.from(body);
+		}
+		return { ...response, body: chunks() };
+	}
+}
+
+describe("BackoffHTTPClient", () => {
+	const url = "https://raw.example.com/commands/en/manifest.json";
+	const statePath = "/cache/backoff.json";
+	let clock: FakeClock;
+	let fileService: InMemoryFileService;
+	let inner: ScriptedClient;
+
+	beforeEach(() => {
+		clock = new FakeClock();
+		fileService = new InMemoryFileService();
+		inner = new ScriptedClient();
+	});
+
+	const client = () =>
+		new BackoffHTTPClient(inner, fileService, statePath, clock);
+	const limited = (retryAt?: number) =>
+		new HTTPRateLimitError(url, 429, "Too Many Requests", retryAt);
+
+	test("should pass successful requests through", async () => {
+		const response = await client().get(url);
+
+		expect(response.body).toBe("ok");
+		expect(await fileService.exists(statePath)).toBe(false);
+	});
+
+	test("should not hit the server again until Retry-After", async () => {
+		inner.outcomes.push(limited(clock.now() + 30000));
+		const backoff = client();
+		await expect(backoff.get(url)).rejects.toThrow(HTTPRateLimitError);
+
+		clock.advance(10000);
+		const error = await backoff.get(url).catch((e) => e);
+
+		expect(error).toBeInstanceOf(HTTPRateLimitError);
+		expect(error.retryAt).toBe(clock.now() + 20000);
+		expect(inner.requests).toBe(1);
+	});
+
+	test("should remember the backoff across instances", async () => {
+		inner.outcomes.push(limited(clock.now() + 30000));
+		await client().get(url).catch(() => {});
+
+		await expect(client().get(url)).rejects.toThrow(HTTPRateLimitError);
+		expect(inner.requests).toBe(1);
+	});
+
+	test("should retry once the window has passed and clear it", async () => {
+		inner.outcomes.push(limited(clock.now() + 30000));
+		await client().get(url).catch(() => {});
+
+		clock.advance(30000);
+		await client().get(url);
+
+		expect(inner.requests).toBe(2);
+		expect(JSON.parse(await fileService.readFile(statePath))).toEqual({});
+	});
+
+	test("should apply a default wait when the server gives none", async () => {
+		inner.outcomes.push(limited());
+
+		const error = await client().get(url).catch((e) => e);
+
+		expect(error.retryAt).toBe(clock.now() + DEFAULT_BACKOFF_MS);
+	});
+
+	test("should cap excessive waits", async () => {
+		inner.outcomes.push(limited(clock.now() + 24 * MAX_BACKOFF_MS));
+
+		const error = await client().get(url).catch((e) => e);
+
+		expect(error.retryAt).toBe(clock.now() + MAX_BACKOFF_MS);
+	});
+
+	test("should only back off the rate-limited host", async () => {
+		inner.outcomes.push(limited(clock.now() + 30000));
+		const backoff = client();
+		await backoff.get(url).catch(() => {});
+
+		await backoff.get("https://other.example.com/manifest.json");
+
+		expect(inner.requests).toBe(2);
+	});
+
+	test("should back off streamed requests too", async () => {
+		inner.outcomes.push(limited(clock.now() + 30000));
+		const backoff = client();
+		await expect(backoff.stream(url)).rejects.toThrow(HTTPRateLimitError);
+
+		await expect(backoff.stream(url)).rejects.toThrow(HTTPRateLimitError);
+		expect(inner.requests).toBe(1);
+	});
+
+	test("should not back off for other errors", async () => {
+		inner.outcomes.push(new HTTPStatusError(url, 500, "Server Error"));
+		const backoff = client();
+		await expect(backoff.get(url)).rejects.toThrow(HTTPStatusError);
+
+		await backoff.get(url);
+
+		expect(inner.requests).toBe(2);
+	});
+});
@@ -0,0 +1,212 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	BUNDLE_FORMAT_VERSION,
+	BundleError,
+	BundleService,
+} from "../../src/services/BundleService.js";
+import { CacheManager } from "../../src/services/CacheManager.js";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import { FallbackRepository } from "../../src/services/FallbackRepository.js";
+import FileSystemRepository from "../../src/services/FileSystemRepository.js";
+import { CURRENT_MANIFEST_SCHEMA } from "../../src/services/ManifestMigrations.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
+import {
+	CommandNotFoundError,
+	ManifestError,
+} from "../../src/types/Command.js";
+import { createTarGz, type TarEntry } from "../../src/utils/tar.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const MANIFEST = {
+	version: "1.0.0",
+	updated: "2025-01-01T00:00:00Z",
+	commands: [
+		{
+			name: "debug-help",
+			description: "Help with debugging",
+			file: "debug-help.md",
+			"allowed-tools": ["Read"],
+		},
+		{
+			name: "frontend:component",
+			description: "Create a component",
+			file: "frontend/component.md",
+			"allowed-tools": ["Write"],
+		},
+	],
+};
+
+describe("BundleService", () => {
+	let fileService: InMemoryFileService;
+	let cacheManager: CacheManager;
+	let source: FileSystemRepository;
+	let bundleService: BundleService;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService({
+			"/source/en/manifest.json": JSON.stringify(MANIFEST),
+			"/source/en/debug-help.md": "Debug body",
+			"/source/en/frontend/component.md": "Component body",
+		});
+		cacheManager = new CacheManager(fileService, "/cache/commands");
+		source = new FileSystemRepository(
+			fileService,
+			new CommandParser(new NamespaceService()),
+			"/source",
+		);
+		bundleService = new BundleService(
+			source,
+			fileService,
+			cacheManager,
+			"/bundles",
+		);
+	});
+
+	async function writeBundle(entries: TarEntry[]): Promise<string> {
+		await fileService.writeBinaryFile(
+			"/tmp/bundle.tar.gz",
+			createTarGz(entries),
+		);
+		return "/tmp/bundle.tar.gz";
+	}
+
+	function metadata(language = "en") {
+		return JSON.stringify({
+			format: BUNDLE_FORMAT_VERSION,
+			language,
+			createdAt: "2025-01-01T00:00:00Z",
+			commandCount: 1,
+		});
+	}
+
+	test("should round-trip a language through export and import", async () => {
+		const exported = await bundleService.exportBundle(
+			"en",
+			"/tmp/en.tar.gz",
+		);
+		expect(exported).toEqual({
+			language: "en",
+			commandCount: 2,
+			path: "/tmp/en.tar.gz",
+		});
+
+		const imported = await bundleService.importBundle("/tmp/en.tar.gz");
+
+		expect(imported.path).toBe("/bundles/commands/en");
+		expect(
+			await fileService.readFile("/bundles/commands/en/debug-help.md"),
+		).toBe("Debug body");
+		expect(
+			await fileService.readFile("/bundles/commands/en/frontend/component.md"),
+		).toBe("Component body");
+		expect(await cacheManager.get("en")).toEqual({
+			...MANIFEST,
+			schemaVersion: CURRENT_MANIFEST_SCHEMA,
+		});
+	});
+
+	test("should replace files of a previously imported bundle", async () => {
+		await fileService.writeFile("/bundles/commands/en/stale.md", "old");
+		await bundleService.exportBundle("en", "/tmp/en.tar.gz");
+
+		await bundleService.importBundle("/tmp/en.tar.gz");
+
+		expect(await fileService.exists("/bundles/commands/en/stale.md")).toBe(
+			false,
+		);
+	});
+
+	test("should reject archives without bundle.json", async () => {
+		const bundlePath = await writeBundle([
+			{ path: "commands/en/manifest.json", content: JSON.stringify(MANIFEST) },
+		]);
+
+		await expect(bundleService.importBundle(bundlePath)).rejects.toThrow(
+			"bundle.json missing",
+		);
+	});
+
+	test("should reject files that are not archives", async () => {
+		await fileService.writeFile("/tmp/bundle.tar.gz", "plain text");
+
+		await expect(
+			bundleService.importBundle("/tmp/bundle.tar.gz"),
+		).rejects.toBeInstanceOf(BundleError);
+	});
+
+	test("should reject bundles missing a command file", async () => {
+		const bundlePath = await writeBundle([
+			{ path: "bundle.json", content: metadata() },
+			{ path: "commands/en/manifest.json", content: JSON.stringify(MANIFEST) },
+			{ path: "commands/en/debug-help.md", content: "Debug body" },
+		]);
+
+		await expect(bundleService.importBundle(bundlePath)).rejects.toThrow(
+			'missing file "frontend/component.md"',
+		);
+	});
+
+	test("should reject unsafe file paths", async () => {
+		const manifest = {
+			...MANIFEST,
+			commands: [{ ...MANIFEST.commands[0], file: "../escape.md" }],
+		};
+		const bundlePath = await writeBundle([
+			{ path: "bundle.json", content: metadata() },
+			{ path: "commands/en/manifest.json", content: JSON.stringify(manifest) },
+			{ path: "commands/escape.md", content: "evil" },
+		]);
+
+		await expect(bundleService.importBundle(bundlePath)).rejects.toThrow(
+			"Unsafe file path",
+		);
+		expect(await fileService.exists("/bundles/commands/escape.md")).toBe(false);
+	});
+
+	test("should reject an invalid language in bundle.json", async () => {
+		const bundlePath = await writeBundle([
+			{ path: "bundle.json", content: metadata("../x") },
+		]);
+
+		await expect(bundleService.importBundle(bundlePath)).rejects.toThrow(
+			"Invalid bundle language",
+		);
+	});
+
+	describe("FallbackRepository", () => {
+		class UnreachableRepository extends FileSystemRepository {
+			override async getManifest(): Promise<never> {
+				throw new ManifestError("en", "network unreachable");
+			}
+		}
+
+		test("should serve imported bundles when the primary fails", async () => {
+			await bundleService.exportBundle("en", "/tmp/en.tar.gz");
+			await bundleService.importBundle("/tmp/en.tar.gz");
+			const parser = new CommandParser(new NamespaceService());
+			const repository = new FallbackRepository(
+				new UnreachableRepository(fileService, parser, "/nowhere"),
+				new FileSystemRepository(
+					fileService,
+					parser,
+					bundleService.getCommandsDir(),
+				),
+			);
+
+			const manifest = await repository.getManifest("en");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual([
+				"debug-help",
+				"frontend:component",
+			]);
+		});
+
+		test("should not fall back when the primary reports a missing command", async () => {
+			const repository = new FallbackRepository(source, source);
+
+			await expect(
+				repository.getCommand("missing", "en"),
+			).rejects.toBeInstanceOf(CommandNotFoundError);
+		});
+	});
+});
@@ -1,8 +1,12 @@
 import { beforeEach, describe, expect, test } from "bun:test";
-import type IFileService from "../../src/interfaces/IFileService";
-import { CacheManager } from "../../src/services/CacheManager";
-import type { Manifest } from "../../src/types/Command";
-import InMemoryFileService from "../mocks/InMemoryFileService";
+import { dirname } from "node:path";
+import type IFileService from "../../src/interfaces/IFileService.js";
+import { FileIOError } from "../../src/interfaces/IFileService.js";
+import { CacheManager } from "../../src/services/CacheManager.js";
+import type { Manifest } from "../../src/types/Command.js";
+import { createRandom, pick } from "../helpers/random.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
 
 describe("CacheManager", () => {
 	let fileService: IFileService;
@@ -176,6 +180,203 @@ describe("CacheManager", () => {
 		});
 	});
 
+	describe("remove", () => {
+		test("should delete the cache file", async () => {
+			await cacheManager.set("en", mockManifest);
+
+			expect(await cacheManager.remove("en")).toBe(true);
+			expect(await fileService.exists(cacheManager.getCachePath("en"))).toBe(
+				false,
+			);
+		});
+
+		test("should report when there is nothing to remove", async () => {
+			expect(await cacheManager.remove("en")).toBe(false);
+		});
+	});
+
+	describe("listLanguages", () => {
+		test("should return an empty list without a cache directory", async () => {
+			expect(await cacheManager.listLanguages()).toEqual([]);
+		});
+
+		test("should list cached languages in order, ignoring other files", async () => {
+			await cacheManager.set("fr", mockManifest);
+			await cacheManager.set("en", mockManifest);
+			const cacheDir = cacheManager.getCacheDir();
+			await fileService.writeFile(`${cacheDir}/notes.txt`, "x");
+			await fileService.writeFile(`${cacheDir}/invalid-lang/manifest.json`, "{}");
+
+			expect(await cacheManager.listLanguages()).toEqual(["en", "fr"]);
+		});
+	});
+
+	describe("inspect", () => {
+		test("should return null when no cache file exists", async () => {
+			expect(await cacheManager.inspect("en")).toBeNull();
+		});
+
+		test.each([
+			["valid", Date.now(), 1],
+			["expired", Date.now() - 8 * 24 * 60 * 60 * 1000, 1],
+		])("should report %s entries", async (status, timestamp, count) => {
+			await cacheManager.set("en", mockManifest, timestamp);
+
+			const inspection = await cacheManager.inspect("en");
+
+			expect(inspection).toMatchObject({
+				language: "en",
+				path: cacheManager.getCachePath("en"),
+				status,
+				timestamp,
+				commandCount: count,
+			});
+			expect(inspection?.sizeBytes).toBeGreaterThan(0);
+		});
+
+		test("should report cleared entries as empty", async () => {
+			await cacheManager.set("en", mockManifest);
+			await cacheManager.clear("en");
+
+			expect((await cacheManager.inspect("en"))?.status).toBe("empty");
+		});
+
+		test.each([
+			"{not json",
+			JSON.stringify({ manifest: mockManifest }),
+			JSON.stringify({ manifest: { commands: "x" }, timestamp: 1 }),
+		])("should report corrupted content %p", async (content) => {
+			await fileService.writeFile(cacheManager.getCachePath("en"), content);
+
+			expect((await cacheManager.inspect("en"))?.status).toBe("corrupted");
+		});
+	});
+
+	describe("with a controlled clock", () => {
+		const WEEK_MS = 7 * 24 * 60 * 60 * 1000;
+		let clock: FakeClock;
+
+		beforeEach(() => {
+			clock = new FakeClock();
+			cacheManager = new CacheManager(fileService, undefined, clock);
+		});
+
+		test("should stamp entries with the clock time", async () => {
+			await cacheManager.set("en", mockManifest);
+
+			expect((await cacheManager.inspect("en"))?.timestamp).toBe(clock.now());
+		});
+
+		test("should expire exactly after the default max age", async () => {
+			await cacheManager.set("en", mockManifest);
+
+			clock.advance(WEEK_MS);
+			expect(await cacheManager.isExpired("en")).toBe(false);
+			expect(await cacheManager.get("en")).toEqual(mockManifest);
+
+			clock.advance(1);
+			expect(await cacheManager.isExpired("en")).toBe(true);
+			expect(await cacheManager.get("en")).toBeNull();
+			expect((await cacheManager.inspect("en"))?.status).toBe("expired");
+		});
+
+		test("should honor a custom max age", async () => {
+			await cacheManager.set("en", mockManifest);
+			clock.advance(60000);
+
+			expect(await cacheManager.isExpired("en", 60000)).toBe(false);
+			expect(await cacheManager.isExpired("en", 59999)).toBe(true);
+		});
+	});
+
+	describe("getHistory", () => {
+		let clock: FakeClock;
+
+		const withCommands = (...names: string[]): Manifest => ({
+			...mockManifest,
+			commands: names.map((name) => ({
+				name,
+				description: `${name} command`,
+				file: `${name}.md`,
+				"allowed-tools": ["Read"],
+			})),
+		});
+
+		beforeEach(() => {
+			clock = new FakeClock();
+			cacheManager = new CacheManager(fileService, "/cache", clock, 2);
+		});
+
+		test("should return nothing when no manifest is cached", async () => {
+			expect(await cacheManager.getHistory("en")).toEqual([]);
+		});
+
+		test("should keep replaced manifests, newest first", async () => {
+			const first = clock.now();
+			await cacheManager.set("en", withCommands("a"));
+			clock.advance(1000);
+			await cacheManager.set("en", withCommands("a", "b"));
+
+			const history = await cacheManager.getHistory("en");
+
+			expect(history).toEqual([
+				{ manifest: withCommands("a", "b"), timestamp: first + 1000 },
+				{ manifest: withCommands("a"), timestamp: first },
+			]);
+			expect(await fileService.exists(`/cache/en/history/${first}.json`)).toBe(
+				true,
+			);
+		});
+
+		test("should not record refreshes that leave the commands alone", async () => {
+			await cacheManager.set("en", withCommands("a"));
+			clock.advance(1000);
+			await cacheManager.set("en", { ...withCommands("a"), version: "2.0.0" });
+
+			expect(await cacheManager.getHistory("en")).toHaveLength(1);
+		});
+
+		test("should drop the oldest manifests beyond the history size", async () => {
+			for (const names of [["a"], ["a", "b"], ["b"], ["b", "c"]]) {
+				await cacheManager.set("en", withCommands(...names));
+				clock.advance(1000);
+			}
+
+			const history = await cacheManager.getHistory("en");
+
+			expect(history.map((entry) => entry.manifest)).toEqual([
+				withCommands("b", "c"),
+				withCommands("b"),
+				withCommands("a", "b"),
+			]);
+		});
+
+		test("should include an expired current manifest", async () => {
+			await cacheManager.set("en", withCommands("a"));
+			clock.advance(8 * 24 * 60 * 60 * 1000);
+
+			expect(await cacheManager.get("en")).toBeNull();
+			expect(await cacheManager.getHistory("en")).toHaveLength(1);
+		});
+
+		test("should keep no history with a history size of 0", async () => {
+			cacheManager = new CacheManager(fileService, "/cache", clock, 0);
+			await cacheManager.set("en", withCommands("a"));
+			await cacheManager.set("en", withCommands("b"));
+
+			expect(await fileService.exists("/cache/en/history")).toBe(false);
+			expect(await cacheManager.getHistory("en")).toHaveLength(1);
+		});
+
+		test("should not list history files as languages", async () => {
+			await cacheManager.set("en", withCommands("a"));
+			clock.advance(1000);
+			await cacheManager.set("en", withCommands("b"));
+
+			expect(await cacheManager.listLanguages()).toEqual(["en"]);
+		});
+	});
+
 	describe("getCachePath", () => {
 		test("should return language-specific cache path", () => {
 			const path = cacheManager.getCachePath("en");
@@ -192,4 +393,119 @@ describe("CacheManager", () => {
 			expect(pathEs).toContain("es");
 		});
 	});
+
+	describe("concurrent access", () => {
+		test("should serialize parallel writers without leaving locks", async () => {
+			const versions = Array.from({ length: 20 }, (_, i) => `1.0.${i}`);
+
+			await Promise.all(
+				versions.map((version) =>
+					cacheManager.set("en", { ...mockManifest, version }),
+				),
+			);
+
+			const cached = await cacheManager.get("en");
+			expect(versions).toContain(cached?.version ?? "");
+			const cacheDir = dirname(cacheManager.getCachePath("en"));
+			expect(await fileService.listFiles(cacheDir)).toEqual(["manifest.json"]);
+		});
+
+		test("should serialize writers sharing one file service", async () => {
+			const other = new CacheManager(fileService);
+
+			await Promise.all([
+				cacheManager.set("en", mockManifest),
+				other.clear("en"),
+				other.set("en", { ...mockManifest, version: "2.0.0" }),
+				cacheManager.remove("en"),
+			]);
+
+			const cacheDir = dirname(cacheManager.getCachePath("en"));
+			const leftovers = (await fileService.listFiles(cacheDir)).filter(
+				(file) => file !== "manifest.json",
+			);
+			expect(leftovers).toEqual([]);
+		});
+
+		test("should recover a lock abandoned by a crashed process", async () => {
+			const clock = new FakeClock();
+			const manager = new CacheManager(fileService, undefined, clock);
+			await fileService.writeFile(
+				`${manager.getCachePath("en")}.lock`,
+				JSON.stringify({
+					pid: process.pid,
+					hostname: "elsewhere",
+					acquiredAt: clock.now(),
+					token: "crashed",
+				}),
+			);
+			clock.advance(60000);
+
+			await manager.set("en", mockManifest);
+
+			expect(await manager.get("en")).toEqual(mockManifest);
+		});
+	});
+
+	describe("crash safety", () => {
+		const CORRUPTION_RUNS = 200;
+
+		test("should not leave temporary files behind", async () => {
+			await cacheManager.set("en", mockManifest);
+			await cacheManager.set("en", mockManifest);
+
+			const cacheDir = dirname(cacheManager.getCachePath("en"));
+			expect(await fileService.listFiles(cacheDir)).toEqual(["manifest.json"]);
+		});
+
+		test("should keep the previous cache when a write is interrupted", async () => {
+			class CrashingFileService extends InMemoryFileService {
+				crash = false;
+				override async rename(from: string, to: string): Promise<void> {
+					if (this.crash) {
+						throw new FileIOError(to, "simulated crash");
+					}
+					return super.rename(from, to);
+				}
+			}
+			const crashing = new CrashingFileService();
+			const manager = new CacheManager(crashing);
+			await manager.set("en", mockManifest);
+
+			crashing.crash = true;
+			await expect(
+				manager.set("en", { ...mockManifest, version: "2.0.0" }),
+			).rejects.toThrow("Failed to store cache");
+
+			expect(await manager.get("en")).toEqual(mockManifest);
+			const cacheDir = dirname(manager.getCachePath("en"));
+			expect(await crashing.listFiles(cacheDir)).toEqual(["manifest.json"]);
+		});
+
+		test("property: corrupted cache files are treated as misses", async () => {
+			const random = createRandom(31);
+			await cacheManager.set("en", mockManifest);
+			const cachePath = cacheManager.getCachePath("en");
+			const valid = await fileService.readFile(cachePath);
+			const noise = ["\0", "{", "}", '"', ",", "x", "\uFFFD", ""];
+
+			for (let run = 0; run < CORRUPTION_RUNS; run++) {
+				const cut = Math.floor(random() * valid.length);
+				const truncated = valid.slice(0, cut);
+				const corrupted =
+					random() < 0.5
+						? truncated
+						: `${truncated}${pick(random, noise)}${valid.slice(cut + 1)}`;
+				await fileService.writeFile(cachePath, corrupted);
+
+				const result = await cacheManager.get("en");
+				expect(result === null || typeof result === "object").toBe(true);
+				expect(typeof (await cacheManager.isExpired("en"))).toBe("boolean");
+
+				// Rewriting always recovers a usable cache
+				await cacheManager.set("en", mockManifest);
+				expect(await cacheManager.get("en")).toEqual(mockManifest);
+			}
+		});
+	});
 });
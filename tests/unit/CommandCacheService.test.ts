@@ -359,4 +359,93 @@ describe("CommandCacheService", () => {
 			expect(result.modified).toBe(0);
 		});
 	});
+
+	describe("prefetchCommands", () => {
+		const manifest: Manifest = {
+			version: "1.0.0",
+			updated: "2025-01-15T10:00:00Z",
+			commands: ["alpha", "beta", "gamma", "delta"].map((name) => ({
+				name,
+				description: `${name} command`,
+				file: `${name}.md`,
+				"allowed-tools": [],
+			})),
+		};
+
+		beforeEach(() => {
+			repository.setManifest("en", manifest);
+			for (const { name } of manifest.commands) {
+				repository.setCommand(name, "en", `# ${name}`);
+			}
+		});
+
+		it("should fetch every command file of the manifest", async () => {
+			const result = await commandCacheService.prefetchCommands({
+				language: "en",
+			});
+
+			expect(result).toEqual({
+				language: "en",
+				total: 4,
+				fetched: 4,
+				failed: [],
+			});
+			const fetched = repository
+				.getRequestHistory()
+				.filter((entry) => entry.method === "getCommand")
+				.map((entry) => entry.commandName)
+				.sort();
+			expect(fetched).toEqual(["alpha", "beta", "delta", "gamma"]);
+		});
+
+		it("should report progress for each file", async () => {
+			const progress: string[] = [];
+
+			await commandCacheService.prefetchCommands({
+				language: "en",
+				concurrency: 2,
+				onProgress: ({ completed, total }) => {
+					progress.push(`${completed}/${total}`);
+				},
+			});
+
+			expect(progress).toEqual(["1/4", "2/4", "3/4", "4/4"]);
+		});
+
+		it("should report downloaded bytes per file", async () => {
+			repository.setCommand("beta", "en", new Error("network down"));
+			const bytes = new Map<string, number>();
+
+			await commandCacheService.prefetchCommands({
+				language: "en",
+				onProgress: (progress) => {
+					bytes.set(progress.commandName, progress.bytes);
+				},
+			});
+
+			expect(bytes.get("alpha")).toBe("# alpha".length);
+			expect(bytes.get("beta")).toBe(0);
+		});
+
+		it("should collect failures without aborting", async () => {
+			repository.setCommand("beta", "en", new Error("network down"));
+
+			const result = await commandCacheService.prefetchCommands({
+				language: "en",
+			});
+
+			expect(result.fetched).toBe(3);
+			expect(result.failed.map((failure) => failure.commandName)).toEqual([
+				"beta",
+			]);
+		});
+
+		it("should propagate manifest errors", async () => {
+			repository.setManifest("en", new ManifestError("en", "offline"));
+
+			await expect(
+				commandCacheService.prefetchCommands({ language: "en" }),
+			).rejects.toThrow(ManifestError);
+		});
+	});
 });
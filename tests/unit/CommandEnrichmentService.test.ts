@@ -124,6 +124,22 @@ This is a local override of the debug command.`,
 			expect(result.availableInSources.length).toBeGreaterThan(1);
 		});
 
+		it("should show the argument hint of local commands", async () => {
+			await fileService.mkdir(".claude/commands");
+			await fileService.writeFile(
+				".claude/commands/local-only.md",
+				"---\ndescription: Local command\nargument-hint: [message]\n---\n\n# Local\n",
+			);
+
+			const result = await commandEnrichmentService.getEnhancedCommandInfo(
+				"local-only",
+				{ language: "en" },
+			);
+
+			expect(result.source).toMatch(/personal|project/);
+			expect(result["argument-hint"]).toBe("[message]");
+		});
+
 		it("should show correct installation status for repository commands", async () => {
 			// Execute: Get info for a repository command that's not installed
 			const result = await commandEnrichmentService.getEnhancedCommandInfo(
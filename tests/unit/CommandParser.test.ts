@@ -183,6 +183,34 @@ argument-hint: add [tagId] | remove [tagId] | list
 			);
 		});
 
+		test("should read an unquoted bracketed argument-hint", async () => {
+			const content = `---
+description: Commit changes
+argument-hint: [message]
+---
+
+# Commit
+`;
+
+			const command = await parser.parseCommandFile(content, "commit");
+
+			expect(command["argument-hint"]).toBe("[message]");
+		});
+
+		test("should parse requires as a list of command names", async () => {
+			const content = `---
+description: Review changes
+requires: lint, format, lint
+---
+
+# Review
+`;
+
+			const command = await parser.parseCommandFile(content, "review");
+
+			expect(command.requires).toEqual(["lint", "format"]);
+		});
+
 		test("should handle command without argument-hint field", async () => {
 			const content = `---
 description: Command without argument hint
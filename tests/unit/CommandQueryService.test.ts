@@ -1,13 +1,16 @@
 import { beforeEach, describe, expect, it } from "bun:test";
+import type IRepository from "../../src/interfaces/IRepository.js";
 import { CacheManager } from "../../src/services/CacheManager.js";
 import { CommandParser } from "../../src/services/CommandParser.js";
 import { CommandQueryService } from "../../src/services/CommandQueryService.js";
+import { ContentSearchIndex } from "../../src/services/ContentSearchIndex.js";
 import { DirectoryDetector } from "../../src/services/DirectoryDetector.js";
 import { LanguageDetector } from "../../src/services/LanguageDetector.js";
 import { LocalCommandRepository } from "../../src/services/LocalCommandRepository.js";
 import NamespaceService from "../../src/services/NamespaceService.js";
 import type { Manifest } from "../../src/types/Command.js";
 import {
+	AmbiguousCommandError,
 	CommandNotFoundError,
 	ManifestError,
 } from "../../src/types/Command.js";
@@ -166,6 +169,91 @@ describe("CommandQueryService", () => {
 			expect(history[0]?.language).toBe("en");
 			expect(history[0]?.options?.forceRefresh).toBe(true);
 		});
+
+		it("should return commands in canonical name order", async () => {
+			const unsortedManifest: Manifest = {
+				version: "1.0.0",
+				updated: "2025-01-15T10:00:00Z",
+				commands: [
+					{
+						name: "zeta",
+						description: "Last command",
+						file: "zeta.md",
+						"allowed-tools": [],
+					},
+					{
+						name: "frontend:component",
+						description: "Namespaced command",
+						file: "frontend/component.md",
+						"allowed-tools": [],
+						namespace: "frontend",
+					},
+					{
+						name: "alpha",
+						description: "First command",
+						file: "alpha.md",
+						"allowed-tools": [],
+					},
+				],
+			};
+			await cacheManager.set("en", unsortedManifest);
+
+			const result = await commandQueryService.listCommands({ language: "en" });
+
+			expect(result.map((cmd) => cmd.name)).toEqual([
+				"alpha",
+				"frontend:component",
+				"zeta",
+			]);
+		});
+
+		it("should reuse the index while the manifest is unchanged", async () => {
+			const manifest: Manifest = {
+				version: "1.0.0",
+				updated: "2025-01-15T10:00:00Z",
+				commands: [
+					{
+						name: "test-command",
+						description: "A test command",
+						file: "test-command.md",
+						"allowed-tools": [],
+					},
+				],
+			};
+			await cacheManager.set("en", manifest);
+
+			const first = await commandQueryService.listCommands({ language: "en" });
+			const second = await commandQueryService.listCommands({ language: "en" });
+
+			expect(second).toBe(first);
+		});
+
+		it("should rebuild the index when the manifest changes", async () => {
+			const command = {
+				name: "test-command",
+				description: "A test command",
+				file: "test-command.md",
+				"allowed-tools": [],
+			};
+			await cacheManager.set("en", {
+				version: "1.0.0",
+				updated: "2025-01-15T10:00:00Z",
+				commands: [command],
+			});
+			await commandQueryService.listCommands({ language: "en" });
+
+			await cacheManager.set("en", {
+				version: "1.0.0",
+				updated: "2025-01-16T10:00:00Z",
+				commands: [command, { ...command, name: "new-command" }],
+			});
+			const result = await commandQueryService.listCommands({ language: "en" });
+
+			expect(result.map((cmd) => cmd.name)).toEqual([
+				"new-command",
+				"test-command",
+			]);
+		});
 	});
 
 	describe("searchCommands", () => {
@@ -221,6 +309,193 @@ describe("CommandQueryService", () => {
 		});
 	});
 
+	describe("tag filtering", () => {
+		beforeEach(async () => {
+			await cacheManager.set("en", {
+				version: "1.0.0",
+				updated: "2025-01-15T10:00:00Z",
+				commands: [
+					{
+						name: "trace",
+						description: "Trace a failure",
+						file: "trace.md",
+						"allowed-tools": [],
+						category: "Debugging",
+						tags: ["logs"],
+					},
+					{
+						name: "review",
+						description: "Review a pull request",
+						file: "review.md",
+						"allowed-tools": [],
+						tags: ["git"],
+					},
+				],
+			});
+		});
+
+		it("should list only commands with the tag or category", async () => {
+			const byCategory = await commandQueryService.listCommands({
+				language: "en",
+				tag: "debugging",
+			});
+			const byTag = await commandQueryService.listCommands({
+				language: "en",
+				tag: "GIT",
+			});
+
+			expect(byCategory.map((c) => c.name)).toEqual(["trace"]);
+			expect(byTag.map((c) => c.name)).toEqual(["review"]);
+		});
+
+		it("should match tags in search queries and combine with --tag", async () => {
+			const byQuery = await commandQueryService.searchCommands("logs", {
+				language: "en",
+			});
+			const filtered = await commandQueryService.searchCommands("a", {
+				language: "en",
+				tag: "git",
+			});
+
+			expect(byQuery.map((c) => c.name)).toEqual(["trace"]);
+			expect(filtered.map((c) => c.name)).toEqual(["review"]);
+		});
+	});
+
+	describe("content search", () => {
+		const manifest: Manifest = {
+			version: "1.0.0",
+			updated: "2025-01-15T10:00:00Z",
+			commands: [
+				{
+					name: "trace",
+					description: "Trace a failure",
+					file: "trace.md",
+					"allowed-tools": [],
+					sha256: "a",
+				},
+				{
+					name: "review",
+					description: "Review a pull request",
+					file: "review.md",
+					"allowed-tools": [],
+					sha256: "b",
+				},
+			],
+		};
+		const bodies: Record<string, string> = {
+			trace: "Read the stack trace and the logs",
+			review: "Check the diff for missing tests",
+		};
+		let fetched: string[];
+		let service: CommandQueryService;
+
+		beforeEach(() => {
+			fetched = [];
+			const stub: IRepository = {
+				getManifest: async () => manifest,
+				getCommand: async (name) => {
+					fetched.push(name);
+					return bodies[name] ?? "";
+				},
+				getAvailableLanguages: async () => [],
+				getAbout: async () => null,
+			};
+			service = new CommandQueryService(
+				stub,
+				cacheManager,
+				languageDetector,
+				new ContentSearchIndex(fileService, "/cache"),
+			);
+		});
+
+		it("should match words in command files only when asked", async () => {
+			const plain = await service.searchCommands("stack", { language: "en" });
+			const content = await service.searchCommands("stack", {
+				language: "en",
+				content: true,
+			});
+
+			expect(plain).toEqual([]);
+			expect(content.map((c) => c.name)).toEqual(["trace"]);
+		});
+
+		it("should reuse the index instead of refetching files", async () => {
+			await service.searchCommands("tests", { language: "en", content: true });
+			fetched = [];
+
+			const result = await service.searchCommands("diff", {
+				language: "en",
+				content: true,
+			});
+
+			expect(result.map((c) => c.name)).toEqual(["review"]);
+			expect(fetched).toEqual([]);
+		});
+	});
+
+	describe("prefixed repositories", () => {
+		let service: CommandQueryService;
+
+		beforeEach(() => {
+			const command = (name: string) => ({
+				name,
+				description: name,
+				file: `${name}.md`,
+				"allowed-tools": [],
+			});
+			const stub: IRepository = {
+				getManifest: async () => ({
+					version: "1.0.0",
+					updated: "2025-01-15T10:00:00Z",
+					commands: [
+						"deploy",
+						"team:deploy",
+						"team:lint",
+						"ops:lint",
+						"ops:rollback",
+					].map(command),
+				}),
+				getCommand: async () => "",
+				getAvailableLanguages: async () => [],
+				getAbout: async () => null,
+			};
+			service = new CommandQueryService(
+				stub,
+				cacheManager,
+				languageDetector,
+				undefined,
+				async () => ["team", "ops"],
+			);
+		});
+
+		it("should keep names that are listed", async () => {
+			expect(
+				await service.resolveCommandName("deploy", { language: "en" }),
+			).toBe("deploy");
+			expect(
+				await service.resolveCommandName("team:lint", { language: "en" }),
+			).toBe("team:lint");
+		});
+
+		it("should find the prefixed command a bare name stands for", async () => {
+			const info = await service.getCommandInfo("rollback", {
+				language: "en",
+			});
+
+			expect(info.name).toBe("ops:rollback");
+			expect(
+				await service.resolveCommandName("nonexistent", { language: "en" }),
+			).toBe("nonexistent");
+		});
+
+		it("should refuse bare names several repositories provide", async () => {
+			await expect(
+				service.getCommandInfo("lint", { language: "en" }),
+			).rejects.toThrow(AmbiguousCommandError);
+		});
+	});
+
 	describe("getCommandInfo", () => {
 		it("should return command metadata when command exists", async () => {
 			// Execute
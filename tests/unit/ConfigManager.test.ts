@@ -180,6 +180,16 @@ describe("ConfigManager", () => {
 			expect(language).toBe("de");
 		});
 
+		test("should prioritize the global --language flag over everything", async () => {
+			await userConfigService.setConfig({ preferredLanguage: "fr" });
+			await projectConfigService.setConfig({ preferredLanguage: "es" });
+			process.env.CLAUDE_CMD_LANG = "de";
+			languageDetector.setCliFlag("ja");
+
+			const language = await configManager.getEffectiveLanguage();
+			expect(language).toBe("ja");
+		});
+
 		test("should prioritize project config over user config", async () => {
 			const userConfig = { preferredLanguage: "fr" };
 			const projectConfig = { preferredLanguage: "es" };
@@ -270,6 +280,56 @@ describe("ConfigManager", () => {
 		});
 	});
 
+	describe("getLanguageResolution", () => {
+		test("should report the default when no source is set", async () => {
+			const resolution = await configManager.getLanguageResolution();
+
+			expect(resolution).toEqual({
+				language: "en",
+				source: "default",
+				sources: { flag: "", env: "", project: "", user: "", locale: "" },
+			});
+		});
+
+		test("should report the winning source and every source's value", async () => {
+			await userConfigService.setConfig({ preferredLanguage: "fr" });
+			await projectConfigService.setConfig({ preferredLanguage: "es" });
+			process.env.LANG = "de_DE.UTF-8";
+
+			const resolution = await configManager.getLanguageResolution();
+
+			expect(resolution.language).toBe("es");
+			expect(resolution.source).toBe("project");
+			expect(resolution.sources).toEqual({
+				flag: "",
+				env: "",
+				project: "es",
+				user: "fr",
+				locale: "de_DE.UTF-8",
+			});
+		});
+
+		test("should attribute the language to the system locale", async () => {
+			process.env.LC_ALL = "pt_BR.UTF-8";
+
+			const resolution = await configManager.getLanguageResolution();
+
+			expect(resolution.language).toBe("pt");
+			expect(resolution.source).toBe("locale");
+		});
+
+		test("should attribute the language to the global flag", async () => {
+			process.env.CLAUDE_CMD_LANG = "de";
+			languageDetector.setCliFlag("ja");
+
+			const resolution = await configManager.getLanguageResolution();
+
+			expect(resolution.language).toBe("ja");
+			expect(resolution.source).toBe("flag");
+			expect(resolution.sources.env).toBe("de");
+		});
+	});
+
 	describe("error handling", () => {
 		test("should handle corrupted config files gracefully", async () => {
 			// Create corrupted files
@@ -186,6 +186,47 @@ describe("ConfigService", () => {
 			expect(frenchLang?.available).toBe(false);
 		});
 
+		test("should mark languages the repository publishes as available", async () => {
+			const languagesUrl = `${HTTPRepository.BASE_URL}/commands/languages.json`;
+			httpClient.setResponse(languagesUrl, {
+				status: 200,
+				statusText: "OK",
+				headers: { "content-type": "application/json" },
+				body: '["fr", {"code": "uk", "name": "Українська"}]',
+				url: languagesUrl,
+			});
+
+			const languages = await userConfigService.getAvailableLanguages();
+
+			expect(languages.find((l) => l.code === "fr")?.available).toBe(true);
+			expect(languages.find((l) => l.code === "de")?.available).toBe(false);
+			expect(languages.find((l) => l.code === "uk")).toEqual({
+				code: "uk",
+				name: "Українська",
+				available: true,
+			});
+		});
+
+		test("should probe manifests when the repository publishes no index", async () => {
+			const manifestUrl = `${HTTPRepository.BASE_URL}/commands/fr/manifest.json`;
+			httpClient.setResponse(manifestUrl, {
+				status: 200,
+				statusText: "OK",
+				headers: { "content-type": "application/json" },
+				body: JSON.stringify({
+					version: "1.0.0",
+					updated: "2025-01-01T00:00:00Z",
+					commands: [],
+				}),
+				url: manifestUrl,
+			});
+
+			const languages = await userConfigService.getAvailableLanguages();
+
+			expect(languages.find((l) => l.code === "fr")?.available).toBe(true);
+			expect(languages.find((l) => l.code === "de")?.available).toBe(false);
+		});
+
 		test("should work consistently across different ConfigService instances", async () => {
 			// Both user and project config services should return same language availability
 			const userLanguages = await userConfigService.getAvailableLanguages();
@@ -235,6 +276,119 @@ describe("ConfigService", () => {
 			).rejects.toThrow("Invalid configuration");
 		});
 
+		test("should accept scp-like SSH URL for git repositories", async () => {
+			const gitConfig = {
+				repositoryType: "git" as const,
+				repositoryURL: "git@github.com:acme/commands.git",
+				repositoryRef: "v1.2.0",
+			};
+
+			await userConfigService.setConfig(gitConfig);
+
+			expect(await userConfigService.getConfig()).toEqual(gitConfig);
+		});
+
+		test("should reject scp-like SSH URL without git repository type", async () => {
+			const invalidConfig = {
+				repositoryURL: "git@github.com:acme/commands.git",
+			};
+
+			await expect(userConfigService.setConfig(invalidConfig)).rejects.toThrow(
+				"Invalid configuration",
+			);
+		});
+
+		test("should reject unknown repository type", async () => {
+			const invalidConfig = { repositoryType: "svn" };
+
+			await expect(
+				userConfigService.setConfig(invalidConfig as any),
+			).rejects.toThrow("Invalid configuration");
+		});
+
+		test("should reject repository ref starting with a dash", async () => {
+			const invalidConfig = {
+				repositoryType: "git" as const,
+				repositoryRef: "--upload-pack=evil",
+			};
+
+			await expect(userConfigService.setConfig(invalidConfig)).rejects.toThrow(
+				"Invalid configuration",
+			);
+		});
+
+		test("should accept HTTP limits", async () => {
+			const config = {
+				maxRequestsPerSecond: 2.5,
+				maxParallelDownloads: 4,
+				maxBytesPerSecond: 1_000_000,
+				maxManifestBytes: 5_000_000,
+				maxCommandBytes: 200_000,
+			};
+
+			await userConfigService.setConfig(config);
+
+			expect(await userConfigService.getConfig()).toEqual(config);
+		});
+
+		test("should reject non-positive or fractional HTTP limits", async () => {
+			for (const invalidConfig of [
+				{ maxRequestsPerSecond: 0 },
+				{ maxBytesPerSecond: -1 },
+				{ maxParallelDownloads: 1.5 },
+				{ maxManifestBytes: 0 },
+				{ maxCommandBytes: 1.5 },
+			]) {
+				await expect(
+					userConfigService.setConfig(invalidConfig),
+				).rejects.toThrow("Invalid configuration");
+			}
+		});
+
+		test("should accept preview limits and reject invalid ones", async () => {
+			const config = { previewLines: 40, previewCharacters: 4000 };
+			await userConfigService.setConfig(config);
+			expect(await userConfigService.getConfig()).toEqual(config);
+
+			for (const invalidConfig of [
+				{ previewLines: 0 },
+				{ previewCharacters: 2.5 },
+			]) {
+				await expect(
+					userConfigService.setConfig(invalidConfig),
+				).rejects.toThrow("Invalid configuration");
+			}
+		});
+
+		test("should accept hook settings and reject invalid ones", async () => {
+			const config = {
+				hooks: { "pre-add": "make check", "post-remove": ["a", "b"] },
+				projectHookTrust: {
+					"/work/app": { allow: true, fingerprint: "a".repeat(64) },
+				},
+				hookTimeoutSeconds: 30,
+			};
+			await userConfigService.setConfig(config);
+			expect(await userConfigService.getConfig()).toEqual(config);
+
+			for (const invalidConfig of [
+				{ hooks: { "before-add": "make check" } },
+				{ hooks: { "post-add": "" } },
+				{ hooks: { "post-add": ["make", 1] } },
+				{ projectHookTrust: { "/work/app": true } },
+				{
+					projectHookTrust: {
+						"work/app": { allow: true, fingerprint: "a".repeat(64) },
+					},
+				},
+				{ hookTimeoutSeconds: 0 },
+			]) {
+				await expect(
+					userConfigService.setConfig(invalidConfig),
+				).rejects.toThrow("Invalid configuration");
+			}
+		});
+
 		test("should accept empty configuration", async () => {
 			const emptyConfig = {};
 
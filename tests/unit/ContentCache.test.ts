@@ -0,0 +1,216 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import type IRepository from "../../src/interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../../src/interfaces/IRepository.js";
+import { ContentCache } from "../../src/services/ContentCache.js";
+import { ContentCachingRepository } from "../../src/services/ContentCachingRepository.js";
+import type { Manifest } from "../../src/types/Command.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+} from "../../src/types/Command.js";
+import type { RepositoryAbout } from "../../src/types/Repository.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const CACHE_DIR = "/cache";
+const BODY = "---\ndescription: Say hello\n---\n\nHello!\n";
+
+/**
+ * Repository serving one language from memory, with a network switch
+ */
+class StubRepository implements IRepository {
+	online = true;
+	fetches = 0;
+
+	constructor(
+		public manifest: Manifest,
+		public readonly files: Map<string, string>,
+	) {}
+
+	async getManifest(): Promise<Manifest> {
+		return this.manifest;
+	}
+
+	async getCommand(commandName: string, language: string): Promise<string> {
+		const command = this.manifest.commands.find((c) => c.name === commandName);
+		if (!command) {
+			throw new CommandNotFoundError(commandName, language);
+		}
+		if (!this.online) {
+			throw new CommandContentError(commandName, language, "network down");
+		}
+		this.fetches++;
+		return this.files.get(command.file) ?? "";
+	}
+
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return [];
+	}
+
+	async getAbout(): Promise<RepositoryAbout | null> {
+		return null;
+	}
+}
+
+function manifest(updated: string, sha256?: string): Manifest {
+	return {
+		version: "1.0.0",
+		updated,
+		commands: [
+			{
+				name: "frontend:hello",
+				description: "Say hello",
+				file: "frontend/hello.md",
+				"allowed-tools": [],
+				...(sha256 ? { sha256 } : {}),
+			},
+		],
+	};
+}
+
+describe("ContentCache", () => {
+	let fileService: InMemoryFileService;
+	let cache: ContentCache;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		cache = new ContentCache(fileService, CACHE_DIR, new FakeClock());
+	});
+
+	test("should store files under pages/{lang}/files", async () => {
+		await cache.set("en", "frontend/hello.md", BODY, "2025-01-01");
+
+		expect(cache.getFilesDir("en")).toBe("/cache/pages/en/files");
+		expect(
+			await fileService.readFile("/cache/pages/en/files/frontend/hello.md"),
+		).toBe(BODY);
+		expect(await cache.get("en", "frontend/hello.md")).toEqual({
+			content: BODY,
+			sha256: ContentCache.checksum(BODY),
+			manifestUpdated: "2025-01-01",
+			cachedAt: Date.UTC(2025, 0, 1),
+		});
+	});
+
+	test("should miss when the expected checksum differs", async () => {
+		await cache.set("en", "hello.md", BODY);
+
+		expect(await cache.get("en", "hello.md", "0".repeat(64))).toBeNull();
+		expect(
+			await cache.get("en", "hello.md", ContentCache.checksum(BODY)),
+		).not.toBeNull();
+	});
+
+	test("should miss when the cached content was altered", async () => {
+		await cache.set("en", "hello.md", BODY);
+		await fileService.writeFile("/cache/pages/en/files/hello.md", "tampered");
+
+		expect(await cache.get("en", "hello.md")).toBeNull();
+	});
+
+	test("should list languages and summarize their cached files", async () => {
+		const clock = new FakeClock();
+		cache = new ContentCache(fileService, CACHE_DIR, clock);
+		await cache.set("fr", "hello.md", BODY);
+		clock.advance(60_000);
+		await cache.set("en", "hello.md", BODY);
+		await cache.set("en", "frontend/button.md", "x");
+
+		expect(await cache.listLanguages()).toEqual(["en", "fr"]);
+		expect(await cache.getStats("en")).toEqual({
+			fileCount: 2,
+			sizeBytes: Buffer.byteLength(BODY) + 1,
+			newestCachedAt: clock.now(),
+		});
+		expect(await cache.getStats("de")).toEqual({ fileCount: 0, sizeBytes: 0 });
+	});
+
+	test("should refuse paths outside the cache directory", async () => {
+		await cache.set("en", "../escape.md", BODY);
+		await cache.set("../x", "hello.md", BODY);
+
+		expect(await fileService.exists("/cache/pages/escape.md")).toBe(false);
+		expect(await cache.get("en", "../escape.md")).toBeNull();
+	});
+});
+
+describe("ContentCachingRepository", () => {
+	let source: StubRepository;
+	let repository: ContentCachingRepository;
+
+	beforeEach(() => {
+		const fileService = new InMemoryFileService();
+		source = new StubRepository(
+			manifest("2025-01-01"),
+			new Map([["frontend/hello.md", BODY]]),
+		);
+		repository = new ContentCachingRepository(
+			source,
+			new ContentCache(fileService, CACHE_DIR, new FakeClock()),
+		);
+	});
+
+	test("should fetch a file once while the manifest is unchanged", async () => {
+		expect(await repository.getCommand("frontend:hello", "en")).toBe(BODY);
+		expect(await repository.getCommand("frontend:hello", "en")).toBe(BODY);
+
+		expect(source.fetches).toBe(1);
+	});
+
+	test("should refetch after the manifest is updated", async () => {
+		await repository.getCommand("frontend:hello", "en");
+		source.manifest = manifest("2025-02-01");
+		source.files.set("frontend/hello.md", "new body");
+
+		expect(await repository.getCommand("frontend:hello", "en")).toBe(
+			"new body",
+		);
+		expect(source.fetches).toBe(2);
+	});
+
+	test("should key on the manifest checksum when one is published", async () => {
+		source.manifest = manifest("2025-01-01", ContentCache.checksum(BODY));
+		await repository.getCommand("frontend:hello", "en");
+
+		// Updated stamp changes but the checksum does not: still a hit
+		source.manifest = manifest("2025-03-01", ContentCache.checksum(BODY));
+		await repository.getCommand("frontend:hello", "en");
+		expect(source.fetches).toBe(1);
+
+		source.manifest = manifest("2025-03-01", ContentCache.checksum("v2"));
+		source.files.set("frontend/hello.md", "v2");
+		expect(await repository.getCommand("frontend:hello", "en")).toBe("v2");
+		expect(source.fetches).toBe(2);
+	});
+
+	test("should bypass the cache on force refresh", async () => {
+		await repository.getCommand("frontend:hello", "en");
+		await repository.getCommand("frontend:hello", "en", {
+			forceRefresh: true,
+		});
+
+		expect(source.fetches).toBe(2);
+	});
+
+	test("should serve the cached copy when offline", async () => {
+		await repository.getCommand("frontend:hello", "en");
+		source.manifest = manifest("2025-02-01");
+		source.online = false;
+
+		expect(await repository.getCommand("frontend:hello", "en")).toBe(BODY);
+	});
+
+	test("should fail offline when the file was never fetched", async () => {
+		source.online = false;
+
+		await expect(
+			repository.getCommand("frontend:hello", "en"),
+		).rejects.toThrow(CommandContentError);
+	});
+
+	test("should report commands missing from the manifest", async () => {
+		await expect(repository.getCommand("missing", "en")).rejects.toThrow(
+			CommandNotFoundError,
+		);
+	});
+});
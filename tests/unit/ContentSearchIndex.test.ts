@@ -0,0 +1,166 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	ContentSearchIndex,
+	tokenize,
+} from "../../src/services/ContentSearchIndex.js";
+import type { Manifest } from "../../src/types/Command.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const CACHE_DIR = "/cache";
+
+function manifest(
+	updated: string,
+	versions: Record<string, string>,
+): Manifest {
+	return {
+		version: "1.0.0",
+		updated,
+		commands: Object.entries(versions).map(([name, sha256]) => ({
+			name,
+			description: name,
+			file: `${name}.md`,
+			"allowed-tools": [],
+			sha256,
+		})),
+	};
+}
+
+describe("ContentSearchIndex", () => {
+	let fileService: InMemoryFileService;
+	let bodies: Map<string, string>;
+	let fetched: string[];
+	const fetch = async (commandName: string): Promise<string> => {
+		fetched.push(commandName);
+		const body = bodies.get(commandName);
+		if (body === undefined) {
+			throw new Error(`offline: ${commandName}`);
+		}
+		return body;
+	};
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		fetched = [];
+		bodies = new Map([
+			["rebase", "Interactive git rebase onto the main branch"],
+			["deploy", "Deploy the service to staging, then production"],
+			["review", "Review the staged git changes"],
+		]);
+	});
+
+	test("should split text into distinct lowercase words", () => {
+		expect(tokenize("Code-review: the CODE, café 42")).toEqual([
+			"code",
+			"review",
+			"the",
+			"café",
+			"42",
+		]);
+	});
+
+	test("should find commands containing every word by prefix", async () => {
+		const index = new ContentSearchIndex(fileService, CACHE_DIR);
+		await index.refresh(
+			"en",
+			manifest("t1", { rebase: "a", deploy: "b", review: "c" }),
+			fetch,
+		);
+
+		expect(await index.search("en", "git")).toEqual(
+			new Set(["rebase", "review"]),
+		);
+		expect(await index.search("en", "GIT stag")).toEqual(new Set(["review"]));
+		expect(await index.search("en", "kubernetes")).toEqual(new Set());
+	});
+
+	test("should return null before an index was built", async () => {
+		const index = new ContentSearchIndex(fileService, CACHE_DIR);
+
+		expect(await index.exists("en")).toBe(false);
+		expect(await index.search("en", "git")).toBeNull();
+	});
+
+	test("should persist the index in the cache directory", async () => {
+		await new ContentSearchIndex(fileService, CACHE_DIR).refresh(
+			"en",
+			manifest("t1", { rebase: "a", deploy: "b" }),
+			fetch,
+		);
+
+		const reloaded = new ContentSearchIndex(fileService, CACHE_DIR);
+		expect(await fileService.exists("/cache/search/en.json")).toBe(true);
+		expect(await reloaded.search("en", "production")).toEqual(
+			new Set(["deploy"]),
+		);
+	});
+
+	test("should only fetch new and changed commands on refresh", async () => {
+		await new ContentSearchIndex(fileService, CACHE_DIR).refresh(
+			"en",
+			manifest("t1", { rebase: "a", deploy: "b", review: "c" }),
+			fetch,
+		);
+		fetched = [];
+		bodies.set("deploy", "Deploy with a canary release");
+		bodies.set("lint", "Lint the sources");
+
+		const refresh = await new ContentSearchIndex(
+			fileService,
+			CACHE_DIR,
+		).refresh(
+			"en",
+			manifest("t2", { rebase: "a", deploy: "b2", lint: "d" }),
+			fetch,
+		);
+
+		expect(fetched.sort()).toEqual(["deploy", "lint"]);
+		expect(refresh).toEqual({
+			indexed: 2,
+			reused: 1,
+			removed: 1,
+			failed: [],
+		});
+		const reloaded = new ContentSearchIndex(fileService, CACHE_DIR);
+		expect(await reloaded.search("en", "canary")).toEqual(new Set(["deploy"]));
+		expect(await reloaded.search("en", "staged")).toEqual(new Set());
+	});
+
+	test("should retry commands that could not be fetched", async () => {
+		const index = new ContentSearchIndex(fileService, CACHE_DIR);
+		bodies.delete("review");
+
+		const first = await index.refresh(
+			"en",
+			manifest("t1", { rebase: "a", review: "c" }),
+			fetch,
+		);
+		bodies.set("review", "Review the staged git changes");
+		fetched = [];
+		const second = await index.refresh(
+			"en",
+			manifest("t1", { rebase: "a", review: "c" }),
+			fetch,
+		);
+
+		expect(first.failed).toEqual([
+			{ commandName: "review", error: "offline: review" },
+		]);
+		expect(fetched).toEqual(["review"]);
+		expect(second.indexed).toBe(1);
+		expect(await index.search("en", "staged")).toEqual(new Set(["review"]));
+	});
+
+	test("should rebuild an unreadable index", async () => {
+		await fileService.writeFile("/cache/search/en.json", "{not json");
+		const index = new ContentSearchIndex(fileService, CACHE_DIR);
+
+		const refresh = await index.refresh(
+			"en",
+			manifest("t1", { rebase: "a" }),
+			fetch,
+		);
+
+		expect(refresh.indexed).toBe(1);
+		expect(await index.search("en", "rebase")).toEqual(new Set(["rebase"]));
+	});
+});
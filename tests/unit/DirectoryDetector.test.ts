@@ -1,6 +1,10 @@
 import { beforeEach, describe, expect, spyOn, test } from "bun:test";
 import os from "node:os";
-import { DirectoryDetector } from "../../src/services/DirectoryDetector.js";
+import { dirname, join } from "node:path";
+import {
+	DEFAULT_SCAN_MAX_DEPTH,
+	DirectoryDetector,
+} from "../../src/services/DirectoryDetector.js";
 import InMemoryFileService from "../mocks/InMemoryFileService.js";
 
 describe("DirectoryDetector", () => {
@@ -142,6 +146,17 @@ describe("DirectoryDetector", () => {
 				process.env.HOME = originalHome;
 			}
 		});
+
+		test("should use a relocated Claude directory", async () => {
+			const detector = new DirectoryDetector(fileService, "/srv/claude");
+
+			expect(await detector.getPersonalDirectory()).toBe(
+				"/srv/claude/commands",
+			);
+			expect(await detector.getPreferredInstallLocation("personal")).toBe(
+				"/srv/claude/commands",
+			);
+		});
 	});
 
 	describe("getProjectDirectory", () => {
@@ -154,6 +169,52 @@ describe("DirectoryDetector", () => {
 			const path = await directoryDetector.getProjectDirectory(true);
 			expect(path).toMatch(/^\/.*\.claude\/commands$/);
 		});
+
+		test("should point at a project root above the working directory", async () => {
+			const root = dirname(dirname(process.cwd()));
+			const detector = new DirectoryDetector(fileService, undefined, root);
+
+			expect(await detector.getProjectDirectory()).toBe(
+				"../../.claude/commands",
+			);
+			expect(await detector.getProjectDirectory(true)).toBe(
+				join(root, ".claude", "commands"),
+			);
+			expect(detector.getProjectRoot()).toBe(root);
+
+			const directories = await detector.getClaudeDirectories();
+			expect(directories.find((d) => d.type === "project")?.projectRoot).toBe(
+				root,
+			);
+		});
+
+		test("should list enclosing projects after the nearest one", async () => {
+			const outer = dirname(dirname(process.cwd()));
+			const detector = new DirectoryDetector(
+				fileService,
+				undefined,
+				process.cwd(),
+				undefined,
+				[outer],
+			);
+			await fileService.writeFile(".claude/commands/local.md", "# Local");
+			await fileService.writeFile("../../.claude/commands/shared.md", "# Team");
+
+			expect(await detector.getProjectDirectories()).toEqual([
+				".claude/commands",
+				"../../.claude/commands",
+			]);
+			expect(detector.getProjectRoots()).toEqual([process.cwd(), outer]);
+			expect(
+				(await detector.getClaudeDirectories())
+					.filter((d) => d.type === "project")
+					.map((d) => d.projectRoot),
+			).toEqual([process.cwd(), outer]);
+			expect((await detector.scanAllClaudeDirectories()).project).toEqual([
+				".claude/commands/local.md",
+				"../../.claude/commands/shared.md",
+			]);
+		});
 	});
 
 	describe("ensureDirectoryExists", () => {
@@ -486,6 +547,78 @@ describe("DirectoryDetector", () => {
 				// Should complete within reasonable time (less than 1 second for this size)
 				expect(endTime - startTime).toBeLessThan(1000);
 			});
+
+			/**
+			 * Add a dependency tree of many markdown files below a directory
+			 */
+			function addPackages(base: string, packages: number): void {
+				for (let pkg = 0; pkg < packages; pkg++) {
+					for (let file = 0; file < 10; file++) {
+						fileService.setFile(
+							`${base}/pkg${pkg}/docs/file${file}.md`,
+							"# Not a command",
+						);
+					}
+				}
+			}
+
+			test("should skip node_modules, vendor and hidden directories", async () => {
+				await fileService.mkdir("/test/big");
+				await fileService.writeFile("/test/big/review.md", "# Review");
+				await fileService.writeFile("/test/big/git/commit.md", "# Commit");
+				addPackages("/test/big/node_modules", 200);
+				addPackages("/test/big/git/vendor", 100);
+				addPackages("/test/big/.cache", 50);
+
+				const commandFiles =
+					await directoryDetector.scanForCommandFiles("/test/big");
+
+				expect(commandFiles).toEqual([
+					"/test/big/git/commit.md",
+					"/test/big/review.md",
+				]);
+			});
+
+			test("should stop at the maximum depth in deeply nested trees", async () => {
+				await fileService.mkdir("/test/deep");
+				let dirPath = "/test/deep";
+				for (let level = 0; level < 100; level++) {
+					fileService.setFile(`${dirPath}/level${level}.md`, "# Level");
+					dirPath += `/d${level}`;
+				}
+
+				const commandFiles =
+					await directoryDetector.scanForCommandFiles("/test/deep");
+
+				// Levels 0 through DEFAULT_SCAN_MAX_DEPTH
+				expect(commandFiles).toHaveLength(DEFAULT_SCAN_MAX_DEPTH + 1);
+			});
+
+			test("should use the configured scan limits", async () => {
+				const detector = new DirectoryDetector(
+					fileService,
+					undefined,
+					undefined,
+					async () => ({ maxDepth: 1, ignore: ["build-*"] }),
+				);
+				await fileService.writeFile("/test/conf/top.md", "# Top");
+				await fileService.writeFile("/test/conf/a/nested.md", "# Nested");
+				await fileService.writeFile("/test/conf/a/b/deep.md", "# Deep");
+				await fileService.writeFile("/test/conf/build-1/out.md", "# Out");
+				await fileService.writeFile(
+					"/test/conf/node_modules/kept.md",
+					"# Kept",
+				);
+
+				const commandFiles = await detector.scanForCommandFiles("/test/conf");
+
+				// Configured patterns replace the defaults
+				expect(commandFiles).toEqual([
+					"/test/conf/a/nested.md",
+					"/test/conf/node_modules/kept.md",
+					"/test/conf/top.md",
+				]);
+			});
 		});
 	});
 });
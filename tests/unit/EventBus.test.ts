@@ -0,0 +1,40 @@
+import { describe, expect, test } from "bun:test";
+import { EventBus } from "../../src/services/EventBus.js";
+import type { ClaudeCmdEvent } from "../../src/types/Events.js";
+
+describe("EventBus", () => {
+	const event: ClaudeCmdEvent = {
+		type: "installed",
+		command: "debug-help",
+		language: "en",
+		target: "personal",
+		path: "/claude/commands/debug-help.md",
+	};
+
+	test("should deliver events to every listener until unsubscribed", () => {
+		const bus = new EventBus();
+		const first: ClaudeCmdEvent[] = [];
+		const second: ClaudeCmdEvent[] = [];
+		const unsubscribe = bus.subscribe((received) => first.push(received));
+		bus.subscribe((received) => second.push(received));
+
+		bus.emit(event);
+		unsubscribe();
+		bus.emit(event);
+
+		expect(first).toEqual([event]);
+		expect(second).toEqual([event, event]);
+	});
+
+	test("should keep delivering when a listener throws", () => {
+		const bus = new EventBus();
+		const received: ClaudeCmdEvent[] = [];
+		bus.subscribe(() => {
+			throw new Error("listener failed");
+		});
+		bus.subscribe((delivered) => received.push(delivered));
+
+		expect(() => bus.emit(event)).not.toThrow();
+		expect(received).toEqual([event]);
+	});
+});
@@ -0,0 +1,274 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import FileSystemRepository from "../../src/services/FileSystemRepository.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+	ManifestSignatureError,
+} from "../../src/types/Command.js";
+import { createTestSigner } from "../fixtures/minisign.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+describe("FileSystemRepository", () => {
+	let fileService: InMemoryFileService;
+	let commandParser: CommandParser;
+	let repository: FileSystemRepository;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService({
+			"/work/commands/en/manifest.json": JSON.stringify({
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [
+					{
+						name: "debug-help",
+						description: "Help with debugging",
+						file: "debug-help.md",
+						"allowed-tools": ["Read"],
+					},
+				],
+			}),
+			"/work/commands/en/debug-help.md":
+				"---\ndescription: Help with debugging\n---\nDebug",
+			"/work/commands/fr/zeta.md": "---\ndescription: Zeta\n---\nZ",
+			"/work/commands/fr/frontend/component.md":
+				"---\ndescription: Composant\n---\nComposant",
+			"/work/commands/fr/notes.txt": "ignored",
+		});
+		commandParser = new CommandParser(new NamespaceService());
+		repository = new FileSystemRepository(
+			fileService,
+			commandParser,
+			"/work/commands",
+		);
+	});
+
+	describe("fromFileURL", () => {
+		test("should use the nested commands directory of a checkout", async () => {
+			const repo = await FileSystemRepository.fromFileURL(
+				fileService,
+				commandParser,
+				"file:///work",
+			);
+
+			expect(repo.getCommandsDir()).toBe("/work/commands");
+		});
+
+		test("should accept a URL pointing at the commands directory", async () => {
+			const repo = await FileSystemRepository.fromFileURL(
+				fileService,
+				commandParser,
+				"file:///work/commands",
+			);
+
+			expect(repo.getCommandsDir()).toBe("/work/commands");
+		});
+
+		test("should reject non-file URLs", async () => {
+			await expect(
+				FileSystemRepository.fromFileURL(
+					fileService,
+					commandParser,
+					"https://example.com/commands",
+				),
+			).rejects.toThrow("Not a file:// URL");
+		});
+	});
+
+	describe("isFileURL", () => {
+		test("should detect file URLs case-insensitively", () => {
+			expect(FileSystemRepository.isFileURL("file:///tmp/commands")).toBe(true);
+			expect(FileSystemRepository.isFileURL("FILE:///tmp/commands")).toBe(true);
+			expect(FileSystemRepository.isFileURL("https://example.com")).toBe(false);
+		});
+	});
+
+	describe("getManifest", () => {
+		test("should read manifest.json when present", async () => {
+			const manifest = await repository.getManifest("en");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual(["debug-help"]);
+		});
+
+		test("should build manifest from markdown files when manifest.json is missing", async () => {
+			const manifest = await repository.getManifest("fr");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual([
+				"frontend:component",
+				"zeta",
+			]);
+		});
+
+		test("should reflect files changed on disk", async () => {
+			await repository.getManifest("fr");
+			fileService.setFile(
+				"/work/commands/fr/alpha.md",
+				"---\ndescription: Alpha\n---\nA",
+			);
+
+			const manifest = await repository.getManifest("fr");
+
+			expect(manifest.commands.map((c) => c.name)).toContain("alpha");
+		});
+
+		test("should throw ManifestError for invalid manifest.json", async () => {
+			fileService.setFile("/work/commands/en/manifest.json", "{not json");
+
+			await expect(repository.getManifest("en")).rejects.toThrow(
+				ManifestError,
+			);
+		});
+
+		test("should throw ManifestError for a missing language", async () => {
+			await expect(repository.getManifest("de")).rejects.toThrow(
+				ManifestError,
+			);
+		});
+
+		describe("with a public key", () => {
+			const signer = createTestSigner();
+
+			function signedRepository(): FileSystemRepository {
+				return new FileSystemRepository(
+					fileService,
+					commandParser,
+					"/work/commands",
+					signer.publicKey,
+				);
+			}
+
+			test("should accept a manifest with a valid signature", async () => {
+				const body = await fileService.readFile(
+					"/work/commands/en/manifest.json",
+				);
+				fileService.setFile(
+					"/work/commands/en/manifest.json.minisig",
+					signer.sign(body),
+				);
+
+				const manifest = await signedRepository().getManifest("en");
+
+				expect(manifest.commands.map((c) => c.name)).toEqual(["debug-help"]);
+			});
+
+			test("should reject an unsigned manifest", async () => {
+				await expect(signedRepository().getManifest("en")).rejects.toThrow(
+					ManifestSignatureError,
+				);
+			});
+
+			test("should reject a signature from another key", async () => {
+				const body = await fileService.readFile(
+					"/work/commands/en/manifest.json",
+				);
+				fileService.setFile(
+					"/work/commands/en/manifest.json.minisig",
+					createTestSigner(2).sign(body),
+				);
+
+				await expect(signedRepository().getManifest("en")).rejects.toThrow(
+					ManifestSignatureError,
+				);
+			});
+
+			test("should refuse to build a manifest from markdown files", async () => {
+				await expect(signedRepository().getManifest("fr")).rejects.toThrow(
+					ManifestSignatureError,
+				);
+			});
+		});
+	});
+
+	describe("getCommand", () => {
+		test("should read command content from disk", async () => {
+			expect(await repository.getCommand("frontend:component", "fr")).toContain(
+				"Composant",
+			);
+		});
+
+		test("should throw CommandNotFoundError for unknown commands", async () => {
+			await expect(repository.getCommand("missing", "en")).rejects.toThrow(
+				CommandNotFoundError,
+			);
+		});
+
+		test("should throw CommandContentError when the listed file is missing", async () => {
+			await fileService.deleteFile("/work/commands/en/debug-help.md");
+
+			await expect(repository.getCommand("debug-help", "en")).rejects.toThrow(
+				CommandContentError,
+			);
+		});
+	});
+
+	describe("getAbout", () => {
+		test("should return null when no about.json is published", async () => {
+			expect(await repository.getAbout("en")).toBeNull();
+		});
+
+		test("should overlay the language about.json on the root one", async () => {
+			await fileService.writeFile(
+				"/work/commands/about.json",
+				JSON.stringify({
+					title: "Team Commands",
+					description: "Shared commands",
+					homepage: "https://example.com",
+					maintainer: { name: "Platform", email: "platform@example.com" },
+				}),
+			);
+			await fileService.writeFile(
+				"/work/commands/fr/about.json",
+				JSON.stringify({
+					description: "Commandes partagées",
+					maintainer: { url: "https://example.com/fr" },
+				}),
+			);
+
+			expect(await repository.getAbout("en")).toEqual({
+				title: "Team Commands",
+				description: "Shared commands",
+				homepage: "https://example.com",
+				maintainer: { name: "Platform", email: "platform@example.com" },
+			});
+			expect(await repository.getAbout("fr")).toEqual({
+				title: "Team Commands",
+				description: "Commandes partagées",
+				homepage: "https://example.com",
+				maintainer: {
+					name: "Platform",
+					email: "platform@example.com",
+					url: "https://example.com/fr",
+				},
+			});
+		});
+
+		test("should ignore a malformed about.json", async () => {
+			await fileService.writeFile("/work/commands/about.json", "{oops");
+
+			expect(await repository.getAbout("en")).toBeNull();
+		});
+	});
+
+	describe("getAvailableLanguages", () => {
+		test("should list language directories with command counts", async () => {
+			const languages = await repository.getAvailableLanguages();
+
+			expect(languages.map((l) => [l.code, l.commandCount])).toEqual([
+				["fr", 2],
+				["en", 1],
+			]);
+		});
+
+		test("should return an empty list when the directory does not exist", async () => {
+			const missing = new FileSystemRepository(
+				fileService,
+				commandParser,
+				"/nowhere",
+			);
+
+			expect(await missing.getAvailableLanguages()).toEqual([]);
+		});
+	});
+});
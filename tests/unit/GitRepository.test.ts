@@ -0,0 +1,265 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { CacheConfig } from "../../src/interfaces/IRepository.js";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import GitRepository from "../../src/services/GitRepository.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+} from "../../src/types/Command.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryGitClient from "../mocks/InMemoryGitClient.js";
+
+const REPO_URL = "git@github.com:acme/commands.git";
+
+const remoteFiles: Record<string, string> = {
+	"commands/en/manifest.json": JSON.stringify({
+		version: "1.0.0",
+		updated: "2025-01-01T00:00:00Z",
+		commands: [
+			{
+				name: "debug-help",
+				description: "Help with debugging",
+				file: "debug-help.md",
+				"allowed-tools": ["Read"],
+			},
+		],
+	}),
+	"commands/en/debug-help.md": "---\ndescription: Help with debugging\n---\nDebug",
+	"commands/fr/zeta.md": "---\ndescription: Zeta\n---\nZ",
+	"commands/fr/frontend/component.md":
+		"---\ndescription: Créer un composant\n---\nComposant",
+	"commands/fr/alpha.md": "---\ndescription: Alpha\n---\nA",
+	"README.md": "# Commands",
+};
+
+describe("GitRepository", () => {
+	let fileService: InMemoryFileService;
+	let gitClient: InMemoryGitClient;
+	let commandParser: CommandParser;
+	let cacheConfig: CacheConfig;
+	let clock: FakeClock;
+
+	const createRepository = (ref?: string) =>
+		new GitRepository(gitClient, fileService, commandParser, {
+			url: REPO_URL,
+			ref,
+			cacheConfig,
+			clock,
+		});
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		gitClient = new InMemoryGitClient(fileService, remoteFiles);
+		commandParser = new CommandParser(new NamespaceService());
+		cacheConfig = new CacheConfig({ cacheDir: "/cache", ttl: 60000 });
+		clock = new FakeClock();
+	});
+
+	describe("constructor", () => {
+		test("should require a repository URL", () => {
+			expect(
+				() =>
+					new GitRepository(gitClient, fileService, commandParser, {
+						url: "",
+					}),
+			).toThrow("Git repository URL is required");
+		});
+
+		test("should use a distinct working tree per ref", () => {
+			const main = createRepository("main");
+			const tagged = createRepository("v1.0.0");
+
+			expect(main.getWorkTreePath()).toStartWith("/cache/git/");
+			expect(main.getWorkTreePath()).not.toBe(tagged.getWorkTreePath());
+		});
+	});
+
+	describe("getManifest", () => {
+		test("should clone on first use and read manifest.json", async () => {
+			const repository = createRepository();
+
+			const manifest = await repository.getManifest("en");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual(["debug-help"]);
+			expect(gitClient.getHistory()).toEqual([
+				{ operation: "clone", target: REPO_URL, ref: undefined },
+			]);
+		});
+
+		test("should build manifest from markdown files when manifest.json is missing", async () => {
+			const repository = createRepository();
+
+			const manifest = await repository.getManifest("fr");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual([
+				"alpha",
+				"frontend:component",
+				"zeta",
+			]);
+			const component = manifest.commands.find(
+				(c) => c.name === "frontend:component",
+			);
+			expect(component?.file).toBe("frontend/component.md");
+			expect(component?.description).toBe("Créer un composant");
+		});
+
+		test("should not pull again while the working tree is fresh", async () => {
+			const repository = createRepository();
+
+			await repository.getManifest("en");
+			await createRepository().getManifest("en");
+
+			expect(gitClient.getHistory().map((h) => h.operation)).toEqual([
+				"clone",
+			]);
+		});
+
+		test("should pull when forceRefresh is requested", async () => {
+			const repository = createRepository();
+			await repository.getManifest("fr");
+
+			gitClient.setRemoteFiles({
+				...remoteFiles,
+				"commands/fr/beta.md": "---\ndescription: Beta\n---\nB",
+			});
+			const manifest = await repository.getManifest("fr", {
+				forceRefresh: true,
+			});
+
+			expect(gitClient.getHistory().map((h) => h.operation)).toEqual([
+				"clone",
+				"update",
+			]);
+			expect(manifest.commands.map((c) => c.name)).toContain("beta");
+		});
+
+		test("should pull when the working tree is older than the TTL", async () => {
+			await createRepository().getManifest("en");
+
+			clock.advance(59999);
+			await createRepository().getManifest("en");
+			expect(gitClient.getHistory().map((h) => h.operation)).toEqual([
+				"clone",
+			]);
+
+			clock.advance(1);
+			await createRepository().getManifest("en");
+			expect(gitClient.getHistory().map((h) => h.operation)).toEqual([
+				"clone",
+				"update",
+			]);
+		});
+
+		test("should keep using the existing working tree when a pull fails", async () => {
+			await createRepository().getManifest("en");
+			fileService.setFile(
+				Object.keys(fileService.fs).find((p) =>
+					p.endsWith(".sync.json"),
+				) as string,
+				JSON.stringify({ url: REPO_URL, timestamp: 0 }),
+			);
+			gitClient.setFailure("Could not resolve host");
+
+			const manifest = await createRepository().getManifest("en");
+
+			expect(manifest.commands).toHaveLength(1);
+		});
+
+		test("should check out the configured ref", async () => {
+			gitClient.setRemoteFiles(
+				{ "commands/en/pinned.md": "---\ndescription: Pinned\n---\nP" },
+				"v1.0.0",
+			);
+			const repository = createRepository("v1.0.0");
+
+			const manifest = await repository.getManifest("en");
+
+			expect(manifest.commands.map((c) => c.name)).toEqual(["pinned"]);
+			expect(gitClient.getHistory()[0]?.ref).toBe("v1.0.0");
+		});
+
+		test("should throw ManifestError when the clone fails", async () => {
+			gitClient.setFailure("Permission denied (publickey)");
+			const repository = createRepository();
+
+			await expect(repository.getManifest("en")).rejects.toThrow(
+				ManifestError,
+			);
+			await expect(repository.getManifest("en")).rejects.toThrow(
+				"Permission denied (publickey)",
+			);
+		});
+
+		test("should throw ManifestError for a language not in the repository", async () => {
+			await expect(createRepository().getManifest("de")).rejects.toThrow(
+				ManifestError,
+			);
+		});
+
+		test("should reject invalid language codes", async () => {
+			await expect(
+				createRepository().getManifest("../etc"),
+			).rejects.toThrow(ManifestError);
+		});
+	});
+
+	describe("getCommand", () => {
+		test("should read command content from the working tree", async () => {
+			const content = await createRepository().getCommand(
+				"frontend:component",
+				"fr",
+			);
+
+			expect(content).toContain("Composant");
+		});
+
+		test("should throw CommandNotFoundError for unknown commands", async () => {
+			await expect(
+				createRepository().getCommand("missing", "en"),
+			).rejects.toThrow(CommandNotFoundError);
+		});
+
+		test("should reject manifest file paths that escape the language directory", async () => {
+			gitClient.setRemoteFiles({
+				"commands/en/manifest.json": JSON.stringify({
+					version: "1.0.0",
+					updated: "2025-01-01T00:00:00Z",
+					commands: [
+						{
+							name: "evil",
+							description: "Escapes",
+							file: "../../secret.md",
+							"allowed-tools": [],
+						},
+					],
+				}),
+			});
+
+			await expect(
+				createRepository().getCommand("evil", "en"),
+			).rejects.toThrow(CommandContentError);
+		});
+	});
+
+	describe("getAvailableLanguages", () => {
+		test("should return an empty list before the repository is cloned", async () => {
+			expect(await createRepository().getAvailableLanguages()).toEqual([]);
+			expect(gitClient.getHistory()).toEqual([]);
+		});
+
+		test("should list languages from the working tree by command count", async () => {
+			const repository = createRepository();
+			await repository.getManifest("en");
+
+			const languages = await repository.getAvailableLanguages();
+
+			expect(languages.map((l) => [l.code, l.commandCount])).toEqual([
+				["fr", 3],
+				["en", 1],
+			]);
+		});
+	});
+});
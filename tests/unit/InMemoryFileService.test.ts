@@ -179,6 +179,20 @@ describe("InMemoryFileService", () => {
 				);
 			});
 
+			test("should not read pruned directories", async () => {
+				await fileService.writeFile("root/file1.txt", "content1");
+				await fileService.writeFile("root/sub1/deep/file2.txt", "content2");
+				await fileService.writeFile("root/.git/config", "content3");
+				const options = { maxDepth: 1, ignore: [".*"] };
+
+				expect(
+					await fileService.listFilesRecursive("root/", options),
+				).toEqual(["file1.txt"]);
+				expect(
+					(await fileService.listDirectoriesRecursive("root/", options)).sort(),
+				).toEqual([".git", "sub1", "sub1/deep"].sort());
+			});
+
 			test("should handle mixed explicit and implicit directories", async () => {
 				// Create explicit directory
 				await fileService.mkdir("explicit/");
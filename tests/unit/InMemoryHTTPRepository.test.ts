@@ -1,7 +1,21 @@
 import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	HTTPNetworkError,
+	HTTPRateLimitError,
+	HTTPStatusError,
+} from "../../src/interfaces/IHTTPClient.js";
 import { CacheConfig } from "../../src/interfaces/IRepository.js";
-import HTTPRepository from "../../src/services/HTTPRepository.js";
+import HTTPRepository, {
+	type ResponseSizeLimits,
+} from "../../src/services/HTTPRepository.js";
+import {
+	CommandContentError,
+	ManifestError,
+	ManifestSignatureError,
+} from "../../src/types/Command.js";
 import { createClaudeCmdResponses } from "../fixtures/httpResponses.js";
+import { createTestSigner } from "../fixtures/minisign.js";
+import FakeClock from "../mocks/FakeClock.js";
 import InMemoryFileService from "../mocks/InMemoryFileService.js";
 import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
 import { runRepositoryContractTests } from "../shared/IRepository.contract.js";
@@ -98,6 +112,17 @@ describe("HTTPRepository", () => {
 			const httpHistory = mockHttpClient.getRequestHistory();
 			expect(httpHistory).toHaveLength(1);
 		});
+
+		test("should decode manifests however the body is chunked", async () => {
+			const whole = await repository.getManifest("en");
+			mockHttpClient.streamChunkSize = 1;
+
+			const chunked = await repository.getManifest("en", {
+				forceRefresh: true,
+			});
+
+			expect(chunked).toEqual(whole);
+		});
 	});
 
 	describe("getCommand", () => {
@@ -143,6 +168,331 @@ describe("HTTPRepository", () => {
 		});
 	});
 
+	describe("getAbout", () => {
+		const aboutUrl = `${HTTPRepository.BASE_URL}/commands/about.json`;
+		const response = (url: string, body: string) => ({
+			status: 200,
+			statusText: "OK",
+			headers: { "content-type": "application/json" },
+			body,
+			url,
+		});
+
+		test("should return null when the repository publishes no about.json", async () => {
+			expect(await repository.getAbout("en")).toBeNull();
+		});
+
+		test("should fetch and merge root and language metadata", async () => {
+			mockHttpClient.setResponse(
+				aboutUrl,
+				response(aboutUrl, '{"title":"Commands","homepage":"https://x.dev"}'),
+			);
+			const frUrl = `${HTTPRepository.BASE_URL}/commands/fr/about.json`;
+			mockHttpClient.setResponse(
+				frUrl,
+				response(frUrl, '{"title":"Commandes"}'),
+			);
+
+			expect(await repository.getAbout("fr")).toEqual({
+				title: "Commandes",
+				homepage: "https://x.dev",
+			});
+		});
+
+		test("should cache metadata between calls", async () => {
+			mockHttpClient.setResponse(
+				aboutUrl,
+				response(aboutUrl, '{"title":"Commands"}'),
+			);
+
+			await repository.getAbout("en");
+			mockHttpClient.clearRequestHistory();
+			expect(await repository.getAbout("en")).toEqual({ title: "Commands" });
+			expect(mockHttpClient.getRequestHistory()).toEqual([]);
+		});
+
+		test("should return null without caching on network failure", async () => {
+			mockHttpClient.setResponse(
+				aboutUrl,
+				new HTTPNetworkError(aboutUrl, "offline"),
+			);
+			expect(await repository.getAbout("en")).toBeNull();
+
+			mockHttpClient.setResponse(
+				aboutUrl,
+				response(aboutUrl, '{"title":"Commands"}'),
+			);
+			expect(await repository.getAbout("en")).toEqual({ title: "Commands" });
+		});
+	});
+
+	describe("getAvailableLanguages", () => {
+		const languagesUrl = `${HTTPRepository.BASE_URL}/commands/languages.json`;
+		const publish = (body: string) =>
+			mockHttpClient.setResponse(languagesUrl, {
+				status: 200,
+				statusText: "OK",
+				headers: { "content-type": "application/json" },
+				body,
+				url: languagesUrl,
+			});
+
+		test("should list published languages before their manifests are cached", async () => {
+			publish('{"languages":[{"code":"uk","name":"Українська"},"fr"]}');
+
+			expect(await repository.getAvailableLanguages()).toEqual([
+				{ code: "fr", name: "Français", commandCount: 0 },
+				{ code: "uk", name: "Українська", commandCount: 0 },
+			]);
+		});
+
+		test("should merge published languages with cached manifests", async () => {
+			publish('["en", "uk"]');
+			const manifest = await repository.getManifest("en");
+
+			expect(await repository.getAvailableLanguages()).toEqual([
+				{
+					code: "en",
+					name: "English",
+					commandCount: manifest.commands.length,
+				},
+				{ code: "uk", name: "UK", commandCount: 0 },
+			]);
+		});
+
+		test("should cache the published index", async () => {
+			publish('["uk"]');
+
+			await repository.getAvailableLanguages();
+			mockHttpClient.clearRequestHistory();
+			await repository.getAvailableLanguages();
+
+			expect(
+				mockHttpClient
+					.getRequestHistory()
+					.filter((request) => request.url === languagesUrl),
+			).toEqual([]);
+		});
+
+		test("should fall back to cached manifests when no index is published", async () => {
+			await repository.getManifest("fr");
+
+			const codes = (await repository.getAvailableLanguages()).map(
+				(language) => language.code,
+			);
+			expect(codes).toEqual(["fr"]);
+		});
+	});
+
+	describe("custom base URL", () => {
+		const baseUrl = "https://example.com/team";
+
+		test("should request manifests below the configured URL", async () => {
+			const custom = new HTTPRepository(
+				mockHttpClient,
+				mockFileService,
+				defaultCacheConfig,
+				undefined,
+				`${baseUrl}/`,
+			);
+
+			await custom.getManifest("en");
+
+			expect(mockHttpClient.getRequestHistory().map((r) => r.url)).toContain(
+				`${baseUrl}/commands/en/manifest.json`,
+			);
+		});
+
+		test("should cache apart from the default repository", async () => {
+			const custom = new HTTPRepository(
+				mockHttpClient,
+				mockFileService,
+				defaultCacheConfig,
+				undefined,
+				baseUrl,
+			);
+
+			await custom.getManifest("en");
+
+			expect(
+				await mockFileService.exists(
+					`${defaultCacheConfig.cacheDir}/manifest-en.json`,
+				),
+			).toBe(false);
+			expect(await repository.getAvailableLanguages()).toEqual([]);
+		});
+	});
+
+	describe("signed manifests", () => {
+		const manifestUrl = `${HTTPRepository.BASE_URL}/commands/en/manifest.json`;
+		const signer = createTestSigner();
+		const signed = () =>
+			new HTTPRepository(
+				mockHttpClient,
+				mockFileService,
+				defaultCacheConfig,
+				undefined,
+				undefined,
+				signer.publicKey,
+			);
+		const publishSignature = (signature: string) => {
+			const url = `${manifestUrl}.minisig`;
+			mockHttpClient.setResponse(url, {
+				status: 200,
+				statusText: "OK",
+				headers: {},
+				body: signature,
+				url,
+			});
+		};
+		const manifestBody = async () =>
+			(await mockHttpClient.get(manifestUrl)).body;
+
+		test("should accept manifests with a valid signature", async () => {
+			publishSignature(signer.sign(await manifestBody()));
+
+			const manifest = await signed().getManifest("en");
+
+			expect(manifest.commands.length).toBeGreaterThan(0);
+		});
+
+		test("should verify manifests streamed in small chunks", async () => {
+			publishSignature(signer.sign(await manifestBody()));
+			mockHttpClient.streamChunkSize = 3;
+
+			const manifest = await signed().getManifest("en");
+
+			expect(manifest.commands.length).toBeGreaterThan(0);
+		});
+
+		test("should refuse unsigned manifests", async () => {
+			const url = `${manifestUrl}.minisig`;
+			mockHttpClient.setResponse(
+				url,
+				new HTTPStatusError(url, 404, "Not Found"),
+			);
+
+			await expect(signed().getManifest("en")).rejects.toThrow(
+				"manifest is not signed",
+			);
+		});
+
+		test("should refuse manifests signed by another key", async () => {
+			publishSignature(createTestSigner(2).sign(await manifestBody()));
+
+			await expect(signed().getManifest("en")).rejects.toThrow(
+				ManifestSignatureError,
+			);
+		});
+
+		test("should not reuse manifests cached without verification", async () => {
+			await repository.getManifest("en");
+
+			await expect(signed().getManifest("en")).rejects.toThrow(
+				ManifestSignatureError,
+			);
+		});
+	});
+
+	describe("rate limits", () => {
+		const manifestUrl = `${HTTPRepository.BASE_URL}/commands/en/manifest.json`;
+		let clock: FakeClock;
+		let limited: HTTPRepository;
+
+		beforeEach(() => {
+			clock = new FakeClock();
+			limited = new HTTPRepository(
+				mockHttpClient,
+				mockFileService,
+				defaultCacheConfig,
+				clock,
+			);
+		});
+
+		const rateLimit = () =>
+			mockHttpClient.setResponse(
+				manifestUrl,
+				new HTTPRateLimitError(
+					manifestUrl,
+					429,
+					"Too Many Requests",
+					clock.now() + 60000,
+				),
+			);
+
+		test("should serve an expired manifest while rate-limited", async () => {
+			const cached = await limited.getManifest("en");
+			clock.advance(defaultCacheConfig.ttl + 1);
+			rateLimit();
+
+			expect(await limited.getManifest("en")).toEqual(cached);
+		});
+
+		test("should serve the cache when force-refreshing", async () => {
+			const cached = await limited.getManifest("en");
+			rateLimit();
+
+			expect(await limited.getManifest("en", { forceRefresh: true })).toEqual(
+				cached,
+			);
+		});
+
+		test("should report the retry time without a cache", async () => {
+			rateLimit();
+
+			const error = await limited.getManifest("en").catch((e) => e);
+
+			expect(error).toBeInstanceOf(ManifestError);
+			expect(error.retryAt).toBe(clock.now() + 60000);
+			expect(error.message).toContain("rate-limiting");
+		});
+
+		test("should not serve expired data for other failures", async () => {
+			await limited.getManifest("en");
+			clock.advance(defaultCacheConfig.ttl + 1);
+			mockHttpClient.setResponse(
+				manifestUrl,
+				new HTTPNetworkError(manifestUrl, "Connection refused"),
+			);
+
+			await expect(limited.getManifest("en")).rejects.toThrow(ManifestError);
+		});
+	});
+
+	describe("response size limits", () => {
+		const small = (limits: ResponseSizeLimits) =>
+			new HTTPRepository(
+				mockHttpClient,
+				mockFileService,
+				defaultCacheConfig,
+				undefined,
+				undefined,
+				undefined,
+				limits,
+			);
+
+		test("should refuse manifests over the limit", async () => {
+			await expect(
+				small({ maxManifestBytes: 10 }).getManifest("en"),
+			).rejects.toThrow("larger than the limit of 10 bytes");
+		});
+
+		test("should refuse command files over the limit", async () => {
+			const error = await small({ maxCommandBytes: 10 })
+				.getCommand("debug-help", "en")
+				.catch((e) => e);
+
+			expect(error).toBeInstanceOf(CommandContentError);
+			expect(error.message).toContain("maxCommandBytes");
+		});
+
+		test("should accept responses within the defaults", async () => {
+			const content = await repository.getCommand("debug-help", "en");
+
+			expect(content.length).toBeGreaterThan(0);
+		});
+	});
+
 	describe("dependency injection", () => {
 		test("should use injected HTTPClient for all network operations", async () => {
 			await repository.getManifest("en");
@@ -1,4 +1,6 @@
 import { beforeEach, describe, expect, test } from "bun:test";
+import { dirname, join } from "node:path";
+import { createHash } from "node:crypto";
 import { CommandParser } from "../../src/services/CommandParser.js";
 import { DirectoryDetector } from "../../src/services/DirectoryDetector.js";
 import {
@@ -9,7 +11,18 @@ import {
 } from "../../src/services/InstallationService.js";
 import { LocalCommandRepository } from "../../src/services/LocalCommandRepository.js";
 import NamespaceService from "../../src/services/NamespaceService.js";
+import { TransactionJournal } from "../../src/services/TransactionJournal.js";
+import { TrashService } from "../../src/services/TrashService.js";
 import type { Command } from "../../src/types/Command.js";
+import {
+	CommandPinnedError,
+	VersionNotAvailableError,
+} from "../../src/types/Installation.js";
+import {
+	readProvenance,
+	stripProvenance,
+} from "../../src/utils/frontmatter.js";
+import { extractTarGz } from "../../src/utils/tar.js";
 import InMemoryFileService from "../mocks/InMemoryFileService.js";
 import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
 import InMemoryRepository from "../mocks/InMemoryRepository.js";
@@ -106,6 +119,24 @@ Use this command when you need help with debugging.
 			expect(installedContent).toBe(mockCommandContent);
 		});
 
+		test("should record provenance in the frontmatter when asked", async () => {
+			await installationService.installCommand("test-command", {
+				provenanceSource: "https://example.com/commands",
+			});
+
+			const installedContent = await fileService.readFile(
+				"/home/testuser/.claude/commands/test-command.md",
+			);
+			expect(readProvenance(installedContent)).toMatchObject({
+				source: "https://example.com/commands",
+				version: "1.0.0",
+				sha256: createHash("sha256")
+					.update(mockCommandContent, "utf8")
+					.digest("hex"),
+			});
+			expect(stripProvenance(installedContent)).toBe(mockCommandContent);
+		});
+
 		test("should create directory if it doesn't exist", async () => {
 			// Ensure directory doesn't exist initially
 			const personalDir = "/home/testuser/.claude/commands";
@@ -150,6 +181,30 @@ Use this command when you need help with debugging.
 			expect(installedContent).toBe(newContent);
 		});
 
+		test("should install a language variant under its local name", async () => {
+			const frenchContent = mockCommandContent.replace("Test", "Test (fr)");
+			repository.setManifest("fr", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [mockCommand],
+			});
+			repository.setCommand("test-command", "fr", frenchContent);
+
+			await installationService.installCommand("test-command");
+			await installationService.installCommand("test-command", {
+				language: "fr",
+				installAs: "test-command-fr",
+			});
+
+			const basePath = "/home/testuser/.claude/commands";
+			expect(await fileService.readFile(`${basePath}/test-command.md`)).toBe(
+				mockCommandContent,
+			);
+			expect(await fileService.readFile(`${basePath}/test-command-fr.md`)).toBe(
+				frenchContent,
+			);
+		});
+
 		test("should throw InstallationError for command not in repository", async () => {
 			await expect(
 				installationService.installCommand("nonexistent-command"),
@@ -180,6 +235,149 @@ allowed-tools: [invalid yaml structure
 		});
 	});
 
+	describe("version pinning", () => {
+		const lockfilePath = "/home/testuser/.claude/claude-cmd.lock.json";
+		const readLock = async () =>
+			JSON.parse(await fileService.readFile(lockfilePath)).commands;
+		const publish = (version: string) =>
+			repository.setManifest("en", {
+				version,
+				updated: "2025-01-01T00:00:00Z",
+				commands: [mockCommand],
+			});
+
+		test("should record the installed version in the lockfile", async () => {
+			await installationService.installCommand("test-command");
+
+			const entry = (await readLock())["test-command"];
+			expect(entry.version).toBe("1.0.0");
+			expect(entry.language).toBe("en");
+			expect(entry.sha256).toMatch(/^[0-9a-f]{64}$/);
+			expect(entry.pinned).toBeUndefined();
+		});
+
+		test("should forget the entry of a command overwritten by import", async () => {
+			await installationService.installCommand("test-command");
+
+			await installationService.importCommand("test-command", "local", {
+				force: true,
+			});
+
+			expect((await readLock())["test-command"]).toBeUndefined();
+		});
+
+		test("should reject versions the repository does not provide", async () => {
+			await expect(
+				installationService.installCommand("test-command", {
+					version: "0.9.0",
+				}),
+			).rejects.toBeInstanceOf(VersionNotAvailableError);
+			expect(
+				await fileService.exists(
+					"/home/testuser/.claude/commands/test-command.md",
+				),
+			).toBe(false);
+		});
+
+		test("should keep pinned commands on their version until re-pinned", async () => {
+			await installationService.installCommand("test-command", {
+				version: "1.0.0",
+			});
+			publish("2.0.0");
+
+			await expect(
+				installationService.installCommand("test-command", { force: true }),
+			).rejects.toBeInstanceOf(CommandPinnedError);
+
+			await installationService.installCommand("test-command", {
+				force: true,
+				version: "2.0.0",
+			});
+			expect((await readLock())["test-command"]).toMatchObject({
+				version: "2.0.0",
+				pinned: true,
+			});
+
+			const info = await installationService.getInstallationInfo("test-command");
+			expect(info?.pinned).toBe(true);
+			expect(info?.version).toBe("2.0.0");
+		});
+
+		test("should forget removed commands", async () => {
+			await installationService.installCommand("test-command");
+			await installationService.removeCommand("test-command", { yes: true });
+
+			expect(await readLock()).toEqual({});
+		});
+
+		test("should carry the entry over when moving a command", async () => {
+			await installationService.installCommand("test-command", {
+				version: "1.0.0",
+			});
+			await installationService.moveCommand("test-command", "renamed");
+
+			const lock = await readLock();
+			expect(lock["test-command"]).toBeUndefined();
+			expect(lock.renamed.pinned).toBe(true);
+		});
+	});
+
+	describe("getMissingDependencies", () => {
+		const commandWith = (name: string, requires?: string[]): Command => ({
+			...mockCommand,
+			name,
+			file: `${name}.md`,
+			...(requires ? { requires } : {}),
+		});
+
+		test("should list uninstalled requirements in install order", async () => {
+			repository.setManifest("en", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [
+					commandWith("review", ["lint", "test-command"]),
+					commandWith("lint", ["format"]),
+					commandWith("format", []),
+					mockCommand,
+				],
+			});
+			await installationService.installCommand("test-command");
+
+			expect(
+				await installationService.getMissingDependencies("review"),
+			).toEqual(["format", "lint"]);
+		});
+
+		test("should read requirements from frontmatter when the manifest has none", async () => {
+			repository.setManifest("en", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [commandWith("review"), commandWith("lint", [])],
+			});
+			repository.setCommand(
+				"review",
+				"en",
+				"---\ndescription: Review\nrequires: [lint]\n---\n\n# Review\n",
+			);
+
+			expect(
+				await installationService.getMissingDependencies("review"),
+			).toEqual(["lint"]);
+		});
+
+		test("should reject circular requirements", async () => {
+			repository.setManifest("en", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [commandWith("a", ["b"]), commandWith("b", ["a"])],
+			});
+
+			await expect(
+				installationService.getMissingDependencies("a"),
+			).rejects.toThrow("Circular command requirements: a -> b -> a");
+		});
+	});
+
 	describe("removeCommand", () => {
 		beforeEach(async () => {
 			// Install a command for removal tests
@@ -219,6 +417,424 @@ allowed-tools: [invalid yaml structure
 			expect(await fileService.exists(personalPath)).toBe(false);
 			expect(await fileService.exists(projectPath)).toBe(true);
 		});
+
+		test("should remove the copy in the requested location", async () => {
+			await installationService.installCommand("test-command", {
+				target: "project",
+			});
+
+			const personalPath = "/home/testuser/.claude/commands/test-command.md";
+			const projectPath = ".claude/commands/test-command.md";
+
+			await installationService.removeCommand("test-command", {
+				yes: true,
+				location: "project",
+			});
+
+			expect(await fileService.exists(personalPath)).toBe(true);
+			expect(await fileService.exists(projectPath)).toBe(false);
+		});
+	});
+
+	describe("restoreCommand", () => {
+		const personalPath = "/home/testuser/.claude/commands/test-command.md";
+		let trashService: TrashService;
+		let service: InstallationService;
+
+		beforeEach(async () => {
+			trashService = new TrashService(fileService, "/cache/trash");
+			const directoryDetector = new DirectoryDetector(fileService);
+			const commandParser = new CommandParser(new NamespaceService());
+			service = new InstallationService(
+				repository,
+				fileService,
+				directoryDetector,
+				commandParser,
+				new LocalCommandRepository(directoryDetector, commandParser),
+				userInteractionService,
+				undefined,
+				undefined,
+				trashService,
+			);
+			await service.installCommand("test-command");
+		});
+
+		test("should move removed commands to the trash and restore them", async () => {
+			await service.removeCommand("test-command", { yes: true });
+			expect(await fileService.exists(personalPath)).toBe(false);
+			expect((await trashService.list()).map((entry) => entry.name)).toEqual([
+				"test-command",
+			]);
+
+			const entry = await service.restoreCommand("test-command");
+
+			expect(entry.originalPath).toBe(personalPath);
+			expect(await fileService.readFile(personalPath)).toBe(mockCommandContent);
+			expect(await trashService.list()).toEqual([]);
+		});
+
+		test("should restore the lockfile entry with the command", async () => {
+			const lockfilePath = "/home/testuser/.claude/claude-cmd.lock.json";
+			const readEntry = async () =>
+				JSON.parse(await fileService.readFile(lockfilePath)).commands[
+					"test-command"
+				];
+			const installed = await readEntry();
+
+			await service.removeCommand("test-command", { yes: true });
+			expect(await readEntry()).toBeUndefined();
+
+			await service.restoreCommand("test-command");
+			expect(await readEntry()).toEqual(installed);
+		});
+
+		test("should delete permanently when purging", async () => {
+			await service.removeCommand("test-command", { yes: true, purge: true });
+
+			expect(await trashService.list()).toEqual([]);
+			await expect(service.restoreCommand("test-command")).rejects.toThrow(
+				"Command 'test-command' is not in the trash",
+			);
+		});
+
+		test("should not overwrite a reinstalled command unless forced", async () => {
+			await service.removeCommand("test-command", { yes: true });
+			await service.installCommand("test-command");
+
+			await expect(service.restoreCommand("test-command")).rejects.toThrow(
+				CommandExistsError,
+			);
+			expect(await trashService.list()).toHaveLength(1);
+
+			await service.restoreCommand("test-command", { force: true });
+			expect(await trashService.list()).toEqual([]);
+		});
+
+		test("should keep the command in the trash if writing it back fails", async () => {
+			await service.removeCommand("test-command", { yes: true });
+			// A directory in the way makes the write fail
+			await fileService.mkdir(personalPath);
+
+			await expect(
+				service.restoreCommand("test-command", { force: true }),
+			).rejects.toThrow(InstallationError);
+			expect((await trashService.list()).map((entry) => entry.name)).toEqual([
+				"test-command",
+			]);
+		});
+	});
+
+	describe("moveCommand", () => {
+		const personalDir = "/home/testuser/.claude/commands";
+
+		beforeEach(async () => {
+			await installationService.installCommand("test-command");
+		});
+
+		test("should rename a command within its location", async () => {
+			const result = await installationService.moveCommand(
+				"test-command",
+				"renamed",
+			);
+
+			expect(result).toEqual({
+				fromPath: `${personalDir}/test-command.md`,
+				toPath: `${personalDir}/renamed.md`,
+				location: "personal",
+			});
+			expect(await fileService.exists(result.fromPath)).toBe(false);
+			expect(await fileService.readFile(result.toPath)).toBe(
+				mockCommandContent,
+			);
+		});
+
+		test("should move between namespaces and clean up empty directories", async () => {
+			await installationService.moveCommand("test-command", "tools:debug");
+			await installationService.moveCommand("tools:debug", "other:debug");
+
+			expect(await fileService.exists(`${personalDir}/other/debug.md`)).toBe(
+				true,
+			);
+			expect(await fileService.exists(`${personalDir}/tools`)).toBe(false);
+		});
+
+		test("should keep empty directories when requested", async () => {
+			await installationService.moveCommand("test-command", "tools:debug");
+			await installationService.moveCommand("tools:debug", "debug", {
+				keepEmptyDirectories: true,
+			});
+
+			expect(await fileService.exists(`${personalDir}/debug.md`)).toBe(true);
+			expect(await fileService.exists(`${personalDir}/tools`)).toBe(true);
+		});
+
+		test("should move from personal to project", async () => {
+			const result = await installationService.moveCommand(
+				"test-command",
+				"test-command",
+				{ target: "project" },
+			);
+
+			expect(result.toPath).toBe(".claude/commands/test-command.md");
+			expect(result.location).toBe("project");
+			expect(await fileService.exists(`${personalDir}/test-command.md`)).toBe(
+				false,
+			);
+		});
+
+		test("should refuse to overwrite an existing command without force", async () => {
+			await fileService.writeFile(`${personalDir}/taken.md`, "existing");
+
+			await expect(
+				installationService.moveCommand("test-command", "taken"),
+			).rejects.toThrow(CommandExistsError);
+
+			await installationService.moveCommand("test-command", "taken", {
+				force: true,
+			});
+			expect(await fileService.readFile(`${personalDir}/taken.md`)).toBe(
+				mockCommandContent,
+			);
+		});
+
+		test("should reject moving a command onto itself", async () => {
+			await expect(
+				installationService.moveCommand("test-command", "test-command"),
+			).rejects.toThrow(InstallationError);
+		});
+
+		test("should reject unsafe destination names", async () => {
+			await expect(
+				installationService.moveCommand("test-command", "../escape"),
+			).rejects.toThrow(InstallationError);
+			expect(await fileService.exists(`${personalDir}/test-command.md`)).toBe(
+				true,
+			);
+		});
+
+		test("should throw CommandNotInstalledError for unknown commands", async () => {
+			await expect(
+				installationService.moveCommand("missing", "other"),
+			).rejects.toThrow(CommandNotInstalledError);
+		});
+
+		test("should rewrite the frontmatter name when requested", async () => {
+			await fileService.writeFile(
+				`${personalDir}/named.md`,
+				"---\nname: named\ndescription: Named\n---\nBody",
+			);
+
+			const result = await installationService.moveCommand(
+				"named",
+				"tools:named",
+				{ rewriteName: true },
+			);
+
+			expect(await fileService.readFile(result.toPath)).toBe(
+				'---\nname: "tools:named"\ndescription: Named\n---\nBody',
+			);
+		});
+	});
+
+	describe("copyCommand", () => {
+		const personalPath = "/home/testuser/.claude/commands/test-command.md";
+		const projectPath = ".claude/commands/test-command.md";
+
+		test("should copy a personal command into the project directory", async () => {
+			await installationService.installCommand("test-command");
+
+			const result = await installationService.copyCommand("test-command", {
+				to: "project",
+			});
+
+			expect(result).toEqual({
+				fromPath: personalPath,
+				toPath: projectPath,
+				location: "project",
+			});
+			expect(await fileService.readFile(projectPath)).toBe(mockCommandContent);
+			expect(await fileService.exists(personalPath)).toBe(true);
+		});
+
+		test("should copy a project command into the personal directory", async () => {
+			await installationService.installCommand("test-command", {
+				target: "project",
+			});
+
+			const result = await installationService.copyCommand("test-command", {
+				to: "personal",
+			});
+
+			expect(result.toPath).toBe(personalPath);
+			expect(await fileService.readFile(personalPath)).toBe(mockCommandContent);
+		});
+
+		test("should refuse to overwrite without force", async () => {
+			await installationService.installCommand("test-command");
+			await fileService.writeFile(projectPath, "project version");
+
+			await expect(
+				installationService.copyCommand("test-command", { to: "project" }),
+			).rejects.toThrow(CommandExistsError);
+			expect(await fileService.readFile(projectPath)).toBe("project version");
+
+			await installationService.copyCommand("test-command", {
+				to: "project",
+				force: true,
+			});
+			expect(await fileService.readFile(projectPath)).toBe(mockCommandContent);
+		});
+
+		test("should throw CommandNotInstalledError when the source location lacks the command", async () => {
+			await installationService.installCommand("test-command", {
+				target: "project",
+			});
+
+			await expect(
+				installationService.copyCommand("test-command", { to: "project" }),
+			).rejects.toThrow(CommandNotInstalledError);
+		});
+	});
+
+	describe("importCommand", () => {
+		test("should write the content into the target directory", async () => {
+			const filePath = await installationService.importCommand(
+				"frontend:review",
+				mockCommandContent,
+				{ target: "project" },
+			);
+
+			expect(filePath).toBe(".claude/commands/frontend/review.md");
+			expect(await fileService.readFile(filePath)).toBe(mockCommandContent);
+		});
+
+		test("should refuse to overwrite without force", async () => {
+			const filePath = await installationService.importCommand(
+				"review",
+				"old",
+			);
+
+			await expect(
+				installationService.importCommand("review", mockCommandContent),
+			).rejects.toThrow(CommandExistsError);
+			expect(await fileService.readFile(filePath)).toBe("old");
+
+			await installationService.importCommand("review", mockCommandContent, {
+				force: true,
+			});
+			expect(await fileService.readFile(filePath)).toBe(mockCommandContent);
+		});
+	});
+
+	describe("exportCommands", () => {
+		beforeEach(async () => {
+			await installationService.installCommand("test-command", {
+				provenanceSource: "https://example.com/commands",
+			});
+			await installationService.importCommand(
+				"frontend:review",
+				mockCommandContent,
+				{ target: "project" },
+			);
+		});
+
+		test("should keep personal and project commands apart", async () => {
+			const result = await installationService.exportCommands("/backup");
+
+			expect(result).toEqual({
+				path: "/backup",
+				format: "directory",
+				commandCount: 2,
+			});
+			expect(
+				await fileService.readFile("/backup/personal/test-command.md"),
+			).toBe(mockCommandContent);
+			expect(
+				await fileService.readFile("/backup/project/frontend/review.md"),
+			).toBe(mockCommandContent);
+		});
+
+		test("should write a tar.gz archive of one scope", async () => {
+			const result = await installationService.exportCommands(
+				"/backup.tar.gz",
+				{ scope: "project" },
+			);
+
+			expect(result.format).toBe("archive");
+			expect(
+				extractTarGz(await fileService.readBinaryFile("/backup.tar.gz")),
+			).toEqual([{ path: "frontend/review.md", content: mockCommandContent }]);
+		});
+
+		test("should refuse to overwrite without force", async () => {
+			await fileService.writeFile("/backup.tgz", "existing");
+
+			await expect(
+				installationService.exportCommands("/backup.tgz"),
+			).rejects.toThrow(InstallationError);
+			expect(await fileService.readFile("/backup.tgz")).toBe("existing");
+
+			await installationService.exportCommands("/backup.tgz", { force: true });
+			expect(
+				extractTarGz(await fileService.readBinaryFile("/backup.tgz")),
+			).toHaveLength(2);
+		});
+	});
+
+	describe("empty namespace cleanup", () => {
+		const personalDir = "/home/testuser/.claude/commands";
+
+		beforeEach(async () => {
+			repository.setManifest("en", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [{ ...mockCommand, name: "frontend:react:component" }],
+			});
+			repository.setCommand(
+				"frontend:react:component",
+				"en",
+				mockCommandContent,
+			);
+			await installationService.installCommand("frontend:react:component");
+		});
+
+		test("should remove namespace directories left empty", async () => {
+			await installationService.removeCommand("frontend:react:component", {
+				yes: true,
+			});
+
+			expect(await fileService.exists(`${personalDir}/frontend`)).toBe(false);
+			expect(await fileService.exists(personalDir)).toBe(true);
+		});
+
+		test("should keep namespace directories that still have commands", async () => {
+			await fileService.writeFile(
+				`${personalDir}/frontend/other.md`,
+				mockCommandContent,
+			);
+
+			await installationService.removeCommand("frontend:react:component", {
+				yes: true,
+			});
+
+			expect(await fileService.exists(`${personalDir}/frontend/react`)).toBe(
+				false,
+			);
+			expect(await fileService.exists(`${personalDir}/frontend/other.md`)).toBe(
+				true,
+			);
+		});
+
+		test("should keep empty directories when requested", async () => {
+			await installationService.removeCommand("frontend:react:component", {
+				yes: true,
+				keepEmptyDirectories: true,
+			});
+
+			expect(await fileService.exists(`${personalDir}/frontend/react`)).toBe(
+				true,
+			);
+		});
 	});
 
 	describe("listInstalledCommands", () => {
@@ -423,6 +1039,17 @@ allowed-tools: [invalid yaml
 
 			expect(info?.location).toBe("project");
 			expect(info?.filePath).toBe(".claude/commands/test-command.md");
+			expect(info?.projectRoot).toBe(process.cwd());
+		});
+
+		test("should leave the project root out for personal commands", async () => {
+			await installationService.installCommand("test-command");
+
+			const found =
+				await installationService.findInstalledCommand("test-command");
+
+			expect(found?.location).toBe("personal");
+			expect(found?.projectRoot).toBeUndefined();
 		});
 	});
 
@@ -478,6 +1105,127 @@ allowed-tools: [invalid yaml
 		});
 	});
 
+	describe("nested project roots", () => {
+		let outerDir: string;
+		const lockedNames = async (commandsDir: string) => {
+			const lockfilePath = join(dirname(commandsDir), "claude-cmd.lock.json");
+			const { commands } = JSON.parse(await fileService.readFile(lockfilePath));
+			return Object.keys(commands);
+		};
+		const serviceFor = (directoryDetector: DirectoryDetector) => {
+			const commandParser = new CommandParser(new NamespaceService());
+			return new InstallationService(
+				repository,
+				fileService,
+				directoryDetector,
+				commandParser,
+				new LocalCommandRepository(directoryDetector, commandParser),
+				userInteractionService,
+			);
+		};
+
+		beforeEach(async () => {
+			// Installed from the enclosing project, then used from a nested one
+			const outer = new DirectoryDetector(fileService, undefined, "/work");
+			outerDir = await outer.getProjectDirectory();
+			await serviceFor(outer).installCommand("test-command", {
+				target: "project",
+			});
+			installationService = serviceFor(
+				new DirectoryDetector(fileService, undefined, "/work/app", undefined, [
+					"/work",
+				]),
+			);
+		});
+
+		test("should move a command within the enclosing project's lockfile", async () => {
+			const result = await installationService.moveCommand(
+				"test-command",
+				"renamed",
+			);
+
+			expect(result.toPath).toBe(join(outerDir, "renamed.md"));
+			expect(await lockedNames(outerDir)).toEqual(["renamed"]);
+		});
+
+		test("should copy the lockfile entry from the enclosing project", async () => {
+			await installationService.copyCommand("test-command", { to: "personal" });
+
+			expect(await lockedNames("/home/testuser/.claude/commands")).toEqual([
+				"test-command",
+			]);
+			expect(await lockedNames(outerDir)).toEqual(["test-command"]);
+		});
+	});
+
+	describe("journaled changes", () => {
+		const commandPath = "/home/testuser/.claude/commands/test-command.md";
+		const lockfilePath = "/home/testuser/.claude/claude-cmd.lock.json";
+		let journal: TransactionJournal;
+
+		beforeEach(() => {
+			journal = new TransactionJournal(fileService, "/journal");
+			const directoryDetector = new DirectoryDetector(fileService);
+			const commandParser = new CommandParser(new NamespaceService());
+			installationService = new InstallationService(
+				repository,
+				fileService,
+				directoryDetector,
+				commandParser,
+				new LocalCommandRepository(directoryDetector, commandParser),
+				userInteractionService,
+				undefined,
+				undefined,
+				undefined,
+				undefined,
+				undefined,
+				journal,
+			);
+		});
+
+		test("should write the command and its lockfile entry together", async () => {
+			await installationService.installCommand("test-command");
+
+			expect(await fileService.readFile(commandPath)).toBe(mockCommandContent);
+			expect(await fileService.readFile(lockfilePath)).toContain(
+				'"test-command"',
+			);
+			expect(await journal.listPending()).toEqual([]);
+		});
+
+		test("should leave an interrupted install recoverable", async () => {
+			const rename = fileService.rename.bind(fileService);
+			fileService.rename = async (from, to) => {
+				if (to === lockfilePath) {
+					throw new Error("simulated crash");
+				}
+				return rename(from, to);
+			};
+
+			await expect(
+				installationService.installCommand("test-command"),
+			).rejects.toThrow(InstallationError);
+			fileService.rename = rename;
+			const [pending] = await journal.listPending();
+
+			expect(pending?.paths).toEqual([commandPath, lockfilePath]);
+			await journal.rollback(pending?.id ?? "");
+			expect(await fileService.exists(commandPath)).toBe(false);
+		});
+
+		test("should move the command and its lockfile entry together", async () => {
+			await installationService.installCommand("test-command");
+
+			await installationService.moveCommand("test-command", "renamed");
+
+			const lockfile = await fileService.readFile(lockfilePath);
+			expect(lockfile).toContain('"renamed"');
+			expect(lockfile).not.toContain('"test-command"');
+			expect(await fileService.exists(commandPath)).toBe(false);
+			expect(await journal.listPending()).toEqual([]);
+		});
+	});
+
 	describe("namespace-aware command discovery", () => {
 		beforeEach(async () => {
 			// Set up directory structure for namespace tests
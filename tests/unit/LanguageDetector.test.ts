@@ -218,6 +218,76 @@ describe("LanguageDetector", () => {
 		});
 	});
 
+	describe("global CLI flag", () => {
+		const context: DetectionContext = {
+			cliFlag: "",
+			envVar: "es",
+			projectConfig: "ja",
+			userConfig: "de",
+			posixLocale: "de_DE.UTF-8",
+		};
+
+		it("should be empty by default", () => {
+			expect(detector.getCliFlag()).toBe("");
+			expect(detector.detect(context)).toBe("es");
+		});
+
+		it("should override every other source once set", () => {
+			detector.setCliFlag("fr");
+
+			expect(detector.getCliFlag()).toBe("fr");
+			expect(detector.detect(context)).toBe("fr");
+		});
+
+		it("should yield to an explicit context cliFlag", () => {
+			detector.setCliFlag("fr");
+
+			expect(detector.detect({ ...context, cliFlag: "ko" })).toBe("ko");
+		});
+
+		it("should stop applying once cleared", () => {
+			detector.setCliFlag("fr");
+			detector.setCliFlag("");
+
+			expect(detector.detect(context)).toBe("es");
+		});
+	});
+
+	describe("resolve method", () => {
+		it("should skip invalid values and report the source that won", () => {
+			const result = detector.resolve({
+				cliFlag: "",
+				envVar: "not a language",
+				userConfig: "fr",
+				posixLocale: "C",
+			});
+
+			expect(result.language).toBe("fr");
+			expect(result.source).toBe("user");
+			expect(result.sources.env).toBe("not a language");
+		});
+
+		it("should fall back to the default when the locale cannot be parsed", () => {
+			const result = detector.resolve({
+				cliFlag: "",
+				envVar: "",
+				posixLocale: "POSIX",
+			});
+
+			expect(result).toEqual({
+				language: "en",
+				source: "default",
+				sources: {
+					flag: "",
+					env: "",
+					project: "",
+					user: "",
+					locale: "POSIX",
+				},
+			});
+		});
+	});
+
 	describe("parseLocale method", () => {
 		it("should parse standard POSIX locale formats", () => {
 			expect(detector.parseLocale("en_US.UTF-8")).toBe("en");
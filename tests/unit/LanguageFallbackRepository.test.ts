@@ -0,0 +1,131 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import type IRepository from "../../src/interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../../src/interfaces/IRepository.js";
+import { LanguageFallbackRepository } from "../../src/services/LanguageFallbackRepository.js";
+import type { Manifest } from "../../src/types/Command.js";
+import {
+	CommandContentError,
+	CommandNotFoundError,
+	ManifestError,
+} from "../../src/types/Command.js";
+import type { RepositoryAbout } from "../../src/types/Repository.js";
+
+function manifest(...names: string[]): Manifest {
+	return {
+		version: "1.0.0",
+		updated: "2025-01-01T00:00:00Z",
+		commands: names.map((name) => ({
+			name,
+			description: name,
+			file: `${name}.md`,
+			"allowed-tools": [],
+		})),
+	};
+}
+
+/**
+ * Repository serving manifests and files per language from memory
+ */
+class StubRepository implements IRepository {
+	readonly manifests = new Map<string, Manifest | Error>();
+	readonly files = new Map<string, string | Error>();
+
+	async getManifest(language: string): Promise<Manifest> {
+		const result =
+			this.manifests.get(language) ??
+			new ManifestError(language, "404 Not Found", true);
+		if (result instanceof Error) {
+			throw result;
+		}
+		return result;
+	}
+
+	async getCommand(commandName: string, language: string): Promise<string> {
+		const found = (await this.getManifest(language)).commands.some(
+			(command) => command.name === commandName,
+		);
+		if (!found) {
+			throw new CommandNotFoundError(commandName, language);
+		}
+		const result =
+			this.files.get(`${language}:${commandName}`) ??
+			new CommandContentError(commandName, language, "404 Not Found", true);
+		if (result instanceof Error) {
+			throw result;
+		}
+		return result;
+	}
+
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return [];
+	}
+
+	async getAbout(): Promise<RepositoryAbout | null> {
+		return null;
+	}
+}
+
+describe("LanguageFallbackRepository", () => {
+	let stub: StubRepository;
+	let strict: boolean;
+	let repository: LanguageFallbackRepository;
+
+	beforeEach(() => {
+		stub = new StubRepository();
+		stub.manifests.set("en", manifest("hello", "bye"));
+		stub.files.set("en:hello", "Hello!");
+		stub.files.set("en:bye", "Bye!");
+		strict = false;
+		repository = new LanguageFallbackRepository(stub, async () => strict);
+	});
+
+	test("should serve the requested language when it exists", async () => {
+		stub.manifests.set("fr", manifest("hello"));
+		stub.files.set("fr:hello", "Bonjour !");
+
+		expect(await repository.getManifest("fr")).toEqual(manifest("hello"));
+		expect(await repository.getCommand("hello", "fr")).toBe("Bonjour !");
+	});
+
+	test("should serve the English manifest when the language has none", async () => {
+		expect(await repository.getManifest("fr")).toEqual(
+			manifest("hello", "bye"),
+		);
+	});
+
+	test("should serve English commands missing from a partial translation", async () => {
+		stub.manifests.set("fr", manifest("hello"));
+		stub.files.set("fr:hello", "Bonjour !");
+
+		expect(await repository.getCommand("bye", "fr")).toBe("Bye!");
+	});
+
+	test("should serve the English file when the translated file is missing", async () => {
+		stub.manifests.set("fr", manifest("hello"));
+
+		expect(await repository.getCommand("hello", "fr")).toBe("Hello!");
+	});
+
+	test("should not fall back on network failures", async () => {
+		const offline = new ManifestError("fr", "Network connection failed");
+		stub.manifests.set("fr", offline);
+
+		await expect(repository.getManifest("fr")).rejects.toBe(offline);
+	});
+
+	test("should rethrow the original error when English is missing too", async () => {
+		stub.manifests.set("fr", manifest("hello"));
+
+		await expect(repository.getCommand("unknown", "fr")).rejects.toThrow(
+			'Command "unknown" not found in language "fr"',
+		);
+	});
+
+	test("should not fall back when strictLanguage is set", async () => {
+		strict = true;
+
+		await expect(repository.getManifest("fr")).rejects.toThrow(
+			'Failed to retrieve manifest for language "fr"',
+		);
+	});
+});
@@ -0,0 +1,116 @@
+import { describe, expect, test } from "bun:test";
+import { ManifestDecoder } from "../../src/services/ManifestDecoder.js";
+import { ManifestSchemaError } from "../../src/services/ManifestMigrations.js";
+import { ManifestError } from "../../src/types/Command.js";
+
+/**
+ * Decode a manifest written in chunks of the given size
+ */
+function decode(json: string, chunkSize = json.length || 1) {
+	const decoder = new ManifestDecoder("en");
+	const bytes = Buffer.from(json, "utf8");
+	for (let offset = 0; offset < bytes.length; offset += chunkSize) {
+		decoder.write(bytes.subarray(offset, offset + chunkSize));
+	}
+	return decoder.end();
+}
+
+const manifest = {
+	version: "1.0.0",
+	schemaVersion: 2,
+	updated: "2025-01-01T00:00:00Z",
+	commands: [
+		{
+			name: "frontend:component",
+			description: 'Tricky "quotes", {braces} and [brackets] \\ é ✓',
+			file: "frontend/component.md",
+			"allowed-tools": ["Read", "Bash(git:*)"],
+			tags: [],
+		},
+		{
+			name: "debug-help",
+			description: "Help with debugging",
+			file: "debug-help.md",
+			"allowed-tools": [],
+			deprecated: false,
+		},
+	],
+};
+
+describe("ManifestDecoder", () => {
+	test("should decode a manifest written at once", () => {
+		expect(decode(JSON.stringify(manifest))).toEqual(manifest);
+	});
+
+	test("should decode a manifest split at every byte", () => {
+		const json = JSON.stringify(manifest, null, 2);
+
+		expect(decode(json, 1)).toEqual(manifest);
+		expect(decode(json, 7)).toEqual(manifest);
+	});
+
+	test("should accept commands before the other fields", () => {
+		const { commands, ...fields } = manifest;
+		const json = JSON.stringify({ commands, ...fields });
+
+		expect(decode(json, 5)).toEqual(manifest);
+	});
+
+	test("should migrate older schemas like a manifest parsed whole", () => {
+		const decoded = decode(
+			JSON.stringify({
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [
+					{
+						name: "a",
+						description: "",
+						file: "a.md",
+						"allowed-tools": "Read, Edit",
+					},
+				],
+			}),
+			4,
+		);
+
+		expect(decoded.schemaVersion).toBe(2);
+		expect(decoded.commands[0]?.["allowed-tools"]).toEqual(["Read", "Edit"]);
+	});
+
+	test("should report an empty body", () => {
+		expect(() => decode("  \n")).toThrow("Empty response received from server");
+	});
+
+	test("should report invalid JSON", () => {
+		expect(() => decode('{"version": "1.0.0",', 3)).toThrow(
+			"Invalid JSON format received from server",
+		);
+		expect(() => decode('{"commands": [{"name": }]}')).toThrow(ManifestError);
+		expect(() => decode('{"commands": []} trailing')).toThrow(
+			"Unexpected token 't'",
+		);
+	});
+
+	test("should require a commands array", () => {
+		expect(() =>
+			decode('{"version": "1.0.0", "commands": {"name": "a"}}'),
+		).toThrow("Manifest does not contain valid commands array");
+		expect(() => decode('{"version": "1.0.0"}')).toThrow(
+			"Manifest does not contain valid commands array",
+		);
+	});
+
+	test("should reject unsupported schema versions", () => {
+		expect(() =>
+			decode('{"schemaVersion": 99, "version": "1", "commands": []}'),
+		).toThrow(ManifestSchemaError);
+	});
+
+	test("should keep errors until end()", () => {
+		const decoder = new ManifestDecoder("en");
+
+		expect(() => decoder.write(Buffer.from("not json"))).not.toThrow();
+		expect(() => decoder.write(Buffer.from("{}"))).not.toThrow();
+		expect(() => decoder.end()).toThrow(ManifestError);
+	});
+});
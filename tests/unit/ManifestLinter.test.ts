@@ -0,0 +1,191 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { ManifestLinter } from "../../src/services/ManifestLinter.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+describe("ManifestLinter", () => {
+	let fileService: InMemoryFileService;
+	let linter: ManifestLinter;
+
+	const command = (name: string, overrides: Record<string, unknown> = {}) => ({
+		name,
+		description: `${name} command`,
+		file: `${name}.md`,
+		"allowed-tools": ["Read"],
+		...overrides,
+	});
+	const writeManifest = (commands: unknown[]) =>
+		fileService.writeFile(
+			"/repo/en/index.json",
+			JSON.stringify({
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands,
+			}),
+		);
+	const writeFiles = async (...files: string[]) => {
+		for (const file of files) {
+			await fileService.writeFile(`/repo/en/${file}`, "# Command\n");
+		}
+	};
+	const rules = async () =>
+		(await linter.lint("/repo/en/index.json")).issues.map(
+			(issue) => `${issue.severity} ${issue.rule} ${issue.command ?? ""}`,
+		);
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		linter = new ManifestLinter(fileService);
+	});
+
+	test("should pass a consistent manifest", async () => {
+		await writeManifest([
+			command("review"),
+			command("frontend:lint", { file: "frontend/lint.md" }),
+		]);
+		await writeFiles("review.md", "frontend/lint.md");
+
+		const result = await linter.lint("/repo/en/index.json");
+
+		expect(result).toEqual({ commandCount: 2, issues: [] });
+	});
+
+	test("should report schema errors and skip the other checks", async () => {
+		await fileService.writeFile(
+			"/repo/en/index.json",
+			JSON.stringify({ version: "1.0.0", updated: "x", commands: [{}] }),
+		);
+
+		const result = await linter.lint("/repo/en/index.json");
+
+		expect(result.commandCount).toBe(0);
+		expect(result.issues).toHaveLength(1);
+		expect(result.issues[0]?.rule).toBe("schema");
+	});
+
+	test("should report invalid JSON as a schema error", async () => {
+		await fileService.writeFile("/repo/en/index.json", "{");
+
+		expect(await rules()).toEqual(["error schema "]);
+	});
+
+	test("should report duplicate and invalid names", async () => {
+		await writeManifest([
+			command("review"),
+			command("review", { file: "review-2.md" }),
+			command("Bad Name", { file: "bad.md" }),
+		]);
+		await writeFiles("review.md", "review-2.md", "bad.md");
+
+		expect(await rules()).toEqual([
+			"error invalid-name Bad Name",
+			"error duplicate-name review",
+		]);
+	});
+
+	test("should report missing, dangling and unreferenced files", async () => {
+		await writeManifest([
+			command("review"),
+			command("escape", { file: "../outside.md" }),
+			command("copy", { file: "review.md" }),
+		]);
+		await writeFiles("orphan.md", "notes.txt");
+
+		expect(await rules()).toEqual([
+			"error missing-file copy",
+			"error dangling-reference escape",
+			"error missing-file review",
+			"warning unreferenced-file ",
+			"warning duplicate-file copy",
+		]);
+	});
+
+	test("should check files against --files-dir", async () => {
+		await writeManifest([command("review")]);
+		await fileService.writeFile("/repo/commands/review.md", "# Review\n");
+
+		const result = await linter.lint("/repo/en/index.json", "/repo/commands");
+
+		expect(result.issues).toEqual([]);
+	});
+
+	test("should report malformed allowed-tools", async () => {
+		await writeManifest([
+			command("review", { "allowed-tools": "Read,,Write" }),
+			command("deploy", { "allowed-tools": ["Read", "Explode"] }),
+			command("bash", { "allowed-tools": ["Bash(git status:*)"] }),
+			command("plain", { "allowed-tools": "" }),
+		]);
+		await writeFiles("review.md", "deploy.md", "bash.md", "plain.md");
+
+		expect(await rules()).toEqual([
+			"error allowed-tools deploy",
+			"error allowed-tools review",
+		]);
+	});
+
+	test("should warn about requirements missing from the manifest", async () => {
+		await writeManifest([
+			command("review", { requires: ["lint", "format"] }),
+			command("lint"),
+		]);
+		await writeFiles("review.md", "lint.md");
+
+		const result = await linter.lint("/repo/en/index.json");
+
+		expect(result.issues).toEqual([
+			{
+				severity: "warning",
+				rule: "unknown-requirement",
+				message: "requires format, which is not in the manifest",
+				command: "review",
+			},
+		]);
+	});
+
+	test("should warn about examples that cannot be run as shown", async () => {
+		await writeManifest([command("review")]);
+		await fileService.writeFile(
+			"/repo/en/review.md",
+			"---\nexamples:\n  - src/app.ts\n  - /deploy prod\n---\n\n# Review\n",
+		);
+
+		expect(await rules()).toEqual(["warning examples review"]);
+	});
+
+	test("should warn about manifest examples that cannot be run as shown", async () => {
+		await writeManifest([
+			command("review", { examples: [{ arguments: "--staged" }, "/deploy"] }),
+		]);
+		await writeFiles("review.md");
+
+		expect(await rules()).toEqual(["warning examples review"]);
+	});
+
+	test("should warn about malformed argument hints", async () => {
+		await writeManifest([
+			command("review", { "argument-hint": "[file" }),
+			command("commit"),
+		]);
+		await writeFiles("review.md");
+		await fileService.writeFile(
+			"/repo/en/commit.md",
+			"---\nargument-hint: [message]\n---\n\n# Commit\n",
+		);
+		await fileService.writeFile(
+			"/repo/en/review.md",
+			"---\nargument-hint: <file> [<line>]\n---\n\n# Review\n",
+		);
+
+		expect(await rules()).toEqual([
+			"warning argument-hint review",
+			"warning argument-hint review",
+		]);
+	});
+
+	test("should warn about argument hints that are not strings", async () => {
+		await writeManifest([command("review", { "argument-hint": 42 })]);
+		await writeFiles("review.md");
+
+		expect(await rules()).toEqual(["warning argument-hint review"]);
+	});
+});
@@ -0,0 +1,65 @@
+import { describe, expect, test } from "bun:test";
+import {
+	CURRENT_MANIFEST_SCHEMA,
+	ManifestSchemaError,
+	migrateManifest,
+} from "../../src/services/ManifestMigrations.js";
+
+describe("migrateManifest", () => {
+	const command = {
+		name: "debug-help",
+		description: "Debug assistance",
+		file: "debug-help.md",
+		sha256: "abc",
+	};
+
+	test("should treat manifests without schemaVersion as schema 1 and upgrade them", () => {
+		const manifest = migrateManifest(
+			{
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [{ ...command, "allowed-tools": "Read, Edit,," }],
+			},
+			"en",
+		);
+
+		expect(manifest.schemaVersion).toBe(CURRENT_MANIFEST_SCHEMA);
+		expect(manifest.commands[0]).toEqual({
+			...command,
+			"allowed-tools": ["Read", "Edit"],
+		});
+	});
+
+	test("should leave current manifests unchanged", () => {
+		const current = {
+			version: "1.0.0",
+			schemaVersion: CURRENT_MANIFEST_SCHEMA,
+			updated: "2025-01-01T00:00:00Z",
+			commands: [{ ...command, "allowed-tools": ["Read"] }],
+		};
+
+		expect(migrateManifest(current, "en")).toEqual(current);
+	});
+
+	test("should reject manifests newer than supported", () => {
+		const newer = {
+			version: "1.0.0",
+			schemaVersion: CURRENT_MANIFEST_SCHEMA + 1,
+			updated: "2025-01-01T00:00:00Z",
+			commands: [],
+		};
+
+		expect(() => migrateManifest(newer, "en")).toThrow(ManifestSchemaError);
+		expect(() => migrateManifest(newer, "en")).toThrow(
+			"Upgrade claude-cmd to use this repository.",
+		);
+	});
+
+	test("should reject invalid schema versions", () => {
+		for (const schemaVersion of [0, 1.5, "2"]) {
+			expect(() =>
+				migrateManifest({ schemaVersion, commands: [] }, "en"),
+			).toThrow("Invalid manifest schema version");
+		}
+	});
+});
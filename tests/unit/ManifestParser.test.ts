@@ -39,7 +39,7 @@ describe("ManifestParser", () => {
 			expect(Array.isArray(result.commands[0]?.["allowed-tools"])).toBe(true);
 		});
 
-		test("should parse manifest with allowed-tools as string", () => {
+		test("should parse schema 1 manifest with allowed-tools as string", () => {
 			const validJson = {
 				version: "1.0.1",
 				updated: "2025-07-09T00:41:00Z",
@@ -56,10 +56,155 @@ describe("ManifestParser", () => {
 
 			const result = parser.parseManifest(JSON.stringify(validJson), "en");
 
+			// Schema 1 manifests are migrated to the array form
 			expect(result.commands[0]).toBeDefined();
-			expect(result.commands[0]?.["allowed-tools"]).toBe(
-				"Read, Edit, Write, Bash(npm:*)",
-			);
+			expect(result.commands[0]?.["allowed-tools"]).toEqual([
+				"Read",
+				"Edit",
+				"Write",
+				"Bash(npm:*)",
+			]);
+		});
+
+		test("should keep category and tags", () => {
+			const validJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{
+						name: "debug-help",
+						description: "Debug assistance",
+						file: "debug-help.md",
+						"allowed-tools": ["Read"],
+						category: "debugging",
+						tags: ["errors", "logs"],
+					},
+				],
+			};
+
+			const result = parser.parseManifest(JSON.stringify(validJson), "en");
+
+			expect(result.commands[0]?.category).toBe("debugging");
+			expect(result.commands[0]?.tags).toEqual(["errors", "logs"]);
+		});
+
+		test("should keep argument-hint", () => {
+			const validJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{
+						name: "commit",
+						description: "Commit changes",
+						file: "commit.md",
+						"allowed-tools": ["Bash(git:*)"],
+						"argument-hint": "[message]",
+					},
+				],
+			};
+
+			const result = parser.parseManifest(JSON.stringify(validJson), "en");
+
+			expect(result.commands[0]?.["argument-hint"]).toBe("[message]");
+		});
+
+		test("should keep the usable examples", () => {
+			const validJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{
+						name: "commit",
+						description: "Commit changes",
+						file: "commit.md",
+						"allowed-tools": [],
+						examples: [
+							{ arguments: "-m fix", description: "Commit with a message" },
+							"/commit --amend",
+							"/push",
+						],
+					},
+					{
+						name: "push",
+						description: "Push changes",
+						file: "push.md",
+						"allowed-tools": [],
+						examples: "not a list",
+					},
+				],
+			};
+
+			const result = parser.parseManifest(JSON.stringify(validJson), "en");
+
+			expect(result.commands[0]?.examples).toEqual([
+				{ arguments: "-m fix", description: "Commit with a message" },
+				{ arguments: "--amend" },
+			]);
+			expect(result.commands[1]).not.toHaveProperty("examples");
+		});
+
+		test("should read argument-hint leniently", () => {
+			const command = {
+				name: "commit",
+				description: "Commit changes",
+				file: "commit.md",
+				"allowed-tools": [],
+			};
+			const validJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{ ...command, "argument-hint": ["message", "files"] },
+					{ ...command, name: "push", "argument-hint": 42 },
+				],
+			};
+
+			const result = parser.parseManifest(JSON.stringify(validJson), "en");
+
+			expect(result.commands[0]?.["argument-hint"]).toBe("[message] [files]");
+			expect(result.commands[1]?.["argument-hint"]).toBeUndefined();
+		});
+
+		test("should keep deprecation metadata", () => {
+			const validJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{
+						name: "old-review",
+						description: "Review changes",
+						file: "old-review.md",
+						"allowed-tools": ["Read"],
+						deprecated: "merged into review",
+						replaced_by: "review",
+					},
+				],
+			};
+
+			const result = parser.parseManifest(JSON.stringify(validJson), "en");
+
+			expect(result.commands[0]?.deprecated).toBe("merged into review");
+			expect(result.commands[0]?.replaced_by).toBe("review");
+		});
+
+		test("should reject tags that are not an array", () => {
+			const invalidJson = {
+				version: "1.0.1",
+				updated: "2025-07-09T00:41:00Z",
+				commands: [
+					{
+						name: "debug-help",
+						description: "Debug assistance",
+						file: "debug-help.md",
+						"allowed-tools": ["Read"],
+						tags: "errors",
+					},
+				],
+			};
+
+			expect(() =>
+				parser.parseManifest(JSON.stringify(invalidJson), "en"),
+			).toThrow("Command at index 0: Invalid field type: tags must be array");
 		});
 
 		test("should parse manifest with multiple commands", () => {
@@ -0,0 +1,190 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import type IRepository from "../../src/interfaces/IRepository.js";
+import type { LanguageStatusInfo } from "../../src/interfaces/IRepository.js";
+import { MultiRepository } from "../../src/services/MultiRepository.js";
+import type { Manifest } from "../../src/types/Command.js";
+import {
+	CommandNotFoundError,
+	ManifestError,
+} from "../../src/types/Command.js";
+import type { RepositoryAbout } from "../../src/types/Repository.js";
+
+function manifest(version: string, ...names: string[]): Manifest {
+	return {
+		version,
+		updated: "2025-01-01T00:00:00Z",
+		commands: names.map((name) => ({
+			name,
+			description: `${name} (${version})`,
+			file: `${name}.md`,
+			"allowed-tools": [],
+		})),
+	};
+}
+
+/**
+ * Repository serving one manifest, optionally after a delay or failing
+ */
+class StubRepository implements IRepository {
+	static maxActive = 0;
+	static running = 0;
+
+	constructor(
+		private readonly result: Manifest | Error,
+		private readonly delayMs = 0,
+	) {}
+
+	async getManifest(): Promise<Manifest> {
+		StubRepository.running++;
+		StubRepository.maxActive = Math.max(
+			StubRepository.maxActive,
+			StubRepository.running,
+		);
+		await new Promise((resolve) => setTimeout(resolve, this.delayMs));
+		StubRepository.running--;
+		if (this.result instanceof Error) {
+			throw this.result;
+		}
+		return this.result;
+	}
+
+	async getCommand(commandName: string, language: string): Promise<string> {
+		if (
+			this.result instanceof Error ||
+			!this.result.commands.some((command) => command.name === commandName)
+		) {
+			throw new CommandNotFoundError(commandName, language);
+		}
+		return `${commandName} from ${this.result.version}`;
+	}
+
+	async getAvailableLanguages(): Promise<LanguageStatusInfo[]> {
+		return [];
+	}
+
+	async getAbout(): Promise<RepositoryAbout | null> {
+		return null;
+	}
+}
+
+describe("MultiRepository", () => {
+	beforeEach(() => {
+		StubRepository.maxActive = 0;
+		StubRepository.running = 0;
+	});
+
+	test("should merge commands, preferring earlier sources", async () => {
+		const repository = new MultiRepository([
+			{ name: "main", repository: new StubRepository(manifest("1", "a", "b")) },
+			{ name: "team", repository: new StubRepository(manifest("2", "b", "c")) },
+		]);
+
+		const merged = await repository.getManifest("en");
+
+		expect(merged.version).toBe("1");
+		expect(merged.commands.map((command) => command.description)).toEqual([
+			"a (1)",
+			"b (1)",
+			"c (2)",
+		]);
+		expect(await repository.getCommand("c", "en")).toBe("c from 2");
+	});
+
+	test("should record which source each command comes from", async () => {
+		const repository = new MultiRepository([
+			{ name: "main", repository: new StubRepository(manifest("1", "a")) },
+			{ name: "team", repository: new StubRepository(manifest("2", "a", "c")) },
+		]);
+
+		const merged = await repository.getManifest("en");
+
+		expect(merged.commands.map((command) => command.repository)).toEqual([
+			"main",
+			"team",
+		]);
+	});
+
+	test("should put a source's prefix before its command names", async () => {
+		const listed = manifest("2", "deploy", "notify");
+		const team = new StubRepository({
+			...listed,
+			commands: listed.commands.map((command) =>
+				command.name === "deploy"
+					? { ...command, requires: ["notify", "main-only"] }
+					: command,
+			),
+		});
+		const repository = new MultiRepository([
+			{ name: "main", repository: new StubRepository(manifest("1", "deploy")) },
+			{ name: "team", repository: team, prefix: "team" },
+		]);
+
+		const merged = await repository.getManifest("en");
+
+		expect(merged.commands.map((command) => command.name)).toEqual([
+			"deploy",
+			"team:deploy",
+			"team:notify",
+		]);
+		expect(merged.commands[1]?.namespace).toBe("team");
+		expect(merged.commands[1]?.requires).toEqual(["team:notify", "main-only"]);
+		expect(await repository.getCommand("team:deploy", "en")).toBe(
+			"deploy from 2",
+		);
+		expect(await repository.getCommand("deploy", "en")).toBe("deploy from 1");
+	});
+
+	test("should leave out sources that fail", async () => {
+		const repository = new MultiRepository([
+			{
+				name: "main",
+				repository: new StubRepository(
+					new ManifestError("en", "connection refused"),
+				),
+			},
+			{ name: "team", repository: new StubRepository(manifest("2", "c")) },
+		]);
+
+		const merged = await repository.getManifest("en");
+
+		expect(merged.commands.map((command) => command.name)).toEqual(["c"]);
+	});
+
+	test("should fail with the first error when every source fails", async () => {
+		const error = new ManifestError("en", "connection refused");
+		const repository = new MultiRepository([
+			{ name: "main", repository: new StubRepository(error) },
+			{
+				name: "team",
+				repository: new StubRepository(new ManifestError("en", "timeout")),
+			},
+		]);
+
+		await expect(repository.getManifest("en")).rejects.toBe(error);
+	});
+
+	test("should fetch manifests concurrently up to the limit", async () => {
+		const sources = Array.from({ length: 6 }, (_, index) => ({
+			name: `source-${index}`,
+			repository: new StubRepository(manifest(String(index), `c${index}`), 5),
+		}));
+		const repository = new MultiRepository(sources, 3);
+
+		const merged = await repository.getManifest("en");
+
+		expect(merged.commands).toHaveLength(6);
+		expect(StubRepository.maxActive).toBe(3);
+	});
+
+	test("should try sources in order before a manifest was fetched", async () => {
+		const repository = new MultiRepository([
+			{ name: "main", repository: new StubRepository(manifest("1", "a")) },
+			{ name: "team", repository: new StubRepository(manifest("2", "c")) },
+		]);
+
+		expect(await repository.getCommand("c", "en")).toBe("c from 2");
+		await expect(repository.getCommand("missing", "en")).rejects.toThrow(
+			CommandNotFoundError,
+		);
+	});
+});
@@ -0,0 +1,126 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	MAX_HISTORY_BATCHES,
+	OperationHistory,
+	UndoError,
+} from "../../src/services/OperationHistory.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const HISTORY_DIR = "/home/user/.cache/claude-cmd/history";
+
+describe("OperationHistory", () => {
+	let fileService: InMemoryFileService;
+	let clock: FakeClock;
+	let history: OperationHistory;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService({
+			"/commands/a.md": "old a",
+		});
+		clock = new FakeClock();
+		history = new OperationHistory(fileService, HISTORY_DIR, clock);
+	});
+
+	async function overwriteAndCreate(): Promise<void> {
+		await history.batch("add a b", async () => {
+			await history.capture("/commands/a.md");
+			await fileService.writeFile("/commands/a.md", "new a");
+			await history.capture("/commands/b.md");
+			await fileService.writeFile("/commands/b.md", "new b");
+		});
+	}
+
+	test("should restore overwritten files and delete created ones", async () => {
+		await overwriteAndCreate();
+
+		const undone = await history.undo();
+
+		expect(undone.name).toBe("add a b");
+		expect(await fileService.readFile("/commands/a.md")).toBe("old a");
+		expect(await fileService.exists("/commands/b.md")).toBe(false);
+		expect(await history.list()).toEqual([]);
+	});
+
+	test("should restore deleted files", async () => {
+		await history.batch("remove a", async () => {
+			await history.capture("/commands/a.md");
+			await fileService.deleteFile("/commands/a.md");
+		});
+
+		await history.undo();
+
+		expect(await fileService.readFile("/commands/a.md")).toBe("old a");
+	});
+
+	test("should undo the newest operation first", async () => {
+		await overwriteAndCreate();
+		clock.advance(1000);
+		await history.batch("remove b", async () => {
+			await history.capture("/commands/b.md");
+			await fileService.deleteFile("/commands/b.md");
+		});
+
+		expect((await history.list()).map((batch) => batch.name)).toEqual([
+			"remove b",
+			"add a b",
+		]);
+		await history.undo();
+		expect(await fileService.readFile("/commands/b.md")).toBe("new b");
+	});
+
+	test("should record partial changes of failed operations", async () => {
+		await expect(
+			history.batch("add a", async () => {
+				await history.capture("/commands/a.md");
+				await fileService.writeFile("/commands/a.md", "new a");
+				throw new Error("boom");
+			}),
+		).rejects.toThrow("boom");
+
+		await history.undo();
+		expect(await fileService.readFile("/commands/a.md")).toBe("old a");
+	});
+
+	test("should not record operations that changed nothing", async () => {
+		await history.batch("remove a", async () => {
+			await history.capture("/commands/a.md");
+		});
+
+		expect(await history.list()).toEqual([]);
+	});
+
+	test("should ignore captures outside a batch", async () => {
+		await history.capture("/commands/a.md");
+		await fileService.writeFile("/commands/a.md", "new a");
+
+		await expect(history.undo()).rejects.toThrow("Nothing to undo.");
+	});
+
+	test("should refuse to clobber files edited since unless forced", async () => {
+		await overwriteAndCreate();
+		await fileService.writeFile("/commands/a.md", "edited a");
+
+		const error = await history.undo().catch((e: unknown) => e);
+		expect(error).toBeInstanceOf(UndoError);
+		expect((error as UndoError).modifiedPaths).toEqual(["/commands/a.md"]);
+		expect(await fileService.readFile("/commands/a.md")).toBe("edited a");
+
+		await history.undo({ force: true });
+		expect(await fileService.readFile("/commands/a.md")).toBe("old a");
+	});
+
+	test("should keep only the most recent operations", async () => {
+		for (let i = 0; i <= MAX_HISTORY_BATCHES; i++) {
+			clock.advance(1000);
+			await history.batch(`add ${i}`, async () => {
+				await history.capture("/commands/a.md");
+				await fileService.writeFile("/commands/a.md", `content ${i}`);
+			});
+		}
+
+		const batches = await history.list();
+		expect(batches).toHaveLength(MAX_HISTORY_BATCHES);
+		expect(batches[0]?.name).toBe(`add ${MAX_HISTORY_BATCHES}`);
+	});
+});
@@ -0,0 +1,143 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { HTTPResponseTooLargeError } from "../../src/interfaces/IHTTPClient.js";
+import {
+	GIST_API_URL,
+	parseGistSpec,
+	RemoteCommandError,
+	RemoteCommandSource,
+	rawFileURL,
+} from "../../src/services/RemoteCommandSource.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
+
+const COMMAND = "---\ndescription: Review changes\n---\n\nReview the diff.\n";
+
+function ok(url: string, body: string) {
+	return { status: 200, statusText: "OK", headers: {}, body, url };
+}
+
+describe("RemoteCommandSource", () => {
+	let httpClient: InMemoryHTTPClient;
+	let fileService: InMemoryFileService;
+	let source: RemoteCommandSource;
+
+	function setGist(id: string, files: Record<string, unknown>): void {
+		const url = `${GIST_API_URL}/${id}`;
+		httpClient.setResponse(url, ok(url, JSON.stringify({ files })));
+	}
+
+	beforeEach(() => {
+		httpClient = new InMemoryHTTPClient();
+		fileService = new InMemoryFileService();
+		source = new RemoteCommandSource(httpClient, fileService, 1024);
+	});
+
+	describe("parseGistSpec", () => {
+		test("should accept gist references and URLs", () => {
+			expect(parseGistSpec("gist:abc123")).toEqual({ id: "abc123" });
+			expect(parseGistSpec("gist:octocat/abc123#review.md")).toEqual({
+				id: "abc123",
+				file: "review.md",
+			});
+			expect(parseGistSpec("https://gist.github.com/octocat/abc123")).toEqual(
+				{ id: "abc123" },
+			);
+		});
+
+		test("should reject anything else", () => {
+			expect(parseGistSpec("https://example.com/abc123")).toBeUndefined();
+			expect(parseGistSpec("gist:not-hex")).toBeUndefined();
+		});
+	});
+
+	test("should rewrite GitHub file pages to raw URLs", () => {
+		expect(
+			rawFileURL("https://github.com/acme/commands/blob/main/en/review.md"),
+		).toBe("https://raw.githubusercontent.com/acme/commands/main/en/review.md");
+		expect(rawFileURL("https://example.com/review.md")).toBe(
+			"https://example.com/review.md",
+		);
+	});
+
+	test("should download a command file by URL", async () => {
+		const url = "https://raw.githubusercontent.com/acme/commands/main/review.md";
+		httpClient.setResponse(url, ok(url, COMMAND));
+
+		const file = await source.fetch(
+			"https://github.com/acme/commands/blob/main/review.md",
+		);
+
+		expect(file).toEqual({ url, fileName: "review.md", content: COMMAND });
+	});
+
+	test("should only download over https", async () => {
+		const url = "http://example.com/review.md";
+		httpClient.setResponse(url, ok(url, COMMAND));
+
+		await expect(source.fetch(url)).rejects.toThrow(RemoteCommandError);
+		await expect(source.fetch("ftp://example.com/review.md")).rejects.toThrow(
+			RemoteCommandError,
+		);
+		await expect(source.fetch("review.md")).rejects.toThrow(RemoteCommandError);
+	});
+
+	test("should read file:// URLs from disk", async () => {
+		await fileService.writeFile("/home/me/review.md", COMMAND);
+
+		expect(await source.fetch("file:///home/me/review.md")).toEqual({
+			url: "file:///home/me/review.md",
+			fileName: "review.md",
+			content: COMMAND,
+		});
+	});
+
+	test("should refuse files over the size limit", async () => {
+		const url = "https://example.com/huge.md";
+		httpClient.setResponse(url, ok(url, "x".repeat(2048)));
+
+		await expect(source.fetch(url)).rejects.toThrow(HTTPResponseTooLargeError);
+	});
+
+	test("should pick the only markdown file of a gist", async () => {
+		setGist("abc123", {
+			"README.txt": { raw_url: "https://gist/readme", content: "notes" },
+			"review.md": { raw_url: "https://gist/review", content: COMMAND },
+		});
+
+		const file = await source.fetch("gist:abc123");
+
+		expect(file).toEqual({
+			url: "https://gist/review",
+			fileName: "review.md",
+			content: COMMAND,
+		});
+	});
+
+	test("should require a file name for gists with several", async () => {
+		setGist("abc123", {
+			"review.md": { raw_url: "https://gist/review", content: COMMAND },
+			"deploy.md": { raw_url: "https://gist/deploy", content: COMMAND },
+		});
+
+		await expect(source.fetch("gist:abc123")).rejects.toThrow(/several/);
+		expect((await source.fetch("gist:abc123#deploy.md")).fileName).toBe(
+			"deploy.md",
+		);
+	});
+
+	test("should download truncated gist files in full", async () => {
+		setGist("abc123", {
+			"review.md": {
+				raw_url: "https://gist/review",
+				content: COMMAND.slice(0, 10),
+				truncated: true,
+			},
+		});
+		httpClient.setResponse(
+			"https://gist/review",
+			ok("https://gist/review", COMMAND),
+		);
+
+		expect((await source.fetch("gist:abc123")).content).toBe(COMMAND);
+	});
+});
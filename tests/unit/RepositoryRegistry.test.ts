@@ -0,0 +1,178 @@
+import { describe, expect, test } from "bun:test";
+import {
+	commandsByRepository,
+	formatRepositoryList,
+} from "../../src/cli/commands/repo.js";
+import type { Config } from "../../src/interfaces/IConfigService.js";
+import HTTPRepository from "../../src/services/HTTPRepository.js";
+import {
+	configForRepository,
+	listConfiguredRepositories,
+	RepositoryRegistry,
+} from "../../src/services/RepositoryRegistry.js";
+import { ManifestError } from "../../src/types/Command.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
+import InMemoryRepository from "../mocks/InMemoryRepository.js";
+
+const TEAM = "https://example.com/team";
+const OLD = "https://example.com/old";
+
+/**
+ * Repository serving a manifest of n commands, or failing with a message
+ */
+function repositoryWith(manifest: number | string): InMemoryRepository {
+	const repository = new InMemoryRepository(
+		new InMemoryHTTPClient(),
+		new InMemoryFileService(),
+	);
+	repository.setManifest(
+		"en",
+		typeof manifest === "number"
+			? {
+					version: "1.0.0",
+					updated: "2025-01-01T00:00:00Z",
+					commands: Array.from({ length: manifest }, (_, index) => ({
+						name: `c${index}`,
+						description: "",
+						file: `c${index}.md`,
+						"allowed-tools": [],
+					})),
+				}
+			: new ManifestError("en", manifest),
+	);
+	return repository;
+}
+
+describe("listConfiguredRepositories", () => {
+	test("should list the primary repository first", () => {
+		expect(listConfiguredRepositories({})).toEqual([
+			{ url: HTTPRepository.BASE_URL, primary: true, enabled: true },
+		]);
+	});
+
+	test("should mark disabled additional repositories", () => {
+		const config: Config = {
+			repositoryURL: "https://example.com/main",
+			additionalRepositories: [TEAM, OLD],
+			disabledRepositories: [`${OLD}/`],
+		};
+
+		expect(listConfiguredRepositories(config)).toEqual([
+			{ url: "https://example.com/main", primary: true, enabled: true },
+			{ url: TEAM, primary: false, enabled: true },
+			{ url: OLD, primary: false, enabled: false },
+		]);
+	});
+});
+
+describe("configForRepository", () => {
+	const config: Config = {
+		repositoryURL: "https://example.com/main",
+		manifestPublicKey: "primary key",
+		repositoryPublicKeys: { [`${TEAM}/`]: "team key" },
+		additionalRepositories: [TEAM, OLD],
+	};
+
+	test("should keep the primary key for the primary repository", () => {
+		expect(
+			configForRepository(config, {
+				url: "https://example.com/main",
+				primary: true,
+			}),
+		).toMatchObject({
+			repositoryURL: "https://example.com/main",
+			manifestPublicKey: "primary key",
+			additionalRepositories: [],
+		});
+	});
+
+	test("should give additional repositories their own key", () => {
+		expect(
+			configForRepository(config, { url: TEAM, primary: false }),
+		).toMatchObject({
+			repositoryURL: TEAM,
+			manifestPublicKey: "team key",
+			additionalRepositories: [],
+		});
+		expect(
+			configForRepository(config, { url: OLD, primary: false })
+				.manifestPublicKey,
+		).toBeUndefined();
+	});
+});
+
+describe("RepositoryRegistry", () => {
+	test("should check each enabled repository on its own", async () => {
+		const checked: (string | undefined)[] = [];
+		const registry = new RepositoryRegistry(
+			async () => ({
+				additionalRepositories: [TEAM, OLD],
+				disabledRepositories: [OLD],
+			}),
+			(config) => {
+				checked.push(config.repositoryURL);
+				expect(config.additionalRepositories).toEqual([]);
+				return config.repositoryURL === TEAM
+					? repositoryWith("Server returned 404 Not Found")
+					: repositoryWith(3);
+			},
+		);
+
+		const health = await registry.check("en");
+
+		expect(checked.sort()).toEqual([TEAM, undefined].sort());
+		expect(health.map(({ url, status }) => [url, status])).toEqual([
+			[HTTPRepository.BASE_URL, "ok"],
+			[TEAM, "failed"],
+			[OLD, "disabled"],
+		]);
+		expect(health[0]?.commandCount).toBe(3);
+		expect(health[1]?.error).toContain("404 Not Found");
+	});
+});
+
+describe("repo list formatting", () => {
+	test("should align repositories and show their state", () => {
+		expect(
+			formatRepositoryList([
+				{
+					url: "https://a.example",
+					primary: true,
+					enabled: true,
+					status: "ok",
+					commandCount: 2,
+				},
+				{ url: "https://bb.example", primary: false, enabled: false },
+			]),
+		).toBe(
+			[
+				"https://a.example   primary     ok, 2 commands",
+				"https://bb.example  additional  disabled",
+			].join("\n"),
+		);
+	});
+
+	test("should group commands by the repository they come from", () => {
+		const grouped = commandsByRepository(
+			[
+				{ name: "a", description: "", file: "a.md", "allowed-tools": [] },
+				{
+					name: "b",
+					description: "",
+					file: "b.md",
+					"allowed-tools": [],
+					repository: TEAM,
+				},
+			],
+			"https://example.com/main",
+		);
+
+		expect(grouped).toEqual(
+			new Map([
+				["https://example.com/main", ["a"]],
+				[TEAM, ["b"]],
+			]),
+		);
+	});
+});
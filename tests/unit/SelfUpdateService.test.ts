@@ -0,0 +1,256 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { createHash } from "node:crypto";
+import { HTTPResponseTooLargeError } from "../../src/interfaces/IHTTPClient.js";
+import {
+	binaryAssetName,
+	findChecksum,
+	SelfUpdateError,
+	SelfUpdateService,
+} from "../../src/services/SelfUpdateService.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
+
+const API_URL =
+	"https://api.github.com/repos/claude-code-commands/claude-cmd/releases/latest";
+const DOWNLOAD_URL =
+	"https://github.com/claude-code-commands/claude-cmd/releases/download/v1.3.0";
+const ASSET = "claude-cmd-linux-x64";
+
+const respond = (url: string, body: string) => ({
+	status: 200,
+	statusText: "OK",
+	headers: {},
+	body,
+	url,
+});
+
+describe("SelfUpdateService", () => {
+	let httpClient: InMemoryHTTPClient;
+	let fileService: InMemoryFileService;
+	let service: SelfUpdateService;
+
+	// Every byte value, so the download must survive a binary round trip
+	const binary = Buffer.from(Array.from({ length: 256 }, (_, i) => i));
+	const sha256 = createHash("sha256").update(binary).digest("hex");
+
+	const setRelease = (assets: string[]) => {
+		httpClient.setResponse(
+			API_URL,
+			respond(
+				API_URL,
+				JSON.stringify({
+					tag_name: "v1.3.0",
+					html_url:
+						"https://github.com/claude-code-commands/claude-cmd/releases/tag/v1.3.0",
+					assets: assets.map((name) => ({
+						name,
+						browser_download_url: `${DOWNLOAD_URL}/${name}`,
+					})),
+				}),
+			),
+		);
+	};
+
+	const setChecksums = (content: string) => {
+		const url = `${DOWNLOAD_URL}/SHA256SUMS`;
+		httpClient.setResponse(url, respond(url, content));
+	};
+
+	beforeEach(() => {
+		httpClient = new InMemoryHTTPClient();
+		fileService = new InMemoryFileService();
+		service = new SelfUpdateService(httpClient, fileService);
+		setRelease([ASSET, "claude-cmd-darwin-arm64", "SHA256SUMS"]);
+		setChecksums(`${sha256}  ${ASSET}\n${"0".repeat(64)}  other\n`);
+		httpClient.setResponse(
+			`${DOWNLOAD_URL}/${ASSET}`,
+			respond(`${DOWNLOAD_URL}/${ASSET}`, binary.toString("latin1")),
+		);
+	});
+
+	describe("check", () => {
+		test("should report a newer release and its assets", async () => {
+			const release = await service.check("1.2.9", "linux", "x64");
+
+			expect(release).toEqual({
+				version: "1.3.0",
+				url: "https://github.com/claude-code-commands/claude-cmd/releases/tag/v1.3.0",
+				newer: true,
+				assetName: ASSET,
+				binaryUrl: `${DOWNLOAD_URL}/${ASSET}`,
+				checksumsUrl: `${DOWNLOAD_URL}/SHA256SUMS`,
+			});
+		});
+
+		test("should not report the running version as newer", async () => {
+			expect((await service.check("1.3.0", "linux", "x64")).newer).toBe(false);
+			expect((await service.check("1.10.0", "linux", "x64")).newer).toBe(false);
+		});
+
+		test("should leave binaryUrl unset without a binary for the platform", async () => {
+			const release = await service.check("1.0.0", "win32", "x64");
+
+			expect(release.assetName).toBe("claude-cmd-windows-x64.exe");
+			expect(release.binaryUrl).toBeUndefined();
+		});
+
+		test("should reject malformed release information", async () => {
+			httpClient.setResponse(API_URL, respond(API_URL, "<html>"));
+
+			await expect(service.check("1.0.0", "linux", "x64")).rejects.toThrow(
+				SelfUpdateError,
+			);
+		});
+	});
+
+	describe("with a directory", () => {
+		const dir = "/opt/claude-cmd";
+		const executable = `${dir}/claude-cmd`;
+		const readBinary = async (file: string) =>
+			Buffer.from(await fileService.readBinaryFile(file));
+
+		beforeEach(async () => {
+			await fileService.writeFile(executable, "old");
+			httpClient.streamEncoding = "latin1";
+		});
+
+		describe("download", () => {
+			test("should write the binary when its checksum matches", async () => {
+				const release = await service.check("1.0.0", "linux", "x64");
+				const destination = `${dir}/binary`;
+
+				await service.download(release, destination);
+
+				expect(await readBinary(destination)).toEqual(binary);
+			});
+
+			test("should reject a binary whose checksum differs", async () => {
+				setChecksums(`${"a".repeat(64)}  ${ASSET}\n`);
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.download(release, `${dir}/binary`),
+				).rejects.toThrow("Checksum mismatch");
+				expect(await fileService.listFiles(dir)).toEqual(["claude-cmd"]);
+			});
+
+			test("should remove the file of a download over the size limit", async () => {
+				httpClient.setResponse(
+					`${DOWNLOAD_URL}/${ASSET}`,
+					new HTTPResponseTooLargeError(`${DOWNLOAD_URL}/${ASSET}`, 1),
+				);
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.download(release, `${dir}/binary`),
+				).rejects.toThrow(HTTPResponseTooLargeError);
+				expect(await fileService.listFiles(dir)).toEqual(["claude-cmd"]);
+			});
+
+			test("should refuse a release without checksums", async () => {
+				setRelease([ASSET]);
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.download(release, `${dir}/binary`),
+				).rejects.toThrow("refusing to install an unverified binary");
+			});
+
+			test("should refuse a binary missing from the checksums", async () => {
+				setChecksums(`${sha256}  claude-cmd-darwin-arm64\n`);
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.download(release, `${dir}/binary`),
+				).rejects.toThrow("does not list claude-cmd-linux-x64");
+			});
+		});
+
+		describe("replaceExecutable", () => {
+			test("should replace the executable in place", async () => {
+				await fileService.writeBinaryFile(`${executable}.new`, binary);
+
+				await service.replaceExecutable(
+					executable,
+					`${executable}.new`,
+					"linux",
+				);
+
+				expect(await readBinary(executable)).toEqual(binary);
+				expect(fileService.fs[executable]).toMatchObject({ executable: true });
+				expect(await fileService.listFiles(dir)).toEqual(["claude-cmd"]);
+			});
+
+			test("should move the running executable aside on Windows", async () => {
+				await fileService.writeFile(`${executable}.old`, "stale");
+				await fileService.writeBinaryFile(`${executable}.new`, binary);
+
+				await service.replaceExecutable(
+					executable,
+					`${executable}.new`,
+					"win32",
+				);
+
+				expect(await readBinary(executable)).toEqual(binary);
+				expect(await fileService.readFile(`${executable}.old`)).toBe("old");
+			});
+		});
+
+		describe("install", () => {
+			test("should download and swap in a verified binary", async () => {
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await service.install(release, executable, "linux");
+
+				expect(await readBinary(executable)).toEqual(binary);
+				expect(await fileService.listFiles(dir)).toEqual(["claude-cmd"]);
+			});
+
+			test("should keep the executable when verification fails", async () => {
+				setChecksums(`${"a".repeat(64)}  ${ASSET}\n`);
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.install(release, executable, "linux"),
+				).rejects.toThrow("Checksum mismatch");
+				expect(await fileService.readFile(executable)).toBe("old");
+				expect(await fileService.listFiles(dir)).toEqual(["claude-cmd"]);
+			});
+
+			test("should refuse a directory it cannot write to", async () => {
+				const release = await service.check("1.0.0", "linux", "x64");
+
+				await expect(
+					service.install(release, "/missing/claude-cmd", "linux"),
+				).rejects.toThrow("Cannot write to /missing");
+			});
+		});
+	});
+});
+
+describe("binaryAssetName", () => {
+	test("should name binaries per platform and architecture", () => {
+		expect(binaryAssetName("linux", "x64")).toBe("claude-cmd-linux-x64");
+		expect(binaryAssetName("darwin", "arm64")).toBe("claude-cmd-darwin-arm64");
+		expect(binaryAssetName("win32", "x64")).toBe("claude-cmd-windows-x64.exe");
+	});
+
+	test("should reject platforms without release binaries", () => {
+		expect(() => binaryAssetName("freebsd", "x64")).toThrow(SelfUpdateError);
+		expect(() => binaryAssetName("linux", "ia32")).toThrow(SelfUpdateError);
+	});
+});
+
+describe("findChecksum", () => {
+	test("should read text and binary mode sha256sum lines", () => {
+		const digest = "AB".repeat(32);
+
+		expect(findChecksum(`${digest}  claude-cmd`, "claude-cmd")).toBe(
+			digest.toLowerCase(),
+		);
+		expect(findChecksum(`${digest} *claude-cmd\r\n`, "claude-cmd")).toBe(
+			digest.toLowerCase(),
+		);
+		expect(findChecksum(`${digest}  claude-cmd`, "other")).toBeUndefined();
+	});
+});
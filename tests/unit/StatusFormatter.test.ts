@@ -1,6 +1,12 @@
 import { describe, expect, test } from "bun:test";
 import { StatusFormatter } from "../../src/services/StatusFormatter.js";
-import type { SystemStatus } from "../../src/types/Status.js";
+import type {
+	AllLanguagesStatus,
+	DiskUsage,
+	SystemStatus,
+} from "../../src/types/Status.js";
+import { COLOR_THEME } from "../../src/utils/style.js";
+import { parseTemplate } from "../../src/utils/template.js";
 
 describe("StatusFormatter", () => {
 	const formatter = new StatusFormatter();
@@ -45,6 +51,8 @@ describe("StatusFormatter", () => {
 			cacheAccessible: true,
 			installationPossible: true,
 			status: "healthy",
+			score: 100,
+			recommendations: [],
 			messages: [],
 		},
 	};
@@ -98,11 +106,92 @@ describe("StatusFormatter", () => {
 			expect(output).toContain("Installation Possible: ✅ Yes");
 		});
 
+		test("should show the health score and numbered recommendations", () => {
+			const output = formatter.format(
+				{
+					...sampleStatus,
+					health: {
+						...sampleStatus.health,
+						repositoryReachable: false,
+						score: 45,
+						recommendations: [
+							{
+								issue: "repository-unreachable",
+								severity: "error",
+								problem: "The repository is unreachable: offline",
+								action: "Check your network connection",
+							},
+							{
+								issue: "cache-stale",
+								severity: "warning",
+								problem: "The cached manifest for en is 8d 0h old",
+								action: "Run 'claude-cmd cache update'",
+							},
+						],
+					},
+				},
+				"default",
+			);
+
+			expect(output).toContain("Repository Reachable: ❌ No");
+			expect(output).toContain("Health Score: 45/100");
+			expect(output).toContain(
+				"Recommendations:\n  1. ❌ The repository is unreachable: offline\n     → Check your network connection\n  2. ⚠️  The cached manifest for en is 8d 0h old\n     → Run 'claude-cmd cache update'",
+			);
+		});
+
+		test("should omit recommendations and repository check when absent", () => {
+			const output = formatter.format(sampleStatus, "default");
+
+			expect(output).toContain("Health Score: 100/100");
+			expect(output).not.toContain("Recommendations:");
+			expect(output).not.toContain("Repository Reachable");
+		});
+
+		test("should show installed commands per namespace", () => {
+			const output = formatter.format(
+				{
+					...sampleStatus,
+					installations: sampleStatus.installations.map((install) =>
+						install.type === "user"
+							? { ...install, namespaces: { backend: 1, frontend: 2 } }
+							: install,
+					),
+				},
+				"default",
+			);
+
+			expect(output).toContain("    By Namespace: backend (1), frontend (2)");
+			expect(formatter.format(sampleStatus, "default")).not.toContain(
+				"By Namespace",
+			);
+		});
+
+		test("should list untracked commands", () => {
+			const output = formatter.format(
+				{
+					...sampleStatus,
+					untracked: [
+						{ name: "mine", file: "mine.md", reason: "local-only" },
+						{ name: "old", file: "old.md", reason: "removed-upstream" },
+					],
+				},
+				"default",
+			);
+
+			expect(output).toContain("Untracked Commands:");
+			expect(output).toContain("  mine (local only)");
+			expect(output).toContain("  old (removed upstream)");
+			expect(formatter.format(sampleStatus, "default")).not.toContain(
+				"Untracked",
+			);
+		});
+
 		test("should display cache information", () => {
 			const output = formatter.format(sampleStatus, "default");
 
 			expect(output).toContain("Language: en");
-			expect(output).toContain("Age: 30m 0s");
+			expect(output).toContain("Updated: 30 minutes ago");
 			expect(output).toContain("Size: 2.0 KB");
 			expect(output).toContain("Commands: 5");
 			expect(output).toContain("Expired: ✅ No");
@@ -116,6 +205,27 @@ describe("StatusFormatter", () => {
 			expect(output).toContain("Commands Installed: 3");
 		});
 
+		test("should show the project root of the project directory", () => {
+			const output = formatter.format(
+				{
+					...sampleStatus,
+					installations: [
+						{
+							type: "project",
+							path: "../../.claude/commands",
+							exists: true,
+							writable: true,
+							commandCount: 1,
+							projectRoot: "/work/monorepo/packages/web",
+						},
+					],
+				},
+				"default",
+			);
+
+			expect(output).toContain("Project Root: /work/monorepo/packages/web");
+		});
+
 		test("should handle degraded health status", () => {
 			const degradedStatus: SystemStatus = {
 				...sampleStatus,
@@ -123,6 +233,8 @@ describe("StatusFormatter", () => {
 					cacheAccessible: false,
 					installationPossible: true,
 					status: "degraded",
+					score: 60,
+					recommendations: [],
 					messages: ["Cache directory not accessible"],
 				},
 			};
@@ -134,6 +246,16 @@ describe("StatusFormatter", () => {
 			expect(output).toContain("⚠️  Cache directory not accessible");
 		});
 
+		test("should color checks and headings with a theme", () => {
+			const output = formatter.format(sampleStatus, "default", {
+				theme: COLOR_THEME,
+			});
+
+			expect(output).toContain("\x1b[1mSystem Health:\x1b[0m");
+			expect(output).toContain("Cache Accessible: \x1b[32m✅ Yes\x1b[0m");
+			expect(output).toContain("Exists: \x1b[31m❌ No\x1b[0m");
+		});
+
 		test("should handle error health status", () => {
 			const errorStatus: SystemStatus = {
 				...sampleStatus,
@@ -141,6 +263,8 @@ describe("StatusFormatter", () => {
 					cacheAccessible: false,
 					installationPossible: false,
 					status: "error",
+					score: 20,
+					recommendations: [],
 					messages: ["Multiple system failures"],
 				},
 			};
@@ -180,6 +304,7 @@ describe("StatusFormatter", () => {
 			// Should be a single line
 			expect(output.split("\\n")).toHaveLength(1);
 			expect(output).toContain(" | ");
+			expect(output).toContain("Score: 100");
 		});
 
 		test("should show warnings count when present", () => {
@@ -201,9 +326,28 @@ describe("StatusFormatter", () => {
 
 			expect(output).not.toContain("Warnings:");
 		});
+
+		test("should not localize counts", () => {
+			const status: SystemStatus = {
+				...sampleStatus,
+				installations: [
+					{
+						type: "user",
+						path: "/home/user/.claude/commands",
+						exists: true,
+						writable: true,
+						commandCount: 1234,
+					},
+				],
+			};
+
+			const output = formatter.format(status, "compact", { locale: "fr" });
+
+			expect(output).toContain("Installs: 1/1 writable, 1234 commands");
+		});
 	});
 
-	describe("duration formatting", () => {
+	describe("age formatting", () => {
 		test("should format seconds", () => {
 			const status: SystemStatus = {
 				...sampleStatus,
@@ -219,8 +363,8 @@ describe("StatusFormatter", () => {
 				],
 			};
 
-			const output = formatter.format(status, "default");
-			expect(output).toContain("Age: 45s");
+			const output = formatter.format(status, "default", { locale: "en" });
+			expect(output).toContain("Updated: 45 seconds ago");
 		});
 
 		test("should format minutes and seconds", () => {
@@ -238,8 +382,8 @@ describe("StatusFormatter", () => {
 				],
 			};
 
-			const output = formatter.format(status, "default");
-			expect(output).toContain("Age: 2m 5s");
+			const output = formatter.format(status, "default", { locale: "en" });
+			expect(output).toContain("Updated: 2 minutes ago");
 		});
 
 		test("should format hours and minutes", () => {
@@ -257,8 +401,8 @@ describe("StatusFormatter", () => {
 				],
 			};
 
-			const output = formatter.format(status, "default");
-			expect(output).toContain("Age: 1h 2m");
+			const output = formatter.format(status, "default", { locale: "en" });
+			expect(output).toContain("Updated: 1 hour ago");
 		});
 
 		test("should format days and hours", () => {
@@ -276,8 +420,32 @@ describe("StatusFormatter", () => {
 				],
 			};
 
-			const output = formatter.format(status, "default");
-			expect(output).toContain("Age: 1d 1h");
+			const output = formatter.format(status, "default", { locale: "en" });
+			expect(output).toContain("Updated: yesterday");
+		});
+
+		test("should write lines with ages and counts in the given language", () => {
+			const status: SystemStatus = {
+				...sampleStatus,
+				cache: [
+					{
+						language: "fr",
+						exists: true,
+						path: "/cache/fr/manifest.json",
+						isExpired: false,
+						ageMs: 3 * 24 * 60 * 60 * 1000,
+						commandCount: 1234,
+					},
+				],
+			};
+
+			const output = formatter.format(status, "default", { locale: "fr" });
+			expect(output).toContain("Mis à jour : il y a 3 jours");
+			expect(output).toMatch(/Commandes : 1\s234/);
+
+			const german = formatter.format(status, "default", { locale: "de" });
+			expect(german).toContain("Updated: 3 days ago");
+			expect(german).toContain("Commands: 1,234");
 		});
 	});
 
@@ -366,4 +534,129 @@ describe("StatusFormatter", () => {
 			expect(output).toContain("❌ ERROR");
 		});
 	});
+
+	describe("formatTemplate", () => {
+		test("should render cache and installation counts", () => {
+			const template = parseTemplate(
+				"{{.Cache.CommandCount}} {{.Installed.TotalCount}} {{.Health.Status}}",
+			);
+
+			expect(formatter.formatTemplate(sampleStatus, template, "en")).toBe(
+				"5 3 healthy",
+			);
+		});
+
+		test("should describe a language without a cache", () => {
+			const template = parseTemplate("{{.Cache.Exists}} {{.Cache.AgeSeconds}}");
+
+			expect(formatter.formatTemplate(sampleStatus, template, "de")).toBe(
+				"false ",
+			);
+		});
+
+		test("should reject unknown fields", () => {
+			expect(() =>
+				formatter.formatTemplate(
+					sampleStatus,
+					parseTemplate("{{.Cache.Commands}}"),
+					"en",
+				),
+			).toThrow("Unknown field '.Cache.Commands'");
+		});
+	});
+
+	describe("formatAllLanguages", () => {
+		const allLanguages: AllLanguagesStatus = {
+			timestamp: Date.UTC(2025, 0, 1),
+			activeLanguage: "en",
+			languages: [
+				{
+					language: "en",
+					active: true,
+					hasManifest: true,
+					isExpired: false,
+					commandCount: 5,
+					manifestAgeMs: 2 * 60 * 60 * 1000,
+					cachedFiles: 3,
+					sizeBytes: 2048,
+					filesAgeMs: 60 * 1000,
+				},
+				{
+					language: "fr",
+					active: false,
+					hasManifest: false,
+					isExpired: true,
+					cachedFiles: 0,
+					sizeBytes: 0,
+				},
+			],
+		};
+
+		test("should list each language with the active one marked", () => {
+			const output = formatter.formatAllLanguages(allLanguages, "default");
+
+			expect(output).toContain("Language Caches (active: en):");
+			expect(output).toMatch(/\* en {2}5 commands, updated .* ago/);
+			expect(output).toContain("3 files cached, newest");
+			expect(output).toMatch(/ {3}fr {2}no manifest \| 0 files cached/);
+		});
+
+		test("should summarize languages on one line in compact format", () => {
+			expect(formatter.formatAllLanguages(allLanguages, "compact")).toBe(
+				"en*: 5 commands, 3 files | fr: 0 commands, 0 files",
+			);
+		});
+
+		test("should output structured data in json format", () => {
+			expect(
+				JSON.parse(formatter.formatAllLanguages(allLanguages, "json")),
+			).toEqual(allLanguages);
+		});
+	});
+
+	describe("formatDiskUsage", () => {
+		const usage: DiskUsage = {
+			timestamp: Date.UTC(2025, 0, 1),
+			cache: [
+				{
+					language: "en",
+					manifestBytes: 1024,
+					filesBytes: 1024,
+					sizeBytes: 2048,
+				},
+			],
+			installations: [
+				{
+					type: "user",
+					path: "/home/user/.claude/commands",
+					commandCount: 3,
+					sizeBytes: 3072,
+					namespaces: { frontend: 2048 },
+				},
+			],
+			totalBytes: 5120,
+		};
+
+		test("should break usage down by language and namespace", () => {
+			const output = formatter.formatDiskUsage(usage, "default");
+
+			expect(output).toContain("Cache: 2.0 KB");
+			expect(output).toContain("  en  2.0 KB (manifest 1.0 KB, files 1.0 KB)");
+			expect(output).toContain("Personal Commands: 3.0 KB in 3 commands");
+			expect(output).toContain("    frontend: 2.0 KB");
+			expect(output).toContain("Total: 5.0 KB");
+		});
+
+		test("should summarize usage on one line in compact format", () => {
+			expect(formatter.formatDiskUsage(usage, "compact")).toBe(
+				"Cache: 2.0 KB | Personal: 3.0 KB | Total: 5.0 KB",
+			);
+		});
+
+		test("should note when nothing is cached", () => {
+			expect(
+				formatter.formatDiskUsage({ ...usage, cache: [] }, "default"),
+			).toContain("  Nothing cached");
+		});
+	});
 });
@@ -3,19 +3,27 @@ import { CacheManager } from "../../src/services/CacheManager.js";
 import { CommandParser } from "../../src/services/CommandParser.js";
 import { ConfigManager } from "../../src/services/ConfigManager.js";
 import { ConfigService } from "../../src/services/ConfigService.js";
+import { ContentCache } from "../../src/services/ContentCache.js";
 import { DirectoryDetector } from "../../src/services/DirectoryDetector.js";
 import { LanguageDetector } from "../../src/services/LanguageDetector.js";
 import { LocalCommandRepository } from "../../src/services/LocalCommandRepository.js";
 import NamespaceService from "../../src/services/NamespaceService.js";
 import { StatusService } from "../../src/services/StatusService.js";
-import { StatusError } from "../../src/types/Status.js";
+import { StatusError, type SystemStatus } from "../../src/types/Status.js";
+import FakeClock from "../mocks/FakeClock.js";
 import InMemoryFileService from "../mocks/InMemoryFileService.js";
 import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
 import InMemoryRepository from "../mocks/InMemoryRepository.js";
 
 describe("StatusService", () => {
 	// Helper to create services with dependencies
-	function createStatusService() {
+	function createStatusService(
+		options: {
+			clock?: FakeClock;
+			snapshotTtlMs?: number;
+			repositoryProbe?: (language: string) => Promise<unknown>;
+		} = {},
+	) {
 		const fileService = new InMemoryFileService();
 		const httpClient = new InMemoryHTTPClient();
 		const repository = new InMemoryRepository(httpClient, fileService);
@@ -50,6 +58,7 @@ describe("StatusService", () => {
 			languageDetector,
 		);
 
+		const contentCache = new ContentCache(fileService, "/cache");
 		const statusService = new StatusService(
 			fileService,
 			cacheManager,
@@ -57,18 +66,162 @@ describe("StatusService", () => {
 			localCommandRepository,
 			languageDetector,
 			configManager,
+			options.clock,
+			contentCache,
+			{
+				snapshotTtlMs: options.snapshotTtlMs,
+				repositoryProbe: options.repositoryProbe,
+			},
 		);
 
 		return {
 			statusService,
 			fileService,
 			cacheManager,
+			contentCache,
 			directoryDetector,
 			localCommandRepository,
 			configManager,
 		};
 	}
 
+	describe("findUntrackedCommands", () => {
+		test("should classify installed commands missing from the manifest", async () => {
+			const {
+				statusService,
+				fileService,
+				cacheManager,
+				contentCache,
+				configManager,
+			} = createStatusService();
+			const language = await configManager.getEffectiveLanguage();
+			const commandsDir = `${process.env.HOME || "/home"}/.claude/commands`;
+			for (const name of [
+				"listed",
+				"listed-de",
+				"listed-api",
+				"retired",
+				"mine",
+			]) {
+				await fileService.writeFile(
+					`${commandsDir}/${name}.md`,
+					`---\ndescription: ${name}\n---\n\n# ${name}`,
+				);
+			}
+			await cacheManager.set(language, {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [
+					{
+						name: "listed",
+						description: "Still upstream",
+						file: "listed.md",
+						"allowed-tools": [],
+					},
+				],
+			});
+			await contentCache.set(language, "retired.md", "# retired");
+			await contentCache.set("de", "listed.md", "# listed");
+
+			const untracked = await statusService.findUntrackedCommands();
+
+			expect(untracked).toEqual([
+				{ name: "listed-api", file: "listed-api.md", reason: "local-only" },
+				{ name: "mine", file: "mine.md", reason: "local-only" },
+				{ name: "retired", file: "retired.md", reason: "removed-upstream" },
+			]);
+			expect((await statusService.getSystemStatus()).untracked).toEqual(
+				untracked ?? [],
+			);
+		});
+
+		test("should return null without a cached manifest", async () => {
+			const { statusService } = createStatusService();
+
+			expect(await statusService.findUntrackedCommands()).toBeNull();
+			expect((await statusService.getSystemStatus()).untracked).toBeUndefined();
+		});
+	});
+
+	describe("getAllLanguagesStatus", () => {
+		test("should report every cached language and mark the active one", async () => {
+			const { statusService, cacheManager, contentCache, configManager } =
+				createStatusService();
+			const active = await configManager.getEffectiveLanguage();
+			const other = active === "fr" ? "de" : "fr";
+			await cacheManager.set(other, {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [
+					{
+						name: "hello",
+						description: "Say hello",
+						file: "hello.md",
+						"allowed-tools": [],
+					},
+				],
+			});
+			await contentCache.set(other, "hello.md", "# Hello");
+
+			const status = await statusService.getAllLanguagesStatus();
+
+			expect(status.activeLanguage).toBe(active);
+			expect(status.languages.map((lang) => lang.language)).toEqual(
+				[active, other].sort(),
+			);
+			const activeStatus = status.languages.find((lang) => lang.active);
+			expect(activeStatus?.language).toBe(active);
+			expect(activeStatus?.hasManifest).toBe(false);
+			const otherStatus = status.languages.find(
+				(lang) => lang.language === other,
+			);
+			expect(otherStatus).toMatchObject({
+				active: false,
+				hasManifest: true,
+				isExpired: false,
+				commandCount: 1,
+				cachedFiles: 1,
+			});
+			expect(otherStatus?.filesAgeMs).toBeGreaterThanOrEqual(0);
+		});
+	});
+
+	describe("getDiskUsage", () => {
+		test("should measure caches per language and commands per namespace", async () => {
+			const { statusService, fileService, cacheManager, contentCache } =
+				createStatusService();
+			await cacheManager.set("fr", {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [],
+			});
+			await contentCache.set("fr", "hello.md", "# Bonjour");
+			const commandsDir = `${process.env.HOME || "/home"}/.claude/commands`;
+			await fileService.writeFile(`${commandsDir}/review.md`, "12345");
+			await fileService.writeFile(`${commandsDir}/frontend/a.md`, "123");
+			await fileService.writeFile(`${commandsDir}/frontend/b.md`, "1234567");
+			await fileService.writeFile(`${commandsDir}/frontend/notes.txt`, "xx");
+
+			const usage = await statusService.getDiskUsage();
+
+			const fr = usage.cache.find((lang) => lang.language === "fr");
+			expect(fr?.filesBytes).toBe(Buffer.byteLength("# Bonjour"));
+			expect(fr?.manifestBytes).toBeGreaterThan(0);
+			expect(fr?.sizeBytes).toBe((fr?.manifestBytes ?? 0) + 9);
+
+			const user = usage.installations.find(({ type }) => type === "user");
+			expect(user).toMatchObject({
+				commandCount: 3,
+				sizeBytes: 15,
+				namespaces: { frontend: 10 },
+			});
+			expect(usage.totalBytes).toBe(
+				usage.cache.reduce((sum, lang) => sum + lang.sizeBytes, 0) +
+					usage.installations.reduce((sum, dir) => sum + dir.sizeBytes, 0),
+			);
+		});
+	});
+
 	describe("getSystemStatus", () => {
 		test("should collect basic system status with no cache", async () => {
 			const { statusService, fileService } = createStatusService();
@@ -86,6 +239,32 @@ describe("StatusService", () => {
 			expect(status.health.status).toBe("healthy");
 		});
 
+		test("should count installed commands per namespace", async () => {
+			const { statusService, fileService } = createStatusService();
+			const commandsDir = `${process.env.HOME || "/home"}/.claude/commands`;
+			for (const file of [
+				"review.md",
+				"frontend/component.md",
+				"frontend/hook.md",
+				"backend/api/route.md",
+				"frontend/notes.txt",
+			]) {
+				await fileService.writeFile(
+					`${commandsDir}/${file}`,
+					"---\ndescription: Test\n---\n\n# Test",
+				);
+			}
+
+			const status = await statusService.getSystemStatus();
+
+			const user = status.installations.find(({ type }) => type === "user");
+			expect(user?.namespaces).toEqual({ "backend:api": 1, frontend: 2 });
+			expect(Object.keys(user?.namespaces ?? {})).toEqual([
+				"backend:api",
+				"frontend",
+			]);
+		});
+
 		test("should show cache status for existing cache files", async () => {
 			const { statusService, fileService, cacheManager } =
 				createStatusService();
@@ -154,6 +333,24 @@ describe("StatusService", () => {
 			expect(status.health.messages).toHaveLength(0);
 		});
 
+		test("should report stray empty namespace directories", async () => {
+			const { statusService, fileService } = createStatusService();
+
+			const homeDir = process.env.HOME || "/home";
+			const commandsDir = `${homeDir}/.claude/commands`;
+			await fileService.writeFile(`${commandsDir}/kept/command.md`, "# Kept");
+			await fileService.mkdir(`${commandsDir}/stale/nested`);
+
+			const status = await statusService.getSystemStatus();
+
+			const personalDir = status.installations.find((i) => i.type === "user");
+			expect(personalDir?.emptyDirectories).toEqual(["stale"]);
+			expect(status.health.status).toBe("healthy");
+			expect(status.health.messages).toEqual([
+				`Empty namespace directories in ${commandsDir}: stale (safe to delete)`,
+			]);
+		});
+
 		test("should handle degraded system state", async () => {
 			const { statusService, fileService } = createStatusService();
 
@@ -273,6 +470,155 @@ describe("StatusService", () => {
 		});
 	});
 
+	describe("recommendations", () => {
+		const issues = (status: SystemStatus) =>
+			status.health.recommendations.map(({ issue }) => issue);
+
+		test("should recommend a cache update without a cached manifest", async () => {
+			const { statusService } = createStatusService();
+
+			const status = await statusService.getSystemStatus();
+
+			const missing = status.health.recommendations.find(
+				({ issue }) => issue === "cache-missing",
+			);
+			expect(missing?.action).toBe("Run 'claude-cmd cache update'");
+			expect(status.health.score).toBeLessThan(100);
+		});
+
+		test("should report commands removed upstream", async () => {
+			const {
+				statusService,
+				fileService,
+				cacheManager,
+				contentCache,
+				configManager,
+			} = createStatusService();
+			const language = await configManager.getEffectiveLanguage();
+			const commandsDir = `${process.env.HOME || "/home"}/.claude/commands`;
+			await fileService.writeFile(
+				`${commandsDir}/retired.md`,
+				"---\ndescription: Retired\n---\n\n# Retired",
+			);
+			await cacheManager.set(language, {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [],
+			});
+			await contentCache.set(language, "retired.md", "# Retired");
+
+			const status = await statusService.getSystemStatus();
+
+			const removed = status.health.recommendations.find(
+				({ issue }) => issue === "removed-upstream",
+			);
+			expect(removed?.problem).toContain("retired");
+			expect(issues(status)).not.toContain("cache-missing");
+		});
+
+		test("should only check the repository when asked", async () => {
+			let probes = 0;
+			const { statusService } = createStatusService({
+				repositoryProbe: async () => {
+					probes++;
+					throw new Error("getaddrinfo ENOTFOUND");
+				},
+			});
+
+			const local = await statusService.getSystemStatus();
+			expect(probes).toBe(0);
+			expect(local.health.repositoryReachable).toBeUndefined();
+
+			const checked = await statusService.getSystemStatus({
+				checkRepository: true,
+			});
+			expect(probes).toBe(1);
+			expect(checked.health.repositoryReachable).toBe(false);
+			expect(issues(checked)[0]).toBe("repository-unreachable");
+			expect(checked.health.recommendations[0]?.problem).toContain(
+				"ENOTFOUND",
+			);
+		});
+
+		test("should order recommendations by severity", async () => {
+			const { statusService } = createStatusService({
+				repositoryProbe: async () => {
+					throw new Error("offline");
+				},
+			});
+
+			const { health } = await statusService.getSystemStatus({
+				checkRepository: true,
+			});
+
+			const ranks = health.recommendations.map(({ severity }) =>
+				["error", "warning", "info"].indexOf(severity),
+			);
+			expect(ranks).toEqual([...ranks].sort((a, b) => a - b));
+		});
+	});
+
+	describe("snapshots", () => {
+		test("should recompute on every call by default", async () => {
+			const clock = new FakeClock();
+			const { statusService } = createStatusService({ clock });
+
+			const first = await statusService.getSystemStatus();
+			clock.advance(1000);
+			const second = await statusService.getSystemStatus();
+
+			expect(second.timestamp).toBe(first.timestamp + 1000);
+		});
+
+		test("should serve a snapshot until its TTL expires", async () => {
+			const clock = new FakeClock();
+			const { statusService } = createStatusService({
+				clock,
+				snapshotTtlMs: 5000,
+			});
+
+			const first = await statusService.getSystemStatus();
+			clock.advance(4999);
+			expect(await statusService.getSystemStatus()).toBe(first);
+
+			clock.advance(1);
+			expect(await statusService.getSystemStatus()).not.toBe(first);
+		});
+
+		test("should recompute after invalidate", async () => {
+			const clock = new FakeClock();
+			const { statusService, cacheManager, configManager } =
+				createStatusService({ clock, snapshotTtlMs: 60000 });
+			const language = await configManager.getEffectiveLanguage();
+
+			const before = await statusService.getAllLanguagesStatus();
+			await cacheManager.set(language, {
+				version: "1.0.0",
+				updated: "2025-01-01T00:00:00Z",
+				commands: [],
+			});
+			expect(await statusService.getAllLanguagesStatus()).toBe(before);
+
+			statusService.invalidate();
+			const after = await statusService.getAllLanguagesStatus();
+			expect(
+				after.languages.find((status) => status.language === language)
+					?.hasManifest,
+			).toBe(true);
+		});
+
+		test("should share one computation between concurrent calls", async () => {
+			const { statusService } = createStatusService();
+
+			const [first, second] = await Promise.all([
+				statusService.getSystemStatus(),
+				statusService.getSystemStatus(),
+			]);
+
+			expect(second).toBe(first);
+		});
+	});
+
 	describe("error handling", () => {
 		test("should throw StatusError when critical error occurs", async () => {
 			// Create a statusService with a broken dependency
@@ -0,0 +1,124 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import type IHTTPClient from "../../src/interfaces/IHTTPClient.js";
+import type {
+	HTTPResponse,
+	HTTPStreamResponse,
+} from "../../src/interfaces/IHTTPClient.js";
+import { ThrottledHTTPClient } from "../../src/services/ThrottledHTTPClient.js";
+import FakeClock from "../mocks/FakeClock.js";
+
+/**
+ * Client that records request start times and concurrency
+ */
+class RecordingClient implements IHTTPClient {
+	readonly startedAt: number[] = [];
+	inFlight = 0;
+	maxInFlight = 0;
+
+	constructor(
+		private readonly clock: FakeClock,
+		private readonly body = "x",
+	) {}
+
+	async get(url: string): Promise<HTTPResponse> {
+		this.startedAt.push(this.clock.now());
+		this.inFlight++;
+		this.maxInFlight = Math.max(this.maxInFlight, this.inFlight);
+		await Promise.resolve();
+		this.inFlight--;
+		return {
+			status: 200,
+			statusText: "OK",
+			headers: {},
+			body: this.body,
+			url,
+		};
+	}
+
+	async stream(url: string): Promise<HTTPStreamResponse> {
+		const { body, ...response } = await this.get(url);
+		async function* chunks() {
+			yield Buffer.from(body);
+		}
+		return { ...response, body: chunks() };
+	}
+}
+
+describe("ThrottledHTTPClient", () => {
+	let clock: FakeClock;
+	let sleeps: number[];
+
+	beforeEach(() => {
+		clock = new FakeClock(0);
+		sleeps = [];
+	});
+
+	function throttled(
+		client: IHTTPClient,
+		limits: ConstructorParameters<typeof ThrottledHTTPClient>[1],
+	): ThrottledHTTPClient {
+		return new ThrottledHTTPClient(client, limits, clock, async (ms) => {
+			sleeps.push(ms);
+			clock.advance(ms);
+		});
+	}
+
+	test("should pass requests through without limits", async () => {
+		const inner = new RecordingClient(clock);
+		const client = throttled(inner, {});
+
+		await Promise.all(["a", "b", "c"].map((u) => client.get(`https://x/${u}`)));
+
+		expect(inner.startedAt).toEqual([0, 0, 0]);
+		expect(sleeps).toEqual([]);
+	});
+
+	test("should space requests to the configured rate", async () => {
+		const inner = new RecordingClient(clock);
+		const client = throttled(inner, { maxRequestsPerSecond: 4 });
+
+		for (const u of ["a", "b", "c"]) {
+			await client.get(`https://x/${u}`);
+		}
+
+		expect(inner.startedAt).toEqual([0, 250, 500]);
+	});
+
+	test("should cap requests in flight", async () => {
+		const inner = new RecordingClient(clock);
+		const client = throttled(inner, { maxParallelDownloads: 2 });
+
+		await Promise.all(
+			["a", "b", "c", "d", "e"].map((u) => client.get(`https://x/${u}`)),
+		);
+
+		expect(inner.startedAt).toHaveLength(5);
+		expect(inner.maxInFlight).toBeLessThanOrEqual(2);
+	});
+
+	test("should hold a slot until a streamed body is read", async () => {
+		const inner = new RecordingClient(clock);
+		const client = throttled(inner, { maxParallelDownloads: 1 });
+
+		const first = await client.stream("https://x/a");
+		const second = client.get("https://x/b");
+		await Promise.resolve();
+		expect(inner.startedAt).toHaveLength(1);
+
+		for await (const _chunk of first.body) {
+			// Drain the body
+		}
+		await second;
+		expect(inner.startedAt).toHaveLength(2);
+	});
+
+	test("should delay the next request until received bytes fit the cap", async () => {
+		const inner = new RecordingClient(clock, "x".repeat(500));
+		const client = throttled(inner, { maxBytesPerSecond: 1000 });
+
+		await client.get("https://x/a");
+		await client.get("https://x/b");
+
+		expect(inner.startedAt).toEqual([0, 500]);
+	});
+});
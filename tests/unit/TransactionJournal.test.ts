@@ -0,0 +1,115 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { FileIOError } from "../../src/interfaces/IFileService.js";
+import {
+	TransactionError,
+	TransactionJournal,
+} from "../../src/services/TransactionJournal.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const JOURNAL_DIR = "/home/user/.config/claude-cmd/journal";
+
+/**
+ * File service that fails when a given path is written, simulating a crash
+ */
+class CrashingFileService extends InMemoryFileService {
+	crashOn: string | null = null;
+
+	override async rename(from: string, to: string): Promise<void> {
+		if (to === this.crashOn) {
+			throw new FileIOError(to, "simulated crash");
+		}
+		return super.rename(from, to);
+	}
+}
+
+describe("TransactionJournal", () => {
+	let fileService: CrashingFileService;
+	let clock: FakeClock;
+	let journal: TransactionJournal;
+
+	beforeEach(() => {
+		fileService = new CrashingFileService({
+			"/commands/a.md": "old a",
+			"/commands/stale.md": "stale",
+		});
+		clock = new FakeClock();
+		journal = new TransactionJournal(fileService, JOURNAL_DIR, clock);
+	});
+
+	async function interruptedRun(): Promise<void> {
+		fileService.crashOn = "/commands/b.md";
+		await expect(
+			journal.run("bundle import (en)", [
+				{ type: "write", path: "/commands/a.md", content: "new a" },
+				{ type: "write", path: "/commands/b.md", content: "new b" },
+				{ type: "delete", path: "/commands/stale.md" },
+			]),
+		).rejects.toThrow(FileIOError);
+		fileService.crashOn = null;
+	}
+
+	test("should apply all operations and leave no journal", async () => {
+		await journal.run("update", [
+			{ type: "write", path: "/commands/a.md", content: "new a" },
+			{ type: "delete", path: "/commands/stale.md" },
+			{ type: "delete", path: "/commands/missing.md" },
+		]);
+
+		expect(await fileService.readFile("/commands/a.md")).toBe("new a");
+		expect(await fileService.exists("/commands/stale.md")).toBe(false);
+		expect(await journal.listPending()).toEqual([]);
+	});
+
+	test("should report an interrupted transaction", async () => {
+		await interruptedRun();
+
+		const pending = await journal.listPending();
+
+		expect(pending).toHaveLength(1);
+		expect(pending[0]?.name).toBe("bundle import (en)");
+		expect(pending[0]?.startedAt).toBe(clock.now());
+		expect(pending[0]?.paths).toEqual([
+			"/commands/a.md",
+			"/commands/b.md",
+			"/commands/stale.md",
+		]);
+	});
+
+	test("should restore previous content on rollback", async () => {
+		await interruptedRun();
+		const [pending] = await journal.listPending();
+
+		await journal.rollback(pending?.id ?? "");
+
+		expect(await fileService.readFile("/commands/a.md")).toBe("old a");
+		expect(await fileService.exists("/commands/b.md")).toBe(false);
+		expect(await fileService.readFile("/commands/stale.md")).toBe("stale");
+		expect(await journal.listPending()).toEqual([]);
+	});
+
+	test("should apply remaining operations on complete", async () => {
+		await interruptedRun();
+		const [pending] = await journal.listPending();
+
+		await journal.complete(pending?.id ?? "");
+
+		expect(await fileService.readFile("/commands/a.md")).toBe("new a");
+		expect(await fileService.readFile("/commands/b.md")).toBe("new b");
+		expect(await fileService.exists("/commands/stale.md")).toBe(false);
+		expect(await journal.listPending()).toEqual([]);
+	});
+
+	test("should reject unknown transaction ids", async () => {
+		await expect(journal.rollback("nope")).rejects.toThrow(TransactionError);
+		await expect(journal.complete("../escape")).rejects.toThrow(
+			TransactionError,
+		);
+	});
+
+	test("should ignore unreadable journal files", async () => {
+		await fileService.writeFile(`${JOURNAL_DIR}/broken.json`, "{not json");
+
+		expect(await journal.listPending()).toEqual([]);
+	});
+});
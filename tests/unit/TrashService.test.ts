@@ -0,0 +1,86 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	MAX_TRASH_ENTRIES,
+	TRASH_RETENTION_MS,
+	TrashService,
+} from "../../src/services/TrashService.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const TRASH_DIR = "/home/user/.cache/claude-cmd/trash";
+
+describe("TrashService", () => {
+	let fileService: InMemoryFileService;
+	let clock: FakeClock;
+	let trash: TrashService;
+
+	const removed = (name: string) => ({
+		name,
+		location: "personal" as const,
+		originalPath: `/home/user/.claude/commands/${name}.md`,
+	});
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		clock = new FakeClock();
+		trash = new TrashService(fileService, TRASH_DIR, clock);
+	});
+
+	test("should list removed commands newest first", async () => {
+		await trash.put(removed("a"), "content a");
+		clock.advance(1000);
+		await trash.put(removed("b"), "content b");
+
+		expect((await trash.list()).map((entry) => entry.name)).toEqual([
+			"b",
+			"a",
+		]);
+	});
+
+	test("should find the most recent version of a command", async () => {
+		await trash.put(removed("a"), "first");
+		clock.advance(1000);
+		await trash.put(removed("a"), "second");
+
+		const found = await trash.find("a");
+
+		expect(found?.content).toBe("second");
+		expect(found?.entry.originalPath).toBe("/home/user/.claude/commands/a.md");
+		expect(await trash.list()).toHaveLength(2);
+	});
+
+	test("should delete an entry by id", async () => {
+		const entry = await trash.put(removed("a"), "content a");
+
+		await trash.delete(entry.id);
+		await trash.delete(entry.id);
+
+		expect(await trash.list()).toEqual([]);
+	});
+
+	test("should filter by location", async () => {
+		await trash.put(removed("a"), "personal a");
+
+		expect(await trash.find("a", "project")).toBeNull();
+		expect((await trash.find("a", "personal"))?.content).toBe("personal a");
+	});
+
+	test("should prune expired entries when trashing", async () => {
+		await trash.put(removed("old"), "old");
+		clock.advance(TRASH_RETENTION_MS + 1);
+		await trash.put(removed("new"), "new");
+
+		expect((await trash.list()).map((entry) => entry.name)).toEqual(["new"]);
+	});
+
+	test("should keep at most MAX_TRASH_ENTRIES entries", async () => {
+		for (let i = 0; i <= MAX_TRASH_ENTRIES; i++) {
+			clock.advance(1);
+			await trash.put(removed(`c${i}`), "content");
+		}
+
+		const entries = await trash.list();
+		expect(entries).toHaveLength(MAX_TRASH_ENTRIES);
+		expect(entries.some((entry) => entry.name === "c0")).toBe(false);
+	});
+});
@@ -0,0 +1,143 @@
+import { beforeEach, describe, expect, spyOn, test } from "bun:test";
+import { HTTPNetworkError } from "../../src/interfaces/IHTTPClient.js";
+import { SelfUpdateService } from "../../src/services/SelfUpdateService.js";
+import {
+	UPDATE_CHECK_RETRY_MS,
+	UPDATE_CHECK_TTL_MS,
+	UpdateNotifier,
+} from "../../src/services/UpdateNotifier.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
+
+const API_URL =
+	"https://api.github.com/repos/claude-code-commands/claude-cmd/releases/latest";
+const STATE_PATH = "/home/user/.cache/claude-cmd/update-check.json";
+
+describe("UpdateNotifier", () => {
+	let httpClient: InMemoryHTTPClient;
+	let fileService: InMemoryFileService;
+	let clock: FakeClock;
+	let notifier: UpdateNotifier;
+
+	const setLatest = (tag: string) => {
+		httpClient.setResponse(API_URL, {
+			status: 200,
+			statusText: "OK",
+			headers: {},
+			body: JSON.stringify({ tag_name: tag, assets: [] }),
+			url: API_URL,
+		});
+	};
+	const lookups = () =>
+		httpClient.getRequestHistory().filter(({ url }) => url === API_URL).length;
+
+	beforeEach(() => {
+		httpClient = new InMemoryHTTPClient();
+		fileService = new InMemoryFileService();
+		clock = new FakeClock();
+		notifier = new UpdateNotifier(
+			new SelfUpdateService(httpClient, fileService),
+			fileService,
+			STATE_PATH,
+			clock,
+		);
+		setLatest("v1.2.0");
+	});
+
+	const check = async (currentVersion: string, timeoutMs = 1000) => {
+		const newer = await notifier.getNewerVersion(currentVersion, timeoutMs);
+		await notifier.whenRefreshed();
+		return newer;
+	};
+
+	test("should report a newer release once it has been looked up", async () => {
+		expect(await check("1.1.0")).toBeUndefined();
+		expect(await check("1.1.0")).toBe("1.2.0");
+		expect(lookups()).toBe(1);
+	});
+
+	test("should report nothing when up to date", async () => {
+		await check("1.2.0");
+
+		expect(await check("1.2.0")).toBeUndefined();
+	});
+
+	test("should not wait for the lookup", async () => {
+		let finish = () => {};
+		spyOn(httpClient, "get").mockImplementation(
+			() =>
+				new Promise((_resolve, reject) => {
+					finish = () => reject(new HTTPNetworkError(API_URL, "offline"));
+				}),
+		);
+		await notifier.record("1.2.0");
+		clock.advance(UPDATE_CHECK_TTL_MS);
+
+		expect(await notifier.getNewerVersion("1.1.0", 1000)).toBe("1.2.0");
+		finish();
+		await notifier.whenRefreshed();
+	});
+
+	test("should look up the latest release at most once per day", async () => {
+		await check("1.1.0");
+		setLatest("v1.3.0");
+		clock.advance(UPDATE_CHECK_TTL_MS - 1);
+
+		expect(await check("1.1.0")).toBe("1.2.0");
+		expect(lookups()).toBe(1);
+
+		clock.advance(1);
+		await check("1.1.0");
+		expect(await check("1.1.0")).toBe("1.3.0");
+		expect(lookups()).toBe(2);
+	});
+
+	test("should use a recorded version without a lookup", async () => {
+		await notifier.record("2.0.0");
+
+		expect(await check("1.1.0")).toBe("2.0.0");
+		expect(lookups()).toBe(0);
+	});
+
+	test("should keep the stale record when the lookup fails", async () => {
+		await notifier.record("1.2.0");
+		clock.advance(UPDATE_CHECK_TTL_MS);
+		httpClient.setResponse(API_URL, new HTTPNetworkError(API_URL, "offline"));
+
+		expect(await check("1.1.0")).toBe("1.2.0");
+		expect(await check("1.1.0")).toBe("1.2.0");
+	});
+
+	test("should not retry a failed lookup before the retry delay", async () => {
+		httpClient.setResponse(API_URL, new HTTPNetworkError(API_URL, "offline"));
+
+		await check("1.1.0");
+		clock.advance(UPDATE_CHECK_RETRY_MS - 1);
+		await check("1.1.0");
+		expect(lookups()).toBe(1);
+
+		setLatest("v1.2.0");
+		clock.advance(1);
+		await check("1.1.0");
+		expect(lookups()).toBe(2);
+		expect(await check("1.1.0")).toBe("1.2.0");
+	});
+
+	test("should abort the lookup once the timeout passes", async () => {
+		await notifier.getNewerVersion("1.1.0", 10);
+		const signal = httpClient.getRequestHistory()[0]?.options?.signal;
+
+		expect(signal?.aborted).toBe(false);
+		await new Promise((resolve) => setTimeout(resolve, 50));
+		expect(signal?.aborted).toBe(true);
+		await notifier.whenRefreshed();
+	});
+
+	test("should ignore a malformed state file", async () => {
+		await fileService.writeFile(STATE_PATH, "{not json");
+
+		await check("1.1.0");
+		expect(await check("1.1.0")).toBe("1.2.0");
+	});
+});
@@ -0,0 +1,113 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	MAX_HISTORY_ENTRIES,
+	UsageStatsService,
+} from "../../src/services/UsageStatsService.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const STATS_PATH = "/home/user/.config/claude-cmd/stats.json";
+
+describe("UsageStatsService", () => {
+	let fileService: InMemoryFileService;
+	let clock: FakeClock;
+	let stats: UsageStatsService;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		clock = new FakeClock();
+		stats = new UsageStatsService(fileService, STATS_PATH, clock);
+	});
+
+	test("should start empty when no stats file exists", async () => {
+		expect(await stats.getCommandUsage()).toEqual([]);
+		expect(await stats.getHistory()).toEqual([]);
+	});
+
+	test("should count installs and removals per command", async () => {
+		await stats.record({ command: "review", action: "install" });
+		clock.advance(1000);
+		await stats.record({ command: "review", action: "remove" });
+		clock.advance(1000);
+		await stats.record({ command: "review", action: "install" });
+
+		expect(await stats.getCommandUsage()).toEqual([
+			{
+				command: "review",
+				installs: 2,
+				removals: 1,
+				lastUsedAt: clock.now(),
+			},
+		]);
+	});
+
+	test("should order commands by installs, then most recent use", async () => {
+		await stats.record({ command: "alpha", action: "install" });
+		clock.advance(1000);
+		await stats.record({ command: "beta", action: "install" });
+		clock.advance(1000);
+		await stats.record({ command: "gamma", action: "install" });
+		await stats.record({ command: "gamma", action: "install" });
+
+		const usage = await stats.getCommandUsage();
+
+		expect(usage.map((entry) => entry.command)).toEqual([
+			"gamma",
+			"beta",
+			"alpha",
+		]);
+	});
+
+	test("should return history most recent first", async () => {
+		await stats.record({
+			command: "review",
+			action: "install",
+			language: "en",
+			target: "personal",
+		});
+		clock.advance(1000);
+		await stats.record({ command: "review", action: "remove" });
+
+		const history = await stats.getHistory();
+
+		expect(history.map((event) => event.action)).toEqual([
+			"remove",
+			"install",
+		]);
+		expect(history[1]).toEqual({
+			command: "review",
+			action: "install",
+			language: "en",
+			target: "personal",
+			at: clock.now() - 1000,
+		});
+		expect(await stats.getHistory(1)).toHaveLength(1);
+	});
+
+	test("should keep a bounded history", async () => {
+		for (let i = 0; i < MAX_HISTORY_ENTRIES + 5; i++) {
+			await stats.record({ command: `cmd-${i}`, action: "install" });
+		}
+
+		const history = await stats.getHistory();
+
+		expect(history).toHaveLength(MAX_HISTORY_ENTRIES);
+		expect(history[0]?.command).toBe(`cmd-${MAX_HISTORY_ENTRIES + 4}`);
+	});
+
+	test("should start over when the stats file is unreadable", async () => {
+		fileService.setFile(STATS_PATH, "{not json");
+
+		await stats.record({ command: "review", action: "install" });
+
+		expect(await stats.getCommandUsage()).toHaveLength(1);
+	});
+
+	test("should clear recorded statistics", async () => {
+		await stats.record({ command: "review", action: "install" });
+
+		expect(await stats.clear()).toBe(true);
+		expect(await stats.getCommandUsage()).toEqual([]);
+		expect(await stats.clear()).toBe(false);
+	});
+});
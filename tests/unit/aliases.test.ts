@@ -0,0 +1,64 @@
+import { describe, expect, test } from "bun:test";
+import { parseAliasDefinition } from "../../src/cli/commands/alias.js";
+import {
+	formatAliasTarget,
+	isAliasMap,
+	parseAliasTarget,
+} from "../../src/utils/aliases.js";
+
+describe("parseAliasTarget", () => {
+	test("should read a location prefix", () => {
+		expect(parseAliasTarget("project:backend:code-review")).toEqual({
+			name: "backend:code-review",
+			location: "project",
+		});
+	});
+
+	test("should accept plain and namespaced names", () => {
+		expect(parseAliasTarget("backend:code-review")).toEqual({
+			name: "backend:code-review",
+		});
+	});
+
+	test("should reject unsafe names", () => {
+		expect(parseAliasTarget("personal:../secrets")).toBeUndefined();
+		expect(parseAliasTarget("")).toBeUndefined();
+	});
+
+	test("should round-trip through formatAliasTarget", () => {
+		const target = parseAliasTarget("personal:review");
+
+		expect(target && formatAliasTarget(target)).toBe("personal:review");
+	});
+});
+
+describe("isAliasMap", () => {
+	test("should accept aliases to command names", () => {
+		expect(isAliasMap({ review: "project:backend:code-review" })).toBe(true);
+	});
+
+	test("should reject other shapes", () => {
+		expect(isAliasMap(["review"])).toBe(false);
+		expect(isAliasMap({ review: 1 })).toBe(false);
+		expect(isAliasMap({ "../x": "review" })).toBe(false);
+	});
+});
+
+describe("parseAliasDefinition", () => {
+	test("should split alias and target", () => {
+		expect(parseAliasDefinition("review=project:backend:code-review")).toEqual(
+			{
+				alias: "review",
+				target: { name: "backend:code-review", location: "project" },
+			},
+		);
+	});
+
+	test("should require both sides", () => {
+		expect(() => parseAliasDefinition("review")).toThrow(
+			"Must be <alias>=<command-name>.",
+		);
+		expect(() => parseAliasDefinition("=review")).toThrow();
+		expect(() => parseAliasDefinition("review=")).toThrow("Target must be");
+	});
+});
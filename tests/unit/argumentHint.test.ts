@@ -0,0 +1,46 @@
+import { describe, expect, test } from "bun:test";
+import {
+	argumentHintProblem,
+	MAX_ARGUMENT_HINT_LENGTH,
+	normalizeArgumentHint,
+} from "../../src/utils/argumentHint.js";
+
+describe("normalizeArgumentHint", () => {
+	test("should trim string hints", () => {
+		expect(normalizeArgumentHint("  <file> [line] ")).toBe("<file> [line]");
+	});
+
+	test("should write YAML lists back as placeholders", () => {
+		expect(normalizeArgumentHint(["message"])).toBe("[message]");
+		expect(normalizeArgumentHint(["pr", "priority"])).toBe("[pr] [priority]");
+	});
+
+	test("should ignore empty hints and other types", () => {
+		expect(normalizeArgumentHint("")).toBeUndefined();
+		expect(normalizeArgumentHint(undefined)).toBeUndefined();
+		expect(normalizeArgumentHint(42)).toBeUndefined();
+		expect(normalizeArgumentHint([1])).toBeUndefined();
+	});
+});
+
+describe("argumentHintProblem", () => {
+	test("should accept well-formed hints", () => {
+		expect(
+			argumentHintProblem("add [tagId] | remove [tagId] | list"),
+		).toBeUndefined();
+		expect(argumentHintProblem("<file> [line]")).toBeUndefined();
+	});
+
+	test("should report unbalanced and nested placeholders", () => {
+		expect(argumentHintProblem("[file")).toMatch(/unclosed/);
+		expect(argumentHintProblem("file]")).toMatch(/unmatched/);
+		expect(argumentHintProblem("[<file>]")).toMatch(/nested/);
+	});
+
+	test("should report multi-line and overlong hints", () => {
+		expect(argumentHintProblem("[a]\n[b]")).toMatch(/one line/);
+		expect(
+			argumentHintProblem("x".repeat(MAX_ARGUMENT_HINT_LENGTH + 1)),
+		).toMatch(/longer/);
+	});
+});
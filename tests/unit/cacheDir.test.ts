@@ -0,0 +1,132 @@
+import { describe, expect, test } from "bun:test";
+import * as os from "node:os";
+import * as path from "node:path";
+import {
+	CACHE_DIR_ENV,
+	defaultCacheDir,
+	legacyCacheDirs,
+	migrateLegacyCacheDir,
+	resolveCacheDir,
+} from "../../src/utils/cacheDir.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+/**
+ * In-memory file service recording directory moves
+ */
+class RecordingFileService extends InMemoryFileService {
+	readonly moves: Array<[string, string]> = [];
+
+	override async rename(from: string, to: string): Promise<void> {
+		this.moves.push([from, to]);
+	}
+}
+
+describe("cacheDir", () => {
+	describe("defaultCacheDir", () => {
+		test("should honor an absolute XDG_CACHE_HOME on Linux", () => {
+			expect(
+				defaultCacheDir(
+					{ XDG_CACHE_HOME: "/var/cache/me" },
+					"linux",
+					"/home/me",
+				),
+			).toBe("/var/cache/me/claude-cmd");
+		});
+
+		test("should ignore a relative XDG_CACHE_HOME", () => {
+			expect(
+				defaultCacheDir({ XDG_CACHE_HOME: "cache" }, "linux", "/home/me"),
+			).toBe("/home/me/.cache/claude-cmd");
+		});
+
+		test("should use Library/Caches on macOS", () => {
+			expect(defaultCacheDir({}, "darwin", "/Users/me")).toBe(
+				"/Users/me/Library/Caches/claude-cmd",
+			);
+		});
+
+		test("should use LOCALAPPDATA on Windows", () => {
+			expect(
+				defaultCacheDir(
+					{ LOCALAPPDATA: "C:\\Users\\me\\AppData\\Local" },
+					"win32",
+					"C:\\Users\\me",
+				),
+			).toBe("C:\\Users\\me\\AppData\\Local\\claude-cmd");
+		});
+	});
+
+	describe("resolveCacheDir", () => {
+		test("should prefer the environment over the configured directory", () => {
+			expect(
+				resolveCacheDir("/configured", { [CACHE_DIR_ENV]: "/from-env" }),
+			).toBe("/from-env");
+		});
+
+		test("should use the configured directory without the environment", () => {
+			expect(resolveCacheDir("~/my-cache", {})).toBe(
+				path.join(os.homedir(), "my-cache"),
+			);
+		});
+
+		test("should fall back to the platform default", () => {
+			expect(resolveCacheDir(undefined, {})).toBe(defaultCacheDir({}));
+		});
+	});
+
+	describe("migrateLegacyCacheDir", () => {
+		test("should move the first old cache found", async () => {
+			const fileService = new RecordingFileService();
+			await fileService.writeFile("/home/me/.old-cache/manifest.json", "{}");
+
+			const moved = await migrateLegacyCacheDir(
+				fileService,
+				"/cache/new",
+				["/home/me/.cache/claude-cmd", "/home/me/.old-cache"],
+				"/cache/new",
+			);
+
+			expect(moved).toBe("/home/me/.old-cache");
+			expect(fileService.moves).toEqual([
+				["/home/me/.old-cache", "/cache/new"],
+			]);
+		});
+
+		test("should not touch an existing cache", async () => {
+			const fileService = new RecordingFileService();
+			await fileService.writeFile("/cache/new/manifest.json", "{}");
+			await fileService.writeFile("/home/me/.old-cache/manifest.json", "{}");
+
+			expect(
+				await migrateLegacyCacheDir(
+					fileService,
+					"/cache/new",
+					["/home/me/.old-cache"],
+					"/cache/new",
+				),
+			).toBeNull();
+			expect(fileService.moves).toEqual([]);
+		});
+
+		test("should not move into an overridden cache directory", async () => {
+			const fileService = new RecordingFileService();
+			await fileService.writeFile("/home/me/.old-cache/manifest.json", "{}");
+
+			expect(
+				await migrateLegacyCacheDir(
+					fileService,
+					"/custom/cache",
+					["/home/me/.old-cache"],
+					"/cache/new",
+				),
+			).toBeNull();
+			expect(fileService.moves).toEqual([]);
+		});
+
+		test("should not adopt caches from shared directories", () => {
+			expect(legacyCacheDirs("/home/me")).toEqual([
+				"/home/me/.cache/claude-cmd",
+			]);
+		});
+	});
+});
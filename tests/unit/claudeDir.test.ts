@@ -0,0 +1,42 @@
+import { describe, expect, test } from "bun:test";
+import * as os from "node:os";
+import * as path from "node:path";
+import { resolveClaudeDir } from "../../src/utils/claudeDir.js";
+
+describe("claudeDir", () => {
+	describe("resolveClaudeDir", () => {
+		test("should default to ~/.claude", () => {
+			expect(resolveClaudeDir(undefined, {}, "/home/me")).toBe(
+				"/home/me/.claude",
+			);
+		});
+
+		test("should prefer CLAUDE_CONFIG_DIR over everything else", () => {
+			expect(
+				resolveClaudeDir(
+					"/configured",
+					{ CLAUDE_CONFIG_DIR: "/from-env", CLAUDE_HOME: "/alias" },
+					"/home/me",
+				),
+			).toBe("/from-env");
+		});
+
+		test("should accept CLAUDE_HOME as an alias", () => {
+			expect(
+				resolveClaudeDir("/configured", { CLAUDE_HOME: "/alias" }, "/home/me"),
+			).toBe("/alias");
+		});
+
+		test("should ignore blank environment values", () => {
+			expect(
+				resolveClaudeDir("/configured", { CLAUDE_CONFIG_DIR: " " }, "/home/me"),
+			).toBe("/configured");
+		});
+
+		test("should expand ~ in the configured directory", () => {
+			expect(resolveClaudeDir("~/dotfiles/claude", {})).toBe(
+				path.join(os.homedir(), "dotfiles", "claude"),
+			);
+		});
+	});
+});
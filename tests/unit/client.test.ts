@@ -0,0 +1,78 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import { ClaudeCmdClient } from "../../src/client.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+describe("ClaudeCmdClient", () => {
+	const userConfigPath = "/config/config.claude-cmd.json";
+	let fileService: InMemoryFileService;
+	let client: ClaudeCmdClient;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		client = ClaudeCmdClient.create({
+			fileService,
+			clock: new FakeClock(),
+			cacheDir: "/cache",
+			userConfigPath,
+			projectConfigPath: "/project/.claude/config.claude-cmd.json",
+			projectRoot: "/project",
+			claudeDir: "/claude",
+		});
+	});
+
+	describe("resolveInstallOptions", () => {
+		test("should default the target to personal", async () => {
+			const options = await client.resolveInstallOptions({ language: "en" });
+
+			expect(options).toEqual({
+				language: "en",
+				target: "personal",
+				provenanceSource: undefined,
+			});
+		});
+
+		test("should apply the configured target and provenance", async () => {
+			await fileService.writeFile(
+				userConfigPath,
+				JSON.stringify({
+					defaultTarget: "project",
+					recordProvenance: true,
+					repositoryURL: "https://example.com/commands",
+				}),
+			);
+
+			const options = await client.resolveInstallOptions({
+				language: "fr",
+				force: true,
+			});
+
+			expect(options).toEqual({
+				language: "fr",
+				force: true,
+				target: "project",
+				provenanceSource: "https://example.com/commands",
+			});
+		});
+
+		test("should prefer the given target over the configured one", async () => {
+			await fileService.writeFile(
+				userConfigPath,
+				JSON.stringify({ defaultTarget: "project" }),
+			);
+
+			const options = await client.resolveInstallOptions({
+				language: "en",
+				target: "personal",
+			});
+
+			expect(options.target).toBe("personal");
+		});
+
+		test("should default the language to the effective language", async () => {
+			const options = await client.resolveInstallOptions();
+
+			expect(options.language).toBe(await client.getLanguage());
+		});
+	});
+});
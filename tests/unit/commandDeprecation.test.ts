@@ -0,0 +1,60 @@
+import { describe, expect, test } from "bun:test";
+import type { Command } from "../../src/types/Command.js";
+import {
+	findDeprecatedInstalled,
+	formatDeprecation,
+	isDeprecated,
+} from "../../src/utils/commandDeprecation.js";
+
+const command = (overrides: Partial<Command> = {}): Command => ({
+	name: "old-review",
+	description: "Review changes",
+	file: "old-review.md",
+	"allowed-tools": [],
+	...overrides,
+});
+
+describe("isDeprecated", () => {
+	test("should accept true and non-empty notes", () => {
+		expect(isDeprecated(command({ deprecated: true }))).toBe(true);
+		expect(isDeprecated(command({ deprecated: "use review" }))).toBe(true);
+	});
+
+	test("should ignore false, empty notes and missing flags", () => {
+		expect(isDeprecated(command({ deprecated: false }))).toBe(false);
+		expect(isDeprecated(command({ deprecated: " " }))).toBe(false);
+		expect(isDeprecated(command())).toBe(false);
+	});
+});
+
+describe("formatDeprecation", () => {
+	test("should include the note and replacement", () => {
+		expect(
+			formatDeprecation(
+				command({ deprecated: "merged into review", replaced_by: "review" }),
+			),
+		).toBe("deprecated: merged into review; use 'review' instead");
+	});
+
+	test("should work without a note or replacement", () => {
+		expect(formatDeprecation(command({ deprecated: true }))).toBe(
+			"deprecated",
+		);
+	});
+});
+
+describe("findDeprecatedInstalled", () => {
+	test("should return installed commands that are deprecated", () => {
+		const commands = [
+			command({ deprecated: true }),
+			command({ name: "legacy", deprecated: true }),
+			command({ name: "review" }),
+		];
+
+		expect(
+			findDeprecatedInstalled(["old-review", "review"], commands).map(
+				(c) => c.name,
+			),
+		).toEqual(["old-review"]);
+	});
+});
@@ -0,0 +1,63 @@
+import { describe, expect, test } from "bun:test";
+import { Command } from "commander";
+import {
+	getCommandGroup,
+	inGroup,
+	registerCommands,
+} from "../../src/cli/commandGroups.js";
+
+describe("commandGroups", () => {
+	test("inGroup annotates a command with its group", () => {
+		const command = inGroup(new Command("list"), "Discover");
+
+		expect(getCommandGroup(command)).toBe("Discover");
+		expect(command.helpGroup()).toBe("Discover:");
+	});
+
+	test("getCommandGroup returns undefined for unannotated commands", () => {
+		expect(getCommandGroup(new Command("plain"))).toBeUndefined();
+	});
+
+	test("registerCommands orders commands by group, keeping order within a group", () => {
+		const program = new Command("claude-cmd");
+		registerCommands(program, [
+			inGroup(new Command("status"), "Maintain"),
+			new Command("plain"),
+			inGroup(new Command("search"), "Discover"),
+			inGroup(new Command("add"), "Install"),
+			inGroup(new Command("list"), "Discover"),
+			inGroup(new Command("language"), "Configure"),
+		]);
+
+		expect(program.commands.map((command) => command.name())).toEqual([
+			"search",
+			"list",
+			"add",
+			"status",
+			"language",
+			"plain",
+		]);
+	});
+
+	test("registerCommands puts unannotated commands in Advanced", () => {
+		const program = new Command("claude-cmd");
+		const plain = new Command("plain");
+
+		registerCommands(program, [plain]);
+
+		expect(getCommandGroup(plain)).toBe("Advanced");
+	});
+
+	test("root help lists group headings in order", () => {
+		const program = new Command("claude-cmd");
+		registerCommands(program, [
+			inGroup(new Command("add"), "Install"),
+			inGroup(new Command("list"), "Discover"),
+		]);
+
+		const help = program.helpInformation();
+
+		expect(help.indexOf("Discover:")).toBeGreaterThan(-1);
+		expect(help.indexOf("Discover:")).toBeLessThan(help.indexOf("Install:"));
+	});
+});
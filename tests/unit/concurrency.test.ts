@@ -0,0 +1,49 @@
+import { describe, expect, test } from "bun:test";
+import { mapConcurrent } from "../../src/utils/concurrency.js";
+
+const tick = () => new Promise((resolve) => setTimeout(resolve, 1));
+
+describe("mapConcurrent", () => {
+	test("should keep results in input order", async () => {
+		const results = await mapConcurrent([30, 10, 20], 3, async (ms) => {
+			await new Promise((resolve) => setTimeout(resolve, ms));
+			return ms * 2;
+		});
+
+		expect(results).toEqual([60, 20, 40]);
+	});
+
+	test("should never exceed the concurrency limit", async () => {
+		let active = 0;
+		let maxActive = 0;
+
+		await mapConcurrent(
+			Array.from({ length: 20 }, (_, i) => i),
+			3,
+			async () => {
+				active++;
+				maxActive = Math.max(maxActive, active);
+				await tick();
+				active--;
+			},
+		);
+
+		expect(maxActive).toBe(3);
+	});
+
+	test("should handle empty input and limits below one", async () => {
+		expect(await mapConcurrent([], 4, async (x) => x)).toEqual([]);
+		expect(await mapConcurrent([1, 2], 0, async (x) => x + 1)).toEqual([2, 3]);
+	});
+
+	test("should reject with the first error", async () => {
+		await expect(
+			mapConcurrent([1, 2, 3], 2, async (x) => {
+				if (x === 2) {
+					throw new Error("two");
+				}
+				return x;
+			}),
+		).rejects.toThrow("two");
+	});
+});
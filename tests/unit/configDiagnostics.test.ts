@@ -0,0 +1,92 @@
+import { describe, expect, test } from "bun:test";
+import {
+	closestKey,
+	diagnoseConfig,
+	formatDiagnostic,
+} from "../../src/utils/configDiagnostics.js";
+
+describe("configDiagnostics", () => {
+	describe("diagnoseConfig", () => {
+		test("should accept a valid configuration", () => {
+			const content =
+				'{\n  "preferredLanguage": "fr",\n  "previewLines": 20\n}';
+
+			expect(diagnoseConfig(content)).toEqual([]);
+		});
+
+		test("should locate unknown keys and suggest the intended one", () => {
+			expect(diagnoseConfig('{\n  "languge": "fr"\n}')).toEqual([
+				{
+					severity: "error",
+					message: "unknown key 'languge' (did you mean 'preferredLanguage'?)",
+					key: "languge",
+					line: 2,
+					column: 3,
+				},
+			]);
+		});
+
+		test("should report values that do not fit their key", () => {
+			const [diagnostic] = diagnoseConfig(
+				'{"strictLanguage": "yes", "repositoryType": "git"}',
+			);
+
+			expect(diagnostic?.message).toBe(
+				'invalid value for strictLanguage: "yes" (expected true or false)',
+			);
+			expect(diagnostic?.column).toBe(2);
+		});
+
+		test("should locate syntax errors", () => {
+			expect(
+				diagnoseConfig('{\n  "previewLines": 20\n  "strictLanguage": true\n}'),
+			).toEqual([
+				{
+					severity: "error",
+					message: "unexpected '\"', expected ',' or '}'",
+					line: 3,
+					column: 3,
+				},
+			]);
+		});
+
+		test("should reject trailing commas and non-object files", () => {
+			expect(diagnoseConfig('{"previewLines": 20,}')[0]?.message).toBe(
+				"unexpected '}', trailing comma is not allowed",
+			);
+			expect(diagnoseConfig("[]")[0]?.message).toBe(
+				"configuration must be a JSON object",
+			);
+			expect(diagnoseConfig("  ")[0]?.message).toBe("file is empty");
+		});
+
+		test("should warn about duplicate keys", () => {
+			const diagnostics = diagnoseConfig(
+				'{"previewLines": 10, "previewLines": 20}',
+			);
+
+			expect(diagnostics).toHaveLength(1);
+			expect(diagnostics[0]?.severity).toBe("warning");
+		});
+	});
+
+	describe("closestKey", () => {
+		test("should ignore keys that are too different", () => {
+			expect(closestKey("previewLine")).toBe("previewLines");
+			expect(closestKey("telemetry")).toBeUndefined();
+		});
+	});
+
+	describe("formatDiagnostic", () => {
+		test("should format as path:line:column", () => {
+			expect(
+				formatDiagnostic("config.json", {
+					severity: "error",
+					message: "unknown key 'x'",
+					line: 2,
+					column: 3,
+				}),
+			).toBe("config.json:2:3: error: unknown key 'x'");
+		});
+	});
+});
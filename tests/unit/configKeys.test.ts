@@ -0,0 +1,109 @@
+import { describe, expect, test } from "bun:test";
+import { InvalidConfigError } from "../../src/interfaces/IConfigService.js";
+import { getConfigKey, parseConfigValue } from "../../src/utils/configKeys.js";
+
+describe("configKeys", () => {
+	describe("getConfigKey", () => {
+		test("should describe known keys", () => {
+			expect(getConfigKey("strictLanguage").type).toBe("boolean");
+		});
+
+		test("should reject unknown keys", () => {
+			expect(() => getConfigKey("languge")).toThrow(InvalidConfigError);
+			expect(() => getConfigKey("toString")).toThrow(
+				"Unknown configuration key 'toString'",
+			);
+		});
+	});
+
+	describe("parseConfigValue", () => {
+		test("should parse booleans", () => {
+			expect(parseConfigValue("strictLanguage", "true")).toBe(true);
+			expect(parseConfigValue("strictLanguage", "OFF")).toBe(false);
+			expect(() => parseConfigValue("strictLanguage", "maybe")).toThrow(
+				"expected true or false",
+			);
+		});
+
+		test("should parse positive integers", () => {
+			expect(parseConfigValue("previewLines", "20")).toBe(20);
+			expect(() => parseConfigValue("previewLines", "2.5")).toThrow(
+				"expected a positive integer",
+			);
+			expect(() => parseConfigValue("previewLines", "0")).toThrow(
+				"expected a positive integer",
+			);
+		});
+
+		test("should parse positive numbers", () => {
+			expect(parseConfigValue("maxRequestsPerSecond", "0.5")).toBe(0.5);
+			expect(() => parseConfigValue("maxRequestsPerSecond", "-1")).toThrow(
+				"expected a positive number",
+			);
+		});
+
+		test("should restrict enumerated strings", () => {
+			expect(parseConfigValue("repositoryType", "git")).toBe("git");
+			expect(() => parseConfigValue("repositoryType", "svn")).toThrow(
+				"expected one of http, git",
+			);
+		});
+
+		test("should split lists on commas", () => {
+			expect(parseConfigValue("commands", "review, debug-help")).toEqual([
+				"review",
+				"debug-help",
+			]);
+			expect(() => parseConfigValue("commands", "../escape")).toThrow(
+				"'../escape' is not a command name",
+			);
+		});
+
+		test("should only accept http(s) and file:// additional repositories", () => {
+			expect(
+				parseConfigValue(
+					"additionalRepositories",
+					"https://example.com/commands, file:///srv/commands",
+				),
+			).toEqual(["https://example.com/commands", "file:///srv/commands"]);
+			expect(() =>
+				parseConfigValue("additionalRepositories", "git@host:acme/x.git"),
+			).toThrow("'git@host:acme/x.git' is not an http(s) or file:// URL");
+		});
+
+		test("should accept directory name patterns for scanIgnore", () => {
+			expect(parseConfigValue("scanIgnore", "node_modules, build-*")).toEqual([
+				"node_modules",
+				"build-*",
+			]);
+			expect(() => parseConfigValue("scanIgnore", "src/vendor")).toThrow(
+				"'src/vendor' is not a directory name pattern",
+			);
+		});
+
+		test("should parse maps as JSON", () => {
+			expect(
+				parseConfigValue(
+					"toolConsent",
+					'{"review": {"allow": true, "tools": ["Bash(git:*)"]}}',
+				),
+			).toEqual({ review: { allow: true, tools: ["Bash(git:*)"] } });
+			expect(() => parseConfigValue("toolConsent", "review")).toThrow(
+				"expected a JSON object",
+			);
+			expect(() =>
+				parseConfigValue("toolConsent", '{"review": {"allow": "yes"}}'),
+			).toThrow("expected {");
+		});
+
+		test("should reject malformed manifest public keys", () => {
+			expect(() => parseConfigValue("manifestPublicKey", "RWQ=")).toThrow(
+				"Malformed public key",
+			);
+		});
+
+		test("should keep plain strings", () => {
+			expect(parseConfigValue("preferredLanguage", "fr")).toBe("fr");
+		});
+	});
+});
@@ -0,0 +1,83 @@
+import { afterEach, beforeEach, describe, expect, spyOn, test } from "bun:test";
+import {
+	backupCommandFile,
+	promptConflictResolution,
+} from "../../src/cli/conflictResolution.js";
+import { CommandExistsError } from "../../src/types/Installation.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryUserInteractionService from "../mocks/InMemoryUserInteractionService.js";
+
+describe("promptConflictResolution", () => {
+	const existingPath = "/home/.claude/commands/test.md";
+	let fileService: InMemoryFileService;
+	let userInteractionService: InMemoryUserInteractionService;
+	let logSpy: ReturnType<typeof spyOn>;
+	let logged: string[];
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService({ [existingPath]: "old\nline" });
+		userInteractionService = new InMemoryUserInteractionService();
+		logged = [];
+		logSpy = spyOn(console, "log").mockImplementation((message) => {
+			logged.push(String(message));
+		});
+	});
+
+	afterEach(() => {
+		logSpy.mockRestore();
+	});
+
+	const conflict = () => new CommandExistsError("test", existingPath);
+
+	test("should return the chosen resolution", async () => {
+		userInteractionService.setChoiceResponses("backup");
+
+		const resolution = await promptConflictResolution(
+			conflict(),
+			async () => "new",
+			{ userInteractionService, fileService },
+		);
+
+		expect(resolution).toBe("backup");
+	});
+
+	test("should show the diff and ask again", async () => {
+		userInteractionService.setChoiceResponses("diff", "overwrite");
+
+		const resolution = await promptConflictResolution(
+			conflict(),
+			async () => "new\nline",
+			{ userInteractionService, fileService },
+		);
+
+		expect(resolution).toBe("overwrite");
+		expect(logged).toEqual(["- old\n+ new\n  line"]);
+		expect(userInteractionService.getInteractionHistory()).toHaveLength(2);
+	});
+
+	test("should return undefined when no terminal is available", async () => {
+		const resolution = await promptConflictResolution(
+			conflict(),
+			async () => "new",
+			{ userInteractionService, fileService },
+		);
+
+		expect(resolution).toBeUndefined();
+	});
+});
+
+describe("backupCommandFile", () => {
+	test("should move the file to the first free .bak name", async () => {
+		const fileService = new InMemoryFileService({
+			"/cmds/test.md": "current",
+			"/cmds/test.md.bak": "older",
+		});
+
+		const backupPath = await backupCommandFile(fileService, "/cmds/test.md");
+
+		expect(backupPath).toBe("/cmds/test.md.bak.1");
+		expect(await fileService.readFile(backupPath)).toBe("current");
+		expect(await fileService.exists("/cmds/test.md")).toBe(false);
+		expect(await fileService.readFile("/cmds/test.md.bak")).toBe("older");
+	});
+});
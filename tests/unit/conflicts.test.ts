@@ -0,0 +1,100 @@
+import { describe, expect, test } from "bun:test";
+import * as path from "node:path";
+import {
+	findScopeConflicts,
+	formatConflicts,
+	type ScopeConflictReport,
+} from "../../src/cli/commands/conflicts.js";
+import type { InstallationInfo } from "../../src/types/Installation.js";
+
+const installed = (
+	name: string,
+	location: "personal" | "project",
+): InstallationInfo => ({
+	name,
+	filePath:
+		location === "personal"
+			? `/home/user/.claude/commands/${name}.md`
+			: `/project/.claude/commands/${name}.md`,
+	location,
+	installedAt: new Date(0),
+	size: 0,
+	source: "repository",
+	metadata: { language: "en" },
+});
+
+describe("findScopeConflicts", () => {
+	test("should pair commands installed in both scopes", () => {
+		const conflicts = findScopeConflicts([
+			installed("review", "personal"),
+			installed("review", "project"),
+			installed("deploy", "personal"),
+			installed("lint", "project"),
+		]);
+
+		expect(conflicts).toEqual([
+			{
+				name: "review",
+				project: installed("review", "project"),
+				personal: installed("review", "personal"),
+			},
+		]);
+	});
+
+	test("should find nothing when scopes do not overlap", () => {
+		expect(
+			findScopeConflicts([
+				installed("deploy", "personal"),
+				installed("lint", "project"),
+			]),
+		).toEqual([]);
+	});
+});
+
+describe("formatConflicts", () => {
+	const report = (
+		name: string,
+		added: number,
+		removed: number,
+		diff = "",
+	): ScopeConflictReport => ({
+		name,
+		project: installed(name, "project"),
+		personal: installed(name, "personal"),
+		added,
+		removed,
+		diff,
+	});
+
+	test("should report when there are no conflicts", () => {
+		expect(formatConflicts([])).toBe(
+			"No command is installed both in the project and personally.",
+		);
+	});
+
+	test("should show which copy wins and how the copies differ", () => {
+		const output = formatConflicts([
+			report("lint", 0, 0),
+			report("review", 2, 1),
+		]);
+
+		expect(output).toContain("2 commands are installed both");
+		expect(output).toContain("lint (identical)");
+		expect(output).toContain(
+			"review (differs: +2 -1 lines in the project copy)",
+		);
+		expect(output).toContain(
+			`uses:     ${path.resolve("/project/.claude/commands/review.md")}`,
+		);
+		expect(output).toContain(
+			`shadowed: ${path.resolve("/home/user/.claude/commands/review.md")}`,
+		);
+	});
+
+	test("should include diffs only when asked", () => {
+		const reports = [report("review", 1, 1, "- old\n+ new")];
+
+		expect(formatConflicts(reports)).not.toContain("+ new");
+		expect(formatConflicts(reports, true)).toContain("    - old\n    + new");
+	});
+});
@@ -0,0 +1,61 @@
+import { describe, expect, test } from "bun:test";
+import {
+	DependencyCycleError,
+	normalizeRequires,
+	resolveDependencies,
+} from "../../src/utils/dependencies.js";
+
+describe("normalizeRequires", () => {
+	test("should accept arrays and comma-separated strings", () => {
+		expect(normalizeRequires(["lint", " format "])).toEqual([
+			"lint",
+			"format",
+		]);
+		expect(normalizeRequires("lint, format,,lint")).toEqual([
+			"lint",
+			"format",
+		]);
+	});
+
+	test("should ignore missing and malformed values", () => {
+		expect(normalizeRequires(undefined)).toEqual([]);
+		expect(normalizeRequires(42)).toEqual([]);
+		expect(normalizeRequires(["lint", 7])).toEqual(["lint"]);
+	});
+});
+
+describe("resolveDependencies", () => {
+	const graph =
+		(edges: Record<string, string[]>) =>
+		async (name: string): Promise<string[]> =>
+			edges[name] ?? [];
+
+	test("should order requirements before the commands that need them", async () => {
+		const order = await resolveDependencies(
+			"review",
+			graph({ review: ["lint", "test"], lint: ["format"], test: ["format"] }),
+		);
+
+		expect(order).toEqual(["format", "lint", "test"]);
+	});
+
+	test("should return nothing for commands without requirements", async () => {
+		expect(await resolveDependencies("solo", graph({}))).toEqual([]);
+	});
+
+	test("should report the cycle path", async () => {
+		const error = await resolveDependencies(
+			"a",
+			graph({ a: ["b"], b: ["c"], c: ["b"] }),
+		).catch((e: unknown) => e);
+
+		expect(error).toBeInstanceOf(DependencyCycleError);
+		expect((error as DependencyCycleError).cycle).toEqual(["b", "c", "b"]);
+	});
+
+	test("should reject commands that require themselves", async () => {
+		await expect(
+			resolveDependencies("a", graph({ a: ["a"] })),
+		).rejects.toThrow("Circular command requirements: a -> a");
+	});
+});
@@ -0,0 +1,191 @@
+import { afterEach, beforeEach, describe, expect, spyOn, test } from "bun:test";
+import { join } from "node:path";
+import { Command } from "commander";
+import "../../src/cli/commands/add.js";
+import "../../src/cli/commands/alias.js";
+import "../../src/cli/commands/bundle.js";
+import "../../src/cli/commands/cache.js";
+import "../../src/cli/commands/completion.js";
+import "../../src/cli/commands/config.js";
+import "../../src/cli/commands/conflicts.js";
+import "../../src/cli/commands/copy.js";
+import "../../src/cli/commands/edit.js";
+import "../../src/cli/commands/export.js";
+import "../../src/cli/commands/grep.js";
+import "../../src/cli/commands/import.js";
+import "../../src/cli/commands/info.js";
+import "../../src/cli/commands/init.js";
+import "../../src/cli/commands/installed.js";
+import "../../src/cli/commands/language.js";
+import "../../src/cli/commands/list.js";
+import "../../src/cli/commands/manifest.js";
+import "../../src/cli/commands/mv.js";
+import "../../src/cli/commands/recover.js";
+import "../../src/cli/commands/remove.js";
+import "../../src/cli/commands/repo.js";
+import "../../src/cli/commands/restore.js";
+import "../../src/cli/commands/search.js";
+import "../../src/cli/commands/selfUpdate.js";
+import "../../src/cli/commands/show.js";
+import "../../src/cli/commands/stats.js";
+import "../../src/cli/commands/status.js";
+import "../../src/cli/commands/undo.js";
+import "../../src/cli/commands/version.js";
+import "../../src/cli/commands/watch.js";
+import "../../src/cli/commands/whatsnew.js";
+import "../../src/cli/commands/which.js";
+import {
+	DeprecationError,
+	deprecateCommand,
+	deprecateOption,
+	formatDeprecation,
+	getOverdueDeprecations,
+	reportDeprecation,
+	resetDeprecationWarnings,
+} from "../../src/cli/deprecation.js";
+
+describe("deprecation", () => {
+	let warnings: string[];
+	let warnSpy: ReturnType<typeof spyOn>;
+
+	beforeEach(() => {
+		resetDeprecationWarnings();
+		warnings = [];
+		warnSpy = spyOn(console, "warn").mockImplementation((message) => {
+			warnings.push(String(message));
+		});
+	});
+
+	afterEach(() => {
+		warnSpy.mockRestore();
+	});
+
+	function createProgram(): { program: Command; ran: string[] } {
+		const ran: string[] = [];
+		const program = new Command("claude-cmd").exitOverride();
+		const old = new Command("old").action(() => {
+			ran.push("old");
+		});
+		const list = new Command("list")
+			.option("--format <format>", "Output format", "default")
+			.option("--output <format>", "Output format")
+			.action(() => {
+				ran.push("list");
+			});
+		program.addCommand(old);
+		program.addCommand(list);
+
+		deprecateCommand(old, {
+			since: "0.1.0",
+			removeIn: "1.0.0",
+			replacement: "claude-cmd new",
+		});
+		deprecateOption(list, "--format", {
+			since: "0.1.0",
+			removeIn: "1.0.0",
+			replacement: "--output",
+		});
+		return { program, ran };
+	}
+
+	test("should warn when a deprecated subcommand runs", async () => {
+		const { program, ran } = createProgram();
+
+		await program.parseAsync(["old"], { from: "user" });
+
+		expect(ran).toEqual(["old"]);
+		expect(warnings).toEqual([
+			"Warning: 'claude-cmd old' is deprecated since v0.1.0 and will be removed in v1.0.0. Use 'claude-cmd new' instead.",
+		]);
+	});
+
+	test("should mark deprecated items in help", () => {
+		const { program } = createProgram();
+
+		const help = program.helpInformation();
+		expect(help).toContain("(deprecated, use 'claude-cmd new')");
+		const listHelp = program.commands[1]?.helpInformation() ?? "";
+		expect(listHelp).toContain("(deprecated, use '--output')");
+	});
+
+	test("should warn about options only when given on the command line", async () => {
+		const { program } = createProgram();
+
+		await program.parseAsync(["list"], { from: "user" });
+		expect(warnings).toEqual([]);
+
+		await program.parseAsync(["list", "--format", "json"], { from: "user" });
+		expect(warnings).toHaveLength(1);
+		expect(warnings[0]).toContain("'claude-cmd list --format'");
+	});
+
+	test("should warn once per invocation", () => {
+		const deprecation = {
+			usage: "claude-cmd old",
+			since: "0.1.0",
+			removeIn: "1.0.0",
+		};
+
+		reportDeprecation(deprecation, {});
+		reportDeprecation(deprecation, {});
+
+		expect(warnings).toHaveLength(1);
+	});
+
+	test("should throw in strict mode", () => {
+		const deprecation = {
+			usage: "claude-cmd old",
+			since: "0.1.0",
+			removeIn: "1.0.0",
+		};
+
+		expect(() =>
+			reportDeprecation(deprecation, {
+				CLAUDE_CMD_STRICT_DEPRECATIONS: "1",
+			}),
+		).toThrow(DeprecationError);
+		expect(warnings).toEqual([]);
+	});
+
+	test("should format without a replacement", () => {
+		expect(
+			formatDeprecation({
+				usage: "claude-cmd old",
+				since: "0.1.0",
+				removeIn: "0.10.0",
+			}),
+		).toBe(
+			"'claude-cmd old' is deprecated since v0.1.0 and will be removed in v0.10.0.",
+		);
+	});
+
+	test("should reject unknown options", () => {
+		expect(() =>
+			deprecateOption(new Command("list"), "--missing", {
+				since: "0.1.0",
+				removeIn: "1.0.0",
+			}),
+		).toThrow("Unknown option --missing");
+	});
+
+	test("should report deprecations past their removal version", () => {
+		createProgram();
+
+		const overdue = getOverdueDeprecations("1.0.0").map((d) => d.usage);
+		expect(overdue).toContain("claude-cmd old");
+		expect(getOverdueDeprecations("0.9.9").map((d) => d.usage)).not.toContain(
+			"claude-cmd old",
+		);
+	});
+
+	test("shipped commands have no overdue deprecations", async () => {
+		const packageJson = await Bun.file(
+			join(import.meta.dir, "../../package.json"),
+		).json();
+
+		const overdue = getOverdueDeprecations(packageJson.version).filter(
+			(deprecation) => deprecation.usage !== "claude-cmd old",
+		);
+		expect(overdue).toEqual([]);
+	});
+});
@@ -0,0 +1,51 @@
+import { describe, expect, test } from "bun:test";
+import {
+	countDiffLines,
+	diffLines,
+	formatLineDiff,
+} from "../../src/utils/diff.js";
+
+describe("diffLines", () => {
+	test("should tag unchanged, removed and added lines in order", () => {
+		expect(diffLines("a\nb\nc", "a\nx\nc")).toEqual([
+			{ kind: "same", text: "a" },
+			{ kind: "removed", text: "b" },
+			{ kind: "added", text: "x" },
+			{ kind: "same", text: "c" },
+		]);
+	});
+
+	test("should handle appended and truncated text", () => {
+		expect(diffLines("a", "a\nb")).toEqual([
+			{ kind: "same", text: "a" },
+			{ kind: "added", text: "b" },
+		]);
+		expect(diffLines("a\nb", "a")).toEqual([
+			{ kind: "same", text: "a" },
+			{ kind: "removed", text: "b" },
+		]);
+	});
+});
+
+describe("formatLineDiff", () => {
+	test("should mark changed lines with - and +", () => {
+		expect(formatLineDiff("a\nb", "a\nc")).toBe("  a\n- b\n+ c");
+	});
+
+	test("should return an empty string for identical text", () => {
+		expect(formatLineDiff("same\ntext", "same\ntext")).toBe("");
+	});
+});
+
+describe("countDiffLines", () => {
+	test("should count added and removed lines", () => {
+		expect(countDiffLines("a\nb\nc", "a\nx\ny\nc")).toEqual({
+			added: 2,
+			removed: 1,
+		});
+	});
+
+	test("should count nothing for identical text", () => {
+		expect(countDiffLines("a\nb", "a\nb")).toEqual({ added: 0, removed: 0 });
+	});
+});
@@ -0,0 +1,22 @@
+import { describe, expect, test } from "bun:test";
+import { resolveEditor } from "../../src/cli/editor.js";
+
+describe("resolveEditor", () => {
+	test("should prefer $VISUAL over $EDITOR", () => {
+		expect(resolveEditor({ VISUAL: "nvim", EDITOR: "nano" }, "linux")).toEqual([
+			"nvim",
+		]);
+	});
+
+	test("should fall back to $EDITOR and split arguments", () => {
+		expect(resolveEditor({ EDITOR: "code --wait" }, "linux")).toEqual([
+			"code",
+			"--wait",
+		]);
+	});
+
+	test("should use a platform default when nothing is configured", () => {
+		expect(resolveEditor({}, "linux")).toEqual(["vi"]);
+		expect(resolveEditor({ EDITOR: "  " }, "win32")).toEqual(["notepad"]);
+	});
+});
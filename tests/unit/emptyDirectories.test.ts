@@ -0,0 +1,104 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import {
+	findEmptyDirectories,
+	removeEmptyParentDirectories,
+} from "../../src/utils/emptyDirectories.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const BASE_DIR = "/home/user/.claude/commands";
+
+describe("emptyDirectories", () => {
+	let fileService: InMemoryFileService;
+
+	beforeEach(async () => {
+		fileService = new InMemoryFileService();
+		await fileService.mkdir(BASE_DIR);
+	});
+
+	describe("removeEmptyParentDirectories", () => {
+		test("should remove every empty parent up to the base directory", async () => {
+			const filePath = `${BASE_DIR}/a/b/c/command.md`;
+			await fileService.writeFile(filePath, "# Command");
+			await fileService.deleteFile(filePath);
+
+			const removed = await removeEmptyParentDirectories(
+				fileService,
+				filePath,
+				BASE_DIR,
+			);
+
+			expect(removed).toEqual([
+				`${BASE_DIR}/a/b/c`,
+				`${BASE_DIR}/a/b`,
+				`${BASE_DIR}/a`,
+			]);
+			expect(await fileService.exists(BASE_DIR)).toBe(true);
+		});
+
+		test("should stop at the first directory with content", async () => {
+			const filePath = `${BASE_DIR}/a/b/command.md`;
+			await fileService.writeFile(filePath, "# Command");
+			await fileService.writeFile(`${BASE_DIR}/a/sibling.md`, "# Sibling");
+			await fileService.deleteFile(filePath);
+
+			const removed = await removeEmptyParentDirectories(
+				fileService,
+				filePath,
+				BASE_DIR,
+			);
+
+			expect(removed).toEqual([`${BASE_DIR}/a/b`]);
+			expect(await fileService.exists(`${BASE_DIR}/a/sibling.md`)).toBe(true);
+		});
+
+		test("should never touch directories outside the base directory", async () => {
+			const filePath = "/elsewhere/dir/command.md";
+			await fileService.writeFile(filePath, "# Command");
+			await fileService.deleteFile(filePath);
+
+			const removed = await removeEmptyParentDirectories(
+				fileService,
+				filePath,
+				BASE_DIR,
+			);
+
+			expect(removed).toEqual([]);
+			expect(await fileService.exists("/elsewhere/dir")).toBe(true);
+		});
+	});
+
+	describe("findEmptyDirectories", () => {
+		test("should report only the outermost empty directory", async () => {
+			await fileService.mkdir(`${BASE_DIR}/stale/nested/deeper`);
+			await fileService.writeFile(`${BASE_DIR}/used/command.md`, "# Used");
+			await fileService.mkdir(`${BASE_DIR}/used/empty`);
+
+			expect(await findEmptyDirectories(fileService, BASE_DIR)).toEqual([
+				"stale",
+				"used/empty",
+			]);
+		});
+
+		test("should return nothing for a tidy directory", async () => {
+			await fileService.writeFile(`${BASE_DIR}/ns/command.md`, "# Command");
+
+			expect(await findEmptyDirectories(fileService, BASE_DIR)).toEqual([]);
+		});
+
+		test("should not report pruned directories or their parents", async () => {
+			await fileService.writeFile(
+				`${BASE_DIR}/ns/node_modules/pkg/readme.md`,
+				"# Readme",
+			);
+			await fileService.writeFile(`${BASE_DIR}/a/b/c/deep.md`, "# Deep");
+			await fileService.mkdir(`${BASE_DIR}/stale`);
+
+			expect(
+				await findEmptyDirectories(fileService, BASE_DIR, {
+					maxDepth: 2,
+					ignore: ["node_modules"],
+				}),
+			).toEqual(["stale"]);
+		});
+	});
+});
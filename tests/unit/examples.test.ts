@@ -0,0 +1,102 @@
+import { describe, expect, test } from "bun:test";
+import {
+	formatExample,
+	parseExamples,
+	parseManifestExamples,
+} from "../../src/utils/examples.js";
+
+describe("parseExamples", () => {
+	test("should accept arguments and invocations", () => {
+		expect(
+			parseExamples(
+				["src/app.ts", "/review --staged", "/frontend:review", ""],
+				"frontend:review",
+			).examples,
+		).toEqual([
+			{ arguments: "src/app.ts" },
+			{ arguments: "--staged" },
+			{ arguments: "" },
+			{ arguments: "" },
+		]);
+	});
+
+	test("should accept entries with a description", () => {
+		expect(
+			parseExamples(
+				[{ args: "--staged", description: " Review the staged changes " }],
+				"review",
+			),
+		).toEqual({
+			examples: [
+				{ arguments: "--staged", description: "Review the staged changes" },
+			],
+			problems: [],
+		});
+	});
+
+	test("should report entries that cannot be run as shown", () => {
+		const { examples, problems } = parseExamples(
+			["ok", "/deploy prod", "two\nlines", 42, { args: "x", description: 1 }],
+			"review",
+		);
+
+		expect(examples).toEqual([{ arguments: "ok" }]);
+		expect(problems).toEqual([
+			"example 2: runs /deploy, not /review",
+			"example 3: must fit on one line",
+			"example 4: expected arguments, an invocation or {args, description}",
+			"example 5: description must be a string",
+		]);
+	});
+
+	test("should treat a missing field as no examples", () => {
+		expect(parseExamples(undefined, "review")).toEqual({
+			examples: [],
+			problems: [],
+		});
+		expect(parseExamples("src/app.ts", "review").problems).toEqual([
+			"examples must be a list",
+		]);
+	});
+});
+
+describe("parseManifestExamples", () => {
+	test("should accept parsed examples and frontmatter entries", () => {
+		expect(
+			parseManifestExamples(
+				[
+					{ arguments: "--staged", description: "Review the staged changes" },
+					{ arguments: "" },
+					"/review src/app.ts",
+					{ args: "--all" },
+				],
+				"review",
+			),
+		).toEqual({
+			examples: [
+				{ arguments: "--staged", description: "Review the staged changes" },
+				{ arguments: "" },
+				{ arguments: "src/app.ts" },
+				{ arguments: "--all" },
+			],
+			problems: [],
+		});
+	});
+
+	test("should report entries that cannot be run as shown", () => {
+		expect(parseManifestExamples([{ arguments: 42 }], "review").problems).toEqual([
+			"example 1: expected arguments, an invocation or {args, description}",
+		]);
+	});
+});
+
+describe("formatExample", () => {
+	test("should show the slash command that runs the example", () => {
+		expect(formatExample("review", { arguments: "--staged" })).toBe(
+			"/review --staged",
+		);
+		expect(formatExample("team:review", { arguments: "" })).toBe(
+			"/team:review",
+		);
+	});
+});
@@ -0,0 +1,80 @@
+import { describe, expect, test } from "bun:test";
+import { ExitCode, exitCodeForError } from "../../src/cli/exitCodes.js";
+import { FileNotFoundError } from "../../src/interfaces/IFileService.js";
+import {
+	HTTPNetworkError,
+	HTTPTimeoutError,
+} from "../../src/interfaces/IHTTPClient.js";
+import { CacheError } from "../../src/services/CacheManager.js";
+import { ManifestSchemaError } from "../../src/services/ManifestMigrations.js";
+import { CommandServiceError } from "../../src/services/shared/CommandServiceError.js";
+import {
+	CommandNotFoundError,
+	ManifestError,
+	ManifestSignatureError,
+} from "../../src/types/Command.js";
+import { CommandNotInstalledError } from "../../src/types/Installation.js";
+import { UnsafeCommandNameError } from "../../src/utils/namespace.js";
+
+describe("exitCodeForError", () => {
+	test("should map missing commands and files to NotFound", () => {
+		expect(exitCodeForError(new CommandNotFoundError("x", "en"))).toBe(
+			ExitCode.NotFound,
+		);
+		expect(exitCodeForError(new CommandNotInstalledError("x"))).toBe(
+			ExitCode.NotFound,
+		);
+		expect(exitCodeForError(new FileNotFoundError("/x"))).toBe(
+			ExitCode.NotFound,
+		);
+	});
+
+	test("should map network and repository failures to Network", () => {
+		expect(exitCodeForError(new HTTPNetworkError("https://x"))).toBe(
+			ExitCode.Network,
+		);
+		expect(exitCodeForError(new HTTPTimeoutError("https://x", 5000))).toBe(
+			ExitCode.Network,
+		);
+		expect(exitCodeForError(new ManifestError("en", "offline"))).toBe(
+			ExitCode.Network,
+		);
+	});
+
+	test("should not treat unsupported or unsigned manifests as network failures", () => {
+		expect(exitCodeForError(new ManifestSchemaError("en", 99))).toBe(
+			ExitCode.Failure,
+		);
+		expect(
+			exitCodeForError(new ManifestSignatureError("en", "not signed")),
+		).toBe(ExitCode.Failure);
+	});
+
+	test("should map cache failures to CacheCorrupted", () => {
+		expect(exitCodeForError(new CacheError("broken", "en"))).toBe(
+			ExitCode.CacheCorrupted,
+		);
+	});
+
+	test("should map invalid names to Validation", () => {
+		expect(exitCodeForError(new UnsafeCommandNameError("bad", "../x"))).toBe(
+			ExitCode.Validation,
+		);
+	});
+
+	test("should classify wrapper errors by their cause", () => {
+		const wrapped = new CommandServiceError(
+			"listCommands failed",
+			"listCommands",
+			"en",
+			new ManifestError("en", "offline"),
+		);
+
+		expect(exitCodeForError(wrapped)).toBe(ExitCode.Network);
+	});
+
+	test("should fall back to Failure", () => {
+		expect(exitCodeForError(new Error("boom"))).toBe(ExitCode.Failure);
+		expect(exitCodeForError("boom")).toBe(ExitCode.Failure);
+	});
+});
@@ -0,0 +1,152 @@
+import { beforeEach, describe, expect, test } from "bun:test";
+import * as os from "node:os";
+import { LockTimeoutError, withFileLock } from "../../src/utils/fileLock.js";
+import FakeClock from "../mocks/FakeClock.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+
+const LOCK_PATH = "/cache/en/manifest.json.lock";
+
+describe("withFileLock", () => {
+	let fileService: InMemoryFileService;
+	let clock: FakeClock;
+
+	beforeEach(() => {
+		fileService = new InMemoryFileService();
+		clock = new FakeClock();
+	});
+
+	function lockContent(overrides: Record<string, unknown> = {}): string {
+		return JSON.stringify({
+			pid: process.pid,
+			hostname: os.hostname(),
+			acquiredAt: clock.now(),
+			token: "other",
+			...overrides,
+		});
+	}
+
+	test("should run the function and remove the lock afterwards", async () => {
+		const result = await withFileLock(fileService, LOCK_PATH, async () => {
+			expect(await fileService.exists(LOCK_PATH)).toBe(true);
+			return 42;
+		});
+
+		expect(result).toBe(42);
+		expect(await fileService.exists(LOCK_PATH)).toBe(false);
+	});
+
+	test("should remove the lock when the function throws", async () => {
+		await expect(
+			withFileLock(fileService, LOCK_PATH, async () => {
+				throw new Error("boom");
+			}),
+		).rejects.toThrow("boom");
+
+		expect(await fileService.exists(LOCK_PATH)).toBe(false);
+	});
+
+	test("should serialize concurrent holders", async () => {
+		let active = 0;
+		let maxActive = 0;
+		const order: number[] = [];
+
+		await Promise.all(
+			Array.from({ length: 10 }, (_, index) =>
+				withFileLock(
+					fileService,
+					LOCK_PATH,
+					async () => {
+						active++;
+						maxActive = Math.max(maxActive, active);
+						await new Promise((resolve) => setTimeout(resolve, 2));
+						order.push(index);
+						active--;
+					},
+					{ clock, retryDelayMs: 1, timeoutMs: 5000 },
+				),
+			),
+		);
+
+		expect(maxActive).toBe(1);
+		expect(order.sort((a, b) => a - b)).toEqual([
+			0, 1, 2, 3, 4, 5, 6, 7, 8, 9,
+		]);
+		expect(await fileService.exists(LOCK_PATH)).toBe(false);
+	});
+
+	test("should time out while a live lock is held", async () => {
+		await fileService.writeFile(LOCK_PATH, lockContent());
+
+		await expect(
+			withFileLock(fileService, LOCK_PATH, async () => {}, {
+				clock,
+				retryDelayMs: 1,
+				timeoutMs: 5,
+			}),
+		).rejects.toThrow(LockTimeoutError);
+		expect(await fileService.readFile(LOCK_PATH)).toBe(lockContent());
+	});
+
+	test("should take over a lock older than the stale age", async () => {
+		await fileService.writeFile(LOCK_PATH, lockContent());
+		clock.advance(30001);
+
+		let ran = false;
+		await withFileLock(
+			fileService,
+			LOCK_PATH,
+			async () => {
+				ran = true;
+			},
+			{ clock, retryDelayMs: 1, timeoutMs: 5 },
+		);
+
+		expect(ran).toBe(true);
+		expect(await fileService.exists(LOCK_PATH)).toBe(false);
+	});
+
+	test("should take over a lock whose process is gone", async () => {
+		await fileService.writeFile(LOCK_PATH, lockContent({ pid: 2 ** 30 }));
+
+		let ran = false;
+		await withFileLock(
+			fileService,
+			LOCK_PATH,
+			async () => {
+				ran = true;
+			},
+			{ clock, retryDelayMs: 1, timeoutMs: 5 },
+		);
+
+		expect(ran).toBe(true);
+	});
+
+	test("should not judge process liveness across hosts", async () => {
+		await fileService.writeFile(
+			LOCK_PATH,
+			lockContent({ pid: 2 ** 30, hostname: `not-${os.hostname()}` }),
+		);
+
+		await expect(
+			withFileLock(fileService, LOCK_PATH, async () => {}, {
+				clock,
+				retryDelayMs: 1,
+				timeoutMs: 5,
+			}),
+		).rejects.toThrow(LockTimeoutError);
+	});
+
+	test("should not remove a lock taken over by someone else", async () => {
+		await withFileLock(
+			fileService,
+			LOCK_PATH,
+			async () => {
+				// Simulate another process recovering our lock as stale
+				await fileService.writeFile(LOCK_PATH, lockContent());
+			},
+			{ clock },
+		);
+
+		expect(await fileService.readFile(LOCK_PATH)).toBe(lockContent());
+	});
+});
@@ -0,0 +1,90 @@
+import { describe, expect, test } from "bun:test";
+import {
+	formatCount,
+	formatQuantity,
+	formatRelativeAge,
+	truncatePreview,
+	truncateText,
+} from "../../src/utils/format.js";
+
+describe("truncatePreview", () => {
+	const content = ["one", "two", "three", "four"].join("\n");
+
+	test("should keep content within the limits unchanged", () => {
+		expect(
+			truncatePreview(content, { maxLines: 4, maxCharacters: 100 }),
+		).toEqual({ preview: content, omittedLines: 0 });
+	});
+
+	test("should cut at the line limit", () => {
+		expect(
+			truncatePreview(content, { maxLines: 2, maxCharacters: 100 }),
+		).toEqual({ preview: "one\ntwo", omittedLines: 2 });
+	});
+
+	test("should cut at the character limit", () => {
+		expect(
+			truncatePreview(content, { maxLines: 10, maxCharacters: 6 }),
+		).toEqual({ preview: "one\ntw", omittedLines: 2 });
+	});
+
+	test("should count a partially shown last line as shown", () => {
+		expect(
+			truncatePreview("abcdef", { maxLines: 1, maxCharacters: 3 }),
+		).toEqual({ preview: "abc", omittedLines: 1 });
+	});
+});
+
+describe("truncateText", () => {
+	test("should keep text that fits", () => {
+		expect(truncateText("review", 6)).toBe("review");
+	});
+
+	test("should mark cut text with an ellipsis", () => {
+		expect(truncateText("review code", 7)).toBe("review…");
+	});
+});
+
+describe("formatRelativeAge", () => {
+	test("should use the largest whole unit", () => {
+		expect(formatRelativeAge(45 * 1000, "en")).toBe("45 seconds ago");
+		expect(formatRelativeAge(3 * 60 * 60 * 1000 + 5000, "en")).toBe(
+			"3 hours ago",
+		);
+		expect(formatRelativeAge(3 * 24 * 60 * 60 * 1000, "en")).toBe(
+			"3 days ago",
+		);
+	});
+
+	test("should write ages in the given language", () => {
+		expect(formatRelativeAge(3 * 24 * 60 * 60 * 1000, "fr")).toBe(
+			"il y a 3 jours",
+		);
+	});
+});
+
+describe("formatCount", () => {
+	test("should group digits the way the language does", () => {
+		expect(formatCount(12345, "en")).toBe("12,345");
+		expect(formatCount(12345, "de")).toBe("12.345");
+	});
+
+	test("should not group digits without a language", () => {
+		expect(formatCount(12345)).toBe("12345");
+	});
+});
+
+describe("formatQuantity", () => {
+	test("should pick the singular or plural noun", () => {
+		expect(formatQuantity(1, "command", "commands", "en")).toBe("1 command");
+		expect(formatQuantity(0, "command", "commands", "en")).toBe("0 commands");
+		expect(formatQuantity(1024, "file", "files", "en")).toBe("1,024 files");
+	});
+
+	test("should follow the plural rules of the language", () => {
+		expect(formatQuantity(0, "commande", "commandes", "fr")).toBe(
+			"0 commande",
+		);
+		expect(formatQuantity(1024, "file", "files")).toBe("1024 files");
+	});
+});
@@ -0,0 +1,85 @@
+import { describe, expect, test } from "bun:test";
+import {
+	addProvenance,
+	type Provenance,
+	readProvenance,
+	rewriteFrontmatterField,
+	stripProvenance,
+} from "../../src/utils/frontmatter.js";
+
+const published = `---
+description: Review a pull request
+allowed-tools: Read, Grep
+---
+
+# Review
+`;
+
+const provenance: Provenance = {
+	source: "https://example.com/commands",
+	version: "1.4.0",
+	sha256: "ab".repeat(32),
+	installedAt: "2026-01-02T03:04:05.000Z",
+};
+
+describe("frontmatter", () => {
+	describe("rewriteFrontmatterField", () => {
+		test("should leave files without the field unchanged", () => {
+			expect(rewriteFrontmatterField(published, "name", "x")).toBe(published);
+		});
+	});
+
+	describe("provenance", () => {
+		test("should append the fields to the frontmatter", () => {
+			const installed = addProvenance(published, provenance);
+
+			expect(installed).toContain(
+				'allowed-tools: Read, Grep\nclaude-cmd-source: "https://example.com/commands"\n',
+			);
+			expect(installed).toEndWith("---\n\n# Review\n");
+			expect(readProvenance(installed)).toEqual(provenance);
+		});
+
+		test("should restore the published file when stripped", () => {
+			expect(stripProvenance(addProvenance(published, provenance))).toBe(
+				published,
+			);
+		});
+
+		test("should replace earlier provenance", () => {
+			const reinstalled = addProvenance(
+				addProvenance(published, provenance),
+				{ ...provenance, version: "1.5.0" },
+			);
+
+			expect(reinstalled.match(/claude-cmd-version/g)).toHaveLength(1);
+			expect(readProvenance(reinstalled)?.version).toBe("1.5.0");
+		});
+
+		test("should keep Windows line endings", () => {
+			const crlf = published.replaceAll("\n", "\r\n");
+
+			const installed = addProvenance(crlf, provenance);
+
+			expect(installed).not.toMatch(/[^\r]\n/);
+			expect(stripProvenance(installed)).toBe(crlf);
+		});
+
+		test("should not add frontmatter to plain Markdown", () => {
+			const plain = "# Review\n\nLook closely.\n";
+
+			expect(addProvenance(plain, provenance)).toBe(plain);
+			expect(readProvenance(plain)).toBeUndefined();
+		});
+
+		test("should ignore incomplete provenance", () => {
+			const partial = published.replace(
+				"---\n\n",
+				'claude-cmd-version: "1.0.0"\n---\n\n',
+			);
+
+			expect(readProvenance(partial)).toBeUndefined();
+			expect(stripProvenance(partial)).toBe(published);
+		});
+	});
+});
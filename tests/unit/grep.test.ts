@@ -0,0 +1,100 @@
+import { describe, expect, test } from "bun:test";
+import {
+	compileGrepPattern,
+	formatGrepMatches,
+	grepContent,
+} from "../../src/cli/commands/grep.js";
+
+const CONTENT = [
+	"---",
+	"allowed-tools: Bash(git:*)",
+	"---",
+	"Review the staged changes.",
+	"Run git diff --cached.",
+	"Summarize the findings.",
+	"Suggest a commit message.",
+	"",
+].join("\n");
+
+describe("compileGrepPattern", () => {
+	test("should compile regular expressions", () => {
+		expect(compileGrepPattern("git (diff|log)").test("git log")).toBe(true);
+	});
+
+	test("should match literal text with fixedStrings", () => {
+		const pattern = compileGrepPattern("Bash(git:*)", { fixedStrings: true });
+
+		expect(pattern.test("allowed-tools: Bash(git:*)")).toBe(true);
+		expect(pattern.test("Bash(git:x)")).toBe(false);
+	});
+
+	test("should ignore case when asked", () => {
+		expect(compileGrepPattern("review").test("Review")).toBe(false);
+		expect(
+			compileGrepPattern("review", { ignoreCase: true }).test("Review"),
+		).toBe(true);
+	});
+
+	test("should reject invalid regular expressions", () => {
+		expect(() => compileGrepPattern("(unclosed")).toThrow(SyntaxError);
+	});
+});
+
+describe("grepContent", () => {
+	test("should return matching lines with their numbers", () => {
+		expect(grepContent(CONTENT, /git/)).toEqual([
+			[{ line: 2, text: "allowed-tools: Bash(git:*)", match: true }],
+			[{ line: 5, text: "Run git diff --cached.", match: true }],
+		]);
+	});
+
+	test("should add context lines around matches", () => {
+		expect(grepContent(CONTENT, /Bash|Suggest/, 1)).toEqual([
+			[
+				{ line: 1, text: "---", match: false },
+				{ line: 2, text: "allowed-tools: Bash(git:*)", match: true },
+				{ line: 3, text: "---", match: false },
+			],
+			[
+				{ line: 6, text: "Summarize the findings.", match: false },
+				{ line: 7, text: "Suggest a commit message.", match: true },
+			],
+		]);
+	});
+
+	test("should merge adjacent groups", () => {
+		const groups = grepContent(CONTENT, /git/, 1);
+
+		expect(groups).toHaveLength(1);
+		expect(groups[0]?.map((line) => line.line)).toEqual([1, 2, 3, 4, 5, 6]);
+	});
+
+	test("should mark matches inside the context of another match", () => {
+		const groups = grepContent(CONTENT, /^S/, 1);
+
+		expect(groups).toHaveLength(1);
+		const matches = groups[0]?.filter((line) => line.match);
+		expect(matches?.map((line) => line.line)).toEqual([6, 7]);
+		expect(groups[0]?.at(-1)?.line).toBe(7);
+	});
+
+	test("should return nothing without a match", () => {
+		expect(grepContent(CONTENT, /deploy/, 2)).toEqual([]);
+	});
+});
+
+describe("formatGrepMatches", () => {
+	test("should format matches and context like grep", () => {
+		expect(
+			formatGrepMatches("/c/review.md", [
+				[
+					{ line: 1, text: "---", match: false },
+					{ line: 2, text: "allowed-tools: Bash", match: true },
+				],
+				[{ line: 5, text: "Run git diff", match: true }],
+			]),
+		).toBe(
+			"/c/review.md-1----\n/c/review.md:2:allowed-tools: Bash\n--\n/c/review.md:5:Run git diff",
+		);
+	});
+});
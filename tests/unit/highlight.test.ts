@@ -0,0 +1,44 @@
+import { describe, expect, test } from "bun:test";
+import { highlightMarkdown, shouldColorize } from "../../src/cli/highlight.js";
+
+// biome-ignore lint/suspicious/noControlCharactersInRegex: stripping ANSI codes
+const stripAnsi = (text: string) => text.replace(/\x1b\[[\d;]*m/g, "");
+
+describe("highlightMarkdown", () => {
+	const content = [
+		"---",
+		"description: Review code",
+		"---",
+		"# Title",
+		"Run `git diff` first.",
+		"```bash",
+		"# not a heading",
+		"```",
+	].join("\n");
+
+	test("should only add escape sequences", () => {
+		expect(stripAnsi(highlightMarkdown(content))).toBe(content);
+	});
+
+	test("should color frontmatter keys, headings and code", () => {
+		const lines = highlightMarkdown(content).split("\n");
+
+		expect(lines[1]).toBe("\x1b[33mdescription\x1b[0m: Review code");
+		expect(lines[3]).toBe("\x1b[1;36m# Title\x1b[0m");
+		expect(lines[4]).toBe("Run \x1b[32m`git diff`\x1b[0m first.");
+		// Lines inside fences are code, not headings
+		expect(lines[6]).toBe("\x1b[32m# not a heading\x1b[0m");
+	});
+
+	test("should leave plain markdown without frontmatter uncolored", () => {
+		expect(highlightMarkdown("plain text")).toBe("plain text");
+	});
+});
+
+describe("shouldColorize", () => {
+	test("should require a terminal and respect NO_COLOR", () => {
+		expect(shouldColorize({ isTTY: true }, {})).toBe(true);
+		expect(shouldColorize({ isTTY: false }, {})).toBe(false);
+		expect(shouldColorize({ isTTY: true }, { NO_COLOR: "1" })).toBe(false);
+	});
+});
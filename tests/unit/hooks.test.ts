@@ -0,0 +1,68 @@
+import { describe, expect, test } from "bun:test";
+import {
+	HOOK_EVENTS,
+	hookCommandsFor,
+	hooksFingerprint,
+	isHookCommands,
+	isHookMap,
+	isHookTrustMap,
+} from "../../src/utils/hooks.js";
+
+describe("hooks", () => {
+	test("HOOK_EVENTS covers pre and post of every operation", () => {
+		expect(HOOK_EVENTS).toEqual([
+			"pre-add",
+			"post-add",
+			"pre-remove",
+			"post-remove",
+			"pre-update",
+			"post-update",
+		]);
+	});
+
+	test("isHookCommands accepts one command or a list of them", () => {
+		expect(isHookCommands("make docs")).toBe(true);
+		expect(isHookCommands(["make docs", "git add docs"])).toBe(true);
+		expect(isHookCommands("  ")).toBe(false);
+		expect(isHookCommands(["make docs", ""])).toBe(false);
+		expect(isHookCommands(42)).toBe(false);
+	});
+
+	test("isHookMap checks events and commands", () => {
+		expect(isHookMap({})).toBe(true);
+		expect(isHookMap({ "post-add": "make docs" })).toBe(true);
+		expect(isHookMap({ "after-add": "make docs" })).toBe(false);
+		expect(isHookMap({ "post-add": null })).toBe(false);
+		expect(isHookMap(["post-add"])).toBe(false);
+	});
+
+	test("hookCommandsFor lists the commands of an event", () => {
+		const hooks = { "pre-add": "make check", "post-add": ["a", "b"] };
+
+		expect(hookCommandsFor(hooks, "pre-add")).toEqual(["make check"]);
+		expect(hookCommandsFor(hooks, "post-add")).toEqual(["a", "b"]);
+		expect(hookCommandsFor(hooks, "pre-remove")).toEqual([]);
+		expect(hookCommandsFor(undefined, "pre-add")).toEqual([]);
+	});
+
+	test("hooksFingerprint changes only when a command does", () => {
+		const hooks = { "pre-add": "make check", "post-add": ["a", "b"] };
+
+		expect(hooksFingerprint(hooks)).toMatch(/^[0-9a-f]{64}$/);
+		expect(
+			hooksFingerprint({ "post-add": ["a", "b"], "pre-add": ["make check"] }),
+		).toBe(hooksFingerprint(hooks));
+		expect(hooksFingerprint({ ...hooks, "post-add": ["a"] })).not.toBe(
+			hooksFingerprint(hooks),
+		);
+	});
+
+	test("isHookTrustMap checks project roots and answers", () => {
+		const trust = { allow: true, fingerprint: "0".repeat(64) };
+
+		expect(isHookTrustMap({ "/work/app": trust })).toBe(true);
+		expect(isHookTrustMap({ "work/app": trust })).toBe(false);
+		expect(isHookTrustMap({ "/work/app": { allow: true } })).toBe(false);
+		expect(isHookTrustMap({ "/work/app": true })).toBe(false);
+	});
+});
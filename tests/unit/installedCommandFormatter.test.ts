@@ -69,6 +69,24 @@ describe("Installed Command Formatter", () => {
 			expect(result).toContain("project-helper");
 		});
 
+		test("should mark commands the repository does not list", async () => {
+			const { formatInstalledCommandsEnhanced } = await import(
+				"../../src/cli/commands/installed.js"
+			);
+
+			const result = formatInstalledCommandsEnhanced(
+				mockInstallationInfos,
+				"en",
+				new Map([
+					["test-command", "removed-upstream"],
+					["project-helper", "local-only"],
+				]),
+			);
+
+			expect(result).toContain("test-command (removed upstream)");
+			expect(result).toContain("project-helper (local only)");
+		});
+
 		test("should show summary with command counts", async () => {
 			const { formatInstalledCommandsSummary } = await import(
 				"../../src/cli/commands/installed.js"
@@ -125,5 +143,53 @@ describe("Installed Command Formatter", () => {
 			expect(result).toMatch(/Personal.*Commands:/);
 			expect(result).toMatch(/Project.*Commands:/);
 		});
+
+		test("should name the project root of project commands", async () => {
+			const { formatInstalledCommandsEnhanced } = await import(
+				"../../src/cli/commands/installed.js"
+			);
+
+			const result = formatInstalledCommandsEnhanced(
+				mockInstallationInfos.map((info) =>
+					info.location === "project"
+						? { ...info, projectRoot: "/work/monorepo/packages/api" }
+						: info,
+				),
+				"en",
+			);
+
+			expect(result).toContain(
+				"Project Commands (/work/monorepo/packages/api):",
+			);
+		});
+
+		test("should list the commands of each project root apart", async () => {
+			const { formatInstalledCommandsEnhanced } = await import(
+				"../../src/cli/commands/installed.js"
+			);
+			const project = mockInstallationInfos.filter(
+				(info) => info.location === "project",
+			);
+
+			const result = formatInstalledCommandsEnhanced(
+				[
+					...project.map((info) => ({
+						...info,
+						projectRoot: "/work/monorepo/packages/api",
+					})),
+					...project.map((info) => ({
+						...info,
+						name: "release",
+						projectRoot: "/work/monorepo",
+					})),
+				],
+				"en",
+			);
+
+			expect(result).toContain(
+				"Project Commands (/work/monorepo/packages/api):\nproject-helper\n",
+			);
+			expect(result).toContain("Project Commands (/work/monorepo):\nrelease");
+		});
 	});
 });
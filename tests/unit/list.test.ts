@@ -0,0 +1,246 @@
+import { describe, expect, test } from "bun:test";
+import {
+	commandTemplateData,
+	filterByInstallState,
+	formatCommandRow,
+	formatCommandsPorcelain,
+	getInstallStates,
+	orderCommands,
+	paginate,
+	parseListSort,
+} from "../../src/cli/commands/list.js";
+import type { Command } from "../../src/types/Command.js";
+import type { InstallationInfo } from "../../src/types/Installation.js";
+import { COLOR_THEME } from "../../src/utils/style.js";
+
+const command = (name: string, fields: Partial<Command> = {}): Command => ({
+	name,
+	description: `Describe ${name}`,
+	file: `${name}.md`,
+	"allowed-tools": [],
+	...fields,
+});
+
+describe("parseListSort", () => {
+	test("should accept the supported orders", () => {
+		expect(parseListSort("updated")).toBe("updated");
+	});
+
+	test("should reject other values", () => {
+		expect(() => parseListSort("size")).toThrow("Must be one of");
+	});
+});
+
+describe("orderCommands", () => {
+	const commands = [
+		command("alpha", { category: "git" }),
+		command("beta", { updated: "2025-03-01T00:00:00Z" }),
+		command("gamma", { category: "debugging", updated: "2025-05-01" }),
+	];
+
+	test("should keep name order", () => {
+		expect(orderCommands(commands, "name")).toEqual(commands);
+	});
+
+	test("should put recently updated commands first, undated last", () => {
+		expect(
+			orderCommands(commands, "updated").map((cmd) => cmd.name),
+		).toEqual(["gamma", "beta", "alpha"]);
+	});
+
+	test("should group by category, uncategorized last", () => {
+		expect(
+			orderCommands(commands, "category").map((cmd) => cmd.name),
+		).toEqual(["gamma", "alpha", "beta"]);
+	});
+});
+
+describe("getInstallStates", () => {
+	const installed = (
+		name: string,
+		fields: Partial<InstallationInfo> = {},
+	): InstallationInfo => ({
+		name,
+		filePath: `/home/user/.claude/commands/${name}.md`,
+		location: "personal",
+		installedAt: new Date(0),
+		size: 0,
+		source: "repository",
+		metadata: { language: "en" },
+		...fields,
+	});
+
+	test("should mark installed, outdated and available commands", () => {
+		const states = getInstallStates(
+			[
+				command("alpha", { sha256: "aaa" }),
+				command("beta", { sha256: "new" }),
+				command("gamma"),
+			],
+			[
+				installed("alpha", { sha256: "aaa" }),
+				installed("beta", { sha256: "old" }),
+			],
+		);
+
+		expect(Object.fromEntries(states)).toEqual({
+			alpha: "installed",
+			beta: "outdated",
+			gamma: "available",
+		});
+	});
+
+	test("should treat copies without a known hash as installed", () => {
+		const states = getInstallStates(
+			[command("alpha", { sha256: "aaa" })],
+			[installed("alpha", { source: "local" })],
+		);
+
+		expect(states.get("alpha")).toBe("installed");
+	});
+
+	test("should mark a command outdated when any copy is", () => {
+		const states = getInstallStates(
+			[command("alpha", { sha256: "new" })],
+			[
+				installed("alpha", { sha256: "new" }),
+				installed("alpha", { location: "project", sha256: "old" }),
+			],
+		);
+
+		expect(states.get("alpha")).toBe("outdated");
+	});
+});
+
+describe("filterByInstallState", () => {
+	const commands = [command("alpha"), command("beta"), command("gamma")];
+	const states = new Map([
+		["alpha", "installed" as const],
+		["beta", "outdated" as const],
+		["gamma", "available" as const],
+	]);
+
+	test("should keep installed and outdated commands", () => {
+		expect(
+			filterByInstallState(commands, states, "installed").map(
+				(cmd) => cmd.name,
+			),
+		).toEqual(["alpha", "beta"]);
+	});
+
+	test("should keep commands that are not installed", () => {
+		expect(
+			filterByInstallState(commands, states, "not-installed").map(
+				(cmd) => cmd.name,
+			),
+		).toEqual(["gamma"]);
+	});
+
+	test("should keep everything without a filter", () => {
+		expect(filterByInstallState(commands, states)).toEqual(commands);
+	});
+});
+
+describe("paginate", () => {
+	const items = ["a", "b", "c", "d", "e"];
+
+	test("should return everything without a limit", () => {
+		expect(paginate(items, 1)).toEqual({
+			items,
+			page: 1,
+			pageCount: 1,
+			offset: 0,
+		});
+	});
+
+	test("should return the requested page", () => {
+		expect(paginate(items, 2, 2)).toEqual({
+			items: ["c", "d"],
+			page: 2,
+			pageCount: 3,
+			offset: 2,
+		});
+	});
+
+	test("should clamp pages past the end to the last page", () => {
+		expect(paginate(items, 9, 2).items).toEqual(["e"]);
+	});
+});
+
+describe("formatCommandRow", () => {
+	test("should pad names to the column width", () => {
+		expect(formatCommandRow(command("ab"), 5)).toBe("ab     Describe ab");
+	});
+
+	test("should cut descriptions to the line width", () => {
+		expect(formatCommandRow(command("ab"), 2, 10)).toBe("ab  Descr…");
+	});
+
+	test("should cut names longer than the column", () => {
+		expect(formatCommandRow(command("abcdef"), 4)).toBe(
+			"abc…  Describe abcdef",
+		);
+	});
+
+	test("should keep the deprecation flag", () => {
+		expect(formatCommandRow(command("ab", { deprecated: true }), 2)).toBe(
+			"ab  Describe ab [deprecated]",
+		);
+	});
+
+	test("should mark the install state", () => {
+		expect(formatCommandRow(command("ab"), 2, undefined, "installed")).toBe(
+			"✓ ab  Describe ab",
+		);
+		expect(formatCommandRow(command("ab"), 2, 12, "outdated")).toBe(
+			"↑ ab  Descr…",
+		);
+		expect(formatCommandRow(command("ab"), 2, undefined, "available")).toBe(
+			"  ab  Describe ab",
+		);
+	});
+
+	test("should style markers, namespaces and the deprecation flag", () => {
+		const row = formatCommandRow(
+			command("git:ab", { deprecated: true }),
+			6,
+			undefined,
+			"installed",
+			COLOR_THEME,
+		);
+
+		expect(row).toBe(
+			"\x1b[32m✓\x1b[0m \x1b[2mgit:\x1b[0mab  Describe git:ab\x1b[33m [deprecated]\x1b[0m",
+		);
+	});
+});
+
+describe("formatCommandsPorcelain", () => {
+	test("should append the install state when given", () => {
+		const commands = [command("alpha"), command("beta")];
+
+		expect(formatCommandsPorcelain(commands)).toBe(
+			"alpha\tDescribe alpha\nbeta\tDescribe beta",
+		);
+		const states = new Map([["alpha", "installed" as const]]);
+		expect(formatCommandsPorcelain(commands, states)).toBe(
+			"alpha\tDescribe alpha\tinstalled\nbeta\tDescribe beta\tavailable",
+		);
+	});
+});
+
+describe("commandTemplateData", () => {
+	test("should expose every field, even missing ones", () => {
+		expect(
+			commandTemplateData(command("ab", { tags: ["git"] }), "outdated"),
+		).toEqual({
+			Name: "ab",
+			Description: "Describe ab",
+			State: "outdated",
+			Category: undefined,
+			Tags: ["git"],
+			Updated: undefined,
+			Deprecated: false,
+		});
+	});
+});
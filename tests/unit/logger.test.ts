@@ -0,0 +1,57 @@
+import { describe, expect, test } from "bun:test";
+import type { LogRecord } from "@logtape/logtape";
+import {
+	formatStructuredRecord,
+	parseLogLevel,
+} from "../../src/utils/logger.js";
+
+describe("logger", () => {
+	describe("parseLogLevel", () => {
+		test("should accept LogTape level names in any case", () => {
+			expect(parseLogLevel("trace")).toBe("trace");
+			expect(parseLogLevel("DEBUG")).toBe("debug");
+			expect(parseLogLevel("warning")).toBe("warning");
+		});
+
+		test("should map the short warn alias", () => {
+			expect(parseLogLevel("warn")).toBe("warning");
+		});
+
+		test("should reject unknown levels", () => {
+			expect(parseLogLevel("verbose")).toBeUndefined();
+			expect(parseLogLevel("")).toBeUndefined();
+		});
+	});
+
+	describe("formatStructuredRecord", () => {
+		test("should render timestamp, level, category, message and properties", () => {
+			const record = {
+				category: ["claude-cmd", "cache"],
+				level: "debug",
+				message: ["cache hit: ", "en", ""],
+				rawMessage: "cache hit: {language}",
+				timestamp: Date.UTC(2025, 0, 15, 10),
+				properties: { language: "en", ageMs: 1200 },
+			} as unknown as LogRecord;
+
+			expect(formatStructuredRecord(record)).toBe(
+				'2025-01-15T10:00:00.000Z DEBUG claude-cmd.cache: cache hit: en language="en" ageMs=1200',
+			);
+		});
+
+		test("should omit properties when there are none", () => {
+			const record = {
+				category: ["claude-cmd"],
+				level: "info",
+				message: ["Debug logging enabled."],
+				rawMessage: "Debug logging enabled.",
+				timestamp: Date.UTC(2025, 0, 15, 10),
+				properties: {},
+			} as unknown as LogRecord;
+
+			expect(formatStructuredRecord(record)).toBe(
+				"2025-01-15T10:00:00.000Z INFO claude-cmd: Debug logging enabled.",
+			);
+		});
+	});
+});
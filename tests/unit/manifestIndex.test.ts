@@ -0,0 +1,50 @@
+import { describe, expect, test } from "bun:test";
+import type { Command, Manifest } from "../../src/types/Command.js";
+import { ManifestIndex } from "../../src/utils/manifestIndex.js";
+
+function command(name: string, extra: Partial<Command> = {}): Command {
+	return {
+		name,
+		description: name,
+		file: `${name}.md`,
+		"allowed-tools": [],
+		...extra,
+	};
+}
+
+const commands = [
+	command("frontend:component", { category: "frontend", tags: ["React"] }),
+	command("frontend:test", { tags: ["testing"] }),
+	command("review", { tags: ["git", "Testing"] }),
+	command("review", { description: "duplicate" }),
+];
+
+describe("ManifestIndex", () => {
+	const index = new ManifestIndex(commands);
+
+	test("should find commands by name, first one winning", () => {
+		expect(index.get("review")?.description).toBe("review");
+		expect(index.get("missing")).toBeUndefined();
+	});
+
+	test("should find commands by tag or category, case-insensitively", () => {
+		expect(index.withTag(" TESTING ").map((c) => c.name)).toEqual([
+			"frontend:test",
+			"review",
+		]);
+		expect(index.withTag("frontend").map((c) => c.name)).toEqual([
+			"frontend:component",
+		]);
+		expect(index.withTag("unknown")).toEqual([]);
+	});
+
+	test("should build one index per manifest", () => {
+		const manifest: Manifest = {
+			version: "1.0.0",
+			updated: "2025-01-01T00:00:00Z",
+			commands,
+		};
+
+		expect(ManifestIndex.of(manifest)).toBe(ManifestIndex.of(manifest));
+	});
+});
@@ -0,0 +1,60 @@
+import { describe, expect, test } from "bun:test";
+import {
+	parsePublicKey,
+	SignatureError,
+	verifySignature,
+} from "../../src/utils/minisign.js";
+import { createTestSigner } from "../fixtures/minisign.js";
+
+describe("minisign", () => {
+	const signer = createTestSigner();
+	const publicKey = parsePublicKey(signer.publicKey);
+	const content = '{"version":"1.0.0","commands":[]}\n';
+
+	test("should read the key id of a minisign.pub file", () => {
+		expect(publicKey.keyId).toBe("0101010101010101");
+		expect(parsePublicKey(signer.publicKey.split("\n")[1] ?? "").keyId).toBe(
+			publicKey.keyId,
+		);
+	});
+
+	test("should reject malformed public keys", () => {
+		expect(() => parsePublicKey("not a key")).toThrow(SignatureError);
+	});
+
+	test.each(["ED", "Ed"] as const)(
+		"should verify %s signatures and return the trusted comment",
+		(algorithm) => {
+			expect(
+				verifySignature(content, signer.sign(content, algorithm), publicKey),
+			).toBe("timestamp:1700000000\tfile:manifest.json");
+		},
+	);
+
+	test("should reject modified content", () => {
+		expect(() =>
+			verifySignature(`${content} `, signer.sign(content), publicKey),
+		).toThrow("Signature does not match the content");
+	});
+
+	test("should reject signatures made with another key", () => {
+		expect(() =>
+			verifySignature(content, createTestSigner(2).sign(content), publicKey),
+		).toThrow("Signed with key 0202020202020202, expected 0101010101010101");
+	});
+
+	test("should reject a swapped trusted comment", () => {
+		const lines = signer.sign(content).split("\n");
+		lines[2] = "trusted comment: timestamp:0";
+
+		expect(() => verifySignature(content, lines.join("\n"), publicKey)).toThrow(
+			"Trusted comment signature is invalid",
+		);
+	});
+
+	test("should reject malformed signature files", () => {
+		expect(() => verifySignature(content, "garbage", publicKey)).toThrow(
+			"Malformed signature file",
+		);
+	});
+});
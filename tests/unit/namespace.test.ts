@@ -0,0 +1,280 @@
+import { describe, expect, test } from "bun:test";
+import * as path from "node:path";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
+import {
+	assertSafeCommandName,
+	constructCommandPath,
+	extractNamespaceFromPath,
+	isCommandPattern,
+	isInNamespace,
+	isSafeCommandName,
+	languageVariantName,
+	matchesCommandPattern,
+	parseNamespacedCommand,
+	parseVersionedCommand,
+	UnsafeCommandNameError,
+} from "../../src/utils/namespace.js";
+import { createRandom, pick } from "../helpers/random.js";
+
+const SEGMENT_CHARS = "abcxyz0189-";
+const SEPARATORS = [":", "/"];
+const UNSAFE_SEGMENTS = ["..", ".", "", " ", "\t"];
+const BASE_DIRS = ["/home/user/.claude/commands", ".claude/commands", "/"];
+const RUNS = 200;
+
+function randomSegment(random: () => number): string {
+	const length = 1 + Math.floor(random() * 6);
+	let segment = "";
+	for (let i = 0; i < length; i++) {
+		segment += pick(random, SEGMENT_CHARS.split(""));
+	}
+	return segment;
+}
+
+function randomSegments(random: () => number): string[] {
+	const count = 1 + Math.floor(random() * 4);
+	return Array.from({ length: count }, () => randomSegment(random));
+}
+
+function joinRandomly(segments: readonly string[], random: () => number) {
+	return segments.reduce((name, segment, index) =>
+		index === 0 ? segment : `${name}${pick(random, SEPARATORS)}${segment}`,
+	);
+}
+
+describe("namespace helpers", () => {
+	describe("parseNamespacedCommand", () => {
+		test.each([
+			["hello", undefined, "hello", "hello"],
+			["frontend:component", "frontend", "component", "frontend:component"],
+			["frontend/component", "frontend", "component", "frontend:component"],
+			["a:b/c", "a:b", "c", "a:b:c"],
+		])("parses %p", (input, namespace, command, name) => {
+			const parsed = parseNamespacedCommand(input);
+
+			expect(parsed.namespace).toBe(namespace);
+			expect(parsed.command).toBe(command);
+			expect(parsed.name).toBe(name);
+		});
+
+		test("property: canonical name is the input with ':' separators", () => {
+			const random = createRandom(11);
+			for (let run = 0; run < RUNS; run++) {
+				const segments = randomSegments(random);
+				const parsed = parseNamespacedCommand(joinRandomly(segments, random));
+
+				expect(parsed.name).toBe(segments.join(":"));
+				expect([...parsed.namespaceSegments, parsed.command]).toEqual(
+					segments,
+				);
+			}
+		});
+
+		test("property: parsing is idempotent on the canonical name", () => {
+			const random = createRandom(12);
+			for (let run = 0; run < RUNS; run++) {
+				const once = parseNamespacedCommand(
+					joinRandomly(randomSegments(random), random),
+				);
+
+				expect(parseNamespacedCommand(once.name)).toEqual(once);
+			}
+		});
+	});
+
+	describe("constructCommandPath / extractNamespaceFromPath", () => {
+		test("builds nested markdown paths", () => {
+			expect(
+				constructCommandPath("frontend:component", "/base/commands"),
+			).toBe(path.join("/base/commands", "frontend", "component.md"));
+			expect(constructCommandPath("hello", ".claude/commands")).toBe(
+				path.join(".claude/commands", "hello.md"),
+			);
+		});
+
+		test("extracts namespaces from relative paths", () => {
+			expect(extractNamespaceFromPath("frontend/component.md")).toMatchObject({
+				name: "frontend:component",
+				namespace: "frontend",
+			});
+			expect(extractNamespaceFromPath("./hello.md").name).toBe("hello");
+			expect(extractNamespaceFromPath("a\\b\\c.md").name).toBe("a:b:c");
+		});
+
+		test("property: path construction and extraction round-trip", () => {
+			const random = createRandom(13);
+			for (let run = 0; run < RUNS; run++) {
+				const segments = randomSegments(random);
+				const baseDir = pick(random, BASE_DIRS);
+
+				const filePath = constructCommandPath(
+					joinRandomly(segments, random),
+					baseDir,
+				);
+				const relative = path.relative(baseDir, filePath);
+
+				expect(extractNamespaceFromPath(relative).name).toBe(
+					segments.join(":"),
+				);
+			}
+		});
+
+		test("property: constructed paths stay inside the base directory", () => {
+			const random = createRandom(14);
+			for (let run = 0; run < RUNS; run++) {
+				const baseDir = pick(random, BASE_DIRS);
+				const filePath = constructCommandPath(
+					joinRandomly(randomSegments(random), random),
+					baseDir,
+				);
+				const relative = path.relative(
+					path.resolve(baseDir),
+					path.resolve(filePath),
+				);
+
+				expect(relative.startsWith("..")).toBe(false);
+				expect(path.isAbsolute(relative)).toBe(false);
+				expect(filePath.endsWith(".md")).toBe(true);
+			}
+		});
+
+		test("property: agrees with CommandParser namespace extraction", async () => {
+			const parser = new CommandParser(new NamespaceService());
+			const random = createRandom(15);
+			for (let run = 0; run < RUNS; run++) {
+				const relative = `${randomSegments(random).join("/")}.md`;
+
+				const command = await parser.parseCommandFile("# Body", relative);
+
+				expect(command.name).toBe(extractNamespaceFromPath(relative).name);
+			}
+		});
+	});
+
+	describe("traversal rejection", () => {
+		test.each([
+			"",
+			"   ",
+			"..",
+			"../../../etc/passwd",
+			"valid/../invalid",
+			"valid:..:invalid",
+			"./hidden",
+			"a//b",
+			"a::b",
+			"trailing:",
+			"/etc/passwd",
+			"C:\\Windows\\evil",
+			"..\\..\\evil",
+			"nul\0byte",
+		])("rejects %p", (name) => {
+			expect(isSafeCommandName(name)).toBe(false);
+			expect(() => assertSafeCommandName(name)).toThrow(
+				UnsafeCommandNameError,
+			);
+			expect(() => parseNamespacedCommand(name)).toThrow(
+				UnsafeCommandNameError,
+			);
+			expect(() => constructCommandPath(name, "/base")).toThrow(
+				UnsafeCommandNameError,
+			);
+		});
+
+		test("property: any unsafe segment anywhere is rejected", () => {
+			const random = createRandom(16);
+			for (let run = 0; run < RUNS; run++) {
+				const segments = randomSegments(random);
+				const position = Math.floor(random() * (segments.length + 1));
+				segments.splice(position, 0, pick(random, UNSAFE_SEGMENTS));
+				const name = joinRandomly(segments, random);
+
+				expect(isSafeCommandName(name)).toBe(false);
+				expect(() => constructCommandPath(name, "/base")).toThrow(
+					UnsafeCommandNameError,
+				);
+			}
+		});
+
+		test("property: every safe name is accepted", () => {
+			const random = createRandom(17);
+			for (let run = 0; run < RUNS; run++) {
+				expect(
+					isSafeCommandName(joinRandomly(randomSegments(random), random)),
+				).toBe(true);
+			}
+		});
+	});
+
+	describe("parseVersionedCommand", () => {
+		test("splits the version off a command spec", () => {
+			expect(parseVersionedCommand("frontend:component@1.2.0")).toEqual({
+				name: "frontend:component",
+				version: "1.2.0",
+			});
+			expect(parseVersionedCommand("review")).toEqual({ name: "review" });
+		});
+
+		test("rejects an empty version", () => {
+			expect(() => parseVersionedCommand("review@")).toThrow(
+				UnsafeCommandNameError,
+			);
+		});
+	});
+
+	describe("languageVariantName", () => {
+		test("suffixes flat command names", () => {
+			expect(languageVariantName("review", "fr")).toBe("review-fr");
+		});
+
+		test("keeps namespaced variants in their namespace", () => {
+			expect(languageVariantName("frontend/component", "de")).toBe(
+				"frontend:component-de",
+			);
+		});
+	});
+
+	describe("isInNamespace", () => {
+		test("includes nested namespaces", () => {
+			expect(isInNamespace("frontend:component", "frontend")).toBe(true);
+			expect(isInNamespace("frontend:react:hooks", "frontend")).toBe(true);
+			expect(isInNamespace("frontend:react:hooks", "frontend/react")).toBe(
+				true,
+			);
+		});
+
+		test("excludes other namespaces and top-level commands", () => {
+			expect(isInNamespace("frontend-tools:lint", "frontend")).toBe(false);
+			expect(isInNamespace("frontend", "frontend")).toBe(false);
+		});
+	});
+
+	describe("matchesCommandPattern", () => {
+		test("matches across namespace separators", () => {
+			expect(matchesCommandPattern("frontend:component", "frontend:*")).toBe(
+				true,
+			);
+			expect(matchesCommandPattern("frontend:react:hooks", "frontend:*")).toBe(
+				true,
+			);
+			expect(matchesCommandPattern("frontend-tools:lint", "frontend:*")).toBe(
+				false,
+			);
+		});
+
+		test("supports ? and either separator", () => {
+			expect(matchesCommandPattern("api:v1", "api/v?")).toBe(true);
+			expect(matchesCommandPattern("api:v10", "api/v?")).toBe(false);
+		});
+
+		test("treats other characters literally", () => {
+			expect(matchesCommandPattern("a.b", "a.b")).toBe(true);
+			expect(matchesCommandPattern("axb", "a.b")).toBe(false);
+		});
+
+		test("detects patterns", () => {
+			expect(isCommandPattern("frontend:*")).toBe(true);
+			expect(isCommandPattern("frontend:component")).toBe(false);
+		});
+	});
+});
@@ -0,0 +1,201 @@
+import { describe, expect, test } from "bun:test";
+import {
+	InvalidNameError,
+	isValidCommandName,
+	isValidFileName,
+	isValidLanguageCode,
+	isValidNamespace,
+	normalizeLanguageCode,
+	normalizeNameSegment,
+	validateCommandName,
+	validateFileName,
+	validateLanguageCode,
+	validateNamespace,
+} from "../../src/utils/naming.js";
+
+describe("naming", () => {
+	describe("command names", () => {
+		test.each([
+			"hello",
+			"debug-help",
+			"my_command",
+			"v1.2",
+			"frontend:component",
+			"frontend/component",
+			"a:b:c:d",
+			"Ünïcode",
+			"with space",
+		])("accepts %p", (name) => {
+			expect(isValidCommandName(name)).toBe(true);
+			expect(() => validateCommandName(name)).not.toThrow();
+		});
+
+		test.each([
+			["", "empty"],
+			["   ", "empty"],
+			["/etc/passwd", "absolute"],
+			["C:\\Windows", "absolute"],
+			["\\\\server\\share", "absolute"],
+			["..", "traversal"],
+			[".", "traversal"],
+			["../escape", "traversal"],
+			["a/../b", "traversal"],
+			["a:..:b", "traversal"],
+			["a//b", "traversal"],
+			["a::b", "traversal"],
+			["trailing:", "traversal"],
+			["a: :b", "traversal"],
+			["back\\slash", "traversal"],
+			["nul\0byte", "traversal"],
+		])("rejects %p (%s)", (name, reason) => {
+			expect(isValidCommandName(name)).toBe(false);
+			try {
+				validateCommandName(name);
+				throw new Error("expected validateCommandName to throw");
+			} catch (error) {
+				expect(error).toBeInstanceOf(InvalidNameError);
+				expect((error as InvalidNameError).kind).toBe("command");
+				expect((error as InvalidNameError).reason).toBe(
+					reason as InvalidNameError["reason"],
+				);
+			}
+		});
+	});
+
+	describe("namespaces", () => {
+		test.each([
+			"frontend",
+			"a",
+			"A1",
+			"front-end",
+			"frontend:react",
+			"frontend/react/hooks",
+		])("accepts %p", (namespace) => {
+			expect(isValidNamespace(namespace)).toBe(true);
+			expect(() => validateNamespace(namespace)).not.toThrow();
+		});
+
+		test.each([
+			["", "empty"],
+			[" ", "empty"],
+			["frontend:", "empty"],
+			["a::b", "empty"],
+			["-leading", "format"],
+			["trailing-", "format"],
+			["under_score", "format"],
+			["dot.ted", "format"],
+			["..", "format"],
+			["spa ce", "format"],
+		])("rejects %p (%s)", (namespace, reason) => {
+			expect(isValidNamespace(namespace)).toBe(false);
+			expect(() => validateNamespace(namespace)).toThrow(InvalidNameError);
+			try {
+				validateNamespace(namespace);
+			} catch (error) {
+				expect((error as InvalidNameError).reason).toBe(
+					reason as InvalidNameError["reason"],
+				);
+			}
+		});
+	});
+
+	describe("language codes", () => {
+		test.each(["en", "fr", "zh", "deu", "spa"])("accepts %p", (code) => {
+			expect(isValidLanguageCode(code)).toBe(true);
+			expect(() => validateLanguageCode(code)).not.toThrow();
+		});
+
+		test.each([
+			"",
+			"e",
+			"engl",
+			"EN",
+			"En",
+			"e1",
+			"en-US",
+			"en_US",
+			" en",
+			"../",
+		])("rejects %p", (code) => {
+			expect(isValidLanguageCode(code)).toBe(false);
+			expect(() => validateLanguageCode(code)).toThrow(InvalidNameError);
+		});
+
+		test.each([
+			["en", "en"],
+			[" EN ", "en"],
+			["Deu", "deu"],
+			["en-US", undefined],
+			["", undefined],
+			["english", undefined],
+		])("normalizes %p to %p", (input, expected) => {
+			expect(normalizeLanguageCode(input)).toBe(expected);
+		});
+	});
+
+	describe("file names", () => {
+		test.each([
+			"hello.md",
+			"frontend/component.md",
+			"frontend\\component.md",
+			"a/b/c/d.md",
+			"manifest.json",
+			"name..with..dots.md",
+		])("accepts %p", (file) => {
+			expect(isValidFileName(file)).toBe(true);
+			expect(() => validateFileName(file)).not.toThrow();
+		});
+
+		test.each([
+			["", "empty"],
+			["/etc/passwd", "absolute"],
+			["C:\\evil.md", "absolute"],
+			["C:/evil.md", "absolute"],
+			["nul\0.md", "characters"],
+			["../escape.md", "traversal"],
+			["a/../../escape.md", "traversal"],
+			["..\\escape.md", "traversal"],
+			["./hello.md", "traversal"],
+			["a//b.md", "traversal"],
+			["dir/", "traversal"],
+		])("rejects %p (%s)", (file, reason) => {
+			expect(isValidFileName(file)).toBe(false);
+			try {
+				validateFileName(file);
+				throw new Error("expected validateFileName to throw");
+			} catch (error) {
+				expect(error).toBeInstanceOf(InvalidNameError);
+				expect((error as InvalidNameError).kind).toBe("filename");
+				expect((error as InvalidNameError).reason).toBe(
+					reason as InvalidNameError["reason"],
+				);
+			}
+		});
+	});
+
+	describe("normalizeNameSegment", () => {
+		test.each([
+			["Code Review", "code-review"],
+			["my_command", "my-command"],
+			["v1.2", "v1-2"],
+			["Ünïcode", "unicode"],
+			["--Fix!! bugs--", "fix-bugs"],
+		])("normalizes %p to %p", (input, expected) => {
+			expect(normalizeNameSegment(input)).toBe(expected);
+		});
+
+		test.each(["", "!!!", "日本語"])("rejects %p", (input) => {
+			expect(normalizeNameSegment(input)).toBeUndefined();
+		});
+	});
+
+	test("validators tolerate non-string input", () => {
+		const notAString = 42 as unknown as string;
+
+		expect(isValidCommandName(notAString)).toBe(false);
+		expect(isValidNamespace(notAString)).toBe(false);
+		expect(isValidLanguageCode(notAString)).toBe(false);
+		expect(isValidFileName(notAString)).toBe(false);
+		expect(normalizeLanguageCode(notAString)).toBeUndefined();
+	});
+});
@@ -0,0 +1,216 @@
+import { describe, expect, test } from "bun:test";
+import {
+	compareOrderingKeys,
+	compareStrings,
+	type OrderingKey,
+	sortByOrderingKey,
+	sortCommands,
+	stringComparator,
+} from "../../src/utils/ordering.js";
+import { createRandom, shuffle } from "../helpers/random.js";
+
+const ALPHABET = ["a", "b", "B", "z", "-", ":", "0", "é"];
+const SOURCES = [undefined, "personal", "project", "repository"];
+
+function randomString(random: () => number, maxLength: number): string {
+	const length = Math.floor(random() * maxLength);
+	let result = "";
+	for (let i = 0; i < length; i++) {
+		result += ALPHABET[Math.floor(random() * ALPHABET.length)];
+	}
+	return result;
+}
+
+function randomKeys(random: () => number, count: number): OrderingKey[] {
+	const keys: OrderingKey[] = [];
+	for (let i = 0; i < count; i++) {
+		const namespace = random() < 0.5 ? randomString(random, 3) : undefined;
+		keys.push({
+			// Small name space so duplicates (and tie-breaks) are common
+			name: randomString(random, 3),
+			namespace,
+			source: SOURCES[Math.floor(random() * SOURCES.length)],
+		});
+	}
+	return keys;
+}
+
+const RUNS = 200;
+
+describe("ordering", () => {
+	describe("compareOrderingKeys", () => {
+		test("should order by name, then namespace, then source", () => {
+			const keys: OrderingKey[] = [
+				{ name: "b" },
+				{ name: "a", namespace: "y" },
+				{ name: "a", namespace: "x", source: "project" },
+				{ name: "a", namespace: "x", source: "personal" },
+				{ name: "a" },
+			];
+
+			const sorted = [...keys].sort(compareOrderingKeys);
+
+			expect(sorted).toEqual([
+				{ name: "a" },
+				{ name: "a", namespace: "x", source: "personal" },
+				{ name: "a", namespace: "x", source: "project" },
+				{ name: "a", namespace: "y" },
+				{ name: "b" },
+			]);
+		});
+
+		test("should use byte order rather than locale order", () => {
+			expect(compareStrings("B", "a")).toBeLessThan(0);
+			expect(compareStrings("a", "é")).toBeLessThan(0);
+			expect(compareStrings("same", "same")).toBe(0);
+		});
+
+		test("property: comparison is antisymmetric", () => {
+			const random = createRandom(1);
+			for (let run = 0; run < RUNS; run++) {
+				const [a, b] = randomKeys(random, 2) as [OrderingKey, OrderingKey];
+				expect(Math.sign(compareOrderingKeys(a, b))).toBe(
+					-Math.sign(compareOrderingKeys(b, a)) || 0,
+				);
+			}
+		});
+
+		test("property: comparison is transitive", () => {
+			const random = createRandom(2);
+			for (let run = 0; run < RUNS; run++) {
+				const [a, b, c] = randomKeys(random, 3).sort(compareOrderingKeys) as [
+					OrderingKey,
+					OrderingKey,
+					OrderingKey,
+				];
+				expect(compareOrderingKeys(a, b)).toBeLessThanOrEqual(0);
+				expect(compareOrderingKeys(b, c)).toBeLessThanOrEqual(0);
+				expect(compareOrderingKeys(a, c)).toBeLessThanOrEqual(0);
+			}
+		});
+	});
+
+	describe("sortCommands", () => {
+		test("should not modify the input array", () => {
+			const input = [{ name: "b" }, { name: "a" }];
+
+			const sorted = sortCommands(input);
+
+			expect(input.map((item) => item.name)).toEqual(["b", "a"]);
+			expect(sorted.map((item) => item.name)).toEqual(["a", "b"]);
+		});
+
+		test("property: output is independent of input order", () => {
+			const random = createRandom(3);
+			for (let run = 0; run < RUNS; run++) {
+				const keys = randomKeys(random, 1 + Math.floor(random() * 20));
+				const expected = sortCommands(keys).map((key) => JSON.stringify(key));
+				const actual = sortCommands(shuffle(keys, random)).map((key) =>
+					JSON.stringify(key),
+				);
+				expect(actual).toEqual(expected);
+			}
+		});
+
+		test("property: output is sorted and is a permutation of the input", () => {
+			const random = createRandom(4);
+			for (let run = 0; run < RUNS; run++) {
+				const keys = randomKeys(random, Math.floor(random() * 20));
+				const sorted = sortCommands(keys);
+
+				expect(sorted).toHaveLength(keys.length);
+				for (let i = 1; i < sorted.length; i++) {
+					expect(
+						compareOrderingKeys(
+							sorted[i - 1] as OrderingKey,
+							sorted[i] as OrderingKey,
+						),
+					).toBeLessThanOrEqual(0);
+				}
+				for (const key of keys) {
+					expect(sorted).toContain(key);
+				}
+			}
+		});
+
+		test("property: sorting is idempotent", () => {
+			const random = createRandom(5);
+			for (let run = 0; run < RUNS; run++) {
+				const once = sortCommands(randomKeys(random, 15));
+				expect(sortCommands(once)).toEqual(once);
+			}
+		});
+	});
+
+	describe("sortByOrderingKey", () => {
+		test("should sort arbitrary items using the extracted key", () => {
+			const infos = [
+				{ name: "deploy", location: "project" },
+				{ name: "deploy", location: "personal" },
+				{ name: "audit", location: "project" },
+			];
+
+			const sorted = sortByOrderingKey(infos, (info) => ({
+				name: info.name,
+				source: info.location,
+			}));
+
+			expect(sorted).toEqual([
+				{ name: "audit", location: "project" },
+				{ name: "deploy", location: "personal" },
+				{ name: "deploy", location: "project" },
+			]);
+		});
+	});
+
+	describe("stringComparator", () => {
+		test("should sort accented names next to their base letters", () => {
+			const names = ["zeta", "étape", "deploy", "Éclair", "eclair"];
+
+			expect([...names].sort(stringComparator("fr"))).toEqual([
+				"deploy",
+				"eclair",
+				"Éclair",
+				"étape",
+				"zeta",
+			]);
+			expect([...names].sort(stringComparator())).toEqual([
+				"deploy",
+				"eclair",
+				"zeta",
+				"Éclair",
+				"étape",
+			]);
+		});
+
+		test("should break collation ties by byte order", () => {
+			// Precomposed and decomposed é collate as equal
+			const precomposed = "caf\u00e9";
+			const decomposed = "cafe\u0301";
+			const compare = stringComparator("en");
+
+			expect(Math.sign(compare(precomposed, decomposed))).toBe(
+				Math.sign(compareStrings(precomposed, decomposed)),
+			);
+			expect(compare(precomposed, precomposed)).toBe(0);
+		});
+
+		test("should fall back to byte order for invalid language tags", () => {
+			expect(stringComparator("not a language")).toBe(compareStrings);
+		});
+
+		test("should give a total order for random keys in every language", () => {
+			const random = createRandom(7);
+			for (const language of ["en", "fr", "de", "ja"]) {
+				const keys = randomKeys(random, 30);
+				const expected = sortCommands(keys, language).map((key) =>
+					JSON.stringify(key),
+				);
+				const actual = sortCommands(shuffle(keys, random), language).map(
+					(key) => JSON.stringify(key),
+				);
+				expect(actual).toEqual(expected);
+			}
+		});
+	});
+});
@@ -0,0 +1,105 @@
+import { afterEach, beforeEach, describe, expect, spyOn, test } from "bun:test";
+import {
+	colorEnabled,
+	configureOutput,
+	isQuiet,
+	success,
+	warning,
+} from "../../src/cli/output.js";
+
+afterEach(() => {
+	configureOutput({});
+});
+
+describe("colorEnabled", () => {
+	test("should color terminals unless NO_COLOR is set", () => {
+		expect(colorEnabled({ isTTY: true }, {})).toBe(true);
+		expect(colorEnabled({ isTTY: false }, {})).toBe(false);
+		expect(colorEnabled({ isTTY: true }, { NO_COLOR: "1" })).toBe(false);
+	});
+
+	test("should never color with --no-color", () => {
+		configureOutput({ color: "never" });
+
+		expect(colorEnabled({ isTTY: true }, {})).toBe(false);
+	});
+
+	test("should always color when configured to", () => {
+		configureOutput({ color: "always" });
+
+		expect(colorEnabled({ isTTY: false }, { NO_COLOR: "1" })).toBe(true);
+	});
+});
+
+describe("success", () => {
+	let logSpy: ReturnType<typeof spyOn>;
+	let logged: string[];
+
+	beforeEach(() => {
+		logged = [];
+		logSpy = spyOn(console, "log").mockImplementation((message) => {
+			logged.push(String(message));
+		});
+	});
+
+	afterEach(() => {
+		logSpy.mockRestore();
+	});
+
+	test("should print a plain check mark without colors", () => {
+		configureOutput({ color: "never" });
+
+		success("Installed debug-help");
+
+		expect(logged).toEqual(["✓ Installed debug-help"]);
+	});
+
+	test("should print nothing with --quiet", () => {
+		configureOutput({ quiet: true });
+
+		success("Installed debug-help");
+
+		expect(isQuiet()).toBe(true);
+		expect(logged).toEqual([]);
+	});
+});
+
+describe("warning", () => {
+	let warnSpy: ReturnType<typeof spyOn>;
+	let warned: string[];
+
+	beforeEach(() => {
+		warned = [];
+		warnSpy = spyOn(console, "warn").mockImplementation((message) => {
+			warned.push(String(message));
+		});
+	});
+
+	afterEach(() => {
+		warnSpy.mockRestore();
+	});
+
+	test("should label the message", () => {
+		configureOutput({ color: "never" });
+
+		warning("editor exited with code 1");
+
+		expect(warned).toEqual(["Warning: editor exited with code 1"]);
+	});
+
+	test("should color the label when colors are on", () => {
+		configureOutput({ color: "always" });
+
+		warning("editor exited with code 1");
+
+		expect(warned[0]).toContain("\x1b[33mWarning:");
+	});
+
+	test("should print nothing with --quiet", () => {
+		configureOutput({ quiet: true });
+
+		warning("editor exited with code 1");
+
+		expect(warned).toEqual([]);
+	});
+});
@@ -0,0 +1,114 @@
+import { describe, expect, test } from "bun:test";
+import * as path from "node:path";
+import { constructCommandPath } from "../../src/utils/namespace.js";
+import { isInsideDirectory, pathSegments } from "../../src/utils/paths.js";
+
+const WINDOWS_COMMANDS = "C:\\Users\\me\\.claude\\commands";
+
+describe("paths", () => {
+	describe("isInsideDirectory", () => {
+		test("should accept nested paths", () => {
+			expect(
+				isInsideDirectory("/home/me/.claude/commands/a/b.md", "/home/me"),
+			).toBe(true);
+		});
+
+		test("should reject the directory itself", () => {
+			expect(isInsideDirectory("/base", "/base/")).toBe(false);
+		});
+
+		test("should reject siblings sharing a prefix", () => {
+			expect(isInsideDirectory("/base-old/cmd.md", "/base")).toBe(false);
+		});
+
+		test("should reject traversal out of the directory", () => {
+			expect(isInsideDirectory("/base/a/../../etc/passwd", "/base")).toBe(
+				false,
+			);
+		});
+
+		test("should accept names starting with two dots", () => {
+			expect(isInsideDirectory("/base/..hidden.md", "/base")).toBe(true);
+		});
+
+		describe("on Windows", () => {
+			test("should accept mixed separators", () => {
+				expect(
+					isInsideDirectory(
+						"C:/Users/me/.claude/commands/frontend\\component.md",
+						WINDOWS_COMMANDS,
+						path.win32,
+					),
+				).toBe(true);
+			});
+
+			test("should ignore drive letter case", () => {
+				expect(
+					isInsideDirectory(
+						"c:\\users\\me\\.claude\\commands\\review.md",
+						WINDOWS_COMMANDS,
+						path.win32,
+					),
+				).toBe(true);
+			});
+
+			test("should reject paths on another drive", () => {
+				expect(
+					isInsideDirectory(
+						"D:\\Users\\me\\.claude\\commands\\review.md",
+						WINDOWS_COMMANDS,
+						path.win32,
+					),
+				).toBe(false);
+			});
+
+			test("should reject siblings sharing a prefix", () => {
+				expect(
+					isInsideDirectory(
+						"C:\\Users\\me\\.claude\\commands-old\\review.md",
+						WINDOWS_COMMANDS,
+						path.win32,
+					),
+				).toBe(false);
+			});
+
+			test("should reject UNC paths", () => {
+				expect(
+					isInsideDirectory(
+						"\\\\server\\share\\review.md",
+						WINDOWS_COMMANDS,
+						path.win32,
+					),
+				).toBe(false);
+			});
+		});
+	});
+
+	describe("pathSegments", () => {
+		test("should split on either separator", () => {
+			expect(pathSegments("a\\b/c.md")).toEqual(["a", "b", "c.md"]);
+		});
+
+		test("should drop empty segments", () => {
+			expect(pathSegments("/a//b\\")).toEqual(["a", "b"]);
+		});
+	});
+
+	describe("constructCommandPath on Windows", () => {
+		test("should build backslash paths for namespaced commands", () => {
+			expect(
+				constructCommandPath(
+					"frontend:component",
+					WINDOWS_COMMANDS,
+					path.win32,
+				),
+			).toBe(`${WINDOWS_COMMANDS}\\frontend\\component.md`);
+		});
+
+		test("should refuse names escaping the directory", () => {
+			expect(() =>
+				constructCommandPath("..\\..\\evil", WINDOWS_COMMANDS, path.win32),
+			).toThrow();
+		});
+	});
+});
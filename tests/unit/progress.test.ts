@@ -0,0 +1,151 @@
+import { describe, expect, test } from "bun:test";
+import { enableJsonEvents } from "../../src/cli/events.js";
+import {
+	createProgressReporter,
+	EventProgressReporter,
+	type ProgressStream,
+	SilentProgressReporter,
+	TerminalProgressReporter,
+} from "../../src/cli/progress.js";
+import { EventBus } from "../../src/services/EventBus.js";
+import type { ClaudeCmdEvent } from "../../src/types/Events.js";
+import FakeClock from "../mocks/FakeClock.js";
+
+class FakeStream implements ProgressStream {
+	readonly output: string[] = [];
+
+	constructor(readonly isTTY: boolean) {}
+
+	write(chunk: string): boolean {
+		this.output.push(chunk);
+		return true;
+	}
+}
+
+describe("createProgressReporter", () => {
+	test("should draw progress on interactive terminals", () => {
+		const reporter = createProgressReporter({ stream: new FakeStream(true) });
+
+		expect(reporter).toBeInstanceOf(TerminalProgressReporter);
+	});
+
+	test("should stay silent when output is not a terminal", () => {
+		const reporter = createProgressReporter({ stream: new FakeStream(false) });
+
+		expect(reporter).toBeInstanceOf(SilentProgressReporter);
+	});
+
+	test("should stay silent with --quiet", () => {
+		const reporter = createProgressReporter({
+			quiet: true,
+			stream: new FakeStream(true),
+		});
+
+		expect(reporter).toBeInstanceOf(SilentProgressReporter);
+	});
+});
+
+describe("TerminalProgressReporter", () => {
+	test("should show steps, bytes and ETA for tasks of known size", () => {
+		const clock = new FakeClock();
+		const reporter = new TerminalProgressReporter(new FakeStream(true), clock);
+
+		reporter.start("Exporting en commands", 4);
+		clock.advance(1000);
+		reporter.advance(1, 2048);
+		const line = reporter.formatLine();
+		reporter.finish();
+
+		expect(line).toStartWith("Exporting en commands");
+		expect(line).toContain("1/4");
+		expect(line).toContain("2.0 KB");
+		expect(line).toContain("ETA 3s");
+	});
+
+	test("should show elapsed time for open-ended tasks", () => {
+		const clock = new FakeClock();
+		const reporter = new TerminalProgressReporter(new FakeStream(true), clock);
+
+		reporter.start("Downloading manifest");
+		clock.advance(5000);
+		const line = reporter.formatLine();
+		reporter.finish();
+
+		expect(line).toStartWith("Downloading manifest");
+		expect(line).toEndWith("5s");
+		expect(line).not.toContain("ETA");
+	});
+
+	test("should clear the status line when finished", () => {
+		const stream = new FakeStream(true);
+		const reporter = new TerminalProgressReporter(stream, new FakeClock());
+
+		reporter.start("Prefetching command files", 2);
+		reporter.advance();
+		reporter.finish();
+
+		expect(stream.output.at(-1)).toBe("\r\x1b[2K");
+	});
+
+	test("should write nothing when finished without starting", () => {
+		const stream = new FakeStream(true);
+		const reporter = new TerminalProgressReporter(stream, new FakeClock());
+
+		reporter.finish();
+
+		expect(stream.output).toEqual([]);
+	});
+});
+
+describe("EventProgressReporter", () => {
+	test("should publish the start, progress and end of a task", () => {
+		const bus = new EventBus();
+		const events: ClaudeCmdEvent[] = [];
+		bus.subscribe((event) => events.push(event));
+		const reporter = new EventProgressReporter(bus);
+
+		reporter.start("Prefetching command files", 2);
+		reporter.advance(1, 100);
+		reporter.advance(1, 50);
+		reporter.finish();
+		reporter.finish();
+
+		expect(events).toEqual([
+			{ type: "fetch_started", label: "Prefetching command files", total: 2 },
+			{
+				type: "download_progress",
+				label: "Prefetching command files",
+				steps: 1,
+				total: 2,
+				bytes: 100,
+			},
+			{
+				type: "download_progress",
+				label: "Prefetching command files",
+				steps: 2,
+				total: 2,
+				bytes: 150,
+			},
+			{
+				type: "fetch_finished",
+				label: "Prefetching command files",
+				steps: 2,
+				bytes: 150,
+			},
+		]);
+	});
+
+	test("should print events as JSON lines with --json-events", () => {
+		const bus = new EventBus();
+		const stream = new FakeStream(false);
+		const disable = enableJsonEvents(bus, stream);
+
+		new EventProgressReporter(bus).start("Downloading manifest");
+		disable();
+		bus.emit({ type: "fetch_started", label: "ignored" });
+
+		expect(stream.output).toEqual([
+			'{"type":"fetch_started","label":"Downloading manifest"}\n',
+		]);
+	});
+});
@@ -0,0 +1,88 @@
+import { describe, expect, test } from "bun:test";
+import {
+	findProjectRoot,
+	findProjectRoots,
+} from "../../src/utils/projectRoot.js";
+
+/**
+ * Existence check over a fixed set of paths
+ */
+const existing =
+	(...paths: string[]) =>
+	(target: string) =>
+		paths.includes(target);
+
+describe("projectRoot", () => {
+	describe("findProjectRoot", () => {
+		test("should find the nearest parent with .claude", () => {
+			expect(
+				findProjectRoot(
+					"/work/app/src/deep",
+					"/home/me",
+					existing("/work/app/.claude", "/work/.claude"),
+				),
+			).toBe("/work/app");
+		});
+
+		test("should accept .claude in the start directory", () => {
+			expect(
+				findProjectRoot("/work/app", "/home/me", existing("/work/app/.claude")),
+			).toBe("/work/app");
+		});
+
+		test("should stop at the git root", () => {
+			expect(
+				findProjectRoot(
+					"/work/app/src",
+					"/home/me",
+					existing("/work/app/.git", "/work/.claude"),
+				),
+			).toBeUndefined();
+		});
+
+		test("should check the git root itself", () => {
+			expect(
+				findProjectRoot(
+					"/work/app/src",
+					"/home/me",
+					existing("/work/app/.git", "/work/app/.claude"),
+				),
+			).toBe("/work/app");
+		});
+
+		test("should skip the personal ~/.claude", () => {
+			expect(
+				findProjectRoot(
+					"/home/me/notes",
+					"/home/me",
+					existing("/home/me/.claude"),
+				),
+			).toBeUndefined();
+		});
+
+		test("should return undefined at the filesystem root", () => {
+			expect(findProjectRoot("/a/b", "/home/me", existing())).toBeUndefined();
+		});
+	});
+
+	describe("findProjectRoots", () => {
+		test("should find nested projects up to the git root", () => {
+			expect(
+				findProjectRoots(
+					"/work/repo/packages/app/src",
+					"/home/me",
+					existing(
+						"/work/repo/packages/app/.claude",
+						"/work/repo/.claude",
+						"/work/repo/.git",
+						"/work/.claude",
+					),
+				),
+			).toEqual(["/work/repo/packages/app", "/work/repo"]);
+		});
+
+		test("should return nothing when no ancestor has .claude", () => {
+			expect(findProjectRoots("/a/b", "/home/me", existing())).toEqual([]);
+		});
+	});
+});
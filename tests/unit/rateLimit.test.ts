@@ -0,0 +1,53 @@
+import { describe, expect, test } from "bun:test";
+import {
+	isRateLimitResponse,
+	parseRetryAt,
+} from "../../src/utils/rateLimit.js";
+
+describe("isRateLimitResponse", () => {
+	test("should treat 429 as a rate limit", () => {
+		expect(isRateLimitResponse(429, {})).toBe(true);
+	});
+
+	test("should treat 403 as a rate limit only when the quota is used up", () => {
+		expect(isRateLimitResponse(403, { "x-ratelimit-remaining": "0" })).toBe(
+			true,
+		);
+		expect(isRateLimitResponse(403, { "x-ratelimit-remaining": "12" })).toBe(
+			false,
+		);
+		expect(isRateLimitResponse(403, {})).toBe(false);
+	});
+
+	test("should ignore other statuses", () => {
+		expect(isRateLimitResponse(500, { "x-ratelimit-remaining": "0" })).toBe(
+			false,
+		);
+	});
+});
+
+describe("parseRetryAt", () => {
+	const now = Date.UTC(2025, 0, 1);
+
+	test("should read Retry-After in seconds", () => {
+		expect(parseRetryAt({ "retry-after": "120" }, now)).toBe(now + 120000);
+	});
+
+	test("should read Retry-After as an HTTP date", () => {
+		expect(
+			parseRetryAt({ "retry-after": "Wed, 01 Jan 2025 00:05:00 GMT" }, now),
+		).toBe(now + 300000);
+	});
+
+	test("should fall back to the GitHub reset header", () => {
+		const reset = String(now / 1000 + 600);
+		expect(parseRetryAt({ "x-ratelimit-reset": reset }, now)).toBe(
+			now + 600000,
+		);
+	});
+
+	test("should return undefined without usable headers", () => {
+		expect(parseRetryAt({}, now)).toBeUndefined();
+		expect(parseRetryAt({ "retry-after": "soon" }, now)).toBeUndefined();
+	});
+});
@@ -0,0 +1,82 @@
+import { describe, expect, test } from "bun:test";
+import {
+	formatRemovalListing,
+	selectCommandsToRemove,
+} from "../../src/cli/commands/remove.js";
+import type { InstallationInfo } from "../../src/types/Installation.js";
+
+const installed = (
+	name: string,
+	location: "personal" | "project" = "personal",
+): InstallationInfo => ({
+	name,
+	filePath: `/${location}/${name.replace(/:/g, "/")}.md`,
+	location,
+	installedAt: new Date(0),
+	size: 0,
+	source: "local",
+	metadata: { language: "en" },
+});
+
+describe("selectCommandsToRemove", () => {
+	const commands = [
+		installed("frontend:button"),
+		installed("frontend:button", "project"),
+		installed("frontend:react:hooks", "project"),
+		installed("frontend-tools:lint"),
+		installed("review"),
+	];
+	const names = (selected: readonly InstallationInfo[]) =>
+		selected.map((info) => `${info.location}:${info.name}`);
+
+	test("should select by pattern in both locations", () => {
+		expect(
+			names(selectCommandsToRemove(commands, { pattern: "frontend:*" })),
+		).toEqual([
+			"personal:frontend:button",
+			"project:frontend:button",
+			"project:frontend:react:hooks",
+		]);
+	});
+
+	test("should honor a location prefix on the pattern", () => {
+		expect(
+			names(
+				selectCommandsToRemove(commands, { pattern: "personal:frontend:*" }),
+			),
+		).toEqual(["personal:frontend:button"]);
+	});
+
+	test("should select a namespace and restrict it to a location", () => {
+		expect(
+			names(
+				selectCommandsToRemove(commands, {
+					namespace: "frontend",
+					from: "project",
+				}),
+			),
+		).toEqual(["project:frontend:button", "project:frontend:react:hooks"]);
+	});
+
+	test("should select nothing when the location prefix and --from disagree", () => {
+		expect(
+			selectCommandsToRemove(commands, {
+				pattern: "personal:*",
+				from: "project",
+			}),
+		).toEqual([]);
+	});
+});
+
+describe("formatRemovalListing", () => {
+	test("should list every command with its location and file", () => {
+		expect(
+			formatRemovalListing([
+				installed("frontend:button"),
+				installed("api", "project"),
+			]),
+		).toBe(
+			"The following 2 commands will be removed:\n  frontend:button  personal  /personal/frontend/button.md\n  api              project   /project/api.md",
+		);
+	});
+});
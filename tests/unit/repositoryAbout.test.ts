@@ -0,0 +1,74 @@
+import { describe, expect, test } from "bun:test";
+import { formatCatalogHeader } from "../../src/cli/commands/repo.js";
+import {
+	loadRepositoryAbout,
+	parseRepositoryAbout,
+} from "../../src/services/shared/repositoryAbout.js";
+
+describe("repositoryAbout", () => {
+	describe("parseRepositoryAbout", () => {
+		test("should keep only non-empty string fields", () => {
+			expect(
+				parseRepositoryAbout(
+					JSON.stringify({
+						title: " Commands ",
+						description: "",
+						homepage: 42,
+						extra: "ignored",
+					}),
+				),
+			).toEqual({ title: "Commands" });
+		});
+
+		test("should accept a bare maintainer name", () => {
+			expect(parseRepositoryAbout('{"maintainer":"Platform team"}')).toEqual({
+				maintainer: { name: "Platform team" },
+			});
+		});
+
+		test("should reject non-object documents", () => {
+			expect(parseRepositoryAbout("[]")).toBeNull();
+			expect(parseRepositoryAbout("null")).toBeNull();
+			expect(parseRepositoryAbout("not json")).toBeNull();
+		});
+	});
+
+	describe("loadRepositoryAbout", () => {
+		test("should return null when no location exists", async () => {
+			expect(await loadRepositoryAbout(async () => null, ["a", "b"])).toBe(
+				null,
+			);
+		});
+
+		test("should skip unreadable locations", async () => {
+			const about = await loadRepositoryAbout(
+				async (location) => {
+					if (location === "a") {
+						throw new Error("boom");
+					}
+					return '{"title":"B"}';
+				},
+				["a", "b"],
+			);
+
+			expect(about).toEqual({ title: "B" });
+		});
+	});
+
+	describe("formatCatalogHeader", () => {
+		test("should be empty without a title or description", () => {
+			expect(formatCatalogHeader(null)).toBe("");
+			expect(formatCatalogHeader({ homepage: "https://x.dev" })).toBe("");
+		});
+
+		test("should show title, homepage and description", () => {
+			expect(
+				formatCatalogHeader({
+					title: "Commands",
+					homepage: "https://x.dev",
+					description: "Shared slash commands",
+				}),
+			).toBe("Commands (https://x.dev)\nShared slash commands\n\n");
+		});
+	});
+});
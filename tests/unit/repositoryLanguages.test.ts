@@ -0,0 +1,37 @@
+import { describe, expect, test } from "bun:test";
+import { parseRepositoryLanguages } from "../../src/services/shared/repositoryLanguages.js";
+
+describe("repositoryLanguages", () => {
+	describe("parseRepositoryLanguages", () => {
+		test("should accept a bare array of codes", () => {
+			expect(parseRepositoryLanguages('["en", "fr"]')).toEqual([
+				{ code: "en" },
+				{ code: "fr" },
+			]);
+		});
+
+		test("should accept a languages object with names", () => {
+			expect(
+				parseRepositoryLanguages(
+					JSON.stringify({
+						languages: [{ code: "pt", name: " Português " }, "uk"],
+					}),
+				),
+			).toEqual([{ code: "pt", name: "Português" }, { code: "uk" }]);
+		});
+
+		test("should skip invalid and duplicate entries", () => {
+			expect(
+				parseRepositoryLanguages(
+					JSON.stringify(["en", "EN-us", 42, null, { name: "x" }, "en", "de"]),
+				),
+			).toEqual([{ code: "en" }, { code: "de" }]);
+		});
+
+		test("should reject documents without a languages array", () => {
+			expect(parseRepositoryLanguages("{}")).toBeNull();
+			expect(parseRepositoryLanguages('{"languages":"en"}')).toBeNull();
+			expect(parseRepositoryLanguages("not json")).toBeNull();
+		});
+	});
+});
@@ -0,0 +1,133 @@
+import { describe, expect, test } from "bun:test";
+import { InvalidConfigError } from "../../src/interfaces/IConfigService.js";
+import HTTPRepository from "../../src/services/HTTPRepository.js";
+import {
+	isRepositoryKeyMap,
+	isSameRepository,
+	parseGitHubShorthand,
+	repositoryPublicKeyFor,
+	resolveRepositorySource,
+} from "../../src/utils/repositorySource.js";
+import { createTestSigner } from "../fixtures/minisign.js";
+
+describe("parseGitHubShorthand", () => {
+	test("should parse owner/name with an optional ref", () => {
+		expect(parseGitHubShorthand("acme/commands")).toEqual({
+			owner: "acme",
+			name: "commands",
+		});
+		expect(parseGitHubShorthand("acme/my.commands@v1.2")).toEqual({
+			owner: "acme",
+			name: "my.commands",
+			ref: "v1.2",
+		});
+		expect(parseGitHubShorthand("acme/commands.git@dev")).toEqual({
+			owner: "acme",
+			name: "commands",
+			ref: "dev",
+		});
+	});
+
+	test("should reject anything else", () => {
+		for (const value of [
+			"commands",
+			"acme/commands/extra",
+			"-acme/commands",
+			"acme/..",
+			"acme/commands@",
+			"acme/commands@../main",
+			"https://github.com/acme/commands",
+		]) {
+			expect(parseGitHubShorthand(value)).toBeUndefined();
+		}
+	});
+});
+
+describe("resolveRepositorySource", () => {
+	test("should expand shorthands to raw GitHub roots", () => {
+		expect(resolveRepositorySource("acme/commands")).toBe(
+			"https://raw.githubusercontent.com/acme/commands/refs/heads/main",
+		);
+		expect(resolveRepositorySource("acme/commands@v2")).toBe(
+			"https://raw.githubusercontent.com/acme/commands/v2",
+		);
+	});
+
+	test("should keep URLs, without trailing slashes", () => {
+		expect(resolveRepositorySource("https://example.com/commands/")).toBe(
+			"https://example.com/commands",
+		);
+		expect(resolveRepositorySource("file:///srv/commands")).toBe(
+			"file:///srv/commands",
+		);
+	});
+
+	test("should reject other sources", () => {
+		expect(() => resolveRepositorySource("git@github.com:a/b")).toThrow(
+			InvalidConfigError,
+		);
+		expect(() => resolveRepositorySource("commands")).toThrow(
+			InvalidConfigError,
+		);
+	});
+});
+
+describe("isSameRepository", () => {
+	test("should match spellings of one GitHub root", () => {
+		expect(
+			isSameRepository(
+				HTTPRepository.BASE_URL,
+				resolveRepositorySource("Claude-Code-Commands/commands"),
+			),
+		).toBe(true);
+		expect(
+			isSameRepository(
+				"https://raw.githubusercontent.com/acme/commands/main/",
+				"https://raw.githubusercontent.com/acme/commands/refs/heads/main",
+			),
+		).toBe(true);
+	});
+
+	test("should tell refs and repositories apart", () => {
+		expect(
+			isSameRepository(
+				resolveRepositorySource("acme/commands@v1"),
+				resolveRepositorySource("acme/commands@v2"),
+			),
+		).toBe(false);
+		expect(
+			isSameRepository("https://example.com/a", "https://example.com/b"),
+		).toBe(false);
+	});
+});
+
+describe("repositoryPublicKeyFor", () => {
+	const { publicKey } = createTestSigner();
+
+	test("should find the key of a repository in any spelling", () => {
+		const keys = {
+			"https://raw.githubusercontent.com/acme/commands/main": publicKey,
+		};
+
+		expect(
+			repositoryPublicKeyFor(keys, resolveRepositorySource("acme/commands")),
+		).toBe(publicKey);
+		expect(
+			repositoryPublicKeyFor(keys, "https://example.com/other"),
+		).toBeUndefined();
+		expect(
+			repositoryPublicKeyFor(undefined, "https://example.com"),
+		).toBeUndefined();
+	});
+
+	test("should only accept URLs mapped to public keys", () => {
+		expect(isRepositoryKeyMap({ "https://example.com": publicKey })).toBe(
+			true,
+		);
+		expect(isRepositoryKeyMap({ "https://example.com": "not a key" })).toBe(
+			false,
+		);
+		expect(isRepositoryKeyMap({ "acme/commands": publicKey })).toBe(false);
+		expect(isRepositoryKeyMap([publicKey])).toBe(false);
+	});
+});
@@ -0,0 +1,72 @@
+import { describe, expect, test } from "bun:test";
+import { SnapshotCache } from "../../src/utils/snapshot.js";
+import FakeClock from "../mocks/FakeClock.js";
+
+describe("SnapshotCache", () => {
+	const counter = () => {
+		let calls = 0;
+		return {
+			compute: async () => ++calls,
+			calls: () => calls,
+		};
+	};
+
+	test("should compute once per TTL", async () => {
+		const clock = new FakeClock();
+		const cache = new SnapshotCache<number>(clock, 1000);
+		const { compute, calls } = counter();
+
+		expect(await cache.get(compute)).toBe(1);
+		clock.advance(999);
+		expect(await cache.get(compute)).toBe(1);
+		clock.advance(1);
+		expect(await cache.get(compute)).toBe(2);
+		expect(calls()).toBe(2);
+	});
+
+	test("should not keep values with a TTL of 0", async () => {
+		const cache = new SnapshotCache<number>(new FakeClock(), 0);
+		const { compute } = counter();
+
+		expect(await cache.get(compute)).toBe(1);
+		expect(await cache.get(compute)).toBe(2);
+	});
+
+	test("should share a computation between concurrent callers", async () => {
+		const cache = new SnapshotCache<number>(new FakeClock(), 0);
+		const { compute, calls } = counter();
+
+		const results = await Promise.all([cache.get(compute), cache.get(compute)]);
+
+		expect(results).toEqual([1, 1]);
+		expect(calls()).toBe(1);
+	});
+
+	test("should not cache failures", async () => {
+		const cache = new SnapshotCache<number>(new FakeClock(), 1000);
+
+		await expect(
+			cache.get(async () => {
+				throw new Error("unavailable");
+			}),
+		).rejects.toThrow("unavailable");
+		expect(await cache.get(async () => 42)).toBe(42);
+	});
+
+	test("should not keep a computation started before invalidate", async () => {
+		const cache = new SnapshotCache<number>(new FakeClock(), 1000);
+		let release: (value: number) => void = () => {};
+		const pending = cache.get(
+			() =>
+				new Promise<number>((resolve) => {
+					release = resolve;
+				}),
+		);
+
+		cache.invalidate();
+		release(1);
+
+		expect(await pending).toBe(1);
+		expect(await cache.get(async () => 2)).toBe(2);
+	});
+});
@@ -0,0 +1,36 @@
+import { describe, expect, test } from "bun:test";
+import { readStream, StdinError } from "../../src/cli/stdin.js";
+
+async function* chunks(...parts: string[]): AsyncIterable<Uint8Array> {
+	for (const part of parts) {
+		yield new TextEncoder().encode(part);
+	}
+}
+
+describe("readStream", () => {
+	test("should join chunks into text", async () => {
+		expect(await readStream(chunks("---\ndesc", "ription: x\n---\n"), 64)).toBe(
+			"---\ndescription: x\n---\n",
+		);
+	});
+
+	test("should decode characters split across chunks", async () => {
+		const bytes = new TextEncoder().encode("café");
+		async function* split(): AsyncIterable<Uint8Array> {
+			yield bytes.slice(0, 4);
+			yield bytes.slice(4);
+		}
+
+		expect(await readStream(split(), 64)).toBe("café");
+	});
+
+	test("should refuse empty input", async () => {
+		await expect(readStream(chunks(" \n"), 64)).rejects.toThrow(StdinError);
+	});
+
+	test("should refuse input over the size limit", async () => {
+		const input = chunks("x".repeat(40), "x".repeat(40));
+
+		await expect(readStream(input, 64)).rejects.toThrow(/larger than 64 bytes/);
+	});
+});
@@ -0,0 +1,30 @@
+import { describe, expect, test } from "bun:test";
+import {
+	COLOR_THEME,
+	PLAIN_THEME,
+	styleCommandName,
+} from "../../src/utils/style.js";
+
+describe("themes", () => {
+	test("should leave text unchanged with the plain theme", () => {
+		expect(PLAIN_THEME.success("done")).toBe("done");
+		expect(PLAIN_THEME.warning("outdated")).toBe("outdated");
+	});
+
+	test("should wrap text in ANSI codes with the color theme", () => {
+		expect(COLOR_THEME.success("done")).toBe("\x1b[32mdone\x1b[0m");
+		expect(COLOR_THEME.dim("")).toBe("");
+	});
+});
+
+describe("styleCommandName", () => {
+	test("should dim the namespace only", () => {
+		expect(styleCommandName("frontend:component", COLOR_THEME)).toBe(
+			"\x1b[2mfrontend:\x1b[0mcomponent",
+		);
+	});
+
+	test("should leave names without a namespace alone", () => {
+		expect(styleCommandName("debug-help", COLOR_THEME)).toBe("debug-help");
+	});
+});
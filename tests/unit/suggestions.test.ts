@@ -0,0 +1,45 @@
+import { describe, expect, test } from "bun:test";
+import { editDistance, suggestNames } from "../../src/utils/suggestions.js";
+
+describe("editDistance", () => {
+	test("should count insertions, deletions and substitutions", () => {
+		expect(editDistance("debg-help", "debug-help")).toBe(1);
+		expect(editDistance("review", "reveiw")).toBe(2);
+		expect(editDistance("", "abc")).toBe(3);
+	});
+});
+
+describe("suggestNames", () => {
+	const names = [
+		"debug-help",
+		"debug-logs",
+		"tools:debug-helper",
+		"review",
+		"refactor",
+	];
+
+	test("should suggest the closest names first", () => {
+		expect(suggestNames("debg-help", names)).toEqual([
+			"debug-help",
+			"tools:debug-helper",
+		]);
+	});
+
+	test("should match names without their namespace", () => {
+		expect(suggestNames("debug-helpr", ["tools:debug-helper"])).toEqual([
+			"tools:debug-helper",
+		]);
+	});
+
+	test("should ignore case and duplicates", () => {
+		expect(suggestNames("REVEIW", ["review", "review"])).toEqual(["review"]);
+	});
+
+	test("should suggest nothing for unrelated names", () => {
+		expect(suggestNames("deploy", names)).toEqual([]);
+	});
+
+	test("should limit the number of suggestions", () => {
+		expect(suggestNames("debg-help", names, 1)).toEqual(["debug-help"]);
+	});
+});
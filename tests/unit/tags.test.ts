@@ -0,0 +1,37 @@
+import { describe, expect, test } from "bun:test";
+import type { Command } from "../../src/types/Command.js";
+import { commandTags, hasTag } from "../../src/utils/tags.js";
+
+describe("commandTags", () => {
+	const base: Command = {
+		name: "trace",
+		description: "Trace a failure",
+		file: "trace.md",
+		"allowed-tools": [],
+	};
+
+	test("should return the category first, normalized and deduplicated", () => {
+		expect(
+			commandTags({
+				...base,
+				category: "Debugging",
+				tags: [" Logs ", "debugging", ""],
+			}),
+		).toEqual(["debugging", "logs"]);
+	});
+
+	test("should ignore malformed tags from unvalidated manifests", () => {
+		const command = { ...base, tags: ["ok", 42] } as unknown as Command;
+
+		expect(commandTags(command)).toEqual(["ok"]);
+	});
+
+	test("should return an empty list for untagged commands", () => {
+		expect(commandTags(base)).toEqual([]);
+		expect(hasTag(base, "logs")).toBe(false);
+	});
+
+	test("should match tags case-insensitively", () => {
+		expect(hasTag({ ...base, tags: ["Git"] }, " git")).toBe(true);
+	});
+});
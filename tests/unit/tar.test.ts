@@ -0,0 +1,141 @@
+import { describe, expect, test } from "bun:test";
+import { gunzipSync, gzipSync } from "node:zlib";
+import {
+	createTarGz,
+	extractTarGz,
+	TarError,
+	type TarEntry,
+} from "../../src/utils/tar.js";
+
+/**
+ * Build an archive whose entries may use any header type flag
+ */
+function createTypedTarGz(
+	entries: readonly (TarEntry & { type?: string })[],
+): Uint8Array {
+	const encoder = new TextEncoder();
+	const tar = new Uint8Array(gunzipSync(createTarGz(entries)));
+	let offset = 0;
+	for (const { content, type } of entries) {
+		if (type) {
+			const header = tar.subarray(offset, offset + 512);
+			header[156] = type.charCodeAt(0);
+			header.fill(0x20, 148, 156);
+			const checksum = header.reduce((sum, byte) => sum + byte, 0);
+			header.set(
+				encoder.encode(`${checksum.toString(8).padStart(6, "0")}\0 `),
+				148,
+			);
+		}
+		offset += 512 + Math.ceil(encoder.encode(content).length / 512) * 512;
+	}
+	return new Uint8Array(gzipSync(tar));
+}
+
+/**
+ * Format a pax extended header record (ASCII only)
+ */
+function paxRecord(key: string, value: string): string {
+	const body = ` ${key}=${value}\n`;
+	let length = body.length + 1;
+	while (`${length}${body}`.length !== length) {
+		length++;
+	}
+	return `${length}${body}`;
+}
+
+describe("tar", () => {
+	const longName = `commands/en/${"a".repeat(120)}.md`;
+	test("should round-trip text entries in order", () => {
+		const entries = [
+			{ path: "bundle.json", content: '{"format":1}' },
+			{ path: "commands/en/hello.md", content: "# Hello ✓\n" },
+			{ path: "commands/en/empty.md", content: "" },
+		];
+
+		expect(extractTarGz(createTarGz(entries))).toEqual(entries);
+	});
+
+	test("should store long paths using the ustar prefix field", () => {
+		const longPath = `commands/en/${"nested/".repeat(15)}command.md`;
+		expect(longPath.length).toBeGreaterThan(100);
+
+		const [entry] = extractTarGz(
+			createTarGz([{ path: longPath, content: "x" }]),
+		);
+
+		expect(entry?.path).toBe(longPath);
+	});
+
+	test("should reject paths that do not fit the ustar format", () => {
+		expect(() =>
+			createTarGz([{ path: "a".repeat(101), content: "x" }]),
+		).toThrow(TarError);
+	});
+
+	test("should reject data that is not gzip compressed", () => {
+		expect(() => extractTarGz(new TextEncoder().encode("not gzip"))).toThrow(
+			TarError,
+		);
+	});
+
+	test("should reject headers with an invalid checksum", () => {
+		const tar = new Uint8Array(
+			gunzipSync(createTarGz([{ path: "file.md", content: "x" }])),
+		);
+		tar[0] = "g".charCodeAt(0);
+
+		expect(() => extractTarGz(new Uint8Array(gzipSync(tar)))).toThrow(
+			/checksum/,
+		);
+	});
+
+	test("should apply a GNU long name to the next entry", () => {
+		const archive = createTypedTarGz([
+			{ path: "././@LongLink", content: `${longName}\0`, type: "L" },
+			{ path: "commands/en/truncated.md", content: "long" },
+			{ path: "commands/en/next.md", content: "next" },
+		]);
+
+		expect(extractTarGz(archive)).toEqual([
+			{ path: longName, content: "long" },
+			{ path: "commands/en/next.md", content: "next" },
+		]);
+	});
+
+	test("should apply a pax path record to the next entry", () => {
+		const archive = createTypedTarGz([
+			{
+				path: "PaxHeaders/truncated.md",
+				content: paxRecord("mtime", "0") + paxRecord("path", longName),
+				type: "x",
+			},
+			{ path: "commands/en/truncated.md", content: "long" },
+			{ path: "commands/en/next.md", content: "next" },
+		]);
+
+		expect(extractTarGz(archive)).toEqual([
+			{ path: longName, content: "long" },
+			{ path: "commands/en/next.md", content: "next" },
+		]);
+	});
+
+	test("should reject a malformed pax header", () => {
+		const archive = createTypedTarGz([
+			{ path: "PaxHeaders/file.md", content: "99 path=x\n", type: "x" },
+			{ path: "file.md", content: "x" },
+		]);
+
+		expect(() => extractTarGz(archive)).toThrow(/pax header/);
+	});
+
+	test("should reject archives that expand beyond the size limit", () => {
+		const archive = createTarGz([
+			{ path: "big.md", content: "a".repeat(8192) },
+		]);
+
+		expect(() => extractTarGz(archive, 4096)).toThrow(
+			/expands to more than 4096 bytes/,
+		);
+	});
+});
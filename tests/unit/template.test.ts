@@ -0,0 +1,83 @@
+import { describe, expect, test } from "bun:test";
+import {
+	checkTemplateFields,
+	isTemplate,
+	parseTemplate,
+	renderTemplate,
+	TemplateError,
+} from "../../src/utils/template.js";
+
+const data = {
+	Name: "debug-help",
+	Tags: ["git", "review"],
+	Category: undefined,
+	Cache: { CommandCount: 12 },
+};
+
+describe("isTemplate", () => {
+	test("should tell templates from format names", () => {
+		expect(isTemplate("{{.Name}}")).toBe(true);
+		expect(isTemplate("json")).toBe(false);
+	});
+});
+
+describe("renderTemplate", () => {
+	test("should fill in fields and keep the text around them", () => {
+		const template = parseTemplate("cmd: {{.Name}} ({{ .Cache.CommandCount }})");
+
+		expect(renderTemplate(template, data)).toBe("cmd: debug-help (12)");
+	});
+
+	test("should join lists and leave missing values empty", () => {
+		const template = parseTemplate("{{.Tags}}|{{.Category}}|");
+
+		expect(renderTemplate(template, data)).toBe("git,review||");
+	});
+
+	test("should name the available fields for unknown ones", () => {
+		const template = parseTemplate("{{.Cache.Count}}");
+
+		expect(() => renderTemplate(template, data)).toThrow(
+			"Unknown field '.Cache.Count'. Available fields: .Cache.CommandCount",
+		);
+	});
+
+	test("should reject fields that have fields of their own", () => {
+		expect(() => renderTemplate(parseTemplate("{{.Cache}}"), data)).toThrow(
+			"Field '.Cache' has fields of its own: .Cache.CommandCount",
+		);
+	});
+});
+
+describe("parseTemplate", () => {
+	test("should reject unclosed actions", () => {
+		expect(() => parseTemplate("{{.Name")).toThrow(
+			"Unclosed action at offset 0",
+		);
+	});
+
+	test("should reject stray closing braces", () => {
+		expect(() => parseTemplate("{{.Name}} }}")).toThrow(
+			"Unexpected '}}' at offset 10",
+		);
+	});
+
+	test("should reject actions other than fields", () => {
+		expect(() => parseTemplate("{{range .Tags}}")).toThrow(TemplateError);
+		expect(() => parseTemplate("{{ }}")).toThrow("Empty action");
+	});
+});
+
+describe("checkTemplateFields", () => {
+	test("should accept fields whose values are missing", () => {
+		expect(() =>
+			checkTemplateFields(parseTemplate("{{.Category}}"), data),
+		).not.toThrow();
+	});
+
+	test("should reject unknown fields", () => {
+		expect(() =>
+			checkTemplateFields(parseTemplate("{{.Nmae}}"), data),
+		).toThrow("Unknown field '.Nmae'");
+	});
+});
@@ -0,0 +1,120 @@
+import { afterEach, beforeEach, describe, expect, spyOn, test } from "bun:test";
+import { confirmToolUse } from "../../src/cli/toolConsent.js";
+import { CommandParser } from "../../src/services/CommandParser.js";
+import { ConfigService } from "../../src/services/ConfigService.js";
+import HTTPRepository from "../../src/services/HTTPRepository.js";
+import { LanguageDetector } from "../../src/services/LanguageDetector.js";
+import NamespaceService from "../../src/services/NamespaceService.js";
+import { decisionCovers, powerfulTools } from "../../src/utils/toolConsent.js";
+import InMemoryFileService from "../mocks/InMemoryFileService.js";
+import InMemoryHTTPClient from "../mocks/InMemoryHTTPClient.js";
+import InMemoryUserInteractionService from "../mocks/InMemoryUserInteractionService.js";
+
+const SHELL_COMMAND = `---
+description: Commit staged changes
+allowed-tools: Bash(git:*), Read
+---
+
+Commit.
+`;
+
+describe("toolConsent", () => {
+	describe("powerfulTools", () => {
+		test("should pick Bash and unrestricted editing tools", () => {
+			expect(
+				powerfulTools([
+					"Read",
+					"Bash(git:*)",
+					"Edit",
+					"Write(**/*.ts)",
+					"Edit(docs/*.md)",
+					"mcp__github__create_issue",
+				]),
+			).toEqual(["Bash(git:*)", "Edit", "Write(**/*.ts)"]);
+		});
+
+		test("should treat absolute, home and parent paths as broad", () => {
+			expect(
+				powerfulTools(["Edit(/etc/*)", "Write(~/.ssh/*)", "Edit(../*)"]),
+			).toHaveLength(3);
+		});
+	});
+
+	test("decisionCovers should require every tool to have been reviewed", () => {
+		const decision = { allow: true, tools: ["Bash(git:*)"] };
+
+		expect(decisionCovers(decision, ["Bash(git:*)"])).toBe(true);
+		expect(decisionCovers(decision, ["Bash(git:*)", "Edit"])).toBe(false);
+	});
+
+	describe("confirmToolUse", () => {
+		const configPath = "/home/user/.config/claude-cmd/config.claude-cmd.json";
+		let userInteractionService: InMemoryUserInteractionService;
+		let configService: ConfigService;
+		let logSpy: ReturnType<typeof spyOn>;
+		let warnSpy: ReturnType<typeof spyOn>;
+
+		beforeEach(() => {
+			const fileService = new InMemoryFileService();
+			userInteractionService = new InMemoryUserInteractionService();
+			configService = new ConfigService(
+				configPath,
+				fileService,
+				new HTTPRepository(new InMemoryHTTPClient(), fileService),
+				new LanguageDetector(),
+			);
+			logSpy = spyOn(console, "log").mockImplementation(() => {});
+			warnSpy = spyOn(console, "warn").mockImplementation(() => {});
+		});
+
+		afterEach(() => {
+			logSpy.mockRestore();
+			warnSpy.mockRestore();
+			process.exitCode = 0;
+		});
+
+		const confirm = (content: string, yes = false) =>
+			confirmToolUse("git:commit", content, yes, {
+				commandParser: new CommandParser(new NamespaceService()),
+				userInteractionService,
+				configService,
+			});
+
+		test("should not ask about harmless tools", async () => {
+			const content = "---\ndescription: Read\nallowed-tools: Read\n---\n";
+
+			expect(await confirm(content)).toBe(true);
+			expect(userInteractionService.getInteractionHistory()).toHaveLength(0);
+		});
+
+		test("should ask once and remember the answer", async () => {
+			userInteractionService.setChoiceResponses("deny");
+
+			expect(await confirm(SHELL_COMMAND)).toBe(false);
+			expect(await confirm(SHELL_COMMAND)).toBe(false);
+
+			expect(userInteractionService.getInteractionHistory()).toHaveLength(1);
+			expect((await configService.getConfig())?.toolConsent).toEqual({
+				"git:commit": { allow: false, tools: ["Bash(git:*)"] },
+			});
+		});
+
+		test("should ask again when the command requests more", async () => {
+			await configService.setConfig({
+				toolConsent: { "git:commit": { allow: true, tools: ["Edit"] } },
+			});
+			userInteractionService.setChoiceResponses("allow");
+
+			expect(await confirm(SHELL_COMMAND)).toBe(true);
+			expect(userInteractionService.getInteractionHistory()).toHaveLength(1);
+		});
+
+		test("should refuse without a terminal unless --yes is given", async () => {
+			expect(await confirm(SHELL_COMMAND)).toBe(false);
+			expect(process.exitCode).toBe(1);
+			expect(await configService.getConfig()).toBeNull();
+
+			expect(await confirm(SHELL_COMMAND, true)).toBe(true);
+		});
+	});
+});